@@ -5,6 +5,7 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 
 	"gogs.io/gogs/internal/errutil"
@@ -21,6 +22,7 @@ const (
 	PAM         // 4
 	DLDAP       // 5
 	GitHub      // 6
+	OIDC        // 7
 )
 
 // Name returns the human-readable name for given authentication type.
@@ -31,6 +33,7 @@ func Name(typ Type) string {
 		SMTP:   "SMTP",
 		PAM:    "PAM",
 		GitHub: "GitHub",
+		OIDC:   "OpenID Connect",
 	}[typ]
 }
 
@@ -53,6 +56,27 @@ func (ErrBadCredentials) NotFound() bool {
 	return true
 }
 
+var _ errutil.NotFound = (*ErrAccountNotExist)(nil)
+
+// ErrAccountNotExist is returned when the external account does not exist or
+// is no longer allowed to sign in.
+type ErrAccountNotExist struct {
+	Args errutil.Args
+}
+
+func IsErrAccountNotExist(err error) bool {
+	_, ok := err.(ErrAccountNotExist)
+	return ok
+}
+
+func (err ErrAccountNotExist) Error() string {
+	return fmt.Sprintf("account does not exist: %v", err.Args)
+}
+
+func (ErrAccountNotExist) NotFound() bool {
+	return true
+}
+
 // ExternalAccount contains queried information returned by an authenticate provider
 // for an external account.
 type ExternalAccount struct {
@@ -70,6 +94,9 @@ type ExternalAccount struct {
 	Website string
 	// Whether the user should be prompted as a site admin.
 	Admin bool
+	// The teams the account is a member of, in the form of "<org>/<team>". It is
+	// only set by providers that implement GroupSyncProvider.
+	Teams []string
 }
 
 // Provider defines an authenticate provider which provides ability to authentication against
@@ -88,3 +115,37 @@ type Provider interface {
 	// SkipTLSVerify returns true if the authenticate provider is configured to skip TLS verify.
 	SkipTLSVerify() bool
 }
+
+// RedirectProvider defines an authenticate provider which authenticates users
+// by redirecting them to sign in at an external identity provider, e.g. OpenID
+// Connect. Authenticating with password is not supported by such providers.
+type RedirectProvider interface {
+	Provider
+
+	// AuthCodeURL returns the URL of the external identity provider to redirect
+	// the user to sign in. The "redirectURL" is where the user comes back after
+	// signed in, with the "state" as is.
+	AuthCodeURL(redirectURL, state, nonce string) (string, error)
+	// Exchange exchanges the authorization code for the information of the
+	// external account, the "nonce" must be the same one used to obtain the
+	// code.
+	Exchange(ctx context.Context, redirectURL, code, nonce string) (*ExternalAccount, error)
+}
+
+// GroupSyncProvider defines an authenticate provider which derives the site
+// admin status and team membership of users from groups of external accounts.
+type GroupSyncProvider interface {
+	Provider
+
+	// SyncsAdmin returns true if the site admin status of users is managed by the
+	// provider.
+	SyncsAdmin() bool
+	// SyncedTeams returns the teams whose membership is managed by the provider,
+	// in the form of "<org>/<team>".
+	SyncedTeams() []string
+	// SearchAccount queries information of the external account with given login
+	// without authenticating it, which is used for periodic synchronization. It
+	// returns ErrAccountNotExist when the account does not exist or is no longer
+	// allowed to sign in.
+	SearchAccount(login string) (*ExternalAccount, error)
+}
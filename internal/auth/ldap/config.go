@@ -12,7 +12,11 @@ import (
 	"strings"
 
 	ldap "github.com/go-ldap/ldap/v3"
+	"github.com/pkg/errors"
 	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/auth"
+	"gogs.io/gogs/internal/errutil"
 )
 
 // SecurityProtocol is the security protocol when the authenticate provider talks to LDAP directory.
@@ -58,6 +62,63 @@ type Config struct {
 	GroupFilter       string // Group name filter
 	GroupMemberUID    string `ini:"group_member_uid"` // Group Attribute containing array of UserUID
 	UserUID           string `ini:"user_uid"`         // User Attribute listed in group
+
+	AdminGroupDN string `ini:"admin_group_dn,omitempty"` // DN of the group whose members are site admins
+	GroupTeamMap string `ini:",omitempty"`               // Mappings from group DNs to organization teams
+}
+
+// GroupTeamMapping maps members of an LDAP group to a team of an organization.
+type GroupTeamMapping struct {
+	GroupDN  string
+	OrgName  string
+	TeamName string
+}
+
+// Team returns the team in the form of "<org>/<team>".
+func (m GroupTeamMapping) Team() string {
+	return m.OrgName + "/" + m.TeamName
+}
+
+// ParseGroupTeamMap parses mappings from group DNs to organization teams. Each
+// line is a mapping in the form of "<group DN> = <org>/<team>", e.g.
+// "cn=backend,ou=groups,dc=acme,dc=com = acme/backend". Empty lines are
+// ignored.
+func ParseGroupTeamMap(s string) ([]GroupTeamMapping, error) {
+	var mappings []GroupTeamMapping
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		i := strings.LastIndex(line, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("missing \"=\" in %q", line)
+		}
+		groupDN := strings.TrimSpace(line[:i])
+		fields := strings.Split(strings.TrimSpace(line[i+1:]), "/")
+		if groupDN == "" || len(fields) != 2 || fields[0] == "" || fields[1] == "" {
+			return nil, fmt.Errorf("invalid mapping %q", line)
+		}
+		mappings = append(mappings, GroupTeamMapping{
+			GroupDN:  groupDN,
+			OrgName:  fields[0],
+			TeamName: fields[1],
+		})
+	}
+	return mappings, nil
+}
+
+// entry contains queried information of a user entry.
+type entry struct {
+	Username  string
+	FirstName string
+	Surname   string
+	Mail      string
+	IsAdmin   bool
+	// The teams mapped from groups that the user is a member of, in the form of
+	// "<org>/<team>".
+	Teams []string
 }
 
 func (c *Config) SecurityProtocolName() string {
@@ -108,15 +169,14 @@ func (*Config) sanitizedGroupDN(groupDn string) (string, bool) {
 	return groupDn, true
 }
 
-func (c *Config) findUserDN(l *ldap.Conn, name string) (string, bool) {
+func (c *Config) findUserDN(l *ldap.Conn, name string) (string, error) {
 	log.Trace("Search for LDAP user: %s", name)
 	if len(c.BindDN) > 0 && len(c.BindPassword) > 0 {
 		// Replace placeholders with username
 		bindDN := strings.ReplaceAll(c.BindDN, "%s", name)
 		err := l.Bind(bindDN, c.BindPassword)
 		if err != nil {
-			log.Trace("LDAP: Failed to bind as BindDN '%s': %v", bindDN, err)
-			return "", false
+			return "", fmt.Errorf("bind as BindDN %q: %v", bindDN, err)
 		}
 		log.Trace("LDAP: Bound as BindDN: %s", bindDN)
 	} else {
@@ -126,7 +186,7 @@ func (c *Config) findUserDN(l *ldap.Conn, name string) (string, bool) {
 	// A search for the user.
 	userFilter, ok := c.sanitizedUserQuery(name)
 	if !ok {
-		return "", false
+		return "", errAccountNotExist(name, "invalid name")
 	}
 
 	log.Trace("LDAP: Searching for DN using filter %q and base %q", userFilter, c.UserBase)
@@ -136,21 +196,36 @@ func (c *Config) findUserDN(l *ldap.Conn, name string) (string, bool) {
 
 	// Ensure we found a user
 	sr, err := l.Search(search)
-	if err != nil || len(sr.Entries) < 1 {
-		log.Trace("LDAP: Failed to search using filter %q: %v", userFilter, err)
-		return "", false
+	if err != nil {
+		return "", fmt.Errorf("search using filter %q: %v", userFilter, err)
+	} else if len(sr.Entries) < 1 {
+		return "", errAccountNotExist(name, "no entry matches the user filter")
 	} else if len(sr.Entries) > 1 {
-		log.Trace("LDAP: Filter %q returned more than one user", userFilter)
-		return "", false
+		return "", fmt.Errorf("filter %q returned more than one user", userFilter)
 	}
 
 	userDN := sr.Entries[0].DN
 	if userDN == "" {
-		log.Error("LDAP: Search was successful, but found no DN!")
-		return "", false
+		return "", errors.New("search was successful, but found no DN")
 	}
 
-	return userDN, true
+	return userDN, nil
+}
+
+// errAccountNotExist returns auth.ErrAccountNotExist for the account with given
+// name and the reason.
+func errAccountNotExist(name, reason string) error {
+	return auth.ErrAccountNotExist{Args: errutil.Args{"login": name, "reason": reason}}
+}
+
+// logQueryError logs the error of querying an entry, errors of accounts that do
+// not exist are expected and only traced.
+func logQueryError(err error) {
+	if auth.IsErrAccountNotExist(err) {
+		log.Trace("LDAP: %v", err)
+		return
+	}
+	log.Error("LDAP: %v", err)
 }
 
 func dial(ls *Config) (*ldap.Conn, error) {
@@ -190,17 +265,52 @@ func bindUser(l *ldap.Conn, userDN, passwd string) error {
 	return err
 }
 
+// isGroupMember returns true if the group entry lists the user as a member.
+func (c *Config) isGroupMember(group *ldap.Entry, dn, uid string) bool {
+	id := uid
+	if c.UserUID == "dn" {
+		id = dn
+	}
+	for _, member := range group.GetAttributeValues(c.GroupMemberUID) {
+		if member == id {
+			return true
+		}
+	}
+	return false
+}
+
+// searchGroupMembers returns the group entry of given DN with the attribute
+// containing its members.
+func (c *Config) searchGroupMembers(l *ldap.Conn, groupDN string) (*ldap.Entry, error) {
+	groupDN, ok := c.sanitizedGroupDN(groupDN)
+	if !ok {
+		return nil, fmt.Errorf("invalid group DN %q", groupDN)
+	}
+
+	search := ldap.NewSearchRequest(
+		groupDN, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false, "(objectClass=*)",
+		[]string{c.GroupMemberUID},
+		nil)
+	sr, err := l.Search(search)
+	if err != nil {
+		return nil, err
+	} else if len(sr.Entries) < 1 {
+		return nil, fmt.Errorf("group %q not found", groupDN)
+	}
+	return sr.Entries[0], nil
+}
+
 // searchEntry searches an LDAP source if an entry (name, passwd) is valid and in the specific filter.
-func (c *Config) searchEntry(name, passwd string, directBind bool) (string, string, string, string, bool, bool) {
+func (c *Config) searchEntry(name, passwd string, directBind bool) (*entry, bool) {
 	// See https://tools.ietf.org/search/rfc4513#section-5.1.2
 	if passwd == "" {
 		log.Trace("authentication failed for '%s' with empty password", name)
-		return "", "", "", "", false, false
+		return nil, false
 	}
 	l, err := dial(c)
 	if err != nil {
 		log.Error("LDAP connect failed for '%s': %v", c.Host, err)
-		return "", "", "", "", false, false
+		return nil, false
 	}
 	defer l.Close()
 
@@ -211,15 +321,15 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 		var ok bool
 		userDN, ok = c.sanitizedUserDN(name)
 		if !ok {
-			return "", "", "", "", false, false
+			return nil, false
 		}
 	} else {
 		log.Trace("LDAP will use BindDN")
 
-		var found bool
-		userDN, found = c.findUserDN(l, name)
-		if !found {
-			return "", "", "", "", false, false
+		userDN, err = c.findUserDN(l, name)
+		if err != nil {
+			logQueryError(err)
+			return nil, false
 		}
 	}
 
@@ -227,13 +337,51 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 		// binds user (checking password) before looking-up attributes in user context
 		err = bindUser(l, userDN, passwd)
 		if err != nil {
-			return "", "", "", "", false, false
+			return nil, false
+		}
+	}
+
+	e, err := c.queryEntry(l, name, userDN, directBind)
+	if err != nil {
+		logQueryError(err)
+		return nil, false
+	}
+
+	if !directBind && c.AttributesInBind {
+		// binds user (checking password) after looking-up attributes in BindDN context
+		err = bindUser(l, userDN, passwd)
+		if err != nil {
+			return nil, false
 		}
 	}
 
+	return e, true
+}
+
+// lookupEntry searches an LDAP source for the entry of given name using the
+// BindDN without checking the password of the entry. It returns
+// auth.ErrAccountNotExist when the entry does not exist or is filtered out.
+func (c *Config) lookupEntry(name string) (*entry, error) {
+	l, err := dial(c)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %q: %v", c.Host, err)
+	}
+	defer l.Close()
+
+	userDN, err := c.findUserDN(l, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.queryEntry(l, name, userDN, false)
+}
+
+// queryEntry queries attributes, group membership and admin status of the
+// entry with given user DN. It returns auth.ErrAccountNotExist when the entry
+// does not exist or is filtered out.
+func (c *Config) queryEntry(l *ldap.Conn, name, userDN string, directBind bool) (*entry, error) {
 	userFilter, ok := c.sanitizedUserQuery(name)
 	if !ok {
-		return "", "", "", "", false, false
+		return nil, errAccountNotExist(name, "invalid name")
 	}
 
 	log.Trace("Fetching attributes %q, %q, %q, %q, %q with user filter %q and user DN %q",
@@ -245,33 +393,32 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 		nil)
 	sr, err := l.Search(search)
 	if err != nil {
-		log.Error("LDAP: User search failed: %v", err)
-		return "", "", "", "", false, false
+		return nil, fmt.Errorf("user search failed: %v", err)
 	} else if len(sr.Entries) < 1 {
 		if directBind {
-			log.Trace("LDAP: User filter inhibited user login")
-		} else {
-			log.Trace("LDAP: User search failed: 0 entries")
+			return nil, errAccountNotExist(name, "user filter inhibited user login")
 		}
-
-		return "", "", "", "", false, false
+		return nil, errAccountNotExist(name, "user search returned no entries")
 	}
 
-	username := sr.Entries[0].GetAttributeValue(c.AttributeUsername)
-	firstname := sr.Entries[0].GetAttributeValue(c.AttributeName)
-	surname := sr.Entries[0].GetAttributeValue(c.AttributeSurname)
-	mail := sr.Entries[0].GetAttributeValue(c.AttributeMail)
+	e := &entry{
+		Username:  sr.Entries[0].GetAttributeValue(c.AttributeUsername),
+		FirstName: sr.Entries[0].GetAttributeValue(c.AttributeName),
+		Surname:   sr.Entries[0].GetAttributeValue(c.AttributeSurname),
+		Mail:      sr.Entries[0].GetAttributeValue(c.AttributeMail),
+	}
+	dn := sr.Entries[0].DN
 	uid := sr.Entries[0].GetAttributeValue(c.UserUID)
 
 	// Check group membership
 	if c.GroupEnabled {
 		groupFilter, ok := c.sanitizedGroupFilter(c.GroupFilter)
 		if !ok {
-			return "", "", "", "", false, false
+			return nil, fmt.Errorf("invalid group filter %q", c.GroupFilter)
 		}
 		groupDN, ok := c.sanitizedGroupDN(c.GroupDN)
 		if !ok {
-			return "", "", "", "", false, false
+			return nil, fmt.Errorf("invalid group DN %q", c.GroupDN)
 		}
 
 		log.Trace("LDAP: Fetching groups '%v' with filter '%s' and base '%s'", c.GroupMemberUID, groupFilter, groupDN)
@@ -282,39 +429,25 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 
 		srg, err := l.Search(groupSearch)
 		if err != nil {
-			log.Error("LDAP: Group search failed: %v", err)
-			return "", "", "", "", false, false
+			return nil, fmt.Errorf("group search failed: %v", err)
 		} else if len(srg.Entries) < 1 {
-			log.Trace("LDAP: Group search returned no entries")
-			return "", "", "", "", false, false
+			return nil, errAccountNotExist(name, "group search returned no entries")
 		}
 
 		isMember := false
-		if c.UserUID == "dn" {
-			for _, group := range srg.Entries {
-				for _, member := range group.GetAttributeValues(c.GroupMemberUID) {
-					if member == sr.Entries[0].DN {
-						isMember = true
-					}
-				}
-			}
-		} else {
-			for _, group := range srg.Entries {
-				for _, member := range group.GetAttributeValues(c.GroupMemberUID) {
-					if member == uid {
-						isMember = true
-					}
-				}
+		for _, group := range srg.Entries {
+			if c.isGroupMember(group, dn, uid) {
+				isMember = true
+				break
 			}
 		}
 
 		if !isMember {
-			log.Trace("LDAP: Group membership test failed [username: %s, group_member_uid: %s, user_uid: %s", username, c.GroupMemberUID, uid)
-			return "", "", "", "", false, false
+			log.Trace("LDAP: Group membership test failed [username: %s, group_member_uid: %s, user_uid: %s", e.Username, c.GroupMemberUID, uid)
+			return nil, errAccountNotExist(name, "not a member of the group")
 		}
 	}
 
-	isAdmin := false
 	if len(c.AdminFilter) > 0 {
 		log.Trace("Checking admin with filter '%s' and base '%s'", c.AdminFilter, userDN)
 		search = ldap.NewSearchRequest(
@@ -328,17 +461,35 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 		} else if len(sr.Entries) < 1 {
 			log.Trace("LDAP: Admin search returned no entries")
 		} else {
-			isAdmin = true
+			e.IsAdmin = true
 		}
 	}
 
-	if !directBind && c.AttributesInBind {
-		// binds user (checking password) after looking-up attributes in BindDN context
-		err = bindUser(l, userDN, passwd)
+	if c.AdminGroupDN != "" {
+		log.Trace("LDAP: Checking admin with group '%s'", c.AdminGroupDN)
+		group, err := c.searchGroupMembers(l, c.AdminGroupDN)
+		if err != nil {
+			return nil, fmt.Errorf("admin group search failed: %v", err)
+		} else if c.isGroupMember(group, dn, uid) {
+			e.IsAdmin = true
+		}
+	}
+
+	// Errors of group searches fail the whole query, otherwise the user would
+	// be removed from teams because of temporary failures.
+	mappings, err := ParseGroupTeamMap(c.GroupTeamMap)
+	if err != nil {
+		return nil, fmt.Errorf("parse group team map: %v", err)
+	}
+	for _, m := range mappings {
+		log.Trace("LDAP: Checking membership of group '%s' for team '%s'", m.GroupDN, m.Team())
+		group, err := c.searchGroupMembers(l, m.GroupDN)
 		if err != nil {
-			return "", "", "", "", false, false
+			return nil, fmt.Errorf("group search for team mapping failed: %v", err)
+		} else if c.isGroupMember(group, dn, uid) {
+			e.Teams = append(e.Teams, m.Team())
 		}
 	}
 
-	return username, firstname, surname, mail, isAdmin, true
+	return e, nil
 }
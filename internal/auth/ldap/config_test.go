@@ -0,0 +1,62 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ldap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGroupTeamMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    []GroupTeamMapping
+		wantErr string
+	}{
+		{
+			name: "empty",
+			s:    "\n  \n",
+		},
+		{
+			name: "valid",
+			s: `cn=backend,ou=groups,dc=acme,dc=com = acme/backend
+ cn=Front End,ou=groups,dc=acme,dc=com=acme/frontend
+
+`,
+			want: []GroupTeamMapping{
+				{GroupDN: "cn=backend,ou=groups,dc=acme,dc=com", OrgName: "acme", TeamName: "backend"},
+				{GroupDN: "cn=Front End,ou=groups,dc=acme,dc=com", OrgName: "acme", TeamName: "frontend"},
+			},
+		},
+		{
+			name:    "missing separator",
+			s:       "acme/backend",
+			wantErr: `missing "=" in "acme/backend"`,
+		},
+		{
+			name:    "missing organization",
+			s:       "cn=backend,ou=groups = backend",
+			wantErr: `invalid mapping "cn=backend,ou=groups = backend"`,
+		},
+		{
+			name:    "missing group DN",
+			s:       " = acme/backend",
+			wantErr: `invalid mapping "= acme/backend"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseGroupTeamMap(test.s)
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
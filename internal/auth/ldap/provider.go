@@ -7,6 +7,8 @@ package ldap
 import (
 	"fmt"
 
+	"github.com/pkg/errors"
+
 	"gogs.io/gogs/internal/auth"
 )
 
@@ -27,14 +29,34 @@ func NewProvider(directBind bool, cfg *Config) auth.Provider {
 // Authenticate queries if login/password is valid against the LDAP directory pool,
 // and returns queried information when succeeded.
 func (p *Provider) Authenticate(login, password string) (*auth.ExternalAccount, error) {
-	username, fn, sn, email, isAdmin, succeed := p.config.searchEntry(login, password, p.directBind)
+	e, succeed := p.config.searchEntry(login, password, p.directBind)
 	if !succeed {
 		return nil, auth.ErrBadCredentials{Args: map[string]interface{}{"login": login}}
 	}
+	return newExternalAccount(login, e), nil
+}
+
+// SearchAccount queries information of the account with given login using the
+// BindDN. It is not supported by LDAP via direct bind because the password of
+// the account is required to bind.
+func (p *Provider) SearchAccount(login string) (*auth.ExternalAccount, error) {
+	if p.directBind {
+		return nil, errors.New("searching accounts is not supported via direct bind")
+	}
 
+	e, err := p.config.lookupEntry(login)
+	if err != nil {
+		return nil, err
+	}
+	return newExternalAccount(login, e), nil
+}
+
+func newExternalAccount(login string, e *entry) *auth.ExternalAccount {
+	username := e.Username
 	if username == "" {
 		username = login
 	}
+	email := e.Mail
 	if email == "" {
 		email = fmt.Sprintf("%s@localhost", username)
 	}
@@ -55,10 +77,35 @@ func (p *Provider) Authenticate(login, password string) (*auth.ExternalAccount,
 	return &auth.ExternalAccount{
 		Login:    login,
 		Name:     username,
-		FullName: composeFullName(fn, sn, username),
+		FullName: composeFullName(e.FirstName, e.Surname, username),
 		Email:    email,
-		Admin:    isAdmin,
-	}, nil
+		Admin:    e.IsAdmin,
+		Teams:    e.Teams,
+	}
+}
+
+// SyncsAdmin returns true if the admin group is set.
+func (p *Provider) SyncsAdmin() bool {
+	return p.config.AdminGroupDN != ""
+}
+
+// SyncedTeams returns the teams in the group team map.
+func (p *Provider) SyncedTeams() []string {
+	mappings, err := ParseGroupTeamMap(p.config.GroupTeamMap)
+	if err != nil {
+		return nil
+	}
+
+	teams := make([]string, 0, len(mappings))
+	seen := make(map[string]bool, len(mappings))
+	for _, m := range mappings {
+		if seen[m.Team()] {
+			continue
+		}
+		seen[m.Team()] = true
+		teams = append(teams, m.Team())
+	}
+	return teams
 }
 
 func (p *Provider) Config() interface{} {
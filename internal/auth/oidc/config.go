@@ -0,0 +1,76 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"strings"
+)
+
+const (
+	defaultScopes        = "openid profile email"
+	defaultUsernameClaim = "preferred_username"
+	defaultEmailClaim    = "email"
+	defaultFullNameClaim = "name"
+	defaultGroupsClaim   = "groups"
+)
+
+// Config contains configuration for OpenID Connect authentication.
+//
+// ⚠️ WARNING: Change to the field name must preserve the INI key name for backward compatibility.
+type Config struct {
+	// The issuer URL of the identity provider, e.g. https://accounts.example.com.
+	// The discovery document is fetched from "/.well-known/openid-configuration"
+	// under the URL.
+	DiscoveryURL string `ini:"discovery_url"`
+	ClientID     string `ini:"client_id"`
+	ClientSecret string
+	// The space-separated scopes to request, the "openid" scope is always
+	// requested.
+	Scopes string
+
+	// The claim to use as the username, default to "preferred_username".
+	UsernameClaim string
+	// The claim to use as the email address, default to "email".
+	EmailClaim string
+	// The claim to use as the full name, default to "name".
+	FullNameClaim string
+	// The boolean claim which indicates the user is a site admin.
+	AdminClaim string
+	// The claim which contains the list of groups, default to "groups".
+	GroupsClaim string
+	// Members of the group are site admins.
+	AdminGroup string
+	// Only members of the group are allowed to sign in.
+	RequiredGroup string
+
+	SkipVerify bool
+}
+
+// issuer returns the issuer URL derived from the discovery URL.
+func (c *Config) issuer() string {
+	issuer := strings.TrimSpace(c.DiscoveryURL)
+	return strings.TrimSuffix(issuer, "/.well-known/openid-configuration")
+}
+
+// scopes returns the list of scopes to request.
+func (c *Config) scopes() []string {
+	scopes := strings.Fields(c.Scopes)
+	if len(scopes) == 0 {
+		scopes = strings.Fields(defaultScopes)
+	}
+	for _, scope := range scopes {
+		if scope == "openid" {
+			return scopes
+		}
+	}
+	return append([]string{"openid"}, scopes...)
+}
+
+func claimOrDefault(claim, defaultClaim string) string {
+	if claim == "" {
+		return defaultClaim
+	}
+	return claim
+}
@@ -0,0 +1,272 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"gogs.io/gogs/internal/auth"
+)
+
+var (
+	_ auth.RedirectProvider  = (*Provider)(nil)
+	_ auth.GroupSyncProvider = (*Provider)(nil)
+)
+
+// Provider contains configuration of an OpenID Connect authentication provider.
+type Provider struct {
+	config *Config
+
+	discoverLock sync.Mutex
+	provider     *gooidc.Provider // The discovered identity provider
+}
+
+// NewProvider creates a new OpenID Connect authentication provider.
+func NewProvider(cfg *Config) auth.Provider {
+	return &Provider{
+		config: cfg,
+	}
+}
+
+// Authenticate always returns auth.ErrBadCredentials because users of OpenID
+// Connect can only sign in at the identity provider.
+func (*Provider) Authenticate(login, _ string) (*auth.ExternalAccount, error) {
+	return nil, auth.ErrBadCredentials{Args: map[string]interface{}{"login": login}}
+}
+
+func (p *Provider) Config() interface{} {
+	return p.config
+}
+
+func (*Provider) HasTLS() bool {
+	return true
+}
+
+func (*Provider) UseTLS() bool {
+	return true
+}
+
+func (p *Provider) SkipTLSVerify() bool {
+	return p.config.SkipVerify
+}
+
+// clientContext returns a copy of the context which carries the HTTP client to
+// talk to the identity provider.
+func (p *Provider) clientContext(ctx context.Context) context.Context {
+	client := &http.Client{
+		Timeout: time.Minute,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: p.config.SkipVerify},
+		},
+	}
+	return gooidc.ClientContext(ctx, client)
+}
+
+// discover fetches the discovery document of the identity provider for the
+// first time, and returns the cached result afterwards.
+func (p *Provider) discover() (*gooidc.Provider, error) {
+	p.discoverLock.Lock()
+	defer p.discoverLock.Unlock()
+
+	if p.provider != nil {
+		return p.provider, nil
+	}
+
+	// NOTE: The context is also used to fetch signing keys later, thus it must
+	// not be bound to any request.
+	provider, err := gooidc.NewProvider(p.clientContext(context.Background()), p.config.issuer())
+	if err != nil {
+		return nil, errors.Wrap(err, "discover identity provider")
+	}
+	p.provider = provider
+	return provider, nil
+}
+
+func (p *Provider) oauth2Config(redirectURL string) (*oauth2.Config, *gooidc.Provider, error) {
+	provider, err := p.discover()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  redirectURL,
+		Scopes:       p.config.scopes(),
+	}, provider, nil
+}
+
+// SyncsAdmin returns true if the admin claim or the admin group is set.
+func (p *Provider) SyncsAdmin() bool {
+	return p.config.AdminClaim != "" || p.config.AdminGroup != ""
+}
+
+// SyncedTeams returns nil because groups are not mapped to teams.
+func (*Provider) SyncedTeams() []string {
+	return nil
+}
+
+// SearchAccount always returns an error because claims of users are only
+// available when they sign in at the identity provider.
+func (*Provider) SearchAccount(string) (*auth.ExternalAccount, error) {
+	return nil, errors.New("searching accounts is not supported by OpenID Connect")
+}
+
+func (p *Provider) AuthCodeURL(redirectURL, state, nonce string) (string, error) {
+	cfg, _, err := p.oauth2Config(redirectURL)
+	if err != nil {
+		return "", err
+	}
+	return cfg.AuthCodeURL(state, gooidc.Nonce(nonce)), nil
+}
+
+func (p *Provider) Exchange(ctx context.Context, redirectURL, code, nonce string) (*auth.ExternalAccount, error) {
+	cfg, provider, err := p.oauth2Config(redirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = p.clientContext(ctx)
+	token, err := cfg.Exchange(ctx, code)
+	if err != nil {
+		return nil, errors.Wrap(err, "exchange token")
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("no ID token in the token response")
+	}
+	idToken, err := provider.Verifier(&gooidc.Config{ClientID: p.config.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "verify ID token")
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("nonce of the ID token mismatched")
+	}
+
+	claims := make(map[string]interface{})
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse claims of the ID token")
+	}
+
+	usernameClaim := claimOrDefault(p.config.UsernameClaim, defaultUsernameClaim)
+	emailClaim := claimOrDefault(p.config.EmailClaim, defaultEmailClaim)
+
+	// Identity providers may only include minimal claims in the ID token, and
+	// leave others to the userinfo endpoint.
+	if stringClaim(claims, usernameClaim) == "" || stringClaim(claims, emailClaim) == "" {
+		userInfo, err := provider.UserInfo(ctx, oauth2.StaticTokenSource(token))
+		if err != nil {
+			return nil, errors.Wrap(err, "get user info")
+		}
+		if userInfo.Subject != idToken.Subject {
+			return nil, errors.New("subject of the user info mismatched")
+		}
+
+		extra := make(map[string]interface{})
+		err = userInfo.Claims(&extra)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse claims of the user info")
+		}
+		for k, v := range extra {
+			if _, ok := claims[k]; !ok {
+				claims[k] = v
+			}
+		}
+	}
+
+	return p.externalAccount(idToken.Subject, claims)
+}
+
+// externalAccount maps claims to the external account.
+func (p *Provider) externalAccount(subject string, claims map[string]interface{}) (*auth.ExternalAccount, error) {
+	groups := stringsClaim(claims, claimOrDefault(p.config.GroupsClaim, defaultGroupsClaim))
+	if p.config.RequiredGroup != "" && !contains(groups, p.config.RequiredGroup) {
+		return nil, auth.ErrBadCredentials{Args: map[string]interface{}{"login": subject, "requiredGroup": p.config.RequiredGroup}}
+	}
+
+	usernameClaim := claimOrDefault(p.config.UsernameClaim, defaultUsernameClaim)
+	username := stringClaim(claims, usernameClaim)
+	// Use the local part when the username claim is an email address.
+	if i := strings.Index(username, "@"); i > 0 {
+		username = username[:i]
+	}
+	if username == "" {
+		return nil, fmt.Errorf("claim %q is missing", usernameClaim)
+	}
+
+	emailClaim := claimOrDefault(p.config.EmailClaim, defaultEmailClaim)
+	email := stringClaim(claims, emailClaim)
+	if email == "" {
+		return nil, fmt.Errorf("claim %q is missing", emailClaim)
+	}
+
+	admin := p.config.AdminClaim != "" && boolClaim(claims, p.config.AdminClaim)
+	if p.config.AdminGroup != "" && contains(groups, p.config.AdminGroup) {
+		admin = true
+	}
+
+	return &auth.ExternalAccount{
+		Login:    subject,
+		Name:     username,
+		FullName: stringClaim(claims, claimOrDefault(p.config.FullNameClaim, defaultFullNameClaim)),
+		Email:    email,
+		Admin:    admin,
+	}, nil
+}
+
+func stringClaim(claims map[string]interface{}, name string) string {
+	v, _ := claims[name].(string)
+	return strings.TrimSpace(v)
+}
+
+// stringsClaim returns the claim as a list of strings, it accepts both a JSON
+// array of strings and a single string.
+func stringsClaim(claims map[string]interface{}, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func boolClaim(claims map[string]interface{}, name string) bool {
+	switch v := claims[name].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
+
+	"gogs.io/gogs/internal/auth"
+)
+
+// stubIdentityProvider is a minimal OpenID Connect identity provider which
+// issues tokens for a single authorization code.
+type stubIdentityProvider struct {
+	*httptest.Server
+
+	key      *rsa.PrivateKey
+	code     string
+	nonce    string
+	claims   map[string]interface{} // Claims of the ID token
+	userInfo map[string]interface{} // Claims of the userinfo endpoint
+}
+
+func newStubIdentityProvider(t *testing.T) *stubIdentityProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &stubIdentityProvider{
+		key:  key,
+		code: "stub-code",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                                idp.URL,
+			"authorization_endpoint":                idp.URL + "/authorize",
+			"token_endpoint":                        idp.URL + "/token",
+			"userinfo_endpoint":                     idp.URL + "/userinfo",
+			"jwks_uri":                              idp.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{
+				{Key: &idp.key.PublicKey, KeyID: "stub", Algorithm: "RS256", Use: "sig"},
+			},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("code") != idp.code {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"access_token": "stub-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idp.signIDToken(t),
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer stub-access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, idp.userInfo)
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func (idp *stubIdentityProvider) signIDToken(t *testing.T) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: idp.key},
+		(&jose.SignerOptions{}).WithHeader("kid", "stub"),
+	)
+	require.NoError(t, err)
+
+	claims := map[string]interface{}{
+		"iss":   idp.URL,
+		"sub":   "1234",
+		"aud":   "gogs",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": idp.nonce,
+	}
+	for k, v := range idp.claims {
+		claims[k] = v
+	}
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	sig, err := signer.Sign(payload)
+	require.NoError(t, err)
+	token, err := sig.CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestProvider_SyncsAdmin(t *testing.T) {
+	assert.False(t, NewProvider(&Config{}).(*Provider).SyncsAdmin())
+	assert.True(t, NewProvider(&Config{AdminClaim: "is_admin"}).(*Provider).SyncsAdmin())
+	assert.True(t, NewProvider(&Config{GroupsClaim: "groups", AdminGroup: "admins"}).(*Provider).SyncsAdmin())
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	idp := newStubIdentityProvider(t)
+	p := NewProvider(&Config{
+		DiscoveryURL: idp.URL + "/.well-known/openid-configuration",
+		ClientID:     "gogs",
+		Scopes:       "profile groups",
+	}).(*Provider)
+
+	got, err := p.AuthCodeURL("http://localhost:3000/user/oauth2/1/callback", "stub-state", "stub-nonce")
+	require.NoError(t, err)
+
+	u, err := url.Parse(got)
+	require.NoError(t, err)
+	assert.Equal(t, idp.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+
+	q := u.Query()
+	assert.Equal(t, "gogs", q.Get("client_id"))
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, "openid profile groups", q.Get("scope"))
+	assert.Equal(t, "stub-state", q.Get("state"))
+	assert.Equal(t, "stub-nonce", q.Get("nonce"))
+	assert.Equal(t, "http://localhost:3000/user/oauth2/1/callback", q.Get("redirect_uri"))
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	ctx := context.Background()
+	const redirectURL = "http://localhost:3000/user/oauth2/1/callback"
+
+	t.Run("claims in the ID token", func(t *testing.T) {
+		idp := newStubIdentityProvider(t)
+		idp.nonce = "stub-nonce"
+		idp.claims = map[string]interface{}{
+			"preferred_username": "alice@example.com",
+			"email":              "alice@example.com",
+			"name":               "Alice",
+			"groups":             []string{"dev", "ops"},
+		}
+
+		p := NewProvider(&Config{
+			DiscoveryURL: idp.URL,
+			ClientID:     "gogs",
+			AdminGroup:   "ops",
+		}).(*Provider)
+		got, err := p.Exchange(ctx, redirectURL, idp.code, "stub-nonce")
+		require.NoError(t, err)
+
+		want := &auth.ExternalAccount{
+			Login:    "1234",
+			Name:     "alice",
+			FullName: "Alice",
+			Email:    "alice@example.com",
+			Admin:    true,
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("claims in the userinfo", func(t *testing.T) {
+		idp := newStubIdentityProvider(t)
+		idp.nonce = "stub-nonce"
+		idp.userInfo = map[string]interface{}{
+			"sub":      "1234",
+			"login":    "bob",
+			"mail":     "bob@example.com",
+			"is_admin": true,
+		}
+
+		p := NewProvider(&Config{
+			DiscoveryURL:  idp.URL,
+			ClientID:      "gogs",
+			UsernameClaim: "login",
+			EmailClaim:    "mail",
+			AdminClaim:    "is_admin",
+		}).(*Provider)
+		got, err := p.Exchange(ctx, redirectURL, idp.code, "stub-nonce")
+		require.NoError(t, err)
+
+		want := &auth.ExternalAccount{
+			Login: "1234",
+			Name:  "bob",
+			Email: "bob@example.com",
+			Admin: true,
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("nonce mismatched", func(t *testing.T) {
+		idp := newStubIdentityProvider(t)
+		idp.nonce = "stub-nonce"
+
+		p := NewProvider(&Config{
+			DiscoveryURL: idp.URL,
+			ClientID:     "gogs",
+		}).(*Provider)
+		_, err := p.Exchange(ctx, redirectURL, idp.code, "bad-nonce")
+		assert.EqualError(t, err, "nonce of the ID token mismatched")
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		idp := newStubIdentityProvider(t)
+
+		p := NewProvider(&Config{
+			DiscoveryURL: idp.URL,
+			ClientID:     "gogs",
+		}).(*Provider)
+		_, err := p.Exchange(ctx, redirectURL, "bad-code", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("not in the required group", func(t *testing.T) {
+		idp := newStubIdentityProvider(t)
+		idp.claims = map[string]interface{}{
+			"preferred_username": "cindy",
+			"email":              "cindy@example.com",
+			"groups":             "dev",
+		}
+
+		p := NewProvider(&Config{
+			DiscoveryURL:  idp.URL,
+			ClientID:      "gogs",
+			RequiredGroup: "ops",
+		}).(*Provider)
+		_, err := p.Exchange(ctx, redirectURL, idp.code, "")
+		assert.True(t, auth.IsErrBadCredentials(err))
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scope is a permission granted to an access token for calling APIs.
+type Scope string
+
+const (
+	ScopeRepoRead  Scope = "repo:read"  // Read repositories and their issues, pull requests, releases etc.
+	ScopeRepoWrite Scope = "repo:write" // Read and write repositories, implies ScopeRepoRead.
+	ScopeAdminOrg  Scope = "admin:org"  // Manage organizations and teams.
+	ScopeUser      Scope = "user"       // Read and write the user profile, emails, followings and notifications.
+	ScopeWriteKeys Scope = "write:keys" // Manage SSH public keys of the user.
+	ScopeAdminSite Scope = "admin:site" // Call site administration APIs as a site admin.
+)
+
+// AllScopes is the list of all scopes in the order of display.
+var AllScopes = Scopes{
+	ScopeRepoRead,
+	ScopeRepoWrite,
+	ScopeAdminOrg,
+	ScopeUser,
+	ScopeWriteKeys,
+	ScopeAdminSite,
+}
+
+// impliedScopes is the list of scopes implied by a scope.
+var impliedScopes = map[Scope]Scopes{
+	ScopeRepoWrite: {ScopeRepoRead},
+}
+
+// LocaleKey returns the key of the scope description in locale files.
+func (s Scope) LocaleKey() string {
+	return "scope." + strings.NewReplacer(":", "_").Replace(string(s))
+}
+
+// Scopes is a list of scopes.
+type Scopes []Scope
+
+// ParseScopes parses the list of scopes separated by spaces or commas. It
+// returns an error if any of the scopes is unknown. Duplicated scopes are
+// removed and the result is in the order of AllScopes.
+func ParseScopes(s string) (Scopes, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+
+	seen := make(map[Scope]bool, len(fields))
+	for _, field := range fields {
+		scope := Scope(field)
+		if !AllScopes.contains(scope) {
+			return nil, fmt.Errorf("unknown scope %q", field)
+		}
+		seen[scope] = true
+	}
+
+	scopes := make(Scopes, 0, len(seen))
+	for _, scope := range AllScopes {
+		if seen[scope] {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+func (ss Scopes) contains(scope Scope) bool {
+	for _, s := range ss {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Has returns true if the scope is in the list or implied by any scope in the
+// list.
+func (ss Scopes) Has(scope Scope) bool {
+	for _, s := range ss {
+		if s == scope || impliedScopes[s].contains(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAll returns true if all of given scopes are granted by the list.
+func (ss Scopes) HasAll(scopes Scopes) bool {
+	for _, scope := range scopes {
+		if !ss.Has(scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the list of scopes separated by spaces.
+func (ss Scopes) String() string {
+	strs := make([]string, len(ss))
+	for i := range ss {
+		strs[i] = string(ss[i])
+	}
+	return strings.Join(strs, " ")
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Scopes
+		wantErr string
+	}{
+		{
+			name: "empty",
+			s:    "",
+			want: Scopes{},
+		},
+		{
+			name: "spaces and commas",
+			s:    "user repo:write,repo:read  user",
+			want: Scopes{ScopeRepoRead, ScopeRepoWrite, ScopeUser},
+		},
+		{
+			name:    "unknown scope",
+			s:       "repo:read repo:delete",
+			wantErr: `unknown scope "repo:delete"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseScopes(test.s)
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestScopes_Has(t *testing.T) {
+	scopes := Scopes{ScopeRepoWrite, ScopeUser}
+	assert.True(t, scopes.Has(ScopeRepoWrite))
+	assert.True(t, scopes.Has(ScopeRepoRead))
+	assert.True(t, scopes.Has(ScopeUser))
+	assert.False(t, scopes.Has(ScopeWriteKeys))
+
+	assert.True(t, scopes.HasAll(Scopes{ScopeRepoRead, ScopeUser}))
+	assert.False(t, scopes.HasAll(Scopes{ScopeRepoRead, ScopeAdminOrg}))
+	assert.True(t, scopes.HasAll(nil))
+}
@@ -5,7 +5,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"reflect"
 	"runtime"
 
@@ -14,6 +16,7 @@ import (
 
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/lfsutil"
 )
 
 var (
@@ -31,6 +34,8 @@ to make automatic initialization process more smoothly`,
 			subcmdRewriteAuthorizedKeys,
 			subcmdSyncRepositoryHooks,
 			subcmdReinitMissingRepositories,
+			subcmdMigrateLFSStorage,
+			subcmdRebuildCodeIndex,
 		},
 	}
 
@@ -130,6 +135,29 @@ to make automatic initialization process more smoothly`,
 			stringFlag("config, c", "", "Custom configuration file path"),
 		},
 	}
+
+	subcmdRebuildCodeIndex = cli.Command{
+		Name:  "rebuild-code-index",
+		Usage: "Rebuild the code search index of all repositories (the Gogs server must be stopped)",
+		Action: adminDashboardOperation(
+			db.RebuildCodeIndex,
+			"The code search index has been rebuilt successfully",
+		),
+		Flags: []cli.Flag{
+			stringFlag("config, c", "", "Custom configuration file path"),
+		},
+	}
+
+	subcmdMigrateLFSStorage = cli.Command{
+		Name:   "migrate-lfs-storage",
+		Usage:  "Migrate LFS objects from one storage backend to another",
+		Action: runMigrateLFSStorage,
+		Flags: []cli.Flag{
+			stringFlag("from", string(lfsutil.StorageLocal), "The storage backend to migrate objects from"),
+			stringFlag("to", string(lfsutil.StorageS3), "The storage backend to migrate objects to"),
+			stringFlag("config, c", "", "Custom configuration file path"),
+		},
+	}
 )
 
 func runCreateUser(c *cli.Context) error {
@@ -165,6 +193,81 @@ func runCreateUser(c *cli.Context) error {
 	return nil
 }
 
+func runMigrateLFSStorage(c *cli.Context) error {
+	err := conf.Init(c.String("config"))
+	if err != nil {
+		return errors.Wrap(err, "init configuration")
+	}
+	conf.InitLogging(true)
+
+	if _, err = db.SetEngine(); err != nil {
+		return errors.Wrap(err, "set engine")
+	}
+
+	storagers, err := db.NewLFSStoragers()
+	if err != nil {
+		return errors.Wrap(err, "new storagers")
+	}
+	from, to := lfsutil.Storage(c.String("from")), lfsutil.Storage(c.String("to"))
+	if from == to {
+		return errors.New("The source and target storage backends are the same")
+	}
+	src, ok := storagers[from]
+	if !ok {
+		return errors.Errorf("Storage backend %q is not available", from)
+	}
+	dst, ok := storagers[to]
+	if !ok {
+		return errors.Errorf("Storage backend %q is not available", to)
+	}
+
+	ctx := context.Background()
+	objects, err := db.LFS.GetObjectsByStorage(ctx, from)
+	if err != nil {
+		return errors.Wrap(err, "get objects by storage")
+	}
+
+	// The same object could be referenced by multiple repositories, but only needs
+	// to be migrated once.
+	migrated := make(map[lfsutil.OID]bool, len(objects))
+	for _, object := range objects {
+		if migrated[object.OID] {
+			continue
+		}
+
+		err = migrateLFSObject(src, dst, object)
+		if err != nil {
+			return errors.Wrapf(err, "migrate object %q", object.OID)
+		}
+
+		err = db.LFS.UpdateObjectsStorage(ctx, object.OID, from, to)
+		if err != nil {
+			return errors.Wrapf(err, "update storage of object %q", object.OID)
+		}
+		migrated[object.OID] = true
+	}
+
+	fmt.Printf("%d LFS objects have been migrated from %q to %q successfully\n", len(migrated), from, to)
+	return nil
+}
+
+// migrateLFSObject streams the content of the object from the source storage
+// backend to the destination storage backend.
+func migrateLFSObject(src, dst lfsutil.Storager, object *db.LFSObject) error {
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(src.Download(object.OID, pw))
+	}()
+
+	written, err := dst.Upload(object.OID, pr)
+	if err != nil {
+		return errors.Wrap(err, "upload")
+	} else if written != object.Size {
+		return errors.Errorf("size mismatch: expect %d but got %d", object.Size, written)
+	}
+	return nil
+}
+
 func adminDashboardOperation(operation func() error, successMessage string) func(*cli.Context) error {
 	return func(c *cli.Context) error {
 		err := conf.Init(c.String("config"))
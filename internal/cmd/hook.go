@@ -7,6 +7,7 @@ package cmd
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net/url"
@@ -69,6 +70,24 @@ func runHookPreReceive(c *cli.Context) error {
 	setup(c, "pre-receive.log", true)
 
 	isWiki := strings.Contains(os.Getenv(db.ENV_REPO_CUSTOM_HOOKS_PATH), ".wiki.git/")
+	repoID := com.StrTo(os.Getenv(db.ENV_REPO_ID)).MustInt64()
+	userID := com.StrTo(os.Getenv(db.ENV_AUTH_USER_ID)).MustInt64()
+	repoPath := db.RepoPath(os.Getenv(db.ENV_REPO_OWNER_NAME), os.Getenv(db.ENV_REPO_NAME))
+
+	// Files locked by other users are not allowed to be changed
+	var lfsLocks map[string]*db.LFSLock
+	if !isWiki {
+		lfsLocks = make(map[string]*db.LFSLock)
+		locks, err := db.LFS.ListLocks(context.Background(), repoID, db.ListLFSLocksOptions{})
+		if err != nil {
+			fail("Internal error", "Failed to list LFS locks [repo_id: %d]: %v", repoID, err)
+		}
+		for _, lock := range locks {
+			if lock.OwnerID != userID {
+				lfsLocks[lock.Path] = lock
+			}
+		}
+	}
 
 	buf := bytes.NewBuffer(nil)
 	scanner := bufio.NewScanner(os.Stdin)
@@ -88,8 +107,28 @@ func runHookPreReceive(c *cli.Context) error {
 		newCommitID := string(fields[1])
 		branchName := git.RefShortName(string(fields[2]))
 
+		// LFS locks
+		if len(lfsLocks) > 0 && newCommitID != git.EmptyID && strings.HasPrefix(string(fields[2]), git.RefsHeads) {
+			paths, err := changedPaths(repoPath, oldCommitID, newCommitID)
+			if err != nil {
+				fail("Internal error", "Failed to get changed paths: %v", err)
+			}
+			for _, path := range paths {
+				lock, ok := lfsLocks[path]
+				if !ok {
+					continue
+				}
+
+				ownerName := "another user"
+				owner, err := db.Users.GetByID(context.Background(), lock.OwnerID)
+				if err == nil {
+					ownerName = owner.Name
+				}
+				fail(fmt.Sprintf("Path '%s' is locked by %s", path, ownerName), "")
+			}
+		}
+
 		// Branch protection
-		repoID := com.StrTo(os.Getenv(db.ENV_REPO_ID)).MustInt64()
 		protectBranch, err := db.GetProtectBranchOfRepoByName(repoID, branchName)
 		if err != nil {
 			if db.IsErrBranchNotExist(err) {
@@ -105,7 +144,6 @@ func runHookPreReceive(c *cli.Context) error {
 		bypassRequirePullRequest := false
 
 		// Check if user is in whitelist when enabled
-		userID := com.StrTo(os.Getenv(db.ENV_AUTH_USER_ID)).MustInt64()
 		if protectBranch.EnableWhitelist {
 			if !db.IsUserInProtectBranchWhitelist(repoID, userID, branchName) {
 				fail(fmt.Sprintf("Branch '%s' is protected and you are not in the push whitelist", branchName), "")
@@ -126,7 +164,7 @@ func runHookPreReceive(c *cli.Context) error {
 
 		// Check force push
 		output, err := git.NewCommand("rev-list", "--max-count=1", oldCommitID, "^"+newCommitID).
-			RunInDir(db.RepoPath(os.Getenv(db.ENV_REPO_OWNER_NAME), os.Getenv(db.ENV_REPO_NAME)))
+			RunInDir(repoPath)
 		if err != nil {
 			fail("Internal error", "Failed to detect force push: %v", err)
 		} else if len(output) > 0 {
@@ -145,7 +183,7 @@ func runHookPreReceive(c *cli.Context) error {
 	} else {
 		hookCmd = exec.Command(customHooksPath)
 	}
-	hookCmd.Dir = db.RepoPath(os.Getenv(db.ENV_REPO_OWNER_NAME), os.Getenv(db.ENV_REPO_NAME))
+	hookCmd.Dir = repoPath
 	hookCmd.Stdout = os.Stdout
 	hookCmd.Stdin = buf
 	hookCmd.Stderr = os.Stderr
@@ -155,6 +193,31 @@ func runHookPreReceive(c *cli.Context) error {
 	return nil
 }
 
+// changedPaths returns paths of files that are changed by updating a reference
+// from the old commit to the new commit. For a new reference, all files changed
+// by commits that are not reachable from any existing reference are returned.
+func changedPaths(repoPath, oldCommitID, newCommitID string) ([]string, error) {
+	var cmd *git.Command
+	if oldCommitID == git.EmptyID {
+		cmd = git.NewCommand("log", "--format=", "--name-only", "-z", newCommitID, "--not", "--all")
+	} else {
+		cmd = git.NewCommand("diff", "--name-only", "-z", oldCommitID, newCommitID)
+	}
+	output, err := cmd.RunInDir(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, path := range bytes.Split(output, []byte{0}) {
+		path = bytes.TrimSpace(path)
+		if len(path) > 0 {
+			paths = append(paths, string(path))
+		}
+	}
+	return paths, nil
+}
+
 func runHookUpdate(c *cli.Context) error {
 	if os.Getenv("SSH_ORIGINAL_COMMAND") == "" {
 		return nil
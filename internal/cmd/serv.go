@@ -208,6 +208,9 @@ func runServ(c *cli.Context) error {
 			if err != nil {
 				fail("Internal error", "Failed to get user by key ID '%d': %v", key.ID, err)
 			}
+			if user.ProhibitLogin {
+				fail(_ACCESS_DENIED_MESSAGE, "User '%s' is not allowed to sign in", user.Name)
+			}
 
 			mode := db.Perms.AccessMode(context.Background(), user.ID, repo.ID,
 				db.AccessModeOptions{
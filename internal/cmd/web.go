@@ -43,6 +43,7 @@ import (
 	"gogs.io/gogs/internal/route/lfs"
 	"gogs.io/gogs/internal/route/org"
 	"gogs.io/gogs/internal/route/repo"
+	"gogs.io/gogs/internal/route/scim"
 	"gogs.io/gogs/internal/route/user"
 	"gogs.io/gogs/internal/template"
 	"gogs.io/gogs/public"
@@ -187,10 +188,17 @@ func runWeb(c *cli.Context) error {
 			m.Get("/repos", route.ExploreRepos)
 			m.Get("/users", route.ExploreUsers)
 			m.Get("/organizations", route.ExploreOrganizations)
+			m.Get("/code", route.ExploreCode)
 		}, ignSignIn)
 		m.Combo("/install", route.InstallInit).Get(route.Install).
 			Post(bindIgnErr(form.Install{}), route.InstallPost)
 		m.Get("/^:type(issues|pulls)$", reqSignIn, user.Issues)
+		m.Group("/notifications", func() {
+			m.Get("", user.Notifications)
+			m.Post("/read", user.MarkAllNotificationsRead)
+			m.Get("/:id", user.NotificationThread)
+			m.Post("/:id/read", user.MarkNotificationRead)
+		}, reqSignIn)
 
 		// ***** START: User *****
 		m.Group("/user", func() {
@@ -199,6 +207,8 @@ func runWeb(c *cli.Context) error {
 					Post(bindIgnErr(form.SignIn{}), user.LoginPost)
 				m.Combo("/two_factor").Get(user.LoginTwoFactor).Post(user.LoginTwoFactorPost)
 				m.Combo("/two_factor_recovery_code").Get(user.LoginTwoFactorRecoveryCode).Post(user.LoginTwoFactorRecoveryCodePost)
+				m.Get("/oidc/:id", user.LoginOIDC)
+				m.Get("/oidc/:id/callback", user.LoginOIDCCallback)
 			})
 
 			m.Get("/sign_up", user.SignUp)
@@ -228,6 +238,11 @@ func runWeb(c *cli.Context) error {
 				m.Combo("/two_factor_recovery_codes").Get(user.SettingsTwoFactorRecoveryCodes).
 					Post(user.SettingsTwoFactorRecoveryCodesPost)
 				m.Post("/two_factor_disable", user.SettingsTwoFactorDisable)
+				m.Group("/webauthn", func() {
+					m.Get("/options", user.SettingsWebAuthnOptions)
+					m.Post("/register", user.SettingsWebAuthnRegisterPost)
+					m.Post("/delete", user.SettingsWebAuthnDelete)
+				})
 			})
 			m.Group("/repositories", func() {
 				m.Get("", user.SettingsRepos)
@@ -240,6 +255,15 @@ func runWeb(c *cli.Context) error {
 			m.Combo("/applications").Get(user.SettingsApplications).
 				Post(bindIgnErr(form.NewAccessToken{}), user.SettingsApplicationsPost)
 			m.Post("/applications/delete", user.SettingsDeleteApplication)
+			m.Group("/applications/oauth2", func() {
+				m.Combo("/new").Get(user.SettingsOAuth2ApplicationNew).
+					Post(bindIgnErr(form.OAuth2Application{}), user.SettingsOAuth2ApplicationNewPost)
+				m.Combo("/:id").Get(user.SettingsOAuth2Application).
+					Post(bindIgnErr(form.OAuth2Application{}), user.SettingsOAuth2ApplicationPost)
+				m.Post("/:id/regenerate_secret", user.SettingsOAuth2ApplicationRegenerateSecret)
+				m.Post("/:id/delete", user.SettingsOAuth2ApplicationDelete)
+				m.Post("/grants/:id/revoke", user.SettingsOAuth2GrantRevoke)
+			})
 			m.Route("/delete", "GET,POST", user.SettingsDelete)
 		}, reqSignIn, func(c *context.Context) {
 			c.Data["PageIsUserSettings"] = true
@@ -253,6 +277,13 @@ func runWeb(c *cli.Context) error {
 			m.Post("/forget_password", user.ForgotPasswdPost)
 			m.Post("/logout", user.SignOut)
 		})
+
+		m.Group("/login/oauth", func() {
+			m.Combo("/authorize", reqSignIn).Get(user.OAuth2Authorize).
+				Post(user.OAuth2AuthorizePost)
+			m.Post("/access_token", user.OAuth2AccessToken)
+			m.Post("/revoke", user.OAuth2Revoke)
+		})
 		// ***** END: User *****
 
 		reqAdmin := context.Toggle(&context.ToggleOptions{SignInRequired: true, AdminRequired: true})
@@ -293,6 +324,14 @@ func runWeb(c *cli.Context) error {
 				m.Post("/delete", admin.DeleteNotices)
 				m.Get("/empty", admin.EmptyNotices)
 			})
+
+			m.Group("/hook_tasks", func() {
+				m.Get("", admin.FailedHookTasks)
+				m.Post("/redeliver", admin.RedeliverHookTasks)
+				m.Post("/redeliver_all", admin.RedeliverAllHookTasks)
+			})
+
+			m.Get("/queues", admin.Queues)
 		}, reqAdmin)
 		// ***** END: Admin *****
 
@@ -354,11 +393,17 @@ func runWeb(c *cli.Context) error {
 				m.Post("/slack/new", bindIgnErr(form.NewSlackHook{}), repo.WebhooksSlackNewPost)
 				m.Post("/discord/new", bindIgnErr(form.NewDiscordHook{}), repo.WebhooksDiscordNewPost)
 				m.Post("/dingtalk/new", bindIgnErr(form.NewDingtalkHook{}), repo.WebhooksDingtalkNewPost)
+				m.Post("/mattermost/new", bindIgnErr(form.NewMattermostHook{}), repo.WebhooksMattermostNewPost)
+				m.Post("/teams/new", bindIgnErr(form.NewTeamsHook{}), repo.WebhooksTeamsNewPost)
+				m.Post("/custom/new", bindIgnErr(form.NewCustomHook{}), repo.WebhooksCustomNewPost)
 				m.Get("/:id", repo.WebhooksEdit)
 				m.Post("/gogs/:id", bindIgnErr(form.NewWebhook{}), repo.WebhooksEditPost)
 				m.Post("/slack/:id", bindIgnErr(form.NewSlackHook{}), repo.WebhooksSlackEditPost)
 				m.Post("/discord/:id", bindIgnErr(form.NewDiscordHook{}), repo.WebhooksDiscordEditPost)
 				m.Post("/dingtalk/:id", bindIgnErr(form.NewDingtalkHook{}), repo.WebhooksDingtalkEditPost)
+				m.Post("/mattermost/:id", bindIgnErr(form.NewMattermostHook{}), repo.WebhooksMattermostEditPost)
+				m.Post("/teams/:id", bindIgnErr(form.NewTeamsHook{}), repo.WebhooksTeamsEditPost)
+				m.Post("/custom/:id", bindIgnErr(form.NewCustomHook{}), repo.WebhooksCustomEditPost)
 			}, repo.InjectOrgRepoContext())
 		}
 
@@ -416,6 +461,7 @@ func runWeb(c *cli.Context) error {
 			m.Post("/create", bindIgnErr(form.CreateRepo{}), repo.CreatePost)
 			m.Get("/migrate", repo.Migrate)
 			m.Post("/migrate", bindIgnErr(form.MigrateRepo{}), repo.MigratePost)
+			m.Get("/migrate/:id", repo.MigrateStatus)
 			m.Combo("/fork/:repoid").Get(repo.Fork).
 				Post(bindIgnErr(form.CreateRepo{}), repo.ForkPost)
 		}, reqSignIn)
@@ -464,6 +510,13 @@ func runWeb(c *cli.Context) error {
 						Post(bindIgnErr(form.AddSSHKey{}), repo.SettingsDeployKeysPost)
 					m.Post("/delete", repo.DeleteDeployKey)
 				})
+
+				m.Group("/push_mirrors", func() {
+					m.Combo("").Get(repo.SettingsPushMirrors).
+						Post(bindIgnErr(form.NewPushMirror{}), repo.SettingsPushMirrorsPost)
+					m.Post("/delete", repo.DeletePushMirror)
+					m.Post("/:id/sync", repo.SyncPushMirror)
+				})
 			}, func(c *context.Context) {
 				c.Data["PageIsSettings"] = true
 			})
@@ -604,14 +657,18 @@ func runWeb(c *cli.Context) error {
 				m.Get("/commits", context.RepoRef(), repo.ViewPullCommits)
 				m.Get("/files", context.RepoRef(), repo.ViewPullFiles)
 				m.Post("/merge", reqRepoWriter, repo.MergePullRequest)
+				m.Post("/files/comments", reqSignIn, repo.CreateCodeComment)
+				m.Post("/reviews", reqSignIn, repo.SubmitReview)
 			}, repo.MustAllowPulls)
 
 			m.Group("", func() {
 				m.Get("/src/*", repo.Home)
+				m.Get("/blame/*", repo.Blame)
 				m.Get("/raw/*", repo.SingleDownload)
 				m.Get("/commits/*", repo.RefCommits)
 				m.Get("/commit/:sha([a-f0-9]{7,40})$", repo.Diff)
 				m.Get("/forks", repo.Forks)
+				m.Get("/search", repo.SearchCode)
 			}, repo.MustBeNotBare, context.RepoRef())
 			m.Get("/commit/:sha([a-f0-9]{7,40})\\.:ext(patch|diff)", repo.MustBeNotBare, repo.RawDiff)
 
@@ -655,6 +712,18 @@ func runWeb(c *cli.Context) error {
 		context.Contexter(),
 	)
 
+	// ***************************
+	// ----- SCIM routes -----
+	// ***************************
+
+	// NOTE: Must be registered before HTTP Git routes, otherwise requests like
+	// "/scim/v2/Users" are captured by "/:username/:reponame".
+	if conf.SCIM.Enabled {
+		m.Group("/scim/v2", func() {
+			scim.RegisterRoutes(m.Router)
+		})
+	}
+
 	// ***************************
 	// ----- HTTP Git routes -----
 	// ***************************
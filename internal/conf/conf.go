@@ -348,6 +348,39 @@ func Init(customConf string) error {
 		return errors.Wrap(err, "mapping [lfs] section")
 	}
 	LFS.ObjectsPath = ensureAbs(LFS.ObjectsPath)
+	switch LFS.Storage {
+	case "local":
+	case "s3":
+		if LFS.S3Endpoint == "" || LFS.S3Bucket == "" {
+			return errors.New("[lfs] S3_ENDPOINT and S3_BUCKET are required for storage \"s3\"")
+		}
+	default:
+		return errors.Errorf("[lfs] unsupported storage %q", LFS.Storage)
+	}
+
+	// *****************************
+	// ----- Indexer settings -----
+	// *****************************
+
+	if err = File.Section("indexer").MapTo(&Indexer); err != nil {
+		return errors.Wrap(err, "mapping [indexer] section")
+	}
+	Indexer.IssuePath = ensureAbs(Indexer.IssuePath)
+	Indexer.RepoPath = ensureAbs(Indexer.RepoPath)
+
+	// ***************************
+	// ----- Queue settings -----
+	// ***************************
+
+	if err = File.Section("queue").MapTo(&Queue); err != nil {
+		return errors.Wrap(err, "mapping [queue] section")
+	}
+	Queue.Path = ensureAbs(Queue.Path)
+	switch Queue.Type {
+	case "database", "disk":
+	default:
+		return errors.Errorf("[queue] unsupported type %q", Queue.Type)
+	}
 
 	handleDeprecated()
 
@@ -371,6 +404,8 @@ func Init(customConf string) error {
 		return errors.Wrap(err, "mapping [git] section")
 	} else if err = File.Section("api").MapTo(&API); err != nil {
 		return errors.Wrap(err, "mapping [api] section")
+	} else if err = File.Section("scim").MapTo(&SCIM); err != nil {
+		return errors.Wrap(err, "mapping [scim] section")
 	} else if err = File.Section("ui").MapTo(&UI); err != nil {
 		return errors.Wrap(err, "mapping [ui] section")
 	} else if err = File.Section("prometheus").MapTo(&Prometheus); err != nil {
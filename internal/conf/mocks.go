@@ -32,6 +32,14 @@ func SetMockSSH(t *testing.T, opts SSHOpts) {
 	})
 }
 
+func SetMockLFS(t *testing.T, opts LFSOpts) {
+	before := LFS
+	LFS = opts
+	t.Cleanup(func() {
+		LFS = before
+	})
+}
+
 func SetMockRepository(t *testing.T, opts RepositoryOpts) {
 	before := Repository
 	Repository = opts
@@ -159,10 +159,14 @@ var (
 
 	// Webhook settings
 	Webhook struct {
-		Types          []string
-		DeliverTimeout int
-		SkipTLSVerify  bool `ini:"SKIP_TLS_VERIFY"`
-		PagingNum      int
+		Types           []string
+		DeliverTimeout  int
+		SkipTLSVerify   bool `ini:"SKIP_TLS_VERIFY"`
+		PagingNum       int
+		MaxAttempts     int
+		RetryInterval   time.Duration
+		Concurrency     int
+		HostConcurrency int
 	}
 
 	// Markdown settings
@@ -211,6 +215,23 @@ var (
 			Schedule   string
 			OlderThan  time.Duration
 		} `ini:"cron.repo_archive_cleanup"`
+		LFSGarbageCollection struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+			OlderThan  time.Duration
+		} `ini:"cron.lfs_gc"`
+		AccessTokenExpiry struct {
+			Enabled      bool
+			RunAtStart   bool
+			Schedule     string
+			NotifyBefore time.Duration
+		} `ini:"cron.access_token_expiry"`
+		ExternalGroupSync struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+		} `ini:"cron.external_group_sync"`
 	}
 
 	// Git settings
@@ -238,6 +259,14 @@ var (
 		MaxResponseItems int
 	}
 
+	// SCIM settings
+	SCIM struct {
+		Enabled bool
+		// The ID of the login source that users created via SCIM are linked to, zero
+		// value means local users.
+		LoginSource int64
+	}
+
 	// Prometheus settings
 	Prometheus struct {
 		Enabled           bool
@@ -370,11 +399,49 @@ var Database DatabaseOpts
 type LFSOpts struct {
 	Storage     string
 	ObjectsPath string
+
+	S3Endpoint        string        `ini:"S3_ENDPOINT"`
+	S3AccessKeyID     string        `ini:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey string        `ini:"S3_SECRET_ACCESS_KEY"`
+	S3Bucket          string        `ini:"S3_BUCKET"`
+	S3Region          string        `ini:"S3_REGION"`
+	S3UseSSL          bool          `ini:"S3_USE_SSL"`
+	S3PresignExpiry   time.Duration `ini:"S3_PRESIGN_EXPIRY"`
+
+	// The maximum total size in MB of LFS objects, zero value means no limit.
+	RepoQuota  int64
+	OwnerQuota int64
 }
 
 // LFS settings
 var LFS LFSOpts
 
+type IndexerOpts struct {
+	// The path to store the issue search index.
+	IssuePath string
+	// The path to store the code search index.
+	RepoPath string
+	// Files larger than this size in bytes are not indexed for code search.
+	MaxFileSize int64
+}
+
+// Indexer settings
+var Indexer IndexerOpts
+
+type QueueOpts struct {
+	// The type of task queues, either "database" or "disk".
+	Type string
+	// The path to store task queues of type "disk".
+	Path string
+	// The duration that a task being processed is invisible to other consumers.
+	VisibilityTimeout time.Duration
+	MaxAttempts       int
+	RetryInterval     time.Duration
+}
+
+// Queue settings
+var Queue QueueOpts
+
 type UIUserOpts struct {
 	RepoPagingNum     int
 	NewsFeedPagingNum int
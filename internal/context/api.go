@@ -93,6 +93,25 @@ func (c *APIContext) SetLinkHeader(total, pageSize int) {
 
 func APIContexter() macaron.Handler {
 	return func(ctx *Context) {
+		// Users authenticated without a session are never restricted to security
+		// settings, reject them until they enable 2FA as required by their
+		// organizations.
+		if ctx.IsBasicAuth || ctx.IsTokenAuth {
+			names, err := twoFactorRequiredBy(ctx.User)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, map[string]string{
+					"message": "Internal server error",
+				})
+				log.Error("Failed to check two-factor requirement [user_id: %d]: %v", ctx.User.ID, err)
+				return
+			} else if len(names) > 0 {
+				ctx.JSON(http.StatusForbidden, map[string]string{
+					"message": fmt.Sprintf("Organization %q requires members to enable two-factor authentication.", names[0]),
+				})
+				return
+			}
+		}
+
 		c := &APIContext{
 			Context: ctx,
 			BaseURL: conf.Server.ExternalURL + "api/v1",
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/go-macaron/csrf"
 	"github.com/go-macaron/session"
@@ -43,6 +44,27 @@ func Toggle(options *ToggleOptions) macaron.Handler {
 			return
 		}
 
+		// Organizations may require 2FA after the user signed in, re-check
+		// periodically rather than on every request.
+		if c.IsLogged && !c.IsBasicAuth && !c.IsTokenAuth {
+			checkedAt, _ := c.Session.Get("twoFactorRequiredCheckedAt").(int64)
+			if time.Since(time.Unix(checkedAt, 0)) > twoFactorRequiredCheckInterval {
+				if err := c.CheckTwoFactorRequired(c.User); err != nil {
+					c.Error(err, "check two-factor requirement")
+					return
+				}
+			}
+		}
+
+		// Restrict users to security settings until they enable 2FA as required by
+		// their organizations.
+		if c.IsLogged && c.Session.Get("twoFactorRequired") != nil && !isAPIPath(c.Req.URL.Path) &&
+			!strings.HasPrefix(c.Req.URL.Path, "/user/settings/security") &&
+			c.Req.URL.Path != "/user/logout" {
+			c.RedirectSubpath("/user/settings/security")
+			return
+		}
+
 		// Check non-logged users landing page.
 		if !c.IsLogged && c.Req.RequestURI == "/" && conf.Server.LandingURL != "/" {
 			c.RedirectSubpath(conf.Server.LandingURL)
@@ -100,15 +122,57 @@ func Toggle(options *ToggleOptions) macaron.Handler {
 	}
 }
 
+// twoFactorRequiredCheckInterval is the interval to re-check whether the
+// signed in user is required to enable 2FA by organizations.
+const twoFactorRequiredCheckInterval = 5 * time.Minute
+
+// twoFactorRequiredBy returns names of organizations that require the user to
+// enable 2FA. It returns nil when the user has enabled 2FA.
+func twoFactorRequiredBy(u *db.User) ([]string, error) {
+	if u.IsEnabledTwoFactor() {
+		return nil, nil
+	}
+
+	orgs, err := db.GetOrgsRequireTwoFactorByUserID(u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(orgs))
+	for i := range orgs {
+		names[i] = orgs[i].Name
+	}
+	return names, nil
+}
+
+// CheckTwoFactorRequired marks the session to be restricted to security
+// settings when the user has not enabled 2FA but joined any organization that
+// requires members to do so.
+func (c *Context) CheckTwoFactorRequired(u *db.User) error {
+	names, err := twoFactorRequiredBy(u)
+	if err != nil {
+		return err
+	}
+
+	_ = c.Session.Set("twoFactorRequiredCheckedAt", time.Now().Unix())
+	if len(names) == 0 {
+		_ = c.Session.Delete("twoFactorRequired")
+		return nil
+	}
+	_ = c.Session.Set("twoFactorRequired", strings.Join(names, ", "))
+	return nil
+}
+
 func isAPIPath(url string) bool {
 	return strings.HasPrefix(url, "/api/")
 }
 
 // authenticatedUserID returns the ID of the authenticated user, along with a bool value
-// which indicates whether the user uses token authentication.
-func authenticatedUserID(c *macaron.Context, sess session.Store) (_ int64, isTokenAuth bool) {
+// which indicates whether the user uses token authentication and the scopes granted to
+// the token.
+func authenticatedUserID(c *macaron.Context, sess session.Store) (_ int64, isTokenAuth bool, scopes auth.Scopes) {
 	if !db.HasEngine {
-		return 0, false
+		return 0, false, nil
 	}
 
 	// Check access token.
@@ -122,7 +186,7 @@ func authenticatedUserID(c *macaron.Context, sess session.Store) (_ int64, isTok
 			auHead := c.Req.Header.Get("Authorization")
 			if len(auHead) > 0 {
 				auths := strings.Fields(auHead)
-				if len(auths) == 2 && auths[0] == "token" {
+				if len(auths) == 2 && (auths[0] == "token" || strings.EqualFold(auths[0], "bearer")) {
 					tokenSHA = auths[1]
 				}
 			}
@@ -131,43 +195,53 @@ func authenticatedUserID(c *macaron.Context, sess session.Store) (_ int64, isTok
 		// Let's see if token is valid.
 		if len(tokenSHA) > 0 {
 			t, err := db.AccessTokens.GetBySHA1(c.Req.Context(), tokenSHA)
-			if err != nil {
-				if !db.IsErrAccessTokenNotExist(err) {
-					log.Error("GetAccessTokenBySHA: %v", err)
+			if err == nil {
+				if err = db.AccessTokens.Touch(c.Req.Context(), t.ID); err != nil {
+					log.Error("Failed to touch access token: %v", err)
 				}
-				return 0, false
+				return t.UserID, true, t.ScopeList()
+			} else if !db.IsErrAccessTokenNotExist(err) {
+				log.Error("GetAccessTokenBySHA: %v", err)
+				return 0, false, nil
 			}
-			if err = db.AccessTokens.Touch(c.Req.Context(), t.ID); err != nil {
-				log.Error("Failed to touch access token: %v", err)
+
+			// Fall back to check with tokens issued to OAuth2 applications.
+			ot, err := db.OAuth2.GetTokenByAccessToken(c.Req.Context(), tokenSHA)
+			if err != nil {
+				if !db.IsErrOAuth2TokenNotExist(err) {
+					log.Error("Failed to get OAuth2 token: %v", err)
+				}
+				return 0, false, nil
 			}
-			return t.UserID, true
+			return ot.UserID, true, ot.ScopeList()
 		}
 	}
 
 	uid := sess.Get("uid")
 	if uid == nil {
-		return 0, false
+		return 0, false, nil
 	}
 	if id, ok := uid.(int64); ok {
 		if _, err := db.GetUserByID(id); err != nil {
 			if !db.IsErrUserNotExist(err) {
 				log.Error("Failed to get user by ID: %v", err)
 			}
-			return 0, false
+			return 0, false, nil
 		}
-		return id, false
+		return id, false, nil
 	}
-	return 0, false
+	return 0, false, nil
 }
 
 // authenticatedUser returns the user object of the authenticated user, along with two bool values
-// which indicate whether the user uses HTTP Basic Authentication or token authentication respectively.
-func authenticatedUser(ctx *macaron.Context, sess session.Store) (_ *db.User, isBasicAuth, isTokenAuth bool) {
+// which indicate whether the user uses HTTP Basic Authentication or token authentication respectively,
+// and the scopes granted to the token.
+func authenticatedUser(ctx *macaron.Context, sess session.Store) (_ *db.User, isBasicAuth, isTokenAuth bool, scopes auth.Scopes) {
 	if !db.HasEngine {
-		return nil, false, false
+		return nil, false, false, nil
 	}
 
-	uid, isTokenAuth := authenticatedUserID(ctx, sess)
+	uid, isTokenAuth, scopes := authenticatedUserID(ctx, sess)
 
 	if uid <= 0 {
 		if conf.Auth.EnableReverseProxyAuthentication {
@@ -177,7 +251,7 @@ func authenticatedUser(ctx *macaron.Context, sess session.Store) (_ *db.User, is
 				if err != nil {
 					if !db.IsErrUserNotExist(err) {
 						log.Error("Failed to get user by name: %v", err)
-						return nil, false, false
+						return nil, false, false, nil
 					}
 
 					// Check if enabled auto-registration.
@@ -191,13 +265,13 @@ func authenticatedUser(ctx *macaron.Context, sess session.Store) (_ *db.User, is
 						if err = db.CreateUser(u); err != nil {
 							// FIXME: should I create a system notice?
 							log.Error("Failed to create user: %v", err)
-							return nil, false, false
+							return nil, false, false, nil
 						} else {
-							return u, false, false
+							return u, false, false, nil
 						}
 					}
 				}
-				return u, false, false
+				return u, false, false, nil
 			}
 		}
 
@@ -213,19 +287,19 @@ func authenticatedUser(ctx *macaron.Context, sess session.Store) (_ *db.User, is
 					if !auth.IsErrBadCredentials(err) {
 						log.Error("Failed to authenticate user: %v", err)
 					}
-					return nil, false, false
+					return nil, false, false, nil
 				}
 
-				return u, true, false
+				return u, true, false, nil
 			}
 		}
-		return nil, false, false
+		return nil, false, false, nil
 	}
 
 	u, err := db.GetUserByID(uid)
 	if err != nil {
 		log.Error("GetUserByID: %v", err)
-		return nil, false, false
+		return nil, false, false, nil
 	}
-	return u, false, isTokenAuth
+	return u, false, isTokenAuth, scopes
 }
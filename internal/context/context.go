@@ -18,6 +18,7 @@ import (
 	"gopkg.in/macaron.v1"
 	log "unknwon.dev/clog/v2"
 
+	"gogs.io/gogs/internal/auth"
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/errutil"
@@ -39,6 +40,7 @@ type Context struct {
 	IsLogged    bool
 	IsBasicAuth bool
 	IsTokenAuth bool
+	TokenScopes auth.Scopes // The scopes granted to the token when IsTokenAuth is true
 
 	Repo *Repository
 	Org  *Organization
@@ -260,7 +262,7 @@ func Contexter() macaron.Handler {
 		}
 
 		// Get user from session or header when possible
-		c.User, c.IsBasicAuth, c.IsTokenAuth = authenticatedUser(c.Context, c.Session)
+		c.User, c.IsBasicAuth, c.IsTokenAuth, c.TokenScopes = authenticatedUser(c.Context, c.Session)
 
 		if c.User != nil {
 			c.IsLogged = true
@@ -269,6 +271,15 @@ func Contexter() macaron.Handler {
 			c.Data["LoggedUserID"] = c.User.ID
 			c.Data["LoggedUserName"] = c.User.Name
 			c.Data["IsAdmin"] = c.User.IsAdmin
+
+			// Only pages show the number of unread notifications.
+			if c.Req.Method == http.MethodGet {
+				count, err := db.Notifications.CountUnread(c.Req.Context(), c.User)
+				if err != nil {
+					log.Error("Failed to count unread notifications of user %d: %v", c.User.ID, err)
+				}
+				c.Data["NotificationUnreadCount"] = count
+			}
 		} else {
 			c.Data["LoggedUserID"] = 0
 			c.Data["LoggedUserName"] = ""
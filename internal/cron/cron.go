@@ -66,9 +66,60 @@ func NewContext() {
 			go db.DeleteOldRepositoryArchives()
 		}
 	}
+	if conf.Cron.LFSGarbageCollection.Enabled {
+		entry, err = c.AddFunc("LFS garbage collection", conf.Cron.LFSGarbageCollection.Schedule, garbageCollectLFSObjects)
+		if err != nil {
+			log.Fatal("Cron.(LFS garbage collection): %v", err)
+		}
+		if conf.Cron.LFSGarbageCollection.RunAtStart {
+			entry.Prev = time.Now()
+			entry.ExecTimes++
+			go garbageCollectLFSObjects()
+		}
+	}
+	if conf.Cron.AccessTokenExpiry.Enabled {
+		entry, err = c.AddFunc("Access token expiry notification", conf.Cron.AccessTokenExpiry.Schedule, notifyExpiringAccessTokens)
+		if err != nil {
+			log.Fatal("Cron.(access token expiry notification): %v", err)
+		}
+		if conf.Cron.AccessTokenExpiry.RunAtStart {
+			entry.Prev = time.Now()
+			entry.ExecTimes++
+			go notifyExpiringAccessTokens()
+		}
+	}
+	if conf.Cron.ExternalGroupSync.Enabled {
+		entry, err = c.AddFunc("External group synchronization", conf.Cron.ExternalGroupSync.Schedule, syncExternalGroups)
+		if err != nil {
+			log.Fatal("Cron.(external group synchronization): %v", err)
+		}
+		if conf.Cron.ExternalGroupSync.RunAtStart {
+			entry.Prev = time.Now()
+			entry.ExecTimes++
+			go syncExternalGroups()
+		}
+	}
 	c.Start()
 }
 
+func garbageCollectLFSObjects() {
+	if err := db.GarbageCollectLFSObjects(); err != nil {
+		log.Error("Failed to garbage collect LFS objects: %v", err)
+	}
+}
+
+func notifyExpiringAccessTokens() {
+	if err := db.NotifyExpiringAccessTokens(); err != nil {
+		log.Error("Failed to notify expiring access tokens: %v", err)
+	}
+}
+
+func syncExternalGroups() {
+	if err := db.SyncExternalGroups(); err != nil {
+		log.Error("Failed to sync external groups: %v", err)
+	}
+}
+
 // ListTasks returns all running cron tasks.
 func ListTasks() []*cron.Entry {
 	return c.Entries()
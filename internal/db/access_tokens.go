@@ -12,6 +12,7 @@ import (
 	gouuid "github.com/satori/go.uuid"
 	"gorm.io/gorm"
 
+	"gogs.io/gogs/internal/auth"
 	"gogs.io/gogs/internal/cryptoutil"
 	"gogs.io/gogs/internal/errutil"
 )
@@ -23,17 +24,23 @@ type AccessTokensStore interface {
 	// Create creates a new access token and persist to database. It returns
 	// ErrAccessTokenAlreadyExist when an access token with same name already exists
 	// for the user.
-	Create(ctx context.Context, userID int64, name string) (*AccessToken, error)
+	Create(ctx context.Context, userID int64, name string, opts CreateAccessTokenOptions) (*AccessToken, error)
 	// DeleteByID deletes the access token by given ID.
 	//
 	// 🚨 SECURITY: The "userID" is required to prevent attacker deletes arbitrary
 	// access token that belongs to another user.
 	DeleteByID(ctx context.Context, userID, id int64) error
 	// GetBySHA1 returns the access token with given SHA1. It returns
-	// ErrAccessTokenNotExist when not found.
+	// ErrAccessTokenNotExist when not found or the access token has expired.
 	GetBySHA1(ctx context.Context, sha1 string) (*AccessToken, error)
 	// List returns all access tokens belongs to given user.
 	List(ctx context.Context, userID int64) ([]*AccessToken, error)
+	// ListExpiring returns access tokens that have not expired but will expire
+	// before the given time, and their owners have not been notified.
+	ListExpiring(ctx context.Context, before time.Time) ([]*AccessToken, error)
+	// MarkExpiryNotified marks the owner of the access token has been notified
+	// about the upcoming expiry.
+	MarkExpiryNotified(ctx context.Context, id int64) error
 	// Touch updates the updated time of the given access token to the current time.
 	Touch(ctx context.Context, id int64) error
 }
@@ -47,6 +54,8 @@ type AccessToken struct {
 	Name   string
 	Sha1   string `gorm:"type:VARCHAR(40);unique"`
 	SHA256 string `gorm:"type:VARCHAR(64);unique;not null"`
+	// The space-separated list of scopes granted to the access token.
+	Scopes string `gorm:"type:VARCHAR(255);not null;default:''"`
 
 	Created           time.Time `gorm:"-" json:"-"`
 	CreatedUnix       int64
@@ -54,6 +63,12 @@ type AccessToken struct {
 	UpdatedUnix       int64
 	HasRecentActivity bool `gorm:"-" json:"-"`
 	HasUsed           bool `gorm:"-" json:"-"`
+
+	// The zero value means the access token never expires.
+	Expires        time.Time `gorm:"-" json:"-"`
+	ExpiresUnix    int64     `gorm:"not null;default:0"`
+	HasExpired     bool      `gorm:"-" json:"-"`
+	ExpiryNotified bool      `gorm:"not null;default:FALSE"`
 }
 
 // BeforeCreate implements the GORM create hook.
@@ -72,9 +87,19 @@ func (t *AccessToken) AfterFind(tx *gorm.DB) error {
 		t.HasUsed = t.Updated.After(t.Created)
 		t.HasRecentActivity = t.Updated.Add(7 * 24 * time.Hour).After(tx.NowFunc())
 	}
+	if t.ExpiresUnix > 0 {
+		t.Expires = time.Unix(t.ExpiresUnix, 0).Local()
+		t.HasExpired = !t.Expires.After(tx.NowFunc())
+	}
 	return nil
 }
 
+// ScopeList returns the list of scopes granted to the access token.
+func (t *AccessToken) ScopeList() auth.Scopes {
+	scopes, _ := auth.ParseScopes(t.Scopes)
+	return scopes
+}
+
 var _ AccessTokensStore = (*accessTokens)(nil)
 
 type accessTokens struct {
@@ -94,7 +119,13 @@ func (err ErrAccessTokenAlreadyExist) Error() string {
 	return fmt.Sprintf("access token already exists: %v", err.args)
 }
 
-func (db *accessTokens) Create(ctx context.Context, userID int64, name string) (*AccessToken, error) {
+type CreateAccessTokenOptions struct {
+	Scopes auth.Scopes
+	// The zero value means the access token never expires.
+	ExpiresAt time.Time
+}
+
+func (db *accessTokens) Create(ctx context.Context, userID int64, name string, opts CreateAccessTokenOptions) (*AccessToken, error) {
 	err := db.WithContext(ctx).Where("uid = ? AND name = ?", userID, name).First(new(AccessToken)).Error
 	if err == nil {
 		return nil, ErrAccessTokenAlreadyExist{args: errutil.Args{"userID": userID, "name": name}}
@@ -110,6 +141,11 @@ func (db *accessTokens) Create(ctx context.Context, userID int64, name string) (
 		Name:   name,
 		Sha1:   sha256[:40], // To pass the column unique constraint, keep the length of SHA1.
 		SHA256: sha256,
+		Scopes: opts.Scopes.String(),
+	}
+	if !opts.ExpiresAt.IsZero() {
+		accessToken.ExpiresUnix = opts.ExpiresAt.Unix()
+		accessToken.Expires = time.Unix(accessToken.ExpiresUnix, 0).Local()
 	}
 	if err = db.WithContext(ctx).Create(accessToken).Error; err != nil {
 		return nil, err
@@ -146,7 +182,10 @@ func (ErrAccessTokenNotExist) NotFound() bool {
 func (db *accessTokens) GetBySHA1(ctx context.Context, sha1 string) (*AccessToken, error) {
 	sha256 := cryptoutil.SHA256(sha1)
 	token := new(AccessToken)
-	err := db.WithContext(ctx).Where("sha256 = ?", sha256).First(token).Error
+	err := db.WithContext(ctx).
+		Where("sha256 = ? AND (expires_unix = 0 OR expires_unix > ?)", sha256, db.NowFunc().Unix()).
+		First(token).
+		Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrAccessTokenNotExist{args: errutil.Args{"sha": sha1}}
@@ -161,6 +200,23 @@ func (db *accessTokens) List(ctx context.Context, userID int64) ([]*AccessToken,
 	return tokens, db.WithContext(ctx).Where("uid = ?", userID).Order("id ASC").Find(&tokens).Error
 }
 
+func (db *accessTokens) ListExpiring(ctx context.Context, before time.Time) ([]*AccessToken, error) {
+	var tokens []*AccessToken
+	return tokens, db.WithContext(ctx).
+		Where("expires_unix > ? AND expires_unix <= ? AND expiry_notified = ?", db.NowFunc().Unix(), before.Unix(), false).
+		Order("id ASC").
+		Find(&tokens).
+		Error
+}
+
+func (db *accessTokens) MarkExpiryNotified(ctx context.Context, id int64) error {
+	return db.WithContext(ctx).
+		Model(new(AccessToken)).
+		Where("id = ?", id).
+		UpdateColumn("expiry_notified", true).
+		Error
+}
+
 func (db *accessTokens) Touch(ctx context.Context, id int64) error {
 	return db.WithContext(ctx).
 		Model(new(AccessToken)).
@@ -0,0 +1,56 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/email"
+)
+
+const _ACCESS_TOKEN_EXPIRY = "access_token_expiry"
+
+// NotifyExpiringAccessTokens sends mail notifications to owners of personal
+// access tokens that are going to expire within the configured period. Each
+// token is only notified once.
+func NotifyExpiringAccessTokens() error {
+	if taskStatusTable.IsRunning(_ACCESS_TOKEN_EXPIRY) {
+		return nil
+	}
+	taskStatusTable.Start(_ACCESS_TOKEN_EXPIRY)
+	defer taskStatusTable.Stop(_ACCESS_TOKEN_EXPIRY)
+
+	log.Trace("Doing: NotifyExpiringAccessTokens")
+
+	ctx := context.Background()
+	tokens, err := AccessTokens.ListExpiring(ctx, time.Now().Add(conf.Cron.AccessTokenExpiry.NotifyBefore))
+	if err != nil {
+		return errors.Wrap(err, "list expiring access tokens")
+	}
+
+	for _, t := range tokens {
+		if conf.Email.Enabled {
+			u, err := Users.GetByID(ctx, t.UserID)
+			if err != nil {
+				if IsErrUserNotExist(err) {
+					continue
+				}
+				return errors.Wrapf(err, "get user [id: %d]", t.UserID)
+			}
+			email.SendAccessTokenExpiryMail(NewMailerUser(u), t.Name, t.Expires)
+		}
+
+		err = AccessTokens.MarkExpiryNotified(ctx, t.ID)
+		if err != nil {
+			return errors.Wrapf(err, "mark expiry notified [id: %d]", t.ID)
+		}
+	}
+	return nil
+}
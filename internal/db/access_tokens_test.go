@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 
+	"gogs.io/gogs/internal/auth"
 	"gogs.io/gogs/internal/dbtest"
 	"gogs.io/gogs/internal/errutil"
 )
@@ -61,6 +62,8 @@ func TestAccessTokens(t *testing.T) {
 		{"DeleteByID", accessTokensDeleteByID},
 		{"GetBySHA1", accessTokensGetBySHA},
 		{"List", accessTokensList},
+		{"ListExpiring", accessTokensListExpiring},
+		{"MarkExpiryNotified", accessTokensMarkExpiryNotified},
 		{"Touch", accessTokensTouch},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -80,20 +83,29 @@ func accessTokensCreate(t *testing.T, db *accessTokens) {
 	ctx := context.Background()
 
 	// Create first access token with name "Test"
-	token, err := db.Create(ctx, 1, "Test")
+	expiresAt := db.NowFunc().Add(24 * time.Hour)
+	token, err := db.Create(ctx, 1, "Test",
+		CreateAccessTokenOptions{
+			Scopes:    auth.Scopes{auth.ScopeRepoRead, auth.ScopeUser},
+			ExpiresAt: expiresAt,
+		},
+	)
 	require.NoError(t, err)
 
 	assert.Equal(t, int64(1), token.UserID)
 	assert.Equal(t, "Test", token.Name)
 	assert.Equal(t, 40, len(token.Sha1), "sha1 length")
 
-	// Get it back and check the Created field
+	// Get it back and check the Created, Expires and Scopes fields
 	token, err = db.GetBySHA1(ctx, token.Sha1)
 	require.NoError(t, err)
 	assert.Equal(t, db.NowFunc().Format(time.RFC3339), token.Created.UTC().Format(time.RFC3339))
+	assert.Equal(t, expiresAt.Format(time.RFC3339), token.Expires.UTC().Format(time.RFC3339))
+	assert.False(t, token.HasExpired)
+	assert.Equal(t, auth.Scopes{auth.ScopeRepoRead, auth.ScopeUser}, token.ScopeList())
 
 	// Try create second access token with same name should fail
-	_, err = db.Create(ctx, token.UserID, token.Name)
+	_, err = db.Create(ctx, token.UserID, token.Name, CreateAccessTokenOptions{})
 	wantErr := ErrAccessTokenAlreadyExist{
 		args: errutil.Args{
 			"userID": token.UserID,
@@ -107,7 +119,7 @@ func accessTokensDeleteByID(t *testing.T, db *accessTokens) {
 	ctx := context.Background()
 
 	// Create an access token with name "Test"
-	token, err := db.Create(ctx, 1, "Test")
+	token, err := db.Create(ctx, 1, "Test", CreateAccessTokenOptions{})
 	require.NoError(t, err)
 
 	// Delete a token with mismatched user ID is noop
@@ -136,7 +148,7 @@ func accessTokensGetBySHA(t *testing.T, db *accessTokens) {
 	ctx := context.Background()
 
 	// Create an access token with name "Test"
-	token, err := db.Create(ctx, 1, "Test")
+	token, err := db.Create(ctx, 1, "Test", CreateAccessTokenOptions{})
 	require.NoError(t, err)
 
 	// We should be able to get it back
@@ -151,19 +163,25 @@ func accessTokensGetBySHA(t *testing.T, db *accessTokens) {
 		},
 	}
 	assert.Equal(t, wantErr, err)
+
+	// Expired tokens are treated as non-existent
+	token, err = db.Create(ctx, 1, "Expired", CreateAccessTokenOptions{ExpiresAt: db.NowFunc().Add(-time.Minute)})
+	require.NoError(t, err)
+	_, err = db.GetBySHA1(ctx, token.Sha1)
+	assert.True(t, IsErrAccessTokenNotExist(err))
 }
 
 func accessTokensList(t *testing.T, db *accessTokens) {
 	ctx := context.Background()
 
 	// Create two access tokens for user 1
-	_, err := db.Create(ctx, 1, "user1_1")
+	_, err := db.Create(ctx, 1, "user1_1", CreateAccessTokenOptions{})
 	require.NoError(t, err)
-	_, err = db.Create(ctx, 1, "user1_2")
+	_, err = db.Create(ctx, 1, "user1_2", CreateAccessTokenOptions{})
 	require.NoError(t, err)
 
 	// Create one access token for user 2
-	_, err = db.Create(ctx, 2, "user2_1")
+	_, err = db.Create(ctx, 2, "user2_1", CreateAccessTokenOptions{})
 	require.NoError(t, err)
 
 	// List all access tokens for user 1
@@ -178,11 +196,53 @@ func accessTokensList(t *testing.T, db *accessTokens) {
 	assert.Equal(t, "user1_2", tokens[1].Name)
 }
 
+func accessTokensListExpiring(t *testing.T, db *accessTokens) {
+	ctx := context.Background()
+
+	now := db.NowFunc()
+	_, err := db.Create(ctx, 1, "never", CreateAccessTokenOptions{})
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 1, "expired", CreateAccessTokenOptions{ExpiresAt: now.Add(-time.Hour)})
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 1, "tomorrow", CreateAccessTokenOptions{ExpiresAt: now.Add(24 * time.Hour)})
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 2, "next week", CreateAccessTokenOptions{ExpiresAt: now.Add(6 * 24 * time.Hour)})
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 2, "next month", CreateAccessTokenOptions{ExpiresAt: now.Add(30 * 24 * time.Hour)})
+	require.NoError(t, err)
+
+	tokens, err := db.ListExpiring(ctx, now.Add(7*24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "tomorrow", tokens[0].Name)
+	assert.Equal(t, "next week", tokens[1].Name)
+}
+
+func accessTokensMarkExpiryNotified(t *testing.T, db *accessTokens) {
+	ctx := context.Background()
+
+	now := db.NowFunc()
+	token, err := db.Create(ctx, 1, "Test", CreateAccessTokenOptions{ExpiresAt: now.Add(24 * time.Hour)})
+	require.NoError(t, err)
+
+	err = db.MarkExpiryNotified(ctx, token.ID)
+	require.NoError(t, err)
+
+	token, err = db.GetBySHA1(ctx, token.Sha1)
+	require.NoError(t, err)
+	assert.True(t, token.ExpiryNotified)
+
+	// Notified tokens are no longer listed
+	tokens, err := db.ListExpiring(ctx, now.Add(7*24*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+}
+
 func accessTokensTouch(t *testing.T, db *accessTokens) {
 	ctx := context.Background()
 
 	// Create an access token with name "Test"
-	token, err := db.Create(ctx, 1, "Test")
+	token, err := db.Create(ctx, 1, "Test", CreateAccessTokenOptions{})
 	require.NoError(t, err)
 
 	// Updated field is zero now
@@ -95,6 +95,11 @@ func dumpTable(ctx context.Context, db *gorm.DB, table interface{}, w io.Writer)
 		}
 
 		switch e := elem.(type) {
+		case *CommitStatus:
+			e.CreatedAt = e.CreatedAt.UTC()
+			e.UpdatedAt = e.UpdatedAt.UTC()
+		case *LFSLock:
+			e.CreatedAt = e.CreatedAt.UTC()
 		case *LFSObject:
 			e.CreatedAt = e.CreatedAt.UTC()
 		}
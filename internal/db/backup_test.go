@@ -31,8 +31,8 @@ func TestDumpAndImport(t *testing.T) {
 	}
 	t.Parallel()
 
-	if len(Tables) != 5 {
-		t.Fatalf("New table has added (want 5 got %d), please add new tests for the table and update this check", len(Tables))
+	if len(Tables) != 16 {
+		t.Fatalf("New table has added (want 16 got %d), please add new tests for the table and update this check", len(Tables))
 	}
 
 	db := dbtest.NewDB(t, "dumpAndImport", Tables...)
@@ -45,6 +45,7 @@ func TestDumpAndImport(t *testing.T) {
 }
 
 func setupDBToDump(t *testing.T, db *gorm.DB) {
+	lastReadAt := time.Unix(1588572486, 0).UTC()
 	vals := []interface{}{
 		&Access{
 			ID:     1,
@@ -64,6 +65,8 @@ func setupDBToDump(t *testing.T, db *gorm.DB) {
 			Name:        "test1",
 			Sha1:        cryptoutil.SHA1("2910d03d-c0b5-4f71-bad5-c4086e4efae3"),
 			SHA256:      cryptoutil.SHA256(cryptoutil.SHA1("2910d03d-c0b5-4f71-bad5-c4086e4efae3")),
+			Scopes:      "repo:read user",
+			ExpiresUnix: 1591160886, // 30 days later
 			CreatedUnix: 1588568886,
 			UpdatedUnix: 1588572486, // 1 hour later
 		},
@@ -131,6 +134,42 @@ func setupDBToDump(t *testing.T, db *gorm.DB) {
 			CreatedUnix:  1588568886,
 		},
 
+		&CommitStatus{
+			RepoID:      1,
+			SHA:         "2b6d9c4f2c1a2ee7b5dd8b0d8b1bf2fc7aeba7d0",
+			State:       CommitStatusPending,
+			Context:     "ci/build",
+			TargetURL:   "https://ci.example.com/builds/1",
+			Description: "Build is running",
+			CreatorID:   1,
+			CreatedAt:   time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:   time.Unix(1588568886, 0).UTC(),
+		},
+		&CommitStatus{
+			RepoID:      1,
+			SHA:         "2b6d9c4f2c1a2ee7b5dd8b0d8b1bf2fc7aeba7d0",
+			State:       CommitStatusSuccess,
+			Context:     "ci/build",
+			TargetURL:   "https://ci.example.com/builds/1",
+			Description: "Build succeeded",
+			CreatorID:   1,
+			CreatedAt:   time.Unix(1588572486, 0).UTC(), // 1 hour later
+			UpdatedAt:   time.Unix(1588572486, 0).UTC(),
+		},
+
+		&LFSLock{
+			RepoID:    1,
+			OwnerID:   1,
+			Path:      "assets/logo.psd",
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+		&LFSLock{
+			RepoID:    1,
+			OwnerID:   2,
+			Path:      "assets/banner.psd",
+			CreatedAt: time.Unix(1588572486, 0).UTC(), // 1 hour later
+		},
+
 		&LFSObject{
 			RepoID:    1,
 			OID:       "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f",
@@ -165,6 +204,118 @@ func setupDBToDump(t *testing.T, db *gorm.DB) {
 			}),
 			CreatedUnix: 1588568886,
 		},
+
+		&Notification{
+			UserID:      1,
+			RepoID:      1,
+			SubjectType: NotificationSubjectIssue,
+			SubjectID:   1,
+			Reason:      NotificationReasonMention,
+			CreatedAt:   time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:   time.Unix(1588568886, 0).UTC(),
+		},
+		&Notification{
+			UserID:          2,
+			RepoID:          1,
+			SubjectType:     NotificationSubjectPull,
+			SubjectID:       2,
+			Reason:          NotificationReasonReviewRequested,
+			LatestCommentID: 3,
+			IsRead:          true,
+			LastReadAt:      &lastReadAt,
+			CreatedAt:       time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:       time.Unix(1588572486, 0).UTC(), // 1 hour later
+		},
+
+		&OAuth2Application{
+			UserID:           1,
+			Name:             "CI dashboard",
+			HomepageURL:      "https://ci.example.com",
+			RedirectURIs:     "https://ci.example.com/callback\nhttp://localhost:8080/callback",
+			ClientID:         "2f6ab2d1-1e5c-4e0c-9d66-3c7ad1b6c2a1",
+			ClientSecretHash: "c2b7c6ef2cbd0d1a0e2e7ad4f2ea5d6b8c3b7e0e6e1f3a9d2c6b0a8e7f5d4c3b",
+			Confidential:     true,
+			CreatedAt:        time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:        time.Unix(1588568886, 0).UTC(),
+		},
+		&OAuth2AuthorizationCode{
+			ApplicationID:       1,
+			UserID:              2,
+			CodeHash:            "5d0b5b8f0e7d8a6c4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b7a6f5e4d3c2b1a",
+			RedirectURI:         "https://ci.example.com/callback",
+			Scopes:              "repo:read user",
+			CodeChallenge:       "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			CodeChallengeMethod: "S256",
+			ExpiresAt:           time.Unix(1588569486, 0).UTC(),
+			CreatedAt:           time.Unix(1588568886, 0).UTC(),
+		},
+		&OAuth2Grant{
+			UserID:        2,
+			ApplicationID: 1,
+			Scopes:        "repo:read user",
+			CreatedAt:     time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:     time.Unix(1588568886, 0).UTC(),
+		},
+		&OAuth2Token{
+			ApplicationID:    1,
+			UserID:           2,
+			AccessTokenHash:  "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+			RefreshTokenHash: "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752",
+			Scopes:           "repo:read user",
+			AccessExpiresAt:  time.Unix(1588572486, 0).UTC(),
+			RefreshExpiresAt: time.Unix(1591160886, 0).UTC(),
+			CreatedAt:        time.Unix(1588568886, 0).UTC(),
+		},
+
+		&PushMirror{
+			RepoID:       1,
+			RemoteURL:    "https://example.com/alice/example.git",
+			Username:     "alice",
+			Password:     "ZW5jcnlwdGVk",
+			SyncInterval: 8,
+			SyncOnPush:   true,
+			LastError:    "fatal: Authentication failed",
+			NextSyncAt:   time.Unix(1588597686, 0).UTC(),
+			CreatedAt:    time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:    time.Unix(1588568886, 0).UTC(),
+		},
+
+		&QueueTask{
+			Queue:     "mirror_sync",
+			Key:       "1",
+			Attempts:  1,
+			LastError: "failed to sync mirror",
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+			VisibleAt: time.Unix(1588568946, 0).UTC(),
+		},
+
+		&RepoMigration{
+			DoerID:         1,
+			OwnerID:        1,
+			RepoName:       "example",
+			RepoID:         1,
+			Service:        "github",
+			CloneAddr:      "https://github.com/alice/example.git",
+			AuthToken:      "ZW5jcnlwdGVk",
+			WithLabels:     true,
+			WithMilestones: true,
+			WithIssues:     true,
+			Status:         RepoMigrationStatusRunning,
+			Stage:          RepoMigrationStageIssues,
+			Progress:       3,
+			CreatedAt:      time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:      time.Unix(1588572486, 0).UTC(), // 1 hour later
+		},
+
+		&WebAuthnCredential{
+			UserID:       1,
+			Name:         "YubiKey",
+			CredentialID: "Y3JlZGVudGlhbC1pZA",
+			PublicKey:    "pQECAyYgASFYIA==",
+			SignCount:    42,
+			CreatedAt:    time.Unix(1588568886, 0).UTC(),
+			LastUsedAt:   &lastReadAt,
+		},
 	}
 	for _, val := range vals {
 		err := db.Create(val).Error
@@ -0,0 +1,313 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+	"github.com/unknwon/com"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/search"
+	"gogs.io/gogs/internal/sync"
+	"gogs.io/gogs/internal/tool"
+)
+
+var (
+	codeIndexer search.CodeIndexer
+	// codeIndexerQueue is a queue of IDs of repositories whose default branch
+	// to be indexed, repositories that no longer exist are removed from the
+	// index.
+	codeIndexerQueue = sync.NewUniqueQueue(1000)
+)
+
+// InitCodeIndexer opens the code search index and starts keeping it up to
+// date. All existing repositories are indexed when the index is newly created.
+func InitCodeIndexer() {
+	indexer, created, err := search.NewBleveCodeIndexer(conf.Indexer.RepoPath)
+	if err != nil {
+		log.Fatal("Failed to open code indexer: %v", err)
+	}
+	codeIndexer = indexer
+
+	go processCodeIndexerQueue()
+	if created {
+		go func() {
+			if err := RebuildCodeIndex(); err != nil {
+				log.Error("Failed to populate code indexer: %v", err)
+			}
+		}()
+	}
+}
+
+// UpdateCodeIndexer queues the default branch of the repository to be
+// (re)indexed, or removed from the index if the repository no longer exists.
+func UpdateCodeIndexer(repoID int64) {
+	if codeIndexer == nil {
+		return
+	}
+	go codeIndexerQueue.Add(repoID)
+}
+
+// UpdateCodeIndexerOnPush queues the repository to be reindexed if the pushed
+// branch is its default branch. It must be called by the web server because
+// the code indexer is not running in the process of Git hooks.
+func UpdateCodeIndexerOnPush(repo *Repository, branch string) {
+	if branch != repo.DefaultBranch {
+		return
+	}
+	UpdateCodeIndexer(repo.ID)
+}
+
+// RebuildCodeIndex reindexes default branches of all repositories. When the
+// code indexer is not running in the current process (e.g. the admin
+// command), the index is opened and rebuilt synchronously, which fails if the
+// index is held by a running server.
+func RebuildCodeIndex() error {
+	if codeIndexer != nil {
+		return x.Where("id > 0").Iterate(new(Repository),
+			func(idx int, bean interface{}) error {
+				UpdateCodeIndexer(bean.(*Repository).ID)
+				return nil
+			},
+		)
+	}
+
+	indexer, _, err := search.NewBleveCodeIndexer(conf.Indexer.RepoPath)
+	if err != nil {
+		return errors.Wrap(err, "open code indexer")
+	}
+	defer func() { _ = indexer.Close() }()
+
+	return x.Where("id > 0").Iterate(new(Repository),
+		func(idx int, bean interface{}) error {
+			repo := bean.(*Repository)
+			if err := indexRepositoryCode(indexer, repo); err != nil {
+				return errors.Wrapf(err, "index repository %d", repo.ID)
+			}
+			return nil
+		},
+	)
+}
+
+func processCodeIndexerQueue() {
+	for id := range codeIndexerQueue.Queue() {
+		codeIndexerQueue.Remove(id)
+		repoID := com.StrTo(id).MustInt64()
+
+		repo, err := getRepositoryByID(x, repoID)
+		if err != nil {
+			if IsErrRepoNotExist(err) {
+				if err = codeIndexer.DeleteRepo(repoID); err != nil {
+					log.Error("Failed to delete repository %d from code indexer: %v", repoID, err)
+				}
+			} else {
+				log.Error("Failed to get repository %d: %v", repoID, err)
+			}
+			continue
+		}
+
+		if err = indexRepositoryCode(codeIndexer, repo); err != nil {
+			log.Error("Failed to index code of repository %d: %v", repoID, err)
+		}
+	}
+}
+
+// indexRepositoryCode replaces documents of the repository in the index with
+// text files in the default branch. Files larger than the limit and binary
+// files are skipped.
+func indexRepositoryCode(indexer search.CodeIndexer, repo *Repository) error {
+	if err := indexer.DeleteRepo(repo.ID); err != nil {
+		return errors.Wrap(err, "delete repository")
+	}
+	if repo.IsBare {
+		return nil
+	}
+
+	repoPath := repo.RepoPath()
+	files, err := lsTreeBlobs(repoPath, git.RefsHeads+repo.DefaultBranch)
+	if err != nil {
+		return errors.Wrap(err, "list files")
+	}
+
+	const batchSize = 100
+	docs := make([]*search.CodeDocument, 0, batchSize)
+	for _, f := range files {
+		if f.size > conf.Indexer.MaxFileSize {
+			continue
+		}
+
+		content, err := git.NewCommand("cat-file", "blob", f.id).RunInDir(repoPath)
+		if err != nil {
+			return errors.Wrapf(err, "read file %q", f.path)
+		} else if !tool.IsTextFile(content) || !utf8.Valid(content) {
+			continue
+		}
+
+		docs = append(docs, &search.CodeDocument{
+			RepoID:  repo.ID,
+			Path:    f.path,
+			Content: string(content),
+		})
+		if len(docs) == batchSize {
+			if err = indexer.Index(docs...); err != nil {
+				return errors.Wrap(err, "index")
+			}
+			docs = docs[:0]
+		}
+	}
+	return indexer.Index(docs...)
+}
+
+type treeBlob struct {
+	id   string
+	path string
+	size int64
+}
+
+// lsTreeBlobs returns all blobs in the tree of given revision recursively. It
+// returns nothing if the revision does not exist.
+func lsTreeBlobs(repoPath, rev string) ([]*treeBlob, error) {
+	if !git.RepoHasReference(repoPath, rev) {
+		return nil, nil
+	}
+
+	stdout, err := git.NewCommand("ls-tree", "-r", "-l", "-z", rev).RunInDir(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobs []*treeBlob
+	for _, line := range bytes.Split(stdout, []byte{0}) {
+		// Format: "<mode> SP <type> SP <object> SP+ <size> TAB <path>"
+		tab := bytes.IndexByte(line, '\t')
+		if tab < 0 {
+			continue
+		}
+		fields := bytes.Fields(line[:tab])
+		if len(fields) != 4 || string(fields[1]) != "blob" {
+			continue
+		}
+
+		size, err := strconv.ParseInt(string(fields[3]), 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse size of %q", line[tab+1:])
+		}
+		blobs = append(blobs, &treeBlob{
+			id:   string(fields[2]),
+			path: string(line[tab+1:]),
+			size: size,
+		})
+	}
+	return blobs, nil
+}
+
+// SearchCodeOptions contains options for searching code.
+type SearchCodeOptions struct {
+	Keyword string
+	// The repository to search in, zero means all repositories the user has
+	// access to.
+	RepoID int64
+	// The user who searches, zero means an anonymous user.
+	UserID   int64
+	Page     int
+	PageSize int
+}
+
+// CodeSearchResult is a file that matches the search keyword with the
+// repository it belongs to.
+type CodeSearchResult struct {
+	*search.CodeHit
+	Repo *Repository
+}
+
+// codeSearchRepoIDs returns IDs of repositories the user has read access to,
+// private and unlisted repositories of others are excluded in the same way as
+// exploring repositories.
+func codeSearchRepoIDs(userID int64) ([]int64, error) {
+	sess := x.Table("repository").Cols("id").Where("is_private = ? AND is_unlisted = ?", false, false)
+	if userID > 0 {
+		sess.Or("owner_id = ?", userID).
+			Or("id IN (SELECT repo_id FROM access WHERE user_id = ? AND mode >= ?)", userID, AccessModeRead)
+	}
+	repoIDs := make([]int64, 0, 10)
+	return repoIDs, sess.Find(&repoIDs)
+}
+
+// SearchCode returns files in default branches of repositories that match
+// the keyword in given page, and the total number of matched files.
+func SearchCode(opts *SearchCodeOptions) ([]*CodeSearchResult, int64, error) {
+	if codeIndexer == nil {
+		return nil, 0, errors.New("code indexer is not initialized")
+	}
+
+	var repoIDs []int64
+	if opts.RepoID > 0 {
+		repoIDs = []int64{opts.RepoID}
+	} else {
+		var err error
+		repoIDs, err = codeSearchRepoIDs(opts.UserID)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "get repository IDs")
+		} else if len(repoIDs) == 0 {
+			return []*CodeSearchResult{}, 0, nil
+		}
+	}
+
+	hits, total, err := codeIndexer.Search(search.CodeSearchOptions{
+		RepoIDs:  repoIDs,
+		Keyword:  opts.Keyword,
+		Page:     opts.Page,
+		PageSize: opts.PageSize,
+	})
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search index")
+	}
+
+	hitRepoIDs := make([]int64, 0, len(hits))
+	for _, hit := range hits {
+		hitRepoIDs = append(hitRepoIDs, hit.RepoID)
+	}
+	repos := make([]*Repository, 0, len(hitRepoIDs))
+	if err = x.In("id", hitRepoIDs).Find(&repos); err != nil {
+		return nil, 0, errors.Wrap(err, "find repositories")
+	} else if err = RepositoryList(repos).LoadAttributes(); err != nil {
+		return nil, 0, errors.Wrap(err, "load attributes")
+	}
+	reposByID := make(map[int64]*Repository, len(repos))
+	for _, repo := range repos {
+		reposByID[repo.ID] = repo
+	}
+
+	results := make([]*CodeSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		repo := reposByID[hit.RepoID]
+		if repo == nil {
+			continue
+		}
+
+		// Double check the access in case permissions have changed since the
+		// list of repositories is retrieved.
+		if !Perms.Authorize(context.TODO(), opts.UserID, repo.ID, AccessModeRead,
+			AccessModeOptions{
+				OwnerID: repo.OwnerID,
+				Private: repo.IsPrivate,
+			},
+		) {
+			continue
+		}
+		results = append(results, &CodeSearchResult{
+			CodeHit: hit,
+			Repo:    repo,
+		})
+	}
+	return results, total, nil
+}
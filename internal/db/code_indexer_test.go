@@ -0,0 +1,31 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gogs.io/gogs/internal/search"
+)
+
+type noopCodeIndexer struct {
+	search.CodeIndexer
+}
+
+func TestUpdateCodeIndexerOnPush(t *testing.T) {
+	queue := setMockCodeIndexer(t, noopCodeIndexer{})
+	repo := &Repository{ID: 1, DefaultBranch: "main"}
+
+	UpdateCodeIndexerOnPush(repo, "feature")
+	assert.False(t, queue.Exist(repo.ID))
+
+	UpdateCodeIndexerOnPush(repo, "main")
+	assert.Eventually(t, func() bool {
+		return queue.Exist(repo.ID)
+	}, time.Second, 10*time.Millisecond)
+}
@@ -36,6 +36,12 @@ const (
 	COMMENT_TYPE_COMMENT_REF
 	// Reference from a pull request
 	COMMENT_TYPE_PULL_REF
+
+	// Submission of a pull request review (ReviewID > 0)
+	COMMENT_TYPE_REVIEW
+	// Comment on a line of a file in the diff of a pull request, which belongs to
+	// a review (ReviewID > 0)
+	COMMENT_TYPE_CODE
 )
 
 type CommentTag int
@@ -65,9 +71,19 @@ type Comment struct {
 	Updated     time.Time `xorm:"-" json:"-"`
 	UpdatedUnix int64
 
-	// Reference issue in commit message
+	// Reference issue in commit message, or the head commit of the pull request
+	// that a code comment is made on.
 	CommitSHA string `xorm:"VARCHAR(40)"`
 
+	// For pull request reviews.
+	ReviewID int64   `xorm:"INDEX"`
+	Review   *Review `xorm:"-" json:"-"`
+	// The path of the file that a code comment is made on, and the line is
+	// positive for the new side and negative for the old side of the diff.
+	TreePath string `xorm:"TEXT"`
+	// Whether the line of a code comment has been changed by a later push.
+	Outdated bool
+
 	Attachments []*Attachment `xorm:"-" json:"-"`
 
 	// For view issue page.
@@ -163,6 +179,20 @@ func (c *Comment) EventTag() string {
 	return "event-" + com.ToStr(c.ID)
 }
 
+// CodeLine returns the line number of the code comment in the file, which is
+// on the left side of the diff if IsLeftSide returns true.
+func (c *Comment) CodeLine() int64 {
+	if c.Line < 0 {
+		return -c.Line
+	}
+	return c.Line
+}
+
+// IsLeftSide returns true if the code comment is made on a deleted line.
+func (c *Comment) IsLeftSide() bool {
+	return c.Line < 0
+}
+
 // mailParticipants sends new comment emails to repository watchers
 // and mentioned people.
 func (cmt *Comment) mailParticipants(e Engine, opType ActionType, issue *Issue) (err error) {
@@ -174,6 +204,9 @@ func (cmt *Comment) mailParticipants(e Engine, opType ActionType, issue *Issue)
 	switch opType {
 	case ActionCommentIssue:
 		issue.Content = cmt.Content
+		if cmt.Type == COMMENT_TYPE_REVIEW && cmt.Review != nil {
+			issue.Content = cmt.Review.mailContent()
+		}
 	case ActionCloseIssue:
 		issue.Content = fmt.Sprintf("Closed #%d", issue.Index)
 	case ActionReopenIssue:
@@ -196,6 +229,11 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 		CommitSHA: opts.CommitSHA,
 		Line:      opts.LineNum,
 		Content:   opts.Content,
+		TreePath:  opts.TreePath,
+	}
+	if opts.Review != nil {
+		comment.ReviewID = opts.Review.ID
+		comment.Review = opts.Review
 	}
 	if _, err = e.Insert(comment); err != nil {
 		return nil, err
@@ -244,6 +282,13 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 			}
 		}
 
+	case COMMENT_TYPE_REVIEW:
+		act.OpType = ActionCommentIssue
+
+		if _, err = e.Exec("UPDATE `issue` SET num_comments=num_comments+1 WHERE id=?", opts.Issue.ID); err != nil {
+			return nil, err
+		}
+
 	case COMMENT_TYPE_REOPEN:
 		act.OpType = ActionReopenIssue
 		if opts.Issue.IsPull {
@@ -275,8 +320,12 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 		}
 	}
 
-	if _, err = e.Exec("UPDATE `issue` SET updated_unix = ? WHERE id = ?", time.Now().Unix(), opts.Issue.ID); err != nil {
-		return nil, fmt.Errorf("update issue 'updated_unix': %v", err)
+	// Code comments belong to a pending review until it is submitted, which then
+	// updates the issue on its own.
+	if opts.Type != COMMENT_TYPE_CODE {
+		if _, err = e.Exec("UPDATE `issue` SET updated_unix = ? WHERE id = ?", time.Now().Unix(), opts.Issue.ID); err != nil {
+			return nil, fmt.Errorf("update issue 'updated_unix': %v", err)
+		}
 	}
 
 	// Notify watchers for whatever action comes in, ignore if no action type.
@@ -314,6 +363,8 @@ type CreateCommentOptions struct {
 	CommitID    int64
 	CommitSHA   string
 	LineNum     int64
+	TreePath    string
+	Review      *Review
 	Content     string
 	Attachments []string // UUIDs of attachments
 }
@@ -331,7 +382,18 @@ func CreateComment(opts *CreateCommentOptions) (comment *Comment, err error) {
 		return nil, err
 	}
 
-	return comment, sess.Commit()
+	if err = sess.Commit(); err != nil {
+		return nil, err
+	}
+	if opts.Type == COMMENT_TYPE_COMMENT {
+		UpdateIssueIndexer(comment.IssueID)
+
+		err = notifyIssueParticipants(opts.Issue, opts.Doer, comment.ID, markup.FindAllMentions(comment.Content))
+		if err != nil {
+			log.Error("notifyIssueParticipants: %v", err)
+		}
+	}
+	return comment, nil
 }
 
 // CreateIssueComment creates a plain issue comment.
@@ -435,7 +497,7 @@ func loadCommentsAttributes(e Engine, comments []*Comment) (err error) {
 
 func getCommentsByIssueIDSince(e Engine, issueID, since int64) ([]*Comment, error) {
 	comments := make([]*Comment, 0, 10)
-	sess := e.Where("issue_id = ?", issueID).Asc("created_unix")
+	sess := e.Where("issue_id = ?", issueID).And("type != ?", COMMENT_TYPE_CODE).Asc("created_unix")
 	if since > 0 {
 		sess.And("updated_unix >= ?", since)
 	}
@@ -448,7 +510,7 @@ func getCommentsByIssueIDSince(e Engine, issueID, since int64) ([]*Comment, erro
 
 func getCommentsByRepoIDSince(e Engine, repoID, since int64) ([]*Comment, error) {
 	comments := make([]*Comment, 0, 10)
-	sess := e.Where("issue.repo_id = ?", repoID).Join("INNER", "issue", "issue.id = comment.issue_id").Asc("comment.created_unix")
+	sess := e.Where("issue.repo_id = ?", repoID).And("comment.type != ?", COMMENT_TYPE_CODE).Join("INNER", "issue", "issue.id = comment.issue_id").Asc("comment.created_unix")
 	if since > 0 {
 		sess.And("comment.updated_unix >= ?", since)
 	}
@@ -482,6 +544,7 @@ func UpdateComment(doer *User, c *Comment, oldContent string) (err error) {
 	if _, err = x.Id(c.ID).AllCols().Update(c); err != nil {
 		return err
 	}
+	UpdateIssueIndexer(c.IssueID)
 
 	if err = c.Issue.LoadAttributes(); err != nil {
 		log.Error("Issue.LoadAttributes [issue_id: %d]: %v", c.IssueID, err)
@@ -523,7 +586,7 @@ func DeleteCommentByID(doer *User, id int64) error {
 		return err
 	}
 
-	if comment.Type == COMMENT_TYPE_COMMENT {
+	if comment.Type == COMMENT_TYPE_COMMENT || comment.Type == COMMENT_TYPE_REVIEW {
 		if _, err = sess.Exec("UPDATE `issue` SET num_comments = num_comments - 1 WHERE id = ?", comment.IssueID); err != nil {
 			return err
 		}
@@ -532,6 +595,7 @@ func DeleteCommentByID(doer *User, id int64) error {
 	if err = sess.Commit(); err != nil {
 		return fmt.Errorf("commit: %v", err)
 	}
+	UpdateIssueIndexer(comment.IssueID)
 
 	_, err = DeleteAttachmentsByComment(comment.ID, true)
 	if err != nil {
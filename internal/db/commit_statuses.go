@@ -0,0 +1,224 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	api "github.com/gogs/go-gogs-client"
+)
+
+// CommitStatusesStore is the persistent interface for commit statuses.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type CommitStatusesStore interface {
+	// Create creates a new commit status for the given commit of the repository
+	// on behalf of the doer, and prepares webhooks of the status event.
+	Create(ctx context.Context, doer *User, repo *Repository, sha string, opts CreateCommitStatusOptions) (*CommitStatus, error)
+	// ListBySHA returns all commit statuses of the given commit of the
+	// repository, in reverse chronological order.
+	ListBySHA(ctx context.Context, repoID int64, sha string) ([]*CommitStatus, error)
+	// ListLatestBySHAs returns the latest commit status of each context for each
+	// of the given commits of the repository. Commits that do not have any status
+	// are not included in the returned map.
+	ListLatestBySHAs(ctx context.Context, repoID int64, shas ...string) (map[string][]*CommitStatus, error)
+}
+
+var CommitStatuses CommitStatusesStore
+
+// CommitStatusState is the state of a commit status.
+type CommitStatusState string
+
+const (
+	CommitStatusPending CommitStatusState = "pending"
+	CommitStatusSuccess CommitStatusState = "success"
+	CommitStatusError   CommitStatusState = "error"
+	CommitStatusFailure CommitStatusState = "failure"
+)
+
+// IsValid returns true if the state is one of the known states.
+func (s CommitStatusState) IsValid() bool {
+	switch s {
+	case CommitStatusPending, CommitStatusSuccess, CommitStatusError, CommitStatusFailure:
+		return true
+	}
+	return false
+}
+
+// IsSuccess returns true if the state is success.
+func (s CommitStatusState) IsSuccess() bool {
+	return s == CommitStatusSuccess
+}
+
+// IsPending returns true if the state is pending.
+func (s CommitStatusState) IsPending() bool {
+	return s == CommitStatusPending
+}
+
+// IsFailed returns true if the state is either error or failure.
+func (s CommitStatusState) IsFailed() bool {
+	return s == CommitStatusError || s == CommitStatusFailure
+}
+
+// CommitStatus is a status reported by an external system (e.g. a CI service)
+// for a commit of a repository.
+type CommitStatus struct {
+	ID          int64             `gorm:"primaryKey"`
+	RepoID      int64             `gorm:"index:idx_commit_status_repo_id_sha;not null"`
+	SHA         string            `gorm:"column:sha;type:VARCHAR(40);index:idx_commit_status_repo_id_sha;not null"`
+	State       CommitStatusState `gorm:"type:VARCHAR(7);not null"`
+	Context     string            `gorm:"not null"`
+	TargetURL   string            `gorm:"type:TEXT"`
+	Description string            `gorm:"type:TEXT"`
+	CreatorID   int64             `gorm:"not null"`
+	Creator     *User             `gorm:"-" json:"-"`
+	CreatedAt   time.Time         `gorm:"not null"`
+	UpdatedAt   time.Time         `gorm:"not null"`
+}
+
+// CombinedCommitStatusState returns the combined state of given commit
+// statuses, which are expected to be the latest status of each context. The
+// combined state is failure if any of the statuses is error or failure,
+// pending if there is no status or any of the statuses is pending, and success
+// otherwise.
+func CombinedCommitStatusState(statuses []*CommitStatus) CommitStatusState {
+	if len(statuses) == 0 {
+		return CommitStatusPending
+	}
+
+	state := CommitStatusSuccess
+	for _, s := range statuses {
+		switch {
+		case s.State.IsFailed():
+			return CommitStatusFailure
+		case s.State.IsPending():
+			state = CommitStatusPending
+		}
+	}
+	return state
+}
+
+var _ CommitStatusesStore = (*commitStatuses)(nil)
+
+type commitStatuses struct {
+	*gorm.DB
+}
+
+// NewCommitStatusesStore returns a persistent interface for commit statuses
+// with given database connection.
+func NewCommitStatusesStore(db *gorm.DB) CommitStatusesStore {
+	return &commitStatuses{DB: db}
+}
+
+type CreateCommitStatusOptions struct {
+	State       CommitStatusState
+	Context     string
+	TargetURL   string
+	Description string
+}
+
+func (db *commitStatuses) Create(ctx context.Context, doer *User, repo *Repository, sha string, opts CreateCommitStatusOptions) (*CommitStatus, error) {
+	if opts.Context == "" {
+		opts.Context = "default"
+	}
+
+	status := &CommitStatus{
+		RepoID:      repo.ID,
+		SHA:         sha,
+		State:       opts.State,
+		Context:     opts.Context,
+		TargetURL:   opts.TargetURL,
+		Description: opts.Description,
+		CreatorID:   doer.ID,
+		Creator:     doer,
+	}
+	err := db.WithContext(ctx).Create(status).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "create")
+	}
+
+	err = PrepareWebhooks(
+		repo,
+		HOOK_EVENT_STATUS,
+		&StatusPayload{
+			ID:          status.ID,
+			SHA:         status.SHA,
+			State:       status.State,
+			Context:     status.Context,
+			TargetURL:   status.TargetURL,
+			Description: status.Description,
+			CreatedAt:   status.CreatedAt,
+			UpdatedAt:   status.UpdatedAt,
+			Repository:  repo.APIFormatLegacy(nil),
+			Sender:      doer.APIFormat(),
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "prepare webhooks")
+	}
+	return status, nil
+}
+
+func (db *commitStatuses) ListBySHA(ctx context.Context, repoID int64, sha string) ([]*CommitStatus, error) {
+	var statuses []*CommitStatus
+	return statuses, db.WithContext(ctx).
+		Where("repo_id = ? AND sha = ?", repoID, sha).
+		Order("id DESC").
+		Find(&statuses).Error
+}
+
+func (db *commitStatuses) ListLatestBySHAs(ctx context.Context, repoID int64, shas ...string) (map[string][]*CommitStatus, error) {
+	if len(shas) == 0 {
+		return map[string][]*CommitStatus{}, nil
+	}
+
+	var statuses []*CommitStatus
+	err := db.WithContext(ctx).
+		Where("repo_id = ? AND sha IN (?)", repoID, shas).
+		Order("id DESC").
+		Find(&statuses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		sha     string
+		context string
+	}
+	seen := make(map[key]bool, len(statuses))
+	latest := make(map[string][]*CommitStatus, len(shas))
+	for _, s := range statuses {
+		k := key{sha: s.SHA, context: s.Context}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		latest[s.SHA] = append(latest[s.SHA], s)
+	}
+	return latest, nil
+}
+
+// StatusPayload represents the payload of a status webhook event.
+type StatusPayload struct {
+	ID          int64             `json:"id"`
+	SHA         string            `json:"sha"`
+	State       CommitStatusState `json:"state"`
+	Context     string            `json:"context"`
+	TargetURL   string            `json:"target_url"`
+	Description string            `json:"description"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	Repository  *api.Repository   `json:"repository"`
+	Sender      *api.User         `json:"sender"`
+}
+
+func (p *StatusPayload) JSONPayload() ([]byte, error) {
+	return jsoniter.MarshalIndent(p, "", "  ")
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestCombinedCommitStatusState(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []*CommitStatus
+		want     CommitStatusState
+	}{
+		{
+			name: "no status",
+			want: CommitStatusPending,
+		},
+		{
+			name: "all success",
+			statuses: []*CommitStatus{
+				{State: CommitStatusSuccess},
+				{State: CommitStatusSuccess},
+			},
+			want: CommitStatusSuccess,
+		},
+		{
+			name: "one pending",
+			statuses: []*CommitStatus{
+				{State: CommitStatusSuccess},
+				{State: CommitStatusPending},
+			},
+			want: CommitStatusPending,
+		},
+		{
+			name: "one error",
+			statuses: []*CommitStatus{
+				{State: CommitStatusPending},
+				{State: CommitStatusError},
+			},
+			want: CommitStatusFailure,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, CombinedCommitStatusState(test.statuses))
+		})
+	}
+}
+
+func TestCommitStatuses(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(CommitStatus)}
+	db := &commitStatuses{
+		DB: dbtest.NewDB(t, "commitStatuses", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *commitStatuses)
+	}{
+		{"Create", commitStatusesCreate},
+		{"ListBySHA", commitStatusesListBySHA},
+		{"ListLatestBySHAs", commitStatusesListLatestBySHAs},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+const testCommitSHA = "2b6d9c4f2c1a2ee7b5dd8b0d8b1bf2fc7aeba7d0"
+
+func testCommitStatusRepo() (*User, *Repository) {
+	doer := &User{ID: 1, Name: "alice"}
+	repo := &Repository{ID: 1, Name: "example", OwnerID: doer.ID, Owner: doer}
+	return doer, repo
+}
+
+func commitStatusesCreate(t *testing.T, db *commitStatuses) {
+	ctx := context.Background()
+	doer, repo := testCommitStatusRepo()
+
+	status, err := db.Create(ctx, doer, repo, testCommitSHA,
+		CreateCommitStatusOptions{
+			State:     CommitStatusPending,
+			TargetURL: "https://ci.example.com/builds/1",
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "default", status.Context)
+	assert.Equal(t, doer.ID, status.CreatorID)
+
+	statuses, err := db.ListBySHA(ctx, repo.ID, testCommitSHA)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, CommitStatusPending, statuses[0].State)
+	assert.Equal(t, "https://ci.example.com/builds/1", statuses[0].TargetURL)
+}
+
+func commitStatusesListBySHA(t *testing.T, db *commitStatuses) {
+	ctx := context.Background()
+	doer, repo := testCommitStatusRepo()
+
+	for _, state := range []CommitStatusState{CommitStatusPending, CommitStatusSuccess} {
+		_, err := db.Create(ctx, doer, repo, testCommitSHA, CreateCommitStatusOptions{State: state, Context: "ci/build"})
+		require.NoError(t, err)
+	}
+	// Statuses of other commits should not be included
+	_, err := db.Create(ctx, doer, repo, "0000000000000000000000000000000000000000", CreateCommitStatusOptions{State: CommitStatusFailure})
+	require.NoError(t, err)
+
+	statuses, err := db.ListBySHA(ctx, repo.ID, testCommitSHA)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, CommitStatusSuccess, statuses[0].State)
+	assert.Equal(t, CommitStatusPending, statuses[1].State)
+}
+
+func commitStatusesListLatestBySHAs(t *testing.T, db *commitStatuses) {
+	ctx := context.Background()
+	doer, repo := testCommitStatusRepo()
+
+	otherSHA := "0000000000000000000000000000000000000000"
+	for _, opts := range []CreateCommitStatusOptions{
+		{State: CommitStatusPending, Context: "ci/build"},
+		{State: CommitStatusSuccess, Context: "ci/build"},
+		{State: CommitStatusFailure, Context: "ci/lint"},
+	} {
+		_, err := db.Create(ctx, doer, repo, testCommitSHA, opts)
+		require.NoError(t, err)
+	}
+	_, err := db.Create(ctx, doer, repo, otherSHA, CreateCommitStatusOptions{State: CommitStatusSuccess})
+	require.NoError(t, err)
+
+	got, err := db.ListLatestBySHAs(ctx, repo.ID, testCommitSHA, otherSHA, "1111111111111111111111111111111111111111")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	require.Len(t, got[testCommitSHA], 2)
+	assert.Equal(t, "ci/lint", got[testCommitSHA][0].Context)
+	assert.Equal(t, CommitStatusFailure, got[testCommitSHA][0].State)
+	assert.Equal(t, "ci/build", got[testCommitSHA][1].Context)
+	assert.Equal(t, CommitStatusSuccess, got[testCommitSHA][1].State)
+
+	require.Len(t, got[otherSHA], 1)
+	assert.Equal(t, CommitStatusSuccess, got[otherSHA][0].State)
+}
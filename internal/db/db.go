@@ -42,7 +42,14 @@ func newLogWriter() (logger.Writer, error) {
 // NOTE: Lines are sorted in alphabetical order, each letter in its own line.
 var Tables = []interface{}{
 	new(Access), new(AccessToken), new(Action),
-	new(LFSObject), new(LoginSource),
+	new(CommitStatus),
+	new(LFSLock), new(LFSObject), new(LoginSource),
+	new(Notification),
+	new(OAuth2Application), new(OAuth2AuthorizationCode), new(OAuth2Grant), new(OAuth2Token),
+	new(PushMirror),
+	new(QueueTask),
+	new(RepoMigration),
+	new(WebAuthnCredential),
 }
 
 // Init initializes the database with given logger.
@@ -120,13 +127,19 @@ func Init(w logger.Writer) (*gorm.DB, error) {
 	// Initialize stores, sorted in alphabetical order.
 	AccessTokens = &accessTokens{DB: db}
 	Actions = NewActionsStore(db)
+	CommitStatuses = NewCommitStatusesStore(db)
 	LoginSources = &loginSources{DB: db, files: sourceFiles}
 	LFS = &lfs{DB: db}
+	Notifications = NewNotificationsStore(db)
+	OAuth2 = NewOAuth2Store(db)
 	Perms = &perms{DB: db}
+	PushMirrors = NewPushMirrorsStore(db)
+	RepoMigrations = NewRepoMigrationsStore(db)
 	Repos = NewReposStore(db)
 	TwoFactors = &twoFactors{DB: db}
 	Users = NewUsersStore(db)
 	Watches = NewWatchesStore(db)
 
+	initDatabaseQueues(db)
 	return db, nil
 }
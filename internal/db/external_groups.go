@@ -0,0 +1,138 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/auth"
+)
+
+// syncExternalGroups synchronizes the site admin status and team membership of
+// the user with groups of the external account. The site admin status is
+// updated via given users store. Teams that do not exist are skipped.
+func syncExternalGroups(ctx context.Context, users UsersStore, u *User, p auth.GroupSyncProvider, extAccount *auth.ExternalAccount) error {
+	if p.SyncsAdmin() && u.IsAdmin != extAccount.Admin {
+		if err := users.SetAdmin(ctx, u.ID, extAccount.Admin); err != nil {
+			return errors.Wrap(err, "update admin status")
+		}
+		u.IsAdmin = extAccount.Admin
+		log.Trace("External groups: Set admin status of %q to %v", u.Name, u.IsAdmin)
+	}
+
+	isMember := make(map[string]bool, len(extAccount.Teams))
+	for _, team := range extAccount.Teams {
+		isMember[strings.ToLower(team)] = true
+	}
+
+	// NOTE: Teams are not yet migrated to GORM, the membership is updated via the
+	// legacy functions.
+	for _, name := range p.SyncedTeams() {
+		fields := strings.SplitN(name, "/", 2)
+		if len(fields) != 2 {
+			continue
+		}
+
+		org, err := GetOrgByName(fields[0])
+		if err != nil {
+			if err == ErrOrgNotExist {
+				log.Warn("External groups: Organization of team %q does not exist", name)
+				continue
+			}
+			return errors.Wrapf(err, "get organization %q", fields[0])
+		}
+		t, err := org.GetTeam(fields[1])
+		if err != nil {
+			if IsErrTeamNotExist(err) {
+				log.Warn("External groups: Team %q does not exist", name)
+				continue
+			}
+			return errors.Wrapf(err, "get team %q", name)
+		}
+
+		wantMember := isMember[strings.ToLower(name)]
+		if wantMember == IsTeamMember(org.ID, t.ID, u.ID) {
+			continue
+		}
+
+		if wantMember {
+			err = AddTeamMember(org.ID, t.ID, u.ID)
+		} else {
+			err = RemoveTeamMember(org.ID, t.ID, u.ID)
+			if IsErrLastOrgOwner(err) {
+				log.Warn("External groups: Cannot remove %q from team %q as the last owner", u.Name, name)
+				continue
+			}
+		}
+		if err != nil {
+			return errors.Wrapf(err, "update membership of team %q", name)
+		}
+		log.Trace("External groups: Set membership of %q in team %q to %v", u.Name, name, wantMember)
+	}
+	return nil
+}
+
+const _EXTERNAL_GROUP_SYNC = "external_group_sync"
+
+// SyncExternalGroups synchronizes the site admin status and team membership of
+// all users of login sources that derive them from external groups.
+func SyncExternalGroups() error {
+	if taskStatusTable.IsRunning(_EXTERNAL_GROUP_SYNC) {
+		return nil
+	}
+	taskStatusTable.Start(_EXTERNAL_GROUP_SYNC)
+	defer taskStatusTable.Stop(_EXTERNAL_GROUP_SYNC)
+
+	log.Trace("Doing: SyncExternalGroups")
+
+	ctx := context.Background()
+	sources, err := LoginSources.List(ctx, ListLoginSourceOptions{OnlyActivated: true})
+	if err != nil {
+		return errors.Wrap(err, "list activated login sources")
+	}
+
+	for _, source := range sources {
+		p, ok := source.Provider.(auth.GroupSyncProvider)
+		if !ok || (!p.SyncsAdmin() && len(p.SyncedTeams()) == 0) {
+			continue
+		}
+
+		// Accounts of LDAP via direct bind cannot be searched without their
+		// passwords, neither can accounts of OpenID Connect without their
+		// tokens. These users are only synchronized when they sign in.
+		if source.Type == auth.DLDAP || source.Type == auth.OIDC {
+			continue
+		}
+
+		var users []*User
+		err = x.Where("login_source = ?", source.ID).Find(&users)
+		if err != nil {
+			return errors.Wrapf(err, "list users of login source %d", source.ID)
+		}
+
+		for _, u := range users {
+			extAccount, err := p.SearchAccount(u.LoginName)
+			if err != nil {
+				if !auth.IsErrAccountNotExist(err) {
+					log.Warn("External groups: Failed to search account %q of login source %d: %v", u.LoginName, source.ID, err)
+					continue
+				}
+
+				// The account is gone from the directory, so are its groups.
+				log.Trace("External groups: Account %q of login source %d does not exist: %v", u.LoginName, source.ID, err)
+				extAccount = &auth.ExternalAccount{Login: u.LoginName}
+			}
+
+			if err = syncExternalGroups(ctx, Users, u, p, extAccount); err != nil {
+				return errors.Wrapf(err, "sync external groups of user %q", u.Name)
+			}
+		}
+	}
+	return nil
+}
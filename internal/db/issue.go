@@ -18,6 +18,7 @@ import (
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/db/errors"
 	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/search"
 	"gogs.io/gogs/internal/tool"
 )
 
@@ -471,6 +472,10 @@ func (issue *Issue) ChangeStatus(doer *User, repo *Repository, isClosed bool) (e
 		return fmt.Errorf("Commit: %v", err)
 	}
 
+	if err = notifyIssueParticipants(issue, doer, 0, nil); err != nil {
+		log.Error("notifyIssueParticipants: %v", err)
+	}
+
 	if issue.IsPull {
 		// Merge pull request calls issue.changeStatus so we need to handle separately.
 		issue.PullRequest.Issue = issue
@@ -513,6 +518,7 @@ func (issue *Issue) ChangeTitle(doer *User, title string) (err error) {
 	if err = UpdateIssueCols(issue, "name"); err != nil {
 		return fmt.Errorf("UpdateIssueCols: %v", err)
 	}
+	UpdateIssueIndexer(issue.ID)
 
 	if issue.IsPull {
 		issue.PullRequest.Issue = issue
@@ -555,6 +561,7 @@ func (issue *Issue) ChangeContent(doer *User, content string) (err error) {
 	if err = UpdateIssueCols(issue, "content"); err != nil {
 		return fmt.Errorf("UpdateIssueCols: %v", err)
 	}
+	UpdateIssueIndexer(issue.ID)
 
 	if issue.IsPull {
 		issue.PullRequest.Issue = issue
@@ -605,6 +612,11 @@ func (issue *Issue) ChangeAssignee(doer *User, assigneeID int64) (err error) {
 
 	// Error not nil here means user does not exist, which is remove assignee.
 	isRemoveAssignee := err != nil
+	if !isRemoveAssignee {
+		if err = notifyIssueAssignee(issue, doer); err != nil {
+			log.Error("notifyIssueAssignee: %v", err)
+		}
+	}
 	if issue.IsPull {
 		issue.PullRequest.Issue = issue
 		apiPullRequest := &api.PullRequestPayload{
@@ -648,9 +660,29 @@ type NewIssueOptions struct {
 	IsPull      bool
 }
 
+// nextIssueIndex returns the next index of issues and pull requests in the
+// repository. The number of them is not enough because migrated issues and pull
+// requests keep their numbers which may have gaps.
+func nextIssueIndex(e Engine, repo *Repository) (int64, error) {
+	var max int64
+	_, err := e.Sql("SELECT COALESCE(MAX(`index`), 0) FROM `issue` WHERE repo_id = ?", repo.ID).Get(&max)
+	if err != nil {
+		return 0, err
+	}
+
+	next := repo.NextIssueIndex()
+	if max >= next {
+		next = max + 1
+	}
+	return next, nil
+}
+
 func newIssue(e *xorm.Session, opts NewIssueOptions) (err error) {
 	opts.Issue.Title = strings.TrimSpace(opts.Issue.Title)
-	opts.Issue.Index = opts.Repo.NextIssueIndex()
+	opts.Issue.Index, err = nextIssueIndex(e, opts.Repo)
+	if err != nil {
+		return fmt.Errorf("get next issue index: %v", err)
+	}
 
 	if opts.Issue.MilestoneID > 0 {
 		milestone, err := getMilestoneByRepoID(e, opts.Issue.RepoID, opts.Issue.MilestoneID)
@@ -759,6 +791,7 @@ func NewIssue(repo *Repository, issue *Issue, labelIDs []int64, uuids []string)
 	if err = sess.Commit(); err != nil {
 		return fmt.Errorf("Commit: %v", err)
 	}
+	UpdateIssueIndexer(issue.ID)
 
 	if err = NotifyWatchers(&Action{
 		ActUserID:    issue.Poster.ID,
@@ -894,10 +927,12 @@ type IssuesOptions struct {
 	IsPull      bool
 	Labels      string
 	SortType    string
+	// The parsed search query, the state and type qualifiers are not applied.
+	Query *search.IssueQuery
 }
 
 // buildIssuesQuery returns nil if it foresees there won't be any value returned.
-func buildIssuesQuery(opts *IssuesOptions) *xorm.Session {
+func buildIssuesQuery(opts *IssuesOptions) (*xorm.Session, error) {
 	sess := x.NewSession()
 
 	if opts.Page <= 0 {
@@ -909,7 +944,7 @@ func buildIssuesQuery(opts *IssuesOptions) *xorm.Session {
 	} else if opts.RepoIDs != nil {
 		// In case repository IDs are provided but actually no repository has issue.
 		if len(opts.RepoIDs) == 0 {
-			return nil
+			return nil, nil
 		}
 		sess.In("issue.repo_id", opts.RepoIDs).And("issue.is_closed=?", opts.IsClosed)
 	} else {
@@ -960,13 +995,24 @@ func buildIssuesQuery(opts *IssuesOptions) *xorm.Session {
 		}
 	}
 
-	return sess
+	if opts.Query != nil {
+		ok, err := applyIssueQuery(sess, opts)
+		if err != nil {
+			return nil, fmt.Errorf("apply query: %v", err)
+		} else if !ok {
+			return nil, nil
+		}
+	}
+
+	return sess, nil
 }
 
 // IssuesCount returns the number of issues by given conditions.
 func IssuesCount(opts *IssuesOptions) (int64, error) {
-	sess := buildIssuesQuery(opts)
-	if sess == nil {
+	sess, err := buildIssuesQuery(opts)
+	if err != nil {
+		return 0, err
+	} else if sess == nil {
 		return 0, nil
 	}
 
@@ -975,8 +1021,10 @@ func IssuesCount(opts *IssuesOptions) (int64, error) {
 
 // Issues returns a list of issues by given conditions.
 func Issues(opts *IssuesOptions) ([]*Issue, error) {
-	sess := buildIssuesQuery(opts)
-	if sess == nil {
+	sess, err := buildIssuesQuery(opts)
+	if err != nil {
+		return nil, err
+	} else if sess == nil {
 		return make([]*Issue, 0), nil
 	}
 
@@ -0,0 +1,221 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/unknwon/com"
+	log "unknwon.dev/clog/v2"
+	"xorm.io/builder"
+	"xorm.io/xorm"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/search"
+	"gogs.io/gogs/internal/sync"
+)
+
+var (
+	issueIndexer search.IssueIndexer
+	// issueIndexerQueue is a queue of IDs of issues to be indexed, issues that
+	// no longer exist are removed from the index.
+	issueIndexerQueue = sync.NewUniqueQueue(1000)
+)
+
+// InitIssueIndexer opens the issue search index and starts keeping it up to
+// date. The index is populated with all existing issues when it is newly
+// created.
+func InitIssueIndexer() {
+	indexer, created, err := search.NewBleveIssueIndexer(conf.Indexer.IssuePath)
+	if err != nil {
+		log.Fatal("Failed to open issue indexer: %v", err)
+	}
+	issueIndexer = indexer
+
+	go processIssueIndexerQueue()
+	if created {
+		go func() {
+			if err := populateIssueIndexer(); err != nil {
+				log.Error("Failed to populate issue indexer: %v", err)
+			}
+		}()
+	}
+}
+
+// UpdateIssueIndexer queues the issue to be (re)indexed, or removed from the
+// index if it no longer exists.
+func UpdateIssueIndexer(issueID int64) {
+	if issueIndexer == nil {
+		return
+	}
+	go issueIndexerQueue.Add(issueID)
+}
+
+// issueDocuments returns search documents of given issues with contents of
+// their comments.
+func issueDocuments(e Engine, issues []*Issue) ([]*search.IssueDocument, error) {
+	docs := make([]*search.IssueDocument, len(issues))
+	issueIDs := make([]int64, len(issues))
+	docsByIssueID := make(map[int64]*search.IssueDocument, len(issues))
+	for i, issue := range issues {
+		docs[i] = &search.IssueDocument{
+			ID:      issue.ID,
+			RepoID:  issue.RepoID,
+			Title:   issue.Title,
+			Content: issue.Content,
+		}
+		issueIDs[i] = issue.ID
+		docsByIssueID[issue.ID] = docs[i]
+	}
+	if len(issues) == 0 {
+		return docs, nil
+	}
+
+	comments := make([]*Comment, 0, len(issues))
+	err := e.In("issue_id", issueIDs).
+		In("type", COMMENT_TYPE_COMMENT, COMMENT_TYPE_REVIEW).
+		Asc("id").
+		Find(&comments)
+	if err != nil {
+		return nil, errors.Wrap(err, "find comments")
+	}
+	for _, c := range comments {
+		if c.Content == "" {
+			continue
+		}
+		doc := docsByIssueID[c.IssueID]
+		doc.Comments = append(doc.Comments, c.Content)
+	}
+	return docs, nil
+}
+
+func populateIssueIndexer() error {
+	log.Trace("Doing: PopulateIssueIndexer")
+
+	const batchSize = 100
+	var lastID int64
+	for {
+		issues := make([]*Issue, 0, batchSize)
+		err := x.Where("id > ?", lastID).Asc("id").Limit(batchSize).Find(&issues)
+		if err != nil {
+			return errors.Wrap(err, "find issues")
+		} else if len(issues) == 0 {
+			return nil
+		}
+		lastID = issues[len(issues)-1].ID
+
+		docs, err := issueDocuments(x, issues)
+		if err != nil {
+			return errors.Wrap(err, "get documents")
+		}
+		if err = issueIndexer.Index(docs...); err != nil {
+			return errors.Wrap(err, "index")
+		}
+	}
+}
+
+func processIssueIndexerQueue() {
+	for id := range issueIndexerQueue.Queue() {
+		issueIndexerQueue.Remove(id)
+		issueID := com.StrTo(id).MustInt64()
+
+		issue, err := getRawIssueByID(x, issueID)
+		if err != nil {
+			if IsErrIssueNotExist(err) {
+				if err = issueIndexer.Delete(issueID); err != nil {
+					log.Error("Failed to delete issue %d from indexer: %v", issueID, err)
+				}
+			} else {
+				log.Error("Failed to get issue %d: %v", issueID, err)
+			}
+			continue
+		}
+
+		docs, err := issueDocuments(x, []*Issue{issue})
+		if err != nil {
+			log.Error("Failed to get document of issue %d: %v", issueID, err)
+			continue
+		}
+		if err = issueIndexer.Index(docs...); err != nil {
+			log.Error("Failed to index issue %d: %v", issueID, err)
+		}
+	}
+}
+
+// applyIssueQuery adds conditions of qualifiers and free text of the search
+// query to the session. It returns false if it foresees there won't be any
+// value returned.
+func applyIssueQuery(sess *xorm.Session, opts *IssuesOptions) (bool, error) {
+	q := opts.Query
+
+	if q.HasText() {
+		if issueIndexer == nil {
+			return false, errors.New("issue indexer is not initialized")
+		}
+
+		repoIDs := opts.RepoIDs
+		if opts.RepoID > 0 {
+			repoIDs = []int64{opts.RepoID}
+		}
+		issueIDs, err := issueIndexer.Search(search.IssueSearchOptions{
+			RepoIDs: repoIDs,
+			Terms:   q.Terms,
+			Phrases: q.Phrases,
+		})
+		if err != nil {
+			return false, errors.Wrap(err, "search index")
+		} else if len(issueIDs) == 0 {
+			return false, nil
+		}
+
+		// Keep each list of IDs short, some databases limit the number of
+		// expressions in a single IN list.
+		const chunkSize = 500
+		conds := make([]builder.Cond, 0, len(issueIDs)/chunkSize+1)
+		for start := 0; start < len(issueIDs); start += chunkSize {
+			end := start + chunkSize
+			if end > len(issueIDs) {
+				end = len(issueIDs)
+			}
+			conds = append(conds, builder.In("issue.id", issueIDs[start:end]))
+		}
+		sess.And(builder.Or(conds...))
+	}
+
+	// Qualifiers of users that do not exist match nothing.
+	userIDs := make(map[string]int64, 3)
+	for _, username := range []string{q.Author, q.Assignee, q.Mentions} {
+		if username == "" {
+			continue
+		}
+		u, err := Users.GetByUsername(context.TODO(), username)
+		if err != nil {
+			if IsErrUserNotExist(err) {
+				return false, nil
+			}
+			return false, errors.Wrapf(err, "get user %q", username)
+		}
+		userIDs[username] = u.ID
+	}
+	if q.Author != "" {
+		sess.And("issue.poster_id = ?", userIDs[q.Author])
+	}
+	if q.Assignee != "" {
+		sess.And("issue.assignee_id = ?", userIDs[q.Assignee])
+	}
+	if q.Mentions != "" {
+		sess.And("issue.id IN (SELECT issue_id FROM issue_user WHERE uid = ? AND is_mentioned = ?)", userIDs[q.Mentions], true)
+	}
+
+	for _, name := range q.Labels {
+		sess.And("issue.id IN (SELECT issue_label.issue_id FROM issue_label INNER JOIN label ON label.id = issue_label.label_id WHERE LOWER(label.name) = ?)", strings.ToLower(name))
+	}
+	if q.Milestone != "" {
+		sess.And("issue.milestone_id IN (SELECT id FROM milestone WHERE LOWER(name) = ?)", strings.ToLower(q.Milestone))
+	}
+	return true, nil
+}
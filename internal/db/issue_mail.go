@@ -164,8 +164,8 @@ func mailIssueCommentToParticipants(issue *Issue, doer *User, mentions []string)
 	return nil
 }
 
-// MailParticipants sends new issue thread created emails to repository watchers
-// and mentioned people.
+// MailParticipants sends new issue thread created emails and notifications to
+// repository watchers and mentioned people.
 func (issue *Issue) MailParticipants() (err error) {
 	mentions := markup.FindAllMentions(issue.Content)
 	if err = updateIssueMentions(x, issue.ID, mentions); err != nil {
@@ -175,6 +175,9 @@ func (issue *Issue) MailParticipants() (err error) {
 	if err = mailIssueCommentToParticipants(issue, issue.Poster, mentions); err != nil {
 		log.Error("mailIssueCommentToParticipants: %v", err)
 	}
+	if err = notifyIssueParticipants(issue, issue.Poster, 0, mentions); err != nil {
+		log.Error("notifyIssueParticipants: %v", err)
+	}
 
 	return nil
 }
@@ -9,8 +9,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/pkg/errors"
 	"gorm.io/gorm"
 
+	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/errutil"
 	"gogs.io/gogs/internal/lfsutil"
 )
@@ -19,14 +21,51 @@ import (
 //
 // NOTE: All methods are sorted in alphabetical order.
 type LFSStore interface {
+	// CountObjectsByOID returns the number of repositories that have the LFS
+	// object with given OID.
+	CountObjectsByOID(ctx context.Context, oid lfsutil.OID) (int64, error)
+	// CreateLock creates a lock on the path of the repository on behalf of the
+	// owner. It returns ErrLFSLockAlreadyExist when the path is already locked.
+	CreateLock(ctx context.Context, repoID, ownerID int64, path string) (*LFSLock, error)
 	// CreateObject creates a LFS object record in database.
 	CreateObject(ctx context.Context, repoID int64, oid lfsutil.OID, size int64, storage lfsutil.Storage) error
+	// DeleteLockByID deletes the lock with given ID of the repository.
+	DeleteLockByID(ctx context.Context, repoID, id int64) error
+	// DeleteObject deletes the LFS object record with given OID of the
+	// repository. The content of the object in storage is not deleted.
+	DeleteObject(ctx context.Context, repoID int64, oid lfsutil.OID) error
+	// GetLockByID returns the lock with given ID of the repository. It returns
+	// ErrLFSLockNotExist when not found.
+	GetLockByID(ctx context.Context, repoID, id int64) (*LFSLock, error)
+	// GetLockByPath returns the lock on the path of the repository. It returns
+	// ErrLFSLockNotExist when not found.
+	GetLockByPath(ctx context.Context, repoID int64, path string) (*LFSLock, error)
 	// GetObjectByOID returns the LFS object with given OID. It returns
 	// ErrLFSObjectNotExist when not found.
 	GetObjectByOID(ctx context.Context, repoID int64, oid lfsutil.OID) (*LFSObject, error)
 	// GetObjectsByOIDs returns LFS objects found within "oids". The returned list
 	// could have less elements if some oids were not found.
 	GetObjectsByOIDs(ctx context.Context, repoID int64, oids ...lfsutil.OID) ([]*LFSObject, error)
+	// GetObjectsByRepoID returns all LFS objects of the repository.
+	GetObjectsByRepoID(ctx context.Context, repoID int64) ([]*LFSObject, error)
+	// GetObjectsByStorage returns all LFS objects that are kept in the given
+	// storage.
+	GetObjectsByStorage(ctx context.Context, storage lfsutil.Storage) ([]*LFSObject, error)
+	// GetOrphanedObjects returns all LFS objects whose repositories no longer
+	// exist and that are created before the given time.
+	GetOrphanedObjects(ctx context.Context, createdBefore time.Time) ([]*LFSObject, error)
+	// ListLocks returns locks of the repository with given options, in the order
+	// of creation.
+	ListLocks(ctx context.Context, repoID int64, opts ListLFSLocksOptions) ([]*LFSLock, error)
+	// TotalSizeByOwnerID returns the total size in bytes of LFS objects of all
+	// repositories owned by the owner.
+	TotalSizeByOwnerID(ctx context.Context, ownerID int64) (int64, error)
+	// TotalSizeByRepoID returns the total size in bytes of LFS objects of the
+	// repository.
+	TotalSizeByRepoID(ctx context.Context, repoID int64) (int64, error)
+	// UpdateObjectsStorage moves all LFS objects with given OID from one
+	// storage to another.
+	UpdateObjectsStorage(ctx context.Context, oid lfsutil.OID, from, to lfsutil.Storage) error
 }
 
 var LFS LFSStore
@@ -40,12 +79,59 @@ type LFSObject struct {
 	CreatedAt time.Time       `gorm:"not null"`
 }
 
+// LFSLock is a lock on a file path of a repository, which prevents users other
+// than the owner from pushing changes to the file.
+type LFSLock struct {
+	ID        int64     `gorm:"primaryKey"`
+	RepoID    int64     `gorm:"uniqueIndex:lfs_lock_repo_path_unique;not null"`
+	OwnerID   int64     `gorm:"not null"`
+	Path      string    `gorm:"type:VARCHAR(255);uniqueIndex:lfs_lock_repo_path_unique;not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
 var _ LFSStore = (*lfs)(nil)
 
 type lfs struct {
 	*gorm.DB
 }
 
+func (db *lfs) CountObjectsByOID(ctx context.Context, oid lfsutil.OID) (int64, error) {
+	var count int64
+	return count, db.WithContext(ctx).Model(new(LFSObject)).Where("oid = ?", oid).Count(&count).Error
+}
+
+type ErrLFSLockAlreadyExist struct {
+	args errutil.Args
+}
+
+func IsErrLFSLockAlreadyExist(err error) bool {
+	_, ok := err.(ErrLFSLockAlreadyExist)
+	return ok
+}
+
+func (err ErrLFSLockAlreadyExist) Error() string {
+	return fmt.Sprintf("LFS lock already exists: %v", err.args)
+}
+
+func (db *lfs) CreateLock(ctx context.Context, repoID, ownerID int64, path string) (*LFSLock, error) {
+	lock := &LFSLock{
+		RepoID:  repoID,
+		OwnerID: ownerID,
+		Path:    path,
+	}
+	err := db.WithContext(ctx).Create(lock).Error
+	if err != nil {
+		// The unique index on (repo_id, path) rejects locking the same path twice,
+		// check the existing lock to tell the violation apart from other errors.
+		_, getErr := db.GetLockByPath(ctx, repoID, path)
+		if getErr == nil {
+			return nil, ErrLFSLockAlreadyExist{args: errutil.Args{"repoID": repoID, "path": path}}
+		}
+		return nil, err
+	}
+	return lock, nil
+}
+
 func (db *lfs) CreateObject(ctx context.Context, repoID int64, oid lfsutil.OID, size int64, storage lfsutil.Storage) error {
 	object := &LFSObject{
 		RepoID:  repoID,
@@ -56,6 +142,55 @@ func (db *lfs) CreateObject(ctx context.Context, repoID int64, oid lfsutil.OID,
 	return db.WithContext(ctx).Create(object).Error
 }
 
+func (db *lfs) DeleteLockByID(ctx context.Context, repoID, id int64) error {
+	return db.WithContext(ctx).Where("repo_id = ? AND id = ?", repoID, id).Delete(new(LFSLock)).Error
+}
+
+func (db *lfs) DeleteObject(ctx context.Context, repoID int64, oid lfsutil.OID) error {
+	return db.WithContext(ctx).Where("repo_id = ? AND oid = ?", repoID, oid).Delete(new(LFSObject)).Error
+}
+
+type ErrLFSLockNotExist struct {
+	args errutil.Args
+}
+
+func IsErrLFSLockNotExist(err error) bool {
+	_, ok := err.(ErrLFSLockNotExist)
+	return ok
+}
+
+func (err ErrLFSLockNotExist) Error() string {
+	return fmt.Sprintf("LFS lock does not exist: %v", err.args)
+}
+
+func (ErrLFSLockNotExist) NotFound() bool {
+	return true
+}
+
+func (db *lfs) GetLockByID(ctx context.Context, repoID, id int64) (*LFSLock, error) {
+	lock := new(LFSLock)
+	err := db.WithContext(ctx).Where("repo_id = ? AND id = ?", repoID, id).First(lock).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrLFSLockNotExist{args: errutil.Args{"repoID": repoID, "id": id}}
+		}
+		return nil, err
+	}
+	return lock, nil
+}
+
+func (db *lfs) GetLockByPath(ctx context.Context, repoID int64, path string) (*LFSLock, error) {
+	lock := new(LFSLock)
+	err := db.WithContext(ctx).Where("repo_id = ? AND path = ?", repoID, path).First(lock).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrLFSLockNotExist{args: errutil.Args{"repoID": repoID, "path": path}}
+		}
+		return nil, err
+	}
+	return lock, nil
+}
+
 type ErrLFSObjectNotExist struct {
 	args errutil.Args
 }
@@ -97,3 +232,97 @@ func (db *lfs) GetObjectsByOIDs(ctx context.Context, repoID int64, oids ...lfsut
 	}
 	return objects, nil
 }
+
+func (db *lfs) GetObjectsByRepoID(ctx context.Context, repoID int64) ([]*LFSObject, error) {
+	var objects []*LFSObject
+	return objects, db.WithContext(ctx).Where("repo_id = ?", repoID).Order("oid").Find(&objects).Error
+}
+
+func (db *lfs) GetObjectsByStorage(ctx context.Context, storage lfsutil.Storage) ([]*LFSObject, error) {
+	var objects []*LFSObject
+	return objects, db.WithContext(ctx).Where("storage = ?", storage).Order("oid").Find(&objects).Error
+}
+
+func (db *lfs) GetOrphanedObjects(ctx context.Context, createdBefore time.Time) ([]*LFSObject, error) {
+	var objects []*LFSObject
+	return objects, db.WithContext(ctx).
+		Where("repo_id NOT IN (?)", db.Model(new(Repository)).Select("id")).
+		Where("created_at < ?", createdBefore).
+		Order("repo_id, oid").
+		Find(&objects).Error
+}
+
+type ListLFSLocksOptions struct {
+	// The path of the lock to filter by.
+	Path string
+	// The minimum ID of locks to return, used for pagination.
+	Cursor int64
+	// The maximum number of locks to return, zero value means no limit.
+	Limit int
+}
+
+func (db *lfs) ListLocks(ctx context.Context, repoID int64, opts ListLFSLocksOptions) ([]*LFSLock, error) {
+	query := db.WithContext(ctx).Where("repo_id = ?", repoID)
+	if opts.Path != "" {
+		query = query.Where("path = ?", opts.Path)
+	}
+	if opts.Cursor > 0 {
+		query = query.Where("id >= ?", opts.Cursor)
+	}
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	var locks []*LFSLock
+	return locks, query.Order("id ASC").Find(&locks).Error
+}
+
+func (db *lfs) TotalSizeByOwnerID(ctx context.Context, ownerID int64) (int64, error) {
+	var size int64
+	return size, db.WithContext(ctx).
+		Model(new(LFSObject)).
+		Select("COALESCE(SUM(size), 0)").
+		Where("repo_id IN (?)", db.Model(new(Repository)).Select("id").Where("owner_id = ?", ownerID)).
+		Scan(&size).Error
+}
+
+func (db *lfs) TotalSizeByRepoID(ctx context.Context, repoID int64) (int64, error) {
+	var size int64
+	return size, db.WithContext(ctx).
+		Model(new(LFSObject)).
+		Select("COALESCE(SUM(size), 0)").
+		Where("repo_id = ?", repoID).
+		Scan(&size).Error
+}
+
+func (db *lfs) UpdateObjectsStorage(ctx context.Context, oid lfsutil.OID, from, to lfsutil.Storage) error {
+	return db.WithContext(ctx).
+		Model(&LFSObject{}).
+		Where("oid = ? AND storage = ?", oid, from).
+		Update("storage", to).
+		Error
+}
+
+// NewLFSStoragers returns all available storage backends of LFS objects based
+// on the configuration.
+func NewLFSStoragers() (map[lfsutil.Storage]lfsutil.Storager, error) {
+	storagers := map[lfsutil.Storage]lfsutil.Storager{
+		lfsutil.StorageLocal: &lfsutil.LocalStorage{Root: conf.LFS.ObjectsPath},
+	}
+	if conf.LFS.S3Endpoint != "" {
+		s3, err := lfsutil.NewS3Storage(lfsutil.S3StorageOptions{
+			Endpoint:        conf.LFS.S3Endpoint,
+			AccessKeyID:     conf.LFS.S3AccessKeyID,
+			SecretAccessKey: conf.LFS.S3SecretAccessKey,
+			Bucket:          conf.LFS.S3Bucket,
+			Region:          conf.LFS.S3Region,
+			UseSSL:          conf.LFS.S3UseSSL,
+			PresignExpiry:   conf.LFS.S3PresignExpiry,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "new S3 storage")
+		}
+		storagers[lfsutil.StorageS3] = s3
+	}
+	return storagers, nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/lfsutil"
+)
+
+const _LFS_GC = "lfs_gc"
+
+// GarbageCollectLFSObjects deletes LFS objects that belong to repositories that
+// no longer exist, or that are no longer referenced by any Git object of their
+// repositories. Objects created within the grace period are always kept to
+// avoid racing with pushes in progress.
+func GarbageCollectLFSObjects() error {
+	if taskStatusTable.IsRunning(_LFS_GC) {
+		return nil
+	}
+	taskStatusTable.Start(_LFS_GC)
+	defer taskStatusTable.Stop(_LFS_GC)
+
+	log.Trace("Doing: GarbageCollectLFSObjects")
+
+	ctx := context.Background()
+	storagers, err := NewLFSStoragers()
+	if err != nil {
+		return errors.Wrap(err, "new storagers")
+	}
+
+	oldestTime := time.Now().Add(-conf.Cron.LFSGarbageCollection.OlderThan)
+	orphaned, err := LFS.GetOrphanedObjects(ctx, oldestTime)
+	if err != nil {
+		return errors.Wrap(err, "get orphaned objects")
+	}
+	for _, object := range orphaned {
+		if err = deleteLFSObject(ctx, storagers, object); err != nil {
+			return err
+		}
+	}
+
+	// Go through repositories page by page to not load all of them at once.
+	const pageSize = 100
+	var lastID int64
+	for {
+		repos := make([]*Repository, 0, pageSize)
+		err = x.Where("id > ?", lastID).Asc("id").Limit(pageSize).Find(&repos)
+		if err != nil {
+			return errors.Wrap(err, "list repositories")
+		} else if len(repos) == 0 {
+			break
+		}
+		lastID = repos[len(repos)-1].ID
+
+		for _, repo := range repos {
+			if err = garbageCollectRepoLFSObjects(ctx, storagers, repo, oldestTime); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// garbageCollectRepoLFSObjects deletes LFS objects of the repository that are
+// created before the given time and no longer referenced by any Git object.
+func garbageCollectRepoLFSObjects(ctx context.Context, storagers map[lfsutil.Storage]lfsutil.Storager, repo *Repository, oldestTime time.Time) error {
+	objects, err := LFS.GetObjectsByRepoID(ctx, repo.ID)
+	if err != nil {
+		return errors.Wrapf(err, "get objects of repository %d", repo.ID)
+	} else if len(objects) == 0 {
+		return nil
+	}
+
+	referenced, err := referencedLFSOIDs(repo.RepoPath())
+	if err != nil {
+		desc := fmt.Sprintf("Failed to collect referenced LFS objects of repository '%s': %v", repo.RepoPath(), err)
+		log.Warn(desc)
+		if err = CreateRepositoryNotice(desc); err != nil {
+			log.Error("CreateRepositoryNotice: %v", err)
+		}
+		return nil
+	}
+
+	for _, object := range objects {
+		if _, ok := referenced[object.OID]; ok || object.CreatedAt.After(oldestTime) {
+			continue
+		}
+		if err = deleteLFSObject(ctx, storagers, object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteLFSObject deletes the LFS object record, and its content in storage
+// when no other repository has the same object.
+func deleteLFSObject(ctx context.Context, storagers map[lfsutil.Storage]lfsutil.Storager, object *LFSObject) error {
+	err := LFS.DeleteObject(ctx, object.RepoID, object.OID)
+	if err != nil {
+		return errors.Wrapf(err, "delete object %q of repository %d", object.OID, object.RepoID)
+	}
+
+	count, err := LFS.CountObjectsByOID(ctx, object.OID)
+	if err != nil {
+		return errors.Wrapf(err, "count objects %q", object.OID)
+	} else if count > 0 {
+		return nil
+	}
+
+	s, ok := storagers[object.Storage]
+	if !ok {
+		log.Warn("Storage %q of LFS object %q is not configured, skip deleting its content", object.Storage, object.OID)
+		return nil
+	}
+	err = s.Delete(object.OID)
+	if err != nil {
+		return errors.Wrapf(err, "delete content of object %q", object.OID)
+	}
+	log.Trace("Deleted LFS object %q of repository %d", object.OID, object.RepoID)
+	return nil
+}
+
+// referencedLFSOIDs returns the OIDs of LFS pointers that are stored as blobs in
+// the repository, mapped to the sizes of the objects. Objects are streamed from
+// Git processes rather than buffered to not hold the whole repository in memory.
+func referencedLFSOIDs(repoPath string) (_ map[lfsutil.OID]int64, err error) {
+	list := exec.Command("git", "cat-file", "--batch-all-objects", "--batch-check=%(objectname) %(objecttype) %(objectsize)")
+	list.Dir = repoPath
+	listOut, err := list.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "get stdout of listing objects")
+	}
+
+	if err = list.Start(); err != nil {
+		return nil, errors.Wrap(err, "start listing objects")
+	}
+	defer func() {
+		if waitErr := list.Wait(); waitErr != nil && err == nil {
+			err = errors.Wrap(waitErr, "list objects")
+		}
+	}()
+
+	read := exec.Command("git", "cat-file", "--batch")
+	read.Dir = repoPath
+	readIn, err := read.StdinPipe()
+	if err != nil {
+		_ = list.Process.Kill()
+		return nil, errors.Wrap(err, "get stdin of reading objects")
+	}
+	readOut, err := read.StdoutPipe()
+	if err != nil {
+		_ = list.Process.Kill()
+		return nil, errors.Wrap(err, "get stdout of reading objects")
+	}
+	if err = read.Start(); err != nil {
+		_ = list.Process.Kill()
+		return nil, errors.Wrap(err, "start reading objects")
+	}
+	defer func() {
+		if waitErr := read.Wait(); waitErr != nil && err == nil {
+			err = errors.Wrap(waitErr, "read objects")
+		}
+	}()
+
+	// Pointer files are small, therefore only blobs no larger than the maximum
+	// size of a pointer file need to be read.
+	go func() {
+		defer readIn.Close()
+
+		scanner := bufio.NewScanner(listOut)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) != 3 || fields[1] != "blob" {
+				continue
+			}
+			size, _ := strconv.ParseInt(fields[2], 10, 64)
+			if size > lfsutil.MaxPointerSize {
+				continue
+			}
+			if _, err := io.WriteString(readIn, fields[0]+"\n"); err != nil {
+				break
+			}
+		}
+		// Drain the rest of output to let the process exit on errors.
+		_, _ = io.Copy(ioutil.Discard, listOut)
+	}()
+
+	// Output of each object is "<sha> <type> <size>\n<content>\n".
+	referenced := make(map[lfsutil.OID]int64)
+	r := bufio.NewReader(readOut)
+	for {
+		header, err := r.ReadString('\n')
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			_, _ = io.Copy(ioutil.Discard, readOut)
+			return nil, errors.Wrap(err, "read header")
+		}
+
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			_, _ = io.Copy(ioutil.Discard, readOut)
+			return nil, errors.Errorf("unexpected header %q", header)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			_, _ = io.Copy(ioutil.Discard, readOut)
+			return nil, errors.Wrapf(err, "parse size %q", fields[2])
+		}
+
+		content := make([]byte, size+1) // Including the trailing newline
+		_, err = io.ReadFull(r, content)
+		if err != nil {
+			_, _ = io.Copy(ioutil.Discard, readOut)
+			return nil, errors.Wrap(err, "read content")
+		}
+
+		oid, objectSize, ok := lfsutil.ParsePointer(content[:size])
+		if ok {
+			referenced[oid] = objectSize
+		}
+	}
+	return referenced, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gogs.io/gogs/internal/lfsutil"
+)
+
+func Test_referencedLFSOIDs(t *testing.T) {
+	repoPath := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=alice",
+			"GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice",
+			"GIT_COMMITTER_EMAIL=alice@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v - %s", args, err, output)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+
+		err := ioutil.WriteFile(filepath.Join(repoPath, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const (
+		oid1 = lfsutil.OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+		oid2 = lfsutil.OID("9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08")
+	)
+	git("init", "--quiet")
+	writeFile("logo.psd", "version https://git-lfs.github.com/spec/v1\noid sha256:"+string(oid1)+"\nsize 12345\n")
+	writeFile("README.md", "# Hello world\n")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "Initial commit")
+
+	// Pointers that are only reachable from the history are still referenced.
+	writeFile("logo.psd", "version https://git-lfs.github.com/spec/v1\noid sha256:"+string(oid2)+"\nsize 4\n")
+	git("commit", "--quiet", "-am", "Update logo")
+
+	got, err := referencedLFSOIDs(repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[lfsutil.OID]int64{
+		oid1: 12345,
+		oid2: 4,
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+// RemainingLFSQuota returns the number of bytes of LFS objects that can still
+// be stored in the repository without exceeding either the repository quota or
+// the owner quota, along with a message describing the quota that limits it.
+// It returns -1 when no quota is configured.
+func RemainingLFSQuota(ctx context.Context, repo *Repository) (remaining int64, message string, _ error) {
+	remaining = -1
+	limited := false
+	if conf.LFS.RepoQuota > 0 {
+		used, err := LFS.TotalSizeByRepoID(ctx, repo.ID)
+		if err != nil {
+			return 0, "", errors.Wrap(err, "get total size of repository")
+		}
+		remaining = conf.LFS.RepoQuota*1024*1024 - used
+		message = "Repository LFS quota exceeded"
+		limited = true
+	}
+
+	if conf.LFS.OwnerQuota > 0 {
+		used, err := LFS.TotalSizeByOwnerID(ctx, repo.OwnerID)
+		if err != nil {
+			return 0, "", errors.Wrap(err, "get total size of owner")
+		}
+		ownerRemaining := conf.LFS.OwnerQuota*1024*1024 - used
+		if !limited || ownerRemaining < remaining {
+			remaining = ownerRemaining
+			message = "Owner LFS quota exceeded"
+		}
+		limited = true
+	}
+
+	if limited && remaining < 0 {
+		remaining = 0
+	}
+	return remaining, message, nil
+}
@@ -23,7 +23,7 @@ func TestLFS(t *testing.T) {
 	}
 	t.Parallel()
 
-	tables := []interface{}{new(LFSObject)}
+	tables := []interface{}{new(LFSLock), new(LFSObject), new(Repository)}
 	db := &lfs{
 		DB: dbtest.NewDB(t, "lfs", tables...),
 	}
@@ -32,9 +32,22 @@ func TestLFS(t *testing.T) {
 		name string
 		test func(*testing.T, *lfs)
 	}{
+		{"CountObjectsByOID", lfsCountObjectsByOID},
+		{"CreateLock", lfsCreateLock},
 		{"CreateObject", lfsCreateObject},
+		{"DeleteLockByID", lfsDeleteLockByID},
+		{"DeleteObject", lfsDeleteObject},
+		{"GetLockByID", lfsGetLockByID},
+		{"GetLockByPath", lfsGetLockByPath},
 		{"GetObjectByOID", lfsGetObjectByOID},
 		{"GetObjectsByOIDs", lfsGetObjectsByOIDs},
+		{"GetObjectsByRepoID", lfsGetObjectsByRepoID},
+		{"GetObjectsByStorage", lfsGetObjectsByStorage},
+		{"GetOrphanedObjects", lfsGetOrphanedObjects},
+		{"ListLocks", lfsListLocks},
+		{"TotalSizeByOwnerID", lfsTotalSizeByOwnerID},
+		{"TotalSizeByRepoID", lfsTotalSizeByRepoID},
+		{"UpdateObjectsStorage", lfsUpdateObjectsStorage},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Cleanup(func() {
@@ -49,6 +62,43 @@ func TestLFS(t *testing.T) {
 	}
 }
 
+func lfsCountObjectsByOID(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	oid := lfsutil.OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+	for _, repoID := range []int64{1, 2} {
+		err := db.CreateObject(ctx, repoID, oid, 12, lfsutil.StorageLocal)
+		require.NoError(t, err)
+	}
+
+	count, err := db.CountObjectsByOID(ctx, oid)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = db.CountObjectsByOID(ctx, "bad_oid")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func lfsCreateLock(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	lock, err := db.CreateLock(ctx, 1, 2, "assets/logo.psd")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), lock.OwnerID)
+	assert.NotZero(t, lock.ID)
+	assert.NotZero(t, lock.CreatedAt)
+
+	// Lock the same path again should fail
+	_, err = db.CreateLock(ctx, 1, 3, "assets/logo.psd")
+	expErr := ErrLFSLockAlreadyExist{args: errutil.Args{"repoID": int64(1), "path": "assets/logo.psd"}}
+	assert.Equal(t, expErr, err)
+
+	// Lock the same path in another repository should be fine
+	_, err = db.CreateLock(ctx, 2, 3, "assets/logo.psd")
+	require.NoError(t, err)
+}
+
 func lfsCreateObject(t *testing.T, db *lfs) {
 	ctx := context.Background()
 
@@ -68,6 +118,76 @@ func lfsCreateObject(t *testing.T, db *lfs) {
 	assert.Error(t, err)
 }
 
+func lfsDeleteLockByID(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	lock, err := db.CreateLock(ctx, 1, 2, "assets/logo.psd")
+	require.NoError(t, err)
+
+	// Deleting with mismatched repository should be noop
+	err = db.DeleteLockByID(ctx, 2, lock.ID)
+	require.NoError(t, err)
+	_, err = db.GetLockByID(ctx, 1, lock.ID)
+	require.NoError(t, err)
+
+	err = db.DeleteLockByID(ctx, 1, lock.ID)
+	require.NoError(t, err)
+	_, err = db.GetLockByID(ctx, 1, lock.ID)
+	expErr := ErrLFSLockNotExist{args: errutil.Args{"repoID": int64(1), "id": lock.ID}}
+	assert.Equal(t, expErr, err)
+}
+
+func lfsDeleteObject(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	oid := lfsutil.OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+	for _, repoID := range []int64{1, 2} {
+		err := db.CreateObject(ctx, repoID, oid, 12, lfsutil.StorageLocal)
+		require.NoError(t, err)
+	}
+
+	err := db.DeleteObject(ctx, 1, oid)
+	require.NoError(t, err)
+
+	_, err = db.GetObjectByOID(ctx, 1, oid)
+	expErr := ErrLFSObjectNotExist{args: errutil.Args{"repoID": int64(1), "oid": oid}}
+	assert.Equal(t, expErr, err)
+
+	// The same object of other repositories should not be affected
+	_, err = db.GetObjectByOID(ctx, 2, oid)
+	require.NoError(t, err)
+}
+
+func lfsGetLockByID(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	lock, err := db.CreateLock(ctx, 1, 2, "assets/logo.psd")
+	require.NoError(t, err)
+
+	got, err := db.GetLockByID(ctx, 1, lock.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "assets/logo.psd", got.Path)
+
+	_, err = db.GetLockByID(ctx, 2, lock.ID)
+	expErr := ErrLFSLockNotExist{args: errutil.Args{"repoID": int64(2), "id": lock.ID}}
+	assert.Equal(t, expErr, err)
+}
+
+func lfsGetLockByPath(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	lock, err := db.CreateLock(ctx, 1, 2, "assets/logo.psd")
+	require.NoError(t, err)
+
+	got, err := db.GetLockByPath(ctx, 1, "assets/logo.psd")
+	require.NoError(t, err)
+	assert.Equal(t, lock.ID, got.ID)
+
+	_, err = db.GetLockByPath(ctx, 1, "assets/404.psd")
+	expErr := ErrLFSLockNotExist{args: errutil.Args{"repoID": int64(1), "path": "assets/404.psd"}}
+	assert.Equal(t, expErr, err)
+}
+
 func lfsGetObjectByOID(t *testing.T, db *lfs) {
 	ctx := context.Background()
 
@@ -110,3 +230,170 @@ func lfsGetObjectsByOIDs(t *testing.T, db *lfs) {
 	assert.Equal(t, repoID, objects[1].RepoID)
 	assert.Equal(t, oid2, objects[1].OID)
 }
+
+func lfsGetObjectsByRepoID(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	oid1 := lfsutil.OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+	oid2 := lfsutil.OID("5cac0a318669fadfee734fb340a5f5b70b428ac57a9f4b109cb6e150b2ba7e57")
+	err := db.CreateObject(ctx, 1, oid1, 12, lfsutil.StorageLocal)
+	require.NoError(t, err)
+	err = db.CreateObject(ctx, 1, oid2, 12, lfsutil.StorageLocal)
+	require.NoError(t, err)
+	err = db.CreateObject(ctx, 2, oid1, 12, lfsutil.StorageLocal)
+	require.NoError(t, err)
+
+	objects, err := db.GetObjectsByRepoID(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(objects), "number of objects")
+	assert.Equal(t, oid2, objects[0].OID)
+	assert.Equal(t, oid1, objects[1].OID)
+}
+
+func lfsGetObjectsByStorage(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	oid1 := lfsutil.OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+	oid2 := lfsutil.OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64g")
+	err := db.CreateObject(ctx, 1, oid1, 12, lfsutil.StorageLocal)
+	require.NoError(t, err)
+	err = db.CreateObject(ctx, 2, oid2, 12, lfsutil.StorageS3)
+	require.NoError(t, err)
+
+	objects, err := db.GetObjectsByStorage(ctx, lfsutil.StorageLocal)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(objects), "number of objects")
+	assert.Equal(t, oid1, objects[0].OID)
+
+	objects, err = db.GetObjectsByStorage(ctx, lfsutil.StorageS3)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(objects), "number of objects")
+	assert.Equal(t, oid2, objects[0].OID)
+}
+
+func lfsGetOrphanedObjects(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	err := db.Create(&Repository{ID: 1, OwnerID: 1, LowerName: "repo1", Name: "repo1"}).Error
+	require.NoError(t, err)
+
+	oid := lfsutil.OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+	for _, repoID := range []int64{1, 2} {
+		err := db.CreateObject(ctx, repoID, oid, 12, lfsutil.StorageLocal)
+		require.NoError(t, err)
+	}
+
+	objects, err := db.GetOrphanedObjects(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(objects), "number of objects")
+	assert.Equal(t, int64(2), objects[0].RepoID)
+
+	// Objects created after the cutoff are kept
+	objects, err = db.GetOrphanedObjects(ctx, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func lfsListLocks(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	lock1, err := db.CreateLock(ctx, 1, 2, "assets/logo.psd")
+	require.NoError(t, err)
+	lock2, err := db.CreateLock(ctx, 1, 3, "assets/banner.psd")
+	require.NoError(t, err)
+	lock3, err := db.CreateLock(ctx, 1, 2, "assets/icon.psd")
+	require.NoError(t, err)
+	_, err = db.CreateLock(ctx, 2, 2, "assets/logo.psd")
+	require.NoError(t, err)
+
+	lockIDs := func(locks []*LFSLock) []int64 {
+		ids := make([]int64, 0, len(locks))
+		for _, lock := range locks {
+			ids = append(ids, lock.ID)
+		}
+		return ids
+	}
+
+	locks, err := db.ListLocks(ctx, 1, ListLFSLocksOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{lock1.ID, lock2.ID, lock3.ID}, lockIDs(locks))
+
+	locks, err = db.ListLocks(ctx, 1, ListLFSLocksOptions{Path: "assets/banner.psd"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{lock2.ID}, lockIDs(locks))
+
+	locks, err = db.ListLocks(ctx, 1, ListLFSLocksOptions{Cursor: lock2.ID, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{lock2.ID}, lockIDs(locks))
+}
+
+func lfsTotalSizeByOwnerID(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	for _, repo := range []*Repository{
+		{ID: 1, OwnerID: 1, LowerName: "repo1", Name: "repo1"},
+		{ID: 2, OwnerID: 1, LowerName: "repo2", Name: "repo2"},
+		{ID: 3, OwnerID: 2, LowerName: "repo3", Name: "repo3"},
+	} {
+		err := db.Create(repo).Error
+		require.NoError(t, err)
+	}
+
+	oid1 := lfsutil.OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+	oid2 := lfsutil.OID("5cac0a318669fadfee734fb340a5f5b70b428ac57a9f4b109cb6e150b2ba7e57")
+	err := db.CreateObject(ctx, 1, oid1, 12, lfsutil.StorageLocal)
+	require.NoError(t, err)
+	err = db.CreateObject(ctx, 2, oid2, 100, lfsutil.StorageLocal)
+	require.NoError(t, err)
+	err = db.CreateObject(ctx, 3, oid1, 12, lfsutil.StorageLocal)
+	require.NoError(t, err)
+
+	size, err := db.TotalSizeByOwnerID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(112), size)
+
+	size, err = db.TotalSizeByOwnerID(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), size)
+}
+
+func lfsTotalSizeByRepoID(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	oid1 := lfsutil.OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+	oid2 := lfsutil.OID("5cac0a318669fadfee734fb340a5f5b70b428ac57a9f4b109cb6e150b2ba7e57")
+	err := db.CreateObject(ctx, 1, oid1, 12, lfsutil.StorageLocal)
+	require.NoError(t, err)
+	err = db.CreateObject(ctx, 1, oid2, 100, lfsutil.StorageLocal)
+	require.NoError(t, err)
+	err = db.CreateObject(ctx, 2, oid1, 12, lfsutil.StorageLocal)
+	require.NoError(t, err)
+
+	size, err := db.TotalSizeByRepoID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(112), size)
+
+	size, err = db.TotalSizeByRepoID(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), size)
+}
+
+func lfsUpdateObjectsStorage(t *testing.T, db *lfs) {
+	ctx := context.Background()
+
+	// The same object could be referenced by multiple repositories
+	oid := lfsutil.OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+	err := db.CreateObject(ctx, 1, oid, 12, lfsutil.StorageLocal)
+	require.NoError(t, err)
+	err = db.CreateObject(ctx, 2, oid, 12, lfsutil.StorageLocal)
+	require.NoError(t, err)
+
+	err = db.UpdateObjectsStorage(ctx, oid, lfsutil.StorageLocal, lfsutil.StorageS3)
+	require.NoError(t, err)
+
+	for _, repoID := range []int64{1, 2} {
+		object, err := db.GetObjectByOID(ctx, repoID, oid)
+		require.NoError(t, err)
+		assert.Equal(t, lfsutil.StorageS3, object.Storage)
+	}
+}
@@ -56,6 +56,8 @@ var migrations = []Migration{
 	NewMigration("migrate access tokens to store SHA56", migrateAccessTokenToSHA256),
 	// v20 -> v21:v0.13.0
 	NewMigration("add index to action.user_id", addIndexToActionUserID),
+	// v21 -> v22:v0.13.0
+	NewMigration("add scopes and expiry to access tokens", addScopesAndExpiryToAccessTokens),
 }
 
 // Migrate migrates the database schema and/or data to the current version.
@@ -0,0 +1,39 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// allScopesV22 is the list of all scopes at the time of the migration. Existing
+// access tokens are granted all of them to keep having full access.
+const allScopesV22 = "repo:read repo:write admin:org user write:keys admin:site"
+
+func addScopesAndExpiryToAccessTokens(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		type accessToken struct {
+			Scopes         string `gorm:"type:VARCHAR(255);not null;default:''"`
+			ExpiresUnix    int64  `gorm:"not null;default:0"`
+			ExpiryNotified bool   `gorm:"not null;default:FALSE"`
+		}
+		for _, field := range []string{"Scopes", "ExpiresUnix", "ExpiryNotified"} {
+			if tx.Migrator().HasColumn(&accessToken{}, field) {
+				continue
+			}
+			err := tx.Migrator().AddColumn(&accessToken{}, field)
+			if err != nil {
+				return errors.Wrapf(err, "add column %q", field)
+			}
+		}
+
+		err := tx.Table("access_token").Where("scopes = ?", "").Update("scopes", allScopesV22).Error
+		if err != nil {
+			return errors.Wrap(err, "grant all scopes")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+type accessTokenV22 struct {
+	ID             int64
+	UserID         int64 `gorm:"column:uid;index"`
+	Name           string
+	Sha1           string `gorm:"type:VARCHAR(40);unique"`
+	SHA256         string `gorm:"type:VARCHAR(64);unique;not null"`
+	Scopes         string `gorm:"type:VARCHAR(255);not null;default:''"`
+	CreatedUnix    int64
+	UpdatedUnix    int64
+	ExpiresUnix    int64 `gorm:"not null;default:0"`
+	ExpiryNotified bool  `gorm:"not null;default:FALSE"`
+}
+
+func (*accessTokenV22) TableName() string {
+	return "access_token"
+}
+
+func TestAddScopesAndExpiryToAccessTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	db := dbtest.NewDB(t, "addScopesAndExpiryToAccessTokens", new(accessTokenV20))
+	err := db.Create(
+		&accessTokenV20{
+			ID:          1,
+			UserID:      1,
+			Name:        "test",
+			Sha1:        "73da7bb9d2a475bbc2ab79da7d4e94940cb9f9d5",
+			SHA256:      "ab144c7bd170691bb9bb995f1541c608e33a78b40174f30fc8a1616c0bc3a477",
+			CreatedUnix: db.NowFunc().Unix(),
+			UpdatedUnix: db.NowFunc().Unix(),
+		},
+	).Error
+	require.NoError(t, err)
+
+	err = addScopesAndExpiryToAccessTokens(db)
+	require.NoError(t, err)
+
+	var got accessTokenV22
+	err = db.Where("id = ?", 1).First(&got).Error
+	require.NoError(t, err)
+	assert.Equal(t, "repo:read repo:write admin:org user write:keys admin:site", got.Scopes)
+	assert.Zero(t, got.ExpiresUnix)
+	assert.False(t, got.ExpiryNotified)
+
+	// Migrating again should be noop
+	err = addScopesAndExpiryToAccessTokens(db)
+	require.NoError(t, err)
+}
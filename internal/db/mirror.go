@@ -11,7 +11,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/unknwon/com"
 	"gopkg.in/ini.v1"
 	log "unknwon.dev/clog/v2"
 	"xorm.io/xorm"
@@ -21,11 +20,8 @@ import (
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/db/errors"
 	"gogs.io/gogs/internal/process"
-	"gogs.io/gogs/internal/sync"
 )
 
-var MirrorQueue = sync.NewUniqueQueue(1000)
-
 // Mirror represents mirror information of a repository.
 type Mirror struct {
 	ID          int64
@@ -33,6 +29,14 @@ type Mirror struct {
 	Repo        *Repository `xorm:"-" json:"-"`
 	Interval    int         // Hour.
 	EnablePrune bool        `xorm:"NOT NULL DEFAULT true"`
+	// Refspecs to fetch from upstream separated by new lines, all references
+	// are fetched when empty.
+	Refspecs string `xorm:"TEXT"`
+	// Whether to fetch LFS objects from upstream after every sync.
+	EnableLFS bool `xorm:"NOT NULL DEFAULT false"`
+	// The LFS endpoint of upstream, it is derived from the mirror address when
+	// empty.
+	LFSEndpoint string `xorm:"TEXT"`
 
 	// Last and next sync time of Git data from upstream
 	LastSync     time.Time `xorm:"-" json:"-"`
@@ -147,6 +151,102 @@ func (m *Mirror) SaveAddress(addr string) error {
 	return nil
 }
 
+// RefspecList returns the list of refspecs to fetch from upstream.
+func (m *Mirror) RefspecList() []string {
+	return strings.Fields(m.Refspecs)
+}
+
+// ErrInvalidRefspec is returned when a refspec is not valid for mirrors.
+type ErrInvalidRefspec struct {
+	Refspec string
+}
+
+func IsErrInvalidRefspec(err error) bool {
+	_, ok := err.(ErrInvalidRefspec)
+	return ok
+}
+
+func (err ErrInvalidRefspec) Error() string {
+	return fmt.Sprintf("invalid refspec: %q", err.Refspec)
+}
+
+// ParseMirrorRefspecs parses refspecs separated by whitespaces and returns them
+// separated by new lines. A refspec without destination (e.g.
+// "refs/heads/release/*") is fetched to the same reference, and every refspec
+// is forced because references of mirrors always follow upstream.
+func ParseMirrorRefspecs(s string) (string, error) {
+	fields := strings.Fields(s)
+	refspecs := make([]string, 0, len(fields))
+	for _, field := range fields {
+		refspec := strings.TrimPrefix(field, "+")
+		src, dst := refspec, refspec
+		if i := strings.Index(refspec, ":"); i > -1 {
+			src, dst = refspec[:i], refspec[i+1:]
+		}
+
+		if !strings.HasPrefix(src, "refs/") || !strings.HasPrefix(dst, "refs/") ||
+			strings.Contains(dst, ":") ||
+			strings.Count(src, "*") > 1 ||
+			strings.Count(src, "*") != strings.Count(dst, "*") ||
+			strings.Contains(src, "..") || strings.Contains(dst, "..") {
+			return "", ErrInvalidRefspec{Refspec: field}
+		}
+		refspecs = append(refspecs, "+"+src+":"+dst)
+	}
+	return strings.Join(refspecs, "\n"), nil
+}
+
+// cloneMirrorRefspecs clones references that match given refspecs from the
+// remote to the path as a mirror repository. HEAD of the repository points to
+// the same branch as upstream when it is fetched, or to the first branch
+// otherwise.
+func cloneMirrorRefspecs(remoteAddr, repoPath string, refspecs []string, timeout time.Duration) error {
+	err := git.Init(repoPath, git.InitOptions{Bare: true, Timeout: timeout})
+	if err != nil {
+		return fmt.Errorf("init: %v", err)
+	}
+	err = git.RemoteAdd(repoPath, "origin", remoteAddr, git.RemoteAddOptions{MirrorFetch: true, Timeout: timeout})
+	if err != nil {
+		return fmt.Errorf("add remote 'origin': %v", err)
+	}
+
+	desc := fmt.Sprintf("cloneMirrorRefspecs: %s", repoPath)
+	_, stderr, err := process.ExecDir(timeout, repoPath, desc,
+		"git", append([]string{"fetch", "--quiet", "--no-tags", "origin"}, refspecs...)...)
+	if err != nil {
+		return fmt.Errorf("fetch: %v - %s", err, stderr)
+	}
+
+	var head string
+	stdout, _, err := process.ExecDir(timeout, repoPath, desc, "git", "ls-remote", "--symref", "origin", "HEAD")
+	if err == nil && strings.HasPrefix(stdout, "ref: ") {
+		head = strings.Fields(strings.TrimPrefix(stdout, "ref: "))[0]
+		if _, _, err = process.ExecDir(timeout, repoPath, desc, "git", "show-ref", "--verify", "--quiet", head); err != nil {
+			head = ""
+		}
+	}
+	if head == "" {
+		stdout, _, err = process.ExecDir(timeout, repoPath, desc, "git", "for-each-ref", "--count=1", "--format=%(refname)", "refs/heads/")
+		if err != nil {
+			return fmt.Errorf("list branches: %v", err)
+		}
+		head = strings.TrimSpace(stdout)
+	}
+	if head == "" {
+		return nil
+	}
+
+	gitRepo, err := git.Open(repoPath)
+	if err != nil {
+		return fmt.Errorf("open repository: %v", err)
+	}
+	_, err = gitRepo.SymbolicRef(git.SymbolicRefOptions{Ref: head})
+	if err != nil {
+		return fmt.Errorf("set HEAD: %v", err)
+	}
+	return nil
+}
+
 const gitShortEmptyID = "0000000"
 
 // mirrorSyncResult contains information of a updated reference.
@@ -225,6 +325,15 @@ func (m *Mirror) runSync() ([]*mirrorSyncResult, bool) {
 	if m.EnablePrune {
 		gitArgs = append(gitArgs, "--prune")
 	}
+	if refspecs := m.RefspecList(); len(refspecs) > 0 {
+		// Tags are only fetched when they match the refspecs
+		gitArgs = []string{"fetch", "--no-tags"}
+		if m.EnablePrune {
+			gitArgs = append(gitArgs, "--prune")
+		}
+		gitArgs = append(gitArgs, "origin")
+		gitArgs = append(gitArgs, refspecs...)
+	}
 	_, stderr, err := process.ExecDir(
 		timeout, repoPath, fmt.Sprintf("Mirror.runSync: %s", repoPath),
 		"git", gitArgs...)
@@ -242,6 +351,17 @@ func (m *Mirror) runSync() ([]*mirrorSyncResult, bool) {
 		log.Error("UpdateSize [repo_id: %d]: %v", m.Repo.ID, err)
 	}
 
+	if m.EnableLFS {
+		// Even if LFS sync failed, we still want results from the main repository
+		if err := m.fetchLFSObjects(context.Background()); err != nil {
+			desc := fmt.Sprintf("Failed to fetch LFS objects of mirror repository '%s': %v", repoPath, err)
+			log.Error(desc)
+			if err = CreateRepositoryNotice(desc); err != nil {
+				log.Error("CreateRepositoryNotice: %v", err)
+			}
+		}
+	}
+
 	if m.Repo.HasWiki() {
 		// Even if wiki sync failed, we still want results from the main repository
 		if _, stderr, err := process.ExecDir(
@@ -305,158 +425,171 @@ func MirrorUpdate() {
 			return nil
 		}
 
-		MirrorQueue.Add(m.RepoID)
+		QueueMirrorSync(m.RepoID)
 		return nil
 	}); err != nil {
 		log.Error("MirrorUpdate: %v", err)
 	}
+
+	if err := queueDuePushMirrors(context.Background()); err != nil {
+		log.Error("MirrorUpdate: %v", err)
+	}
 }
 
-// SyncMirrors checks and syncs mirrors.
-// TODO: sync more mirrors at same time.
+// SyncMirrors syncs mirrors in the queue.
 func SyncMirrors() {
-	ctx := context.Background()
+	consumeQueue(MirrorQueue, syncMirror, nil)
+}
 
-	// Start listening on new sync requests.
-	for repoID := range MirrorQueue.Queue() {
-		log.Trace("SyncMirrors [repo_id: %s]", repoID)
-		MirrorQueue.Remove(repoID)
+// syncMirror syncs the mirror repository, and creates actions of the changes.
+func syncMirror(repoID int64) error {
+	ctx := context.Background()
+	log.Trace("SyncMirrors [repo_id: %d]", repoID)
 
-		m, err := GetMirrorByRepoID(com.StrTo(repoID).MustInt64())
-		if err != nil {
-			log.Error("GetMirrorByRepoID [%d]: %v", m.RepoID, err)
-			continue
+	m, err := GetMirrorByRepoID(repoID)
+	if err != nil {
+		if errors.IsMirrorNotExist(err) {
+			return nil
 		}
+		return fmt.Errorf("get mirror by repository ID: %v", err)
+	}
 
-		results, ok := m.runSync()
-		if !ok {
-			continue
-		}
+	results, ok := m.runSync()
+	if !ok {
+		return fmt.Errorf("failed to sync mirror")
+	}
 
-		m.ScheduleNextSync()
-		if err = UpdateMirror(m); err != nil {
-			log.Error("UpdateMirror [%d]: %v", m.RepoID, err)
-			continue
+	m.ScheduleNextSync()
+	if err = UpdateMirror(m); err != nil {
+		log.Error("UpdateMirror [%d]: %v", m.RepoID, err)
+		return nil
+	}
+
+	// TODO:
+	// - Create "Mirror Sync" webhook event
+	// - Create mirror sync (create, push and delete) events and trigger the "mirror sync" webhooks
+
+	if len(results) == 0 {
+		log.Trace("SyncMirrors [repo_id: %d]: no commits fetched", m.RepoID)
+	}
+	for _, result := range results {
+		if result.refName == m.Repo.DefaultBranch {
+			UpdateCodeIndexer(m.RepoID)
+			break
 		}
+	}
 
-		// TODO:
-		// - Create "Mirror Sync" webhook event
-		// - Create mirror sync (create, push and delete) events and trigger the "mirror sync" webhooks
+	gitRepo, err := git.Open(m.Repo.RepoPath())
+	if err != nil {
+		log.Error("Failed to open repository [repo_id: %d]: %v", m.RepoID, err)
+		return nil
+	}
 
-		if len(results) == 0 {
-			log.Trace("SyncMirrors [repo_id: %d]: no commits fetched", m.RepoID)
+	for _, result := range results {
+		// Discard GitHub pull requests, i.e. refs/pull/*
+		if strings.HasPrefix(result.refName, "refs/pull/") {
+			continue
 		}
 
-		gitRepo, err := git.Open(m.Repo.RepoPath())
-		if err != nil {
-			log.Error("Failed to open repository [repo_id: %d]: %v", m.RepoID, err)
+		// Delete reference
+		if result.newCommitID == gitShortEmptyID {
+			if err = Actions.MirrorSyncDelete(ctx, m.Repo.MustOwner(), m.Repo, result.refName); err != nil {
+				log.Error("Failed to create action for mirror sync delete [repo_id: %d]: %v", m.RepoID, err)
+			}
 			continue
 		}
 
-		for _, result := range results {
-			// Discard GitHub pull requests, i.e. refs/pull/*
-			if strings.HasPrefix(result.refName, "refs/pull/") {
+		// New reference
+		isNewRef := false
+		if result.oldCommitID == gitShortEmptyID {
+			if err = Actions.MirrorSyncCreate(ctx, m.Repo.MustOwner(), m.Repo, result.refName); err != nil {
+				log.Error("Failed to create action for mirror sync create [repo_id: %d]: %v", m.RepoID, err)
 				continue
 			}
+			isNewRef = true
+		}
 
-			// Delete reference
-			if result.newCommitID == gitShortEmptyID {
-				if err = Actions.MirrorSyncDelete(ctx, m.Repo.MustOwner(), m.Repo, result.refName); err != nil {
-					log.Error("Failed to create action for mirror sync delete [repo_id: %d]: %v", m.RepoID, err)
-				}
+		// Push commits
+		var commits []*git.Commit
+		var oldCommitID string
+		var newCommitID string
+		if !isNewRef {
+			oldCommitID, err = gitRepo.RevParse(result.oldCommitID)
+			if err != nil {
+				log.Error("Failed to parse revision [repo_id: %d, old_commit_id: %s]: %v", m.RepoID, result.oldCommitID, err)
 				continue
 			}
-
-			// New reference
-			isNewRef := false
-			if result.oldCommitID == gitShortEmptyID {
-				if err = Actions.MirrorSyncCreate(ctx, m.Repo.MustOwner(), m.Repo, result.refName); err != nil {
-					log.Error("Failed to create action for mirror sync create [repo_id: %d]: %v", m.RepoID, err)
-					continue
-				}
-				isNewRef = true
+			newCommitID, err = gitRepo.RevParse(result.newCommitID)
+			if err != nil {
+				log.Error("Failed to parse revision [repo_id: %d, new_commit_id: %s]: %v", m.RepoID, result.newCommitID, err)
+				continue
+			}
+			commits, err = gitRepo.RevList([]string{oldCommitID + "..." + newCommitID})
+			if err != nil {
+				log.Error("Failed to list commits [repo_id: %d, old_commit_id: %s, new_commit_id: %s]: %v", m.RepoID, oldCommitID, newCommitID, err)
+				continue
 			}
 
-			// Push commits
-			var commits []*git.Commit
-			var oldCommitID string
-			var newCommitID string
-			if !isNewRef {
-				oldCommitID, err = gitRepo.RevParse(result.oldCommitID)
-				if err != nil {
-					log.Error("Failed to parse revision [repo_id: %d, old_commit_id: %s]: %v", m.RepoID, result.oldCommitID, err)
-					continue
-				}
-				newCommitID, err = gitRepo.RevParse(result.newCommitID)
-				if err != nil {
-					log.Error("Failed to parse revision [repo_id: %d, new_commit_id: %s]: %v", m.RepoID, result.newCommitID, err)
-					continue
-				}
-				commits, err = gitRepo.RevList([]string{oldCommitID + "..." + newCommitID})
-				if err != nil {
-					log.Error("Failed to list commits [repo_id: %d, old_commit_id: %s, new_commit_id: %s]: %v", m.RepoID, oldCommitID, newCommitID, err)
-					continue
-				}
-
-			} else if gitRepo.HasBranch(result.refName) {
-				refNewCommit, err := gitRepo.BranchCommit(result.refName)
-				if err != nil {
-					log.Error("Failed to get branch commit [repo_id: %d, branch: %s]: %v", m.RepoID, result.refName, err)
-					continue
-				}
-
-				// TODO(unknwon): Get the commits for the new ref until the closest ancestor branch like GitHub does.
-				commits, err = refNewCommit.Ancestors(git.LogOptions{MaxCount: 9})
-				if err != nil {
-					log.Error("Failed to get ancestors [repo_id: %d, commit_id: %s]: %v", m.RepoID, refNewCommit.ID, err)
-					continue
-				}
-
-				// Put the latest commit in front of ancestors
-				commits = append([]*git.Commit{refNewCommit}, commits...)
-
-				oldCommitID = git.EmptyID
-				newCommitID = refNewCommit.ID.String()
+		} else if gitRepo.HasBranch(result.refName) {
+			refNewCommit, err := gitRepo.BranchCommit(result.refName)
+			if err != nil {
+				log.Error("Failed to get branch commit [repo_id: %d, branch: %s]: %v", m.RepoID, result.refName, err)
+				continue
 			}
 
-			err = Actions.MirrorSyncPush(ctx,
-				MirrorSyncPushOptions{
-					Owner:       m.Repo.MustOwner(),
-					Repo:        m.Repo,
-					RefName:     result.refName,
-					OldCommitID: oldCommitID,
-					NewCommitID: newCommitID,
-					Commits:     CommitsToPushCommits(commits),
-				},
-			)
+			// TODO(unknwon): Get the commits for the new ref until the closest ancestor branch like GitHub does.
+			commits, err = refNewCommit.Ancestors(git.LogOptions{MaxCount: 9})
 			if err != nil {
-				log.Error("Failed to create action for mirror sync push [repo_id: %d]: %v", m.RepoID, err)
+				log.Error("Failed to get ancestors [repo_id: %d, commit_id: %s]: %v", m.RepoID, refNewCommit.ID, err)
 				continue
 			}
-		}
 
-		if _, err = x.Exec("UPDATE mirror SET updated_unix = ? WHERE repo_id = ?", time.Now().Unix(), m.RepoID); err != nil {
-			log.Error("Update 'mirror.updated_unix' [%d]: %v", m.RepoID, err)
-			continue
+			// Put the latest commit in front of ancestors
+			commits = append([]*git.Commit{refNewCommit}, commits...)
+
+			oldCommitID = git.EmptyID
+			newCommitID = refNewCommit.ID.String()
 		}
 
-		// Get latest commit date and compare to current repository updated time,
-		// update if latest commit date is newer.
-		latestCommitTime, err := gitRepo.LatestCommitTime()
+		err = Actions.MirrorSyncPush(ctx,
+			MirrorSyncPushOptions{
+				Owner:       m.Repo.MustOwner(),
+				Repo:        m.Repo,
+				RefName:     result.refName,
+				OldCommitID: oldCommitID,
+				NewCommitID: newCommitID,
+				Commits:     CommitsToPushCommits(commits),
+			},
+		)
 		if err != nil {
-			log.Error("GetLatestCommitDate [%d]: %v", m.RepoID, err)
-			continue
-		} else if !latestCommitTime.After(m.Repo.Updated) {
+			log.Error("Failed to create action for mirror sync push [repo_id: %d]: %v", m.RepoID, err)
 			continue
 		}
+	}
 
-		if _, err = x.Exec("UPDATE repository SET updated_unix = ? WHERE id = ?", latestCommitTime.Unix(), m.RepoID); err != nil {
-			log.Error("Update 'repository.updated_unix' [%d]: %v", m.RepoID, err)
-			continue
-		}
+	if _, err = x.Exec("UPDATE mirror SET updated_unix = ? WHERE repo_id = ?", time.Now().Unix(), m.RepoID); err != nil {
+		log.Error("Update 'mirror.updated_unix' [%d]: %v", m.RepoID, err)
+		return nil
+	}
+
+	// Get latest commit date and compare to current repository updated time,
+	// update if latest commit date is newer.
+	latestCommitTime, err := gitRepo.LatestCommitTime()
+	if err != nil {
+		log.Error("GetLatestCommitDate [%d]: %v", m.RepoID, err)
+		return nil
+	} else if !latestCommitTime.After(m.Repo.Updated) {
+		return nil
+	}
+
+	if _, err = x.Exec("UPDATE repository SET updated_unix = ? WHERE id = ?", latestCommitTime.Unix(), m.RepoID); err != nil {
+		log.Error("Update 'repository.updated_unix' [%d]: %v", m.RepoID, err)
+		return nil
 	}
+	return nil
 }
 
 func InitSyncMirrors() {
 	go SyncMirrors()
+	go SyncPushMirrors()
 }
@@ -0,0 +1,144 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/lfsutil"
+	"gogs.io/gogs/internal/netutil"
+)
+
+// lfsEndpoint returns the LFS endpoint of upstream.
+func (m *Mirror) lfsEndpoint() string {
+	if m.LFSEndpoint != "" {
+		return m.LFSEndpoint
+	}
+	return lfsutil.DefaultEndpoint(m.RawAddress())
+}
+
+// missingLFSObjects returns LFS objects that are referenced by the repository
+// but not yet stored.
+func missingLFSObjects(ctx context.Context, repoID int64, repoPath string) ([]lfsutil.Object, error) {
+	referenced, err := referencedLFSOIDs(repoPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "get referenced objects")
+	}
+
+	stored, err := LFS.GetObjectsByRepoID(ctx, repoID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get stored objects")
+	}
+	for _, object := range stored {
+		delete(referenced, object.OID)
+	}
+
+	missing := make([]lfsutil.Object, 0, len(referenced))
+	for oid, size := range referenced {
+		missing = append(missing, lfsutil.Object{OID: oid, Size: size})
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].OID < missing[j].OID
+	})
+	return missing, nil
+}
+
+// fitLFSQuota returns objects in order that fit in the remaining quota, along
+// with the number of objects that do not fit. All objects fit when the
+// remaining quota is negative, i.e. no quota is configured.
+func fitLFSQuota(objects []lfsutil.Object, remaining int64) (fit []lfsutil.Object, exceeded int) {
+	if remaining < 0 {
+		return objects, 0
+	}
+
+	fit = make([]lfsutil.Object, 0, len(objects))
+	for _, object := range objects {
+		if object.Size > remaining {
+			exceeded++
+			continue
+		}
+		remaining -= object.Size
+		fit = append(fit, object)
+	}
+	return fit, exceeded
+}
+
+// fetchLFSObjects downloads LFS objects that are referenced by the repository
+// but not yet stored from the LFS server of upstream.
+func (m *Mirror) fetchLFSObjects(ctx context.Context) error {
+	missing, err := missingLFSObjects(ctx, m.RepoID, m.Repo.RepoPath())
+	if err != nil {
+		return err
+	} else if len(missing) == 0 {
+		return nil
+	}
+
+	// Only fetch objects that fit in the remaining quota, the rest are reported
+	// after the others are stored.
+	remaining, message, err := RemainingLFSQuota(ctx, m.Repo)
+	if err != nil {
+		return errors.Wrap(err, "get remaining quota")
+	}
+	missing, exceeded := fitLFSQuota(missing, remaining)
+
+	if len(missing) > 0 {
+		err = m.downloadLFSObjects(ctx, missing)
+		if err != nil {
+			return err
+		}
+	}
+	if exceeded > 0 {
+		return errors.Errorf("%s: %d object(s) are not fetched", message, exceeded)
+	}
+	return nil
+}
+
+// downloadLFSObjects downloads given LFS objects from the LFS server of
+// upstream and stores them.
+func (m *Mirror) downloadLFSObjects(ctx context.Context, objects []lfsutil.Object) error {
+	endpoint := m.lfsEndpoint()
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		// NOTE: The parse error contains credentials in the endpoint.
+		return errors.Errorf("LFS endpoint %q is not valid", HandleMirrorCredentials(endpoint, true))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Errorf("LFS endpoint %q is not HTTP or HTTPS", HandleMirrorCredentials(endpoint, true))
+	}
+
+	storagers, err := NewLFSStoragers()
+	if err != nil {
+		return errors.Wrap(err, "new storagers")
+	}
+	s, ok := storagers[lfsutil.Storage(conf.LFS.Storage)]
+	if !ok {
+		return errors.Errorf("storage %q is not configured", conf.LFS.Storage)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(conf.Git.Timeout.Mirror)*time.Second)
+	defer cancel()
+
+	client := &lfsutil.Client{
+		Endpoint: endpoint,
+		AllowURL: func(u *url.URL) bool {
+			return (u.Scheme == "http" || u.Scheme == "https") &&
+				!netutil.IsBlockedLocalHostname(u.Hostname(), conf.Security.LocalNetworkAllowlist)
+		},
+	}
+	return client.Download(ctx, objects, s, func(oid lfsutil.OID, size int64) error {
+		err := LFS.CreateObject(ctx, m.RepoID, oid, size, s.Storage())
+		if err != nil {
+			return errors.Wrapf(err, "create object %q", oid)
+		}
+		log.Trace("[LFS] Object fetched from mirror upstream [repo_id: %d]: %q", m.RepoID, oid)
+		return nil
+	})
+}
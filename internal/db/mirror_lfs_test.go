@@ -0,0 +1,53 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gogs.io/gogs/internal/lfsutil"
+)
+
+func Test_fitLFSQuota(t *testing.T) {
+	objects := []lfsutil.Object{
+		{OID: "1", Size: 5},
+		{OID: "2", Size: 10},
+		{OID: "3", Size: 3},
+	}
+
+	tests := []struct {
+		name         string
+		remaining    int64
+		wantFit      []lfsutil.Object
+		wantExceeded int
+	}{
+		{
+			name:      "no quota",
+			remaining: -1,
+			wantFit:   objects,
+		},
+		{
+			name:         "skip objects that do not fit",
+			remaining:    9,
+			wantFit:      []lfsutil.Object{objects[0], objects[2]},
+			wantExceeded: 1,
+		},
+		{
+			name:         "quota exceeded",
+			remaining:    0,
+			wantFit:      []lfsutil.Object{},
+			wantExceeded: 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fit, exceeded := fitLFSQuota(objects, test.remaining)
+			assert.Equal(t, test.wantFit, fit)
+			assert.Equal(t, test.wantExceeded, exceeded)
+		})
+	}
+}
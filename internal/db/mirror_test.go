@@ -5,9 +5,13 @@
 package db
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_parseRemoteUpdateOutput(t *testing.T) {
@@ -35,3 +39,91 @@ From https://try.gogs.io/unknwon/upsteam
 		})
 	}
 }
+
+func TestParseMirrorRefspecs(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "empty",
+			input: " \n",
+			want:  "",
+		},
+		{
+			name:  "patterns",
+			input: "refs/heads/release/*\r\nrefs/tags/*\n",
+			want:  "+refs/heads/release/*:refs/heads/release/*\n+refs/tags/*:refs/tags/*",
+		},
+		{
+			name:  "with destination",
+			input: "+refs/heads/main:refs/heads/upstream",
+			want:  "+refs/heads/main:refs/heads/upstream",
+		},
+		{
+			name:    "not a reference",
+			input:   "main",
+			wantErr: ErrInvalidRefspec{Refspec: "main"},
+		},
+		{
+			name:    "mismatched patterns",
+			input:   "refs/heads/*:refs/heads/main",
+			wantErr: ErrInvalidRefspec{Refspec: "refs/heads/*:refs/heads/main"},
+		},
+		{
+			name:    "multiple colons",
+			input:   "refs/heads/a:refs/heads/b:refs/heads/c",
+			wantErr: ErrInvalidRefspec{Refspec: "refs/heads/a:refs/heads/b:refs/heads/c"},
+		},
+		{
+			name:    "parent directory",
+			input:   "refs/heads/../../config",
+			wantErr: ErrInvalidRefspec{Refspec: "refs/heads/../../config"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseMirrorRefspecs(test.input)
+			assert.Equal(t, test.wantErr, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func Test_cloneMirrorRefspecs(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "source.git")
+	work := filepath.Join(root, "work")
+	runTestGit(t, root, "init", "--bare", source)
+	runTestGit(t, root, "clone", source, work)
+	runTestGit(t, work, "commit", "--allow-empty", "-m", "initial")
+	runTestGit(t, work, "tag", "v1.0.0")
+	runTestGit(t, work, "push", "origin", "HEAD:refs/heads/main", "HEAD:refs/heads/release/v1", "HEAD:refs/heads/feature", "v1.0.0")
+	runTestGit(t, source, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	t.Run("upstream HEAD is fetched", func(t *testing.T) {
+		repoPath := filepath.Join(t.TempDir(), "mirror.git")
+		refspecs, err := ParseMirrorRefspecs("refs/heads/main refs/tags/*")
+		require.NoError(t, err)
+		err = cloneMirrorRefspecs(source, repoPath, strings.Fields(refspecs), time.Minute)
+		require.NoError(t, err)
+
+		refs := runTestGit(t, repoPath, "for-each-ref", "--format=%(refname)")
+		assert.Equal(t, "refs/heads/main\nrefs/tags/v1.0.0", refs)
+		assert.Equal(t, "refs/heads/main", runTestGit(t, repoPath, "symbolic-ref", "HEAD"))
+	})
+
+	t.Run("upstream HEAD is not fetched", func(t *testing.T) {
+		repoPath := filepath.Join(t.TempDir(), "mirror.git")
+		refspecs, err := ParseMirrorRefspecs("refs/heads/release/*")
+		require.NoError(t, err)
+		err = cloneMirrorRefspecs(source, repoPath, strings.Fields(refspecs), time.Minute)
+		require.NoError(t, err)
+
+		refs := runTestGit(t, repoPath, "for-each-ref", "--format=%(refname)")
+		assert.Equal(t, "refs/heads/release/v1", refs)
+		assert.Equal(t, "refs/heads/release/v1", runTestGit(t, repoPath, "symbolic-ref", "HEAD"))
+	})
+}
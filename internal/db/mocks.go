@@ -6,6 +6,9 @@ package db
 
 import (
 	"testing"
+
+	"gogs.io/gogs/internal/search"
+	"gogs.io/gogs/internal/sync"
 )
 
 func SetMockAccessTokensStore(t *testing.T, mock AccessTokensStore) {
@@ -24,6 +27,19 @@ func SetMockLFSStore(t *testing.T, mock LFSStore) {
 	})
 }
 
+// setMockCodeIndexer sets the code indexer with an empty queue, and returns the
+// queue of IDs of repositories to be indexed.
+func setMockCodeIndexer(t *testing.T, mock search.CodeIndexer) *sync.UniqueQueue {
+	beforeIndexer, beforeQueue := codeIndexer, codeIndexerQueue
+	codeIndexer = mock
+	codeIndexerQueue = sync.NewUniqueQueue(1000)
+	t.Cleanup(func() {
+		codeIndexer = beforeIndexer
+		codeIndexerQueue = beforeQueue
+	})
+	return codeIndexerQueue
+}
+
 func setMockLoginSourcesStore(t *testing.T, mock LoginSourcesStore) {
 	before := LoginSources
 	LoginSources = mock
@@ -53,7 +53,7 @@ func init() {
 		new(User), new(PublicKey), new(TwoFactor), new(TwoFactorRecoveryCode),
 		new(Repository), new(DeployKey), new(Collaboration), new(Upload),
 		new(Watch), new(Star), new(Follow),
-		new(Issue), new(PullRequest), new(Comment), new(Attachment), new(IssueUser),
+		new(Issue), new(PullRequest), new(Comment), new(Attachment), new(IssueUser), new(Review),
 		new(Label), new(IssueLabel), new(Milestone),
 		new(Mirror), new(Release), new(Webhook), new(HookTask),
 		new(ProtectBranch), new(ProtectBranchWhitelist),
@@ -195,7 +195,7 @@ func NewEngine() (err error) {
 		return errors.Wrap(err, "sync tables")
 	}
 
-	return nil
+	return initDiskQueues()
 }
 
 type Statistic struct {
@@ -0,0 +1,286 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// notificationRecipients is a set of IDs of users to be notified with the
+// reasons why they are notified.
+type notificationRecipients map[int64]NotificationReason
+
+// add adds the user to the recipients, the reason is kept if the user is
+// already added for a reason with higher priority.
+func (rs notificationRecipients) add(userID int64, reason NotificationReason) {
+	if userID <= 0 {
+		return
+	}
+	if existing, ok := rs[userID]; ok && existing.priority() >= reason.priority() {
+		return
+	}
+	rs[userID] = reason
+}
+
+// notifyRecipients records activity on the subject of the repository made by
+// the doer for the recipients. The doer, organizations, inactive users and
+// users who do not have read access to the repository are never notified.
+func notifyRecipients(repo *Repository, doer *User, subjectType NotificationSubjectType, subjectID, commentID int64, recipients notificationRecipients) error {
+	delete(recipients, doer.ID)
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(recipients))
+	for userID := range recipients {
+		userIDs = append(userIDs, userID)
+	}
+	users := make([]*User, 0, len(userIDs))
+	if err := x.In("id", userIDs).Find(&users); err != nil {
+		return errors.Wrap(err, "find users")
+	}
+
+	ctx := context.TODO()
+	allowed := make(map[int64]NotificationReason, len(users))
+	for _, u := range users {
+		if u.IsOrganization() || !u.IsActive {
+			continue
+		}
+		if !Perms.Authorize(ctx, u.ID, repo.ID, AccessModeRead,
+			AccessModeOptions{
+				OwnerID: repo.OwnerID,
+				Private: repo.IsPrivate,
+			},
+		) {
+			continue
+		}
+		allowed[u.ID] = recipients[u.ID]
+	}
+	return Notifications.Notify(ctx, repo.ID, subjectType, subjectID, commentID, allowed)
+}
+
+// notifyIssueParticipants notifies repository watchers, users who participated
+// in the issue, the assignee and mentioned users of new activity on the issue
+// made by the doer. The commentID is zero if the activity is not a comment.
+func notifyIssueParticipants(issue *Issue, doer *User, commentID int64, mentions []string) error {
+	if issue.Repo == nil {
+		repo, err := getRepositoryByID(x, issue.RepoID)
+		if err != nil {
+			return errors.Wrap(err, "get repository")
+		}
+		issue.Repo = repo
+	}
+
+	watchers, err := GetWatchers(issue.RepoID)
+	if err != nil {
+		return errors.Wrap(err, "get watchers")
+	}
+	participants, err := GetParticipantsByIssueID(issue.ID)
+	if err != nil {
+		return errors.Wrap(err, "get participants")
+	}
+
+	recipients := make(notificationRecipients, len(watchers)+len(participants)+len(mentions)+2)
+	for _, w := range watchers {
+		recipients.add(w.UserID, NotificationReasonSubscribed)
+	}
+	for _, p := range participants {
+		recipients.add(p.ID, NotificationReasonComment)
+	}
+	recipients.add(issue.PosterID, NotificationReasonAuthor)
+	if issue.IsPull {
+		recipients.add(issue.AssigneeID, NotificationReasonReviewRequested)
+	} else {
+		recipients.add(issue.AssigneeID, NotificationReasonAssign)
+	}
+	for _, userID := range GetUserIDsByNames(mentions) {
+		recipients.add(userID, NotificationReasonMention)
+	}
+
+	subjectType := NotificationSubjectIssue
+	if issue.IsPull {
+		subjectType = NotificationSubjectPull
+	}
+	return notifyRecipients(issue.Repo, doer, subjectType, issue.ID, commentID, recipients)
+}
+
+// notifyIssueAssignee notifies the assignee of the issue that the issue is
+// assigned to them by the doer. The assignee of a pull request is requested
+// for a review.
+func notifyIssueAssignee(issue *Issue, doer *User) error {
+	if issue.AssigneeID <= 0 {
+		return nil
+	}
+
+	subjectType, reason := NotificationSubjectIssue, NotificationReasonAssign
+	if issue.IsPull {
+		subjectType, reason = NotificationSubjectPull, NotificationReasonReviewRequested
+	}
+	recipients := notificationRecipients{}
+	recipients.add(issue.AssigneeID, reason)
+	return notifyRecipients(issue.Repo, doer, subjectType, issue.ID, 0, recipients)
+}
+
+// notifyReleaseWatchers notifies repository watchers that the release is
+// published. The release must have its repository and publisher loaded.
+func notifyReleaseWatchers(r *Release) error {
+	watchers, err := GetWatchers(r.RepoID)
+	if err != nil {
+		return errors.Wrap(err, "get watchers")
+	}
+
+	recipients := make(notificationRecipients, len(watchers))
+	for _, w := range watchers {
+		recipients.add(w.UserID, NotificationReasonSubscribed)
+	}
+	return notifyRecipients(r.Repo, r.Publisher, NotificationSubjectRelease, r.ID, 0, recipients)
+}
+
+// NotificationThread is a notification with its repository and subject
+// loaded.
+type NotificationThread struct {
+	*Notification
+	Repo *Repository
+	// The subject, only one of them is set depending on the subject type.
+	Issue   *Issue
+	Release *Release
+}
+
+// Title returns the title of the subject.
+func (t *NotificationThread) Title() string {
+	if t.Release != nil {
+		if t.Release.Title != "" {
+			return t.Release.Title
+		}
+		return t.Release.TagName
+	}
+	return t.Issue.Title
+}
+
+// HTMLURL returns the URL of the latest activity on the subject.
+func (t *NotificationThread) HTMLURL() string {
+	if t.Release != nil {
+		return t.Repo.HTMLURL() + "/releases"
+	}
+	if t.LatestCommentID > 0 {
+		return t.Issue.HTMLURL() + "#" + CommentHashTag(t.LatestCommentID)
+	}
+	return t.Issue.HTMLURL()
+}
+
+// GetNotificationRepos returns repositories with given IDs that the user has
+// read access to, in alphabetical order of their full names.
+func GetNotificationRepos(userID int64, repoIDs []int64) ([]*Repository, error) {
+	if len(repoIDs) == 0 {
+		return []*Repository{}, nil
+	}
+
+	repos := make([]*Repository, 0, len(repoIDs))
+	if err := x.In("id", repoIDs).Find(&repos); err != nil {
+		return nil, errors.Wrap(err, "find repositories")
+	} else if err = RepositoryList(repos).LoadAttributes(); err != nil {
+		return nil, errors.Wrap(err, "load attributes")
+	}
+
+	ctx := context.TODO()
+	allowed := repos[:0]
+	for _, repo := range repos {
+		if !Perms.Authorize(ctx, userID, repo.ID, AccessModeRead,
+			AccessModeOptions{
+				OwnerID: repo.OwnerID,
+				Private: repo.IsPrivate,
+			},
+		) {
+			continue
+		}
+		allowed = append(allowed, repo)
+	}
+	sort.Slice(allowed, func(i, j int) bool {
+		return allowed[i].FullName() < allowed[j].FullName()
+	})
+	return allowed, nil
+}
+
+// LoadNotificationThreads loads repositories and subjects of notifications of
+// the user. Notifications whose subjects no longer exist or repositories the
+// user no longer has read access to are excluded.
+func LoadNotificationThreads(userID int64, notifications []*Notification) ([]*NotificationThread, error) {
+	repoIDs := make([]int64, 0, len(notifications))
+	issueIDs := make([]int64, 0, len(notifications))
+	releaseIDs := make([]int64, 0, len(notifications))
+	for _, n := range notifications {
+		repoIDs = append(repoIDs, n.RepoID)
+		switch n.SubjectType {
+		case NotificationSubjectIssue, NotificationSubjectPull:
+			issueIDs = append(issueIDs, n.SubjectID)
+		case NotificationSubjectRelease:
+			releaseIDs = append(releaseIDs, n.SubjectID)
+		}
+	}
+
+	repos, err := GetNotificationRepos(userID, repoIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "get repositories")
+	}
+	reposByID := make(map[int64]*Repository, len(repos))
+	for _, repo := range repos {
+		reposByID[repo.ID] = repo
+	}
+
+	issues := make([]*Issue, 0, len(issueIDs))
+	if len(issueIDs) > 0 {
+		if err := x.In("id", issueIDs).Find(&issues); err != nil {
+			return nil, errors.Wrap(err, "find issues")
+		}
+	}
+	issuesByID := make(map[int64]*Issue, len(issues))
+	for _, issue := range issues {
+		issuesByID[issue.ID] = issue
+	}
+
+	releases := make([]*Release, 0, len(releaseIDs))
+	if len(releaseIDs) > 0 {
+		if err := x.In("id", releaseIDs).Find(&releases); err != nil {
+			return nil, errors.Wrap(err, "find releases")
+		}
+	}
+	releasesByID := make(map[int64]*Release, len(releases))
+	for _, r := range releases {
+		releasesByID[r.ID] = r
+	}
+
+	threads := make([]*NotificationThread, 0, len(notifications))
+	for _, n := range notifications {
+		t := &NotificationThread{
+			Notification: n,
+			Repo:         reposByID[n.RepoID],
+		}
+		if t.Repo == nil {
+			continue
+		}
+
+		switch n.SubjectType {
+		case NotificationSubjectIssue, NotificationSubjectPull:
+			t.Issue = issuesByID[n.SubjectID]
+			if t.Issue == nil {
+				continue
+			}
+			t.Issue.Repo = t.Repo
+		case NotificationSubjectRelease:
+			t.Release = releasesByID[n.SubjectID]
+			if t.Release == nil {
+				continue
+			}
+			t.Release.Repo = t.Repo
+		default:
+			continue
+		}
+		threads = append(threads, t)
+	}
+	return threads, nil
+}
@@ -0,0 +1,433 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// NotificationsStore is the persistent interface for notifications.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type NotificationsStore interface {
+	// Count returns the number of notifications of the user that match the
+	// options, paging options are ignored.
+	Count(ctx context.Context, userID int64, opts ListNotificationsOptions) (int64, error)
+	// CountByRepo returns the number of notifications of the user in each
+	// repository that match the options, the repository filter and paging
+	// options are ignored.
+	CountByRepo(ctx context.Context, userID int64, opts ListNotificationsOptions) (map[int64]int64, error)
+	// CountUnread returns the number of unread notifications of the user. The
+	// count is cached in the user row until the user reads or receives
+	// notifications, or accesses to repositories are changed. Counts of site
+	// admins are not cached.
+	CountUnread(ctx context.Context, u *User) (int64, error)
+	// DeleteBySubject deletes all notifications of the subject.
+	DeleteBySubject(ctx context.Context, subjectType NotificationSubjectType, subjectID int64) error
+	// GetByID returns the notification with given ID of the user. It returns
+	// ErrNotificationNotExist when not found.
+	GetByID(ctx context.Context, userID, id int64) (*Notification, error)
+	// List returns notifications of the user that match the options, in reverse
+	// chronological order of the latest activity.
+	List(ctx context.Context, userID int64, opts ListNotificationsOptions) ([]*Notification, error)
+	// MarkAllRead marks all notifications of the user that are updated no later
+	// than given time as read. Only notifications of the repository are marked
+	// when repoID is not zero.
+	MarkAllRead(ctx context.Context, userID, repoID int64, before time.Time) error
+	// MarkRead marks the notification with given ID of the user as read. It
+	// returns ErrNotificationNotExist when not found.
+	MarkRead(ctx context.Context, userID, id int64) error
+	// Notify records new activity on the subject for each of the recipients with
+	// the reason why the recipient is notified. The existing notification of
+	// the subject of a recipient is marked as unread again instead of creating a
+	// new one.
+	Notify(ctx context.Context, repoID int64, subjectType NotificationSubjectType, subjectID, commentID int64, recipients map[int64]NotificationReason) error
+}
+
+var Notifications NotificationsStore
+
+// NotificationSubjectType is the type of the thing a notification is about.
+type NotificationSubjectType string
+
+const (
+	NotificationSubjectIssue   NotificationSubjectType = "issue"
+	NotificationSubjectPull    NotificationSubjectType = "pull"
+	NotificationSubjectRelease NotificationSubjectType = "release"
+)
+
+// NotificationReason is the reason why a user is notified, the values are the
+// same as GitHub's.
+type NotificationReason string
+
+const (
+	NotificationReasonSubscribed      NotificationReason = "subscribed"
+	NotificationReasonComment         NotificationReason = "comment"
+	NotificationReasonAuthor          NotificationReason = "author"
+	NotificationReasonAssign          NotificationReason = "assign"
+	NotificationReasonReviewRequested NotificationReason = "review_requested"
+	NotificationReasonMention         NotificationReason = "mention"
+)
+
+// priority returns the priority of the reason, a user involved in the subject
+// for multiple reasons is notified with the one has the highest priority.
+func (r NotificationReason) priority() int {
+	switch r {
+	case NotificationReasonMention:
+		return 5
+	case NotificationReasonReviewRequested:
+		return 4
+	case NotificationReasonAssign:
+		return 3
+	case NotificationReasonAuthor:
+		return 2
+	case NotificationReasonComment:
+		return 1
+	}
+	return 0
+}
+
+// Notification is a thread of activity on a subject (e.g. an issue) that a
+// user is notified of. Each user has at most one notification for a subject.
+type Notification struct {
+	ID          int64                   `gorm:"primaryKey"`
+	UserID      int64                   `gorm:"uniqueIndex:notification_user_subject_unique;index:idx_notification_user_id_updated_at;not null"`
+	RepoID      int64                   `gorm:"index;not null"`
+	SubjectType NotificationSubjectType `gorm:"type:VARCHAR(7);uniqueIndex:notification_user_subject_unique;not null"`
+	SubjectID   int64                   `gorm:"uniqueIndex:notification_user_subject_unique;not null"`
+	Reason      NotificationReason      `gorm:"type:VARCHAR(16);not null"`
+	// The ID of the latest comment on the subject, zero means the subject
+	// itself is the latest activity.
+	LatestCommentID int64 `gorm:"not null"`
+	IsRead          bool  `gorm:"not null"`
+	LastReadAt      *time.Time
+	CreatedAt       time.Time `gorm:"not null"`
+	UpdatedAt       time.Time `gorm:"index:idx_notification_user_id_updated_at;not null"`
+}
+
+var _ NotificationsStore = (*notifications)(nil)
+
+type notifications struct {
+	*gorm.DB
+}
+
+// NewNotificationsStore returns a persistent interface for notifications with
+// given database connection.
+func NewNotificationsStore(db *gorm.DB) NotificationsStore {
+	return &notifications{DB: db}
+}
+
+type ListNotificationsOptions struct {
+	// The repository to filter by, zero means all repositories.
+	RepoID int64
+	// Whether to include notifications that have been read.
+	All bool
+	// Only include notifications updated after the time when not zero.
+	Since time.Time
+	// Only include notifications updated before the time when not zero.
+	Before   time.Time
+	Page     int
+	PageSize int
+}
+
+func (db *notifications) query(ctx context.Context, userID int64, opts ListNotificationsOptions) *gorm.DB {
+	/*
+		Equivalent SQL for PostgreSQL:
+
+		SELECT * FROM "notification"
+		WHERE
+			user_id = @userID
+		AND (
+				repo_id IN (
+					SELECT id FROM "repository"
+					WHERE is_private = FALSE OR owner_id = @userID
+				)
+			OR	repo_id IN (
+					SELECT repo_id FROM "access"
+					WHERE user_id = @userID AND mode >= @AccessModeRead
+				)
+			OR	@userID IN (
+					SELECT id FROM "user"
+					WHERE is_admin = TRUE
+				)
+			)
+	*/
+	query := db.WithContext(ctx).Model(&Notification{}).
+		Where("user_id = ?", userID).
+		// Exclude repositories that the user no longer has read access to.
+		Where(db.
+			Where("repo_id IN (?)",
+				db.Select("id").
+					Table("repository").
+					Where("is_private = ? OR owner_id = ?", false, userID),
+			).
+			Or("repo_id IN (?)",
+				db.Select("repo_id").
+					Table("access").
+					Where("user_id = ? AND mode >= ?", userID, AccessModeRead),
+			).
+			// Site admins have access to all repositories.
+			Or("? IN (?)",
+				userID,
+				db.Model(&User{}).
+					Select("id").
+					Where("is_admin = ?", true),
+			),
+		)
+	if opts.RepoID > 0 {
+		query = query.Where("repo_id = ?", opts.RepoID)
+	}
+	if !opts.All {
+		query = query.Where("is_read = ?", false)
+	}
+	if !opts.Since.IsZero() {
+		query = query.Where("updated_at > ?", opts.Since.UTC())
+	}
+	if !opts.Before.IsZero() {
+		query = query.Where("updated_at < ?", opts.Before.UTC())
+	}
+	return query
+}
+
+func (db *notifications) Count(ctx context.Context, userID int64, opts ListNotificationsOptions) (int64, error) {
+	var count int64
+	return count, db.query(ctx, userID, opts).Count(&count).Error
+}
+
+func (db *notifications) CountByRepo(ctx context.Context, userID int64, opts ListNotificationsOptions) (map[int64]int64, error) {
+	opts.RepoID = 0
+	var rows []struct {
+		RepoID int64
+		Count  int64
+	}
+	err := db.query(ctx, userID, opts).
+		Select("repo_id, COUNT(*) AS count").
+		Group("repo_id").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		counts[row.RepoID] = row.Count
+	}
+	return counts, nil
+}
+
+func (db *notifications) CountUnread(ctx context.Context, u *User) (int64, error) {
+	// The count of site admins is not cached, so that the cache does not need to
+	// be invalidated when the admin status changes.
+	if u.IsAdmin {
+		return db.Count(ctx, u.ID, ListNotificationsOptions{})
+	}
+
+	if u.NumUnreadNotifications >= 0 {
+		return u.NumUnreadNotifications, nil
+	}
+
+	count, err := db.Count(ctx, u.ID, ListNotificationsOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	// The cache may have been invalidated again in the meantime, it is fine to
+	// leave it as is.
+	err = db.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND num_unread_notifications < ?", u.ID, 0).
+		UpdateColumn("num_unread_notifications", count).
+		Error
+	if err != nil {
+		return 0, errors.Wrap(err, "cache count")
+	}
+	u.NumUnreadNotifications = count
+	return count, nil
+}
+
+// invalidateUnreadCounts resets the cached number of unread notifications of
+// given users.
+func invalidateUnreadCounts(tx *gorm.DB, userIDs ...int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return tx.Model(&User{}).
+		Where("id IN (?)", userIDs).
+		UpdateColumn("num_unread_notifications", -1).
+		Error
+}
+
+// invalidateRepoUnreadCounts resets the cached number of unread notifications
+// of users who have unread notifications in the repository. It must be called
+// when accesses to the repository are changed, because notifications in
+// repositories that users have no access to are not counted.
+//
+// NOTE: Accesses are not yet migrated to GORM, this is called within the
+// legacy sessions.
+func invalidateRepoUnreadCounts(e Engine, repoID int64) error {
+	_, err := e.Exec("UPDATE `user` SET num_unread_notifications = -1 WHERE id IN (SELECT user_id FROM notification WHERE repo_id = ? AND is_read = ?)", repoID, false)
+	return err
+}
+
+func (db *notifications) DeleteBySubject(ctx context.Context, subjectType NotificationSubjectType, subjectID int64) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var userIDs []int64
+		err := tx.Model(&Notification{}).
+			Where("subject_type = ? AND subject_id = ? AND is_read = ?", subjectType, subjectID, false).
+			Pluck("user_id", &userIDs).
+			Error
+		if err != nil {
+			return errors.Wrap(err, "list users")
+		}
+
+		err = tx.Where("subject_type = ? AND subject_id = ?", subjectType, subjectID).Delete(&Notification{}).Error
+		if err != nil {
+			return errors.Wrap(err, "delete")
+		}
+		return invalidateUnreadCounts(tx, userIDs...)
+	})
+}
+
+var _ errutil.NotFound = (*ErrNotificationNotExist)(nil)
+
+type ErrNotificationNotExist struct {
+	args errutil.Args
+}
+
+func IsErrNotificationNotExist(err error) bool {
+	_, ok := err.(ErrNotificationNotExist)
+	return ok
+}
+
+func (err ErrNotificationNotExist) Error() string {
+	return fmt.Sprintf("notification does not exist: %v", err.args)
+}
+
+func (ErrNotificationNotExist) NotFound() bool {
+	return true
+}
+
+func (db *notifications) GetByID(ctx context.Context, userID, id int64) (*Notification, error) {
+	n := new(Notification)
+	err := db.WithContext(ctx).Where("user_id = ? AND id = ?", userID, id).First(n).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotificationNotExist{args: errutil.Args{"userID": userID, "id": id}}
+		}
+		return nil, err
+	}
+	return n, nil
+}
+
+func (db *notifications) List(ctx context.Context, userID int64, opts ListNotificationsOptions) ([]*Notification, error) {
+	query := db.query(ctx, userID, opts).Order("updated_at DESC, id DESC")
+	if opts.PageSize > 0 {
+		if opts.Page <= 0 {
+			opts.Page = 1
+		}
+		query = query.Limit(opts.PageSize).Offset((opts.Page - 1) * opts.PageSize)
+	}
+
+	notifications := make([]*Notification, 0, opts.PageSize)
+	return notifications, query.Find(&notifications).Error
+}
+
+func (db *notifications) MarkAllRead(ctx context.Context, userID, repoID int64, before time.Time) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&Notification{}).
+			Where("user_id = ? AND is_read = ? AND updated_at <= ?", userID, false, before.UTC())
+		if repoID > 0 {
+			query = query.Where("repo_id = ?", repoID)
+		}
+		err := query.
+			UpdateColumns(map[string]interface{}{
+				"is_read":      true,
+				"last_read_at": tx.NowFunc(),
+			}).
+			Error
+		if err != nil {
+			return err
+		}
+		return invalidateUnreadCounts(tx, userID)
+	})
+}
+
+func (db *notifications) MarkRead(ctx context.Context, userID, id int64) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Notification{}).
+			Where("user_id = ? AND id = ?", userID, id).
+			UpdateColumns(map[string]interface{}{
+				"is_read":      true,
+				"last_read_at": tx.NowFunc(),
+			})
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return ErrNotificationNotExist{args: errutil.Args{"userID": userID, "id": id}}
+		}
+		return invalidateUnreadCounts(tx, userID)
+	})
+}
+
+func (db *notifications) Notify(ctx context.Context, repoID int64, subjectType NotificationSubjectType, subjectID, commentID int64, recipients map[int64]NotificationReason) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []*Notification
+		err := tx.Where("subject_type = ? AND subject_id = ?", subjectType, subjectID).Find(&existing).Error
+		if err != nil {
+			return errors.Wrap(err, "list existing")
+		}
+		existingByUserID := make(map[int64]*Notification, len(existing))
+		for _, n := range existing {
+			existingByUserID[n.UserID] = n
+		}
+
+		now := tx.NowFunc()
+		for userID, reason := range recipients {
+			n := existingByUserID[userID]
+			if n == nil {
+				err = tx.Create(&Notification{
+					UserID:          userID,
+					RepoID:          repoID,
+					SubjectType:     subjectType,
+					SubjectID:       subjectID,
+					Reason:          reason,
+					LatestCommentID: commentID,
+					CreatedAt:       now,
+					UpdatedAt:       now,
+				}).Error
+				if err != nil {
+					return errors.Wrapf(err, "create for user %d", userID)
+				}
+				continue
+			}
+
+			err = tx.Model(n).UpdateColumns(map[string]interface{}{
+				"reason":            reason,
+				"latest_comment_id": commentID,
+				"is_read":           false,
+				"updated_at":        now,
+			}).Error
+			if err != nil {
+				return errors.Wrapf(err, "update for user %d", userID)
+			}
+		}
+
+		userIDs := make([]int64, 0, len(recipients))
+		for userID := range recipients {
+			userIDs = append(userIDs, userID)
+		}
+		return invalidateUnreadCounts(tx, userIDs...)
+	})
+}
@@ -0,0 +1,385 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestNotificationRecipients(t *testing.T) {
+	recipients := notificationRecipients{}
+	recipients.add(0, NotificationReasonMention)
+	recipients.add(1, NotificationReasonSubscribed)
+	recipients.add(1, NotificationReasonMention)
+	recipients.add(1, NotificationReasonComment)
+	recipients.add(2, NotificationReasonAuthor)
+	recipients.add(2, NotificationReasonSubscribed)
+
+	want := notificationRecipients{
+		1: NotificationReasonMention,
+		2: NotificationReasonAuthor,
+	}
+	assert.Equal(t, want, recipients)
+}
+
+func TestNotifications(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(Notification), new(User), new(Repository), new(Access)}
+	db := &notifications{
+		DB: dbtest.NewDB(t, "notifications", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *notifications)
+	}{
+		{"Count", notificationsCount},
+		{"CountByRepo", notificationsCountByRepo},
+		{"CountUnread", notificationsCountUnread},
+		{"DeleteBySubject", notificationsDeleteBySubject},
+		{"GetByID", notificationsGetByID},
+		{"List", notificationsList},
+		{"MarkAllRead", notificationsMarkAllRead},
+		{"MarkRead", notificationsMarkRead},
+		{"Notify", notificationsNotify},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+
+			// Notifications are in public repositories unless noted otherwise.
+			for _, repo := range []*Repository{
+				{ID: 1, OwnerID: 9, LowerName: "repo1", Name: "repo1"},
+				{ID: 2, OwnerID: 9, LowerName: "repo2", Name: "repo2"},
+			} {
+				err := db.Create(repo).Error
+				require.NoError(t, err)
+			}
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func notificationsCount(t *testing.T, db *notifications) {
+	ctx := context.Background()
+
+	err := db.Create(&Repository{ID: 3, OwnerID: 9, LowerName: "private", Name: "private", IsPrivate: true}).Error
+	require.NoError(t, err)
+	for _, repoID := range []int64{1, 3} {
+		err = db.Notify(ctx, repoID, NotificationSubjectIssue, repoID, 0, map[int64]NotificationReason{1: NotificationReasonSubscribed})
+		require.NoError(t, err)
+	}
+
+	// Notifications of repositories the user no longer has access to are excluded
+	count, err := db.Count(ctx, 1, ListNotificationsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	err = db.Create(&Access{UserID: 1, RepoID: 3, Mode: AccessModeRead}).Error
+	require.NoError(t, err)
+	count, err = db.Count(ctx, 1, ListNotificationsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// Site admins have access to all repositories
+	err = db.Create(&User{ID: 2, LowerName: "admin", Name: "admin", IsAdmin: true}).Error
+	require.NoError(t, err)
+	err = db.Notify(ctx, 3, NotificationSubjectIssue, 3, 0, map[int64]NotificationReason{2: NotificationReasonSubscribed})
+	require.NoError(t, err)
+	count, err = db.Count(ctx, 2, ListNotificationsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func notificationsCountByRepo(t *testing.T, db *notifications) {
+	ctx := context.Background()
+
+	for _, n := range []struct {
+		repoID    int64
+		subjectID int64
+	}{
+		{1, 1},
+		{1, 2},
+		{2, 3},
+	} {
+		err := db.Notify(ctx, n.repoID, NotificationSubjectIssue, n.subjectID, 0, map[int64]NotificationReason{1: NotificationReasonSubscribed})
+		require.NoError(t, err)
+	}
+	err := db.MarkAllRead(ctx, 1, 2, time.Now())
+	require.NoError(t, err)
+
+	got, err := db.CountByRepo(ctx, 1, ListNotificationsOptions{RepoID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 2}, got)
+
+	got, err = db.CountByRepo(ctx, 1, ListNotificationsOptions{All: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 2, 2: 1}, got)
+}
+
+func notificationsCountUnread(t *testing.T, db *notifications) {
+	ctx := context.Background()
+
+	err := db.Create(&User{ID: 1, LowerName: "alice", Name: "alice"}).Error
+	require.NoError(t, err)
+	getUser := func() *User {
+		u := new(User)
+		err := db.First(u, 1).Error
+		require.NoError(t, err)
+		return u
+	}
+
+	u := getUser()
+	assert.Equal(t, int64(-1), u.NumUnreadNotifications)
+	count, err := db.CountUnread(ctx, u)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Zero(t, getUser().NumUnreadNotifications)
+
+	// New notifications invalidate the cache
+	err = db.Notify(ctx, 1, NotificationSubjectIssue, 1, 0, map[int64]NotificationReason{1: NotificationReasonSubscribed})
+	require.NoError(t, err)
+	u = getUser()
+	assert.Equal(t, int64(-1), u.NumUnreadNotifications)
+	count, err = db.CountUnread(ctx, u)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, int64(1), getUser().NumUnreadNotifications)
+
+	// So does reading them
+	err = db.MarkAllRead(ctx, 1, 0, time.Now())
+	require.NoError(t, err)
+	count, err = db.CountUnread(ctx, getUser())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	// Counts of site admins are not cached
+	err = db.Model(&User{}).Where("id = ?", 1).UpdateColumn("is_admin", true).Error
+	require.NoError(t, err)
+	err = db.Notify(ctx, 1, NotificationSubjectIssue, 2, 0, map[int64]NotificationReason{1: NotificationReasonSubscribed})
+	require.NoError(t, err)
+	count, err = db.CountUnread(ctx, getUser())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, int64(-1), getUser().NumUnreadNotifications)
+}
+
+func notificationsDeleteBySubject(t *testing.T, db *notifications) {
+	ctx := context.Background()
+
+	err := db.Notify(ctx, 1, NotificationSubjectRelease, 1, 0, map[int64]NotificationReason{1: NotificationReasonSubscribed})
+	require.NoError(t, err)
+	err = db.Notify(ctx, 1, NotificationSubjectIssue, 1, 0, map[int64]NotificationReason{1: NotificationReasonSubscribed})
+	require.NoError(t, err)
+
+	err = db.DeleteBySubject(ctx, NotificationSubjectRelease, 1)
+	require.NoError(t, err)
+
+	got, err := db.List(ctx, 1, ListNotificationsOptions{})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, NotificationSubjectIssue, got[0].SubjectType)
+}
+
+func notificationsGetByID(t *testing.T, db *notifications) {
+	ctx := context.Background()
+
+	err := db.Notify(ctx, 1, NotificationSubjectIssue, 1, 0, map[int64]NotificationReason{1: NotificationReasonSubscribed})
+	require.NoError(t, err)
+	list, err := db.List(ctx, 1, ListNotificationsOptions{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	got, err := db.GetByID(ctx, 1, list[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got.SubjectID)
+
+	// Notifications of other users are invisible
+	_, err = db.GetByID(ctx, 2, list[0].ID)
+	wantErr := ErrNotificationNotExist{args: errutil.Args{"userID": int64(2), "id": list[0].ID}}
+	assert.Equal(t, wantErr, err)
+}
+
+func notificationsList(t *testing.T, db *notifications) {
+	ctx := context.Background()
+
+	for _, n := range []struct {
+		repoID    int64
+		subjectID int64
+	}{
+		{1, 1},
+		{1, 2},
+		{2, 3},
+	} {
+		err := db.Notify(ctx, n.repoID, NotificationSubjectIssue, n.subjectID, 0, map[int64]NotificationReason{1: NotificationReasonSubscribed})
+		require.NoError(t, err)
+	}
+	err := db.Notify(ctx, 1, NotificationSubjectIssue, 4, 0, map[int64]NotificationReason{2: NotificationReasonSubscribed})
+	require.NoError(t, err)
+
+	list, err := db.List(ctx, 1, ListNotificationsOptions{})
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	// The latest activity comes first
+	assert.Equal(t, int64(3), list[0].SubjectID)
+
+	err = db.MarkRead(ctx, 1, list[0].ID)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		opts  ListNotificationsOptions
+		count int64
+		want  []int64
+	}{
+		{
+			name:  "unread",
+			opts:  ListNotificationsOptions{},
+			count: 2,
+			want:  []int64{2, 1},
+		},
+		{
+			name:  "all",
+			opts:  ListNotificationsOptions{All: true},
+			count: 3,
+			want:  []int64{3, 2, 1},
+		},
+		{
+			name:  "by repository",
+			opts:  ListNotificationsOptions{RepoID: 1, All: true},
+			count: 2,
+			want:  []int64{2, 1},
+		},
+		{
+			name:  "paging",
+			opts:  ListNotificationsOptions{All: true, Page: 2, PageSize: 2},
+			count: 3,
+			want:  []int64{1},
+		},
+		{
+			name:  "since",
+			opts:  ListNotificationsOptions{All: true, Since: time.Now().Add(time.Hour)},
+			count: 0,
+			want:  []int64{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			count, err := db.Count(ctx, 1, test.opts)
+			require.NoError(t, err)
+			assert.Equal(t, test.count, count)
+
+			list, err := db.List(ctx, 1, test.opts)
+			require.NoError(t, err)
+			got := make([]int64, 0, len(list))
+			for _, n := range list {
+				got = append(got, n.SubjectID)
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func notificationsMarkAllRead(t *testing.T, db *notifications) {
+	ctx := context.Background()
+
+	for _, repoID := range []int64{1, 2} {
+		err := db.Notify(ctx, repoID, NotificationSubjectIssue, repoID, 0, map[int64]NotificationReason{1: NotificationReasonSubscribed})
+		require.NoError(t, err)
+	}
+
+	// Notifications updated after the time are not marked
+	err := db.MarkAllRead(ctx, 1, 0, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	count, err := db.Count(ctx, 1, ListNotificationsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	err = db.MarkAllRead(ctx, 1, 2, time.Now())
+	require.NoError(t, err)
+	list, err := db.List(ctx, 1, ListNotificationsOptions{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, int64(1), list[0].RepoID)
+
+	err = db.MarkAllRead(ctx, 1, 0, time.Now())
+	require.NoError(t, err)
+	count, err = db.Count(ctx, 1, ListNotificationsOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func notificationsMarkRead(t *testing.T, db *notifications) {
+	ctx := context.Background()
+
+	err := db.Notify(ctx, 1, NotificationSubjectIssue, 1, 0, map[int64]NotificationReason{1: NotificationReasonSubscribed})
+	require.NoError(t, err)
+	list, err := db.List(ctx, 1, ListNotificationsOptions{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	err = db.MarkRead(ctx, 2, list[0].ID)
+	wantErr := ErrNotificationNotExist{args: errutil.Args{"userID": int64(2), "id": list[0].ID}}
+	assert.Equal(t, wantErr, err)
+
+	err = db.MarkRead(ctx, 1, list[0].ID)
+	require.NoError(t, err)
+	got, err := db.GetByID(ctx, 1, list[0].ID)
+	require.NoError(t, err)
+	assert.True(t, got.IsRead)
+	assert.NotNil(t, got.LastReadAt)
+}
+
+func notificationsNotify(t *testing.T, db *notifications) {
+	ctx := context.Background()
+
+	err := db.Notify(ctx, 1, NotificationSubjectPull, 1, 0,
+		map[int64]NotificationReason{
+			1: NotificationReasonSubscribed,
+			2: NotificationReasonReviewRequested,
+		},
+	)
+	require.NoError(t, err)
+
+	list, err := db.List(ctx, 1, ListNotificationsOptions{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	err = db.MarkRead(ctx, 1, list[0].ID)
+	require.NoError(t, err)
+
+	// New activity marks the existing notification as unread again
+	err = db.Notify(ctx, 1, NotificationSubjectPull, 1, 10, map[int64]NotificationReason{1: NotificationReasonMention})
+	require.NoError(t, err)
+
+	list, err = db.List(ctx, 1, ListNotificationsOptions{All: true})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.False(t, list[0].IsRead)
+	assert.Equal(t, NotificationReasonMention, list[0].Reason)
+	assert.Equal(t, int64(10), list[0].LatestCommentID)
+
+	list, err = db.List(ctx, 2, ListNotificationsOptions{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, NotificationReasonReviewRequested, list[0].Reason)
+	assert.Zero(t, list[0].LatestCommentID)
+}
@@ -0,0 +1,771 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	gouuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/auth"
+	"gogs.io/gogs/internal/cryptoutil"
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/strutil"
+)
+
+const (
+	// OAuth2AuthorizationCodeLifetime is how long an authorization code is
+	// valid to be exchanged for tokens.
+	OAuth2AuthorizationCodeLifetime = 10 * time.Minute
+	// OAuth2AccessTokenLifetime is how long an access token is valid.
+	OAuth2AccessTokenLifetime = time.Hour
+	// OAuth2RefreshTokenLifetime is how long a refresh token is valid.
+	OAuth2RefreshTokenLifetime = 30 * 24 * time.Hour
+)
+
+// OAuth2Store is the persistent interface for OAuth2 applications, grants and
+// tokens issued to third-party applications.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type OAuth2Store interface {
+	// CreateApplication creates a new OAuth2 application owned by the given
+	// user. It returns ErrOAuth2ApplicationAlreadyExist when an application
+	// with same name already exists for the user. The raw client secret is only
+	// available in the returned application.
+	CreateApplication(ctx context.Context, userID int64, opts CreateOAuth2ApplicationOptions) (*OAuth2Application, error)
+	// CreateAuthorizationCode creates a new authorization code for the
+	// application on behalf of the user, and returns the raw code.
+	CreateAuthorizationCode(ctx context.Context, opts CreateOAuth2AuthorizationCodeOptions) (string, error)
+	// DeleteApplication deletes the OAuth2 application by given ID, along with
+	// all grants and tokens issued to the application.
+	//
+	// 🚨 SECURITY: The "userID" is required to prevent attacker deletes arbitrary
+	// application that belongs to another user.
+	DeleteApplication(ctx context.Context, userID, id int64) error
+	// DeleteByUser deletes all OAuth2 applications owned by the user, and all
+	// grants and tokens authorized by the user.
+	DeleteByUser(ctx context.Context, userID int64) error
+	// ExchangeAuthorizationCode consumes the authorization code of the
+	// application. It returns ErrOAuth2AuthorizationCodeNotExist when the code
+	// does not exist, has been consumed or has expired.
+	ExchangeAuthorizationCode(ctx context.Context, applicationID int64, code string) (*OAuth2AuthorizationCode, error)
+	// GetApplicationByClientID returns the OAuth2 application with given client
+	// ID. It returns ErrOAuth2ApplicationNotExist when not found.
+	GetApplicationByClientID(ctx context.Context, clientID string) (*OAuth2Application, error)
+	// GetApplicationByID returns the OAuth2 application with given ID owned by
+	// the user. It returns ErrOAuth2ApplicationNotExist when not found.
+	GetApplicationByID(ctx context.Context, userID, id int64) (*OAuth2Application, error)
+	// GetGrant returns the grant of the user to the application. It returns
+	// ErrOAuth2GrantNotExist when not found.
+	GetGrant(ctx context.Context, userID, applicationID int64) (*OAuth2Grant, error)
+	// GetTokenByAccessToken returns the token with given raw access token. It
+	// returns ErrOAuth2TokenNotExist when not found or the access token has
+	// expired.
+	GetTokenByAccessToken(ctx context.Context, accessToken string) (*OAuth2Token, error)
+	// IssueToken issues a new pair of access token and refresh token for the
+	// application on behalf of the user. The raw tokens are only available in
+	// the returned token.
+	IssueToken(ctx context.Context, applicationID, userID int64, scopes auth.Scopes) (*OAuth2Token, error)
+	// ListApplications returns all OAuth2 applications owned by the user.
+	ListApplications(ctx context.Context, userID int64) ([]*OAuth2Application, error)
+	// ListGrants returns all grants of the user along with their applications.
+	ListGrants(ctx context.Context, userID int64) ([]*OAuth2Grant, error)
+	// RefreshToken exchanges the refresh token of the application for a new
+	// pair of tokens, the old pair is revoked. The "scopes" must be a subset of
+	// originally granted scopes, an empty list means to keep them unchanged. It
+	// returns ErrOAuth2TokenNotExist when not found or the refresh token has
+	// expired, and ErrOAuth2ScopesExceeded when requested scopes are not
+	// granted.
+	RefreshToken(ctx context.Context, applicationID int64, refreshToken string, scopes auth.Scopes) (*OAuth2Token, error)
+	// RegenerateClientSecret generates a new client secret for the OAuth2
+	// application owned by the user, and returns the raw secret.
+	RegenerateClientSecret(ctx context.Context, userID, id int64) (string, error)
+	// RevokeGrant deletes the grant of the user to the application, along with
+	// all tokens issued to the application on behalf of the user.
+	RevokeGrant(ctx context.Context, userID, applicationID int64) error
+	// RevokeToken deletes the token of the application, given either the raw
+	// access token or refresh token. Unknown tokens are ignored.
+	RevokeToken(ctx context.Context, applicationID int64, token string) error
+	// SaveGrant records that the user has granted the scopes to the
+	// application, scopes granted previously are preserved.
+	SaveGrant(ctx context.Context, userID, applicationID int64, scopes auth.Scopes) error
+	// UpdateApplication updates the OAuth2 application owned by the user. It
+	// returns ErrOAuth2ApplicationAlreadyExist when another application with
+	// same name already exists for the user.
+	UpdateApplication(ctx context.Context, userID, id int64, opts UpdateOAuth2ApplicationOptions) error
+}
+
+var OAuth2 OAuth2Store
+
+// OAuth2Application is a third-party application registered by a user to
+// access APIs on behalf of other users.
+type OAuth2Application struct {
+	ID          int64  `gorm:"primaryKey"`
+	UserID      int64  `gorm:"index;not null"`
+	Name        string `gorm:"not null"`
+	HomepageURL string `gorm:"not null"`
+	// The newline-separated list of allowed redirect URIs.
+	RedirectURIs     string `gorm:"type:TEXT;not null"`
+	ClientID         string `gorm:"type:VARCHAR(36);unique;not null"`
+	ClientSecretHash string `gorm:"type:VARCHAR(64);not null"`
+	// Whether the application is able to keep the client secret confidential.
+	// Public applications (e.g. desktop and single-page applications) must use
+	// PKCE instead of the client secret.
+	Confidential bool      `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"not null"`
+	UpdatedAt    time.Time `gorm:"not null"`
+
+	// The raw client secret, only available right after created or regenerated.
+	ClientSecret string `gorm:"-" json:"-"`
+}
+
+// TableName implements the GORM table name interface.
+func (*OAuth2Application) TableName() string {
+	return "oauth2_application"
+}
+
+// RedirectURIList returns the list of allowed redirect URIs.
+func (app *OAuth2Application) RedirectURIList() []string {
+	return strings.Fields(app.RedirectURIs)
+}
+
+// IsValidRedirectURI returns true if the URI exactly matches one of allowed
+// redirect URIs.
+func (app *OAuth2Application) IsValidRedirectURI(uri string) bool {
+	for _, u := range app.RedirectURIList() {
+		if u == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateClientSecret returns true if the raw client secret matches.
+func (app *OAuth2Application) ValidateClientSecret(secret string) bool {
+	return secret != "" && cryptoutil.SHA256(secret) == app.ClientSecretHash
+}
+
+// OAuth2Grant records scopes a user has granted to an OAuth2 application.
+type OAuth2Grant struct {
+	ID            int64     `gorm:"primaryKey"`
+	UserID        int64     `gorm:"uniqueIndex:oauth2_grant_user_application_unique;not null"`
+	ApplicationID int64     `gorm:"uniqueIndex:oauth2_grant_user_application_unique;index;not null"`
+	Scopes        string    `gorm:"not null"`
+	CreatedAt     time.Time `gorm:"not null"`
+	UpdatedAt     time.Time `gorm:"not null"`
+
+	Application *OAuth2Application `gorm:"-" json:"-"`
+}
+
+// TableName implements the GORM table name interface.
+func (*OAuth2Grant) TableName() string {
+	return "oauth2_grant"
+}
+
+// ScopeList returns the list of granted scopes.
+func (g *OAuth2Grant) ScopeList() auth.Scopes {
+	scopes, _ := auth.ParseScopes(g.Scopes)
+	return scopes
+}
+
+// OAuth2AuthorizationCode is a short-lived code which an OAuth2 application
+// exchanges for tokens.
+type OAuth2AuthorizationCode struct {
+	ID            int64  `gorm:"primaryKey"`
+	ApplicationID int64  `gorm:"index;not null"`
+	UserID        int64  `gorm:"index;not null"`
+	CodeHash      string `gorm:"type:VARCHAR(64);unique;not null"`
+	RedirectURI   string `gorm:"type:TEXT;not null"`
+	Scopes        string `gorm:"not null"`
+	// The PKCE code challenge and its method, either "plain" or "S256".
+	CodeChallenge       string    `gorm:"type:VARCHAR(128);not null"`
+	CodeChallengeMethod string    `gorm:"type:VARCHAR(5);not null"`
+	ExpiresAt           time.Time `gorm:"not null"`
+	CreatedAt           time.Time `gorm:"not null"`
+}
+
+// TableName implements the GORM table name interface.
+func (*OAuth2AuthorizationCode) TableName() string {
+	return "oauth2_authorization_code"
+}
+
+// ScopeList returns the list of scopes to be granted.
+func (c *OAuth2AuthorizationCode) ScopeList() auth.Scopes {
+	scopes, _ := auth.ParseScopes(c.Scopes)
+	return scopes
+}
+
+// VerifyCodeChallenge returns true if the PKCE code verifier matches the code
+// challenge, or no code challenge was given when the code was created.
+func (c *OAuth2AuthorizationCode) VerifyCodeChallenge(verifier string) bool {
+	if c.CodeChallenge == "" {
+		return true
+	} else if verifier == "" {
+		return false
+	}
+
+	if c.CodeChallengeMethod == "S256" {
+		sum := sha256.Sum256([]byte(verifier))
+		return base64.RawURLEncoding.EncodeToString(sum[:]) == c.CodeChallenge
+	}
+	return verifier == c.CodeChallenge
+}
+
+// OAuth2Token is a pair of access token and refresh token issued to an OAuth2
+// application on behalf of a user.
+type OAuth2Token struct {
+	ID               int64     `gorm:"primaryKey"`
+	ApplicationID    int64     `gorm:"index;not null"`
+	UserID           int64     `gorm:"index;not null"`
+	AccessTokenHash  string    `gorm:"type:VARCHAR(64);unique;not null"`
+	RefreshTokenHash string    `gorm:"type:VARCHAR(64);unique;not null"`
+	Scopes           string    `gorm:"not null"`
+	AccessExpiresAt  time.Time `gorm:"not null"`
+	RefreshExpiresAt time.Time `gorm:"not null"`
+	CreatedAt        time.Time `gorm:"not null"`
+
+	// The raw tokens, only available right after issued.
+	AccessToken  string `gorm:"-" json:"-"`
+	RefreshToken string `gorm:"-" json:"-"`
+}
+
+// TableName implements the GORM table name interface.
+func (*OAuth2Token) TableName() string {
+	return "oauth2_token"
+}
+
+// ScopeList returns the list of scopes granted to the token.
+func (t *OAuth2Token) ScopeList() auth.Scopes {
+	scopes, _ := auth.ParseScopes(t.Scopes)
+	return scopes
+}
+
+var _ OAuth2Store = (*oauth2)(nil)
+
+type oauth2 struct {
+	*gorm.DB
+}
+
+// NewOAuth2Store returns a persistent interface for OAuth2 applications, grants
+// and tokens with given database connection.
+func NewOAuth2Store(db *gorm.DB) OAuth2Store {
+	return &oauth2{DB: db}
+}
+
+type CreateOAuth2ApplicationOptions struct {
+	Name         string
+	HomepageURL  string
+	RedirectURIs []string
+	Confidential bool
+}
+
+type ErrOAuth2ApplicationAlreadyExist struct {
+	args errutil.Args
+}
+
+func IsErrOAuth2ApplicationAlreadyExist(err error) bool {
+	_, ok := err.(ErrOAuth2ApplicationAlreadyExist)
+	return ok
+}
+
+func (err ErrOAuth2ApplicationAlreadyExist) Error() string {
+	return fmt.Sprintf("OAuth2 application already exists: %v", err.args)
+}
+
+func (db *oauth2) nameTaken(tx *gorm.DB, userID, excludeID int64, name string) (bool, error) {
+	err := tx.Where("user_id = ? AND id != ? AND name = ?", userID, excludeID, name).First(new(OAuth2Application)).Error
+	if err == nil {
+		return true, nil
+	} else if err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+	return false, nil
+}
+
+func (db *oauth2) CreateApplication(ctx context.Context, userID int64, opts CreateOAuth2ApplicationOptions) (*OAuth2Application, error) {
+	taken, err := db.nameTaken(db.WithContext(ctx), userID, 0, opts.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "check name")
+	} else if taken {
+		return nil, ErrOAuth2ApplicationAlreadyExist{args: errutil.Args{"userID": userID, "name": opts.Name}}
+	}
+
+	secret, err := strutil.RandomChars(40)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate client secret")
+	}
+
+	app := &OAuth2Application{
+		UserID:           userID,
+		Name:             opts.Name,
+		HomepageURL:      opts.HomepageURL,
+		RedirectURIs:     strings.Join(opts.RedirectURIs, "\n"),
+		ClientID:         gouuid.NewV4().String(),
+		ClientSecretHash: cryptoutil.SHA256(secret),
+		Confidential:     opts.Confidential,
+	}
+	if err = db.WithContext(ctx).Create(app).Error; err != nil {
+		return nil, err
+	}
+
+	app.ClientSecret = secret
+	return app, nil
+}
+
+type CreateOAuth2AuthorizationCodeOptions struct {
+	ApplicationID       int64
+	UserID              int64
+	RedirectURI         string
+	Scopes              auth.Scopes
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+func (db *oauth2) CreateAuthorizationCode(ctx context.Context, opts CreateOAuth2AuthorizationCodeOptions) (string, error) {
+	code, err := strutil.RandomChars(40)
+	if err != nil {
+		return "", errors.Wrap(err, "generate code")
+	}
+
+	now := db.NowFunc()
+	err = db.WithContext(ctx).Create(
+		&OAuth2AuthorizationCode{
+			ApplicationID:       opts.ApplicationID,
+			UserID:              opts.UserID,
+			CodeHash:            cryptoutil.SHA256(code),
+			RedirectURI:         opts.RedirectURI,
+			Scopes:              opts.Scopes.String(),
+			CodeChallenge:       opts.CodeChallenge,
+			CodeChallengeMethod: opts.CodeChallengeMethod,
+			ExpiresAt:           now.Add(OAuth2AuthorizationCodeLifetime),
+			CreatedAt:           now,
+		},
+	).Error
+	if err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+func (db *oauth2) DeleteApplication(ctx context.Context, userID, id int64) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(new(OAuth2Application))
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "delete application")
+		} else if result.RowsAffected == 0 {
+			return nil
+		}
+
+		for _, table := range []interface{}{new(OAuth2Grant), new(OAuth2AuthorizationCode), new(OAuth2Token)} {
+			err := tx.Where("application_id = ?", id).Delete(table).Error
+			if err != nil {
+				return errors.Wrapf(err, "delete %T", table)
+			}
+		}
+		return nil
+	})
+}
+
+func (db *oauth2) DeleteByUser(ctx context.Context, userID int64) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var appIDs []int64
+		err := tx.Model(new(OAuth2Application)).Where("user_id = ?", userID).Pluck("id", &appIDs).Error
+		if err != nil {
+			return errors.Wrap(err, "list application IDs")
+		}
+
+		for _, table := range []interface{}{new(OAuth2Grant), new(OAuth2AuthorizationCode), new(OAuth2Token)} {
+			q := tx.Where("user_id = ?", userID)
+			if len(appIDs) > 0 {
+				q = q.Or("application_id IN (?)", appIDs)
+			}
+			err = q.Delete(table).Error
+			if err != nil {
+				return errors.Wrapf(err, "delete %T", table)
+			}
+		}
+		return tx.Where("user_id = ?", userID).Delete(new(OAuth2Application)).Error
+	})
+}
+
+var _ errutil.NotFound = (*ErrOAuth2AuthorizationCodeNotExist)(nil)
+
+type ErrOAuth2AuthorizationCodeNotExist struct {
+	args errutil.Args
+}
+
+func IsErrOAuth2AuthorizationCodeNotExist(err error) bool {
+	_, ok := err.(ErrOAuth2AuthorizationCodeNotExist)
+	return ok
+}
+
+func (err ErrOAuth2AuthorizationCodeNotExist) Error() string {
+	return fmt.Sprintf("OAuth2 authorization code does not exist: %v", err.args)
+}
+
+func (ErrOAuth2AuthorizationCodeNotExist) NotFound() bool {
+	return true
+}
+
+func (db *oauth2) ExchangeAuthorizationCode(ctx context.Context, applicationID int64, code string) (*OAuth2AuthorizationCode, error) {
+	authCode := new(OAuth2AuthorizationCode)
+	err := db.WithContext(ctx).Where("application_id = ? AND code_hash = ?", applicationID, cryptoutil.SHA256(code)).First(authCode).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrOAuth2AuthorizationCodeNotExist{args: errutil.Args{"applicationID": applicationID}}
+		}
+		return nil, err
+	}
+
+	// Authorization codes are single use, whether it has expired or not.
+	result := db.WithContext(ctx).Where("id = ?", authCode.ID).Delete(new(OAuth2AuthorizationCode))
+	if result.Error != nil {
+		return nil, errors.Wrap(result.Error, "delete")
+	} else if result.RowsAffected == 0 {
+		// Consumed by a concurrent request
+		return nil, ErrOAuth2AuthorizationCodeNotExist{args: errutil.Args{"applicationID": applicationID}}
+	}
+
+	if !authCode.ExpiresAt.After(db.NowFunc()) {
+		return nil, ErrOAuth2AuthorizationCodeNotExist{args: errutil.Args{"applicationID": applicationID}}
+	}
+	return authCode, nil
+}
+
+var _ errutil.NotFound = (*ErrOAuth2ApplicationNotExist)(nil)
+
+type ErrOAuth2ApplicationNotExist struct {
+	args errutil.Args
+}
+
+func IsErrOAuth2ApplicationNotExist(err error) bool {
+	_, ok := err.(ErrOAuth2ApplicationNotExist)
+	return ok
+}
+
+func (err ErrOAuth2ApplicationNotExist) Error() string {
+	return fmt.Sprintf("OAuth2 application does not exist: %v", err.args)
+}
+
+func (ErrOAuth2ApplicationNotExist) NotFound() bool {
+	return true
+}
+
+func (db *oauth2) GetApplicationByClientID(ctx context.Context, clientID string) (*OAuth2Application, error) {
+	app := new(OAuth2Application)
+	err := db.WithContext(ctx).Where("client_id = ?", clientID).First(app).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrOAuth2ApplicationNotExist{args: errutil.Args{"clientID": clientID}}
+		}
+		return nil, err
+	}
+	return app, nil
+}
+
+func (db *oauth2) GetApplicationByID(ctx context.Context, userID, id int64) (*OAuth2Application, error) {
+	app := new(OAuth2Application)
+	err := db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(app).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrOAuth2ApplicationNotExist{args: errutil.Args{"userID": userID, "id": id}}
+		}
+		return nil, err
+	}
+	return app, nil
+}
+
+var _ errutil.NotFound = (*ErrOAuth2GrantNotExist)(nil)
+
+type ErrOAuth2GrantNotExist struct {
+	args errutil.Args
+}
+
+func IsErrOAuth2GrantNotExist(err error) bool {
+	_, ok := err.(ErrOAuth2GrantNotExist)
+	return ok
+}
+
+func (err ErrOAuth2GrantNotExist) Error() string {
+	return fmt.Sprintf("OAuth2 grant does not exist: %v", err.args)
+}
+
+func (ErrOAuth2GrantNotExist) NotFound() bool {
+	return true
+}
+
+func (db *oauth2) GetGrant(ctx context.Context, userID, applicationID int64) (*OAuth2Grant, error) {
+	grant := new(OAuth2Grant)
+	err := db.WithContext(ctx).Where("user_id = ? AND application_id = ?", userID, applicationID).First(grant).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrOAuth2GrantNotExist{args: errutil.Args{"userID": userID, "applicationID": applicationID}}
+		}
+		return nil, err
+	}
+	return grant, nil
+}
+
+var _ errutil.NotFound = (*ErrOAuth2TokenNotExist)(nil)
+
+type ErrOAuth2TokenNotExist struct {
+	args errutil.Args
+}
+
+func IsErrOAuth2TokenNotExist(err error) bool {
+	_, ok := err.(ErrOAuth2TokenNotExist)
+	return ok
+}
+
+func (err ErrOAuth2TokenNotExist) Error() string {
+	return fmt.Sprintf("OAuth2 token does not exist: %v", err.args)
+}
+
+func (ErrOAuth2TokenNotExist) NotFound() bool {
+	return true
+}
+
+func (db *oauth2) GetTokenByAccessToken(ctx context.Context, accessToken string) (*OAuth2Token, error) {
+	// 🚨 SECURITY: Prevent timing attacks by comparing hashes instead of the raw
+	// tokens.
+	token := new(OAuth2Token)
+	err := db.WithContext(ctx).
+		Where("access_token_hash = ? AND access_expires_at > ?", cryptoutil.SHA256(accessToken), db.NowFunc()).
+		First(token).
+		Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrOAuth2TokenNotExist{args: errutil.Args{"accessToken": "***"}}
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+func (db *oauth2) issueToken(tx *gorm.DB, applicationID, userID int64, scopes auth.Scopes) (*OAuth2Token, error) {
+	accessToken, err := strutil.RandomChars(40)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate access token")
+	}
+	refreshToken, err := strutil.RandomChars(40)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate refresh token")
+	}
+
+	now := tx.NowFunc()
+	token := &OAuth2Token{
+		ApplicationID:    applicationID,
+		UserID:           userID,
+		AccessTokenHash:  cryptoutil.SHA256(accessToken),
+		RefreshTokenHash: cryptoutil.SHA256(refreshToken),
+		Scopes:           scopes.String(),
+		AccessExpiresAt:  now.Add(OAuth2AccessTokenLifetime),
+		RefreshExpiresAt: now.Add(OAuth2RefreshTokenLifetime),
+		CreatedAt:        now,
+	}
+	if err = tx.Create(token).Error; err != nil {
+		return nil, err
+	}
+
+	token.AccessToken = accessToken
+	token.RefreshToken = refreshToken
+	return token, nil
+}
+
+func (db *oauth2) IssueToken(ctx context.Context, applicationID, userID int64, scopes auth.Scopes) (*OAuth2Token, error) {
+	return db.issueToken(db.WithContext(ctx), applicationID, userID, scopes)
+}
+
+func (db *oauth2) ListApplications(ctx context.Context, userID int64) ([]*OAuth2Application, error) {
+	var apps []*OAuth2Application
+	return apps, db.WithContext(ctx).Where("user_id = ?", userID).Order("id ASC").Find(&apps).Error
+}
+
+func (db *oauth2) ListGrants(ctx context.Context, userID int64) ([]*OAuth2Grant, error) {
+	var grants []*OAuth2Grant
+	err := db.WithContext(ctx).Where("user_id = ?", userID).Order("id ASC").Find(&grants).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "list grants")
+	} else if len(grants) == 0 {
+		return grants, nil
+	}
+
+	appIDs := make([]int64, len(grants))
+	for i := range grants {
+		appIDs[i] = grants[i].ApplicationID
+	}
+	var apps []*OAuth2Application
+	err = db.WithContext(ctx).Where("id IN (?)", appIDs).Find(&apps).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "list applications")
+	}
+	appsByID := make(map[int64]*OAuth2Application, len(apps))
+	for _, app := range apps {
+		appsByID[app.ID] = app
+	}
+
+	// Skip grants whose applications have gone.
+	result := grants[:0]
+	for _, grant := range grants {
+		grant.Application = appsByID[grant.ApplicationID]
+		if grant.Application != nil {
+			result = append(result, grant)
+		}
+	}
+	return result, nil
+}
+
+type ErrOAuth2ScopesExceeded struct {
+	args errutil.Args
+}
+
+func IsErrOAuth2ScopesExceeded(err error) bool {
+	_, ok := err.(ErrOAuth2ScopesExceeded)
+	return ok
+}
+
+func (err ErrOAuth2ScopesExceeded) Error() string {
+	return fmt.Sprintf("requested scopes exceed the granted scopes: %v", err.args)
+}
+
+func (db *oauth2) RefreshToken(ctx context.Context, applicationID int64, refreshToken string, scopes auth.Scopes) (*OAuth2Token, error) {
+	var token *OAuth2Token
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		old := new(OAuth2Token)
+		err := tx.
+			Where("application_id = ? AND refresh_token_hash = ? AND refresh_expires_at > ?", applicationID, cryptoutil.SHA256(refreshToken), tx.NowFunc()).
+			First(old).
+			Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrOAuth2TokenNotExist{args: errutil.Args{"applicationID": applicationID, "refreshToken": "***"}}
+			}
+			return err
+		}
+
+		granted := old.ScopeList()
+		if len(scopes) == 0 {
+			scopes = granted
+		} else if !granted.HasAll(scopes) {
+			return ErrOAuth2ScopesExceeded{args: errutil.Args{"requested": scopes.String(), "granted": granted.String()}}
+		}
+
+		// Refresh tokens are single use, the old pair is revoked.
+		result := tx.Where("id = ?", old.ID).Delete(new(OAuth2Token))
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "delete old token")
+		} else if result.RowsAffected == 0 {
+			// Refreshed by a concurrent request
+			return ErrOAuth2TokenNotExist{args: errutil.Args{"applicationID": applicationID, "refreshToken": "***"}}
+		}
+
+		token, err = db.issueToken(tx, applicationID, old.UserID, scopes)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (db *oauth2) RegenerateClientSecret(ctx context.Context, userID, id int64) (string, error) {
+	secret, err := strutil.RandomChars(40)
+	if err != nil {
+		return "", errors.Wrap(err, "generate client secret")
+	}
+
+	result := db.WithContext(ctx).
+		Model(new(OAuth2Application)).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(map[string]interface{}{
+			"client_secret_hash": cryptoutil.SHA256(secret),
+			"updated_at":         db.NowFunc(),
+		})
+	if result.Error != nil {
+		return "", result.Error
+	} else if result.RowsAffected == 0 {
+		return "", ErrOAuth2ApplicationNotExist{args: errutil.Args{"userID": userID, "id": id}}
+	}
+	return secret, nil
+}
+
+func (db *oauth2) RevokeGrant(ctx context.Context, userID, applicationID int64) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, table := range []interface{}{new(OAuth2Grant), new(OAuth2AuthorizationCode), new(OAuth2Token)} {
+			err := tx.Where("user_id = ? AND application_id = ?", userID, applicationID).Delete(table).Error
+			if err != nil {
+				return errors.Wrapf(err, "delete %T", table)
+			}
+		}
+		return nil
+	})
+}
+
+func (db *oauth2) RevokeToken(ctx context.Context, applicationID int64, token string) error {
+	hash := cryptoutil.SHA256(token)
+	return db.WithContext(ctx).
+		Where("application_id = ? AND (access_token_hash = ? OR refresh_token_hash = ?)", applicationID, hash, hash).
+		Delete(new(OAuth2Token)).
+		Error
+}
+
+func (db *oauth2) SaveGrant(ctx context.Context, userID, applicationID int64, scopes auth.Scopes) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		grant := new(OAuth2Grant)
+		err := tx.Where("user_id = ? AND application_id = ?", userID, applicationID).First(grant).Error
+		if err == gorm.ErrRecordNotFound {
+			return tx.Create(
+				&OAuth2Grant{
+					UserID:        userID,
+					ApplicationID: applicationID,
+					Scopes:        scopes.String(),
+				},
+			).Error
+		} else if err != nil {
+			return errors.Wrap(err, "get grant")
+		}
+
+		merged, _ := auth.ParseScopes(grant.Scopes + " " + scopes.String())
+		return tx.Model(grant).Updates(map[string]interface{}{
+			"scopes":     merged.String(),
+			"updated_at": tx.NowFunc(),
+		}).Error
+	})
+}
+
+type UpdateOAuth2ApplicationOptions struct {
+	Name         string
+	HomepageURL  string
+	RedirectURIs []string
+	Confidential bool
+}
+
+func (db *oauth2) UpdateApplication(ctx context.Context, userID, id int64, opts UpdateOAuth2ApplicationOptions) error {
+	taken, err := db.nameTaken(db.WithContext(ctx), userID, id, opts.Name)
+	if err != nil {
+		return errors.Wrap(err, "check name")
+	} else if taken {
+		return ErrOAuth2ApplicationAlreadyExist{args: errutil.Args{"userID": userID, "name": opts.Name}}
+	}
+
+	return db.WithContext(ctx).
+		Model(new(OAuth2Application)).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(map[string]interface{}{
+			"name":          opts.Name,
+			"homepage_url":  opts.HomepageURL,
+			"redirect_uris": strings.Join(opts.RedirectURIs, "\n"),
+			"confidential":  opts.Confidential,
+			"updated_at":    db.NowFunc(),
+		}).
+		Error
+}
@@ -0,0 +1,428 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/auth"
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestOAuth2AuthorizationCode_VerifyCodeChallenge(t *testing.T) {
+	sum := sha256.Sum256([]byte("verifier"))
+	s256 := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	tests := []struct {
+		name     string
+		code     *OAuth2AuthorizationCode
+		verifier string
+		want     bool
+	}{
+		{
+			name: "no code challenge",
+			code: &OAuth2AuthorizationCode{},
+			want: true,
+		},
+		{
+			name:     "plain",
+			code:     &OAuth2AuthorizationCode{CodeChallenge: "verifier", CodeChallengeMethod: "plain"},
+			verifier: "verifier",
+			want:     true,
+		},
+		{
+			name:     "S256",
+			code:     &OAuth2AuthorizationCode{CodeChallenge: s256, CodeChallengeMethod: "S256"},
+			verifier: "verifier",
+			want:     true,
+		},
+		{
+			name:     "S256 mismatched",
+			code:     &OAuth2AuthorizationCode{CodeChallenge: s256, CodeChallengeMethod: "S256"},
+			verifier: "bad-verifier",
+			want:     false,
+		},
+		{
+			name: "missing verifier",
+			code: &OAuth2AuthorizationCode{CodeChallenge: s256, CodeChallengeMethod: "S256"},
+			want: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.code.VerifyCodeChallenge(test.verifier))
+		})
+	}
+}
+
+func TestOAuth2(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(OAuth2Application), new(OAuth2AuthorizationCode), new(OAuth2Grant), new(OAuth2Token)}
+	db := &oauth2{
+		DB: dbtest.NewDB(t, "oauth2", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *oauth2)
+	}{
+		{"CreateApplication", oauth2CreateApplication},
+		{"DeleteApplication", oauth2DeleteApplication},
+		{"DeleteByUser", oauth2DeleteByUser},
+		{"ExchangeAuthorizationCode", oauth2ExchangeAuthorizationCode},
+		{"GetTokenByAccessToken", oauth2GetTokenByAccessToken},
+		{"ListGrants", oauth2ListGrants},
+		{"RefreshToken", oauth2RefreshToken},
+		{"RegenerateClientSecret", oauth2RegenerateClientSecret},
+		{"RevokeGrant", oauth2RevokeGrant},
+		{"RevokeToken", oauth2RevokeToken},
+		{"SaveGrant", oauth2SaveGrant},
+		{"UpdateApplication", oauth2UpdateApplication},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func createTestOAuth2Application(t *testing.T, db *oauth2, userID int64, name string) *OAuth2Application {
+	app, err := db.CreateApplication(context.Background(), userID,
+		CreateOAuth2ApplicationOptions{
+			Name:         name,
+			HomepageURL:  "https://example.com",
+			RedirectURIs: []string{"https://example.com/callback", "http://localhost:8080/callback"},
+			Confidential: true,
+		},
+	)
+	require.NoError(t, err)
+	return app
+}
+
+func oauth2CreateApplication(t *testing.T, db *oauth2) {
+	ctx := context.Background()
+
+	app := createTestOAuth2Application(t, db, 1, "CI dashboard")
+	assert.NotEmpty(t, app.ClientID)
+	assert.Len(t, app.ClientSecret, 40)
+	assert.True(t, app.ValidateClientSecret(app.ClientSecret))
+	assert.False(t, app.ValidateClientSecret(""))
+	assert.True(t, app.IsValidRedirectURI("http://localhost:8080/callback"))
+	assert.False(t, app.IsValidRedirectURI("http://localhost:8080/callback/evil"))
+
+	got, err := db.GetApplicationByClientID(ctx, app.ClientID)
+	require.NoError(t, err)
+	assert.Equal(t, app.ID, got.ID)
+	assert.Empty(t, got.ClientSecret)
+
+	_, err = db.CreateApplication(ctx, 1, CreateOAuth2ApplicationOptions{Name: "CI dashboard"})
+	wantErr := ErrOAuth2ApplicationAlreadyExist{args: errutil.Args{"userID": int64(1), "name": "CI dashboard"}}
+	assert.Equal(t, wantErr, err)
+
+	// Same name of a different user is fine
+	_ = createTestOAuth2Application(t, db, 2, "CI dashboard")
+}
+
+func oauth2DeleteApplication(t *testing.T, db *oauth2) {
+	ctx := context.Background()
+
+	app := createTestOAuth2Application(t, db, 1, "CI dashboard")
+	err := db.SaveGrant(ctx, 2, app.ID, auth.Scopes{auth.ScopeRepoRead})
+	require.NoError(t, err)
+	token, err := db.IssueToken(ctx, app.ID, 2, auth.Scopes{auth.ScopeRepoRead})
+	require.NoError(t, err)
+
+	// Deleting with a different user should be noop
+	err = db.DeleteApplication(ctx, 2, app.ID)
+	require.NoError(t, err)
+	_, err = db.GetApplicationByID(ctx, 1, app.ID)
+	require.NoError(t, err)
+
+	err = db.DeleteApplication(ctx, 1, app.ID)
+	require.NoError(t, err)
+
+	_, err = db.GetApplicationByID(ctx, 1, app.ID)
+	wantErr := ErrOAuth2ApplicationNotExist{args: errutil.Args{"userID": int64(1), "id": app.ID}}
+	assert.Equal(t, wantErr, err)
+
+	_, err = db.GetGrant(ctx, 2, app.ID)
+	assert.True(t, IsErrOAuth2GrantNotExist(err))
+	_, err = db.GetTokenByAccessToken(ctx, token.AccessToken)
+	assert.True(t, IsErrOAuth2TokenNotExist(err))
+}
+
+func oauth2DeleteByUser(t *testing.T, db *oauth2) {
+	ctx := context.Background()
+
+	app1 := createTestOAuth2Application(t, db, 1, "CI dashboard")
+	app2 := createTestOAuth2Application(t, db, 2, "Review bot")
+
+	// Tokens of other users to the application owned by the user
+	token1, err := db.IssueToken(ctx, app1.ID, 3, auth.Scopes{auth.ScopeUser})
+	require.NoError(t, err)
+	// Tokens of the user to the application owned by others
+	token2, err := db.IssueToken(ctx, app2.ID, 1, auth.Scopes{auth.ScopeUser})
+	require.NoError(t, err)
+	// Tokens irrelevant to the user
+	token3, err := db.IssueToken(ctx, app2.ID, 3, auth.Scopes{auth.ScopeUser})
+	require.NoError(t, err)
+
+	err = db.DeleteByUser(ctx, 1)
+	require.NoError(t, err)
+
+	apps, err := db.ListApplications(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, apps)
+
+	_, err = db.GetTokenByAccessToken(ctx, token1.AccessToken)
+	assert.True(t, IsErrOAuth2TokenNotExist(err))
+	_, err = db.GetTokenByAccessToken(ctx, token2.AccessToken)
+	assert.True(t, IsErrOAuth2TokenNotExist(err))
+	_, err = db.GetTokenByAccessToken(ctx, token3.AccessToken)
+	assert.NoError(t, err)
+}
+
+func oauth2ExchangeAuthorizationCode(t *testing.T, db *oauth2) {
+	ctx := context.Background()
+
+	app := createTestOAuth2Application(t, db, 1, "CI dashboard")
+	code, err := db.CreateAuthorizationCode(ctx,
+		CreateOAuth2AuthorizationCodeOptions{
+			ApplicationID:       app.ID,
+			UserID:              2,
+			RedirectURI:         "https://example.com/callback",
+			Scopes:              auth.Scopes{auth.ScopeRepoRead, auth.ScopeUser},
+			CodeChallenge:       "challenge",
+			CodeChallengeMethod: "plain",
+		},
+	)
+	require.NoError(t, err)
+
+	// Codes are bound to the application
+	_, err = db.ExchangeAuthorizationCode(ctx, app.ID+1, code)
+	assert.True(t, IsErrOAuth2AuthorizationCodeNotExist(err))
+
+	got, err := db.ExchangeAuthorizationCode(ctx, app.ID, code)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), got.UserID)
+	assert.Equal(t, "https://example.com/callback", got.RedirectURI)
+	assert.Equal(t, auth.Scopes{auth.ScopeRepoRead, auth.ScopeUser}, got.ScopeList())
+	assert.True(t, got.VerifyCodeChallenge("challenge"))
+
+	// Codes are single use
+	_, err = db.ExchangeAuthorizationCode(ctx, app.ID, code)
+	assert.True(t, IsErrOAuth2AuthorizationCodeNotExist(err))
+
+	// Expired codes are rejected
+	code, err = db.CreateAuthorizationCode(ctx, CreateOAuth2AuthorizationCodeOptions{ApplicationID: app.ID, UserID: 2})
+	require.NoError(t, err)
+	err = db.Model(new(OAuth2AuthorizationCode)).Where("application_id = ?", app.ID).
+		Update("expires_at", db.NowFunc().Add(-time.Minute)).Error
+	require.NoError(t, err)
+	_, err = db.ExchangeAuthorizationCode(ctx, app.ID, code)
+	assert.True(t, IsErrOAuth2AuthorizationCodeNotExist(err))
+}
+
+func oauth2GetTokenByAccessToken(t *testing.T, db *oauth2) {
+	ctx := context.Background()
+
+	token, err := db.IssueToken(ctx, 1, 2, auth.Scopes{auth.ScopeRepoWrite})
+	require.NoError(t, err)
+	assert.Len(t, token.AccessToken, 40)
+	assert.Len(t, token.RefreshToken, 40)
+
+	got, err := db.GetTokenByAccessToken(ctx, token.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), got.UserID)
+	assert.Equal(t, auth.Scopes{auth.ScopeRepoWrite}, got.ScopeList())
+
+	// Refresh tokens are not access tokens
+	_, err = db.GetTokenByAccessToken(ctx, token.RefreshToken)
+	assert.True(t, IsErrOAuth2TokenNotExist(err))
+
+	// Expired access tokens are rejected
+	err = db.Model(new(OAuth2Token)).Where("id = ?", token.ID).
+		Update("access_expires_at", db.NowFunc().Add(-time.Minute)).Error
+	require.NoError(t, err)
+	_, err = db.GetTokenByAccessToken(ctx, token.AccessToken)
+	assert.True(t, IsErrOAuth2TokenNotExist(err))
+}
+
+func oauth2ListGrants(t *testing.T, db *oauth2) {
+	ctx := context.Background()
+
+	app1 := createTestOAuth2Application(t, db, 1, "CI dashboard")
+	app2 := createTestOAuth2Application(t, db, 1, "Review bot")
+	for _, appID := range []int64{app1.ID, app2.ID, 404} {
+		err := db.SaveGrant(ctx, 2, appID, auth.Scopes{auth.ScopeUser})
+		require.NoError(t, err)
+	}
+
+	grants, err := db.ListGrants(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+	assert.Equal(t, "CI dashboard", grants[0].Application.Name)
+	assert.Equal(t, "Review bot", grants[1].Application.Name)
+}
+
+func oauth2RefreshToken(t *testing.T, db *oauth2) {
+	ctx := context.Background()
+
+	token, err := db.IssueToken(ctx, 1, 2, auth.Scopes{auth.ScopeRepoWrite, auth.ScopeUser})
+	require.NoError(t, err)
+
+	// Refresh tokens are bound to the application
+	_, err = db.RefreshToken(ctx, 2, token.RefreshToken, nil)
+	assert.True(t, IsErrOAuth2TokenNotExist(err))
+
+	// Scopes cannot be escalated
+	_, err = db.RefreshToken(ctx, 1, token.RefreshToken, auth.Scopes{auth.ScopeAdminSite})
+	assert.True(t, IsErrOAuth2ScopesExceeded(err))
+
+	refreshed, err := db.RefreshToken(ctx, 1, token.RefreshToken, auth.Scopes{auth.ScopeRepoRead})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), refreshed.UserID)
+	assert.Equal(t, auth.Scopes{auth.ScopeRepoRead}, refreshed.ScopeList())
+	assert.NotEqual(t, token.AccessToken, refreshed.AccessToken)
+
+	// The old pair is revoked
+	_, err = db.GetTokenByAccessToken(ctx, token.AccessToken)
+	assert.True(t, IsErrOAuth2TokenNotExist(err))
+	_, err = db.RefreshToken(ctx, 1, token.RefreshToken, nil)
+	assert.True(t, IsErrOAuth2TokenNotExist(err))
+
+	refreshed, err = db.RefreshToken(ctx, 1, refreshed.RefreshToken, nil)
+	require.NoError(t, err)
+	assert.Equal(t, auth.Scopes{auth.ScopeRepoRead}, refreshed.ScopeList())
+}
+
+func oauth2RegenerateClientSecret(t *testing.T, db *oauth2) {
+	ctx := context.Background()
+
+	app := createTestOAuth2Application(t, db, 1, "CI dashboard")
+
+	_, err := db.RegenerateClientSecret(ctx, 2, app.ID)
+	wantErr := ErrOAuth2ApplicationNotExist{args: errutil.Args{"userID": int64(2), "id": app.ID}}
+	assert.Equal(t, wantErr, err)
+
+	secret, err := db.RegenerateClientSecret(ctx, 1, app.ID)
+	require.NoError(t, err)
+
+	got, err := db.GetApplicationByID(ctx, 1, app.ID)
+	require.NoError(t, err)
+	assert.True(t, got.ValidateClientSecret(secret))
+	assert.False(t, got.ValidateClientSecret(app.ClientSecret))
+}
+
+func oauth2RevokeGrant(t *testing.T, db *oauth2) {
+	ctx := context.Background()
+
+	err := db.SaveGrant(ctx, 2, 1, auth.Scopes{auth.ScopeUser})
+	require.NoError(t, err)
+	token, err := db.IssueToken(ctx, 1, 2, auth.Scopes{auth.ScopeUser})
+	require.NoError(t, err)
+	other, err := db.IssueToken(ctx, 1, 3, auth.Scopes{auth.ScopeUser})
+	require.NoError(t, err)
+
+	err = db.RevokeGrant(ctx, 2, 1)
+	require.NoError(t, err)
+
+	_, err = db.GetGrant(ctx, 2, 1)
+	wantErr := ErrOAuth2GrantNotExist{args: errutil.Args{"userID": int64(2), "applicationID": int64(1)}}
+	assert.Equal(t, wantErr, err)
+	_, err = db.GetTokenByAccessToken(ctx, token.AccessToken)
+	assert.True(t, IsErrOAuth2TokenNotExist(err))
+
+	// Tokens of other users are not affected
+	_, err = db.GetTokenByAccessToken(ctx, other.AccessToken)
+	assert.NoError(t, err)
+}
+
+func oauth2RevokeToken(t *testing.T, db *oauth2) {
+	ctx := context.Background()
+
+	token1, err := db.IssueToken(ctx, 1, 2, auth.Scopes{auth.ScopeUser})
+	require.NoError(t, err)
+	token2, err := db.IssueToken(ctx, 1, 2, auth.Scopes{auth.ScopeUser})
+	require.NoError(t, err)
+
+	// Tokens of other applications cannot be revoked
+	err = db.RevokeToken(ctx, 2, token1.AccessToken)
+	require.NoError(t, err)
+	_, err = db.GetTokenByAccessToken(ctx, token1.AccessToken)
+	require.NoError(t, err)
+
+	err = db.RevokeToken(ctx, 1, token1.AccessToken)
+	require.NoError(t, err)
+	_, err = db.GetTokenByAccessToken(ctx, token1.AccessToken)
+	assert.True(t, IsErrOAuth2TokenNotExist(err))
+
+	err = db.RevokeToken(ctx, 1, token2.RefreshToken)
+	require.NoError(t, err)
+	_, err = db.GetTokenByAccessToken(ctx, token2.AccessToken)
+	assert.True(t, IsErrOAuth2TokenNotExist(err))
+
+	// Unknown tokens are ignored
+	err = db.RevokeToken(ctx, 1, "404")
+	require.NoError(t, err)
+}
+
+func oauth2SaveGrant(t *testing.T, db *oauth2) {
+	ctx := context.Background()
+
+	err := db.SaveGrant(ctx, 2, 1, auth.Scopes{auth.ScopeUser})
+	require.NoError(t, err)
+	err = db.SaveGrant(ctx, 2, 1, auth.Scopes{auth.ScopeRepoRead})
+	require.NoError(t, err)
+
+	grant, err := db.GetGrant(ctx, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, auth.Scopes{auth.ScopeRepoRead, auth.ScopeUser}, grant.ScopeList())
+}
+
+func oauth2UpdateApplication(t *testing.T, db *oauth2) {
+	ctx := context.Background()
+
+	app := createTestOAuth2Application(t, db, 1, "CI dashboard")
+	_ = createTestOAuth2Application(t, db, 1, "Review bot")
+
+	opts := UpdateOAuth2ApplicationOptions{
+		Name:         "Review bot",
+		HomepageURL:  "https://bot.example.com",
+		RedirectURIs: []string{"https://bot.example.com/callback"},
+	}
+	err := db.UpdateApplication(ctx, 1, app.ID, opts)
+	wantErr := ErrOAuth2ApplicationAlreadyExist{args: errutil.Args{"userID": int64(1), "name": "Review bot"}}
+	assert.Equal(t, wantErr, err)
+
+	opts.Name = "Code review bot"
+	err = db.UpdateApplication(ctx, 1, app.ID, opts)
+	require.NoError(t, err)
+
+	got, err := db.GetApplicationByID(ctx, 1, app.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Code review bot", got.Name)
+	assert.Equal(t, "https://bot.example.com", got.HomepageURL)
+	assert.Equal(t, []string{"https://bot.example.com/callback"}, got.RedirectURIList())
+	assert.False(t, got.Confidential)
+}
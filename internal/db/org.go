@@ -283,6 +283,15 @@ func GetOrgsByUserIDDesc(userID int64, desc string, showAll bool) ([]*User, erro
 	return getOrgsByUserID(x.NewSession().Desc(desc), userID, showAll)
 }
 
+// GetOrgsRequireTwoFactorByUserID returns a list of organizations that the
+// given user ID has joined and require members to enable two-factor
+// authentication.
+func GetOrgsRequireTwoFactorByUserID(userID int64) ([]*User, error) {
+	orgs := make([]*User, 0, 1)
+	return orgs, x.Where("`org_user`.uid=?", userID).And("`user`.require_two_factor=?", true).
+		Join("INNER", "`org_user`", "`org_user`.org_id=`user`.id").Find(&orgs)
+}
+
 func getOwnedOrgsByUserID(sess *xorm.Session, userID int64) ([]*User, error) {
 	orgs := make([]*User, 0, 10)
 	return orgs, sess.Where("`org_user`.uid=?", userID).And("`org_user`.is_owner=?", true).
@@ -430,6 +439,8 @@ func RemoveOrgUser(orgID, userID int64) error {
 	if len(repoIDs) > 0 {
 		if _, err = sess.Where("user_id = ?", user.ID).In("repo_id", repoIDs).Delete(new(Access)); err != nil {
 			return err
+		} else if _, err = sess.Exec("UPDATE `user` SET num_unread_notifications = -1 WHERE id = ?", user.ID); err != nil {
+			return err
 		}
 	}
 
@@ -362,6 +362,19 @@ func GetTeamsByOrgID(orgID int64) ([]*Team, error) {
 	return getTeamsByOrgID(x, orgID)
 }
 
+// CountTeams returns number of teams of all organizations.
+func CountTeams() int64 {
+	count, _ := x.Count(new(Team))
+	return count
+}
+
+// ListTeams returns at most "limit" teams of all organizations starting at
+// given offset.
+func ListTeams(offset, limit int) ([]*Team, error) {
+	teams := make([]*Team, 0, limit)
+	return teams, x.Limit(limit, offset).Asc("id").Find(&teams)
+}
+
 // UpdateTeam updates information of team.
 func UpdateTeam(t *Team, authChanged bool) (err error) {
 	if t.Name == "" {
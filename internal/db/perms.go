@@ -137,6 +137,23 @@ func (db *perms) SetRepoPerms(ctx context.Context, repoID int64, accessMap map[i
 			return err
 		}
 
-		return tx.Create(&records).Error
+		err = tx.Create(&records).Error
+		if err != nil {
+			return err
+		}
+
+		var userIDs []int64
+		err = tx.Model(&Notification{}).
+			Where("repo_id = ? AND is_read = ?", repoID, false).
+			Distinct().
+			Pluck("user_id", &userIDs).
+			Error
+		if err != nil {
+			return err
+		}
+
+		// Notifications in the repository are counted differently once accesses
+		// are changed.
+		return invalidateUnreadCounts(tx, userIDs...)
 	})
 }
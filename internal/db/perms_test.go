@@ -20,7 +20,7 @@ func TestPerms(t *testing.T) {
 	}
 	t.Parallel()
 
-	tables := []interface{}{new(Access)}
+	tables := []interface{}{new(Access), new(Notification), new(User)}
 	db := &perms{
 		DB: dbtest.NewDB(t, "perms", tables...),
 	}
@@ -311,4 +311,17 @@ func permsSetRepoPerms(t *testing.T, db *perms) {
 		{UserID: 5, RepoID: 2, Mode: AccessModeWrite},
 	}
 	assert.Equal(t, wantAccesses, accesses)
+
+	// Cached counts of unread notifications in the repository are invalidated
+	err = db.Create(&User{ID: 1, LowerName: "alice", Name: "alice", NumUnreadNotifications: 1}).Error
+	require.NoError(t, err)
+	err = db.Create(&Notification{UserID: 1, RepoID: 2, SubjectType: NotificationSubjectIssue, SubjectID: 1}).Error
+	require.NoError(t, err)
+	err = db.SetRepoPerms(ctx, 2, map[int64]AccessMode{5: AccessModeWrite})
+	require.NoError(t, err)
+
+	u := new(User)
+	err = db.First(u, 1).Error
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1), u.NumUnreadNotifications)
 }
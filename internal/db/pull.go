@@ -23,11 +23,8 @@ import (
 	"gogs.io/gogs/internal/errutil"
 	"gogs.io/gogs/internal/osutil"
 	"gogs.io/gogs/internal/process"
-	"gogs.io/gogs/internal/sync"
 )
 
-var PullRequestQueue = sync.NewUniqueQueue(1000)
-
 type PullRequestType int
 
 const (
@@ -43,6 +40,18 @@ const (
 	PULL_REQUEST_STATUS_MERGEABLE
 )
 
+func (status PullRequestStatus) String() string {
+	switch status {
+	case PULL_REQUEST_STATUS_CONFLICT:
+		return "conflict"
+	case PULL_REQUEST_STATUS_CHECKING:
+		return "checking"
+	case PULL_REQUEST_STATUS_MERGEABLE:
+		return "mergeable"
+	}
+	return "unknown"
+}
+
 // PullRequest represents relation between pull request and repositories.
 type PullRequest struct {
 	ID     int64
@@ -185,21 +194,153 @@ func (pr *PullRequest) CanAutoMerge() bool {
 	return pr.Status == PULL_REQUEST_STATUS_MERGEABLE
 }
 
+// HeadCommitID returns the ID of the latest commit of the head branch.
+func (pr *PullRequest) HeadCommitID() (string, error) {
+	if pr.HeadRepo == nil {
+		return "", ErrRepoNotExist{args: map[string]interface{}{"repoID": pr.HeadRepoID}}
+	}
+
+	headGitRepo, err := git.Open(pr.HeadRepo.RepoPath())
+	if err != nil {
+		return "", fmt.Errorf("open repository: %v", err)
+	}
+	return headGitRepo.BranchCommitID(pr.HeadBranch)
+}
+
+// PullRequestProtectionCheck is the result of checking a pull request against
+// the merge requirements of its protected base branch.
+type PullRequestProtectionCheck struct {
+	// Commit status contexts that are required to be successful.
+	RequiredContexts []string
+	// Required contexts whose latest status of the head commit is not successful.
+	MissingContexts   []string
+	RequiredApprovals int
+	Approvals         int
+}
+
+// IsSatisfied returns true if all merge requirements are met.
+func (check *PullRequestProtectionCheck) IsSatisfied() bool {
+	return len(check.MissingContexts) == 0 && check.Approvals >= check.RequiredApprovals
+}
+
+// CheckProtectBranch checks the pull request against the merge requirements of
+// its base branch with given head commit. The returned check is always
+// satisfied when the base branch is not protected.
+func (pr *PullRequest) CheckProtectBranch(headCommitID string) (*PullRequestProtectionCheck, error) {
+	check := new(PullRequestProtectionCheck)
+	protectBranch, err := GetProtectBranchOfRepoByName(pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		if IsErrBranchNotExist(err) {
+			return check, nil
+		}
+		return nil, fmt.Errorf("get protect branch of repository by name: %v", err)
+	} else if !protectBranch.Protected {
+		return check, nil
+	}
+
+	check.RequiredContexts = protectBranch.RequiredStatusCheckContexts()
+	if len(check.RequiredContexts) > 0 {
+		latest, err := CommitStatuses.ListLatestBySHAs(context.TODO(), pr.BaseRepoID, headCommitID)
+		if err != nil {
+			return nil, fmt.Errorf("list latest commit statuses: %v", err)
+		}
+
+		successful := make(map[string]bool, len(latest[headCommitID]))
+		for _, status := range latest[headCommitID] {
+			successful[status.Context] = status.State.IsSuccess()
+		}
+		for _, name := range check.RequiredContexts {
+			if !successful[name] {
+				check.MissingContexts = append(check.MissingContexts, name)
+			}
+		}
+	}
+
+	check.RequiredApprovals = protectBranch.RequiredApprovals
+	if check.RequiredApprovals > 0 {
+		check.Approvals, err = CountApprovals(pr.IssueID)
+		if err != nil {
+			return nil, fmt.Errorf("count approvals: %v", err)
+		}
+	}
+	return check, nil
+}
+
+type ErrPullRequestProtectionNotSatisfied struct {
+	args map[string]interface{}
+}
+
+func IsErrPullRequestProtectionNotSatisfied(err error) bool {
+	_, ok := err.(ErrPullRequestProtectionNotSatisfied)
+	return ok
+}
+
+func (err ErrPullRequestProtectionNotSatisfied) Error() string {
+	return fmt.Sprintf("merge requirements of protected branch are not satisfied: %v", err.args)
+}
+
 // MergeStyle represents the approach to merge commits into base branch.
 type MergeStyle string
 
 const (
-	MERGE_STYLE_REGULAR MergeStyle = "create_merge_commit"
-	MERGE_STYLE_REBASE  MergeStyle = "rebase_before_merging"
+	MERGE_STYLE_REGULAR      MergeStyle = "create_merge_commit"
+	MERGE_STYLE_REBASE       MergeStyle = "rebase_before_merging"
+	MERGE_STYLE_SQUASH       MergeStyle = "squash"
+	MERGE_STYLE_FAST_FORWARD MergeStyle = "fast_forward_only"
 )
 
-// Merge merges pull request to base repository.
+type ErrPullRequestNotFastForward struct {
+	args map[string]interface{}
+}
+
+func IsErrPullRequestNotFastForward(err error) bool {
+	_, ok := err.(ErrPullRequestNotFastForward)
+	return ok
+}
+
+func (err ErrPullRequestNotFastForward) Error() string {
+	return fmt.Sprintf("pull request cannot be merged by fast-forward: %v", err.args)
+}
+
+// SquashCommitMessage returns the default message of the squashed commit for
+// given commits of the pull request, which lists summaries of commits in
+// chronological order. The pull request must have its issue loaded.
+func (pr *PullRequest) SquashCommitMessage(commits []*git.Commit) string {
+	var buf strings.Builder
+	buf.WriteString(fmt.Sprintf("%s (#%d)\n", pr.Issue.Title, pr.Index))
+	if len(commits) > 0 {
+		buf.WriteString("\n")
+	}
+	for i := len(commits) - 1; i >= 0; i-- {
+		buf.WriteString("* " + commits[i].Summary() + "\n")
+	}
+	return buf.String()
+}
+
+// Merge merges pull request to base repository. For squash merge, the
+// commitDescription is used as the message of the squashed commit, and the
+// message returned by SquashCommitMessage is used if it is empty.
 // FIXME: add repoWorkingPull make sure two merges does not happen at same time.
 func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle MergeStyle, commitDescription string) (err error) {
 	ctx := context.TODO()
 
+	headCommitID, err := pr.HeadCommitID()
+	if err != nil {
+		return fmt.Errorf("get head commit ID: %v", err)
+	}
+	check, err := pr.CheckProtectBranch(headCommitID)
+	if err != nil {
+		return fmt.Errorf("check protect branch: %v", err)
+	} else if !check.IsSatisfied() {
+		return ErrPullRequestProtectionNotSatisfied{args: map[string]interface{}{
+			"missingContexts":   check.MissingContexts,
+			"approvals":         check.Approvals,
+			"requiredApprovals": check.RequiredApprovals,
+		}}
+	}
+
 	defer func() {
-		go HookQueue.Add(pr.BaseRepo.ID)
+		go QueueHookDelivery(pr.BaseRepo.ID)
 		go AddTestPullRequestTask(doer, pr.BaseRepo.ID, pr.BaseBranch, false)
 	}()
 
@@ -252,20 +393,20 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle
 		return fmt.Errorf("git fetch [%s -> %s]: %s", headRepoPath, tmpBasePath, stderr)
 	}
 
-	remoteHeadBranch := "head_repo/" + pr.HeadBranch
-
 	// Check if merge style is allowed, reset to default style if not
-	if mergeStyle == MERGE_STYLE_REBASE && !pr.BaseRepo.PullsAllowRebase {
+	if !pr.BaseRepo.IsMergeStyleAllowed(mergeStyle) {
 		mergeStyle = MERGE_STYLE_REGULAR
 	}
 
+	// NOTE: Merge the head commit that has been checked against the protected
+	// branch instead of the head branch, which may have been pushed since then.
 	switch mergeStyle {
 	case MERGE_STYLE_REGULAR: // Create merge commit
 
 		// Merge changes from head branch.
 		if _, stderr, err = process.ExecDir(-1, tmpBasePath,
 			fmt.Sprintf("PullRequest.Merge (git merge --no-ff --no-commit): %s", tmpBasePath),
-			"git", "merge", "--no-ff", "--no-commit", remoteHeadBranch); err != nil {
+			"git", "merge", "--no-ff", "--no-commit", headCommitID); err != nil {
 			return fmt.Errorf("git merge --no-ff --no-commit [%s]: %v - %s", tmpBasePath, err, stderr)
 		}
 
@@ -284,8 +425,8 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle
 		// Rebase head branch based on base branch, this creates a non-branch commit state.
 		if _, stderr, err = process.ExecDir(-1, tmpBasePath,
 			fmt.Sprintf("PullRequest.Merge (git rebase): %s", tmpBasePath),
-			"git", "rebase", "--quiet", pr.BaseBranch, remoteHeadBranch); err != nil {
-			return fmt.Errorf("git rebase [%s on %s]: %s", remoteHeadBranch, pr.BaseBranch, stderr)
+			"git", "rebase", "--quiet", pr.BaseBranch, headCommitID); err != nil {
+			return fmt.Errorf("git rebase [%s on %s]: %s", headCommitID, pr.BaseBranch, stderr)
 		}
 
 		// Name non-branch commit state to a new temporary branch in order to save changes.
@@ -310,6 +451,47 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle
 			return fmt.Errorf("git merge [%s]: %v - %s", tmpBasePath, err, stderr)
 		}
 
+	case MERGE_STYLE_SQUASH: // Squash all commits into one
+
+		// Stage changes from head branch without committing.
+		if _, stderr, err = process.ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git merge --squash): %s", tmpBasePath),
+			"git", "merge", "--squash", headCommitID); err != nil {
+			return fmt.Errorf("git merge --squash [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+
+		commitMessage := commitDescription
+		if strings.TrimSpace(commitMessage) == "" {
+			commits, err := headGitRepo.RevList([]string{pr.MergeBase + "..." + headCommitID})
+			if err != nil {
+				return fmt.Errorf("list commits [merge_base: %s, head: %s]: %v", pr.MergeBase, headCommitID, err)
+			}
+			commitMessage = pr.SquashCommitMessage(commits)
+		}
+
+		// Create the squashed commit for the base branch.
+		sig := doer.NewGitSig()
+		if _, stderr, err = process.ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git commit): %s", tmpBasePath),
+			"git", "commit", fmt.Sprintf("--author='%s <%s>'", sig.Name, sig.Email),
+			"-m", commitMessage); err != nil {
+			return fmt.Errorf("git commit [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+
+	case MERGE_STYLE_FAST_FORWARD: // Fast-forward only
+
+		// Move the base branch to the head branch, which fails if the base branch
+		// has diverged from the head branch.
+		if _, stderr, err = process.ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git merge --ff-only): %s", tmpBasePath),
+			"git", "merge", "--ff-only", headCommitID); err != nil {
+			return ErrPullRequestNotFastForward{args: map[string]interface{}{
+				"baseBranch": pr.BaseBranch,
+				"headBranch": pr.HeadBranch,
+				"stderr":     stderr,
+			}}
+		}
+
 	default:
 		return fmt.Errorf("unknown merge style: %s", mergeStyle)
 	}
@@ -321,10 +503,7 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle
 		return fmt.Errorf("git push: %s", stderr)
 	}
 
-	pr.MergedCommitID, err = headGitRepo.BranchCommitID(pr.HeadBranch)
-	if err != nil {
-		return fmt.Errorf("get head branch %q commit ID: %v", pr.HeadBranch, err)
-	}
+	pr.MergedCommitID = headCommitID
 
 	pr.HasMerged = true
 	pr.Merged = time.Now()
@@ -340,6 +519,9 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle
 	if err = Actions.MergePullRequest(ctx, doer, pr.Issue.Repo.Owner, pr.Issue.Repo, pr.Issue); err != nil {
 		log.Error("Failed to create action for merge pull request, pull_request_id: %d, error: %v", pr.ID, err)
 	}
+	if err = notifyIssueParticipants(pr.Issue, doer, 0, nil); err != nil {
+		log.Error("Failed to notify participants of merged pull request, pull_request_id: %d, error: %v", pr.ID, err)
+	}
 
 	// Reload pull request information.
 	if err = pr.LoadAttributes(); err != nil {
@@ -371,8 +553,11 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle
 		log.Error("Failed to get base branch %q commit: %v", pr.BaseBranch, err)
 		return nil
 	}
-	if mergeStyle == MERGE_STYLE_REGULAR {
+	switch mergeStyle {
+	case MERGE_STYLE_REGULAR:
 		commits = append([]*git.Commit{mergeCommit}, commits...)
+	case MERGE_STYLE_SQUASH:
+		commits = []*git.Commit{mergeCommit}
 	}
 
 	pcs, err := CommitsToPushCommits(commits).APIFormat(ctx, Users, pr.BaseRepo.RepoPath(), pr.BaseRepo.HTMLURL())
@@ -486,6 +671,7 @@ func NewPullRequest(repo *Repository, pull *Issue, labelIDs []int64, uuids []str
 	if err = sess.Commit(); err != nil {
 		return fmt.Errorf("Commit: %v", err)
 	}
+	UpdateIssueIndexer(pull.ID)
 
 	if err = NotifyWatchers(&Action{
 		ActUserID:    pull.Poster.ID,
@@ -706,12 +892,13 @@ func (pr *PullRequest) PushToBaseRepo() (err error) {
 
 // AddToTaskQueue adds itself to pull request test task queue.
 func (pr *PullRequest) AddToTaskQueue() {
-	go PullRequestQueue.AddFunc(pr.ID, func() {
+	go func() {
 		pr.Status = PULL_REQUEST_STATUS_CHECKING
 		if err := pr.UpdateCols("status"); err != nil {
 			log.Error("AddToTaskQueue.UpdateCols[%d].(add to queue): %v", pr.ID, err)
 		}
-	})
+		pushQueue(PullRequestQueue, pr.ID)
+	}()
 }
 
 type PullRequestList []*PullRequest
@@ -797,6 +984,10 @@ func AddTestPullRequestTask(doer *User, repoID int64, branch string, isSync bool
 					log.Error("LoadAttributes: %v", err)
 					continue
 				}
+				pr.dismissStaleApprovals()
+				if err = pr.markOutdatedCodeComments(); err != nil {
+					log.Error("Mark outdated code comments [pull_id: %d]: %v", pr.ID, err)
+				}
 				if err = PrepareWebhooks(pr.Issue.Repo, HOOK_EVENT_PULL_REQUEST, &api.PullRequestPayload{
 					Action:      api.HOOK_ISSUE_SYNCHRONIZED,
 					Index:       pr.Issue.Index,
@@ -824,6 +1015,24 @@ func AddTestPullRequestTask(doer *User, repoID int64, branch string, isSync bool
 	}
 }
 
+// dismissStaleApprovals dismisses existing approvals of the pull request when
+// its protected base branch requires so on new pushes.
+func (pr *PullRequest) dismissStaleApprovals() {
+	protectBranch, err := GetProtectBranchOfRepoByName(pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		if !IsErrBranchNotExist(err) {
+			log.Error("Get protect branch of repository by name [repo_id: %d, name: %s]: %v", pr.BaseRepoID, pr.BaseBranch, err)
+		}
+		return
+	} else if !protectBranch.Protected || !protectBranch.DismissStaleApprovals {
+		return
+	}
+
+	if err = DismissApprovals(pr.IssueID); err != nil {
+		log.Error("Dismiss approvals [pull_id: %d]: %v", pr.ID, err)
+	}
+}
+
 func ChangeUsernameInPullRequests(oldUserName, newUserName string) error {
 	pr := PullRequest{
 		HeadUserName: strings.ToLower(newUserName),
@@ -841,8 +1050,11 @@ func (pr *PullRequest) checkAndUpdateStatus() {
 	}
 
 	// Make sure there is no waiting test to process before leaving the checking status.
-	if !PullRequestQueue.Exist(pr.ID) {
-		if err := pr.UpdateCols("status"); err != nil {
+	waiting, err := PullRequestQueue.Has(com.ToStr(pr.ID))
+	if err != nil {
+		log.Error("Check pull request queue [%d]: %v", pr.ID, err)
+	} else if !waiting {
+		if err = pr.UpdateCols("status"); err != nil {
 			log.Error("Update[%d]: %v", pr.ID, err)
 		}
 	}
@@ -851,46 +1063,49 @@ func (pr *PullRequest) checkAndUpdateStatus() {
 // TestPullRequests checks and tests untested patches of pull requests.
 // TODO: test more pull requests at same time.
 func TestPullRequests() {
-	prs := make([]*PullRequest, 0, 10)
+	// Pull requests that are still checking may have been added before the queue
+	// was persisted, make sure they are in the queue.
 	_ = x.Iterate(PullRequest{
 		Status: PULL_REQUEST_STATUS_CHECKING,
 	},
 		func(idx int, bean interface{}) error {
-			pr := bean.(*PullRequest)
-
-			if err := pr.LoadAttributes(); err != nil {
-				log.Error("LoadAttributes: %v", err)
-				return nil
-			}
-
-			if err := pr.testPatch(); err != nil {
-				log.Error("testPatch: %v", err)
-				return nil
-			}
-			prs = append(prs, pr)
+			pushQueue(PullRequestQueue, bean.(*PullRequest).ID)
 			return nil
 		})
 
-	// Update pull request status.
-	for _, pr := range prs {
-		pr.checkAndUpdateStatus()
-	}
-
 	// Start listening on new test requests.
-	for prID := range PullRequestQueue.Queue() {
-		log.Trace("TestPullRequests[%v]: processing test task", prID)
-		PullRequestQueue.Remove(prID)
+	consumeQueue(PullRequestQueue, testPullRequest, giveUpTestingPullRequest)
+}
 
-		pr, err := GetPullRequestByID(com.StrTo(prID).MustInt64())
-		if err != nil {
-			log.Error("GetPullRequestByID[%s]: %v", prID, err)
-			continue
-		} else if err = pr.testPatch(); err != nil {
-			log.Error("testPatch[%d]: %v", pr.ID, err)
-			continue
+// testPullRequest tests the patch of the pull request and updates its status.
+func testPullRequest(prID int64) error {
+	log.Trace("TestPullRequests[%d]: processing test task", prID)
+
+	pr, err := GetPullRequestByID(prID)
+	if err != nil {
+		if IsErrPullRequestNotExist(err) {
+			return nil
 		}
+		return fmt.Errorf("GetPullRequestByID: %v", err)
+	} else if err = pr.testPatch(); err != nil {
+		return fmt.Errorf("testPatch: %v", err)
+	}
 
-		pr.checkAndUpdateStatus()
+	pr.checkAndUpdateStatus()
+	return nil
+}
+
+// giveUpTestingPullRequest marks the pull request as conflicting after it has
+// failed to be tested too many times, so it does not stay checking forever. It
+// is tested again when the head or base branch is updated.
+func giveUpTestingPullRequest(prID int64, reason string) {
+	log.Error("TestPullRequests[%d]: giving up after too many failed attempts: %s", prID, reason)
+
+	_, err := x.Where("id = ? AND status = ?", prID, PULL_REQUEST_STATUS_CHECKING).
+		Cols("status").
+		Update(&PullRequest{Status: PULL_REQUEST_STATUS_CONFLICT})
+	if err != nil {
+		log.Error("Failed to update status of pull request [id: %d]: %v", prID, err)
 	}
 }
 
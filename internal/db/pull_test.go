@@ -0,0 +1,31 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/gogs/git-module"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullRequest_SquashCommitMessage(t *testing.T) {
+	pr := &PullRequest{
+		Index: 7,
+		Issue: &Issue{Title: "Add README"},
+	}
+
+	t.Run("no commits", func(t *testing.T) {
+		assert.Equal(t, "Add README (#7)\n", pr.SquashCommitMessage(nil))
+	})
+
+	t.Run("commits in reverse chronological order", func(t *testing.T) {
+		commits := []*git.Commit{
+			{Message: "Fix typo\n\nSigned-off-by: alice"},
+			{Message: "Add README"},
+		}
+		assert.Equal(t, "Add README (#7)\n\n* Add README\n* Fix typo\n", pr.SquashCommitMessage(commits))
+	})
+}
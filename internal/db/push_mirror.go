@@ -0,0 +1,155 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/process"
+)
+
+// pushMirrorRefspecs are the references pushed to push mirrors. Other
+// references (e.g. refs/pull/*) are internal to the repository and are not
+// pushed.
+var pushMirrorRefspecs = []string{
+	"+refs/heads/*:refs/heads/*",
+	"+refs/tags/*:refs/tags/*",
+}
+
+// authURL returns the remote URL with credentials decrypted with given key. The
+// credentials are only applied to HTTP and HTTPS remotes.
+func (m *PushMirror) authURL(key string) (string, error) {
+	if m.Username == "" && m.Password == "" {
+		return m.RemoteURL, nil
+	}
+
+	u, err := url.Parse(m.RemoteURL)
+	if err != nil {
+		return "", errors.Wrap(err, "parse remote URL")
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return m.RemoteURL, nil
+	}
+
+	password, err := m.DecryptPassword(key)
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		u.User = url.User(m.Username)
+	} else {
+		u.User = url.UserPassword(m.Username, password)
+	}
+	return u.String(), nil
+}
+
+// push pushes all branches and tags of the repository at given path to the
+// remote, references that no longer exist in the repository are deleted from
+// the remote. Credentials are removed from the returned error.
+func (m *PushMirror) push(repoPath, key string) error {
+	remote, err := m.authURL(key)
+	if err != nil {
+		return err
+	}
+
+	timeout := time.Duration(conf.Git.Timeout.Mirror) * time.Second
+	args := append([]string{"push", "--prune", remote}, pushMirrorRefspecs...)
+	_, stderr, err := process.ExecDir(
+		timeout, repoPath, fmt.Sprintf("PushMirror.push: %s", repoPath),
+		"git", args...)
+	if err == nil {
+		return nil
+	}
+
+	output := strings.TrimSpace(stderr)
+	if output == "" {
+		output = err.Error()
+	}
+	if remote != m.RemoteURL {
+		output = strings.ReplaceAll(output, remote, m.RemoteURL)
+		if password, _ := m.DecryptPassword(key); password != "" {
+			output = strings.ReplaceAll(output, password, "<credentials>")
+			escaped := strings.TrimPrefix(url.UserPassword("", password).String(), ":")
+			output = strings.ReplaceAll(output, escaped, "<credentials>")
+		}
+	}
+	return errors.New(output)
+}
+
+// QueuePushMirrorsOnPush adds push mirrors of the repository that are synced on
+// every push to the queue. It must be called by the web server because tasks
+// pushed by Git hooks are discarded when the queues are stored on disk.
+func QueuePushMirrorsOnPush(ctx context.Context, repoID int64) error {
+	mirrors, err := PushMirrors.List(ctx, repoID)
+	if err != nil {
+		return errors.Wrap(err, "list push mirrors")
+	}
+	for _, m := range mirrors {
+		if m.SyncOnPush {
+			QueuePushMirrorSync(m.ID)
+		}
+	}
+	return nil
+}
+
+// queueDuePushMirrors adds push mirrors that are due to be synced to the queue.
+func queueDuePushMirrors(ctx context.Context) error {
+	mirrors, err := PushMirrors.ListDue(ctx)
+	if err != nil {
+		return errors.Wrap(err, "list due push mirrors")
+	}
+	for _, m := range mirrors {
+		QueuePushMirrorSync(m.ID)
+	}
+	return nil
+}
+
+// SyncPushMirrors syncs push mirrors in the queue.
+func SyncPushMirrors() {
+	consumeQueue(PushMirrorQueue, syncPushMirror, nil)
+}
+
+// syncPushMirror pushes the repository to the push mirror and records the
+// result. It returns an error when the push failed so that it is retried.
+func syncPushMirror(id int64) error {
+	ctx := context.Background()
+	log.Trace("SyncPushMirrors [id: %d]", id)
+
+	m, err := PushMirrors.GetByID(ctx, id)
+	if err != nil {
+		if IsErrPushMirrorNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "get push mirror")
+	}
+
+	repo, err := GetRepositoryByID(m.RepoID)
+	if err != nil {
+		if IsErrRepoNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "get repository")
+	}
+
+	var output string
+	pushErr := m.push(repo.RepoPath(), conf.Security.SecretKey)
+	if pushErr != nil {
+		output = pushErr.Error()
+	}
+	if err = PushMirrors.UpdateSyncResult(ctx, m.ID, output); err != nil {
+		return errors.Wrap(err, "update sync result")
+	}
+	if pushErr != nil {
+		return errors.Wrapf(pushErr, "push to %q", m.RemoteURL)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/cryptoutil"
+	"gogs.io/gogs/internal/errutil"
+)
+
+// PushMirrorsStore is the persistent interface for push mirrors.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type PushMirrorsStore interface {
+	// Create creates a new push mirror for the repository, the password is
+	// encrypted with given key.
+	Create(ctx context.Context, repoID int64, key string, opts CreatePushMirrorOptions) (*PushMirror, error)
+	// DeleteByID deletes the push mirror with given ID of the repository.
+	DeleteByID(ctx context.Context, repoID, id int64) error
+	// GetByID returns the push mirror with given ID. It returns
+	// ErrPushMirrorNotExist when not found.
+	GetByID(ctx context.Context, id int64) (*PushMirror, error)
+	// List returns all push mirrors of the repository in the order of creation.
+	List(ctx context.Context, repoID int64) ([]*PushMirror, error)
+	// ListDue returns all push mirrors that have a sync interval and are due
+	// to be synced.
+	ListDue(ctx context.Context) ([]*PushMirror, error)
+	// UpdateSyncResult records the result of a sync of the push mirror, an empty
+	// error output means the sync has succeeded. It also schedules the next sync
+	// based on the sync interval.
+	UpdateSyncResult(ctx context.Context, id int64, errOutput string) error
+}
+
+var PushMirrors PushMirrorsStore
+
+// PushMirror is a remote that a repository is mirrored to by pushing all of its
+// branches and tags.
+type PushMirror struct {
+	ID        int64  `gorm:"primaryKey"`
+	RepoID    int64  `gorm:"index;not null"`
+	RemoteURL string `gorm:"type:TEXT;not null"` // Without credentials.
+	Username  string
+	Password  string // Encrypted and base64-encoded.
+	// The number of hours between syncs, zero means no periodic sync.
+	SyncInterval int  `gorm:"not null"`
+	SyncOnPush   bool `gorm:"not null"`
+
+	LastSyncAt *time.Time
+	// The error output of the last sync, it is empty if the sync succeeded.
+	LastError  string    `gorm:"type:TEXT"`
+	NextSyncAt time.Time `gorm:"index;not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+	UpdatedAt  time.Time `gorm:"not null"`
+}
+
+// PushMirrorStatus is the status of the last sync of a push mirror.
+type PushMirrorStatus string
+
+const (
+	PushMirrorStatusNever     PushMirrorStatus = "never"
+	PushMirrorStatusSucceeded PushMirrorStatus = "succeeded"
+	PushMirrorStatusFailed    PushMirrorStatus = "failed"
+)
+
+// Status returns the status of the last sync.
+func (m *PushMirror) Status() PushMirrorStatus {
+	switch {
+	case m.LastSyncAt == nil:
+		return PushMirrorStatusNever
+	case m.LastError != "":
+		return PushMirrorStatusFailed
+	default:
+		return PushMirrorStatusSucceeded
+	}
+}
+
+// DecryptPassword returns the password in plain text that was encrypted with
+// given key.
+func (m *PushMirror) DecryptPassword(key string) (string, error) {
+	if m.Password == "" {
+		return "", nil
+	}
+
+	encrypted, err := base64.StdEncoding.DecodeString(m.Password)
+	if err != nil {
+		return "", errors.Wrap(err, "decode password")
+	}
+	password, err := cryptoutil.AESGCMDecrypt(cryptoutil.MD5Bytes(key), encrypted)
+	if err != nil {
+		return "", errors.Wrap(err, "decrypt password")
+	}
+	return string(password), nil
+}
+
+var _ PushMirrorsStore = (*pushMirrors)(nil)
+
+type pushMirrors struct {
+	*gorm.DB
+}
+
+// NewPushMirrorsStore returns a persistent interface for push mirrors with
+// given database connection.
+func NewPushMirrorsStore(db *gorm.DB) PushMirrorsStore {
+	return &pushMirrors{DB: db}
+}
+
+type CreatePushMirrorOptions struct {
+	RemoteURL    string
+	Username     string
+	Password     string
+	SyncInterval int
+	SyncOnPush   bool
+}
+
+func (db *pushMirrors) Create(ctx context.Context, repoID int64, key string, opts CreatePushMirrorOptions) (*PushMirror, error) {
+	m := &PushMirror{
+		RepoID:       repoID,
+		RemoteURL:    opts.RemoteURL,
+		Username:     opts.Username,
+		SyncInterval: opts.SyncInterval,
+		SyncOnPush:   opts.SyncOnPush,
+	}
+	if opts.Password != "" {
+		encrypted, err := cryptoutil.AESGCMEncrypt(cryptoutil.MD5Bytes(key), []byte(opts.Password))
+		if err != nil {
+			return nil, errors.Wrap(err, "encrypt password")
+		}
+		m.Password = base64.StdEncoding.EncodeToString(encrypted)
+	}
+	m.NextSyncAt = db.NowFunc().Add(time.Duration(m.SyncInterval) * time.Hour)
+	if err := db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (db *pushMirrors) DeleteByID(ctx context.Context, repoID, id int64) error {
+	return db.WithContext(ctx).Where("id = ? AND repo_id = ?", id, repoID).Delete(new(PushMirror)).Error
+}
+
+var _ errutil.NotFound = (*ErrPushMirrorNotExist)(nil)
+
+type ErrPushMirrorNotExist struct {
+	args errutil.Args
+}
+
+func IsErrPushMirrorNotExist(err error) bool {
+	_, ok := err.(ErrPushMirrorNotExist)
+	return ok
+}
+
+func (err ErrPushMirrorNotExist) Error() string {
+	return fmt.Sprintf("push mirror does not exist: %v", err.args)
+}
+
+func (ErrPushMirrorNotExist) NotFound() bool {
+	return true
+}
+
+func (db *pushMirrors) GetByID(ctx context.Context, id int64) (*PushMirror, error) {
+	m := new(PushMirror)
+	err := db.WithContext(ctx).Where("id = ?", id).First(m).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPushMirrorNotExist{args: errutil.Args{"id": id}}
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func (db *pushMirrors) List(ctx context.Context, repoID int64) ([]*PushMirror, error) {
+	mirrors := make([]*PushMirror, 0, 2)
+	return mirrors, db.WithContext(ctx).Where("repo_id = ?", repoID).Order("id ASC").Find(&mirrors).Error
+}
+
+func (db *pushMirrors) ListDue(ctx context.Context) ([]*PushMirror, error) {
+	var mirrors []*PushMirror
+	return mirrors, db.WithContext(ctx).
+		Where("sync_interval > 0 AND next_sync_at <= ?", db.NowFunc()).
+		Order("next_sync_at ASC").
+		Find(&mirrors).Error
+}
+
+func (db *pushMirrors) UpdateSyncResult(ctx context.Context, id int64, errOutput string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		m := new(PushMirror)
+		err := tx.Where("id = ?", id).First(m).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrPushMirrorNotExist{args: errutil.Args{"id": id}}
+			}
+			return err
+		}
+
+		now := tx.NowFunc()
+		return tx.Model(m).Updates(map[string]interface{}{
+			"last_sync_at": now,
+			"last_error":   errOutput,
+			"next_sync_at": now.Add(time.Duration(m.SyncInterval) * time.Hour),
+			"updated_at":   now,
+		}).Error
+	})
+}
@@ -0,0 +1,354 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/queue"
+)
+
+// Names of task queues.
+const (
+	hookQueueName          = "webhook"
+	mirrorQueueName        = "mirror_sync"
+	pullRequestQueueName   = "pull_request_test"
+	pushMirrorQueueName    = "push_mirror"
+	repoMigrationQueueName = "repo_migration"
+)
+
+var (
+	// HookQueue is the queue of repositories that have webhooks to be delivered.
+	HookQueue queue.Queue = queue.NewDiscard(hookQueueName)
+	// MirrorQueue is the queue of mirror repositories to be synced.
+	MirrorQueue queue.Queue = queue.NewDiscard(mirrorQueueName)
+	// PullRequestQueue is the queue of pull requests to be tested.
+	PullRequestQueue queue.Queue = queue.NewDiscard(pullRequestQueueName)
+	// PushMirrorQueue is the queue of push mirrors to be synced.
+	PushMirrorQueue queue.Queue = queue.NewDiscard(pushMirrorQueueName)
+	// RepoMigrationQueue is the queue of repository migrations to be run.
+	RepoMigrationQueue queue.Queue = queue.NewDiscard(repoMigrationQueueName)
+)
+
+// Queues returns all task queues.
+func Queues() []queue.Queue {
+	return []queue.Queue{HookQueue, MirrorQueue, PullRequestQueue, PushMirrorQueue, RepoMigrationQueue}
+}
+
+func queueOptions() queue.Options {
+	return queue.Options{
+		VisibilityTimeout: conf.Queue.VisibilityTimeout,
+		MaxAttempts:       conf.Queue.MaxAttempts,
+		RetryInterval:     conf.Queue.RetryInterval,
+	}
+}
+
+// initDatabaseQueues sets task queues to be backed by the database when
+// configured, which are shared by all processes.
+func initDatabaseQueues(db *gorm.DB) {
+	if conf.Queue.Type == "disk" {
+		return
+	}
+
+	opts := queueOptions()
+	HookQueue = NewDatabaseQueue(db, hookQueueName, opts)
+	MirrorQueue = NewDatabaseQueue(db, mirrorQueueName, opts)
+	PullRequestQueue = NewDatabaseQueue(db, pullRequestQueueName, opts)
+	PushMirrorQueue = NewDatabaseQueue(db, pushMirrorQueueName, opts)
+	RepoMigrationQueue = NewDatabaseQueue(db, repoMigrationQueueName, opts)
+}
+
+// initDiskQueues sets task queues to be backed by the embedded on-disk storage
+// when configured. Because the storage can only be opened by one process, tasks
+// pushed by other processes are discarded, and the web server relies on being
+// triggered by them (e.g. Git hooks) instead.
+func initDiskQueues() error {
+	if conf.Queue.Type != "disk" {
+		return nil
+	}
+
+	d, err := queue.OpenDisk(conf.Queue.Path)
+	if err != nil {
+		return errors.Wrap(err, "open disk queues")
+	}
+
+	opts := queueOptions()
+	HookQueue = d.Queue(hookQueueName, opts)
+	MirrorQueue = d.Queue(mirrorQueueName, opts)
+	PullRequestQueue = d.Queue(pullRequestQueueName, opts)
+	PushMirrorQueue = d.Queue(pushMirrorQueueName, opts)
+	RepoMigrationQueue = d.Queue(repoMigrationQueueName, opts)
+	return nil
+}
+
+// pushQueue pushes the ID to the queue, and logs the error if any.
+func pushQueue(q queue.Queue, id int64) {
+	if err := q.Push(strconv.FormatInt(id, 10)); err != nil {
+		log.Error("Failed to push %d to queue %q: %v", id, q.Name(), err)
+	}
+}
+
+// QueueHookDelivery adds the repository to the queue to deliver its webhooks.
+func QueueHookDelivery(repoID int64) {
+	pushQueue(HookQueue, repoID)
+}
+
+// QueueMirrorSync adds the mirror repository to the queue to be synced.
+func QueueMirrorSync(repoID int64) {
+	pushQueue(MirrorQueue, repoID)
+}
+
+// QueuePushMirrorSync adds the push mirror to the queue to be synced.
+func QueuePushMirrorSync(id int64) {
+	pushQueue(PushMirrorQueue, id)
+}
+
+// QueueRepoMigration adds the repository migration to the queue to be run.
+func QueueRepoMigration(id int64) {
+	pushQueue(RepoMigrationQueue, id)
+}
+
+// consumeQueue processes tasks of the queue with IDs as keys. The optional
+// giveUp function is called when a task runs out of attempts.
+func consumeQueue(q queue.Queue, handle func(id int64) error, giveUp func(id int64, reason string)) {
+	var giveUpKey func(key, reason string)
+	if giveUp != nil {
+		giveUpKey = func(key, reason string) {
+			// The key has been parsed successfully before the task failed.
+			id, _ := strconv.ParseInt(key, 10, 64)
+			giveUp(id, reason)
+		}
+	}
+	queue.Consume(q, func(key string) error {
+		id, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			// Retrying would never help.
+			log.Error("Invalid key %q of queue %q: %v", key, q.Name(), err)
+			return nil
+		}
+		return handle(id)
+	}, giveUpKey)
+}
+
+// QueueTask is a task in the database-backed queue.
+type QueueTask struct {
+	ID        int64     `gorm:"primaryKey"`
+	Queue     string    `gorm:"type:VARCHAR(64);uniqueIndex:queue_task_queue_key_unique;not null"`
+	Key       string    `gorm:"column:task_key;type:VARCHAR(255);uniqueIndex:queue_task_queue_key_unique;not null"`
+	Attempts  int       `gorm:"not null"`
+	LastError string    `gorm:"type:TEXT"`
+	Requeued  bool      `gorm:"not null"`
+	Receipt   string    `gorm:"type:VARCHAR(32);not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	VisibleAt time.Time `gorm:"index;not null"`
+}
+
+func (t *QueueTask) toTask() *queue.Task {
+	return &queue.Task{
+		Key:       t.Key,
+		Attempts:  t.Attempts,
+		LastError: t.LastError,
+		Requeued:  t.Requeued,
+		Receipt:   t.Receipt,
+		CreatedAt: t.CreatedAt,
+		VisibleAt: t.VisibleAt,
+	}
+}
+
+// update updates the row with given task if the row has not been changed by
+// others since it was read, and returns false otherwise.
+func (t *QueueTask) update(tx *gorm.DB, task *queue.Task) (bool, error) {
+	result := tx.Model(new(QueueTask)).
+		Where("id = ? AND receipt = ? AND requeued = ? AND attempts = ?", t.ID, t.Receipt, t.Requeued, t.Attempts).
+		Updates(map[string]interface{}{
+			"attempts":   task.Attempts,
+			"last_error": task.LastError,
+			"requeued":   task.Requeued,
+			"receipt":    task.Receipt,
+			"visible_at": task.VisibleAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+var _ queue.Queue = (*databaseQueue)(nil)
+
+type databaseQueue struct {
+	*gorm.DB
+	name string
+	opts queue.Options
+}
+
+// NewDatabaseQueue returns a task queue with given name that is backed by the
+// database connection.
+func NewDatabaseQueue(db *gorm.DB, name string, opts queue.Options) queue.Queue {
+	return &databaseQueue{DB: db, name: name, opts: opts}
+}
+
+// maxQueueRetries is the maximum number of times to retry an operation that
+// conflicts with other processes.
+const maxQueueRetries = 3
+
+func (q *databaseQueue) Name() string {
+	return q.name
+}
+
+func (q *databaseQueue) get(key string) (*QueueTask, error) {
+	t := new(QueueTask)
+	err := q.Where("queue = ? AND task_key = ?", q.name, key).First(t).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (q *databaseQueue) Push(key string) error {
+	for i := 0; i < maxQueueRetries; i++ {
+		t, err := q.get(key)
+		if err != nil {
+			return errors.Wrap(err, "get task")
+		}
+
+		now := q.NowFunc()
+		if t == nil {
+			task := queue.NewTask(key, now)
+			err = q.Create(&QueueTask{
+				Queue:     q.name,
+				Key:       key,
+				CreatedAt: task.CreatedAt,
+				VisibleAt: task.VisibleAt,
+			}).Error
+			if err != nil {
+				// The task may have been created by others at the same time.
+				continue
+			}
+			queue.Wake(q.name)
+			return nil
+		}
+
+		task := t.toTask()
+		if !task.MarkPushed(now) {
+			return nil
+		}
+		updated, err := t.update(q.DB, task)
+		if err != nil {
+			return errors.Wrap(err, "update task")
+		} else if updated {
+			queue.Wake(q.name)
+			return nil
+		}
+	}
+	return errors.Errorf("push task %q: too many conflicts", key)
+}
+
+func (q *databaseQueue) Pop() (*queue.Task, error) {
+	for i := 0; i < maxQueueRetries; i++ {
+		now := q.NowFunc()
+		var candidates []*QueueTask
+		err := q.Where("queue = ? AND visible_at <= ?", q.name, now).
+			Order("visible_at ASC, id ASC").
+			Limit(5).
+			Find(&candidates).Error
+		if err != nil {
+			return nil, errors.Wrap(err, "find visible tasks")
+		} else if len(candidates) == 0 {
+			return nil, queue.ErrEmpty
+		}
+
+		for _, t := range candidates {
+			task := t.toTask()
+			if err = task.MarkPopped(now, q.opts); err != nil {
+				return nil, err
+			}
+			updated, err := t.update(q.DB, task)
+			if err != nil {
+				return nil, errors.Wrap(err, "update task")
+			} else if updated {
+				return task, nil
+			}
+		}
+	}
+	return nil, queue.ErrEmpty
+}
+
+// release applies the function to the task if it has not been popped again,
+// and removes the task when the function returns true.
+func (q *databaseQueue) release(task *queue.Task, fn func(current *queue.Task, now time.Time) bool) (removed bool, _ error) {
+	for i := 0; i < maxQueueRetries; i++ {
+		t, err := q.get(task.Key)
+		if err != nil {
+			return false, errors.Wrap(err, "get task")
+		} else if t == nil || t.Receipt != task.Receipt {
+			return false, queue.ErrStaleReceipt
+		}
+
+		current := t.toTask()
+		if fn(current, q.NowFunc()) {
+			result := q.Where("id = ? AND receipt = ? AND requeued = ?", t.ID, t.Receipt, t.Requeued).Delete(new(QueueTask))
+			if result.Error != nil {
+				return false, errors.Wrap(result.Error, "delete task")
+			} else if result.RowsAffected > 0 {
+				return true, nil
+			}
+			continue
+		}
+
+		updated, err := t.update(q.DB, current)
+		if err != nil {
+			return false, errors.Wrap(err, "update task")
+		} else if updated {
+			return false, nil
+		}
+	}
+	return false, errors.Errorf("release task %q: too many conflicts", task.Key)
+}
+
+func (q *databaseQueue) Ack(t *queue.Task) error {
+	_, err := q.release(t, func(current *queue.Task, now time.Time) bool {
+		return current.MarkAcked(now)
+	})
+	return err
+}
+
+func (q *databaseQueue) Nack(t *queue.Task, reason string) (bool, error) {
+	return q.release(t, func(current *queue.Task, now time.Time) bool {
+		return current.MarkNacked(now, q.opts, reason)
+	})
+}
+
+func (q *databaseQueue) Has(key string) (bool, error) {
+	t, err := q.get(key)
+	if err != nil {
+		return false, err
+	} else if t == nil {
+		return false, nil
+	}
+	return t.Requeued || !t.toTask().IsProcessing(q.NowFunc()), nil
+}
+
+func (q *databaseQueue) List() ([]*queue.Task, error) {
+	var rows []*QueueTask
+	err := q.Where("queue = ?", q.name).Order("id ASC").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*queue.Task, len(rows))
+	for i := range rows {
+		tasks[i] = rows[i].toTask()
+	}
+	return tasks, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/queue"
+)
+
+func TestDatabaseQueue(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(QueueTask)}
+	db := dbtest.NewDB(t, "databaseQueue", tables...)
+	now := db.NowFunc()
+	db.Config.NowFunc = func() time.Time { return now }
+
+	opts := queue.Options{
+		VisibilityTimeout: time.Minute,
+		MaxAttempts:       2,
+		RetryInterval:     time.Minute,
+	}
+	q := NewDatabaseQueue(db, "test", opts)
+	other := NewDatabaseQueue(db, "other", opts)
+
+	_, err := q.Pop()
+	assert.Equal(t, queue.ErrEmpty, err)
+
+	// Tasks are deduplicated by keys, and queues do not interfere with each other.
+	require.NoError(t, q.Push("1"))
+	now = now.Add(time.Second)
+	require.NoError(t, q.Push("2"))
+	require.NoError(t, q.Push("1"))
+	require.NoError(t, other.Push("1"))
+	tasks, err := q.List()
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "1", tasks[0].Key)
+	assert.Equal(t, "2", tasks[1].Key)
+
+	// Tasks are popped in order, and are invisible while being processed.
+	task1, err := q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "1", task1.Key)
+	has, err := q.Has("1")
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	task2, err := q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "2", task2.Key)
+	_, err = q.Pop()
+	assert.Equal(t, queue.ErrEmpty, err)
+
+	// Acknowledged task is removed.
+	require.NoError(t, q.Ack(task1))
+	has, err = q.Has("1")
+	require.NoError(t, err)
+	assert.False(t, has)
+	has, err = other.Has("1")
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	// Failed task is retried after the backoff.
+	dropped, err := q.Nack(task2, "boom")
+	require.NoError(t, err)
+	assert.False(t, dropped)
+	_, err = q.Pop()
+	assert.Equal(t, queue.ErrEmpty, err)
+	now = now.Add(time.Minute)
+	task2, err = q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, 2, task2.Attempts)
+	assert.Equal(t, "boom", task2.LastError)
+
+	// Task becomes visible again after the visibility timeout, and the stale
+	// receipt cannot be used anymore.
+	now = now.Add(2 * time.Minute)
+	again, err := q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "2", again.Key)
+	assert.Equal(t, queue.ErrStaleReceipt, q.Ack(task2))
+
+	// Task is removed after running out of attempts.
+	dropped, err = q.Nack(again, "boom")
+	require.NoError(t, err)
+	assert.True(t, dropped)
+	tasks, err = q.List()
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	// Pushing the task being processed processes it again.
+	require.NoError(t, q.Push("3"))
+	task3, err := q.Pop()
+	require.NoError(t, err)
+	require.NoError(t, q.Push("3"))
+	has, err = q.Has("3")
+	require.NoError(t, err)
+	assert.True(t, has)
+	require.NoError(t, q.Ack(task3))
+	task3, err = q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "3", task3.Key)
+	assert.Equal(t, 1, task3.Attempts)
+}
@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -203,6 +204,9 @@ func NewRelease(gitRepo *git.Repository, r *Release, uuids []string) error {
 		return fmt.Errorf("GetReleaseByID: %v", err)
 	}
 	r.preparePublishWebhooks()
+	if err = notifyReleaseWatchers(r); err != nil {
+		log.Error("notifyReleaseWatchers: %v", err)
+	}
 	return nil
 }
 
@@ -342,6 +346,9 @@ func UpdateRelease(doer *User, gitRepo *git.Repository, r *Release, isPublish bo
 	}
 	r.Publisher = doer
 	r.preparePublishWebhooks()
+	if err = notifyReleaseWatchers(r); err != nil {
+		log.Error("notifyReleaseWatchers: %v", err)
+	}
 	return nil
 }
 
@@ -372,6 +379,9 @@ func DeleteReleaseOfRepoByID(repoID, id int64) error {
 	if _, err = x.Id(rel.ID).Delete(new(Release)); err != nil {
 		return fmt.Errorf("Delete: %v", err)
 	}
+	if err = Notifications.DeleteBySubject(context.TODO(), NotificationSubjectRelease, rel.ID); err != nil {
+		return fmt.Errorf("delete notifications: %v", err)
+	}
 
 	return nil
 }
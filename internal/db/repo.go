@@ -201,6 +201,8 @@ type Repository struct {
 	EnablePulls           bool              `xorm:"NOT NULL DEFAULT true" gorm:"not null;default:TRUE"`
 	PullsIgnoreWhitespace bool              `xorm:"NOT NULL DEFAULT false" gorm:"not null;default:FALSE"`
 	PullsAllowRebase      bool              `xorm:"NOT NULL DEFAULT false" gorm:"not null;default:FALSE"`
+	PullsAllowSquash      bool              `xorm:"NOT NULL DEFAULT false" gorm:"not null;default:FALSE"`
+	PullsAllowFastForward bool              `xorm:"NOT NULL DEFAULT false" gorm:"not null;default:FALSE"`
 
 	IsFork   bool `xorm:"NOT NULL DEFAULT false" gorm:"not null;default:FALSE"`
 	ForkID   int64
@@ -591,6 +593,22 @@ func (repo *Repository) AllowsPulls() bool {
 	return repo.CanEnablePulls() && repo.EnablePulls
 }
 
+// IsMergeStyleAllowed returns true if the given merge style is enabled for pull
+// requests of the repository.
+func (repo *Repository) IsMergeStyleAllowed(style MergeStyle) bool {
+	switch style {
+	case MERGE_STYLE_REGULAR:
+		return true
+	case MERGE_STYLE_REBASE:
+		return repo.PullsAllowRebase
+	case MERGE_STYLE_SQUASH:
+		return repo.PullsAllowSquash
+	case MERGE_STYLE_FAST_FORWARD:
+		return repo.PullsAllowFastForward
+	}
+	return false
+}
+
 func (repo *Repository) IsBranchRequirePullRequest(name string) bool {
 	return IsBranchOfRepoRequirePullRequest(repo.ID, name)
 }
@@ -732,6 +750,11 @@ type MigrateRepoOptions struct {
 	IsUnlisted  bool
 	IsMirror    bool
 	RemoteAddr  string
+	// Refspecs of mirrors separated by new lines (see ParseMirrorRefspecs), all
+	// references are cloned when empty.
+	MirrorRefspecs string
+	// Whether to fetch LFS objects from upstream for mirrors.
+	MirrorLFS bool
 }
 
 /*
@@ -782,11 +805,16 @@ func MigrateRepository(doer, owner *User, opts MigrateRepoOptions) (*Repository,
 	migrateTimeout := time.Duration(conf.Git.Timeout.Migrate) * time.Second
 
 	RemoveAllWithNotice("Repository path erase before creation", repoPath)
-	if err = git.Clone(opts.RemoteAddr, repoPath, git.CloneOptions{
-		Mirror:  true,
-		Quiet:   true,
-		Timeout: migrateTimeout,
-	}); err != nil {
+	if opts.IsMirror && opts.MirrorRefspecs != "" {
+		err = cloneMirrorRefspecs(opts.RemoteAddr, repoPath, strings.Fields(opts.MirrorRefspecs), migrateTimeout)
+	} else {
+		err = git.Clone(opts.RemoteAddr, repoPath, git.CloneOptions{
+			Mirror:  true,
+			Quiet:   true,
+			Timeout: migrateTimeout,
+		})
+	}
+	if err != nil {
 		return repo, fmt.Errorf("clone: %v", err)
 	}
 
@@ -835,16 +863,28 @@ func MigrateRepository(doer, owner *User, opts MigrateRepoOptions) (*Repository,
 			RepoID:      repo.ID,
 			Interval:    conf.Mirror.DefaultInterval,
 			EnablePrune: true,
+			Refspecs:    opts.MirrorRefspecs,
+			EnableLFS:   opts.MirrorLFS,
 			NextSync:    time.Now().Add(time.Duration(conf.Mirror.DefaultInterval) * time.Hour),
 		}); err != nil {
 			return repo, fmt.Errorf("InsertOne: %v", err)
 		}
 
 		repo.IsMirror = true
-		return repo, UpdateRepository(repo, false)
+		if err = UpdateRepository(repo, false); err != nil {
+			return repo, err
+		}
+
+		// LFS objects are fetched by a sync to not block the migration
+		if opts.MirrorLFS {
+			go QueueMirrorSync(repo.ID)
+		}
+	} else if repo, err = CleanUpMigrateInfo(repo); err != nil {
+		return repo, err
 	}
 
-	return CleanUpMigrateInfo(repo)
+	UpdateCodeIndexer(repo.ID)
+	return repo, nil
 }
 
 // cleanUpMigrateGitConfig removes mirror info which prevents "push --all".
@@ -1553,6 +1593,10 @@ func updateRepository(e Engine, repo *Repository, visibilityChanged bool) (err e
 		if _, err = e.Where("repo_id = ?", repo.ID).Cols("is_private").Update(&Action{IsPrivate: repo.IsPrivate || repo.IsUnlisted}); err != nil {
 			return fmt.Errorf("change action visibility of repository: %v", err)
 		}
+
+		if err = invalidateRepoUnreadCounts(e, repo.ID); err != nil {
+			return fmt.Errorf("invalidate unread notification counts: %v", err)
+		}
 	}
 
 	return nil
@@ -1616,6 +1660,7 @@ func DeleteRepository(ownerID, repoID int64) error {
 		&Watch{RepoID: repoID},
 		&Star{RepoID: repoID},
 		&Mirror{RepoID: repoID},
+		&PushMirror{RepoID: repoID},
 		&IssueUser{RepoID: repoID},
 		&Milestone{RepoID: repoID},
 		&Release{RepoID: repoID},
@@ -1625,7 +1670,8 @@ func DeleteRepository(ownerID, repoID int64) error {
 		&ProtectBranchWhitelist{RepoID: repoID},
 		&Webhook{RepoID: repoID},
 		&HookTask{RepoID: repoID},
-		&LFSObject{RepoID: repoID},
+		&LFSLock{RepoID: repoID},
+		&Notification{RepoID: repoID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
@@ -1683,6 +1729,11 @@ func DeleteRepository(ownerID, repoID int64) error {
 		RemoveAllWithNotice("Delete attachment", attachmentPaths[i])
 	}
 
+	for i := range issues {
+		UpdateIssueIndexer(issues[i].ID)
+	}
+	UpdateCodeIndexer(repo.ID)
+
 	if repo.NumForks > 0 {
 		if _, err = x.Exec("UPDATE `repository` SET fork_id=0,is_fork=? WHERE fork_id=?", false, repo.ID); err != nil {
 			log.Error("reset 'fork_id' and 'is_fork': %v", err)
@@ -2593,6 +2644,8 @@ func (repo *Repository) refreshAccesses(e Engine, accessMap map[int64]AccessMode
 		return fmt.Errorf("delete old accesses: %v", err)
 	} else if _, err = e.Insert(newAccesses); err != nil {
 		return fmt.Errorf("insert new accesses: %v", err)
+	} else if err = invalidateRepoUnreadCounts(e, repo.ID); err != nil {
+		return fmt.Errorf("invalidate unread notification counts: %v", err)
 	}
 	return nil
 }
@@ -110,6 +110,32 @@ type ProtectBranch struct {
 	EnableWhitelist    bool
 	WhitelistUserIDs   string `xorm:"TEXT"`
 	WhitelistTeamIDs   string `xorm:"TEXT"`
+
+	// Merge requirements of pull requests
+	EnableStatusCheck     bool
+	StatusCheckContexts   string `xorm:"TEXT"`
+	RequiredApprovals     int
+	DismissStaleApprovals bool
+}
+
+// RequiredStatusCheckContexts returns the list of commit status contexts that
+// must be successful before a pull request can be merged into the branch.
+func (protectBranch *ProtectBranch) RequiredStatusCheckContexts() []string {
+	if !protectBranch.EnableStatusCheck {
+		return nil
+	}
+
+	fields := strings.FieldsFunc(protectBranch.StatusCheckContexts, func(r rune) bool {
+		return r == ',' || r == '\n'
+	})
+	contexts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			contexts = append(contexts, field)
+		}
+	}
+	return contexts
 }
 
 // GetProtectBranchOfRepoByName returns *ProtectBranch by branch name in given repository.
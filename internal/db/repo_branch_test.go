@@ -0,0 +1,84 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtectBranch_RequiredStatusCheckContexts(t *testing.T) {
+	tests := []struct {
+		name          string
+		protectBranch *ProtectBranch
+		want          []string
+	}{
+		{
+			name: "status check disabled",
+			protectBranch: &ProtectBranch{
+				StatusCheckContexts: "ci/build",
+			},
+			want: nil,
+		},
+		{
+			name: "separated by comma and new line",
+			protectBranch: &ProtectBranch{
+				EnableStatusCheck:   true,
+				StatusCheckContexts: "ci/build, ci/lint\r\n\nci/test ,",
+			},
+			want: []string{"ci/build", "ci/lint", "ci/test"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.protectBranch.RequiredStatusCheckContexts())
+		})
+	}
+}
+
+func TestPullRequestProtectionCheck_IsSatisfied(t *testing.T) {
+	tests := []struct {
+		name  string
+		check *PullRequestProtectionCheck
+		want  bool
+	}{
+		{
+			name:  "no requirements",
+			check: &PullRequestProtectionCheck{},
+			want:  true,
+		},
+		{
+			name: "missing status checks",
+			check: &PullRequestProtectionCheck{
+				RequiredContexts: []string{"ci/build"},
+				MissingContexts:  []string{"ci/build"},
+			},
+			want: false,
+		},
+		{
+			name: "not enough approvals",
+			check: &PullRequestProtectionCheck{
+				RequiredApprovals: 2,
+				Approvals:         1,
+			},
+			want: false,
+		},
+		{
+			name: "all requirements met",
+			check: &PullRequestProtectionCheck{
+				RequiredContexts:  []string{"ci/build"},
+				RequiredApprovals: 1,
+				Approvals:         1,
+			},
+			want: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.check.IsSatisfied())
+		})
+	}
+}
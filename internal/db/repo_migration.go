@@ -0,0 +1,278 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/migrate"
+	"gogs.io/gogs/internal/netutil"
+)
+
+// splitCloneAddrCredentials returns the clone address without credentials, and
+// the username and password in the address.
+func splitCloneAddrCredentials(addr string) (string, string, string) {
+	u, err := url.Parse(addr)
+	if err != nil || u.User == nil {
+		return addr, "", ""
+	}
+
+	username := u.User.Username()
+	password, _ := u.User.Password()
+	u.User = nil
+	return u.String(), username, password
+}
+
+// CreateRepoMigration checks whether the repository can be created for the
+// owner, and queues the migration to be run in the background. Credentials in
+// the clone address are stored separately.
+func CreateRepoMigration(ctx context.Context, doer, owner *User, opts CreateRepoMigrationOptions) (*RepoMigration, error) {
+	if !owner.CanCreateRepo() {
+		return nil, ErrReachLimitOfRepo{Limit: owner.RepoCreationNum()}
+	} else if err := isRepoNameAllowed(opts.RepoName); err != nil {
+		return nil, err
+	}
+
+	exist, err := IsRepositoryExist(owner, opts.RepoName)
+	if err != nil {
+		return nil, errors.Wrap(err, "check repository existence")
+	} else if !exist {
+		exist, err = RepoMigrations.HasUnfinished(ctx, owner.ID, opts.RepoName)
+		if err != nil {
+			return nil, errors.Wrap(err, "check unfinished migrations")
+		}
+	}
+	if exist {
+		return nil, ErrRepoAlreadyExist{args: errutil.Args{"ownerID": owner.ID, "name": opts.RepoName}}
+	}
+
+	opts.CloneAddr, opts.AuthUsername, opts.AuthPassword = splitCloneAddrCredentials(opts.CloneAddr)
+	if opts.Service == "" {
+		opts.Service = migrate.ServiceGit
+	}
+	if opts.Service == migrate.ServiceGit {
+		opts.AuthToken = ""
+		opts.WithLabels = false
+		opts.WithMilestones = false
+		opts.WithIssues = false
+		opts.WithPullRequests = false
+		opts.WithReleases = false
+	} else if _, err = migrate.NewDownloader(opts.Service, migrate.Options{CloneAddr: opts.CloneAddr}); err != nil {
+		return nil, ErrInvalidCloneAddr{IsURLError: true}
+	}
+	// Mirrors do not accept pull requests.
+	if opts.IsMirror {
+		opts.WithPullRequests = false
+	}
+
+	m, err := RepoMigrations.Create(ctx, doer.ID, owner.ID, conf.Security.SecretKey, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "create migration")
+	}
+	QueueRepoMigration(m.ID)
+	return m, nil
+}
+
+// WithMetadata returns true if any metadata is migrated in addition to the Git
+// data.
+func (m *RepoMigration) WithMetadata() bool {
+	return m.WithLabels || m.WithMilestones || m.WithIssues || m.WithPullRequests || m.WithReleases
+}
+
+// remoteAddr returns the clone address with credentials. The token is used as
+// the password when no username and password are given, which is accepted by
+// all supported services.
+func (m *RepoMigration) remoteAddr(password, token string) string {
+	u, err := url.Parse(m.CloneAddr)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "git") {
+		return m.CloneAddr
+	}
+
+	switch {
+	case m.AuthUsername != "" || password != "":
+		u.User = url.UserPassword(m.AuthUsername, password)
+	case token != "":
+		u.User = url.UserPassword("oauth2", token)
+	}
+	return u.String()
+}
+
+// sanitizeRepoMigrationMessage removes credentials from the error message.
+func sanitizeRepoMigrationMessage(message string, secrets ...string) string {
+	message = HandleMirrorCredentials(message, true)
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		message = strings.ReplaceAll(message, secret, "<credentials>")
+		message = strings.ReplaceAll(message, url.QueryEscape(secret), "<credentials>")
+		message = strings.ReplaceAll(message, url.PathEscape(secret), "<credentials>")
+	}
+	return message
+}
+
+// RunRepoMigrations runs repository migrations in the queue.
+func RunRepoMigrations() {
+	consumeQueue(RepoMigrationQueue, runRepoMigration, nil)
+}
+
+func InitRepoMigrations() {
+	go RunRepoMigrations()
+}
+
+// runRepoMigration runs the repository migration and records the result. A
+// failed migration is not retried because it is unlikely to succeed without
+// changes (e.g. credentials), the user should start a new one instead.
+func runRepoMigration(id int64) error {
+	ctx := context.Background()
+	log.Trace("RunRepoMigration [id: %d]", id)
+
+	m, err := RepoMigrations.GetByID(ctx, id)
+	if err != nil {
+		if IsErrRepoMigrationNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "get migration")
+	}
+
+	switch m.Status {
+	case RepoMigrationStatusSucceeded, RepoMigrationStatusFailed:
+		return nil
+	case RepoMigrationStatusRunning:
+		// The migration may still be running by another process if it has made
+		// progress recently, the task is then retried later.
+		if time.Since(m.UpdatedAt) < conf.Queue.VisibilityTimeout {
+			return errors.New("migration is still running")
+		}
+		// Otherwise it has been interrupted (e.g. by a restart), start over.
+		m.deleteRepository()
+	}
+
+	if err = RepoMigrations.Start(ctx, m.ID); err != nil {
+		return errors.Wrap(err, "start migration")
+	}
+	m.RepoID = 0
+
+	password, token, err := m.DecryptCredentials(conf.Security.SecretKey)
+	if err != nil {
+		return RepoMigrations.Finish(ctx, m.ID, "Failed to decrypt credentials: "+err.Error())
+	}
+
+	// Cloning and waiting for rate limits may take longer than the visibility
+	// timeout without making progress, keep telling others it is still alive.
+	stopHeartbeat := m.heartbeat(ctx, conf.Queue.VisibilityTimeout/3)
+	var message string
+	err = m.run(ctx, password, token)
+	stopHeartbeat()
+	if err != nil {
+		message = sanitizeRepoMigrationMessage(err.Error(), password, token)
+		log.Error("Failed to migrate repository [migration_id: %d]: %s", m.ID, message)
+		m.deleteRepository()
+	}
+	if err = RepoMigrations.Finish(ctx, m.ID, message); err != nil {
+		return errors.Wrap(err, "finish migration")
+	}
+	return nil
+}
+
+// heartbeat touches the migration at given interval until the returned function
+// is called.
+func (m *RepoMigration) heartbeat(ctx context.Context, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := RepoMigrations.Touch(ctx, m.ID); err != nil {
+					log.Error("Failed to touch migration [id: %d]: %v", m.ID, err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// deleteRepository deletes the repository created by the migration if any.
+func (m *RepoMigration) deleteRepository() {
+	if m.RepoID <= 0 {
+		return
+	}
+
+	if err := DeleteRepository(m.OwnerID, m.RepoID); err != nil {
+		log.Error("Failed to delete repository of migration [migration_id: %d, repo_id: %d]: %v", m.ID, m.RepoID, err)
+	}
+}
+
+// run migrates the Git data and then the metadata of the repository.
+func (m *RepoMigration) run(ctx context.Context, password, token string) error {
+	doer, err := Users.GetByID(ctx, m.DoerID)
+	if err != nil {
+		return errors.Wrap(err, "get doer")
+	}
+	owner, err := Users.GetByID(ctx, m.OwnerID)
+	if err != nil {
+		return errors.Wrap(err, "get owner")
+	}
+
+	repo, err := MigrateRepository(doer, owner, MigrateRepoOptions{
+		Name:           m.RepoName,
+		Description:    m.Description,
+		IsPrivate:      m.IsPrivate,
+		IsUnlisted:     m.IsUnlisted,
+		IsMirror:       m.IsMirror,
+		RemoteAddr:     m.remoteAddr(password, token),
+		MirrorRefspecs: m.MirrorRefspecs,
+		MirrorLFS:      m.MirrorLFS,
+	})
+	if repo != nil && repo.ID > 0 {
+		m.RepoID = repo.ID
+		if err := RepoMigrations.SetRepoID(ctx, m.ID, repo.ID); err != nil {
+			return errors.Wrap(err, "set repository ID")
+		}
+	}
+	if err != nil {
+		return err
+	} else if !m.WithMetadata() {
+		return nil
+	}
+
+	d, err := migrate.NewDownloader(m.Service, migrate.Options{
+		CloneAddr: m.CloneAddr,
+		Username:  m.AuthUsername,
+		Password:  password,
+		Token:     token,
+		AllowURL: func(u *url.URL) bool {
+			return !netutil.IsBlockedLocalHostname(u.Hostname(), conf.Security.LocalNetworkAllowlist)
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "new downloader")
+	}
+
+	repo.Owner = owner
+	return newRepoImporter(m, doer, repo, d).run(ctx)
+}
@@ -0,0 +1,721 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+	gouuid "github.com/satori/go.uuid"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/migrate"
+)
+
+// repoImporter imports metadata of a migrated repository from the code hosting
+// service. Records are inserted directly without sending notifications or
+// webhooks, as if they were created locally in the first place.
+type repoImporter struct {
+	migration  *RepoMigration
+	doer       *User
+	repo       *Repository
+	downloader migrate.Downloader
+
+	// Local user IDs by lowercased emails, zero means there is no local user with
+	// the email.
+	userIDs      map[string]int64
+	labelIDs     map[string]int64 // By names.
+	milestoneIDs map[string]int64 // By titles.
+}
+
+func newRepoImporter(m *RepoMigration, doer *User, repo *Repository, d migrate.Downloader) *repoImporter {
+	return &repoImporter{
+		migration:    m,
+		doer:         doer,
+		repo:         repo,
+		downloader:   d,
+		userIDs:      make(map[string]int64),
+		labelIDs:     make(map[string]int64),
+		milestoneIDs: make(map[string]int64),
+	}
+}
+
+func (im *repoImporter) run(ctx context.Context) error {
+	if im.migration.WithLabels {
+		if err := im.importLabels(ctx); err != nil {
+			return errors.Wrap(err, "import labels")
+		}
+	}
+	if im.migration.WithMilestones {
+		if err := im.importMilestones(ctx); err != nil {
+			return errors.Wrap(err, "import milestones")
+		}
+	}
+	if im.migration.WithIssues || im.migration.WithPullRequests {
+		if err := im.importIssues(ctx); err != nil {
+			return errors.Wrap(err, "import issues")
+		}
+	}
+	if im.migration.WithReleases {
+		if err := im.importReleases(ctx); err != nil {
+			return errors.Wrap(err, "import releases")
+		}
+	}
+	return errors.Wrap(im.updateCounters(), "update counters")
+}
+
+func (im *repoImporter) updateProgress(ctx context.Context, stage RepoMigrationStage, progress int) error {
+	return RepoMigrations.UpdateProgress(ctx, im.migration.ID, stage, progress)
+}
+
+// userID returns the ID of the local user who has the email of given user, or
+// zero if there is none.
+func (im *repoImporter) userID(ctx context.Context, u migrate.User) (int64, error) {
+	if u.Email == "" {
+		return 0, nil
+	}
+
+	email := strings.ToLower(u.Email)
+	if id, ok := im.userIDs[email]; ok {
+		return id, nil
+	}
+
+	var id int64
+	user, err := Users.GetByEmail(ctx, email)
+	if err == nil {
+		id = user.ID
+	} else if !IsErrUserNotExist(err) {
+		return 0, errors.Wrapf(err, "get user by email %q", email)
+	}
+	im.userIDs[email] = id
+	return id, nil
+}
+
+// poster returns the ID of the local user for the poster and the content to be
+// saved. The doer becomes the poster when there is no local user for the
+// poster, and the original poster is noted at the beginning of the content.
+func (im *repoImporter) poster(ctx context.Context, u migrate.User, content string) (int64, string, error) {
+	id, err := im.userID(ctx, u)
+	if err != nil {
+		return 0, "", err
+	} else if id > 0 {
+		return id, content, nil
+	}
+
+	name := u.Name
+	if name == "" {
+		name = "ghost"
+	}
+	// The name is not mentioned with "@" so that local users with the same name
+	// are not notified.
+	note := fmt.Sprintf("*Originally posted by %s on %s*", name, im.migration.Service.DisplayName())
+	if content == "" {
+		return im.doer.ID, note, nil
+	}
+	return im.doer.ID, note + "\n\n" + content, nil
+}
+
+func (im *repoImporter) importLabels(ctx context.Context) error {
+	if err := im.updateProgress(ctx, RepoMigrationStageLabels, 0); err != nil {
+		return errors.Wrap(err, "update progress")
+	}
+
+	labels, err := im.downloader.Labels(ctx)
+	if err != nil {
+		return errors.Wrap(err, "download")
+	}
+
+	for i, l := range labels {
+		if _, ok := im.labelIDs[l.Name]; ok {
+			continue
+		}
+
+		label := &Label{
+			RepoID: im.repo.ID,
+			Name:   l.Name,
+			Color:  l.Color,
+		}
+		if _, err = x.Insert(label); err != nil {
+			return errors.Wrapf(err, "insert %q", l.Name)
+		}
+		im.labelIDs[l.Name] = label.ID
+
+		if err = im.updateProgress(ctx, RepoMigrationStageLabels, i+1); err != nil {
+			return errors.Wrap(err, "update progress")
+		}
+	}
+	return nil
+}
+
+func (im *repoImporter) importMilestones(ctx context.Context) error {
+	if err := im.updateProgress(ctx, RepoMigrationStageMilestones, 0); err != nil {
+		return errors.Wrap(err, "update progress")
+	}
+
+	milestones, err := im.downloader.Milestones(ctx)
+	if err != nil {
+		return errors.Wrap(err, "download")
+	}
+
+	// Milestones without deadlines are saved with the same placeholder as the
+	// ones created in the web UI.
+	noDeadline, _ := time.ParseInLocation("2006-01-02", "9999-12-31", time.Local)
+	for i, m := range milestones {
+		if _, ok := im.milestoneIDs[m.Title]; ok {
+			continue
+		}
+
+		milestone := &Milestone{
+			RepoID:   im.repo.ID,
+			Name:     m.Title,
+			Content:  m.Description,
+			IsClosed: m.IsClosed,
+			Deadline: noDeadline,
+		}
+		if m.Deadline != nil {
+			milestone.Deadline = *m.Deadline
+		}
+		if m.Closed != nil {
+			milestone.ClosedDateUnix = m.Closed.Unix()
+		}
+		if _, err = x.Insert(milestone); err != nil {
+			return errors.Wrapf(err, "insert %q", m.Title)
+		}
+		im.milestoneIDs[m.Title] = milestone.ID
+
+		if err = im.updateProgress(ctx, RepoMigrationStageMilestones, i+1); err != nil {
+			return errors.Wrap(err, "update progress")
+		}
+	}
+	return nil
+}
+
+// importItem is an issue or a pull request to be imported.
+type importItem struct {
+	issue *migrate.Issue
+	pull  *migrate.PullRequest // Nil for an issue.
+}
+
+// importIssueIndexes returns local numbers of items sorted in the order of
+// creation. Items keep their numbers of the source unless the number is taken
+// by an earlier item, because issues and pull requests share the same sequence
+// of numbers locally, which is not the case for all services. Those items are
+// numbered after the largest number in the order of creation.
+func importIssueIndexes(items []importItem) []int64 {
+	taken := make(map[int64]bool, len(items))
+	var max int64
+	for _, item := range items {
+		if item.issue.Number > max {
+			max = item.issue.Number
+		}
+	}
+
+	indexes := make([]int64, len(items))
+	for i, item := range items {
+		number := item.issue.Number
+		if number > 0 && !taken[number] {
+			indexes[i] = number
+			taken[number] = true
+		}
+	}
+	for i := range indexes {
+		if indexes[i] == 0 {
+			max++
+			indexes[i] = max
+		}
+	}
+	return indexes
+}
+
+// importIssues imports issues and pull requests with their comments, see
+// importIssueIndexes for how they are numbered.
+func (im *repoImporter) importIssues(ctx context.Context) error {
+	if err := im.updateProgress(ctx, RepoMigrationStageIssues, 0); err != nil {
+		return errors.Wrap(err, "update progress")
+	}
+
+	var items []importItem
+	if im.migration.WithIssues {
+		for page := 1; ; page++ {
+			issues, isLast, err := im.downloader.Issues(ctx, page)
+			if err != nil {
+				return errors.Wrapf(err, "download issues page %d", page)
+			}
+			for _, issue := range issues {
+				items = append(items, importItem{issue: issue})
+			}
+			if isLast {
+				break
+			}
+		}
+	}
+	if im.migration.WithPullRequests {
+		for page := 1; ; page++ {
+			pulls, isLast, err := im.downloader.PullRequests(ctx, page)
+			if err != nil {
+				return errors.Wrapf(err, "download pull requests page %d", page)
+			}
+			for _, pull := range pulls {
+				items = append(items, importItem{issue: &pull.Issue, pull: pull})
+			}
+			if isLast {
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].issue, items[j].issue
+		if !a.Created.Equal(b.Created) {
+			return a.Created.Before(b.Created)
+		}
+		return a.Number < b.Number
+	})
+
+	gitRepo, err := git.Open(im.repo.RepoPath())
+	if err != nil {
+		return errors.Wrap(err, "open repository")
+	}
+
+	indexes := importIssueIndexes(items)
+	for i, item := range items {
+		if err = im.importIssue(ctx, gitRepo, indexes[i], item); err != nil {
+			return errors.Wrapf(err, "import #%d", item.issue.Number)
+		}
+
+		if err = im.updateProgress(ctx, RepoMigrationStageIssues, i+1); err != nil {
+			return errors.Wrap(err, "update progress")
+		}
+	}
+	return nil
+}
+
+// updatedUnix returns the Unix time of the update, or of the creation if it has
+// not been updated.
+func updatedUnix(created, updated time.Time) int64 {
+	if updated.IsZero() {
+		return created.Unix()
+	}
+	return updated.Unix()
+}
+
+func (im *repoImporter) importIssue(ctx context.Context, gitRepo *git.Repository, index int64, item importItem) error {
+	src := item.issue
+	posterID, content, err := im.poster(ctx, src.Poster, src.Content)
+	if err != nil {
+		return errors.Wrap(err, "get poster")
+	}
+
+	var comments []*migrate.Comment
+	if src.NumComments != 0 {
+		comments, err = im.downloader.Comments(ctx, src.Number, item.pull != nil)
+		if err != nil {
+			return errors.Wrap(err, "download comments")
+		}
+	}
+
+	issue := &Issue{
+		RepoID:      im.repo.ID,
+		Index:       index,
+		PosterID:    posterID,
+		Title:       src.Title,
+		Content:     content,
+		MilestoneID: im.milestoneIDs[src.Milestone],
+		// Pull requests are imported as records, they cannot be merged locally.
+		IsClosed:    src.IsClosed || item.pull != nil,
+		IsPull:      item.pull != nil,
+		NumComments: len(comments),
+	}
+
+	var pr *PullRequest
+	if item.pull != nil {
+		pr, err = im.newPullRequest(gitRepo, index, item.pull)
+		if err != nil {
+			return errors.Wrap(err, "new pull request")
+		}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err = sess.Insert(issue); err != nil {
+		return errors.Wrap(err, "insert issue")
+	}
+	// Timestamps are always set to the current time on insert.
+	if _, err = sess.Exec("UPDATE `issue` SET created_unix = ?, updated_unix = ? WHERE id = ?",
+		src.Created.Unix(), updatedUnix(src.Created, src.Updated), issue.ID); err != nil {
+		return errors.Wrap(err, "update issue timestamps")
+	}
+
+	labeled := make(map[int64]bool, len(src.Labels))
+	for _, name := range src.Labels {
+		labelID, ok := im.labelIDs[name]
+		if !ok || labeled[labelID] {
+			continue
+		}
+		if _, err = sess.Insert(&IssueLabel{IssueID: issue.ID, LabelID: labelID}); err != nil {
+			return errors.Wrap(err, "insert issue label")
+		}
+		labeled[labelID] = true
+	}
+
+	if err = newIssueUsers(sess, im.repo, issue); err != nil {
+		return errors.Wrap(err, "new issue users")
+	}
+
+	if pr != nil {
+		pr.IssueID = issue.ID
+		if _, err = sess.Insert(pr); err != nil {
+			return errors.Wrap(err, "insert pull request")
+		}
+	}
+
+	for _, c := range comments {
+		posterID, content, err := im.poster(ctx, c.Poster, c.Content)
+		if err != nil {
+			return errors.Wrap(err, "get comment poster")
+		}
+
+		comment := &Comment{
+			Type:     COMMENT_TYPE_COMMENT,
+			PosterID: posterID,
+			IssueID:  issue.ID,
+			Content:  content,
+		}
+		if _, err = sess.Insert(comment); err != nil {
+			return errors.Wrap(err, "insert comment")
+		}
+		if _, err = sess.Exec("UPDATE `comment` SET created_unix = ?, updated_unix = ? WHERE id = ?",
+			c.Created.Unix(), updatedUnix(c.Created, c.Updated), comment.ID); err != nil {
+			return errors.Wrap(err, "update comment timestamps")
+		}
+	}
+
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	UpdateIssueIndexer(issue.ID)
+	return nil
+}
+
+// isCommitSHA returns true if given string is a full SHA1 commit ID.
+func isCommitSHA(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	for _, r := range s {
+		if !('0' <= r && r <= '9') && !('a' <= r && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// hasCommit returns true if the commit with given SHA exists in the repository.
+func hasCommit(gitRepo *git.Repository, sha string) bool {
+	if !isCommitSHA(sha) {
+		return false
+	}
+	_, err := gitRepo.CatFileCommit(sha)
+	return err == nil
+}
+
+// newPullRequest returns the record of the pull request. The head commit is
+// kept with the reference of the pull request when it exists locally, and the
+// pull request is only recorded as merged when its merge commit exists locally.
+func (im *repoImporter) newPullRequest(gitRepo *git.Repository, index int64, src *migrate.PullRequest) (*PullRequest, error) {
+	pr := &PullRequest{
+		Type:         PULL_REQUEST_GOGS,
+		Status:       PULL_REQUEST_STATUS_MERGEABLE,
+		Index:        index,
+		HeadRepoID:   im.repo.ID,
+		BaseRepoID:   im.repo.ID,
+		HeadUserName: im.repo.Owner.Name,
+		HeadBranch:   src.Head.Ref,
+		BaseBranch:   src.Base.Ref,
+	}
+	if pr.BaseBranch == "" {
+		pr.BaseBranch = im.repo.DefaultBranch
+	}
+
+	hasHead := hasCommit(gitRepo, src.Head.SHA)
+	if hasHead {
+		ref := fmt.Sprintf("refs/pull/%d/head", index)
+		_, err := git.NewCommand("update-ref", ref, src.Head.SHA).RunInDir(gitRepo.Path())
+		if err != nil {
+			return nil, errors.Wrapf(err, "update reference %q", ref)
+		}
+	}
+
+	if !src.IsMerged || !hasCommit(gitRepo, src.MergeCommitSHA) || !hasCommit(gitRepo, src.Base.SHA) {
+		return pr, nil
+	}
+
+	pr.MergeBase = src.Base.SHA
+	if hasHead {
+		mergeBase, err := gitRepo.MergeBase(src.Base.SHA, src.Head.SHA)
+		if err == nil {
+			pr.MergeBase = mergeBase
+		}
+	}
+	pr.HasMerged = true
+	pr.MergedCommitID = src.MergeCommitSHA
+	// The merger is unknown, which is shown as a ghost.
+	pr.MergerID = -1
+	if src.Merged != nil {
+		pr.MergedUnix = src.Merged.Unix()
+	}
+	return pr, nil
+}
+
+func (im *repoImporter) importReleases(ctx context.Context) error {
+	if err := im.updateProgress(ctx, RepoMigrationStageReleases, 0); err != nil {
+		return errors.Wrap(err, "update progress")
+	}
+
+	releases, err := im.downloader.Releases(ctx)
+	if err != nil {
+		return errors.Wrap(err, "download")
+	}
+
+	gitRepo, err := git.Open(im.repo.RepoPath())
+	if err != nil {
+		return errors.Wrap(err, "open repository")
+	}
+
+	for i, r := range releases {
+		if err = im.importRelease(ctx, gitRepo, r); err != nil {
+			return errors.Wrapf(err, "import %q", r.TagName)
+		}
+
+		if err = im.updateProgress(ctx, RepoMigrationStageReleases, i+1); err != nil {
+			return errors.Wrap(err, "update progress")
+		}
+	}
+	return nil
+}
+
+// importRelease imports the release and its assets. A published release is
+// imported as a draft if its tag does not exist locally. Assets that failed to
+// be imported are reported as system notices instead of failing the migration.
+func (im *repoImporter) importRelease(ctx context.Context, gitRepo *git.Repository, src *migrate.Release) error {
+	exist, err := IsReleaseExist(im.repo.ID, src.TagName)
+	if err != nil {
+		return errors.Wrap(err, "check existence")
+	} else if exist || src.TagName == "" {
+		return nil
+	}
+
+	publisherID, note, err := im.poster(ctx, src.Publisher, src.Body)
+	if err != nil {
+		return errors.Wrap(err, "get publisher")
+	}
+
+	r := &Release{
+		RepoID:       im.repo.ID,
+		PublisherID:  publisherID,
+		TagName:      src.TagName,
+		LowerTagName: strings.ToLower(src.TagName),
+		Target:       src.Target,
+		Title:        src.Name,
+		Note:         note,
+		IsDraft:      src.IsDraft,
+		IsPrerelease: src.IsPrerelease,
+		CreatedUnix:  src.Created.Unix(),
+	}
+	if r.Target == "" {
+		r.Target = im.repo.DefaultBranch
+	}
+	if r.Title == "" {
+		r.Title = src.TagName
+	}
+
+	if !r.IsDraft {
+		if gitRepo.HasTag(r.TagName) {
+			commit, err := gitRepo.TagCommit(r.TagName)
+			if err != nil {
+				return errors.Wrap(err, "get tag commit")
+			}
+
+			r.Sha1 = commit.ID.String()
+			r.NumCommits, err = commit.CommitsCount()
+			if err != nil {
+				return errors.Wrap(err, "count commits")
+			}
+		} else {
+			r.IsDraft = true
+		}
+	}
+
+	if _, err = x.Insert(r); err != nil {
+		return errors.Wrap(err, "insert")
+	}
+
+	if !conf.Release.Attachment.Enabled {
+		return nil
+	}
+
+	for i, asset := range src.Assets {
+		if conf.Release.Attachment.MaxFiles > 0 && i >= conf.Release.Attachment.MaxFiles {
+			break
+		}
+
+		if err = im.importReleaseAsset(ctx, r, asset); err != nil {
+			desc := fmt.Sprintf("Failed to migrate asset %q of release %q [repo_id: %d]: %v", asset.Name, src.TagName, im.repo.ID, err)
+			log.Warn(desc)
+			if err = CreateRepositoryNotice(desc); err != nil {
+				log.Error("CreateRepositoryNotice: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// importReleaseAsset downloads the asset as an attachment of the release, with
+// the same restrictions of size and file types as uploaded attachments.
+func (im *repoImporter) importReleaseAsset(ctx context.Context, r *Release, asset *migrate.ReleaseAsset) (err error) {
+	maxSize := conf.Release.Attachment.MaxSize << 20
+	if asset.Size > maxSize {
+		return errors.Errorf("size %d exceeds the maximum of %d", asset.Size, maxSize)
+	}
+
+	rc, err := im.downloader.DownloadAsset(ctx, asset)
+	if err != nil {
+		return errors.Wrap(err, "download")
+	}
+	defer func() { _ = rc.Close() }()
+
+	buf := make([]byte, 1024)
+	n, err := io.ReadFull(rc, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return errors.Wrap(err, "read")
+	}
+	buf = buf[:n]
+
+	fileType := http.DetectContentType(buf)
+	allowed := false
+	for _, t := range conf.Release.Attachment.AllowedTypes {
+		t := strings.Trim(t, " ")
+		if t == "*/*" || t == fileType {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return errors.Errorf("file type %q is not allowed", fileType)
+	}
+
+	attach := &Attachment{
+		UUID:      gouuid.NewV4().String(),
+		ReleaseID: r.ID,
+		Name:      asset.Name,
+	}
+
+	localPath := attach.LocalPath()
+	if err = os.MkdirAll(path.Dir(localPath), os.ModePerm); err != nil {
+		return errors.Wrap(err, "create directories")
+	}
+
+	fw, err := os.Create(localPath)
+	if err != nil {
+		return errors.Wrap(err, "create file")
+	}
+	defer func() {
+		_ = fw.Close()
+		if err != nil {
+			_ = os.Remove(localPath)
+		}
+	}()
+
+	if _, err = fw.Write(buf); err != nil {
+		return errors.Wrap(err, "write")
+	}
+	written, err := io.Copy(fw, io.LimitReader(rc, maxSize-int64(n)+1))
+	if err != nil {
+		return errors.Wrap(err, "copy")
+	} else if int64(n)+written > maxSize {
+		return errors.Errorf("size exceeds the maximum of %d", maxSize)
+	}
+
+	if _, err = x.Insert(attach); err != nil {
+		return errors.Wrap(err, "insert")
+	}
+	return nil
+}
+
+// updateCounters recalculates the numbers of issues, pull requests and
+// milestones of the repository, and the numbers of issues of its labels and
+// milestones.
+func (im *repoImporter) updateCounters() error {
+	repoID := im.repo.ID
+	_, err := x.Exec("UPDATE `repository` SET "+
+		"num_issues = (SELECT COUNT(*) FROM `issue` WHERE repo_id = ? AND is_pull = ?), "+
+		"num_closed_issues = (SELECT COUNT(*) FROM `issue` WHERE repo_id = ? AND is_pull = ? AND is_closed = ?), "+
+		"num_pulls = (SELECT COUNT(*) FROM `issue` WHERE repo_id = ? AND is_pull = ?), "+
+		"num_closed_pulls = (SELECT COUNT(*) FROM `issue` WHERE repo_id = ? AND is_pull = ? AND is_closed = ?), "+
+		"num_milestones = (SELECT COUNT(*) FROM `milestone` WHERE repo_id = ?), "+
+		"num_closed_milestones = (SELECT COUNT(*) FROM `milestone` WHERE repo_id = ? AND is_closed = ?) "+
+		"WHERE id = ?",
+		repoID, false,
+		repoID, false, true,
+		repoID, true,
+		repoID, true, true,
+		repoID,
+		repoID, true,
+		repoID,
+	)
+	if err != nil {
+		return errors.Wrap(err, "repository")
+	}
+
+	_, err = x.Exec("UPDATE `label` SET "+
+		"num_issues = (SELECT COUNT(*) FROM `issue_label` WHERE label_id = `label`.id), "+
+		"num_closed_issues = (SELECT COUNT(*) FROM `issue_label` INNER JOIN `issue` ON `issue`.id = `issue_label`.issue_id WHERE `issue_label`.label_id = `label`.id AND `issue`.is_closed = ?) "+
+		"WHERE repo_id = ?",
+		true, repoID,
+	)
+	if err != nil {
+		return errors.Wrap(err, "labels")
+	}
+
+	_, err = x.Exec("UPDATE `milestone` SET "+
+		"num_issues = (SELECT COUNT(*) FROM `issue` WHERE milestone_id = `milestone`.id), "+
+		"num_closed_issues = (SELECT COUNT(*) FROM `issue` WHERE milestone_id = `milestone`.id AND is_closed = ?) "+
+		"WHERE repo_id = ?",
+		true, repoID,
+	)
+	if err != nil {
+		return errors.Wrap(err, "milestones")
+	}
+
+	milestones := make([]*Milestone, 0, len(im.milestoneIDs))
+	if err = x.Where("repo_id = ?", repoID).Find(&milestones); err != nil {
+		return errors.Wrap(err, "find milestones")
+	}
+	for _, m := range milestones {
+		completeness := 0
+		if m.NumIssues > 0 {
+			completeness = m.NumClosedIssues * 100 / m.NumIssues
+		}
+		if _, err = x.Exec("UPDATE `milestone` SET completeness = ? WHERE id = ?", completeness, m.ID); err != nil {
+			return errors.Wrap(err, "update milestone completeness")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/cryptoutil"
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/migrate"
+)
+
+// RepoMigrationsStore is the persistent interface for repository migrations.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type RepoMigrationsStore interface {
+	// Create creates a new queued migration, the password and the token are
+	// encrypted with given key.
+	Create(ctx context.Context, doerID, ownerID int64, key string, opts CreateRepoMigrationOptions) (*RepoMigration, error)
+	// Finish marks the migration as finished and clears its credentials, an
+	// empty message means the migration has succeeded.
+	Finish(ctx context.Context, id int64, message string) error
+	// GetByID returns the migration with given ID. It returns
+	// ErrRepoMigrationNotExist when not found.
+	GetByID(ctx context.Context, id int64) (*RepoMigration, error)
+	// HasUnfinished returns true if there is a queued or running migration to
+	// the repository with given name of the owner.
+	HasUnfinished(ctx context.Context, ownerID int64, repoName string) (bool, error)
+	// SetRepoID records the ID of the repository created by the migration.
+	SetRepoID(ctx context.Context, id, repoID int64) error
+	// Start marks the migration as running from the beginning.
+	Start(ctx context.Context, id int64) error
+	// Touch updates the time of the running migration to indicate it is still
+	// alive.
+	Touch(ctx context.Context, id int64) error
+	// UpdateProgress records the current stage of the migration and the number of
+	// items that have been migrated in the stage.
+	UpdateProgress(ctx context.Context, id int64, stage RepoMigrationStage, progress int) error
+}
+
+var RepoMigrations RepoMigrationsStore
+
+// RepoMigrationStatus is the status of a repository migration.
+type RepoMigrationStatus string
+
+const (
+	RepoMigrationStatusQueued    RepoMigrationStatus = "queued"
+	RepoMigrationStatusRunning   RepoMigrationStatus = "running"
+	RepoMigrationStatusSucceeded RepoMigrationStatus = "succeeded"
+	RepoMigrationStatusFailed    RepoMigrationStatus = "failed"
+)
+
+// RepoMigrationStage is a stage of a running repository migration.
+type RepoMigrationStage string
+
+const (
+	RepoMigrationStageRepository RepoMigrationStage = "repository"
+	RepoMigrationStageLabels     RepoMigrationStage = "labels"
+	RepoMigrationStageMilestones RepoMigrationStage = "milestones"
+	RepoMigrationStageIssues     RepoMigrationStage = "issues"
+	RepoMigrationStageReleases   RepoMigrationStage = "releases"
+)
+
+// RepoMigration is a migration of a repository from a remote address that runs
+// in the background, and it optionally migrates metadata (e.g. issues) from the
+// code hosting service of the remote repository.
+type RepoMigration struct {
+	ID       int64  `gorm:"primaryKey"`
+	DoerID   int64  `gorm:"not null"`
+	OwnerID  int64  `gorm:"index;not null"`
+	RepoName string `gorm:"not null"`
+	// The ID of the repository once it is created.
+	RepoID int64 `gorm:"not null"`
+
+	Service      migrate.Service `gorm:"not null"`
+	CloneAddr    string          `gorm:"type:TEXT;not null"` // Without credentials.
+	AuthUsername string
+	AuthPassword string `gorm:"type:TEXT"` // Encrypted and base64-encoded.
+	AuthToken    string `gorm:"type:TEXT"` // Encrypted and base64-encoded.
+
+	Description    string `gorm:"type:TEXT"`
+	IsPrivate      bool   `gorm:"not null"`
+	IsUnlisted     bool   `gorm:"not null"`
+	IsMirror       bool   `gorm:"not null"`
+	MirrorRefspecs string `gorm:"type:TEXT"`
+	MirrorLFS      bool   `gorm:"not null"`
+
+	WithLabels       bool `gorm:"not null"`
+	WithMilestones   bool `gorm:"not null"`
+	WithIssues       bool `gorm:"not null"`
+	WithPullRequests bool `gorm:"not null"`
+	WithReleases     bool `gorm:"not null"`
+
+	Status RepoMigrationStatus `gorm:"index;not null"`
+	Stage  RepoMigrationStage
+	// The number of items that have been migrated in the current stage.
+	Progress int `gorm:"not null"`
+	// The error message of a failed migration.
+	Message    string    `gorm:"type:TEXT"`
+	CreatedAt  time.Time `gorm:"not null"`
+	UpdatedAt  time.Time `gorm:"not null"`
+	FinishedAt *time.Time
+}
+
+// IsFinished returns true if the migration has succeeded or failed.
+func (m *RepoMigration) IsFinished() bool {
+	return m.Status == RepoMigrationStatusSucceeded || m.Status == RepoMigrationStatusFailed
+}
+
+// decryptRepoMigrationSecret returns the value in plain text that was
+// encrypted with given key.
+func decryptRepoMigrationSecret(key, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	encrypted, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", errors.Wrap(err, "decode")
+	}
+	plain, err := cryptoutil.AESGCMDecrypt(cryptoutil.MD5Bytes(key), encrypted)
+	if err != nil {
+		return "", errors.Wrap(err, "decrypt")
+	}
+	return string(plain), nil
+}
+
+// DecryptCredentials returns the password and the token in plain text that were
+// encrypted with given key.
+func (m *RepoMigration) DecryptCredentials(key string) (password, token string, err error) {
+	password, err = decryptRepoMigrationSecret(key, m.AuthPassword)
+	if err != nil {
+		return "", "", errors.Wrap(err, "password")
+	}
+	token, err = decryptRepoMigrationSecret(key, m.AuthToken)
+	if err != nil {
+		return "", "", errors.Wrap(err, "token")
+	}
+	return password, token, nil
+}
+
+var _ RepoMigrationsStore = (*repoMigrations)(nil)
+
+type repoMigrations struct {
+	*gorm.DB
+}
+
+// NewRepoMigrationsStore returns a persistent interface for repository
+// migrations with given database connection.
+func NewRepoMigrationsStore(db *gorm.DB) RepoMigrationsStore {
+	return &repoMigrations{DB: db}
+}
+
+type CreateRepoMigrationOptions struct {
+	RepoName       string
+	Service        migrate.Service
+	CloneAddr      string
+	AuthUsername   string
+	AuthPassword   string
+	AuthToken      string
+	Description    string
+	IsPrivate      bool
+	IsUnlisted     bool
+	IsMirror       bool
+	MirrorRefspecs string
+	MirrorLFS      bool
+
+	WithLabels       bool
+	WithMilestones   bool
+	WithIssues       bool
+	WithPullRequests bool
+	WithReleases     bool
+}
+
+// encryptRepoMigrationSecret returns the value encrypted with given key and
+// base64-encoded.
+func encryptRepoMigrationSecret(key, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	encrypted, err := cryptoutil.AESGCMEncrypt(cryptoutil.MD5Bytes(key), []byte(value))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+func (db *repoMigrations) Create(ctx context.Context, doerID, ownerID int64, key string, opts CreateRepoMigrationOptions) (*RepoMigration, error) {
+	m := &RepoMigration{
+		DoerID:           doerID,
+		OwnerID:          ownerID,
+		RepoName:         opts.RepoName,
+		Service:          opts.Service,
+		CloneAddr:        opts.CloneAddr,
+		AuthUsername:     opts.AuthUsername,
+		Description:      opts.Description,
+		IsPrivate:        opts.IsPrivate,
+		IsUnlisted:       opts.IsUnlisted,
+		IsMirror:         opts.IsMirror,
+		MirrorRefspecs:   opts.MirrorRefspecs,
+		MirrorLFS:        opts.MirrorLFS,
+		WithLabels:       opts.WithLabels,
+		WithMilestones:   opts.WithMilestones,
+		WithIssues:       opts.WithIssues,
+		WithPullRequests: opts.WithPullRequests,
+		WithReleases:     opts.WithReleases,
+		Status:           RepoMigrationStatusQueued,
+	}
+
+	var err error
+	m.AuthPassword, err = encryptRepoMigrationSecret(key, opts.AuthPassword)
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypt password")
+	}
+	m.AuthToken, err = encryptRepoMigrationSecret(key, opts.AuthToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypt token")
+	}
+
+	if err = db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (db *repoMigrations) Finish(ctx context.Context, id int64, message string) error {
+	status := RepoMigrationStatusSucceeded
+	if message != "" {
+		status = RepoMigrationStatusFailed
+	}
+	return db.WithContext(ctx).
+		Model(new(RepoMigration)).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"message":       message,
+			"auth_password": "",
+			"auth_token":    "",
+			"finished_at":   db.NowFunc(),
+			"updated_at":    db.NowFunc(),
+		}).
+		Error
+}
+
+var _ errutil.NotFound = (*ErrRepoMigrationNotExist)(nil)
+
+type ErrRepoMigrationNotExist struct {
+	args errutil.Args
+}
+
+func IsErrRepoMigrationNotExist(err error) bool {
+	_, ok := err.(ErrRepoMigrationNotExist)
+	return ok
+}
+
+func (err ErrRepoMigrationNotExist) Error() string {
+	return fmt.Sprintf("repository migration does not exist: %v", err.args)
+}
+
+func (ErrRepoMigrationNotExist) NotFound() bool {
+	return true
+}
+
+func (db *repoMigrations) GetByID(ctx context.Context, id int64) (*RepoMigration, error) {
+	m := new(RepoMigration)
+	err := db.WithContext(ctx).Where("id = ?", id).First(m).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrRepoMigrationNotExist{args: errutil.Args{"id": id}}
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func (db *repoMigrations) HasUnfinished(ctx context.Context, ownerID int64, repoName string) (bool, error) {
+	var count int64
+	err := db.WithContext(ctx).
+		Model(new(RepoMigration)).
+		Where("owner_id = ? AND LOWER(repo_name) = ? AND status IN (?)",
+			ownerID, strings.ToLower(repoName),
+			[]RepoMigrationStatus{RepoMigrationStatusQueued, RepoMigrationStatusRunning},
+		).
+		Count(&count).
+		Error
+	return count > 0, err
+}
+
+func (db *repoMigrations) SetRepoID(ctx context.Context, id, repoID int64) error {
+	return db.WithContext(ctx).
+		Model(new(RepoMigration)).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"repo_id":    repoID,
+			"updated_at": db.NowFunc(),
+		}).
+		Error
+}
+
+func (db *repoMigrations) Start(ctx context.Context, id int64) error {
+	return db.WithContext(ctx).
+		Model(new(RepoMigration)).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     RepoMigrationStatusRunning,
+			"repo_id":    0,
+			"stage":      RepoMigrationStageRepository,
+			"progress":   0,
+			"message":    "",
+			"updated_at": db.NowFunc(),
+		}).
+		Error
+}
+
+func (db *repoMigrations) Touch(ctx context.Context, id int64) error {
+	return db.WithContext(ctx).
+		Model(new(RepoMigration)).
+		Where("id = ? AND status = ?", id, RepoMigrationStatusRunning).
+		Update("updated_at", db.NowFunc()).
+		Error
+}
+
+func (db *repoMigrations) UpdateProgress(ctx context.Context, id int64, stage RepoMigrationStage, progress int) error {
+	return db.WithContext(ctx).
+		Model(new(RepoMigration)).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"stage":      stage,
+			"progress":   progress,
+			"updated_at": db.NowFunc(),
+		}).
+		Error
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/migrate"
+)
+
+func TestRepoMigrations(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(RepoMigration)}
+	db := &repoMigrations{
+		DB: dbtest.NewDB(t, "repoMigrations", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *repoMigrations)
+	}{
+		{"Create", repoMigrationsCreate},
+		{"Finish", repoMigrationsFinish},
+		{"HasUnfinished", repoMigrationsHasUnfinished},
+		{"Start", repoMigrationsStart},
+		{"Touch", repoMigrationsTouch},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+const testRepoMigrationKey = "secret-key"
+
+func repoMigrationsCreate(t *testing.T, db *repoMigrations) {
+	ctx := context.Background()
+
+	m, err := db.Create(ctx, 1, 2, testRepoMigrationKey,
+		CreateRepoMigrationOptions{
+			RepoName:     "example",
+			Service:      migrate.ServiceGitHub,
+			CloneAddr:    "https://github.com/alice/example.git",
+			AuthUsername: "alice",
+			AuthPassword: "p@ssw0rd",
+			AuthToken:    "gh-token",
+			WithIssues:   true,
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, RepoMigrationStatusQueued, m.Status)
+
+	got, err := db.GetByID(ctx, m.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got.DoerID)
+	assert.Equal(t, int64(2), got.OwnerID)
+	assert.True(t, got.WithIssues)
+	assert.False(t, got.WithReleases)
+	assert.NotEqual(t, "p@ssw0rd", got.AuthPassword)
+	assert.NotEqual(t, "gh-token", got.AuthToken)
+
+	password, token, err := got.DecryptCredentials(testRepoMigrationKey)
+	require.NoError(t, err)
+	assert.Equal(t, "p@ssw0rd", password)
+	assert.Equal(t, "gh-token", token)
+
+	_, _, err = got.DecryptCredentials("bad-key")
+	assert.Error(t, err)
+
+	_, err = db.GetByID(ctx, 404)
+	assert.True(t, IsErrRepoMigrationNotExist(err))
+}
+
+func repoMigrationsFinish(t *testing.T, db *repoMigrations) {
+	ctx := context.Background()
+
+	succeeded, err := db.Create(ctx, 1, 1, testRepoMigrationKey, CreateRepoMigrationOptions{RepoName: "succeeded", AuthPassword: "p@ssw0rd"})
+	require.NoError(t, err)
+	err = db.Finish(ctx, succeeded.ID, "")
+	require.NoError(t, err)
+	succeeded, err = db.GetByID(ctx, succeeded.ID)
+	require.NoError(t, err)
+	assert.Equal(t, RepoMigrationStatusSucceeded, succeeded.Status)
+	assert.True(t, succeeded.IsFinished())
+	assert.NotNil(t, succeeded.FinishedAt)
+	// Credentials are no longer needed.
+	assert.Empty(t, succeeded.AuthPassword)
+
+	failed, err := db.Create(ctx, 1, 1, testRepoMigrationKey, CreateRepoMigrationOptions{RepoName: "failed"})
+	require.NoError(t, err)
+	err = db.Finish(ctx, failed.ID, "fatal: Authentication failed")
+	require.NoError(t, err)
+	failed, err = db.GetByID(ctx, failed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, RepoMigrationStatusFailed, failed.Status)
+	assert.Equal(t, "fatal: Authentication failed", failed.Message)
+}
+
+func repoMigrationsHasUnfinished(t *testing.T, db *repoMigrations) {
+	ctx := context.Background()
+
+	m, err := db.Create(ctx, 1, 1, testRepoMigrationKey, CreateRepoMigrationOptions{RepoName: "Example"})
+	require.NoError(t, err)
+
+	has, err := db.HasUnfinished(ctx, 1, "example")
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	// Repositories of other owners are not affected.
+	has, err = db.HasUnfinished(ctx, 2, "example")
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	err = db.Finish(ctx, m.ID, "")
+	require.NoError(t, err)
+	has, err = db.HasUnfinished(ctx, 1, "example")
+	require.NoError(t, err)
+	assert.False(t, has)
+}
+
+func repoMigrationsStart(t *testing.T, db *repoMigrations) {
+	ctx := context.Background()
+
+	m, err := db.Create(ctx, 1, 1, testRepoMigrationKey, CreateRepoMigrationOptions{RepoName: "example"})
+	require.NoError(t, err)
+
+	err = db.Start(ctx, m.ID)
+	require.NoError(t, err)
+	err = db.SetRepoID(ctx, m.ID, 10)
+	require.NoError(t, err)
+	err = db.UpdateProgress(ctx, m.ID, RepoMigrationStageIssues, 5)
+	require.NoError(t, err)
+
+	m, err = db.GetByID(ctx, m.ID)
+	require.NoError(t, err)
+	assert.Equal(t, RepoMigrationStatusRunning, m.Status)
+	assert.Equal(t, int64(10), m.RepoID)
+	assert.Equal(t, RepoMigrationStageIssues, m.Stage)
+	assert.Equal(t, 5, m.Progress)
+
+	// Starting over resets the progress.
+	err = db.Start(ctx, m.ID)
+	require.NoError(t, err)
+	m, err = db.GetByID(ctx, m.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), m.RepoID)
+	assert.Equal(t, RepoMigrationStageRepository, m.Stage)
+	assert.Equal(t, 0, m.Progress)
+}
+
+func repoMigrationsTouch(t *testing.T, db *repoMigrations) {
+	ctx := context.Background()
+
+	m, err := db.Create(ctx, 1, 1, testRepoMigrationKey, CreateRepoMigrationOptions{RepoName: "example"})
+	require.NoError(t, err)
+	err = db.Start(ctx, m.ID)
+	require.NoError(t, err)
+	m, err = db.GetByID(ctx, m.ID)
+	require.NoError(t, err)
+
+	before := db.NowFunc
+	db.NowFunc = func() time.Time { return before().Add(time.Hour) }
+	defer func() { db.NowFunc = before }()
+
+	err = db.Touch(ctx, m.ID)
+	require.NoError(t, err)
+	got, err := db.GetByID(ctx, m.ID)
+	require.NoError(t, err)
+	assert.True(t, got.UpdatedAt.After(m.UpdatedAt.Add(30*time.Minute)))
+
+	// Finished migrations are not touched.
+	err = db.Finish(ctx, m.ID, "")
+	require.NoError(t, err)
+	m, err = db.GetByID(ctx, m.ID)
+	require.NoError(t, err)
+	db.NowFunc = func() time.Time { return before().Add(2 * time.Hour) }
+	err = db.Touch(ctx, m.ID)
+	require.NoError(t, err)
+	got, err = db.GetByID(ctx, m.ID)
+	require.NoError(t, err)
+	assert.Equal(t, m.UpdatedAt.Unix(), got.UpdatedAt.Unix())
+}
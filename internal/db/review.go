@@ -0,0 +1,623 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogs/git-module"
+	jsoniter "github.com/json-iterator/go"
+	log "unknwon.dev/clog/v2"
+	"xorm.io/xorm"
+
+	api "github.com/gogs/go-gogs-client"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/markup"
+)
+
+// ReviewType defines the verdict of a pull request review.
+type ReviewType int
+
+const (
+	// Review with general feedback only, does not affect approvals
+	REVIEW_TYPE_COMMENT ReviewType = iota
+	REVIEW_TYPE_APPROVE
+	REVIEW_TYPE_REQUEST_CHANGES
+	// Review that is collecting code comments and has not been submitted yet,
+	// which is only visible to the reviewer.
+	REVIEW_TYPE_PENDING
+)
+
+// State returns the state name of the review type used by webhook payloads.
+func (t ReviewType) State() string {
+	switch t {
+	case REVIEW_TYPE_COMMENT:
+		return "commented"
+	case REVIEW_TYPE_APPROVE:
+		return "approved"
+	case REVIEW_TYPE_REQUEST_CHANGES:
+		return "changes_requested"
+	case REVIEW_TYPE_PENDING:
+		return "pending"
+	}
+	return "unknown"
+}
+
+// Review represents a review submitted to a pull request.
+type Review struct {
+	ID         int64
+	Type       ReviewType
+	ReviewerID int64 `xorm:"INDEX"`
+	Reviewer   *User `xorm:"-" json:"-"`
+	IssueID    int64 `xorm:"INDEX"`
+	// The head commit of the pull request at the time of review.
+	CommitID  string `xorm:"VARCHAR(40)"`
+	Content   string `xorm:"TEXT"`
+	Dismissed bool
+
+	Created     time.Time `xorm:"-" json:"-"`
+	CreatedUnix int64
+	Updated     time.Time `xorm:"-" json:"-"`
+	UpdatedUnix int64
+
+	// Code comments of the review.
+	Comments []*Comment `xorm:"-" json:"-"`
+}
+
+func (r *Review) BeforeInsert() {
+	r.CreatedUnix = time.Now().Unix()
+	r.UpdatedUnix = r.CreatedUnix
+}
+
+func (r *Review) BeforeUpdate() {
+	r.UpdatedUnix = time.Now().Unix()
+}
+
+func (r *Review) AfterSet(colName string, _ xorm.Cell) {
+	switch colName {
+	case "created_unix":
+		r.Created = time.Unix(r.CreatedUnix, 0).Local()
+	case "updated_unix":
+		r.Updated = time.Unix(r.UpdatedUnix, 0).Local()
+	}
+}
+
+func (r *Review) loadAttributes(e Engine) (err error) {
+	if r.Reviewer == nil {
+		r.Reviewer, err = getUserByID(e, r.ReviewerID)
+		if err != nil {
+			if IsErrUserNotExist(err) {
+				r.ReviewerID = -1
+				r.Reviewer = NewGhostUser()
+			} else {
+				return fmt.Errorf("get reviewer by ID [%d]: %v", r.ReviewerID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Review) LoadAttributes() error {
+	return r.loadAttributes(x)
+}
+
+// IsApprove returns true if the review approves the pull request.
+func (r *Review) IsApprove() bool {
+	return r.Type == REVIEW_TYPE_APPROVE
+}
+
+// IsRequestChanges returns true if the review requests changes to the pull
+// request.
+func (r *Review) IsRequestChanges() bool {
+	return r.Type == REVIEW_TYPE_REQUEST_CHANGES
+}
+
+// IsPending returns true if the review has not been submitted.
+func (r *Review) IsPending() bool {
+	return r.Type == REVIEW_TYPE_PENDING
+}
+
+// mailContent returns the Markdown content of the review used by email
+// notifications, which includes the verdict and code comments.
+func (r *Review) mailContent() string {
+	var buf strings.Builder
+	switch r.Type {
+	case REVIEW_TYPE_APPROVE:
+		buf.WriteString("**Approved these changes**")
+	case REVIEW_TYPE_REQUEST_CHANGES:
+		buf.WriteString("**Requested changes**")
+	default:
+		buf.WriteString("**Reviewed these changes**")
+	}
+
+	if r.Content != "" {
+		buf.WriteString("\n\n")
+		buf.WriteString(r.Content)
+	}
+	for _, c := range r.Comments {
+		buf.WriteString(fmt.Sprintf("\n\n`%s:%d`\n\n", c.TreePath, c.CodeLine()))
+		buf.WriteString(c.Content)
+	}
+	return buf.String()
+}
+
+// getPendingReview returns the pending review of the reviewer to the pull
+// request with given issue ID, it returns nil if there is no pending review.
+func getPendingReview(e Engine, issueID, reviewerID int64) (*Review, error) {
+	review := new(Review)
+	has, err := e.Where("issue_id = ?", issueID).
+		And("reviewer_id = ?", reviewerID).
+		And("type = ?", REVIEW_TYPE_PENDING).
+		Get(review)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return review, nil
+}
+
+func getOrCreatePendingReview(e *xorm.Session, doer *User, issueID int64) (*Review, error) {
+	review, err := getPendingReview(e, issueID, doer.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get pending review: %v", err)
+	} else if review != nil {
+		review.Reviewer = doer
+		return review, nil
+	}
+
+	review = &Review{
+		Type:       REVIEW_TYPE_PENDING,
+		ReviewerID: doer.ID,
+		Reviewer:   doer,
+		IssueID:    issueID,
+	}
+	if _, err = e.Insert(review); err != nil {
+		return nil, fmt.Errorf("insert pending review: %v", err)
+	}
+	return review, nil
+}
+
+// GetPendingReview returns the pending review of the reviewer to the pull
+// request with given issue ID, it returns nil if there is no pending review.
+func GetPendingReview(issueID, reviewerID int64) (*Review, error) {
+	return getPendingReview(x, issueID, reviewerID)
+}
+
+// CountReviewCodeComments returns the number of code comments of the review
+// with given ID.
+func CountReviewCodeComments(reviewID int64) (int64, error) {
+	return x.Where("review_id = ?", reviewID).And("type = ?", COMMENT_TYPE_CODE).Count(new(Comment))
+}
+
+// CreateCodeComment creates a code comment on given line of the file in the
+// diff of the pull request, the comment is added to the pending review of the
+// doer and only becomes visible to others when the review is submitted. The
+// pull request must have its issue and base repository loaded.
+func CreateCodeComment(doer *User, pr *PullRequest, commitID, treePath string, line int64, content string) (*Comment, error) {
+	if pr.Issue.IsClosed || pr.HasMerged {
+		return nil, ErrPullRequestNotOpen{args: map[string]interface{}{"pullID": pr.ID}}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	review, err := getOrCreatePendingReview(sess, doer, pr.IssueID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := createComment(sess, &CreateCommentOptions{
+		Type:      COMMENT_TYPE_CODE,
+		Doer:      doer,
+		Repo:      pr.BaseRepo,
+		Issue:     pr.Issue,
+		CommitSHA: commitID,
+		LineNum:   line,
+		TreePath:  treePath,
+		Review:    review,
+		Content:   content,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create comment: %v", err)
+	}
+	return comment, sess.Commit()
+}
+
+type ErrPullRequestNotOpen struct {
+	args map[string]interface{}
+}
+
+func IsErrPullRequestNotOpen(err error) bool {
+	_, ok := err.(ErrPullRequestNotOpen)
+	return ok
+}
+
+func (err ErrPullRequestNotOpen) Error() string {
+	return fmt.Sprintf("pull request is closed or merged: %v", err.args)
+}
+
+type ErrReviewEmpty struct{}
+
+func IsErrReviewEmpty(err error) bool {
+	_, ok := err.(ErrReviewEmpty)
+	return ok
+}
+
+func (err ErrReviewEmpty) Error() string {
+	return "review has neither content nor code comments"
+}
+
+// SubmitReview submits the pending review of the doer with given type, content
+// and the head commit of the pull request. A new review is created if the doer
+// does not have a pending review. The pull request must have its issue and
+// base repository loaded.
+func SubmitReview(doer *User, pr *PullRequest, typ ReviewType, commitID, content string) (*Review, error) {
+	if typ == REVIEW_TYPE_PENDING {
+		return nil, fmt.Errorf("cannot submit review as pending")
+	} else if pr.Issue.IsClosed || pr.HasMerged {
+		return nil, ErrPullRequestNotOpen{args: map[string]interface{}{"pullID": pr.ID}}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	review, err := getOrCreatePendingReview(sess, doer, pr.IssueID)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Comments = make([]*Comment, 0, 5)
+	err = sess.Where("review_id = ?", review.ID).And("type = ?", COMMENT_TYPE_CODE).Asc("id").Find(&review.Comments)
+	if err != nil {
+		return nil, fmt.Errorf("find code comments: %v", err)
+	} else if typ == REVIEW_TYPE_COMMENT && content == "" && len(review.Comments) == 0 {
+		return nil, ErrReviewEmpty{}
+	}
+
+	review.Type = typ
+	review.CommitID = commitID
+	review.Content = content
+	if _, err = sess.ID(review.ID).Cols("type", "commit_id", "content", "updated_unix").Update(review); err != nil {
+		return nil, fmt.Errorf("update review: %v", err)
+	}
+
+	comment, err := createComment(sess, &CreateCommentOptions{
+		Type:    COMMENT_TYPE_REVIEW,
+		Doer:    doer,
+		Repo:    pr.BaseRepo,
+		Issue:   pr.Issue,
+		Review:  review,
+		Content: content,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create comment: %v", err)
+	}
+
+	if err = sess.Commit(); err != nil {
+		return nil, err
+	}
+	UpdateIssueIndexer(pr.IssueID)
+
+	if err = notifyIssueParticipants(pr.Issue, doer, comment.ID, markup.FindAllMentions(content)); err != nil {
+		log.Error("notifyIssueParticipants: %v", err)
+	}
+
+	pr.Issue.PullRequest = pr
+	if err = PrepareWebhooks(pr.BaseRepo, HOOK_EVENT_PULL_REQUEST_REVIEW, &PullRequestReviewPayload{
+		Action:      "submitted",
+		Review:      review.apiFormat(pr.Issue.HTMLURL() + "#" + comment.HashTag()),
+		PullRequest: pr.APIFormat(),
+		Repository:  pr.BaseRepo.APIFormatLegacy(nil),
+		Sender:      doer.APIFormat(),
+	}); err != nil {
+		log.Error("PrepareWebhooks [review_id: %d]: %v", review.ID, err)
+	}
+	return review, nil
+}
+
+// GetReviewsByIssueID returns all submitted reviews of the pull request with
+// given issue ID in chronological order.
+func GetReviewsByIssueID(issueID int64) ([]*Review, error) {
+	reviews := make([]*Review, 0, 5)
+	err := x.Where("issue_id = ?", issueID).And("type != ?", REVIEW_TYPE_PENDING).Asc("id").Find(&reviews)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reviews {
+		if err := reviews[i].LoadAttributes(); err != nil {
+			return nil, err
+		}
+	}
+	return reviews, nil
+}
+
+// CountApprovals returns the number of reviewers whose latest verdict on the
+// pull request with given issue ID is an approval that has not been dismissed.
+func CountApprovals(issueID int64) (int, error) {
+	reviews := make([]*Review, 0, 5)
+	err := x.Where("issue_id = ?", issueID).
+		In("type", REVIEW_TYPE_APPROVE, REVIEW_TYPE_REQUEST_CHANGES).
+		Desc("id").
+		Find(&reviews)
+	if err != nil {
+		return 0, err
+	}
+
+	seen := make(map[int64]bool, len(reviews))
+	approvals := 0
+	for _, r := range reviews {
+		if seen[r.ReviewerID] {
+			continue
+		}
+		seen[r.ReviewerID] = true
+
+		if r.IsApprove() && !r.Dismissed {
+			approvals++
+		}
+	}
+	return approvals, nil
+}
+
+// DismissApprovals marks all approvals of the pull request with given issue ID
+// as dismissed, so they are no longer counted towards required approvals.
+func DismissApprovals(issueID int64) error {
+	_, err := x.Where("issue_id = ?", issueID).
+		And("type = ?", REVIEW_TYPE_APPROVE).
+		And("dismissed = ?", false).
+		Cols("dismissed", "updated_unix").
+		Update(&Review{Dismissed: true})
+	return err
+}
+
+// GetCodeComments returns code comments of the pull request with given issue
+// ID that are visible to the viewer, i.e. comments of submitted reviews and of
+// the pending review of the viewer.
+func GetCodeComments(issueID, viewerID int64) ([]*Comment, error) {
+	reviews := make([]*Review, 0, 5)
+	if err := x.Where("issue_id = ?", issueID).Find(&reviews); err != nil {
+		return nil, fmt.Errorf("find reviews: %v", err)
+	}
+	reviewsByID := make(map[int64]*Review, len(reviews))
+	for _, r := range reviews {
+		reviewsByID[r.ID] = r
+	}
+
+	comments := make([]*Comment, 0, 10)
+	if err := x.Where("issue_id = ?", issueID).And("type = ?", COMMENT_TYPE_CODE).Asc("id").Find(&comments); err != nil {
+		return nil, fmt.Errorf("find code comments: %v", err)
+	}
+
+	visible := comments[:0]
+	for _, c := range comments {
+		review, ok := reviewsByID[c.ReviewID]
+		if !ok || (review.IsPending() && review.ReviewerID != viewerID) {
+			continue
+		}
+		c.Review = review
+		visible = append(visible, c)
+	}
+	return visible, loadCommentsAttributes(x, visible)
+}
+
+// LoadReviewsOfComments loads reviews and their code comments for review
+// comments in the list.
+func LoadReviewsOfComments(comments []*Comment) error {
+	reviewIDs := make([]int64, 0, 5)
+	for _, c := range comments {
+		if c.Type == COMMENT_TYPE_REVIEW && c.Review == nil {
+			reviewIDs = append(reviewIDs, c.ReviewID)
+		}
+	}
+	if len(reviewIDs) == 0 {
+		return nil
+	}
+
+	reviews := make([]*Review, 0, len(reviewIDs))
+	if err := x.In("id", reviewIDs).Find(&reviews); err != nil {
+		return fmt.Errorf("find reviews: %v", err)
+	}
+	reviewsByID := make(map[int64]*Review, len(reviews))
+	for _, r := range reviews {
+		reviewsByID[r.ID] = r
+	}
+
+	codeComments := make([]*Comment, 0, 10)
+	if err := x.In("review_id", reviewIDs).And("type = ?", COMMENT_TYPE_CODE).Asc("id").Find(&codeComments); err != nil {
+		return fmt.Errorf("find code comments: %v", err)
+	} else if err = loadCommentsAttributes(x, codeComments); err != nil {
+		return err
+	}
+	for _, c := range codeComments {
+		if r := reviewsByID[c.ReviewID]; r != nil {
+			r.Comments = append(r.Comments, c)
+		}
+	}
+
+	for _, c := range comments {
+		if c.Type == COMMENT_TYPE_REVIEW && c.Review == nil {
+			c.Review = reviewsByID[c.ReviewID]
+		}
+	}
+	return nil
+}
+
+// markOutdatedCodeComments marks code comments of the pull request as outdated
+// when the lines they are made on have been changed since the commit of the
+// comment, comparing to the latest commit of the head branch.
+func (pr *PullRequest) markOutdatedCodeComments() error {
+	if pr.HeadRepo == nil {
+		return nil
+	}
+
+	headGitRepo, err := git.Open(pr.HeadRepo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("open repository: %v", err)
+	}
+	headCommitID, err := headGitRepo.BranchCommitID(pr.HeadBranch)
+	if err != nil {
+		return fmt.Errorf("get head branch commit ID: %v", err)
+	}
+
+	comments := make([]*Comment, 0, 10)
+	err = x.Where("issue_id = ?", pr.IssueID).
+		And("type = ?", COMMENT_TYPE_CODE).
+		And("outdated = ?", false).
+		And("commit_sha != ?", headCommitID).
+		Find(&comments)
+	if err != nil {
+		return fmt.Errorf("find code comments: %v", err)
+	}
+
+	commentsByCommit := make(map[string][]*Comment)
+	for _, c := range comments {
+		commentsByCommit[c.CommitSHA] = append(commentsByCommit[c.CommitSHA], c)
+	}
+
+	outdatedIDs := make([]int64, 0, len(comments))
+	for commitID, comments := range commentsByCommit {
+		diff, err := headGitRepo.Diff(headCommitID, 0, 0, 0,
+			git.DiffOptions{
+				Base:    commitID,
+				Timeout: time.Duration(conf.Git.Timeout.Diff) * time.Second,
+			},
+		)
+		if err != nil {
+			// The commit may no longer exist after a force push
+			log.Trace("Failed to get diff [base: %s, head: %s]: %v", commitID, headCommitID, err)
+			for _, c := range comments {
+				outdatedIDs = append(outdatedIDs, c.ID)
+			}
+			continue
+		}
+
+		for _, c := range comments {
+			if isCodeCommentOutdated(diff, c) {
+				outdatedIDs = append(outdatedIDs, c.ID)
+			}
+		}
+	}
+	if len(outdatedIDs) == 0 {
+		return nil
+	}
+
+	_, err = x.In("id", outdatedIDs).Cols("outdated").Update(&Comment{Outdated: true})
+	return err
+}
+
+// isCodeCommentOutdated returns true if the line of the code comment has been
+// changed or moved in the diff, i.e. any line at or above it has been added or
+// deleted. Comments on the old side of the diff are only outdated when the file
+// is removed or renamed.
+func isCodeCommentOutdated(diff *git.Diff, c *Comment) bool {
+	for _, file := range diff.Files {
+		if file.Name != c.TreePath && file.OldName() != c.TreePath {
+			continue
+		}
+
+		if file.IsDeleted() || file.IsRenamed() {
+			return true
+		} else if c.Line <= 0 {
+			return false
+		}
+
+		for _, section := range file.Sections {
+			// The line number in the old file that lines added are inserted after
+			var prevLeftLine int64
+			for _, line := range section.Lines {
+				switch line.Type {
+				case git.DiffLinePlain:
+					prevLeftLine = int64(line.LeftLine)
+				case git.DiffLineDelete:
+					if int64(line.LeftLine) <= c.Line {
+						return true
+					}
+					prevLeftLine = int64(line.LeftLine)
+				case git.DiffLineAdd:
+					if prevLeftLine < c.Line {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// PullRequestReviewComment represents a code comment in the payload of a pull
+// request review webhook event.
+type PullRequestReviewComment struct {
+	ID       int64  `json:"id"`
+	Path     string `json:"path"`
+	Line     int64  `json:"line"`
+	Side     string `json:"side"`
+	Body     string `json:"body"`
+	CommitID string `json:"commit_id"`
+}
+
+// PullRequestReview represents a review in the payload of a pull request review
+// webhook event.
+type PullRequestReview struct {
+	ID          int64                       `json:"id"`
+	State       string                      `json:"state"`
+	Body        string                      `json:"body"`
+	CommitID    string                      `json:"commit_id"`
+	HTMLURL     string                      `json:"html_url"`
+	Reviewer    *api.User                   `json:"user"`
+	Comments    []*PullRequestReviewComment `json:"comments"`
+	SubmittedAt time.Time                   `json:"submitted_at"`
+}
+
+func (r *Review) apiFormat(htmlURL string) *PullRequestReview {
+	comments := make([]*PullRequestReviewComment, len(r.Comments))
+	for i, c := range r.Comments {
+		side := "RIGHT"
+		if c.IsLeftSide() {
+			side = "LEFT"
+		}
+		comments[i] = &PullRequestReviewComment{
+			ID:       c.ID,
+			Path:     c.TreePath,
+			Line:     c.CodeLine(),
+			Side:     side,
+			Body:     c.Content,
+			CommitID: c.CommitSHA,
+		}
+	}
+	return &PullRequestReview{
+		ID:          r.ID,
+		State:       r.Type.State(),
+		Body:        r.Content,
+		CommitID:    r.CommitID,
+		HTMLURL:     htmlURL,
+		Reviewer:    r.Reviewer.APIFormat(),
+		Comments:    comments,
+		SubmittedAt: time.Unix(r.UpdatedUnix, 0),
+	}
+}
+
+// PullRequestReviewPayload represents the payload of a pull request review
+// webhook event.
+type PullRequestReviewPayload struct {
+	Action      string             `json:"action"`
+	Review      *PullRequestReview `json:"review"`
+	PullRequest *api.PullRequest   `json:"pull_request"`
+	Repository  *api.Repository    `json:"repository"`
+	Sender      *api.User          `json:"sender"`
+}
+
+func (p *PullRequestReviewPayload) JSONPayload() ([]byte, error) {
+	return jsoniter.MarshalIndent(p, "", "  ")
+}
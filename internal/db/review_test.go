@@ -0,0 +1,102 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/gogs/git-module"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCodeCommentOutdated(t *testing.T) {
+	diff := &git.Diff{
+		Files: []*git.DiffFile{
+			{
+				Name: "README.md",
+				Type: git.DiffFileChange,
+				Sections: []*git.DiffSection{
+					{
+						Lines: []*git.DiffLine{
+							{Type: git.DiffLinePlain, LeftLine: 1, RightLine: 1},
+							{Type: git.DiffLineDelete, LeftLine: 2},
+							{Type: git.DiffLineAdd, RightLine: 2},
+						},
+					},
+				},
+			},
+			{
+				Name: "main.go",
+				Type: git.DiffFileDelete,
+			},
+			{
+				Name: "CHANGELOG.md",
+				Type: git.DiffFileChange,
+				Sections: []*git.DiffSection{
+					{
+						Lines: []*git.DiffLine{
+							{Type: git.DiffLineSection},
+							{Type: git.DiffLinePlain, LeftLine: 10, RightLine: 10},
+							{Type: git.DiffLineAdd, RightLine: 11},
+							{Type: git.DiffLinePlain, LeftLine: 11, RightLine: 12},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		comment *Comment
+		want    bool
+	}{
+		{
+			name:    "file not changed",
+			comment: &Comment{TreePath: "LICENSE", Line: 2},
+			want:    false,
+		},
+		{
+			name:    "line not changed",
+			comment: &Comment{TreePath: "README.md", Line: 1},
+			want:    false,
+		},
+		{
+			name:    "line changed",
+			comment: &Comment{TreePath: "README.md", Line: 2},
+			want:    true,
+		},
+		{
+			name:    "line inserted above",
+			comment: &Comment{TreePath: "CHANGELOG.md", Line: 11},
+			want:    true,
+		},
+		{
+			name:    "line inserted below",
+			comment: &Comment{TreePath: "CHANGELOG.md", Line: 10},
+			want:    false,
+		},
+		{
+			name:    "line above the hunk",
+			comment: &Comment{TreePath: "CHANGELOG.md", Line: 3},
+			want:    false,
+		},
+		{
+			name:    "left side of changed file",
+			comment: &Comment{TreePath: "README.md", Line: -2},
+			want:    false,
+		},
+		{
+			name:    "file deleted",
+			comment: &Comment{TreePath: "main.go", Line: -3},
+			want:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, isCodeCommentOutdated(diff, test.comment))
+		})
+	}
+}
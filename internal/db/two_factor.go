@@ -39,7 +39,8 @@ func (t *TwoFactor) ValidateTOTP(passcode string) (bool, error) {
 	return totp.Validate(passcode, string(decryptSecret)), nil
 }
 
-// DeleteTwoFactor removes two-factor authentication token and recovery codes of given user.
+// DeleteTwoFactor removes two-factor authentication token of given user, and
+// recovery codes when the user has no WebAuthn credentials.
 func DeleteTwoFactor(userID int64) (err error) {
 	sess := x.NewSession()
 	defer sess.Close()
@@ -49,8 +50,15 @@ func DeleteTwoFactor(userID int64) (err error) {
 
 	if _, err = sess.Where("user_id = ?", userID).Delete(new(TwoFactor)); err != nil {
 		return fmt.Errorf("delete two-factor: %v", err)
-	} else if err = deleteRecoveryCodesByUserID(sess, userID); err != nil {
-		return fmt.Errorf("deleteRecoveryCodesByUserID: %v", err)
+	}
+
+	numCredentials, err := sess.Where("user_id = ?", userID).Count(new(WebAuthnCredential))
+	if err != nil {
+		return fmt.Errorf("count WebAuthn credentials: %v", err)
+	} else if numCredentials == 0 {
+		if err = deleteRecoveryCodesByUserID(sess, userID); err != nil {
+			return fmt.Errorf("deleteRecoveryCodesByUserID: %v", err)
+		}
 	}
 
 	return sess.Commit()
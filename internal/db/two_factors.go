@@ -18,22 +18,41 @@ import (
 	"gogs.io/gogs/internal/cryptoutil"
 	"gogs.io/gogs/internal/errutil"
 	"gogs.io/gogs/internal/strutil"
+	"gogs.io/gogs/internal/webauthn"
 )
 
 // TwoFactorsStore is the persistent interface for 2FA.
 //
 // NOTE: All methods are sorted in alphabetical order.
 type TwoFactorsStore interface {
-	// Create creates a new 2FA token and recovery codes for given user. The "key"
-	// is used to encrypt and later decrypt given "secret", which should be
-	// configured in site-level and change of the "key" will break all existing 2FA
-	// tokens.
+	// Create creates a new 2FA token for given user, and recovery codes unless the
+	// user has enabled 2FA with WebAuthn credentials. The "key" is used to encrypt
+	// and later decrypt given "secret", which should be configured in site-level
+	// and change of the "key" will break all existing 2FA tokens.
 	Create(ctx context.Context, userID int64, key, secret string) error
+	// CreateWebAuthnCredential creates a new WebAuthn credential with given name
+	// for the user, and recovery codes if the user has not enabled 2FA before. It
+	// returns ErrWebAuthnCredentialAlreadyExist when a credential with same name
+	// or same credential ID already exists for the user.
+	CreateWebAuthnCredential(ctx context.Context, userID int64, name string, cred *webauthn.Credential) (*WebAuthnCredential, error)
+	// DeleteWebAuthnCredential deletes the WebAuthn credential by given ID, and
+	// recovery codes if the user no longer has 2FA enabled. It returns
+	// ErrWebAuthnCredentialNotExist when not found.
+	//
+	// 🚨 SECURITY: The "userID" is required to prevent attacker deletes arbitrary
+	// credential that belongs to another user.
+	DeleteWebAuthnCredential(ctx context.Context, userID, id int64) error
 	// GetByUserID returns the 2FA token of given user. It returns
 	// ErrTwoFactorNotFound when not found.
 	GetByUserID(ctx context.Context, userID int64) (*TwoFactor, error)
-	// IsUserEnabled returns true if the user has enabled 2FA.
+	// IsUserEnabled returns true if the user has enabled 2FA with either a 2FA
+	// token or a WebAuthn credential.
 	IsUserEnabled(ctx context.Context, userID int64) bool
+	// ListWebAuthnCredentials returns all WebAuthn credentials of the user.
+	ListWebAuthnCredentials(ctx context.Context, userID int64) ([]*WebAuthnCredential, error)
+	// UseWebAuthnCredential updates the signature counter and the last used time
+	// of the WebAuthn credential.
+	UseWebAuthnCredential(ctx context.Context, id int64, signCount uint32) error
 }
 
 var TwoFactors TwoFactorsStore
@@ -74,9 +93,18 @@ func (db *twoFactors) Create(ctx context.Context, userID int64, key, secret stri
 	}
 
 	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		err := tx.Create(tf).Error
+		// Keep recovery codes generated along with WebAuthn credentials.
+		var count int64
+		err := tx.Model(new(WebAuthnCredential)).Where("user_id = ?", userID).Count(&count).Error
+		if err != nil {
+			return errors.Wrap(err, "count WebAuthn credentials")
+		}
+
+		err = tx.Create(tf).Error
 		if err != nil {
 			return err
+		} else if count > 0 {
+			return nil
 		}
 
 		return tx.Create(&recoveryCodes).Error
@@ -115,12 +143,11 @@ func (db *twoFactors) GetByUserID(ctx context.Context, userID int64) (*TwoFactor
 }
 
 func (db *twoFactors) IsUserEnabled(ctx context.Context, userID int64) bool {
-	var count int64
-	err := db.WithContext(ctx).Model(new(TwoFactor)).Where("user_id = ?", userID).Count(&count).Error
+	enabled, err := isUserEnabled(db.WithContext(ctx), userID)
 	if err != nil {
 		log.Error("Failed to count two factors [user_id: %d]: %v", userID, err)
 	}
-	return count > 0
+	return enabled
 }
 
 // generateRecoveryCodes generates N number of recovery codes for 2FA.
@@ -138,3 +165,171 @@ func generateRecoveryCodes(userID int64, n int) ([]*TwoFactorRecoveryCode, error
 	}
 	return recoveryCodes, nil
 }
+
+// WebAuthnCredential is a security key registered by a user as the second
+// factor.
+type WebAuthnCredential struct {
+	ID     int64  `gorm:"primaryKey"`
+	UserID int64  `gorm:"index;not null"`
+	Name   string `gorm:"not null"`
+	// The credential ID encoded in base64url.
+	CredentialID string `gorm:"type:TEXT;not null"`
+	// The credential public key as COSE_Key encoded in base64.
+	PublicKey  string    `gorm:"type:TEXT;not null"`
+	SignCount  int64     `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+	LastUsedAt *time.Time
+}
+
+// TableName implements the GORM table name interface.
+func (*WebAuthnCredential) TableName() string {
+	return "webauthn_credential"
+}
+
+// Credential returns the credential to be verified by the relying party.
+func (c *WebAuthnCredential) Credential() (*webauthn.Credential, error) {
+	id, err := webauthn.DecodeString(c.CredentialID)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode credential ID")
+	}
+	publicKey, err := base64.StdEncoding.DecodeString(c.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode public key")
+	}
+	return &webauthn.Credential{
+		ID:        id,
+		PublicKey: publicKey,
+		SignCount: uint32(c.SignCount),
+	}, nil
+}
+
+var _ errutil.NotFound = (*ErrWebAuthnCredentialNotExist)(nil)
+
+type ErrWebAuthnCredentialNotExist struct {
+	args errutil.Args
+}
+
+func IsErrWebAuthnCredentialNotExist(err error) bool {
+	_, ok := err.(ErrWebAuthnCredentialNotExist)
+	return ok
+}
+
+func (err ErrWebAuthnCredentialNotExist) Error() string {
+	return fmt.Sprintf("WebAuthn credential does not exist: %v", err.args)
+}
+
+func (ErrWebAuthnCredentialNotExist) NotFound() bool {
+	return true
+}
+
+type ErrWebAuthnCredentialAlreadyExist struct {
+	args errutil.Args
+}
+
+func IsErrWebAuthnCredentialAlreadyExist(err error) bool {
+	_, ok := err.(ErrWebAuthnCredentialAlreadyExist)
+	return ok
+}
+
+func (err ErrWebAuthnCredentialAlreadyExist) Error() string {
+	return fmt.Sprintf("WebAuthn credential already exists: %v", err.args)
+}
+
+// isUserEnabled returns true if the user has either a TOTP token or a WebAuthn
+// credential.
+func isUserEnabled(tx *gorm.DB, userID int64) (bool, error) {
+	for _, table := range []interface{}{new(TwoFactor), new(WebAuthnCredential)} {
+		var count int64
+		err := tx.Model(table).Where("user_id = ?", userID).Count(&count).Error
+		if err != nil {
+			return false, err
+		} else if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (db *twoFactors) CreateWebAuthnCredential(ctx context.Context, userID int64, name string, cred *webauthn.Credential) (*WebAuthnCredential, error) {
+	c := &WebAuthnCredential{
+		UserID:       userID,
+		Name:         name,
+		CredentialID: base64.RawURLEncoding.EncodeToString(cred.ID),
+		PublicKey:    base64.StdEncoding.EncodeToString(cred.PublicKey),
+		SignCount:    int64(cred.SignCount),
+	}
+	return c, db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var credentials []*WebAuthnCredential
+		err := tx.Where("user_id = ?", userID).Find(&credentials).Error
+		if err != nil {
+			return errors.Wrap(err, "list credentials")
+		}
+		for _, existing := range credentials {
+			if strings.EqualFold(existing.Name, name) {
+				return ErrWebAuthnCredentialAlreadyExist{args: errutil.Args{"userID": userID, "name": name}}
+			} else if existing.CredentialID == c.CredentialID {
+				return ErrWebAuthnCredentialAlreadyExist{args: errutil.Args{"userID": userID, "credentialID": c.CredentialID}}
+			}
+		}
+
+		enabled, err := isUserEnabled(tx, userID)
+		if err != nil {
+			return errors.Wrap(err, "check 2FA status")
+		}
+
+		err = tx.Create(c).Error
+		if err != nil {
+			return err
+		}
+
+		// Recovery codes are generated when 2FA is enabled for the first time.
+		if enabled {
+			return nil
+		}
+		recoveryCodes, err := generateRecoveryCodes(userID, 10)
+		if err != nil {
+			return errors.Wrap(err, "generate recovery codes")
+		}
+		err = tx.Where("user_id = ?", userID).Delete(new(TwoFactorRecoveryCode)).Error
+		if err != nil {
+			return errors.Wrap(err, "delete old recovery codes")
+		}
+		return tx.Create(&recoveryCodes).Error
+	})
+}
+
+func (db *twoFactors) DeleteWebAuthnCredential(ctx context.Context, userID, id int64) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(new(WebAuthnCredential))
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return ErrWebAuthnCredentialNotExist{args: errutil.Args{"userID": userID, "id": id}}
+		}
+
+		// Recovery codes are useless when 2FA is no longer enabled.
+		enabled, err := isUserEnabled(tx, userID)
+		if err != nil {
+			return errors.Wrap(err, "check 2FA status")
+		} else if enabled {
+			return nil
+		}
+		return tx.Where("user_id = ?", userID).Delete(new(TwoFactorRecoveryCode)).Error
+	})
+}
+
+func (db *twoFactors) ListWebAuthnCredentials(ctx context.Context, userID int64) ([]*WebAuthnCredential, error) {
+	var credentials []*WebAuthnCredential
+	return credentials, db.WithContext(ctx).Where("user_id = ?", userID).Order("id ASC").Find(&credentials).Error
+}
+
+func (db *twoFactors) UseWebAuthnCredential(ctx context.Context, id int64, signCount uint32) error {
+	return db.WithContext(ctx).
+		Model(new(WebAuthnCredential)).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"sign_count":   signCount,
+			"last_used_at": db.NowFunc(),
+		}).
+		Error
+}
@@ -15,6 +15,7 @@ import (
 
 	"gogs.io/gogs/internal/dbtest"
 	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/webauthn"
 )
 
 func TestTwoFactor_BeforeCreate(t *testing.T) {
@@ -47,7 +48,7 @@ func TestTwoFactors(t *testing.T) {
 	}
 	t.Parallel()
 
-	tables := []interface{}{new(TwoFactor), new(TwoFactorRecoveryCode)}
+	tables := []interface{}{new(TwoFactor), new(TwoFactorRecoveryCode), new(WebAuthnCredential)}
 	db := &twoFactors{
 		DB: dbtest.NewDB(t, "twoFactors", tables...),
 	}
@@ -57,8 +58,12 @@ func TestTwoFactors(t *testing.T) {
 		test func(*testing.T, *twoFactors)
 	}{
 		{"Create", twoFactorsCreate},
+		{"CreateWebAuthnCredential", twoFactorsCreateWebAuthnCredential},
+		{"DeleteWebAuthnCredential", twoFactorsDeleteWebAuthnCredential},
 		{"GetByUserID", twoFactorsGetByUserID},
 		{"IsUserEnabled", twoFactorsIsUserEnabled},
+		{"ListWebAuthnCredentials", twoFactorsListWebAuthnCredentials},
+		{"UseWebAuthnCredential", twoFactorsUseWebAuthnCredential},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Cleanup(func() {
@@ -92,6 +97,111 @@ func twoFactorsCreate(t *testing.T, db *twoFactors) {
 	assert.Equal(t, int64(10), count)
 }
 
+func countRecoveryCodes(t *testing.T, db *twoFactors, userID int64) int64 {
+	var count int64
+	err := db.Model(new(TwoFactorRecoveryCode)).Where("user_id = ?", userID).Count(&count).Error
+	require.NoError(t, err)
+	return count
+}
+
+func twoFactorsCreateWebAuthnCredential(t *testing.T, db *twoFactors) {
+	ctx := context.Background()
+
+	// Recovery codes are generated along with the first credential
+	cred, err := db.CreateWebAuthnCredential(ctx, 1, "YubiKey", &webauthn.Credential{ID: []byte("id1"), PublicKey: []byte("key1"), SignCount: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "aWQx", cred.CredentialID)
+	assert.Equal(t, int64(3), cred.SignCount)
+	assert.Equal(t, int64(10), countRecoveryCodes(t, db, 1))
+
+	got, err := cred.Credential()
+	require.NoError(t, err)
+	assert.Equal(t, &webauthn.Credential{ID: []byte("id1"), PublicKey: []byte("key1"), SignCount: 3}, got)
+
+	// Recovery codes are kept with the second credential
+	var code TwoFactorRecoveryCode
+	err = db.Where("user_id = ?", 1).First(&code).Error
+	require.NoError(t, err)
+	_, err = db.CreateWebAuthnCredential(ctx, 1, "Phone", &webauthn.Credential{ID: []byte("id2"), PublicKey: []byte("key2")})
+	require.NoError(t, err)
+	err = db.Where("code = ?", code.Code).First(new(TwoFactorRecoveryCode)).Error
+	require.NoError(t, err)
+
+	// Names and credential IDs are unique per user
+	_, err = db.CreateWebAuthnCredential(ctx, 1, "yubikey", &webauthn.Credential{ID: []byte("id3"), PublicKey: []byte("key3")})
+	assert.True(t, IsErrWebAuthnCredentialAlreadyExist(err))
+	_, err = db.CreateWebAuthnCredential(ctx, 1, "Another", &webauthn.Credential{ID: []byte("id1"), PublicKey: []byte("key1")})
+	assert.True(t, IsErrWebAuthnCredentialAlreadyExist(err))
+	_, err = db.CreateWebAuthnCredential(ctx, 2, "YubiKey", &webauthn.Credential{ID: []byte("id4"), PublicKey: []byte("key4")})
+	require.NoError(t, err)
+
+	// Existing recovery codes are kept when enabling TOTP afterwards
+	err = db.Create(ctx, 1, "secure-key", "secure-secret")
+	require.NoError(t, err)
+	err = db.Where("code = ?", code.Code).First(new(TwoFactorRecoveryCode)).Error
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), countRecoveryCodes(t, db, 1))
+}
+
+func twoFactorsDeleteWebAuthnCredential(t *testing.T, db *twoFactors) {
+	ctx := context.Background()
+
+	cred1, err := db.CreateWebAuthnCredential(ctx, 1, "YubiKey", &webauthn.Credential{ID: []byte("id1"), PublicKey: []byte("key1")})
+	require.NoError(t, err)
+	cred2, err := db.CreateWebAuthnCredential(ctx, 1, "Phone", &webauthn.Credential{ID: []byte("id2"), PublicKey: []byte("key2")})
+	require.NoError(t, err)
+
+	// Cannot delete credentials of other users
+	err = db.DeleteWebAuthnCredential(ctx, 2, cred1.ID)
+	wantErr := ErrWebAuthnCredentialNotExist{args: errutil.Args{"userID": int64(2), "id": cred1.ID}}
+	assert.Equal(t, wantErr, err)
+
+	// Recovery codes are kept until the last credential is deleted
+	err = db.DeleteWebAuthnCredential(ctx, 1, cred1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), countRecoveryCodes(t, db, 1))
+
+	err = db.DeleteWebAuthnCredential(ctx, 1, cred2.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), countRecoveryCodes(t, db, 1))
+	assert.False(t, db.IsUserEnabled(ctx, 1))
+}
+
+func twoFactorsListWebAuthnCredentials(t *testing.T, db *twoFactors) {
+	ctx := context.Background()
+
+	_, err := db.CreateWebAuthnCredential(ctx, 1, "YubiKey", &webauthn.Credential{ID: []byte("id1"), PublicKey: []byte("key1")})
+	require.NoError(t, err)
+	_, err = db.CreateWebAuthnCredential(ctx, 1, "Phone", &webauthn.Credential{ID: []byte("id2"), PublicKey: []byte("key2")})
+	require.NoError(t, err)
+	_, err = db.CreateWebAuthnCredential(ctx, 2, "YubiKey", &webauthn.Credential{ID: []byte("id3"), PublicKey: []byte("key3")})
+	require.NoError(t, err)
+
+	creds, err := db.ListWebAuthnCredentials(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, creds, 2)
+	assert.Equal(t, "YubiKey", creds[0].Name)
+	assert.Equal(t, "Phone", creds[1].Name)
+}
+
+func twoFactorsUseWebAuthnCredential(t *testing.T, db *twoFactors) {
+	ctx := context.Background()
+
+	cred, err := db.CreateWebAuthnCredential(ctx, 1, "YubiKey", &webauthn.Credential{ID: []byte("id1"), PublicKey: []byte("key1")})
+	require.NoError(t, err)
+	assert.Nil(t, cred.LastUsedAt)
+
+	err = db.UseWebAuthnCredential(ctx, cred.ID, 5)
+	require.NoError(t, err)
+
+	creds, err := db.ListWebAuthnCredentials(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, creds, 1)
+	assert.Equal(t, int64(5), creds[0].SignCount)
+	require.NotNil(t, creds[0].LastUsedAt)
+	assert.Equal(t, db.NowFunc().Format(time.RFC3339), creds[0].LastUsedAt.UTC().Format(time.RFC3339))
+}
+
 func twoFactorsGetByUserID(t *testing.T, db *twoFactors) {
 	ctx := context.Background()
 
@@ -118,4 +228,9 @@ func twoFactorsIsUserEnabled(t *testing.T, db *twoFactors) {
 
 	assert.True(t, db.IsUserEnabled(ctx, 1))
 	assert.False(t, db.IsUserEnabled(ctx, 2))
+
+	// Create a WebAuthn credential for user 2
+	_, err = db.CreateWebAuthnCredential(ctx, 2, "YubiKey", &webauthn.Credential{ID: []byte("id"), PublicKey: []byte("key")})
+	require.NoError(t, err)
+	assert.True(t, db.IsUserEnabled(ctx, 2))
 }
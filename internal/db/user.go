@@ -94,13 +94,18 @@ type User struct {
 	NumFollowing int `xorm:"NOT NULL DEFAULT 0" gorm:"not null;default:0"`
 	NumStars     int
 	NumRepos     int
+	// The cached number of unread notifications, -1 means it needs to be
+	// counted again. It is only written by the notifications store.
+	NumUnreadNotifications int64 `xorm:"<- NOT NULL DEFAULT -1" gorm:"not null;default:-1" json:"-"`
 
 	// For organization
 	Description string
 	NumTeams    int
 	NumMembers  int
-	Teams       []*Team `xorm:"-" gorm:"-" json:"-"`
-	Members     []*User `xorm:"-" gorm:"-" json:"-"`
+	// Whether members are required to enable two-factor authentication
+	RequireTwoFactor bool
+	Teams            []*Team `xorm:"-" gorm:"-" json:"-"`
+	Members          []*User `xorm:"-" gorm:"-" json:"-"`
 }
 
 func (u *User) BeforeInsert() {
@@ -501,7 +506,7 @@ func NewGhostUser() *User {
 }
 
 var (
-	reservedUsernames    = []string{"-", "explore", "create", "assets", "css", "img", "js", "less", "plugins", "debug", "raw", "install", "api", "avatar", "user", "org", "help", "stars", "issues", "pulls", "commits", "repo", "template", "admin", "new", ".", ".."}
+	reservedUsernames    = []string{"-", "explore", "create", "assets", "css", "img", "js", "less", "plugins", "debug", "raw", "install", "api", "avatar", "user", "org", "help", "stars", "issues", "pulls", "commits", "repo", "template", "admin", "new", "scim", ".", ".."}
 	reservedUserPatterns = []string{"*.keys"}
 )
 
@@ -627,6 +632,12 @@ func ListUsers(page, pageSize int) ([]*User, error) {
 	return users, x.Limit(pageSize, (page-1)*pageSize).Where("type=0").Asc("id").Find(&users)
 }
 
+// ListUsersByOffset returns at most "limit" users starting at given offset.
+func ListUsersByOffset(offset, limit int) ([]*User, error) {
+	users := make([]*User, 0, limit)
+	return users, x.Limit(limit, offset).Where("type=0").Asc("id").Find(&users)
+}
+
 // parseUserFromCode returns user by username encoded in code.
 // It returns nil if code or username is invalid.
 func parseUserFromCode(code string) (user *User) {
@@ -750,6 +761,51 @@ func UpdateUser(u *User) error {
 	return updateUser(x, u)
 }
 
+// UpdateUserProhibitLogin updates user's information like UpdateUser, and when
+// the user is prohibited from signing in, also deletes access tokens and public
+// keys of the user in the same transaction so that the user can no longer
+// access repositories via Git.
+func UpdateUserProhibitLogin(u *User) (err error) {
+	if !u.ProhibitLogin {
+		return UpdateUser(u)
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if err = updateUser(sess, u); err != nil {
+		return err
+	}
+
+	if _, err = sess.Delete(&AccessToken{UserID: u.ID}); err != nil {
+		return fmt.Errorf("delete access tokens: %v", err)
+	}
+
+	keys := make([]*PublicKey, 0, 10)
+	if err = sess.Find(&keys, &PublicKey{OwnerID: u.ID}); err != nil {
+		return fmt.Errorf("get all public keys: %v", err)
+	}
+	keyIDs := make([]int64, len(keys))
+	for i := range keys {
+		keyIDs[i] = keys[i].ID
+	}
+	if err = deletePublicKeys(sess, keyIDs...); err != nil {
+		return fmt.Errorf("deletePublicKeys: %v", err)
+	}
+
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+	return RewriteAuthorizedKeys()
+}
+
 // deleteBeans deletes all given beans, beans should contain delete conditions.
 func deleteBeans(e Engine, beans ...interface{}) (err error) {
 	for i := range beans {
@@ -827,6 +883,8 @@ func deleteUser(e *xorm.Session, u *User) error {
 		&Action{UserID: u.ID},
 		&IssueUser{UID: u.ID},
 		&EmailAddress{UID: u.ID},
+		&Notification{UserID: u.ID},
+		&WebAuthnCredential{UserID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
@@ -883,6 +941,10 @@ func DeleteUser(u *User) (err error) {
 		return err
 	}
 
+	if err = OAuth2.DeleteByUser(context.TODO(), u.ID); err != nil {
+		return fmt.Errorf("delete OAuth2 applications and tokens: %v", err)
+	}
+
 	return RewriteAuthorizedKeys()
 }
 
@@ -972,6 +1034,22 @@ func GetUserByName(name string) (*User, error) {
 	return u, nil
 }
 
+// GetUserByLoginName returns a user by given login source and the login name
+// in the source.
+func GetUserByLoginName(sourceID int64, loginName string) (*User, error) {
+	if loginName == "" {
+		return nil, ErrUserNotExist{args: map[string]interface{}{"loginName": loginName}}
+	}
+	u := &User{LoginSource: sourceID, LoginName: loginName}
+	has, err := x.Get(u)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrUserNotExist{args: map[string]interface{}{"loginName": loginName}}
+	}
+	return u, nil
+}
+
 // GetUserEmailsByNames returns a list of e-mails corresponds to names.
 func GetUserEmailsByNames(names []string) []string {
 	mails := make([]string, 0, len(names))
@@ -13,6 +13,7 @@ import (
 	"github.com/go-macaron/binding"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	log "unknwon.dev/clog/v2"
 
 	"gogs.io/gogs/internal/auth"
 	"gogs.io/gogs/internal/cryptoutil"
@@ -35,6 +36,12 @@ type UsersStore interface {
 	// When the "loginSourceID" is positive, it tries to authenticate via given
 	// login source and creates a new user when not yet exists in the database.
 	Authenticate(ctx context.Context, username, password string, loginSourceID int64) (*User, error)
+	// AuthenticateExternal returns the user linked to the external account of
+	// given login source, which has been authenticated by the login source. It
+	// creates a new user when not yet exists in the database, and synchronizes
+	// the site admin status and team membership of the user when the login
+	// source derives them from external groups.
+	AuthenticateExternal(ctx context.Context, loginSourceID int64, extAccount *auth.ExternalAccount) (*User, error)
 	// Create creates a new user and persists to database. It returns
 	// ErrUserAlreadyExist when a user with same name already exists, or
 	// ErrEmailAlreadyUsed if the email has been used by another user.
@@ -48,6 +55,8 @@ type UsersStore interface {
 	// GetByUsername returns the user with given username. It returns
 	// ErrUserNotExist when not found.
 	GetByUsername(ctx context.Context, username string) (*User, error)
+	// SetAdmin sets the site admin status of the user with given ID.
+	SetAdmin(ctx context.Context, userID int64, admin bool) error
 }
 
 var Users UsersStore
@@ -150,10 +159,54 @@ func (db *users) Authenticate(ctx context.Context, login, password string, login
 		return nil, err
 	}
 
-	if !createNewUser {
-		return user, nil
+	if createNewUser {
+		user, err = db.createExternal(ctx, authSourceID, extAccount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p, ok := source.Provider.(auth.GroupSyncProvider); ok {
+		err = syncExternalGroups(ctx, db, user, p, extAccount)
+		if err != nil {
+			log.Error("Failed to sync external groups of user %q: %v", user.Name, err)
+		}
+	}
+	return user, nil
+}
+
+func (db *users) AuthenticateExternal(ctx context.Context, loginSourceID int64, extAccount *auth.ExternalAccount) (*User, error) {
+	user := new(User)
+	err := db.WithContext(ctx).
+		Where("login_source = ? AND login_name = ?", loginSourceID, extAccount.Login).
+		First(user).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		user, err = db.createExternal(ctx, loginSourceID, extAccount)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, errors.Wrap(err, "get user")
 	}
 
+	// Claims may have changed since the last sign in.
+	source, err := LoginSources.GetByID(ctx, loginSourceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get login source")
+	}
+	if p, ok := source.Provider.(auth.GroupSyncProvider); ok {
+		err = syncExternalGroups(ctx, db, user, p, extAccount)
+		if err != nil {
+			log.Error("Failed to sync external groups of user %q: %v", user.Name, err)
+		}
+	}
+	return user, nil
+}
+
+// createExternal creates a new user for the external account of given login
+// source.
+func (db *users) createExternal(ctx context.Context, loginSourceID int64, extAccount *auth.ExternalAccount) (*User, error) {
 	// Validate username make sure it satisfies requirement.
 	if binding.AlphaDashDotPattern.MatchString(extAccount.Name) {
 		return nil, fmt.Errorf("invalid pattern for attribute 'username' [%s]: must be valid alpha or numeric or dash(-_) or dot characters", extAccount.Name)
@@ -162,7 +215,7 @@ func (db *users) Authenticate(ctx context.Context, login, password string, login
 	return db.Create(ctx, extAccount.Name, extAccount.Email,
 		CreateUserOptions{
 			FullName:    extAccount.FullName,
-			LoginSource: authSourceID,
+			LoginSource: loginSourceID,
 			LoginName:   extAccount.Login,
 			Location:    extAccount.Location,
 			Website:     extAccount.Website,
@@ -344,3 +397,11 @@ func (db *users) GetByUsername(ctx context.Context, username string) (*User, err
 	}
 	return user, nil
 }
+
+func (db *users) SetAdmin(ctx context.Context, userID int64, admin bool) error {
+	return db.WithContext(ctx).
+		Model(&User{}).
+		Where("id = ?", userID).
+		UpdateColumn("is_admin", admin).
+		Error
+}
@@ -34,10 +34,12 @@ func TestUsers(t *testing.T) {
 		test func(*testing.T, *users)
 	}{
 		{"Authenticate", usersAuthenticate},
+		{"AuthenticateExternal", usersAuthenticateExternal},
 		{"Create", usersCreate},
 		{"GetByEmail", usersGetByEmail},
 		{"GetByID", usersGetByID},
 		{"GetByUsername", usersGetByUsername},
+		{"SetAdmin", usersSetAdmin},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Cleanup(func() {
@@ -147,6 +149,116 @@ func usersAuthenticate(t *testing.T, db *users) {
 		require.NoError(t, err)
 		assert.Equal(t, "cindy@example.com", user.Email)
 	})
+
+	t.Run("sync admin status via login source", func(t *testing.T) {
+		mockLoginSources := NewMockLoginSourcesStore()
+		mockLoginSources.GetByIDFunc.SetDefaultHook(func(ctx context.Context, id int64) (*LoginSource, error) {
+			mockProvider := NewMockProvider()
+			mockProvider.AuthenticateFunc.SetDefaultReturn(&auth.ExternalAccount{Admin: true}, nil)
+			s := &LoginSource{
+				IsActived: true,
+				Provider: &mockGroupSyncProvider{
+					MockProvider: mockProvider,
+					syncsAdmin:   true,
+				},
+			}
+			return s, nil
+		})
+		setMockLoginSourcesStore(t, mockLoginSources)
+
+		dave, err := db.Create(ctx, "dave", "dave@example.com",
+			CreateUserOptions{
+				Password:    password,
+				LoginSource: 1,
+			},
+		)
+		require.NoError(t, err)
+
+		user, err := db.Authenticate(ctx, dave.Email, password, 1)
+		require.NoError(t, err)
+		assert.True(t, user.IsAdmin)
+
+		user, err = db.GetByID(ctx, dave.ID)
+		require.NoError(t, err)
+		assert.True(t, user.IsAdmin)
+	})
+}
+
+// mockGroupSyncProvider is a group sync provider that only syncs the site admin
+// status.
+type mockGroupSyncProvider struct {
+	*MockProvider
+	syncsAdmin bool
+}
+
+func (p *mockGroupSyncProvider) SyncsAdmin() bool {
+	return p.syncsAdmin
+}
+
+func (*mockGroupSyncProvider) SyncedTeams() []string {
+	return nil
+}
+
+func (*mockGroupSyncProvider) SearchAccount(login string) (*auth.ExternalAccount, error) {
+	return nil, auth.ErrAccountNotExist{Args: errutil.Args{"login": login}}
+}
+
+func usersAuthenticateExternal(t *testing.T, db *users) {
+	ctx := context.Background()
+
+	mockLoginSources := NewMockLoginSourcesStore()
+	mockLoginSources.GetByIDFunc.SetDefaultHook(func(ctx context.Context, id int64) (*LoginSource, error) {
+		s := &LoginSource{
+			IsActived: true,
+			Provider: &mockGroupSyncProvider{
+				MockProvider: NewMockProvider(),
+				syncsAdmin:   true,
+			},
+		}
+		return s, nil
+	})
+	setMockLoginSourcesStore(t, mockLoginSources)
+
+	extAccount := &auth.ExternalAccount{
+		Login:    "1234",
+		Name:     "alice",
+		FullName: "Alice",
+		Email:    "alice@example.com",
+		Admin:    true,
+	}
+	alice, err := db.AuthenticateExternal(ctx, 1, extAccount)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", alice.Name)
+	assert.Equal(t, int64(1), alice.LoginSource)
+	assert.Equal(t, "1234", alice.LoginName)
+	assert.True(t, alice.IsActive)
+	assert.True(t, alice.IsAdmin)
+
+	// The user is linked by the login name rather than the username
+	extAccount.Name = "alice2"
+	user, err := db.AuthenticateExternal(ctx, 1, extAccount)
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID, user.ID)
+
+	// The admin status is synced on every sign in
+	extAccount.Admin = false
+	user, err = db.AuthenticateExternal(ctx, 1, extAccount)
+	require.NoError(t, err)
+	assert.False(t, user.IsAdmin)
+
+	user, err = db.GetByID(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.False(t, user.IsAdmin)
+
+	// The same login name of a different login source is another user
+	extAccount.Email = "alice2@example.com"
+	user, err = db.AuthenticateExternal(ctx, 2, extAccount)
+	require.NoError(t, err)
+	assert.NotEqual(t, alice.ID, user.ID)
+
+	_, err = db.AuthenticateExternal(ctx, 3, extAccount)
+	wantErr := ErrUserAlreadyExist{args: errutil.Args{"name": "alice2"}}
+	assert.Equal(t, wantErr, err)
 }
 
 func usersCreate(t *testing.T, db *users) {
@@ -275,3 +387,23 @@ func usersGetByUsername(t *testing.T, db *users) {
 	wantErr := ErrUserNotExist{args: errutil.Args{"name": "bad_username"}}
 	assert.Equal(t, wantErr, err)
 }
+
+func usersSetAdmin(t *testing.T, db *users) {
+	ctx := context.Background()
+
+	alice, err := db.Create(ctx, "alice", "alice@example.com", CreateUserOptions{})
+	require.NoError(t, err)
+	assert.False(t, alice.IsAdmin)
+
+	err = db.SetAdmin(ctx, alice.ID, true)
+	require.NoError(t, err)
+	user, err := db.GetByID(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.True(t, user.IsAdmin)
+
+	err = db.SetAdmin(ctx, alice.ID, false)
+	require.NoError(t, err)
+	user, err = db.GetByID(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.False(t, user.IsAdmin)
+}
@@ -26,12 +26,9 @@ import (
 	"gogs.io/gogs/internal/errutil"
 	"gogs.io/gogs/internal/httplib"
 	"gogs.io/gogs/internal/netutil"
-	"gogs.io/gogs/internal/sync"
 	"gogs.io/gogs/internal/testutil"
 )
 
-var HookQueue = sync.NewUniqueQueue(1000)
-
 type HookContentType int
 
 const (
@@ -74,6 +71,9 @@ type HookEvents struct {
 	PullRequest  bool `json:"pull_request"`
 	IssueComment bool `json:"issue_comment"`
 	Release      bool `json:"release"`
+	Status       bool `json:"status"`
+
+	PullRequestReview bool `json:"pull_request_review"`
 }
 
 // HookEvent represents events that will delivery hook.
@@ -147,6 +147,14 @@ func (w *Webhook) SlackMeta() *SlackMeta {
 	return s
 }
 
+func (w *Webhook) CustomMeta() *CustomMeta {
+	m := &CustomMeta{}
+	if err := jsoniter.Unmarshal([]byte(w.Meta), m); err != nil {
+		log.Error("Failed to get custom meta [webhook_id: %d]: %v", w.ID, err)
+	}
+	return m
+}
+
 // History returns history of webhook by given conditions.
 func (w *Webhook) History(page int) ([]*HookTask, error) {
 	return HookTasks(w.ID, page)
@@ -207,13 +215,25 @@ func (w *Webhook) HasReleaseEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Release)
 }
 
+// HasStatusEvent returns true if hook enabled status event.
+func (w *Webhook) HasStatusEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Status)
+}
+
+// HasPullRequestReviewEvent returns true if hook enabled pull request review event.
+func (w *Webhook) HasPullRequestReviewEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.PullRequestReview)
+}
+
 type eventChecker struct {
 	checker func() bool
 	typ     HookEventType
 }
 
 func (w *Webhook) EventsArray() []string {
-	events := make([]string, 0, 8)
+	events := make([]string, 0, 10)
 	eventCheckers := []eventChecker{
 		{w.HasCreateEvent, HOOK_EVENT_CREATE},
 		{w.HasDeleteEvent, HOOK_EVENT_DELETE},
@@ -223,6 +243,8 @@ func (w *Webhook) EventsArray() []string {
 		{w.HasPullRequestEvent, HOOK_EVENT_PULL_REQUEST},
 		{w.HasIssueCommentEvent, HOOK_EVENT_ISSUE_COMMENT},
 		{w.HasReleaseEvent, HOOK_EVENT_RELEASE},
+		{w.HasStatusEvent, HOOK_EVENT_STATUS},
+		{w.HasPullRequestReviewEvent, HOOK_EVENT_PULL_REQUEST_REVIEW},
 	}
 	for _, c := range eventCheckers {
 		if c.checker() {
@@ -372,13 +394,19 @@ const (
 	SLACK
 	DISCORD
 	DINGTALK
+	MATTERMOST
+	TEAMS
+	CUSTOM
 )
 
 var hookTaskTypes = map[string]HookTaskType{
-	"gogs":     GOGS,
-	"slack":    SLACK,
-	"discord":  DISCORD,
-	"dingtalk": DINGTALK,
+	"gogs":       GOGS,
+	"slack":      SLACK,
+	"discord":    DISCORD,
+	"dingtalk":   DINGTALK,
+	"mattermost": MATTERMOST,
+	"teams":      TEAMS,
+	"custom":     CUSTOM,
 }
 
 // ToHookTaskType returns HookTaskType by given name.
@@ -396,6 +424,12 @@ func (t HookTaskType) Name() string {
 		return "discord"
 	case DINGTALK:
 		return "dingtalk"
+	case MATTERMOST:
+		return "mattermost"
+	case TEAMS:
+		return "teams"
+	case CUSTOM:
+		return "custom"
 	}
 	return ""
 }
@@ -417,6 +451,9 @@ const (
 	HOOK_EVENT_PULL_REQUEST  HookEventType = "pull_request"
 	HOOK_EVENT_ISSUE_COMMENT HookEventType = "issue_comment"
 	HOOK_EVENT_RELEASE       HookEventType = "release"
+	HOOK_EVENT_STATUS        HookEventType = "status"
+
+	HOOK_EVENT_PULL_REQUEST_REVIEW HookEventType = "pull_request_review"
 )
 
 // HookRequest represents hook task request information.
@@ -431,23 +468,40 @@ type HookResponse struct {
 	Body    string            `json:"body"`
 }
 
+// HookAttempt represents information of a single delivery attempt of a hook
+// task.
+type HookAttempt struct {
+	Delivered int64  `json:"delivered"` // Unix nanoseconds
+	Duration  int64  `json:"duration"`  // Milliseconds
+	IsSucceed bool   `json:"is_succeed"`
+	Status    int    `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DeliveredTime returns the time when the attempt was made.
+func (a *HookAttempt) DeliveredTime() time.Time {
+	return time.Unix(0, a.Delivered)
+}
+
 // HookTask represents a hook task.
 type HookTask struct {
-	ID              int64
-	RepoID          int64 `xorm:"INDEX"`
-	HookID          int64
-	UUID            string
-	Type            HookTaskType
-	URL             string `xorm:"TEXT"`
-	Signature       string `xorm:"TEXT"`
-	api.Payloader   `xorm:"-" json:"-"`
-	PayloadContent  string `xorm:"TEXT"`
-	ContentType     HookContentType
-	EventType       HookEventType
-	IsSSL           bool
-	IsDelivered     bool
-	Delivered       int64
-	DeliveredString string `xorm:"-" json:"-"`
+	ID             int64
+	RepoID         int64 `xorm:"INDEX"`
+	HookID         int64
+	UUID           string
+	Type           HookTaskType
+	URL            string `xorm:"TEXT"`
+	Signature      string `xorm:"TEXT"`
+	api.Payloader  `xorm:"-" json:"-"`
+	PayloadContent string `xorm:"TEXT"`
+	ContentType    HookContentType
+	// The content type of the body rendered by a custom template webhook.
+	CustomContentType string
+	EventType         HookEventType
+	IsSSL             bool
+	IsDelivered       bool
+	Delivered         int64
+	DeliveredString   string `xorm:"-" json:"-"`
 
 	// History info.
 	IsSucceed       bool
@@ -455,6 +509,14 @@ type HookTask struct {
 	RequestInfo     *HookRequest  `xorm:"-" json:"-"`
 	ResponseContent string        `xorm:"TEXT"`
 	ResponseInfo    *HookResponse `xorm:"-" json:"-"`
+
+	// Retry info.
+	Attempts        int
+	AttemptsContent string         `xorm:"TEXT"`
+	AttemptsInfo    []*HookAttempt `xorm:"-" json:"-"`
+	NextAttemptUnix int64          `xorm:"INDEX"`
+	// Whether the delivery has failed after running out of attempts.
+	IsFailed bool `xorm:"INDEX"`
 }
 
 func (t *HookTask) BeforeUpdate() {
@@ -464,6 +526,9 @@ func (t *HookTask) BeforeUpdate() {
 	if t.ResponseInfo != nil {
 		t.ResponseContent = t.ToJSON(t.ResponseInfo)
 	}
+	if len(t.AttemptsInfo) > 0 {
+		t.AttemptsContent = t.ToJSON(t.AttemptsInfo)
+	}
 }
 
 func (t *HookTask) AfterSet(colName string, _ xorm.Cell) {
@@ -491,9 +556,27 @@ func (t *HookTask) AfterSet(colName string, _ xorm.Cell) {
 		if err = jsoniter.Unmarshal([]byte(t.ResponseContent), t.ResponseInfo); err != nil {
 			log.Error("Unmarshal [%d]: %v", t.ID, err)
 		}
+
+	case "attempts_content":
+		if t.AttemptsContent == "" {
+			return
+		}
+
+		if err = jsoniter.Unmarshal([]byte(t.AttemptsContent), &t.AttemptsInfo); err != nil {
+			log.Error("Unmarshal [%d]: %v", t.ID, err)
+		}
 	}
 }
 
+// NextAttemptTime returns the time when the next attempt is scheduled, or zero
+// value if there is none.
+func (t *HookTask) NextAttemptTime() time.Time {
+	if t.NextAttemptUnix <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(t.NextAttemptUnix, 0)
+}
+
 func (t *HookTask) ToJSON(v interface{}) string {
 	p, err := jsoniter.Marshal(v)
 	if err != nil {
@@ -561,6 +644,51 @@ func UpdateHookTask(t *HookTask) error {
 	return err
 }
 
+// CountFailedHookTasks returns the number of hook tasks that have failed after
+// running out of attempts.
+func CountFailedHookTasks() int64 {
+	count, _ := x.Where("is_failed = ?", true).Count(new(HookTask))
+	return count
+}
+
+// FailedHookTasks returns a list of hook tasks that have failed after running
+// out of attempts, in the reverse order of creation.
+func FailedHookTasks(page, pageSize int) ([]*HookTask, error) {
+	tasks := make([]*HookTask, 0, pageSize)
+	return tasks, x.Where("is_failed = ?", true).Limit(pageSize, (page-1)*pageSize).Desc("id").Find(&tasks)
+}
+
+// RedeliverHookTasks resets attempts of failed hook tasks with given IDs and
+// adds them to the delivery queue. All failed hook tasks are redelivered if no
+// ID is given.
+func RedeliverHookTasks(ids ...int64) error {
+	sess := x.Where("is_failed = ?", true)
+	if len(ids) > 0 {
+		sess.In("id", ids)
+	}
+	tasks := make([]*HookTask, 0, len(ids))
+	if err := sess.Find(&tasks); err != nil {
+		return fmt.Errorf("find failed hook tasks: %v", err)
+	}
+
+	repoIDs := make(map[int64]struct{})
+	for _, t := range tasks {
+		t.IsDelivered = false
+		t.IsFailed = false
+		t.Attempts = 0
+		t.NextAttemptUnix = 0
+		if err := UpdateHookTask(t); err != nil {
+			return fmt.Errorf("update hook task [%d]: %v", t.ID, err)
+		}
+		repoIDs[t.RepoID] = struct{}{}
+	}
+
+	for repoID := range repoIDs {
+		go QueueHookDelivery(repoID)
+	}
+	return nil
+}
+
 // prepareHookTasks adds list of webhooks to task queue.
 func prepareHookTasks(e Engine, repo *Repository, event HookEventType, p api.Payloader, webhooks []*Webhook) (err error) {
 	if len(webhooks) == 0 {
@@ -602,6 +730,14 @@ func prepareHookTasks(e Engine, repo *Repository, event HookEventType, p api.Pay
 			if !w.HasReleaseEvent() {
 				continue
 			}
+		case HOOK_EVENT_STATUS:
+			if !w.HasStatusEvent() {
+				continue
+			}
+		case HOOK_EVENT_PULL_REQUEST_REVIEW:
+			if !w.HasPullRequestReviewEvent() {
+				continue
+			}
 		}
 
 		// Use separate objects so modifications won't be made on payload on non-Gogs type hooks.
@@ -621,6 +757,37 @@ func prepareHookTasks(e Engine, repo *Repository, event HookEventType, p api.Pay
 			if err != nil {
 				return fmt.Errorf("GetDingtalkPayload: %v", err)
 			}
+		case MATTERMOST:
+			payloader, err = GetMattermostPayload(p, event, w.Meta)
+			if err != nil {
+				return fmt.Errorf("GetMattermostPayload: %v", err)
+			}
+		case TEAMS:
+			payloader, err = GetTeamsPayload(p, event)
+			if err != nil {
+				return fmt.Errorf("GetTeamsPayload: %v", err)
+			}
+		case CUSTOM:
+			payloader, err = GetCustomPayload(p, event, w.Meta)
+			if err != nil {
+				// A broken template should not prevent other webhooks from being
+				// delivered, the failure is recorded in the history of the webhook.
+				t := &HookTask{
+					RepoID:      repo.ID,
+					HookID:      w.ID,
+					Type:        w.HookTaskType,
+					URL:         w.URL,
+					Payloader:   p,
+					ContentType: w.ContentType,
+					EventType:   event,
+					IsSSL:       w.IsSSL,
+				}
+				t.fail(fmt.Sprintf("Cannot render payload template: %v", err))
+				if err = createHookTask(e, t); err != nil {
+					return fmt.Errorf("createHookTask: %v", err)
+				}
+				continue
+			}
 		default:
 			payloader = p
 		}
@@ -636,16 +803,22 @@ func prepareHookTasks(e Engine, repo *Repository, event HookEventType, p api.Pay
 			signature = hex.EncodeToString(sig.Sum(nil))
 		}
 
+		var customContentType string
+		if custom, ok := payloader.(*CustomPayload); ok {
+			customContentType = custom.ContentType
+		}
+
 		if err = createHookTask(e, &HookTask{
-			RepoID:      repo.ID,
-			HookID:      w.ID,
-			Type:        w.HookTaskType,
-			URL:         w.URL,
-			Signature:   signature,
-			Payloader:   payloader,
-			ContentType: w.ContentType,
-			EventType:   event,
-			IsSSL:       w.IsSSL,
+			RepoID:            repo.ID,
+			HookID:            w.ID,
+			Type:              w.HookTaskType,
+			URL:               w.URL,
+			Signature:         signature,
+			Payloader:         payloader,
+			ContentType:       w.ContentType,
+			CustomContentType: customContentType,
+			EventType:         event,
+			IsSSL:             w.IsSSL,
 		}); err != nil {
 			return fmt.Errorf("createHookTask: %v", err)
 		}
@@ -654,7 +827,7 @@ func prepareHookTasks(e Engine, repo *Repository, event HookEventType, p api.Pay
 	// It's safe to fail when the whole function is called during hook execution
 	// because resource released after exit. Also, there is no process started to
 	// consume this input during hook execution.
-	go HookQueue.Add(repo.ID)
+	go QueueHookDelivery(repo.ID)
 	return nil
 }
 
@@ -695,18 +868,90 @@ func TestWebhook(repo *Repository, event HookEventType, p api.Payloader, webhook
 	return prepareHookTasks(x, repo, event, p, []*Webhook{webhook})
 }
 
+// hookRetryBackoff returns the duration to wait before the next attempt after
+// given number of failed attempts. The duration is doubled after each failed
+// attempt.
+func hookRetryBackoff(attempts int) time.Duration {
+	backoff := conf.Webhook.RetryInterval
+	for i := 1; i < attempts && backoff < 24*time.Hour; i++ {
+		backoff *= 2
+	}
+	if backoff > 24*time.Hour {
+		backoff = 24 * time.Hour
+	}
+	return backoff
+}
+
+// deliver makes an attempt to deliver the hook task.
 func (t *HookTask) deliver() {
-	payloadURL, err := url.Parse(t.URL)
-	if err != nil {
-		t.ResponseContent = fmt.Sprintf(`{"body": "Cannot parse payload URL: %v"}`, err)
+	start := time.Now()
+	retryable := t.send()
+	t.recordAttempt(start, retryable)
+}
+
+// recordAttempt records the attempt started at given time in the history. The
+// next attempt is scheduled with exponential backoff if the attempt failed and
+// there are attempts left, otherwise the task is marked as failed.
+func (t *HookTask) recordAttempt(start time.Time, retryable bool) {
+	t.Delivered = time.Now().UnixNano()
+
+	attempt := &HookAttempt{
+		Delivered: start.UnixNano(),
+		Duration:  time.Since(start).Milliseconds(),
+		IsSucceed: t.IsSucceed,
+	}
+	if t.ResponseInfo != nil {
+		attempt.Status = t.ResponseInfo.Status
+		if attempt.Status == 0 {
+			attempt.Error = t.ResponseInfo.Body
+		}
+	}
+	t.AttemptsInfo = append(t.AttemptsInfo, attempt)
+	t.Attempts++
+
+	t.IsDelivered = true
+	t.NextAttemptUnix = 0
+	if t.IsSucceed {
 		return
 	}
-	if netutil.IsBlockedLocalHostname(payloadURL.Hostname(), conf.Security.LocalNetworkAllowlist) {
-		t.ResponseContent = `{"body": "Payload URL resolved to a local network address that is implicitly blocked."}`
+
+	if retryable && t.Attempts < conf.Webhook.MaxAttempts {
+		t.IsDelivered = false
+		t.NextAttemptUnix = start.Add(hookRetryBackoff(t.Attempts)).Unix()
+		log.Trace("Hook delivery will be retried at %s: %s", t.NextAttemptTime(), t.UUID)
 		return
 	}
+	t.IsFailed = true
+}
 
-	t.IsDelivered = true
+// fail marks the hook task as failed without making any delivery attempt, e.g.
+// the payload cannot be rendered.
+func (t *HookTask) fail(reason string) {
+	t.ResponseInfo = &HookResponse{
+		Headers: map[string]string{},
+		Body:    reason,
+	}
+	t.recordAttempt(time.Now(), false)
+	t.BeforeUpdate()
+}
+
+// send sends the payload of the hook task to the payload URL. It returns false
+// if the failure is permanent and the delivery should not be retried.
+func (t *HookTask) send() (retryable bool) {
+	t.IsSucceed = false
+	t.ResponseInfo = &HookResponse{
+		Headers: map[string]string{},
+	}
+
+	payloadURL, err := url.Parse(t.URL)
+	if err != nil {
+		t.ResponseInfo.Body = fmt.Sprintf("Cannot parse payload URL: %v", err)
+		return false
+	}
+	if netutil.IsBlockedLocalHostname(payloadURL.Hostname(), conf.Security.LocalNetworkAllowlist) {
+		t.ResponseInfo.Body = "Payload URL resolved to a local network address that is implicitly blocked."
+		return false
+	}
 
 	timeout := time.Duration(conf.Webhook.DeliverTimeout) * time.Second
 	req := httplib.Post(t.URL).SetTimeout(timeout, timeout).
@@ -717,10 +962,12 @@ func (t *HookTask) deliver() {
 		Header("X-Gogs-Event", string(t.EventType)).
 		SetTLSClientConfig(&tls.Config{InsecureSkipVerify: conf.Webhook.SkipTLSVerify})
 
-	switch t.ContentType {
-	case JSON:
+	switch {
+	case t.Type == CUSTOM:
+		req = req.Header("Content-Type", t.CustomContentType).Body(t.PayloadContent)
+	case t.ContentType == JSON:
 		req = req.Header("Content-Type", "application/json").Body(t.PayloadContent)
-	case FORM:
+	case t.ContentType == FORM:
 		req.Param("payload", t.PayloadContent)
 	}
 
@@ -732,12 +979,7 @@ func (t *HookTask) deliver() {
 		t.RequestInfo.Headers[k] = strings.Join(vals, ",")
 	}
 
-	t.ResponseInfo = &HookResponse{
-		Headers: map[string]string{},
-	}
-
 	defer func() {
-		t.Delivered = time.Now().UnixNano()
 		if t.IsSucceed {
 			log.Trace("Hook delivered: %s", t.UUID)
 		} else {
@@ -764,7 +1006,7 @@ func (t *HookTask) deliver() {
 	resp, err := req.Response()
 	if err != nil {
 		t.ResponseInfo.Body = fmt.Sprintf("Delivery: %v", err)
-		return
+		return true
 	}
 	defer resp.Body.Close()
 
@@ -778,48 +1020,80 @@ func (t *HookTask) deliver() {
 	p, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		t.ResponseInfo.Body = fmt.Sprintf("read body: %s", err)
-		return
+		return true
 	}
 	t.ResponseInfo.Body = string(p)
+	return true
+}
+
+// hookRetryCheckInterval is the interval to check hook tasks that are due to
+// be retried.
+const hookRetryCheckInterval = 15 * time.Second
+
+// queueDueHookTasks adds repositories that have hook tasks due to be retried to
+// the delivery queue.
+func queueDueHookTasks() {
+	tasks := make([]*HookTask, 0, 5)
+	err := x.Distinct("repo_id").
+		Where("is_delivered = ?", false).
+		And("next_attempt_unix > 0 AND next_attempt_unix <= ?", time.Now().Unix()).
+		Find(&tasks)
+	if err != nil {
+		log.Error("Failed to get hook tasks due to be retried: %v", err)
+		return
+	}
+	for _, t := range tasks {
+		QueueHookDelivery(t.RepoID)
+	}
 }
 
-// DeliverHooks checks and delivers undelivered hooks.
-// TODO: shoot more hooks at same time.
+// scheduleRepoHooks adds webhooks of the repository that have hook tasks due
+// to be delivered to the worker pool.
+func scheduleRepoHooks(pool *hookPool, repoID int64) error {
+	tasks := make([]*HookTask, 0, 5)
+	err := x.Distinct("hook_id").
+		Where("repo_id = ?", repoID).
+		And("is_delivered = ?", false).
+		And("next_attempt_unix <= ?", time.Now().Unix()).
+		Find(&tasks)
+	if err != nil {
+		return fmt.Errorf("get repository [%d] hook tasks: %v", repoID, err)
+	}
+	for _, t := range tasks {
+		pool.schedule(t.HookID)
+	}
+	return nil
+}
+
+// DeliverHooks starts the worker pool and delivers undelivered hooks.
 func DeliverHooks() {
+	hosts := newHostLimiter(conf.Webhook.HostConcurrency)
+	pool := newHookPool(func(hookID int64) bool {
+		return deliverHookTasks(hosts, hookID)
+	})
+	hookDeliveryPool = pool
+	pool.start(conf.Webhook.Concurrency)
+
+	go func() {
+		for range time.Tick(hookRetryCheckInterval) {
+			queueDueHookTasks()
+		}
+	}()
+
 	tasks := make([]*HookTask, 0, 10)
-	_ = x.Where("is_delivered = ?", false).Iterate(new(HookTask),
-		func(idx int, bean interface{}) error {
-			t := bean.(*HookTask)
-			t.deliver()
-			tasks = append(tasks, t)
-			return nil
-		})
-
-	// Update hook task status.
+	err := x.Distinct("hook_id").Where("is_delivered = ?", false).And("next_attempt_unix <= ?", time.Now().Unix()).Find(&tasks)
+	if err != nil {
+		log.Error("Failed to get undelivered hook tasks: %v", err)
+	}
 	for _, t := range tasks {
-		if err := UpdateHookTask(t); err != nil {
-			log.Error("UpdateHookTask [%d]: %v", t.ID, err)
-		}
+		pool.schedule(t.HookID)
 	}
 
 	// Start listening on new hook requests.
-	for repoID := range HookQueue.Queue() {
-		log.Trace("DeliverHooks [repo_id: %v]", repoID)
-		HookQueue.Remove(repoID)
-
-		tasks = make([]*HookTask, 0, 5)
-		if err := x.Where("repo_id = ?", repoID).And("is_delivered = ?", false).Find(&tasks); err != nil {
-			log.Error("Get repository [%s] hook tasks: %v", repoID, err)
-			continue
-		}
-		for _, t := range tasks {
-			t.deliver()
-			if err := UpdateHookTask(t); err != nil {
-				log.Error("UpdateHookTask [%d]: %v", t.ID, err)
-				continue
-			}
-		}
-	}
+	consumeQueue(HookQueue, func(repoID int64) error {
+		log.Trace("DeliverHooks [repo_id: %d]", repoID)
+		return scheduleRepoHooks(pool, repoID)
+	}, nil)
 }
 
 func InitDeliverHooks() {
@@ -0,0 +1,77 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"text/template"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	api "github.com/gogs/go-gogs-client"
+)
+
+// CustomMeta contains the metadata of a custom template webhook.
+type CustomMeta struct {
+	Template    string `json:"template"`
+	ContentType string `json:"content_type"`
+}
+
+// CustomPayload is the body rendered by the template of a custom template
+// webhook.
+type CustomPayload struct {
+	Body        []byte
+	ContentType string
+}
+
+func (p *CustomPayload) JSONPayload() ([]byte, error) {
+	return p.Body, nil
+}
+
+// ParseCustomTemplate parses the template of a custom template webhook. The
+// event of the payload is available via the "event" function, and the "json"
+// function encodes any value as JSON.
+func ParseCustomTemplate(tmpl string, event HookEventType) (*template.Template, error) {
+	return template.New("webhook").
+		Funcs(template.FuncMap{
+			"event": func() string {
+				return string(event)
+			},
+			"json": func(v interface{}) (string, error) {
+				data, err := jsoniter.Marshal(v)
+				return string(data), err
+			},
+		}).
+		Parse(tmpl)
+}
+
+// GetCustomPayload renders the payload of the event with the template of the
+// custom template webhook.
+func GetCustomPayload(p api.Payloader, event HookEventType, meta string) (*CustomPayload, error) {
+	custom := &CustomMeta{}
+	if err := jsoniter.Unmarshal([]byte(meta), &custom); err != nil {
+		return nil, errors.Wrap(err, "unmarshal meta")
+	}
+
+	tmpl, err := ParseCustomTemplate(custom.Template, event)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse template")
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, p); err != nil {
+		return nil, errors.Wrap(err, "execute template")
+	}
+
+	contentType := custom.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	return &CustomPayload{
+		Body:        buf.Bytes(),
+		ContentType: contentType,
+	}, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	api "github.com/gogs/go-gogs-client"
+)
+
+func TestGetCustomPayload(t *testing.T) {
+	p := &api.PushPayload{
+		Ref:    "refs/heads/main",
+		Pusher: &api.User{UserName: "alice"},
+	}
+
+	t.Run("render", func(t *testing.T) {
+		payload, err := GetCustomPayload(p, HOOK_EVENT_PUSH, `{"template":"{\"text\": {{json (printf \"%s: %s\" event .Ref)}}, \"user\": \"{{.Pusher.UserName}}\"}","content_type":"application/vnd.example+json"}`)
+		require.NoError(t, err)
+
+		got, err := payload.JSONPayload()
+		require.NoError(t, err)
+		assert.Equal(t, `{"text": "push: refs/heads/main", "user": "alice"}`, string(got))
+		assert.Equal(t, "application/vnd.example+json", payload.ContentType)
+	})
+
+	t.Run("default content type", func(t *testing.T) {
+		payload, err := GetCustomPayload(p, HOOK_EVENT_PUSH, `{"template":"{{.Ref}}"}`)
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", payload.ContentType)
+	})
+
+	t.Run("bad template", func(t *testing.T) {
+		_, err := GetCustomPayload(p, HOOK_EVENT_PUSH, `{"template":"{{.Ref"}`)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		_, err := GetCustomPayload(p, HOOK_EVENT_PUSH, `{"template":"{{.NoSuchField}}"}`)
+		assert.Error(t, err)
+	})
+}
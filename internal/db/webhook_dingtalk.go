@@ -77,6 +77,10 @@ func GetDingtalkPayload(p api.Payloader, event HookEventType) (payload *Dingtalk
 		payload = getDingtalkPullRequestPayload(p.(*api.PullRequestPayload))
 	case HOOK_EVENT_RELEASE:
 		payload = getDingtalkReleasePayload(p.(*api.ReleasePayload))
+	case HOOK_EVENT_STATUS:
+		payload = getDingtalkStatusPayload(p.(*StatusPayload))
+	case HOOK_EVENT_PULL_REQUEST_REVIEW:
+		payload = getDingtalkPullRequestReviewPayload(p.(*PullRequestReviewPayload))
 	default:
 		return nil, errors.Errorf("unexpected event %q", event)
 	}
@@ -270,6 +274,46 @@ func getDingtalkReleasePayload(p *api.ReleasePayload) *DingtalkPayload {
 	}
 }
 
+func getDingtalkStatusPayload(p *StatusPayload) *DingtalkPayload {
+	commitURL := p.Repository.HTMLURL + "/commit/" + p.SHA
+	detailsURL := p.TargetURL
+	if detailsURL == "" {
+		detailsURL = commitURL
+	}
+
+	actionCard := NewDingtalkActionCard("View Details", detailsURL)
+	actionCard.Text += "# Commit Status Event"
+	actionCard.Text += "\n- Repo: " + MarkdownLinkFormatter(p.Repository.HTMLURL, p.Repository.Name)
+	actionCard.Text += "\n- Commit: " + MarkdownLinkFormatter(commitURL, p.SHA[:7])
+	actionCard.Text += "\n- Context: " + p.Context
+	actionCard.Text += "\n- State: **" + string(p.State) + "**"
+	if p.Description != "" {
+		actionCard.Text += "\n- Description: " + p.Description
+	}
+
+	return &DingtalkPayload{
+		MsgType:    "actionCard",
+		ActionCard: actionCard,
+	}
+}
+
+func getDingtalkPullRequestReviewPayload(p *PullRequestReviewPayload) *DingtalkPayload {
+	actionCard := NewDingtalkActionCard("View Pull Request", p.Review.HTMLURL)
+	actionCard.Text += "# Pull Request Review Event"
+	actionCard.Text += "\n- Repo: " + MarkdownLinkFormatter(p.Repository.HTMLURL, p.Repository.Name)
+	actionCard.Text += fmt.Sprintf("\n- Pull Request: #%d %s", p.PullRequest.Index, p.PullRequest.Title)
+	actionCard.Text += "\n- Reviewer: " + p.Sender.UserName
+	actionCard.Text += "\n- State: **" + p.Review.State + "**"
+	if p.Review.Body != "" {
+		actionCard.Text += "\n- Body: " + p.Review.Body
+	}
+
+	return &DingtalkPayload{
+		MsgType:    "actionCard",
+		ActionCard: actionCard,
+	}
+}
+
 // MarkdownLinkFormatter formats link address and title into Markdown style.
 func MarkdownLinkFormatter(link, text string) string {
 	return "[" + text + "](" + link + ")"
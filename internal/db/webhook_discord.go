@@ -371,6 +371,37 @@ func getDiscordReleasePayload(p *api.ReleasePayload) *DiscordPayload {
 	}
 }
 
+func getDiscordStatusPayload(p *StatusPayload) *DiscordPayload {
+	repoLink := DiscordLinkFormatter(p.Repository.HTMLURL, p.Repository.Name)
+	commitLink := DiscordLinkFormatter(p.Repository.HTMLURL+"/commit/"+p.SHA, p.SHA[:7])
+	return &DiscordPayload{
+		Embeds: []*DiscordEmbedObject{{
+			Title:       fmt.Sprintf("Commit status %s: %s", p.Context, p.State),
+			Description: fmt.Sprintf("[%s] commit %s\n%s", repoLink, commitLink, p.Description),
+			URL:         p.TargetURL,
+			Author: &DiscordEmbedAuthorObject{
+				Name:    p.Sender.UserName,
+				IconURL: p.Sender.AvatarUrl,
+			},
+		}},
+	}
+}
+
+func getDiscordPullRequestReviewPayload(p *PullRequestReviewPayload) *DiscordPayload {
+	repoLink := DiscordLinkFormatter(p.Repository.HTMLURL, p.Repository.Name)
+	return &DiscordPayload{
+		Embeds: []*DiscordEmbedObject{{
+			Title:       fmt.Sprintf("Pull request review %s: #%d %s", p.Review.State, p.PullRequest.Index, p.PullRequest.Title),
+			Description: fmt.Sprintf("[%s] %s", repoLink, p.Review.Body),
+			URL:         p.Review.HTMLURL,
+			Author: &DiscordEmbedAuthorObject{
+				Name:    p.Sender.UserName,
+				IconURL: p.Sender.AvatarUrl,
+			},
+		}},
+	}
+}
+
 func GetDiscordPayload(p api.Payloader, event HookEventType, meta string) (payload *DiscordPayload, err error) {
 	slack := &SlackMeta{}
 	if err := jsoniter.Unmarshal([]byte(meta), &slack); err != nil {
@@ -394,6 +425,10 @@ func GetDiscordPayload(p api.Payloader, event HookEventType, meta string) (paylo
 		payload = getDiscordPullRequestPayload(p.(*api.PullRequestPayload), slack)
 	case HOOK_EVENT_RELEASE:
 		payload = getDiscordReleasePayload(p.(*api.ReleasePayload))
+	case HOOK_EVENT_STATUS:
+		payload = getDiscordStatusPayload(p.(*StatusPayload))
+	case HOOK_EVENT_PULL_REQUEST_REVIEW:
+		payload = getDiscordPullRequestReviewPayload(p.(*PullRequestReviewPayload))
 	default:
 		return nil, errors.Errorf("unexpected event %q", event)
 	}
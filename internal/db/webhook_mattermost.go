@@ -0,0 +1,315 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/gogs/git-module"
+	api "github.com/gogs/go-gogs-client"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+// Refer: https://developers.mattermost.com/integrate/reference/message-attachments/
+type MattermostAttachment struct {
+	Fallback  string `json:"fallback"`
+	Color     string `json:"color"`
+	Title     string `json:"title"`
+	TitleLink string `json:"title_link,omitempty"`
+	Text      string `json:"text"`
+}
+
+// Refer: https://developers.mattermost.com/integrate/webhooks/incoming/
+type MattermostPayload struct {
+	Channel     string                  `json:"channel,omitempty"`
+	Text        string                  `json:"text"`
+	Username    string                  `json:"username,omitempty"`
+	IconURL     string                  `json:"icon_url,omitempty"`
+	Attachments []*MattermostAttachment `json:"attachments,omitempty"`
+}
+
+func (p *MattermostPayload) JSONPayload() ([]byte, error) {
+	data, err := jsoniter.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+// mattermostUserLink returns the Markdown link to the profile page of the user.
+func mattermostUserLink(username string) string {
+	return MarkdownLinkFormatter(conf.Server.ExternalURL+username, username)
+}
+
+func getMattermostCreatePayload(p *api.CreatePayload) *MattermostPayload {
+	refName := git.RefShortName(p.Ref)
+	repoLink := MarkdownLinkFormatter(p.Repo.HTMLURL, p.Repo.Name)
+	refLink := MarkdownLinkFormatter(p.Repo.HTMLURL+"/src/"+refName, refName)
+	return &MattermostPayload{
+		Text: fmt.Sprintf("[%s:%s] %s created by %s", repoLink, refLink, p.RefType, mattermostUserLink(p.Sender.UserName)),
+	}
+}
+
+func getMattermostDeletePayload(p *api.DeletePayload) *MattermostPayload {
+	refName := git.RefShortName(p.Ref)
+	repoLink := MarkdownLinkFormatter(p.Repo.HTMLURL, p.Repo.Name)
+	return &MattermostPayload{
+		Text: fmt.Sprintf("[%s:%s] %s deleted by %s", repoLink, refName, p.RefType, mattermostUserLink(p.Sender.UserName)),
+	}
+}
+
+func getMattermostForkPayload(p *api.ForkPayload) *MattermostPayload {
+	baseLink := MarkdownLinkFormatter(p.Repo.HTMLURL, p.Repo.Name)
+	forkLink := MarkdownLinkFormatter(p.Forkee.HTMLURL, p.Forkee.FullName)
+	return &MattermostPayload{
+		Text: fmt.Sprintf("%s is forked to %s", baseLink, forkLink),
+	}
+}
+
+func getMattermostPushPayload(p *api.PushPayload) *MattermostPayload {
+	branchName := git.RefShortName(p.Ref)
+
+	commitDesc := fmt.Sprintf("%d new commits", len(p.Commits))
+	if len(p.Commits) == 1 {
+		commitDesc = "1 new commit"
+	}
+	if p.CompareURL != "" {
+		commitDesc = MarkdownLinkFormatter(p.CompareURL, commitDesc)
+	}
+
+	repoLink := MarkdownLinkFormatter(p.Repo.HTMLURL, p.Repo.Name)
+	branchLink := MarkdownLinkFormatter(p.Repo.HTMLURL+"/src/"+branchName, branchName)
+	text := fmt.Sprintf("[%s:%s] %s pushed by %s", repoLink, branchLink, commitDesc, mattermostUserLink(p.Pusher.UserName))
+
+	lines := make([]string, len(p.Commits))
+	for i, commit := range p.Commits {
+		lines[i] = fmt.Sprintf("%s: %s - %s", MarkdownLinkFormatter(commit.URL, commit.ID[:7]), strings.Split(commit.Message, "\n")[0], commit.Author.Name)
+	}
+
+	return &MattermostPayload{
+		Text: text,
+		Attachments: []*MattermostAttachment{{
+			Text: strings.Join(lines, "\n"),
+		}},
+	}
+}
+
+func getMattermostIssuesPayload(p *api.IssuesPayload) *MattermostPayload {
+	senderLink := mattermostUserLink(p.Sender.UserName)
+	title := fmt.Sprintf("#%d %s", p.Index, p.Issue.Title)
+	titleURL := fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Index)
+	titleLink := MarkdownLinkFormatter(titleURL, title)
+
+	var text, body string
+	switch p.Action {
+	case api.HOOK_ISSUE_OPENED:
+		text = fmt.Sprintf("[%s] New issue created by %s", p.Repository.FullName, senderLink)
+		body = p.Issue.Body
+	case api.HOOK_ISSUE_CLOSED:
+		text = fmt.Sprintf("[%s] Issue closed: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HOOK_ISSUE_REOPENED:
+		text = fmt.Sprintf("[%s] Issue re-opened: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HOOK_ISSUE_EDITED:
+		text = fmt.Sprintf("[%s] Issue edited: %s by %s", p.Repository.FullName, titleLink, senderLink)
+		body = p.Issue.Body
+	case api.HOOK_ISSUE_ASSIGNED:
+		text = fmt.Sprintf("[%s] Issue assigned to %s: %s by %s", p.Repository.FullName,
+			mattermostUserLink(p.Issue.Assignee.UserName), titleLink, senderLink)
+	case api.HOOK_ISSUE_UNASSIGNED:
+		text = fmt.Sprintf("[%s] Issue unassigned: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HOOK_ISSUE_LABEL_UPDATED:
+		text = fmt.Sprintf("[%s] Issue labels updated: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HOOK_ISSUE_LABEL_CLEARED:
+		text = fmt.Sprintf("[%s] Issue labels cleared: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HOOK_ISSUE_MILESTONED:
+		text = fmt.Sprintf("[%s] Issue milestoned: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HOOK_ISSUE_DEMILESTONED:
+		text = fmt.Sprintf("[%s] Issue demilestoned: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	}
+
+	payload := &MattermostPayload{
+		Text: text,
+	}
+	if body != "" {
+		payload.Attachments = []*MattermostAttachment{{
+			Title:     title,
+			TitleLink: titleURL,
+			Text:      body,
+		}}
+	}
+	return payload
+}
+
+func getMattermostIssueCommentPayload(p *api.IssueCommentPayload) *MattermostPayload {
+	senderLink := mattermostUserLink(p.Sender.UserName)
+	title := fmt.Sprintf("#%d %s", p.Issue.Index, p.Issue.Title)
+	titleURL := fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Issue.Index)
+	if p.Action != api.HOOK_ISSUE_COMMENT_DELETED {
+		titleURL += "#" + CommentHashTag(p.Comment.ID)
+	}
+
+	var text string
+	switch p.Action {
+	case api.HOOK_ISSUE_COMMENT_CREATED:
+		text = fmt.Sprintf("[%s] New comment created by %s", p.Repository.FullName, senderLink)
+	case api.HOOK_ISSUE_COMMENT_EDITED:
+		text = fmt.Sprintf("[%s] Comment edited by %s", p.Repository.FullName, senderLink)
+	case api.HOOK_ISSUE_COMMENT_DELETED:
+		text = fmt.Sprintf("[%s] Comment deleted by %s", p.Repository.FullName, senderLink)
+	}
+
+	return &MattermostPayload{
+		Text: text,
+		Attachments: []*MattermostAttachment{{
+			Title:     title,
+			TitleLink: titleURL,
+			Text:      p.Comment.Body,
+		}},
+	}
+}
+
+func getMattermostPullRequestPayload(p *api.PullRequestPayload) *MattermostPayload {
+	senderLink := mattermostUserLink(p.Sender.UserName)
+	title := fmt.Sprintf("#%d %s", p.Index, p.PullRequest.Title)
+	titleURL := fmt.Sprintf("%s/pulls/%d", p.Repository.HTMLURL, p.Index)
+	titleLink := MarkdownLinkFormatter(titleURL, title)
+
+	var text, body string
+	switch p.Action {
+	case api.HOOK_ISSUE_OPENED:
+		text = fmt.Sprintf("[%s] Pull request submitted by %s", p.Repository.FullName, senderLink)
+		body = p.PullRequest.Body
+	case api.HOOK_ISSUE_CLOSED:
+		if p.PullRequest.HasMerged {
+			text = fmt.Sprintf("[%s] Pull request merged: %s by %s", p.Repository.FullName, titleLink, senderLink)
+		} else {
+			text = fmt.Sprintf("[%s] Pull request closed: %s by %s", p.Repository.FullName, titleLink, senderLink)
+		}
+	case api.HOOK_ISSUE_REOPENED:
+		text = fmt.Sprintf("[%s] Pull request re-opened: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HOOK_ISSUE_EDITED:
+		text = fmt.Sprintf("[%s] Pull request edited: %s by %s", p.Repository.FullName, titleLink, senderLink)
+		body = p.PullRequest.Body
+	case api.HOOK_ISSUE_ASSIGNED:
+		text = fmt.Sprintf("[%s] Pull request assigned to %s: %s by %s", p.Repository.FullName,
+			mattermostUserLink(p.PullRequest.Assignee.UserName), titleLink, senderLink)
+	case api.HOOK_ISSUE_UNASSIGNED:
+		text = fmt.Sprintf("[%s] Pull request unassigned: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HOOK_ISSUE_LABEL_UPDATED:
+		text = fmt.Sprintf("[%s] Pull request labels updated: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HOOK_ISSUE_LABEL_CLEARED:
+		text = fmt.Sprintf("[%s] Pull request labels cleared: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HOOK_ISSUE_SYNCHRONIZED:
+		text = fmt.Sprintf("[%s] Pull request synchronized: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HOOK_ISSUE_MILESTONED:
+		text = fmt.Sprintf("[%s] Pull request milestoned: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	case api.HOOK_ISSUE_DEMILESTONED:
+		text = fmt.Sprintf("[%s] Pull request demilestoned: %s by %s", p.Repository.FullName, titleLink, senderLink)
+	}
+
+	payload := &MattermostPayload{
+		Text: text,
+	}
+	if body != "" {
+		payload.Attachments = []*MattermostAttachment{{
+			Title:     title,
+			TitleLink: titleURL,
+			Text:      body,
+		}}
+	}
+	return payload
+}
+
+func getMattermostReleasePayload(p *api.ReleasePayload) *MattermostPayload {
+	repoLink := MarkdownLinkFormatter(p.Repository.HTMLURL, p.Repository.Name)
+	refLink := MarkdownLinkFormatter(p.Repository.HTMLURL+"/src/"+p.Release.TagName, p.Release.TagName)
+	return &MattermostPayload{
+		Text: fmt.Sprintf("[%s] new release %s published by %s", repoLink, refLink, mattermostUserLink(p.Sender.UserName)),
+	}
+}
+
+func getMattermostStatusPayload(p *StatusPayload) *MattermostPayload {
+	repoLink := MarkdownLinkFormatter(p.Repository.HTMLURL, p.Repository.Name)
+	commitLink := MarkdownLinkFormatter(p.Repository.HTMLURL+"/commit/"+p.SHA, p.SHA[:7])
+	text := fmt.Sprintf("[%s] commit %s status %s: **%s**", repoLink, commitLink, p.Context, p.State)
+	if p.TargetURL != "" {
+		text += " " + MarkdownLinkFormatter(p.TargetURL, "details")
+	}
+
+	payload := &MattermostPayload{
+		Text: text,
+	}
+	if p.Description != "" {
+		payload.Attachments = []*MattermostAttachment{{
+			Text: p.Description,
+		}}
+	}
+	return payload
+}
+
+func getMattermostPullRequestReviewPayload(p *PullRequestReviewPayload) *MattermostPayload {
+	title := fmt.Sprintf("#%d %s", p.PullRequest.Index, p.PullRequest.Title)
+	repoLink := MarkdownLinkFormatter(p.Repository.HTMLURL, p.Repository.Name)
+	text := fmt.Sprintf("[%s] Pull request review %s: %s by %s", repoLink, p.Review.State,
+		MarkdownLinkFormatter(p.Review.HTMLURL, title), mattermostUserLink(p.Sender.UserName))
+
+	payload := &MattermostPayload{
+		Text: text,
+	}
+	if p.Review.Body != "" {
+		payload.Attachments = []*MattermostAttachment{{
+			Text: p.Review.Body,
+		}}
+	}
+	return payload
+}
+
+// GetMattermostPayload converts the payload of the event to the Mattermost
+// incoming webhook format.
+func GetMattermostPayload(p api.Payloader, event HookEventType, meta string) (payload *MattermostPayload, err error) {
+	mattermost := &SlackMeta{}
+	if err := jsoniter.Unmarshal([]byte(meta), &mattermost); err != nil {
+		return nil, errors.Wrap(err, "unmarshal meta")
+	}
+
+	switch event {
+	case HOOK_EVENT_CREATE:
+		payload = getMattermostCreatePayload(p.(*api.CreatePayload))
+	case HOOK_EVENT_DELETE:
+		payload = getMattermostDeletePayload(p.(*api.DeletePayload))
+	case HOOK_EVENT_FORK:
+		payload = getMattermostForkPayload(p.(*api.ForkPayload))
+	case HOOK_EVENT_PUSH:
+		payload = getMattermostPushPayload(p.(*api.PushPayload))
+	case HOOK_EVENT_ISSUES:
+		payload = getMattermostIssuesPayload(p.(*api.IssuesPayload))
+	case HOOK_EVENT_ISSUE_COMMENT:
+		payload = getMattermostIssueCommentPayload(p.(*api.IssueCommentPayload))
+	case HOOK_EVENT_PULL_REQUEST:
+		payload = getMattermostPullRequestPayload(p.(*api.PullRequestPayload))
+	case HOOK_EVENT_RELEASE:
+		payload = getMattermostReleasePayload(p.(*api.ReleasePayload))
+	case HOOK_EVENT_STATUS:
+		payload = getMattermostStatusPayload(p.(*StatusPayload))
+	case HOOK_EVENT_PULL_REQUEST_REVIEW:
+		payload = getMattermostPullRequestReviewPayload(p.(*PullRequestReviewPayload))
+	default:
+		return nil, errors.Errorf("unexpected event %q", event)
+	}
+
+	payload.Channel = mattermost.Channel
+	payload.Username = mattermost.Username
+	payload.IconURL = mattermost.IconURL
+	for _, attachment := range payload.Attachments {
+		attachment.Color = mattermost.Color
+		attachment.Fallback = payload.Text
+	}
+	return payload, nil
+}
@@ -0,0 +1,265 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "unknwon.dev/clog/v2"
+)
+
+var (
+	hookQueueLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "gogs",
+		Subsystem: "webhook",
+		Name:      "queue_latency_seconds",
+		Help:      "Time that webhooks wait in the queue before a worker starts delivering.",
+		Buckets:   []float64{0.01, 0.1, 0.5, 1, 5, 10, 30, 60, 300},
+	})
+	hookDeliveryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "gogs",
+		Subsystem: "webhook",
+		Name:      "delivery_duration_seconds",
+		Help:      "Time taken to deliver a hook task, partitioned by whether the delivery succeeded.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"status"})
+	hookQueueDepth = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "gogs",
+		Subsystem: "webhook",
+		Name:      "queue_depth",
+		Help:      "Number of webhooks that have tasks waiting for a worker.",
+	}, func() float64 {
+		if hookDeliveryPool == nil {
+			return 0
+		}
+		return float64(hookDeliveryPool.depth())
+	})
+)
+
+func init() {
+	prometheus.MustRegister(hookQueueLatency, hookDeliveryDuration, hookQueueDepth)
+}
+
+// hookDeliveryPool is the worker pool started by DeliverHooks.
+var hookDeliveryPool *hookPool
+
+// hostLimiter limits the number of concurrent deliveries to the same host.
+type hostLimiter struct {
+	limit int
+
+	lock  sync.Mutex
+	hosts map[string]int
+}
+
+func newHostLimiter(limit int) *hostLimiter {
+	if limit <= 0 {
+		limit = 1
+	}
+	return &hostLimiter{
+		limit: limit,
+		hosts: make(map[string]int),
+	}
+}
+
+// tryAcquire acquires a slot for the host, it returns false without blocking
+// when the host has reached the limit.
+func (l *hostLimiter) tryAcquire(host string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.hosts[host] >= l.limit {
+		return false
+	}
+	l.hosts[host]++
+	return true
+}
+
+// release releases a slot acquired for the host.
+func (l *hostLimiter) release(host string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.hosts[host]--
+	if l.hosts[host] <= 0 {
+		delete(l.hosts, host)
+	}
+}
+
+// hookHost returns the host of the payload URL that concurrency is limited by.
+func hookHost(payloadURL string) string {
+	u, err := url.Parse(payloadURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+type hookState int
+
+const (
+	hookQueued hookState = iota + 1
+	hookRunning
+	// The hook is being delivered and new tasks have been added meanwhile, so it
+	// needs another run.
+	hookRerun
+	// The hook is waiting for the destination host to have capacity.
+	hookWaiting
+)
+
+// hookHostRetryDelay is the delay before a hook that is blocked by the
+// per-host limit is queued again.
+const hookHostRetryDelay = time.Second
+
+// hookPool delivers hook tasks with a bounded number of workers. Tasks of the
+// same webhook are delivered one at a time in order, and each worker delivers
+// all pending tasks of a webhook before picking up the next one.
+type hookPool struct {
+	// deliver delivers pending tasks of the webhook, it returns false when the
+	// delivery has to be postponed because the destination host is busy.
+	deliver func(hookID int64) bool
+
+	lock   sync.Mutex
+	cond   *sync.Cond
+	queue  []int64
+	queued map[int64]time.Time
+	states map[int64]hookState
+}
+
+func newHookPool(deliver func(hookID int64) bool) *hookPool {
+	p := &hookPool{
+		deliver: deliver,
+		queued:  make(map[int64]time.Time),
+		states:  make(map[int64]hookState),
+	}
+	p.cond = sync.NewCond(&p.lock)
+	return p
+}
+
+// start starts given number of workers.
+func (p *hookPool) start(workers int) {
+	if workers <= 0 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+}
+
+// depth returns the number of webhooks waiting for a worker.
+func (p *hookPool) depth() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.queue)
+}
+
+// schedule adds the webhook to the queue unless it is already pending.
+func (p *hookPool) schedule(hookID int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	switch p.states[hookID] {
+	case hookQueued, hookRerun, hookWaiting:
+	case hookRunning:
+		p.states[hookID] = hookRerun
+	default:
+		p.enqueue(hookID)
+	}
+}
+
+// enqueue must be called with the lock held.
+func (p *hookPool) enqueue(hookID int64) {
+	p.states[hookID] = hookQueued
+	p.queued[hookID] = time.Now()
+	p.queue = append(p.queue, hookID)
+	p.cond.Signal()
+}
+
+// next blocks until there is a webhook in the queue and marks it as running.
+func (p *hookPool) next() int64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for len(p.queue) == 0 {
+		p.cond.Wait()
+	}
+	hookID := p.queue[0]
+	p.queue = p.queue[1:]
+	p.states[hookID] = hookRunning
+	hookQueueLatency.Observe(time.Since(p.queued[hookID]).Seconds())
+	delete(p.queued, hookID)
+	return hookID
+}
+
+// done marks the run of the webhook as finished.
+func (p *hookPool) done(hookID int64, delivered bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	switch {
+	case !delivered:
+		p.states[hookID] = hookWaiting
+		time.AfterFunc(hookHostRetryDelay, func() {
+			p.lock.Lock()
+			defer p.lock.Unlock()
+			p.enqueue(hookID)
+		})
+	case p.states[hookID] == hookRerun:
+		p.enqueue(hookID)
+	default:
+		delete(p.states, hookID)
+	}
+}
+
+func (p *hookPool) work() {
+	for {
+		hookID := p.next()
+		p.done(hookID, p.deliver(hookID))
+	}
+}
+
+// deliverHookTasks delivers undelivered tasks of the webhook in order. It stops
+// at the first task that is waiting to be retried to keep the order, and
+// returns false when the destination host has reached the concurrency limit.
+func deliverHookTasks(hosts *hostLimiter, hookID int64) bool {
+	tasks := make([]*HookTask, 0, 5)
+	err := x.Where("hook_id = ?", hookID).And("is_delivered = ?", false).Asc("id").Find(&tasks)
+	if err != nil {
+		log.Error("Failed to get undelivered hook tasks [hook_id: %d]: %v", hookID, err)
+		return true
+	}
+
+	now := time.Now().Unix()
+	for _, t := range tasks {
+		if t.NextAttemptUnix > now {
+			return true
+		}
+
+		host := hookHost(t.URL)
+		if !hosts.tryAcquire(host) {
+			return false
+		}
+		start := time.Now()
+		t.deliver()
+		hosts.release(host)
+
+		status := "failed"
+		if t.IsSucceed {
+			status = "succeeded"
+		}
+		hookDeliveryDuration.WithLabelValues(status).Observe(time.Since(start).Seconds())
+
+		if err = UpdateHookTask(t); err != nil {
+			log.Error("UpdateHookTask [%d]: %v", t.ID, err)
+			return true
+		} else if !t.IsDelivered {
+			return true
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_hostLimiter(t *testing.T) {
+	l := newHostLimiter(2)
+	assert.True(t, l.tryAcquire("example.com"))
+	assert.True(t, l.tryAcquire("example.com"))
+	assert.False(t, l.tryAcquire("example.com"))
+
+	// Other hosts are not affected.
+	assert.True(t, l.tryAcquire("gogs.io"))
+
+	l.release("example.com")
+	assert.True(t, l.tryAcquire("example.com"))
+}
+
+func Test_hookHost(t *testing.T) {
+	assert.Equal(t, "example.com:8080", hookHost("http://Example.com:8080/hook"))
+	assert.Equal(t, "example.com", hookHost("https://example.com/hook?token=1"))
+	assert.Equal(t, "", hookHost("://bad"))
+}
+
+func TestHookPool(t *testing.T) {
+	t.Run("deliver different webhooks concurrently", func(t *testing.T) {
+		started := make(chan int64, 2)
+		release := make(chan struct{})
+		p := newHookPool(func(hookID int64) bool {
+			started <- hookID
+			<-release
+			return true
+		})
+		p.start(2)
+
+		p.schedule(1)
+		p.schedule(2)
+		got := map[int64]bool{<-started: true, <-started: true}
+		assert.Equal(t, map[int64]bool{1: true, 2: true}, got)
+		close(release)
+	})
+
+	t.Run("deliver same webhook one at a time", func(t *testing.T) {
+		var lock sync.Mutex
+		running, maxRunning, runs := 0, 0, 0
+		started := make(chan struct{}, 10)
+		release := make(chan struct{})
+		finished := make(chan struct{}, 10)
+		p := newHookPool(func(hookID int64) bool {
+			lock.Lock()
+			running++
+			runs++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			lock.Unlock()
+
+			started <- struct{}{}
+			<-release
+
+			lock.Lock()
+			running--
+			lock.Unlock()
+			finished <- struct{}{}
+			return true
+		})
+		p.start(4)
+
+		p.schedule(1)
+		<-started
+
+		// Scheduling the running webhook requests exactly one more run.
+		p.schedule(1)
+		p.schedule(1)
+		assert.Equal(t, 0, p.depth())
+
+		close(release)
+		<-finished
+		<-finished
+
+		select {
+		case <-finished:
+			t.Fatal("webhook is delivered more than twice")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		assert.Equal(t, 1, maxRunning)
+		assert.Equal(t, 2, runs)
+	})
+
+	t.Run("postpone webhook when host is busy", func(t *testing.T) {
+		var lock sync.Mutex
+		attempts := 0
+		delivered := make(chan struct{})
+		p := newHookPool(func(hookID int64) bool {
+			lock.Lock()
+			defer lock.Unlock()
+			attempts++
+			if attempts == 1 {
+				return false
+			}
+			close(delivered)
+			return true
+		})
+		p.start(1)
+
+		p.schedule(1)
+		select {
+		case <-delivered:
+		case <-time.After(5 * hookHostRetryDelay):
+			t.Fatal("postponed webhook is not delivered")
+		}
+	})
+}
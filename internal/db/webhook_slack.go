@@ -288,6 +288,34 @@ func getSlackReleasePayload(p *api.ReleasePayload) *SlackPayload {
 	}
 }
 
+func getSlackStatusPayload(p *StatusPayload) *SlackPayload {
+	repoLink := SlackLinkFormatter(p.Repository.HTMLURL, p.Repository.Name)
+	commitLink := SlackLinkFormatter(p.Repository.HTMLURL+"/commit/"+p.SHA, p.SHA[:7])
+	text := fmt.Sprintf("[%s] commit %s status %s: %s", repoLink, commitLink, p.Context, p.State)
+	if p.TargetURL != "" {
+		text += " " + SlackLinkFormatter(p.TargetURL, "details")
+	}
+	return &SlackPayload{
+		Text: text,
+		Attachments: []*SlackAttachment{{
+			Text: SlackTextFormatter(p.Description),
+		}},
+	}
+}
+
+func getSlackPullRequestReviewPayload(p *PullRequestReviewPayload) *SlackPayload {
+	title := fmt.Sprintf("#%d %s", p.PullRequest.Index, p.PullRequest.Title)
+	titleLink := SlackLinkFormatter(p.Review.HTMLURL, title)
+	repoLink := SlackLinkFormatter(p.Repository.HTMLURL, p.Repository.Name)
+	text := fmt.Sprintf("[%s] Pull request review %s: %s by %s", repoLink, p.Review.State, titleLink, p.Sender.UserName)
+	return &SlackPayload{
+		Text: text,
+		Attachments: []*SlackAttachment{{
+			Text: SlackTextFormatter(p.Review.Body),
+		}},
+	}
+}
+
 func GetSlackPayload(p api.Payloader, event HookEventType, meta string) (payload *SlackPayload, err error) {
 	slack := &SlackMeta{}
 	if err := jsoniter.Unmarshal([]byte(meta), &slack); err != nil {
@@ -311,6 +339,10 @@ func GetSlackPayload(p api.Payloader, event HookEventType, meta string) (payload
 		payload = getSlackPullRequestPayload(p.(*api.PullRequestPayload), slack)
 	case HOOK_EVENT_RELEASE:
 		payload = getSlackReleasePayload(p.(*api.ReleasePayload))
+	case HOOK_EVENT_STATUS:
+		payload = getSlackStatusPayload(p.(*StatusPayload))
+	case HOOK_EVENT_PULL_REQUEST_REVIEW:
+		payload = getSlackPullRequestReviewPayload(p.(*PullRequestReviewPayload))
 	default:
 		return nil, errors.Errorf("unexpected event %q", event)
 	}
@@ -0,0 +1,297 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/gogs/git-module"
+	api "github.com/gogs/go-gogs-client"
+)
+
+const (
+	teamsColorGreen  = "2cbe4e"
+	teamsColorRed    = "cb2431"
+	teamsColorPurple = "6f42c1"
+	teamsColorBlue   = "0366d6"
+	teamsColorGrey   = "808080"
+)
+
+type TeamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type TeamsSection struct {
+	ActivityTitle    string       `json:"activityTitle,omitempty"`
+	ActivitySubtitle string       `json:"activitySubtitle,omitempty"`
+	ActivityImage    string       `json:"activityImage,omitempty"`
+	Text             string       `json:"text,omitempty"`
+	Facts            []*TeamsFact `json:"facts,omitempty"`
+	Markdown         bool         `json:"markdown"`
+}
+
+type TeamsActionTarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+type TeamsAction struct {
+	Type    string               `json:"@type"`
+	Name    string               `json:"name"`
+	Targets []*TeamsActionTarget `json:"targets"`
+}
+
+// Refer: https://learn.microsoft.com/en-us/outlook/actionable-messages/message-card-reference
+type TeamsPayload struct {
+	Type            string          `json:"@type"`
+	Context         string          `json:"@context"`
+	ThemeColor      string          `json:"themeColor"`
+	Summary         string          `json:"summary"`
+	Title           string          `json:"title"`
+	Sections        []*TeamsSection `json:"sections,omitempty"`
+	PotentialAction []*TeamsAction  `json:"potentialAction,omitempty"`
+}
+
+func (p *TeamsPayload) JSONPayload() ([]byte, error) {
+	data, err := jsoniter.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+// newTeamsPayload returns a message card with a single section of given text
+// and a button to open the given URL.
+func newTeamsPayload(title, color string, sender *api.User, text, url string, facts ...*TeamsFact) *TeamsPayload {
+	return &TeamsPayload{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: color,
+		Summary:    title,
+		Title:      title,
+		Sections: []*TeamsSection{{
+			ActivityTitle: sender.UserName,
+			ActivityImage: sender.AvatarUrl,
+			Text:          text,
+			Facts:         facts,
+			Markdown:      true,
+		}},
+		PotentialAction: []*TeamsAction{{
+			Type: "OpenUri",
+			Name: "View in browser",
+			Targets: []*TeamsActionTarget{{
+				OS:  "default",
+				URI: url,
+			}},
+		}},
+	}
+}
+
+func getTeamsCreatePayload(p *api.CreatePayload) *TeamsPayload {
+	refName := git.RefShortName(p.Ref)
+	title := fmt.Sprintf("[%s] %s %s created", p.Repo.FullName, p.RefType, refName)
+	return newTeamsPayload(title, teamsColorGreen, p.Sender, "", p.Repo.HTMLURL+"/src/"+refName)
+}
+
+func getTeamsDeletePayload(p *api.DeletePayload) *TeamsPayload {
+	refName := git.RefShortName(p.Ref)
+	title := fmt.Sprintf("[%s] %s %s deleted", p.Repo.FullName, p.RefType, refName)
+	return newTeamsPayload(title, teamsColorRed, p.Sender, "", p.Repo.HTMLURL)
+}
+
+func getTeamsForkPayload(p *api.ForkPayload) *TeamsPayload {
+	title := fmt.Sprintf("[%s] Repository forked to %s", p.Repo.FullName, p.Forkee.FullName)
+	return newTeamsPayload(title, teamsColorGreen, p.Sender, "", p.Forkee.HTMLURL)
+}
+
+func getTeamsPushPayload(p *api.PushPayload) *TeamsPayload {
+	branchName := git.RefShortName(p.Ref)
+
+	commitDesc := fmt.Sprintf("%d new commits", len(p.Commits))
+	if len(p.Commits) == 1 {
+		commitDesc = "1 new commit"
+	}
+	title := fmt.Sprintf("[%s:%s] %s", p.Repo.FullName, branchName, commitDesc)
+
+	lines := make([]string, len(p.Commits))
+	for i, commit := range p.Commits {
+		lines[i] = fmt.Sprintf("%s: %s - %s", MarkdownLinkFormatter(commit.URL, commit.ID[:7]), strings.Split(commit.Message, "\n")[0], commit.Author.Name)
+	}
+
+	url := p.CompareURL
+	if url == "" {
+		url = p.Repo.HTMLURL + "/src/" + branchName
+	}
+	return newTeamsPayload(title, teamsColorBlue, p.Pusher, strings.Join(lines, "\n\n"), url)
+}
+
+func getTeamsIssuesPayload(p *api.IssuesPayload) *TeamsPayload {
+	url := fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Index)
+
+	var action, text string
+	color := teamsColorGrey
+	switch p.Action {
+	case api.HOOK_ISSUE_OPENED:
+		action = "opened"
+		text = p.Issue.Body
+		color = teamsColorGreen
+	case api.HOOK_ISSUE_CLOSED:
+		action = "closed"
+		color = teamsColorRed
+	case api.HOOK_ISSUE_REOPENED:
+		action = "re-opened"
+		color = teamsColorGreen
+	case api.HOOK_ISSUE_EDITED:
+		action = "edited"
+		text = p.Issue.Body
+	case api.HOOK_ISSUE_ASSIGNED:
+		action = "assigned to " + p.Issue.Assignee.UserName
+	case api.HOOK_ISSUE_UNASSIGNED:
+		action = "unassigned"
+	case api.HOOK_ISSUE_LABEL_UPDATED:
+		action = "labels updated"
+	case api.HOOK_ISSUE_LABEL_CLEARED:
+		action = "labels cleared"
+	case api.HOOK_ISSUE_MILESTONED:
+		action = "milestoned"
+	case api.HOOK_ISSUE_DEMILESTONED:
+		action = "demilestoned"
+	}
+
+	title := fmt.Sprintf("[%s] Issue %s: #%d %s", p.Repository.FullName, action, p.Index, p.Issue.Title)
+	return newTeamsPayload(title, color, p.Sender, text, url)
+}
+
+func getTeamsIssueCommentPayload(p *api.IssueCommentPayload) *TeamsPayload {
+	url := fmt.Sprintf("%s/issues/%d", p.Repository.HTMLURL, p.Issue.Index)
+
+	var action string
+	color := teamsColorGrey
+	switch p.Action {
+	case api.HOOK_ISSUE_COMMENT_CREATED:
+		action = "created"
+		color = teamsColorGreen
+		url += "#" + CommentHashTag(p.Comment.ID)
+	case api.HOOK_ISSUE_COMMENT_EDITED:
+		action = "edited"
+		url += "#" + CommentHashTag(p.Comment.ID)
+	case api.HOOK_ISSUE_COMMENT_DELETED:
+		action = "deleted"
+		color = teamsColorRed
+	}
+
+	title := fmt.Sprintf("[%s] Comment %s: #%d %s", p.Repository.FullName, action, p.Issue.Index, p.Issue.Title)
+	return newTeamsPayload(title, color, p.Sender, p.Comment.Body, url)
+}
+
+func getTeamsPullRequestPayload(p *api.PullRequestPayload) *TeamsPayload {
+	url := fmt.Sprintf("%s/pulls/%d", p.Repository.HTMLURL, p.Index)
+
+	var action, text string
+	color := teamsColorGrey
+	switch p.Action {
+	case api.HOOK_ISSUE_OPENED:
+		action = "opened"
+		text = p.PullRequest.Body
+		color = teamsColorGreen
+	case api.HOOK_ISSUE_CLOSED:
+		if p.PullRequest.HasMerged {
+			action = "merged"
+			color = teamsColorPurple
+		} else {
+			action = "closed"
+			color = teamsColorRed
+		}
+	case api.HOOK_ISSUE_REOPENED:
+		action = "re-opened"
+		color = teamsColorGreen
+	case api.HOOK_ISSUE_EDITED:
+		action = "edited"
+		text = p.PullRequest.Body
+	case api.HOOK_ISSUE_ASSIGNED:
+		action = "assigned to " + p.PullRequest.Assignee.UserName
+	case api.HOOK_ISSUE_UNASSIGNED:
+		action = "unassigned"
+	case api.HOOK_ISSUE_LABEL_UPDATED:
+		action = "labels updated"
+	case api.HOOK_ISSUE_LABEL_CLEARED:
+		action = "labels cleared"
+	case api.HOOK_ISSUE_SYNCHRONIZED:
+		action = "synchronized"
+		color = teamsColorBlue
+	case api.HOOK_ISSUE_MILESTONED:
+		action = "milestoned"
+	case api.HOOK_ISSUE_DEMILESTONED:
+		action = "demilestoned"
+	}
+
+	title := fmt.Sprintf("[%s] Pull request %s: #%d %s", p.Repository.FullName, action, p.Index, p.PullRequest.Title)
+	return newTeamsPayload(title, color, p.Sender, text, url,
+		&TeamsFact{Name: "Base", Value: p.PullRequest.BaseBranch},
+		&TeamsFact{Name: "Head", Value: p.PullRequest.HeadBranch},
+	)
+}
+
+func getTeamsReleasePayload(p *api.ReleasePayload) *TeamsPayload {
+	title := fmt.Sprintf("[%s] Release %s published", p.Repository.FullName, p.Release.TagName)
+	return newTeamsPayload(title, teamsColorGreen, p.Sender, p.Release.Body, p.Repository.HTMLURL+"/src/"+p.Release.TagName)
+}
+
+func getTeamsStatusPayload(p *StatusPayload) *TeamsPayload {
+	color := teamsColorGrey
+	switch p.State {
+	case CommitStatusSuccess:
+		color = teamsColorGreen
+	case CommitStatusFailure, CommitStatusError:
+		color = teamsColorRed
+	}
+
+	url := p.TargetURL
+	if url == "" {
+		url = p.Repository.HTMLURL + "/commit/" + p.SHA
+	}
+	title := fmt.Sprintf("[%s] Commit %s status %s: %s", p.Repository.FullName, p.SHA[:7], p.Context, p.State)
+	return newTeamsPayload(title, color, p.Sender, p.Description, url)
+}
+
+func getTeamsPullRequestReviewPayload(p *PullRequestReviewPayload) *TeamsPayload {
+	title := fmt.Sprintf("[%s] Pull request review %s: #%d %s", p.Repository.FullName, p.Review.State, p.PullRequest.Index, p.PullRequest.Title)
+	return newTeamsPayload(title, teamsColorBlue, p.Sender, p.Review.Body, p.Review.HTMLURL)
+}
+
+// GetTeamsPayload converts the payload of the event to the Microsoft Teams
+// message card format.
+func GetTeamsPayload(p api.Payloader, event HookEventType) (payload *TeamsPayload, err error) {
+	switch event {
+	case HOOK_EVENT_CREATE:
+		payload = getTeamsCreatePayload(p.(*api.CreatePayload))
+	case HOOK_EVENT_DELETE:
+		payload = getTeamsDeletePayload(p.(*api.DeletePayload))
+	case HOOK_EVENT_FORK:
+		payload = getTeamsForkPayload(p.(*api.ForkPayload))
+	case HOOK_EVENT_PUSH:
+		payload = getTeamsPushPayload(p.(*api.PushPayload))
+	case HOOK_EVENT_ISSUES:
+		payload = getTeamsIssuesPayload(p.(*api.IssuesPayload))
+	case HOOK_EVENT_ISSUE_COMMENT:
+		payload = getTeamsIssueCommentPayload(p.(*api.IssueCommentPayload))
+	case HOOK_EVENT_PULL_REQUEST:
+		payload = getTeamsPullRequestPayload(p.(*api.PullRequestPayload))
+	case HOOK_EVENT_RELEASE:
+		payload = getTeamsReleasePayload(p.(*api.ReleasePayload))
+	case HOOK_EVENT_STATUS:
+		payload = getTeamsStatusPayload(p.(*StatusPayload))
+	case HOOK_EVENT_PULL_REQUEST_REVIEW:
+		payload = getTeamsPullRequestReviewPayload(p.(*PullRequestReviewPayload))
+	default:
+		return nil, errors.Errorf("unexpected event %q", event)
+	}
+	return payload, nil
+}
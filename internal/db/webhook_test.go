@@ -0,0 +1,93 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+func setMockWebhookRetry(t *testing.T, maxAttempts int, retryInterval time.Duration) {
+	beforeMaxAttempts := conf.Webhook.MaxAttempts
+	beforeRetryInterval := conf.Webhook.RetryInterval
+	conf.Webhook.MaxAttempts = maxAttempts
+	conf.Webhook.RetryInterval = retryInterval
+	t.Cleanup(func() {
+		conf.Webhook.MaxAttempts = beforeMaxAttempts
+		conf.Webhook.RetryInterval = beforeRetryInterval
+	})
+}
+
+func Test_hookRetryBackoff(t *testing.T) {
+	setMockWebhookRetry(t, 5, time.Minute)
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Minute},
+		{attempts: 2, want: 2 * time.Minute},
+		{attempts: 3, want: 4 * time.Minute},
+		{attempts: 4, want: 8 * time.Minute},
+		{attempts: 100, want: 24 * time.Hour},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, hookRetryBackoff(test.attempts), "attempts: %d", test.attempts)
+	}
+}
+
+func TestHookTask_recordAttempt(t *testing.T) {
+	setMockWebhookRetry(t, 2, time.Minute)
+
+	start := time.Now()
+	task := &HookTask{
+		ResponseInfo: &HookResponse{Body: "Delivery: connection refused"},
+	}
+
+	// The first failed attempt schedules a retry.
+	task.recordAttempt(start, true)
+	assert.False(t, task.IsDelivered)
+	assert.False(t, task.IsFailed)
+	assert.Equal(t, 1, task.Attempts)
+	assert.Equal(t, start.Add(time.Minute).Unix(), task.NextAttemptUnix)
+
+	// The task fails after running out of attempts.
+	task.recordAttempt(start, true)
+	assert.True(t, task.IsDelivered)
+	assert.True(t, task.IsFailed)
+	assert.Equal(t, 2, task.Attempts)
+	assert.Zero(t, task.NextAttemptUnix)
+
+	assert.Len(t, task.AttemptsInfo, 2)
+	assert.Equal(t, "Delivery: connection refused", task.AttemptsInfo[0].Error)
+
+	// Permanent failures are not retried.
+	task = &HookTask{ResponseInfo: &HookResponse{}}
+	task.recordAttempt(start, false)
+	assert.True(t, task.IsDelivered)
+	assert.True(t, task.IsFailed)
+
+	// Successful deliveries are done.
+	task = &HookTask{IsSucceed: true, ResponseInfo: &HookResponse{Status: 200}}
+	task.recordAttempt(start, true)
+	assert.True(t, task.IsDelivered)
+	assert.False(t, task.IsFailed)
+	assert.Equal(t, 200, task.AttemptsInfo[0].Status)
+}
+
+func TestHookTask_fail(t *testing.T) {
+	task := &HookTask{}
+	task.fail("Cannot render payload template: boom")
+	assert.True(t, task.IsDelivered)
+	assert.True(t, task.IsFailed)
+	assert.False(t, task.IsSucceed)
+	assert.Equal(t, 1, task.Attempts)
+	assert.Contains(t, task.ResponseContent, "Cannot render payload template: boom")
+	assert.Contains(t, task.AttemptsContent, "Cannot render payload template: boom")
+}
@@ -29,7 +29,8 @@ const (
 	MAIL_ISSUE_COMMENT = "issue/comment"
 	MAIL_ISSUE_MENTION = "issue/mention"
 
-	MAIL_NOTIFY_COLLABORATOR = "notify/collaborator"
+	MAIL_NOTIFY_COLLABORATOR        = "notify/collaborator"
+	MAIL_NOTIFY_ACCESS_TOKEN_EXPIRY = "notify/access_token_expiry"
 )
 
 var (
@@ -187,6 +188,30 @@ func SendCollaboratorMail(u, doer User, repo Repository) {
 	Send(msg)
 }
 
+// SendAccessTokenExpiryMail sends mail notification to the user whose personal
+// access token is about to expire.
+func SendAccessTokenExpiryMail(u User, tokenName string, expiresAt time.Time) {
+	subject := fmt.Sprintf("Your personal access token %q is about to expire", tokenName)
+
+	data := map[string]interface{}{
+		"Subject":   subject,
+		"Username":  u.DisplayName(),
+		"TokenName": tokenName,
+		"ExpiresAt": expiresAt.Format("2006-01-02 15:04 MST"),
+		"Link":      conf.Server.ExternalURL + "user/settings/applications",
+	}
+	body, err := render(MAIL_NOTIFY_ACCESS_TOKEN_EXPIRY, data)
+	if err != nil {
+		log.Error("HTMLString: %v", err)
+		return
+	}
+
+	msg := NewMessage([]string{u.Email()}, subject, body)
+	msg.Info = fmt.Sprintf("UID: %d, access token expiry", u.ID())
+
+	Send(msg)
+}
+
 func composeTplData(subject, body, link string) map[string]interface{} {
 	data := make(map[string]interface{}, 10)
 	data["Subject"] = subject
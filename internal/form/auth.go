@@ -11,7 +11,7 @@ import (
 
 type Authentication struct {
 	ID                int64
-	Type              int    `binding:"Range(2,6)"`
+	Type              int    `binding:"Range(2,7)"`
 	Name              string `binding:"Required;MaxSize(30)"`
 	Host              string
 	Port              int
@@ -31,6 +31,8 @@ type Authentication struct {
 	GroupFilter       string
 	GroupMemberUID    string
 	UserUID           string
+	AdminGroupDN      string `form:"admin_group_dn"`
+	GroupTeamMap      string
 	IsActive          bool
 	IsDefault         bool
 	SMTPAuth          string
@@ -42,6 +44,17 @@ type Authentication struct {
 	SkipVerify        bool
 	PAMServiceName    string
 	GitHubAPIEndpoint string `form:"github_api_endpoint" binding:"Url"`
+	OIDCDiscoveryURL  string `form:"oidc_discovery_url" binding:"Url"`
+	OIDCClientID      string `form:"oidc_client_id"`
+	OIDCClientSecret  string `form:"oidc_client_secret"`
+	OIDCScopes        string `form:"oidc_scopes"`
+	OIDCUsernameClaim string `form:"oidc_username_claim"`
+	OIDCEmailClaim    string `form:"oidc_email_claim"`
+	OIDCFullNameClaim string `form:"oidc_full_name_claim"`
+	OIDCAdminClaim    string `form:"oidc_admin_claim"`
+	OIDCGroupsClaim   string `form:"oidc_groups_claim"`
+	OIDCAdminGroup    string `form:"oidc_admin_group"`
+	OIDCRequiredGroup string `form:"oidc_required_group"`
 }
 
 func (f *Authentication) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
@@ -41,15 +41,27 @@ func (f *CreateRepo) Validate(ctx *macaron.Context, errs binding.Errors) binding
 }
 
 type MigrateRepo struct {
-	CloneAddr    string `json:"clone_addr" binding:"Required"`
-	AuthUsername string `json:"auth_username"`
-	AuthPassword string `json:"auth_password"`
-	Uid          int64  `json:"uid" binding:"Required"`
-	RepoName     string `json:"repo_name" binding:"Required;AlphaDashDot;MaxSize(100)"`
-	Mirror       bool   `json:"mirror"`
-	Private      bool   `json:"private"`
-	Unlisted     bool   `json:"unlisted"`
-	Description  string `json:"description" binding:"MaxSize(512)"`
+	CloneAddr      string `json:"clone_addr" binding:"Required"`
+	AuthUsername   string `json:"auth_username"`
+	AuthPassword   string `json:"auth_password"`
+	Uid            int64  `json:"uid" binding:"Required"`
+	RepoName       string `json:"repo_name" binding:"Required;AlphaDashDot;MaxSize(100)"`
+	Mirror         bool   `json:"mirror"`
+	MirrorRefspecs string `json:"mirror_refspecs"`
+	MirrorLFS      bool   `json:"mirror_lfs"`
+	Private        bool   `json:"private"`
+	Unlisted       bool   `json:"unlisted"`
+	Description    string `json:"description" binding:"MaxSize(512)"`
+
+	// The code hosting service to migrate metadata from, only Git data is
+	// migrated when empty.
+	Service      string `json:"service" binding:"OmitEmpty;In(git,github,gitlab,gitea)"`
+	AuthToken    string `json:"auth_token"`
+	Labels       bool   `json:"labels"`
+	Milestones   bool   `json:"milestones"`
+	Issues       bool   `json:"issues"`
+	PullRequests bool   `json:"pull_requests"`
+	Releases     bool   `json:"releases"`
 }
 
 func (f *MigrateRepo) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
@@ -103,6 +115,9 @@ type RepoSetting struct {
 	Private       bool
 	Unlisted      bool
 	EnablePrune   bool
+	Refspecs      string
+	EnableLFS     bool
+	LFSEndpoint   string
 
 	// Advanced settings
 	EnableWiki            bool
@@ -118,12 +133,32 @@ type RepoSetting struct {
 	EnablePulls           bool
 	PullsIgnoreWhitespace bool
 	PullsAllowRebase      bool
+	PullsAllowSquash      bool
+	PullsAllowFastForward bool
 }
 
 func (f *RepoSetting) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// ParseLFSEndpoint checks if given LFS endpoint of the mirror is valid, an
+// empty endpoint is valid and means the endpoint is derived from the mirror
+// address.
+func (f *RepoSetting) ParseLFSEndpoint() (string, error) {
+	endpoint := strings.TrimSpace(f.LFSEndpoint)
+	if endpoint == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", db.ErrInvalidCloneAddr{IsURLError: true}
+	} else if netutil.IsBlockedLocalHostname(u.Hostname(), conf.Security.LocalNetworkAllowlist) {
+		return "", db.ErrInvalidCloneAddr{IsBlockedLocalAddress: true}
+	}
+	return u.String(), nil
+}
+
 // __________                             .__
 // \______   \____________    ____   ____ |  |__
 //  |    |  _/\_  __ \__  \  /    \_/ ___\|  |  \
@@ -132,17 +167,69 @@ func (f *RepoSetting) Validate(ctx *macaron.Context, errs binding.Errors) bindin
 //         \/             \/     \/     \/     \/
 
 type ProtectBranch struct {
-	Protected          bool
-	RequirePullRequest bool
-	EnableWhitelist    bool
-	WhitelistUsers     string
-	WhitelistTeams     string
+	Protected             bool
+	RequirePullRequest    bool
+	EnableWhitelist       bool
+	WhitelistUsers        string
+	WhitelistTeams        string
+	EnableStatusCheck     bool
+	StatusCheckContexts   string
+	RequiredApprovals     int `binding:"Range(0,100)"`
+	DismissStaleApprovals bool
 }
 
 func (f *ProtectBranch) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+type NewPushMirror struct {
+	RemoteAddr   string `json:"remote_addr" binding:"Required" locale:"repo.settings.push_mirrors.remote_addr"`
+	AuthUsername string `json:"auth_username"`
+	AuthPassword string `json:"auth_password"`
+	// The number of hours between syncs, zero means no periodic sync.
+	SyncInterval int  `json:"sync_interval" binding:"Range(0,8760)" locale:"repo.settings.push_mirrors.sync_interval"`
+	SyncOnPush   bool `json:"sync_on_push"`
+}
+
+func (f *NewPushMirror) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// ParseRemoteAddr checks if given remote address is valid, and returns the
+// address without credentials. Credentials in the address are moved to the
+// username and password unless they are given separately. It also checks if
+// given user has permission when remote address is actually a local path.
+func (f *NewPushMirror) ParseRemoteAddr(user *db.User) (string, error) {
+	remoteAddr := strings.TrimSpace(f.RemoteAddr)
+
+	// Remote address can be HTTP/HTTPS URL or local path.
+	if strings.HasPrefix(remoteAddr, "http://") ||
+		strings.HasPrefix(remoteAddr, "https://") {
+		u, err := url.Parse(remoteAddr)
+		if err != nil {
+			return "", db.ErrInvalidCloneAddr{IsURLError: true}
+		}
+
+		if netutil.IsBlockedLocalHostname(u.Hostname(), conf.Security.LocalNetworkAllowlist) {
+			return "", db.ErrInvalidCloneAddr{IsBlockedLocalAddress: true}
+		}
+
+		if u.User != nil && len(f.AuthUsername)+len(f.AuthPassword) == 0 {
+			f.AuthUsername = u.User.Username()
+			f.AuthPassword, _ = u.User.Password()
+		}
+		u.User = nil
+		return u.String(), nil
+	}
+
+	if !user.CanImportLocal() {
+		return "", db.ErrInvalidCloneAddr{IsPermissionDenied: true}
+	} else if !com.IsDir(strings.TrimPrefix(remoteAddr, "file://")) {
+		return "", db.ErrInvalidCloneAddr{IsInvalidPath: true}
+	}
+	return remoteAddr, nil
+}
+
 //  __      __      ___.   .__    .__            __
 // /  \    /  \ ____\_ |__ |  |__ |  |__   ____ |  | __
 // \   \/\/   // __ \| __ \|  |  \|  |  \ /  _ \|  |/ /
@@ -160,7 +247,10 @@ type Webhook struct {
 	IssueComment bool
 	PullRequest  bool
 	Release      bool
+	Status       bool
 	Active       bool
+
+	PullRequestReview bool
 }
 
 func (f Webhook) PushOnly() bool {
@@ -220,6 +310,40 @@ func (f *NewDingtalkHook) Validate(ctx *macaron.Context, errs binding.Errors) bi
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+type NewMattermostHook struct {
+	PayloadURL string `binding:"Required;Url"`
+	Channel    string
+	Username   string
+	IconURL    string
+	Color      string
+	Webhook
+}
+
+func (f *NewMattermostHook) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+type NewTeamsHook struct {
+	PayloadURL string `binding:"Required;Url"`
+	Webhook
+}
+
+func (f *NewTeamsHook) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+type NewCustomHook struct {
+	PayloadURL        string `binding:"Required;Url"`
+	Template          string `binding:"Required"`
+	CustomContentType string `binding:"Required;MaxSize(255)"`
+	Secret            string
+	Webhook
+}
+
+func (f *NewCustomHook) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // .___
 // |   | ______ ________ __   ____
 // |   |/  ___//  ___/  |  \_/ __ \
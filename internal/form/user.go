@@ -148,9 +148,23 @@ func (f *AddSSHKey) Validate(ctx *macaron.Context, errs binding.Errors) binding.
 }
 
 type NewAccessToken struct {
-	Name string `binding:"Required"`
+	Name   string `binding:"Required"`
+	Scopes []string
+	// The number of days before the token expires, zero means never.
+	Expiration int `binding:"Range(0,365)"`
 }
 
 func (f *NewAccessToken) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
+
+type OAuth2Application struct {
+	Name         string `binding:"Required;MaxSize(255)" locale:"settings.oauth2_app_name"`
+	HomepageURL  string `form:"homepage_url" binding:"Required;Url;MaxSize(255)" locale:"settings.oauth2_homepage_url"`
+	RedirectURIs string `form:"redirect_uris" binding:"Required" locale:"settings.oauth2_redirect_uris"`
+	Confidential bool
+}
+
+func (f *OAuth2Application) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+)
+
+// BlameCommit contains information of a commit that last changed some lines of
+// a file.
+type BlameCommit struct {
+	ID        string
+	Author    *git.Signature
+	Committer *git.Signature
+	// The first line of the commit message.
+	Summary string
+	// The ID of the parent commit and the path of the file in it. Both are empty
+	// when the lines were introduced by a root commit.
+	PreviousID   string
+	PreviousPath string
+}
+
+// BlamePart is consecutive lines of a file that were last changed by the same
+// commit.
+type BlamePart struct {
+	Commit *BlameCommit
+	// The line number of the first line in the file (1-based).
+	StartLine int
+	Lines     []string
+}
+
+// Blame returns blame results of the file in given revision of the repository
+// in given path, lines are grouped by the commit that last changed them.
+func Blame(repoPath, rev, file string) ([]*BlamePart, error) {
+	stdout, err := git.NewCommand("blame", "--porcelain", rev, "--", file).RunInDir(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	return parseBlamePorcelain(stdout)
+}
+
+// parseBlameTimezone returns the location of the timezone in the format of
+// "+0800".
+func parseBlameTimezone(tz string) (*time.Location, error) {
+	if len(tz) != 5 || (tz[0] != '+' && tz[0] != '-') {
+		return nil, errors.Errorf("malformed timezone %q", tz)
+	}
+	hours, err := strconv.Atoi(tz[1:3])
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse hours of timezone %q", tz)
+	}
+	minutes, err := strconv.Atoi(tz[3:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse minutes of timezone %q", tz)
+	}
+
+	offset := hours*3600 + minutes*60
+	if tz[0] == '-' {
+		offset = -offset
+	}
+	return time.FixedZone("", offset), nil
+}
+
+// unquoteBlameFilename returns the filename that git quotes when it contains
+// special characters.
+func unquoteBlameFilename(name string) string {
+	if !strings.HasPrefix(name, `"`) {
+		return name
+	}
+	unquoted, err := strconv.Unquote(name)
+	if err != nil {
+		return name
+	}
+	return unquoted
+}
+
+// parseBlamePorcelain parses the output of "git blame --porcelain".
+//
+// Docs: https://git-scm.com/docs/git-blame#_the_porcelain_format
+func parseBlamePorcelain(data []byte) ([]*BlamePart, error) {
+	commits := make(map[string]*BlameCommit)
+	parts := make([]*BlamePart, 0, 10)
+
+	var commit *BlameCommit
+	var lineNum int
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			continue
+		}
+
+		// The content of the line is prefixed with a TAB and ends the information
+		// of the line.
+		if line[0] == '\t' {
+			if commit == nil {
+				return nil, errors.Errorf("line %d has no header", lineNum)
+			}
+
+			last := len(parts) - 1
+			if last >= 0 && parts[last].Commit == commit && parts[last].StartLine+len(parts[last].Lines) == lineNum {
+				parts[last].Lines = append(parts[last].Lines, line[1:])
+			} else {
+				parts = append(parts, &BlamePart{
+					Commit:    commit,
+					StartLine: lineNum,
+					Lines:     []string{line[1:]},
+				})
+			}
+			commit = nil
+			continue
+		}
+
+		// Header of the line: "<sha1> <orig line> <final line> [<lines in group>]"
+		if commit == nil {
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				return nil, errors.Errorf("malformed header %q", line)
+			}
+
+			var err error
+			lineNum, err = strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, errors.Wrapf(err, "parse line number of header %q", line)
+			}
+
+			id := fields[0]
+			commit = commits[id]
+			if commit == nil {
+				commit = &BlameCommit{
+					ID:        id,
+					Author:    &git.Signature{},
+					Committer: &git.Signature{},
+				}
+				commits[id] = commit
+			}
+			continue
+		}
+
+		key, value := line, ""
+		if i := strings.IndexByte(line, ' '); i > 0 {
+			key, value = line[:i], line[i+1:]
+		}
+
+		var sig *git.Signature
+		switch {
+		case strings.HasPrefix(key, "author"):
+			sig = commit.Author
+		case strings.HasPrefix(key, "committer"):
+			sig = commit.Committer
+		}
+
+		switch key {
+		case "author", "committer":
+			sig.Name = value
+		case "author-mail", "committer-mail":
+			sig.Email = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		case "author-time", "committer-time":
+			sec, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parse %s %q", key, value)
+			}
+			sig.When = time.Unix(sec, 0).In(sig.When.Location())
+		case "author-tz", "committer-tz":
+			loc, err := parseBlameTimezone(value)
+			if err != nil {
+				return nil, err
+			}
+			sig.When = sig.When.In(loc)
+		case "summary":
+			commit.Summary = value
+		case "previous":
+			fields := strings.SplitN(value, " ", 2)
+			if len(fields) != 2 {
+				return nil, errors.Errorf("malformed previous %q", value)
+			}
+			commit.PreviousID = fields[0]
+			commit.PreviousPath = unquoteBlameFilename(fields[1])
+		}
+	}
+	return parts, nil
+}
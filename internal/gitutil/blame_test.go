@@ -0,0 +1,95 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBlamePorcelain(t *testing.T) {
+	output := `79ba6a9cb4409f0a3fd8c2e393c79b9e40c85af0 1 1 2
+author Alice
+author-mail <alice@example.com>
+author-time 1700000000
+author-tz +0800
+committer Bob
+committer-mail <bob@example.com>
+committer-time 1700000100
+committer-tz -0130
+summary Initial commit
+boundary
+filename main.go
+	package main
+79ba6a9cb4409f0a3fd8c2e393c79b9e40c85af0 2 2
+	
+6de1ff0fc86f517126b5f1fbbc917539bcdc347b 2 3 1
+author Bob
+author-mail <bob@example.com>
+author-time 1700000200
+author-tz +0000
+committer Bob
+committer-mail <bob@example.com>
+committer-time 1700000200
+committer-tz +0000
+summary Add main function
+previous 79ba6a9cb4409f0a3fd8c2e393c79b9e40c85af0 "old\tname.go"
+filename main.go
+	func main() {}
+79ba6a9cb4409f0a3fd8c2e393c79b9e40c85af0 4 4 1
+filename main.go
+	// EOF
+`
+	parts, err := parseBlamePorcelain([]byte(output))
+	require.NoError(t, err)
+
+	initial := &BlameCommit{
+		ID: "79ba6a9cb4409f0a3fd8c2e393c79b9e40c85af0",
+		Author: &git.Signature{
+			Name:  "Alice",
+			Email: "alice@example.com",
+			When:  time.Unix(1700000000, 0).In(time.FixedZone("", 8*3600)),
+		},
+		Committer: &git.Signature{
+			Name:  "Bob",
+			Email: "bob@example.com",
+			When:  time.Unix(1700000100, 0).In(time.FixedZone("", -90*60)),
+		},
+		Summary: "Initial commit",
+	}
+	second := &BlameCommit{
+		ID: "6de1ff0fc86f517126b5f1fbbc917539bcdc347b",
+		Author: &git.Signature{
+			Name:  "Bob",
+			Email: "bob@example.com",
+			When:  time.Unix(1700000200, 0).In(time.FixedZone("", 0)),
+		},
+		Committer: &git.Signature{
+			Name:  "Bob",
+			Email: "bob@example.com",
+			When:  time.Unix(1700000200, 0).In(time.FixedZone("", 0)),
+		},
+		Summary:      "Add main function",
+		PreviousID:   "79ba6a9cb4409f0a3fd8c2e393c79b9e40c85af0",
+		PreviousPath: "old\tname.go",
+	}
+	want := []*BlamePart{
+		{Commit: initial, StartLine: 1, Lines: []string{"package main", ""}},
+		{Commit: second, StartLine: 3, Lines: []string{"func main() {}"}},
+		{Commit: initial, StartLine: 4, Lines: []string{"// EOF"}},
+	}
+	assert.Equal(t, want, parts)
+}
+
+func TestParseBlameTimezone(t *testing.T) {
+	for _, tz := range []string{"0800", "+08", "+08a0"} {
+		_, err := parseBlameTimezone(tz)
+		assert.Error(t, err, tz)
+	}
+}
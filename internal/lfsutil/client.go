@@ -0,0 +1,311 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfsutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultEndpoint returns the default LFS endpoint of the remote Git repository
+// with given URL.
+//
+// Spec: https://github.com/git-lfs/git-lfs/blob/master/docs/api/server-discovery.md
+func DefaultEndpoint(remoteURL string) string {
+	remoteURL = strings.TrimSuffix(remoteURL, "/")
+	if !strings.HasSuffix(remoteURL, ".git") {
+		remoteURL += ".git"
+	}
+	return remoteURL + "/info/lfs"
+}
+
+// Object is an LFS object to be downloaded.
+type Object struct {
+	OID  OID
+	Size int64
+}
+
+// Client is a client of the LFS batch API for downloading objects from a remote
+// LFS server.
+type Client struct {
+	// The LFS endpoint of the remote repository, e.g.
+	// "https://example.com/owner/repo.git/info/lfs". Credentials in the endpoint
+	// are only sent to the batch API.
+	Endpoint string
+	// AllowURL reports whether objects are allowed to be downloaded from given
+	// URL, it also applies to redirects of all requests. All URLs are allowed
+	// when it is nil.
+	AllowURL func(u *url.URL) bool
+	// The HTTP client to send requests, http.DefaultClient is used when it is
+	// nil.
+	HTTPClient *http.Client
+}
+
+// batchSize is the maximum number of objects in a single batch request.
+const batchSize = 100
+
+const batchContentType = "application/vnd.git-lfs+json"
+
+type batchRequestObject struct {
+	OID  OID   `json:"oid"`
+	Size int64 `json:"size"`
+}
+
+type batchRequest struct {
+	Operation string               `json:"operation"`
+	Transfers []string             `json:"transfers"`
+	Objects   []batchRequestObject `json:"objects"`
+}
+
+type batchError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type batchAction struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header"`
+}
+
+type batchObject struct {
+	OID     OID   `json:"oid"`
+	Size    int64 `json:"size"`
+	Actions struct {
+		Download *batchAction `json:"download"`
+		// NOTE: Some servers (including Gogs) put the error of an object in its
+		// actions.
+		Error *batchError `json:"error"`
+	} `json:"actions"`
+	Error *batchError `json:"error"`
+}
+
+type batchResponse struct {
+	Transfer string        `json:"transfer"`
+	Objects  []batchObject `json:"objects"`
+	Message  string        `json:"message"`
+}
+
+// maxRedirects is the maximum number of redirects to follow for a request,
+// which is the same as the default of the http.Client.
+const maxRedirects = 10
+
+// httpClient returns the HTTP client that only follows redirects to allowed
+// URLs.
+func (c *Client) httpClient() *http.Client {
+	client := http.DefaultClient
+	if c.HTTPClient != nil {
+		client = c.HTTPClient
+	}
+	if c.AllowURL == nil {
+		return client
+	}
+
+	checked := *client
+	checked.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if !c.AllowURL(req.URL) {
+			return errors.Errorf("redirect to %q is not allowed", req.URL.Redacted())
+		} else if client.CheckRedirect != nil {
+			return client.CheckRedirect(req, via)
+		} else if len(via) >= maxRedirects {
+			return errors.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+	return &checked
+}
+
+// batch requests download actions of given objects.
+func (c *Client) batch(ctx context.Context, objects []Object) ([]batchObject, error) {
+	endpoint, err := url.Parse(c.Endpoint)
+	if err != nil {
+		// NOTE: The parse error contains credentials in the endpoint.
+		return nil, errors.New("endpoint is not a valid URL")
+	}
+	user := endpoint.User
+	endpoint.User = nil
+
+	body := batchRequest{
+		Operation: "download",
+		Transfers: []string{"basic"},
+		Objects:   make([]batchRequestObject, 0, len(objects)),
+	}
+	for _, obj := range objects {
+		body.Objects = append(body.Objects, batchRequestObject{OID: obj.OID, Size: obj.Size})
+	}
+	p, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint.String(), "/")+"/objects/batch", bytes.NewReader(p))
+	if err != nil {
+		return nil, errors.Wrap(err, "new request")
+	}
+	req.Header.Set("Accept", batchContentType)
+	req.Header.Set("Content-Type", batchContentType)
+	if user != nil {
+		password, _ := user.Password()
+		req.SetBasicAuth(user.Username(), password)
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "send request")
+	}
+	defer resp.Body.Close()
+
+	var batchResp batchResponse
+	err = json.NewDecoder(resp.Body).Decode(&batchResp)
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %d: %s", resp.StatusCode, batchResp.Message)
+	} else if err != nil {
+		return nil, errors.Wrap(err, "decode response")
+	} else if batchResp.Transfer != "" && batchResp.Transfer != "basic" {
+		return nil, errors.Errorf("unsupported transfer %q", batchResp.Transfer)
+	}
+	return batchResp.Objects, nil
+}
+
+// download downloads the object with the action to a temporary file and
+// verifies its content against the size of the pointer. The caller is
+// responsible for removing the file.
+func (c *Client) download(ctx context.Context, object batchObject, size int64) (string, error) {
+	href, err := url.Parse(object.Actions.Download.Href)
+	if err != nil {
+		return "", errors.Wrap(err, "parse href")
+	} else if c.AllowURL != nil && !c.AllowURL(href) {
+		return "", errors.Errorf("download URL %q is not allowed", href.Redacted())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, href.String(), nil)
+	if err != nil {
+		return "", errors.Wrap(err, "new request")
+	}
+	for k, v := range object.Actions.Download.Header {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "send request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	f, err := ioutil.TempFile("", "lfs-object-")
+	if err != nil {
+		return "", errors.Wrap(err, "create temporary file")
+	}
+	defer f.Close()
+
+	// Read one more byte to tell whether the content is larger than expected
+	// without copying all of it.
+	h := sha256.New()
+	written, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(resp.Body, size+1))
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", errors.Wrap(err, "copy content")
+	} else if written > size {
+		_ = os.Remove(f.Name())
+		return "", errors.Errorf("expect %d bytes but got more", size)
+	} else if written != size {
+		_ = os.Remove(f.Name())
+		return "", errors.Errorf("expect %d bytes but got %d", size, written)
+	} else if OID(hex.EncodeToString(h.Sum(nil))) != object.OID {
+		_ = os.Remove(f.Name())
+		return "", errors.New("content does not match the OID")
+	}
+	return f.Name(), nil
+}
+
+// Download downloads given objects from the remote LFS server and uploads them
+// to the storage, stored is called for every object that is uploaded. Objects
+// that failed to download do not stop others from being downloaded, and their
+// errors are returned together.
+func (c *Client) Download(ctx context.Context, objects []Object, s Storager, stored func(oid OID, size int64) error) error {
+	var failures []string
+	for start := 0; start < len(objects); start += batchSize {
+		end := start + batchSize
+		if end > len(objects) {
+			end = len(objects)
+		}
+
+		// The sizes in the response are not trusted, use those of the pointers.
+		sizes := make(map[OID]int64, end-start)
+		for _, obj := range objects[start:end] {
+			sizes[obj.OID] = obj.Size
+		}
+
+		batchObjects, err := c.batch(ctx, objects[start:end])
+		if err != nil {
+			return errors.Wrap(err, "batch")
+		}
+
+		for _, object := range batchObjects {
+			size, ok := sizes[object.OID]
+			if !ok {
+				failures = append(failures, fmt.Sprintf("%s: not requested", object.OID))
+				continue
+			}
+			// Objects may be listed more than once in a malicious response.
+			delete(sizes, object.OID)
+
+			objErr := object.Error
+			if objErr == nil {
+				objErr = object.Actions.Error
+			}
+			if objErr != nil {
+				failures = append(failures, fmt.Sprintf("%s: %s (%d)", object.OID, objErr.Message, objErr.Code))
+				continue
+			} else if object.Actions.Download == nil {
+				failures = append(failures, fmt.Sprintf("%s: no download action", object.OID))
+				continue
+			} else if !ValidOID(object.OID) {
+				failures = append(failures, fmt.Sprintf("%s: %v", object.OID, ErrInvalidOID))
+				continue
+			}
+
+			fpath, err := c.download(ctx, object, size)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", object.OID, err))
+				continue
+			}
+
+			f, err := os.Open(fpath)
+			if err != nil {
+				_ = os.Remove(fpath)
+				return errors.Wrap(err, "open temporary file")
+			}
+			_, err = s.Upload(object.OID, f)
+			_ = os.Remove(fpath)
+			if err != nil {
+				return errors.Wrapf(err, "upload object %q", object.OID)
+			}
+
+			if err = stored(object.OID, size); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return errors.Errorf("failed to download %d object(s): %s", len(failures), strings.Join(failures, "; "))
+	}
+	return nil
+}
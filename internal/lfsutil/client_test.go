@@ -0,0 +1,216 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfsutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultEndpoint(t *testing.T) {
+	tests := []struct {
+		remoteURL string
+		want      string
+	}{
+		{
+			remoteURL: "https://example.com/alice/example.git",
+			want:      "https://example.com/alice/example.git/info/lfs",
+		},
+		{
+			remoteURL: "https://example.com/alice/example",
+			want:      "https://example.com/alice/example.git/info/lfs",
+		},
+		{
+			remoteURL: "https://example.com/alice/example/",
+			want:      "https://example.com/alice/example.git/info/lfs",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.remoteURL, func(t *testing.T) {
+			assert.Equal(t, test.want, DefaultEndpoint(test.remoteURL))
+		})
+	}
+}
+
+func TestClient_Download(t *testing.T) {
+	contents := map[OID]string{}
+	newObject := func(content string) Object {
+		sum := sha256.Sum256([]byte(content))
+		oid := OID(hex.EncodeToString(sum[:]))
+		contents[oid] = content
+		return Object{OID: oid, Size: int64(len(content))}
+	}
+	hello := newObject("Hello world!")
+	corrupted := newObject("Corrupted")
+	redirected := newObject("Redirected")
+	missing := Object{OID: "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f", Size: 1}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/alice/example.git/info/lfs/objects/batch":
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "alice" || password != "p@ssw0rd" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"message":"Credentials needed"}`))
+				return
+			}
+
+			var req batchRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "download", req.Operation)
+
+			objects := make([]map[string]interface{}, 0, len(req.Objects))
+			for _, obj := range req.Objects {
+				object := map[string]interface{}{
+					"oid":  obj.OID,
+					"size": obj.Size,
+				}
+				if _, ok := contents[obj.OID]; ok {
+					href := server.URL + "/objects/" + string(obj.OID)
+					if obj.OID == redirected.OID {
+						href = server.URL + "/redirect/" + string(obj.OID)
+					}
+					object["actions"] = map[string]interface{}{
+						"download": map[string]interface{}{
+							"href":   href,
+							"header": map[string]string{"Authorization": "Bearer token"},
+						},
+					}
+				} else {
+					object["error"] = map[string]interface{}{
+						"code":    404,
+						"message": "Object does not exist",
+					}
+				}
+				objects = append(objects, object)
+			}
+			w.Header().Set("Content-Type", batchContentType)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"transfer": "basic",
+				"objects":  objects,
+			})
+
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/redirect/"):
+			http.Redirect(w, r, "/objects/"+strings.TrimPrefix(r.URL.Path, "/redirect/"), http.StatusFound)
+
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/objects/"):
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			oid := OID(strings.TrimPrefix(r.URL.Path, "/objects/"))
+			content := contents[oid]
+			if oid == corrupted.OID {
+				content = strings.ToUpper(content)
+			}
+			_, _ = w.Write([]byte(content))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL + "/alice/example.git/info/lfs")
+	require.NoError(t, err)
+	u.User = url.UserPassword("alice", "p@ssw0rd")
+
+	t.Run("download objects", func(t *testing.T) {
+		s := &LocalStorage{Root: t.TempDir()}
+		client := &Client{Endpoint: u.String()}
+
+		var stored []OID
+		err := client.Download(context.Background(), []Object{hello, corrupted, missing}, s,
+			func(oid OID, size int64) error {
+				stored = append(stored, oid)
+				return nil
+			},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to download 2 object(s)")
+		assert.Contains(t, err.Error(), fmt.Sprintf("%s: content does not match the OID", corrupted.OID))
+		assert.Contains(t, err.Error(), fmt.Sprintf("%s: Object does not exist (404)", missing.OID))
+		assert.Equal(t, []OID{hello.OID}, stored)
+
+		var buf bytes.Buffer
+		require.NoError(t, s.Download(hello.OID, &buf))
+		assert.Equal(t, "Hello world!", buf.String())
+		assert.Equal(t, ErrObjectNotExist, s.Download(corrupted.OID, &buf))
+	})
+
+	t.Run("download URL not allowed", func(t *testing.T) {
+		s := &LocalStorage{Root: t.TempDir()}
+		client := &Client{
+			Endpoint: u.String(),
+			AllowURL: func(u *url.URL) bool {
+				return !strings.HasPrefix(u.Path, "/objects/")
+			},
+		}
+
+		err := client.Download(context.Background(), []Object{hello}, s,
+			func(oid OID, size int64) error {
+				t.Fatal("object should not be stored")
+				return nil
+			},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not allowed")
+	})
+
+	t.Run("redirect not allowed", func(t *testing.T) {
+		s := &LocalStorage{Root: t.TempDir()}
+		client := &Client{
+			Endpoint: u.String(),
+			AllowURL: func(u *url.URL) bool {
+				return !strings.HasPrefix(u.Path, "/objects/")
+			},
+		}
+
+		err := client.Download(context.Background(), []Object{redirected}, s,
+			func(oid OID, size int64) error {
+				t.Fatal("object should not be stored")
+				return nil
+			},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "redirect to")
+		assert.Contains(t, err.Error(), "is not allowed")
+	})
+
+	t.Run("content larger than pointer", func(t *testing.T) {
+		s := &LocalStorage{Root: t.TempDir()}
+		client := &Client{Endpoint: u.String()}
+
+		err := client.Download(context.Background(), []Object{{OID: hello.OID, Size: 5}}, s,
+			func(oid OID, size int64) error {
+				t.Fatal("object should not be stored")
+				return nil
+			},
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("%s: expect 5 bytes but got more", hello.OID))
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		client := &Client{Endpoint: server.URL + "/alice/example.git/info/lfs"}
+		err := client.Download(context.Background(), []Object{hello}, &LocalStorage{Root: t.TempDir()}, nil)
+		require.Error(t, err)
+		assert.Equal(t, "batch: unexpected status 401: Credentials needed", err.Error())
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfsutil
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// MaxPointerSize is the maximum size in bytes of a pointer file.
+const MaxPointerSize = 1024
+
+// ParsePointer returns the OID and size of the LFS object that is referenced by
+// the content of a pointer file. It returns false if the content is not a valid
+// pointer file.
+//
+// Spec: https://github.com/git-lfs/git-lfs/blob/master/docs/spec.md
+func ParsePointer(content []byte) (OID, int64, bool) {
+	if len(content) > MaxPointerSize {
+		return "", 0, false
+	}
+
+	var version string
+	var oid OID
+	size := int64(-1)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "version":
+			version = fields[1]
+		case "oid":
+			oid = OID(strings.TrimPrefix(fields[1], "sha256:"))
+		case "size":
+			v, err := strconv.ParseInt(fields[1], 10, 64)
+			if err == nil {
+				size = v
+			}
+		}
+	}
+
+	switch version {
+	case "https://git-lfs.github.com/spec/v1", "https://hawser.github.com/spec/v1":
+	default:
+		return "", 0, false
+	}
+	if !ValidOID(oid) || size < 0 {
+		return "", 0, false
+	}
+	return oid, size, true
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfsutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		expOID  OID
+		expSize int64
+		expOK   bool
+	}{
+		{
+			name:    "valid pointer",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f\nsize 12\n",
+			expOID:  "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f",
+			expSize: 12,
+			expOK:   true,
+		},
+		{
+			name:    "legacy version",
+			content: "version https://hawser.github.com/spec/v1\noid sha256:ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f\nsize 12\n",
+			expOID:  "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f",
+			expSize: 12,
+			expOK:   true,
+		},
+		{
+			name:    "unknown version",
+			content: "version https://example.com/spec/v1\noid sha256:ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f\nsize 12\n",
+		},
+		{
+			name:    "invalid oid",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:bad_oid\nsize 12\n",
+		},
+		{
+			name:    "missing size",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f\n",
+		},
+		{
+			name:    "regular file",
+			content: "Hello world!\n",
+		},
+		{
+			name:    "too large",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f\nsize 12\n" + strings.Repeat("x", MaxPointerSize),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oid, size, ok := ParsePointer([]byte(test.content))
+			assert.Equal(t, test.expOID, oid)
+			assert.Equal(t, test.expSize, size)
+			assert.Equal(t, test.expOK, ok)
+		})
+	}
+}
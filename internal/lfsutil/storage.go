@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -28,6 +29,9 @@ type Storager interface {
 	// responsibility the close the writer when needed. ErrObjectNotExist is
 	// returned if the given oid does not exist.
 	Download(oid OID, w io.Writer) error
+	// Delete deletes the content of given oid. It is not an error if the oid
+	// does not exist.
+	Delete(oid OID) error
 }
 
 // Storage is the storage type of an LFS object.
@@ -35,8 +39,19 @@ type Storage string
 
 const (
 	StorageLocal Storage = "local"
+	StorageS3    Storage = "s3"
 )
 
+// Presigner is an optional interface of storage backends that are able to
+// generate temporary URLs for clients to download LFS objects directly from the
+// storage backend.
+type Presigner interface {
+	// PresignDownloadURL returns a presigned URL for downloading given oid and the
+	// duration that the URL expires in. It returns an empty URL when presigned
+	// URLs are not enabled for the storage backend.
+	PresignDownloadURL(oid OID) (string, time.Duration, error)
+}
+
 var _ Storager = (*LocalStorage)(nil)
 
 // LocalStorage is a LFS storage backend on local file system.
@@ -107,3 +122,16 @@ func (s *LocalStorage) Download(oid OID, w io.Writer) error {
 	}
 	return nil
 }
+
+func (s *LocalStorage) Delete(oid OID) error {
+	fpath := s.storagePath(oid)
+	if fpath == "" {
+		return nil
+	}
+
+	err := os.Remove(fpath)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "remove file")
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfsutil
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pkg/errors"
+)
+
+var (
+	_ Storager  = (*S3Storage)(nil)
+	_ Presigner = (*S3Storage)(nil)
+)
+
+// S3StorageOptions contains options for creating a S3Storage.
+type S3StorageOptions struct {
+	// The host (and optional port) of the S3-compatible service, e.g.
+	// "s3.amazonaws.com" or "localhost:9000".
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	// The bucket to store LFS objects, it must exist before use.
+	Bucket string
+	Region string
+	UseSSL bool
+	// The duration that presigned download URLs expire in, zero value disables
+	// presigned URLs.
+	PresignExpiry time.Duration
+}
+
+// S3Storage is a LFS storage backend on S3-compatible object storage services,
+// e.g. Amazon S3 and MinIO.
+type S3Storage struct {
+	client        *minio.Client
+	bucket        string
+	presignExpiry time.Duration
+}
+
+// NewS3Storage returns a new S3Storage with given options.
+func NewS3Storage(opts S3StorageOptions) (*S3Storage, error) {
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKeyID, opts.SecretAccessKey, ""),
+		Secure: opts.UseSSL,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "new client")
+	}
+	return &S3Storage{
+		client:        client,
+		bucket:        opts.Bucket,
+		presignExpiry: opts.PresignExpiry,
+	}, nil
+}
+
+func (*S3Storage) Storage() Storage {
+	return StorageS3
+}
+
+// objectName returns the name of the object for given oid, which uses the same
+// layout as the LocalStorage.
+func (*S3Storage) objectName(oid OID) string {
+	if len(oid) < 2 {
+		return ""
+	}
+
+	return path.Join(string(oid[0]), string(oid[1]), string(oid))
+}
+
+// s3PartSize is the size of each part for multipart uploads. Objects are
+// uploaded with unknown size, which makes the client buffer a whole part in
+// memory.
+const s3PartSize = 16 << 20
+
+func (s *S3Storage) Upload(oid OID, rc io.ReadCloser) (int64, error) {
+	defer rc.Close()
+
+	if !ValidOID(oid) {
+		return 0, ErrInvalidOID
+	}
+
+	info, err := s.client.PutObject(context.Background(), s.bucket, s.objectName(oid), rc, -1,
+		minio.PutObjectOptions{
+			ContentType: "application/octet-stream",
+			PartSize:    s3PartSize,
+		},
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "put object")
+	}
+	return info.Size, nil
+}
+
+func (s *S3Storage) Download(oid OID, w io.Writer) error {
+	if !ValidOID(oid) {
+		return ErrObjectNotExist
+	}
+
+	obj, err := s.client.GetObject(context.Background(), s.bucket, s.objectName(oid), minio.GetObjectOptions{})
+	if err != nil {
+		return errors.Wrap(err, "get object")
+	}
+	defer obj.Close()
+
+	// The request is only sent when reading the object, use Stat to check the
+	// existence before writing anything.
+	_, err = obj.Stat()
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return ErrObjectNotExist
+		}
+		return errors.Wrap(err, "stat object")
+	}
+
+	_, err = io.Copy(w, obj)
+	if err != nil {
+		return errors.Wrap(err, "copy object")
+	}
+	return nil
+}
+
+func (s *S3Storage) Delete(oid OID) error {
+	if !ValidOID(oid) {
+		return nil
+	}
+
+	// Removing a non-existent object is not an error for S3-compatible services.
+	err := s.client.RemoveObject(context.Background(), s.bucket, s.objectName(oid), minio.RemoveObjectOptions{})
+	if err != nil {
+		return errors.Wrap(err, "remove object")
+	}
+	return nil
+}
+
+func (s *S3Storage) PresignDownloadURL(oid OID) (string, time.Duration, error) {
+	if s.presignExpiry <= 0 {
+		return "", 0, nil
+	}
+
+	u, err := s.client.PresignedGetObject(context.Background(), s.bucket, s.objectName(oid), s.presignExpiry, url.Values{})
+	if err != nil {
+		return "", 0, errors.Wrap(err, "presign get object")
+	}
+	return u.String(), s.presignExpiry, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfsutil
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3Storage_objectName(t *testing.T) {
+	s := &S3Storage{}
+
+	tests := []struct {
+		name    string
+		oid     OID
+		expName string
+	}{
+		{
+			name: "empty oid",
+			oid:  "",
+		},
+
+		{
+			name:    "valid oid",
+			oid:     "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f",
+			expName: "e/f/ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expName, s.objectName(test.oid))
+		})
+	}
+}
+
+// newTestS3Storage returns a new S3Storage backed by the S3-compatible service
+// specified by environment variables, e.g. a local MinIO server. The test is
+// skipped when the service is not specified.
+func newTestS3Storage(t *testing.T) *S3Storage {
+	endpoint := os.Getenv("GOGS_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("GOGS_TEST_S3_ENDPOINT is not set")
+	}
+
+	bucket := os.Getenv("GOGS_TEST_S3_BUCKET")
+	if bucket == "" {
+		bucket = "gogs-lfs-test"
+	}
+	s, err := NewS3Storage(S3StorageOptions{
+		Endpoint:        endpoint,
+		AccessKeyID:     os.Getenv("GOGS_TEST_S3_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("GOGS_TEST_S3_SECRET_ACCESS_KEY"),
+		Bucket:          bucket,
+		PresignExpiry:   time.Minute,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	exists, err := s.client.BucketExists(ctx, bucket)
+	require.NoError(t, err)
+	if !exists {
+		err = s.client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{})
+		require.NoError(t, err)
+	}
+	return s
+}
+
+func TestS3Storage(t *testing.T) {
+	s := newTestS3Storage(t)
+
+	oid := OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+	t.Cleanup(func() {
+		_ = s.client.RemoveObject(context.Background(), s.bucket, s.objectName(oid), minio.RemoveObjectOptions{})
+	})
+
+	t.Run("upload with invalid oid", func(t *testing.T) {
+		written, err := s.Upload("bad_oid", ioutil.NopCloser(strings.NewReader("Hello world!")))
+		assert.Equal(t, int64(0), written)
+		assert.Equal(t, ErrInvalidOID, err)
+	})
+
+	t.Run("download non-existent object", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := s.Download(oid, &buf)
+		assert.Equal(t, ErrObjectNotExist, err)
+	})
+
+	t.Run("upload and download", func(t *testing.T) {
+		written, err := s.Upload(oid, ioutil.NopCloser(strings.NewReader("Hello world!")))
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), written)
+
+		var buf bytes.Buffer
+		err = s.Download(oid, &buf)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello world!", buf.String())
+	})
+
+	t.Run("presign download URL", func(t *testing.T) {
+		href, expiresIn, err := s.PresignDownloadURL(oid)
+		require.NoError(t, err)
+		assert.Contains(t, href, s.objectName(oid))
+		assert.Equal(t, time.Minute, expiresIn)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := s.Delete(oid)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		err = s.Download(oid, &buf)
+		assert.Equal(t, ErrObjectNotExist, err)
+
+		// Deleting a non-existent object should be noop
+		err = s.Delete(oid)
+		require.NoError(t, err)
+	})
+}
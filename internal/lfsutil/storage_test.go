@@ -14,6 +14,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"gogs.io/gogs/internal/osutil"
 )
 
 func TestLocalStorage_storagePath(t *testing.T) {
@@ -132,3 +134,26 @@ func TestLocalStorage_Download(t *testing.T) {
 		})
 	}
 }
+
+func TestLocalStorage_Delete(t *testing.T) {
+	oid := OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+	s := &LocalStorage{
+		Root: filepath.Join(os.TempDir(), "lfs-objects"),
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(s.Root)
+	})
+
+	_, err := s.Upload(oid, ioutil.NopCloser(strings.NewReader("Hello world!")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.Delete(oid)
+	assert.Nil(t, err)
+	assert.False(t, osutil.IsFile(s.storagePath(oid)))
+
+	// Deleting a non-existent object should be noop
+	err = s.Delete(oid)
+	assert.Nil(t, err)
+}
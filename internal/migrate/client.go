@@ -0,0 +1,247 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// perPage is the number of items requested for each page.
+const perPage = 50
+
+// requestTimeout is the timeout of a single API request.
+const requestTimeout = time.Minute
+
+// maxRateLimitWait is the maximum duration to wait for the rate limit to be
+// reset before giving up.
+const maxRateLimitWait = time.Hour
+
+// errNotFound is returned when the requested resource does not exist or is
+// disabled on the service.
+var errNotFound = errors.New("not found")
+
+// client is a client of the REST API of a service.
+type client struct {
+	baseURL    string
+	header     http.Header
+	allowURL   func(u *url.URL) bool
+	httpClient *http.Client
+}
+
+func newClient(baseURL string, header http.Header, opts Options) *client {
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		header:     header,
+		allowURL:   opts.AllowURL,
+		httpClient: httpClient,
+	}
+}
+
+// do sends the GET request to given URL, and waits for the rate limit to be
+// reset if it is exceeded. It is caller's responsibility to close the body of
+// the returned response.
+func (c *client) do(ctx context.Context, rawURL string) (*http.Response, error) {
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "new request")
+		}
+		for k, v := range c.header {
+			req.Header[k] = v
+		}
+
+		resp, err := c.checkedClient().Do(req)
+		if err != nil {
+			return nil, errors.Wrap(err, "send request")
+		}
+
+		wait, limited := rateLimitWait(resp)
+		if !limited {
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+		if wait > maxRateLimitWait {
+			return nil, errors.Errorf("rate limit exceeded, reset in %s", wait)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// rateLimitWait returns the duration to wait for the rate limit to be reset,
+// and whether the response is rejected because of the rate limit.
+func rateLimitWait(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	// GitHub uses "X-RateLimit-*" and GitLab uses "RateLimit-*".
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+	reset := resp.Header.Get("X-RateLimit-Reset")
+	if remaining == "" {
+		remaining = resp.Header.Get("RateLimit-Remaining")
+		reset = resp.Header.Get("RateLimit-Reset")
+	}
+	if remaining != "0" {
+		return 0, false
+	}
+
+	unix, err := strconv.ParseInt(reset, 10, 64)
+	if err != nil {
+		return time.Minute, true
+	}
+	wait := time.Until(time.Unix(unix, 0))
+	if wait < time.Second {
+		wait = time.Second
+	}
+	return wait, true
+}
+
+// get requests the API path with given query and decodes the JSON response
+// into v. It returns headers of the response.
+func (c *client) get(ctx context.Context, path string, query url.Values, v interface{}) (http.Header, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	rawURL := c.baseURL + path
+	if len(query) > 0 {
+		rawURL += "?" + query.Encode()
+	}
+	resp, err := c.do(ctx, rawURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", path)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return nil, errNotFound
+	default:
+		p, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, errors.Errorf("GET %s: unexpected status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(p)))
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, errors.Wrapf(err, "GET %s: decode response", path)
+	}
+	return resp.Header, nil
+}
+
+// getList is like get but treats resources that do not exist or are disabled
+// as empty lists.
+func (c *client) getList(ctx context.Context, path string, query url.Values, v interface{}) (http.Header, error) {
+	header, err := c.get(ctx, path, query, v)
+	if err == errNotFound {
+		return http.Header{}, nil
+	}
+	return header, err
+}
+
+// maxRedirects is the maximum number of redirects to follow for a request,
+// which is the same as the default of the http.Client.
+const maxRedirects = 10
+
+// checkedClient returns the HTTP client that only follows redirects to allowed
+// URLs, and does not send headers of the client to other hosts.
+func (c *client) checkedClient() *http.Client {
+	checked := *c.httpClient
+	checked.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if c.allowURL != nil && !c.allowURL(req.URL) {
+			return errors.Errorf("redirect to %q is not allowed", req.URL.Redacted())
+		}
+
+		// NOTE: The http.Client only removes the "Authorization" and "Cookie"
+		// headers when redirected to another host, but other headers (e.g. the
+		// "PRIVATE-TOKEN" of GitLab) also contain credentials.
+		if req.URL.Host != via[0].URL.Host {
+			for k := range c.header {
+				req.Header.Del(k)
+			}
+		}
+
+		if c.httpClient.CheckRedirect != nil {
+			return c.httpClient.CheckRedirect(req, via)
+		} else if len(via) >= maxRedirects {
+			return errors.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+	return &checked
+}
+
+// download opens the content of given URL. Headers of the client are only sent
+// when the URL has the same host as the API.
+func (c *client) download(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse URL")
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("URL %q is not HTTP or HTTPS", u.Redacted())
+	} else if c.allowURL != nil && !c.allowURL(u) {
+		return nil, errors.Errorf("URL %q is not allowed", u.Redacted())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "new request")
+	}
+	base, err := url.Parse(c.baseURL)
+	if err == nil && base.Host == u.Host {
+		for k, v := range c.header {
+			req.Header[k] = v
+		}
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+
+	resp, err := c.checkedClient().Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "send request")
+	} else if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, errors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// pageQuery returns the query to request the page (starting from 1).
+func pageQuery(page int) url.Values {
+	return url.Values{
+		"page":     []string{strconv.Itoa(page)},
+		"per_page": []string{strconv.Itoa(perPage)},
+	}
+}
+
+// basicAuthHeader returns headers for the HTTP Basic Authentication.
+func basicAuthHeader(username, password string) http.Header {
+	req := &http.Request{Header: http.Header{}}
+	req.SetBasicAuth(username, password)
+	return req.Header
+}
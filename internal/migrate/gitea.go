@@ -0,0 +1,313 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type giteaUser struct {
+	Login string `json:"login"`
+	Email string `json:"email"`
+}
+
+type giteaLabel struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+type giteaMilestone struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	DueOn       *time.Time `json:"due_on"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+type giteaIssue struct {
+	Number      int64           `json:"number"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	User        *giteaUser      `json:"user"`
+	Labels      []giteaLabel    `json:"labels"`
+	Milestone   *giteaMilestone `json:"milestone"`
+	State       string          `json:"state"`
+	Comments    int             `json:"comments"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	ClosedAt    *time.Time      `json:"closed_at"`
+	PullRequest *struct{}       `json:"pull_request"`
+}
+
+type giteaComment struct {
+	User      *giteaUser `json:"user"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type giteaBranch struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+type giteaPullRequest struct {
+	giteaIssue
+	Head           giteaBranch `json:"head"`
+	Base           giteaBranch `json:"base"`
+	Merged         bool        `json:"merged"`
+	MergedAt       *time.Time  `json:"merged_at"`
+	MergeCommitSHA string      `json:"merge_commit_sha"`
+}
+
+type giteaRelease struct {
+	TagName         string     `json:"tag_name"`
+	TargetCommitish string     `json:"target_commitish"`
+	Name            string     `json:"name"`
+	Body            string     `json:"body"`
+	Draft           bool       `json:"draft"`
+	Prerelease      bool       `json:"prerelease"`
+	Author          *giteaUser `json:"author"`
+	CreatedAt       time.Time  `json:"created_at"`
+	Assets          []struct {
+		Name               string `json:"name"`
+		Size               int64  `json:"size"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+var _ Downloader = (*giteaDownloader)(nil)
+
+type giteaDownloader struct {
+	client *client
+	// The API path of the repository, e.g. "/repos/gogs/gogs".
+	repoPath string
+}
+
+// newGiteaDownloader returns a downloader for Gitea. Gitea may be served under
+// a sub-path, thus segments except the last two are the prefix of the API.
+func newGiteaDownloader(u *url.URL, segments []string, opts Options) *giteaDownloader {
+	header := http.Header{}
+	if opts.Username != "" || opts.Password != "" {
+		header = basicAuthHeader(opts.Username, opts.Password)
+	}
+	if opts.Token != "" {
+		header.Set("Authorization", "token "+opts.Token)
+	}
+
+	n := len(segments)
+	prefix := ""
+	if n > 2 {
+		prefix = "/" + strings.Join(segments[:n-2], "/")
+	}
+	return &giteaDownloader{
+		client:   newClient(u.Scheme+"://"+u.Host+prefix+"/api/v1", header, opts),
+		repoPath: "/repos/" + url.PathEscape(segments[n-2]) + "/" + url.PathEscape(segments[n-1]),
+	}
+}
+
+// giteaPageQuery returns the query to request the page, Gitea uses "limit"
+// instead of "per_page".
+func giteaPageQuery(page int) url.Values {
+	query := pageQuery(page)
+	query.Del("per_page")
+	query.Set("limit", strconv.Itoa(perPage))
+	return query
+}
+
+func (*giteaDownloader) user(u *giteaUser) User {
+	if u == nil {
+		return User{Name: "ghost"}
+	}
+	return User{Name: u.Login, Email: u.Email}
+}
+
+func (d *giteaDownloader) Comments(ctx context.Context, number int64, _ bool) ([]*Comment, error) {
+	var comments []*Comment
+	for page := 1; ; page++ {
+		var raw []giteaComment
+		_, err := d.client.getList(ctx, fmt.Sprintf("%s/issues/%d/comments", d.repoPath, number), giteaPageQuery(page), &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range raw {
+			comments = append(comments, &Comment{
+				Poster:  d.user(c.User),
+				Content: c.Body,
+				Created: c.CreatedAt,
+				Updated: c.UpdatedAt,
+			})
+		}
+		if len(raw) < perPage {
+			return comments, nil
+		}
+	}
+}
+
+func (d *giteaDownloader) DownloadAsset(ctx context.Context, asset *ReleaseAsset) (io.ReadCloser, error) {
+	return d.client.download(ctx, asset.DownloadURL)
+}
+
+func (d *giteaDownloader) convertIssue(raw *giteaIssue) *Issue {
+	issue := &Issue{
+		Number:      raw.Number,
+		Title:       raw.Title,
+		Content:     raw.Body,
+		Poster:      d.user(raw.User),
+		IsClosed:    raw.State == "closed",
+		Created:     raw.CreatedAt,
+		Updated:     raw.UpdatedAt,
+		Closed:      raw.ClosedAt,
+		NumComments: raw.Comments,
+	}
+	for _, label := range raw.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	if raw.Milestone != nil {
+		issue.Milestone = raw.Milestone.Title
+	}
+	return issue
+}
+
+func (d *giteaDownloader) Issues(ctx context.Context, page int) ([]*Issue, bool, error) {
+	query := giteaPageQuery(page)
+	query.Set("state", "all")
+	query.Set("type", "issues")
+
+	var raw []giteaIssue
+	_, err := d.client.getList(ctx, d.repoPath+"/issues", query, &raw)
+	if err != nil {
+		return nil, false, err
+	}
+
+	issues := make([]*Issue, 0, len(raw))
+	for i := range raw {
+		// NOTE: Old versions of Gitea do not support filtering by type.
+		if raw[i].PullRequest != nil {
+			continue
+		}
+		issues = append(issues, d.convertIssue(&raw[i]))
+	}
+	return issues, len(raw) < perPage, nil
+}
+
+func (d *giteaDownloader) Labels(ctx context.Context) ([]*Label, error) {
+	var labels []*Label
+	for page := 1; ; page++ {
+		var raw []giteaLabel
+		_, err := d.client.getList(ctx, d.repoPath+"/labels", giteaPageQuery(page), &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range raw {
+			labels = append(labels, &Label{
+				Name:        l.Name,
+				Color:       normalizeColor(l.Color),
+				Description: l.Description,
+			})
+		}
+		if len(raw) < perPage {
+			return labels, nil
+		}
+	}
+}
+
+func (d *giteaDownloader) Milestones(ctx context.Context) ([]*Milestone, error) {
+	var milestones []*Milestone
+	for page := 1; ; page++ {
+		query := giteaPageQuery(page)
+		query.Set("state", "all")
+
+		var raw []giteaMilestone
+		_, err := d.client.getList(ctx, d.repoPath+"/milestones", query, &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range raw {
+			milestones = append(milestones, &Milestone{
+				Title:       m.Title,
+				Description: m.Description,
+				Deadline:    m.DueOn,
+				IsClosed:    m.State == "closed",
+				Created:     m.CreatedAt,
+				Closed:      m.ClosedAt,
+			})
+		}
+		if len(raw) < perPage {
+			return milestones, nil
+		}
+	}
+}
+
+func (d *giteaDownloader) PullRequests(ctx context.Context, page int) ([]*PullRequest, bool, error) {
+	query := giteaPageQuery(page)
+	query.Set("state", "all")
+
+	var raw []giteaPullRequest
+	_, err := d.client.getList(ctx, d.repoPath+"/pulls", query, &raw)
+	if err != nil {
+		return nil, false, err
+	}
+
+	pulls := make([]*PullRequest, 0, len(raw))
+	for i := range raw {
+		pulls = append(pulls, &PullRequest{
+			Issue:          *d.convertIssue(&raw[i].giteaIssue),
+			Head:           PullRequestBranch{Ref: raw[i].Head.Ref, SHA: raw[i].Head.SHA},
+			Base:           PullRequestBranch{Ref: raw[i].Base.Ref, SHA: raw[i].Base.SHA},
+			IsMerged:       raw[i].Merged,
+			Merged:         raw[i].MergedAt,
+			MergeCommitSHA: raw[i].MergeCommitSHA,
+		})
+	}
+	return pulls, len(raw) < perPage, nil
+}
+
+func (d *giteaDownloader) Releases(ctx context.Context) ([]*Release, error) {
+	var releases []*Release
+	for page := 1; ; page++ {
+		var raw []giteaRelease
+		_, err := d.client.getList(ctx, d.repoPath+"/releases", giteaPageQuery(page), &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range raw {
+			release := &Release{
+				TagName:      r.TagName,
+				Target:       r.TargetCommitish,
+				Name:         r.Name,
+				Body:         r.Body,
+				IsDraft:      r.Draft,
+				IsPrerelease: r.Prerelease,
+				Publisher:    d.user(r.Author),
+				Created:      r.CreatedAt,
+			}
+			for _, asset := range r.Assets {
+				release.Assets = append(release.Assets, &ReleaseAsset{
+					Name:        asset.Name,
+					Size:        asset.Size,
+					DownloadURL: asset.BrowserDownloadURL,
+				})
+			}
+			releases = append(releases, release)
+		}
+		if len(raw) < perPage {
+			return releases, nil
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGiteaDownloader(t *testing.T) {
+	server := newFixtureServer(t, ServiceGitea, func(r *http.Request) bool {
+		username, password, ok := r.BasicAuth()
+		return ok && username == "alice" && password == "p@ssw0rd"
+	})
+
+	ctx := context.Background()
+	// Gitea is served under the sub-path "/git".
+	d, err := NewDownloader(ServiceGitea, Options{
+		CloneAddr: server.URL + "/git/alice/example.git",
+		Username:  "alice",
+		Password:  "p@ssw0rd",
+	})
+	require.NoError(t, err)
+
+	t.Run("labels", func(t *testing.T) {
+		labels, err := d.Labels(ctx)
+		require.NoError(t, err)
+		want := []*Label{
+			{Name: "bug", Color: "#ee0701", Description: "Something is not working"},
+		}
+		assert.Equal(t, want, labels)
+	})
+
+	t.Run("milestones", func(t *testing.T) {
+		milestones, err := d.Milestones(ctx)
+		require.NoError(t, err)
+		want := []*Milestone{
+			{
+				Title:       "v1.0",
+				Description: "First release",
+				Deadline:    mustParseTime(t, "2026-02-01T08:00:00Z"),
+				IsClosed:    true,
+				Created:     *mustParseTime(t, "2026-01-02T10:00:00Z"),
+				Closed:      mustParseTime(t, "2026-02-01T10:00:00Z"),
+			},
+		}
+		assert.Equal(t, want, milestones)
+	})
+
+	t.Run("issues", func(t *testing.T) {
+		issues, isLast, err := d.Issues(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, isLast)
+		want := []*Issue{
+			{
+				Number:      1,
+				Title:       "Crash on startup",
+				Content:     "It crashes.",
+				Poster:      User{Name: "alice", Email: "alice@example.com"},
+				Labels:      []string{"bug"},
+				Milestone:   "v1.0",
+				IsClosed:    true,
+				Created:     *mustParseTime(t, "2026-01-03T10:00:00Z"),
+				Updated:     *mustParseTime(t, "2026-01-05T10:00:00Z"),
+				Closed:      mustParseTime(t, "2026-01-05T10:00:00Z"),
+				NumComments: 1,
+			},
+		}
+		assert.Equal(t, want, issues)
+
+		comments, err := d.Comments(ctx, 1, false)
+		require.NoError(t, err)
+		wantComments := []*Comment{
+			{
+				Poster:  User{Name: "bob", Email: "bob@noreply.example.com"},
+				Content: "Confirmed on Linux.",
+				Created: *mustParseTime(t, "2026-01-04T09:00:00Z"),
+				Updated: *mustParseTime(t, "2026-01-04T09:30:00Z"),
+			},
+		}
+		assert.Equal(t, wantComments, comments)
+	})
+
+	t.Run("pull requests", func(t *testing.T) {
+		pulls, isLast, err := d.PullRequests(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, isLast)
+		want := []*PullRequest{
+			{
+				Issue: Issue{
+					Number:    2,
+					Title:     "Fix crash on startup",
+					Content:   "Fixes #1",
+					Poster:    User{Name: "bob", Email: "bob@noreply.example.com"},
+					Milestone: "v1.0",
+					IsClosed:  true,
+					Created:   *mustParseTime(t, "2026-01-04T10:00:00Z"),
+					Updated:   *mustParseTime(t, "2026-01-05T10:00:00Z"),
+					Closed:    mustParseTime(t, "2026-01-05T10:00:00Z"),
+				},
+				Head:           PullRequestBranch{Ref: "fix-crash", SHA: "c0ffee0000000000000000000000000000000001"},
+				Base:           PullRequestBranch{Ref: "main", SHA: "c0ffee0000000000000000000000000000000000"},
+				IsMerged:       true,
+				Merged:         mustParseTime(t, "2026-01-05T10:00:00Z"),
+				MergeCommitSHA: "c0ffee0000000000000000000000000000000002",
+			},
+		}
+		assert.Equal(t, want, pulls)
+	})
+
+	t.Run("releases", func(t *testing.T) {
+		releases, err := d.Releases(ctx)
+		require.NoError(t, err)
+		want := []*Release{
+			{
+				TagName:      "v1.0",
+				Target:       "main",
+				Name:         "Version 1.0",
+				Body:         "First release.",
+				IsPrerelease: true,
+				Publisher:    User{Name: "alice", Email: "alice@example.com"},
+				Created:      *mustParseTime(t, "2026-02-01T10:00:00Z"),
+				Assets: []*ReleaseAsset{
+					{
+						Name:        "example.tar.gz",
+						Size:        13,
+						DownloadURL: server.URL + "/git/attachments/7a1b1a2c-4d5e-4f60-8a9b-0c1d2e3f4a5b",
+					},
+				},
+			},
+		}
+		assert.Equal(t, want, releases)
+		assert.Equal(t, "Hello world!\n", readAsset(t, d, releases[0].Assets[0]))
+	})
+}
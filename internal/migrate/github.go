@@ -0,0 +1,359 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type githubUser struct {
+	Login string `json:"login"`
+	Email string `json:"email"`
+}
+
+type githubLabel struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+type githubMilestone struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	DueOn       *time.Time `json:"due_on"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+type githubIssue struct {
+	Number      int64            `json:"number"`
+	Title       string           `json:"title"`
+	Body        string           `json:"body"`
+	User        *githubUser      `json:"user"`
+	Labels      []githubLabel    `json:"labels"`
+	Milestone   *githubMilestone `json:"milestone"`
+	State       string           `json:"state"`
+	Comments    int              `json:"comments"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	ClosedAt    *time.Time       `json:"closed_at"`
+	PullRequest *struct{}        `json:"pull_request"`
+}
+
+type githubComment struct {
+	User      *githubUser `json:"user"`
+	Body      string      `json:"body"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type githubBranch struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+type githubPullRequest struct {
+	githubIssue
+	Head           githubBranch `json:"head"`
+	Base           githubBranch `json:"base"`
+	MergedAt       *time.Time   `json:"merged_at"`
+	MergeCommitSHA string       `json:"merge_commit_sha"`
+}
+
+type githubReleaseAsset struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// The API URL of the asset, which works for private repositories.
+	URL string `json:"url"`
+}
+
+type githubRelease struct {
+	TagName         string               `json:"tag_name"`
+	TargetCommitish string               `json:"target_commitish"`
+	Name            string               `json:"name"`
+	Body            string               `json:"body"`
+	Draft           bool                 `json:"draft"`
+	Prerelease      bool                 `json:"prerelease"`
+	Author          *githubUser          `json:"author"`
+	CreatedAt       time.Time            `json:"created_at"`
+	Assets          []githubReleaseAsset `json:"assets"`
+}
+
+var _ Downloader = (*githubDownloader)(nil)
+
+type githubDownloader struct {
+	client *client
+	// The API path of the repository, e.g. "/repos/gogs/gogs".
+	repoPath string
+
+	usersLock sync.Mutex
+	// The cache of users by their login.
+	users map[string]User
+}
+
+// newGitHubDownloader returns a downloader for GitHub and GitHub Enterprise
+// Server. The API of GitHub Enterprise Server is served under "/api/v3".
+func newGitHubDownloader(u *url.URL, segments []string, opts Options) *githubDownloader {
+	baseURL := "https://api.github.com"
+	if u.Host != "github.com" && u.Host != "www.github.com" {
+		baseURL = u.Scheme + "://" + u.Host + "/api/v3"
+	}
+
+	header := http.Header{}
+	if opts.Username != "" || opts.Password != "" {
+		header = basicAuthHeader(opts.Username, opts.Password)
+	}
+	if opts.Token != "" {
+		header.Set("Authorization", "token "+opts.Token)
+	}
+	header.Set("Accept", "application/vnd.github+json")
+
+	n := len(segments)
+	return &githubDownloader{
+		client:   newClient(baseURL, header, opts),
+		repoPath: "/repos/" + url.PathEscape(segments[n-2]) + "/" + url.PathEscape(segments[n-1]),
+		users:    make(map[string]User),
+	}
+}
+
+// user returns the user with the email if it is public.
+func (d *githubDownloader) user(ctx context.Context, u *githubUser) (User, error) {
+	if u == nil {
+		return User{Name: "ghost"}, nil
+	}
+
+	d.usersLock.Lock()
+	defer d.usersLock.Unlock()
+
+	if user, ok := d.users[u.Login]; ok {
+		return user, nil
+	}
+
+	var profile githubUser
+	_, err := d.client.get(ctx, "/users/"+url.PathEscape(u.Login), nil, &profile)
+	if err != nil && err != errNotFound {
+		return User{}, errors.Wrapf(err, "get user %q", u.Login)
+	}
+	user := User{Name: u.Login, Email: profile.Email}
+	d.users[u.Login] = user
+	return user, nil
+}
+
+func (d *githubDownloader) Comments(ctx context.Context, number int64, _ bool) ([]*Comment, error) {
+	var comments []*Comment
+	for page := 1; ; page++ {
+		var raw []githubComment
+		_, err := d.client.getList(ctx, fmt.Sprintf("%s/issues/%d/comments", d.repoPath, number), pageQuery(page), &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range raw {
+			poster, err := d.user(ctx, c.User)
+			if err != nil {
+				return nil, err
+			}
+			comments = append(comments, &Comment{
+				Poster:  poster,
+				Content: c.Body,
+				Created: c.CreatedAt,
+				Updated: c.UpdatedAt,
+			})
+		}
+		if len(raw) < perPage {
+			return comments, nil
+		}
+	}
+}
+
+func (d *githubDownloader) DownloadAsset(ctx context.Context, asset *ReleaseAsset) (io.ReadCloser, error) {
+	return d.client.download(ctx, asset.DownloadURL)
+}
+
+func (d *githubDownloader) convertIssue(ctx context.Context, raw *githubIssue) (*Issue, error) {
+	poster, err := d.user(ctx, raw.User)
+	if err != nil {
+		return nil, err
+	}
+
+	issue := &Issue{
+		Number:      raw.Number,
+		Title:       raw.Title,
+		Content:     raw.Body,
+		Poster:      poster,
+		IsClosed:    raw.State == "closed",
+		Created:     raw.CreatedAt,
+		Updated:     raw.UpdatedAt,
+		Closed:      raw.ClosedAt,
+		NumComments: raw.Comments,
+	}
+	for _, label := range raw.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	if raw.Milestone != nil {
+		issue.Milestone = raw.Milestone.Title
+	}
+	return issue, nil
+}
+
+func (d *githubDownloader) Issues(ctx context.Context, page int) ([]*Issue, bool, error) {
+	query := pageQuery(page)
+	query.Set("state", "all")
+	query.Set("sort", "created")
+	query.Set("direction", "asc")
+
+	var raw []githubIssue
+	_, err := d.client.getList(ctx, d.repoPath+"/issues", query, &raw)
+	if err != nil {
+		return nil, false, err
+	}
+
+	issues := make([]*Issue, 0, len(raw))
+	for i := range raw {
+		// NOTE: GitHub considers every pull request an issue.
+		if raw[i].PullRequest != nil {
+			continue
+		}
+
+		issue, err := d.convertIssue(ctx, &raw[i])
+		if err != nil {
+			return nil, false, err
+		}
+		issues = append(issues, issue)
+	}
+	return issues, len(raw) < perPage, nil
+}
+
+func (d *githubDownloader) Labels(ctx context.Context) ([]*Label, error) {
+	var labels []*Label
+	for page := 1; ; page++ {
+		var raw []githubLabel
+		_, err := d.client.getList(ctx, d.repoPath+"/labels", pageQuery(page), &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range raw {
+			labels = append(labels, &Label{
+				Name:        l.Name,
+				Color:       normalizeColor(l.Color),
+				Description: l.Description,
+			})
+		}
+		if len(raw) < perPage {
+			return labels, nil
+		}
+	}
+}
+
+func (d *githubDownloader) Milestones(ctx context.Context) ([]*Milestone, error) {
+	var milestones []*Milestone
+	for page := 1; ; page++ {
+		query := pageQuery(page)
+		query.Set("state", "all")
+
+		var raw []githubMilestone
+		_, err := d.client.getList(ctx, d.repoPath+"/milestones", query, &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range raw {
+			milestones = append(milestones, &Milestone{
+				Title:       m.Title,
+				Description: m.Description,
+				Deadline:    m.DueOn,
+				IsClosed:    m.State == "closed",
+				Created:     m.CreatedAt,
+				Closed:      m.ClosedAt,
+			})
+		}
+		if len(raw) < perPage {
+			return milestones, nil
+		}
+	}
+}
+
+func (d *githubDownloader) PullRequests(ctx context.Context, page int) ([]*PullRequest, bool, error) {
+	query := pageQuery(page)
+	query.Set("state", "all")
+	query.Set("sort", "created")
+	query.Set("direction", "asc")
+
+	var raw []githubPullRequest
+	_, err := d.client.getList(ctx, d.repoPath+"/pulls", query, &raw)
+	if err != nil {
+		return nil, false, err
+	}
+
+	pulls := make([]*PullRequest, 0, len(raw))
+	for i := range raw {
+		issue, err := d.convertIssue(ctx, &raw[i].githubIssue)
+		if err != nil {
+			return nil, false, err
+		}
+		// NOTE: The list of pull requests does not contain the number of comments.
+		issue.NumComments = -1
+
+		pulls = append(pulls, &PullRequest{
+			Issue:          *issue,
+			Head:           PullRequestBranch{Ref: raw[i].Head.Ref, SHA: raw[i].Head.SHA},
+			Base:           PullRequestBranch{Ref: raw[i].Base.Ref, SHA: raw[i].Base.SHA},
+			IsMerged:       raw[i].MergedAt != nil,
+			Merged:         raw[i].MergedAt,
+			MergeCommitSHA: raw[i].MergeCommitSHA,
+		})
+	}
+	return pulls, len(raw) < perPage, nil
+}
+
+func (d *githubDownloader) Releases(ctx context.Context) ([]*Release, error) {
+	var releases []*Release
+	for page := 1; ; page++ {
+		var raw []githubRelease
+		_, err := d.client.getList(ctx, d.repoPath+"/releases", pageQuery(page), &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range raw {
+			publisher, err := d.user(ctx, r.Author)
+			if err != nil {
+				return nil, err
+			}
+
+			release := &Release{
+				TagName:      r.TagName,
+				Target:       r.TargetCommitish,
+				Name:         r.Name,
+				Body:         r.Body,
+				IsDraft:      r.Draft,
+				IsPrerelease: r.Prerelease,
+				Publisher:    publisher,
+				Created:      r.CreatedAt,
+			}
+			for _, asset := range r.Assets {
+				release.Assets = append(release.Assets, &ReleaseAsset{
+					Name:        asset.Name,
+					Size:        asset.Size,
+					DownloadURL: asset.URL,
+				})
+			}
+			releases = append(releases, release)
+		}
+		if len(raw) < perPage {
+			return releases, nil
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseTime(t *testing.T, s string) *time.Time {
+	v, err := time.Parse(time.RFC3339, s)
+	require.NoError(t, err)
+	return &v
+}
+
+func TestGitHubDownloader(t *testing.T) {
+	server := newFixtureServer(t, ServiceGitHub, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "token gh-token"
+	})
+
+	ctx := context.Background()
+	d, err := NewDownloader(ServiceGitHub, Options{
+		CloneAddr: server.URL + "/alice/example.git",
+		Token:     "gh-token",
+	})
+	require.NoError(t, err)
+
+	t.Run("labels", func(t *testing.T) {
+		labels, err := d.Labels(ctx)
+		require.NoError(t, err)
+		want := []*Label{
+			{Name: "bug", Color: "#d73a4a", Description: "Something isn't working"},
+			{Name: "enhancement", Color: "#a2eeef", Description: "New feature or request"},
+		}
+		assert.Equal(t, want, labels)
+	})
+
+	t.Run("milestones", func(t *testing.T) {
+		milestones, err := d.Milestones(ctx)
+		require.NoError(t, err)
+		want := []*Milestone{
+			{
+				Title:       "v1.0",
+				Description: "First release",
+				Deadline:    mustParseTime(t, "2026-02-01T08:00:00Z"),
+				IsClosed:    true,
+				Created:     *mustParseTime(t, "2026-01-02T10:00:00Z"),
+				Closed:      mustParseTime(t, "2026-02-01T10:00:00Z"),
+			},
+			{
+				Title:   "v2.0",
+				Created: *mustParseTime(t, "2026-02-02T10:00:00Z"),
+			},
+		}
+		assert.Equal(t, want, milestones)
+	})
+
+	t.Run("issues", func(t *testing.T) {
+		issues, isLast, err := d.Issues(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, isLast)
+		want := []*Issue{
+			{
+				Number:      1,
+				Title:       "Crash on startup",
+				Content:     "It crashes.",
+				Poster:      User{Name: "alice", Email: "alice@example.com"},
+				Labels:      []string{"bug"},
+				Milestone:   "v1.0",
+				IsClosed:    true,
+				Created:     *mustParseTime(t, "2026-01-03T10:00:00Z"),
+				Updated:     *mustParseTime(t, "2026-01-05T10:00:00Z"),
+				Closed:      mustParseTime(t, "2026-01-05T10:00:00Z"),
+				NumComments: 1,
+			},
+			{
+				Number:    3,
+				Title:     "Support dark mode",
+				Poster:    User{Name: "ghost"},
+				Milestone: "v2.0",
+				Created:   *mustParseTime(t, "2026-02-03T10:00:00Z"),
+				Updated:   *mustParseTime(t, "2026-02-03T10:00:00Z"),
+			},
+		}
+		assert.Equal(t, want, issues)
+
+		comments, err := d.Comments(ctx, 1, false)
+		require.NoError(t, err)
+		wantComments := []*Comment{
+			{
+				Poster:  User{Name: "bob"},
+				Content: "Confirmed on Linux.",
+				Created: *mustParseTime(t, "2026-01-04T09:00:00Z"),
+				Updated: *mustParseTime(t, "2026-01-04T09:30:00Z"),
+			},
+		}
+		assert.Equal(t, wantComments, comments)
+	})
+
+	t.Run("pull requests", func(t *testing.T) {
+		pulls, isLast, err := d.PullRequests(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, isLast)
+		want := []*PullRequest{
+			{
+				Issue: Issue{
+					Number:      2,
+					Title:       "Fix crash on startup",
+					Content:     "Fixes #1",
+					Poster:      User{Name: "bob"},
+					Milestone:   "v1.0",
+					IsClosed:    true,
+					Created:     *mustParseTime(t, "2026-01-04T10:00:00Z"),
+					Updated:     *mustParseTime(t, "2026-01-05T10:00:00Z"),
+					Closed:      mustParseTime(t, "2026-01-05T10:00:00Z"),
+					NumComments: -1,
+				},
+				Head:           PullRequestBranch{Ref: "fix-crash", SHA: "c0ffee0000000000000000000000000000000001"},
+				Base:           PullRequestBranch{Ref: "main", SHA: "c0ffee0000000000000000000000000000000000"},
+				IsMerged:       true,
+				Merged:         mustParseTime(t, "2026-01-05T10:00:00Z"),
+				MergeCommitSHA: "c0ffee0000000000000000000000000000000002",
+			},
+		}
+		assert.Equal(t, want, pulls)
+	})
+
+	t.Run("releases", func(t *testing.T) {
+		releases, err := d.Releases(ctx)
+		require.NoError(t, err)
+		want := []*Release{
+			{
+				TagName:   "v1.0",
+				Target:    "main",
+				Name:      "Version 1.0",
+				Body:      "First release.",
+				Publisher: User{Name: "alice", Email: "alice@example.com"},
+				Created:   *mustParseTime(t, "2026-02-01T10:00:00Z"),
+				Assets: []*ReleaseAsset{
+					{
+						Name:        "example.tar.gz",
+						Size:        13,
+						DownloadURL: server.URL + "/api/v3/repos/alice/example/releases/assets/1",
+					},
+				},
+			},
+		}
+		assert.Equal(t, want, releases)
+		assert.Equal(t, "Hello world!\n", readAsset(t, d, releases[0].Assets[0]))
+	})
+
+	t.Run("bad credentials", func(t *testing.T) {
+		d, err := NewDownloader(ServiceGitHub, Options{
+			CloneAddr: server.URL + "/alice/example.git",
+			Token:     "bad-token",
+		})
+		require.NoError(t, err)
+
+		_, err = d.Labels(ctx)
+		require.Error(t, err)
+		assert.Equal(t, `GET /repos/alice/example/labels: unexpected status 401: {"message":"Bad credentials"}`, err.Error())
+	})
+}
@@ -0,0 +1,384 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type gitlabUser struct {
+	ID          int64  `json:"id"`
+	Username    string `json:"username"`
+	PublicEmail string `json:"public_email"`
+}
+
+type gitlabLabel struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+type gitlabMilestone struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	State       string    `json:"state"`
+	DueDate     string    `json:"due_date"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type gitlabIssue struct {
+	IID            int64            `json:"iid"`
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	Author         *gitlabUser      `json:"author"`
+	Labels         []string         `json:"labels"`
+	Milestone      *gitlabMilestone `json:"milestone"`
+	State          string           `json:"state"`
+	UserNotesCount int              `json:"user_notes_count"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	ClosedAt       *time.Time       `json:"closed_at"`
+}
+
+type gitlabNote struct {
+	Body      string      `json:"body"`
+	Author    *gitlabUser `json:"author"`
+	System    bool        `json:"system"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type gitlabMergeRequest struct {
+	gitlabIssue
+	SourceBranch   string     `json:"source_branch"`
+	TargetBranch   string     `json:"target_branch"`
+	SHA            string     `json:"sha"`
+	MergeCommitSHA string     `json:"merge_commit_sha"`
+	MergedAt       *time.Time `json:"merged_at"`
+	DiffRefs       *struct {
+		BaseSHA string `json:"base_sha"`
+	} `json:"diff_refs"`
+}
+
+type gitlabRelease struct {
+	TagName     string      `json:"tag_name"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Author      *gitlabUser `json:"author"`
+	CreatedAt   time.Time   `json:"created_at"`
+	// The release is upcoming when its release date is in the future.
+	UpcomingRelease bool `json:"upcoming_release"`
+	Commit          struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+	Assets struct {
+		Links []struct {
+			Name           string `json:"name"`
+			URL            string `json:"url"`
+			DirectAssetURL string `json:"direct_asset_url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+var _ Downloader = (*gitlabDownloader)(nil)
+
+type gitlabDownloader struct {
+	client *client
+	// The API path of the project, e.g. "/projects/gogs%2Fgogs".
+	projectPath string
+
+	usersLock sync.Mutex
+	// The cache of users by their ID.
+	users map[int64]User
+}
+
+// newGitLabDownloader returns a downloader for GitLab. Projects may be nested in
+// subgroups, thus all segments of the path are used.
+//
+// NOTE: The API of GitLab does not accept passwords, so the password is used as
+// a personal access token when no token is given.
+func newGitLabDownloader(u *url.URL, segments []string, opts Options) *gitlabDownloader {
+	token := opts.Token
+	if token == "" {
+		token = opts.Password
+	}
+	header := http.Header{}
+	if token != "" {
+		header.Set("PRIVATE-TOKEN", token)
+	}
+
+	return &gitlabDownloader{
+		client:      newClient(u.Scheme+"://"+u.Host+"/api/v4", header, opts),
+		projectPath: "/projects/" + url.PathEscape(strings.Join(segments, "/")),
+		users:       make(map[int64]User),
+	}
+}
+
+// user returns the user with the email if it is public.
+func (d *gitlabDownloader) user(ctx context.Context, u *gitlabUser) (User, error) {
+	if u == nil {
+		return User{Name: "ghost"}, nil
+	}
+
+	d.usersLock.Lock()
+	defer d.usersLock.Unlock()
+
+	if user, ok := d.users[u.ID]; ok {
+		return user, nil
+	}
+
+	var profile gitlabUser
+	_, err := d.client.get(ctx, fmt.Sprintf("/users/%d", u.ID), nil, &profile)
+	if err != nil && err != errNotFound {
+		return User{}, errors.Wrapf(err, "get user %q", u.Username)
+	}
+	user := User{Name: u.Username, Email: profile.PublicEmail}
+	d.users[u.ID] = user
+	return user, nil
+}
+
+func (d *gitlabDownloader) Comments(ctx context.Context, number int64, isPull bool) ([]*Comment, error) {
+	typ := "issues"
+	if isPull {
+		typ = "merge_requests"
+	}
+
+	var comments []*Comment
+	for page := 1; ; page++ {
+		query := pageQuery(page)
+		query.Set("sort", "asc")
+		query.Set("order_by", "created_at")
+
+		var raw []gitlabNote
+		_, err := d.client.getList(ctx, fmt.Sprintf("%s/%s/%d/notes", d.projectPath, typ, number), query, &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, note := range raw {
+			// Skip notes of events, e.g. "changed the description".
+			if note.System {
+				continue
+			}
+
+			poster, err := d.user(ctx, note.Author)
+			if err != nil {
+				return nil, err
+			}
+			comments = append(comments, &Comment{
+				Poster:  poster,
+				Content: note.Body,
+				Created: note.CreatedAt,
+				Updated: note.UpdatedAt,
+			})
+		}
+		if len(raw) < perPage {
+			return comments, nil
+		}
+	}
+}
+
+func (d *gitlabDownloader) DownloadAsset(ctx context.Context, asset *ReleaseAsset) (io.ReadCloser, error) {
+	return d.client.download(ctx, asset.DownloadURL)
+}
+
+func (d *gitlabDownloader) convertIssue(ctx context.Context, raw *gitlabIssue) (*Issue, error) {
+	poster, err := d.user(ctx, raw.Author)
+	if err != nil {
+		return nil, err
+	}
+
+	issue := &Issue{
+		Number:      raw.IID,
+		Title:       raw.Title,
+		Content:     raw.Description,
+		Poster:      poster,
+		Labels:      raw.Labels,
+		IsClosed:    raw.State == "closed" || raw.State == "merged",
+		Created:     raw.CreatedAt,
+		Updated:     raw.UpdatedAt,
+		Closed:      raw.ClosedAt,
+		NumComments: raw.UserNotesCount,
+	}
+	if raw.Milestone != nil {
+		issue.Milestone = raw.Milestone.Title
+	}
+	return issue, nil
+}
+
+func (d *gitlabDownloader) Issues(ctx context.Context, page int) ([]*Issue, bool, error) {
+	query := pageQuery(page)
+	query.Set("scope", "all")
+	query.Set("sort", "asc")
+	query.Set("order_by", "created_at")
+
+	var raw []gitlabIssue
+	_, err := d.client.getList(ctx, d.projectPath+"/issues", query, &raw)
+	if err != nil {
+		return nil, false, err
+	}
+
+	issues := make([]*Issue, 0, len(raw))
+	for i := range raw {
+		issue, err := d.convertIssue(ctx, &raw[i])
+		if err != nil {
+			return nil, false, err
+		}
+		issues = append(issues, issue)
+	}
+	return issues, len(raw) < perPage, nil
+}
+
+func (d *gitlabDownloader) Labels(ctx context.Context) ([]*Label, error) {
+	var labels []*Label
+	for page := 1; ; page++ {
+		var raw []gitlabLabel
+		_, err := d.client.getList(ctx, d.projectPath+"/labels", pageQuery(page), &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range raw {
+			labels = append(labels, &Label{
+				Name:        l.Name,
+				Color:       normalizeColor(l.Color),
+				Description: l.Description,
+			})
+		}
+		if len(raw) < perPage {
+			return labels, nil
+		}
+	}
+}
+
+func (d *gitlabDownloader) Milestones(ctx context.Context) ([]*Milestone, error) {
+	var milestones []*Milestone
+	for page := 1; ; page++ {
+		var raw []gitlabMilestone
+		_, err := d.client.getList(ctx, d.projectPath+"/milestones", pageQuery(page), &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range raw {
+			milestone := &Milestone{
+				Title:       m.Title,
+				Description: m.Description,
+				IsClosed:    m.State == "closed",
+				Created:     m.CreatedAt,
+			}
+			if m.DueDate != "" {
+				deadline, err := time.Parse("2006-01-02", m.DueDate)
+				if err == nil {
+					milestone.Deadline = &deadline
+				}
+			}
+			// NOTE: GitLab does not record when a milestone is closed, the last
+			// update is the closest.
+			if milestone.IsClosed {
+				closed := m.UpdatedAt
+				milestone.Closed = &closed
+			}
+			milestones = append(milestones, milestone)
+		}
+		if len(raw) < perPage {
+			return milestones, nil
+		}
+	}
+}
+
+func (d *gitlabDownloader) PullRequests(ctx context.Context, page int) ([]*PullRequest, bool, error) {
+	query := pageQuery(page)
+	query.Set("scope", "all")
+	query.Set("state", "all")
+	query.Set("sort", "asc")
+	query.Set("order_by", "created_at")
+
+	var raw []gitlabMergeRequest
+	_, err := d.client.getList(ctx, d.projectPath+"/merge_requests", query, &raw)
+	if err != nil {
+		return nil, false, err
+	}
+
+	pulls := make([]*PullRequest, 0, len(raw))
+	for i := range raw {
+		issue, err := d.convertIssue(ctx, &raw[i].gitlabIssue)
+		if err != nil {
+			return nil, false, err
+		}
+
+		pull := &PullRequest{
+			Issue:          *issue,
+			Head:           PullRequestBranch{Ref: raw[i].SourceBranch, SHA: raw[i].SHA},
+			Base:           PullRequestBranch{Ref: raw[i].TargetBranch},
+			IsMerged:       raw[i].State == "merged",
+			Merged:         raw[i].MergedAt,
+			MergeCommitSHA: raw[i].MergeCommitSHA,
+		}
+		if raw[i].DiffRefs != nil {
+			pull.Base.SHA = raw[i].DiffRefs.BaseSHA
+		}
+		// NOTE: Merged merge requests have no time of closing.
+		if pull.IsMerged && pull.Closed == nil {
+			pull.Closed = pull.Merged
+		}
+		pulls = append(pulls, pull)
+	}
+	return pulls, len(raw) < perPage, nil
+}
+
+func (d *gitlabDownloader) Releases(ctx context.Context) ([]*Release, error) {
+	var releases []*Release
+	for page := 1; ; page++ {
+		var raw []gitlabRelease
+		_, err := d.client.getList(ctx, d.projectPath+"/releases", pageQuery(page), &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range raw {
+			publisher, err := d.user(ctx, r.Author)
+			if err != nil {
+				return nil, err
+			}
+
+			release := &Release{
+				TagName:      r.TagName,
+				Target:       r.Commit.ID,
+				Name:         r.Name,
+				Body:         r.Description,
+				IsPrerelease: r.UpcomingRelease,
+				Publisher:    publisher,
+				Created:      r.CreatedAt,
+			}
+			// NOTE: Only links are migrated, sources are archives generated from
+			// the tag.
+			for _, link := range r.Assets.Links {
+				downloadURL := link.DirectAssetURL
+				if downloadURL == "" {
+					downloadURL = link.URL
+				}
+				release.Assets = append(release.Assets, &ReleaseAsset{
+					Name:        link.Name,
+					DownloadURL: downloadURL,
+				})
+			}
+			releases = append(releases, release)
+		}
+		if len(raw) < perPage {
+			return releases, nil
+		}
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLabDownloader(t *testing.T) {
+	server := newFixtureServer(t, ServiceGitLab, func(r *http.Request) bool {
+		// Assets are downloaded from the web server, which does not require the
+		// token for public projects.
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			return true
+		}
+		return r.Header.Get("PRIVATE-TOKEN") == "gl-token"
+	})
+
+	ctx := context.Background()
+	d, err := NewDownloader(ServiceGitLab, Options{
+		CloneAddr: server.URL + "/alice/group/example.git",
+		Username:  "alice",
+		Password:  "gl-token",
+	})
+	require.NoError(t, err)
+
+	t.Run("labels", func(t *testing.T) {
+		labels, err := d.Labels(ctx)
+		require.NoError(t, err)
+		want := []*Label{
+			{Name: "bug", Color: "#d9534f", Description: "Something isn't working"},
+			{Name: "feature", Color: "#5cb85c"},
+		}
+		assert.Equal(t, want, labels)
+	})
+
+	t.Run("milestones", func(t *testing.T) {
+		milestones, err := d.Milestones(ctx)
+		require.NoError(t, err)
+		want := []*Milestone{
+			{
+				Title:       "v1.0",
+				Description: "First release",
+				Deadline:    mustParseTime(t, "2026-02-01T00:00:00Z"),
+				IsClosed:    true,
+				Created:     *mustParseTime(t, "2026-01-02T10:00:00Z"),
+				Closed:      mustParseTime(t, "2026-02-01T10:00:00Z"),
+			},
+		}
+		assert.Equal(t, want, milestones)
+	})
+
+	t.Run("issues", func(t *testing.T) {
+		issues, isLast, err := d.Issues(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, isLast)
+		want := []*Issue{
+			{
+				Number:      1,
+				Title:       "Crash on startup",
+				Content:     "It crashes.",
+				Poster:      User{Name: "alice", Email: "alice@example.com"},
+				Labels:      []string{"bug"},
+				Milestone:   "v1.0",
+				IsClosed:    true,
+				Created:     *mustParseTime(t, "2026-01-03T10:00:00Z"),
+				Updated:     *mustParseTime(t, "2026-01-05T10:00:00Z"),
+				Closed:      mustParseTime(t, "2026-01-05T10:00:00Z"),
+				NumComments: 1,
+			},
+		}
+		assert.Equal(t, want, issues)
+
+		// System notes are skipped.
+		comments, err := d.Comments(ctx, 1, false)
+		require.NoError(t, err)
+		wantComments := []*Comment{
+			{
+				Poster:  User{Name: "bob"},
+				Content: "Confirmed on Linux.",
+				Created: *mustParseTime(t, "2026-01-04T09:00:00Z"),
+				Updated: *mustParseTime(t, "2026-01-04T09:30:00Z"),
+			},
+		}
+		assert.Equal(t, wantComments, comments)
+
+		// Merge requests have their own notes.
+		comments, err = d.Comments(ctx, 1, true)
+		require.NoError(t, err)
+		assert.Empty(t, comments)
+	})
+
+	t.Run("pull requests", func(t *testing.T) {
+		pulls, isLast, err := d.PullRequests(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, isLast)
+		want := []*PullRequest{
+			{
+				Issue: Issue{
+					Number:    1,
+					Title:     "Fix crash on startup",
+					Content:   "Closes #1",
+					Poster:    User{Name: "bob"},
+					Labels:    []string{},
+					Milestone: "v1.0",
+					IsClosed:  true,
+					Created:   *mustParseTime(t, "2026-01-04T10:00:00Z"),
+					Updated:   *mustParseTime(t, "2026-01-05T10:00:00Z"),
+					Closed:    mustParseTime(t, "2026-01-05T10:00:00Z"),
+				},
+				Head:           PullRequestBranch{Ref: "fix-crash", SHA: "c0ffee0000000000000000000000000000000001"},
+				Base:           PullRequestBranch{Ref: "main", SHA: "c0ffee0000000000000000000000000000000000"},
+				IsMerged:       true,
+				Merged:         mustParseTime(t, "2026-01-05T10:00:00Z"),
+				MergeCommitSHA: "c0ffee0000000000000000000000000000000002",
+			},
+		}
+		assert.Equal(t, want, pulls)
+	})
+
+	t.Run("releases", func(t *testing.T) {
+		releases, err := d.Releases(ctx)
+		require.NoError(t, err)
+		want := []*Release{
+			{
+				TagName:   "v1.0",
+				Target:    "c0ffee0000000000000000000000000000000002",
+				Name:      "Version 1.0",
+				Body:      "First release.",
+				Publisher: User{Name: "alice", Email: "alice@example.com"},
+				Created:   *mustParseTime(t, "2026-02-01T10:00:00Z"),
+				Assets: []*ReleaseAsset{
+					{
+						Name:        "example.tar.gz",
+						DownloadURL: server.URL + "/alice/group/example/-/releases/v1.0/downloads/example.tar.gz",
+					},
+				},
+			},
+		}
+		assert.Equal(t, want, releases)
+		assert.Equal(t, "Hello world!\n", readAsset(t, d, releases[0].Assets[0]))
+	})
+
+	t.Run("asset URL not allowed", func(t *testing.T) {
+		d, err := NewDownloader(ServiceGitLab, Options{
+			CloneAddr: server.URL + "/alice/group/example.git",
+			Token:     "gl-token",
+			AllowURL: func(u *url.URL) bool {
+				return strings.HasPrefix(u.Path, "/api/")
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = d.DownloadAsset(ctx, &ReleaseAsset{DownloadURL: server.URL + "/alice/group/example/-/releases/v1.0/downloads/example.tar.gz"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not allowed")
+	})
+
+	t.Run("asset redirect not allowed", func(t *testing.T) {
+		redirect := httptest.NewServer(http.RedirectHandler(server.URL+"/alice/group/example/-/releases/v1.0/downloads/example.tar.gz", http.StatusFound))
+		t.Cleanup(redirect.Close)
+
+		d, err := NewDownloader(ServiceGitLab, Options{
+			CloneAddr: server.URL + "/alice/group/example.git",
+			Token:     "gl-token",
+			AllowURL: func(u *url.URL) bool {
+				return u.Host == redirect.Listener.Addr().String() || strings.HasPrefix(u.Path, "/api/")
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = d.DownloadAsset(ctx, &ReleaseAsset{DownloadURL: redirect.URL + "/example.tar.gz"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not allowed")
+	})
+
+	t.Run("API redirect not allowed", func(t *testing.T) {
+		redirect := httptest.NewServer(http.RedirectHandler(server.URL+"/api/v4/projects/alice%2Fgroup%2Fexample/labels", http.StatusFound))
+		t.Cleanup(redirect.Close)
+
+		d, err := NewDownloader(ServiceGitLab, Options{
+			CloneAddr: redirect.URL + "/alice/group/example.git",
+			Token:     "gl-token",
+			AllowURL: func(u *url.URL) bool {
+				return u.Host == redirect.Listener.Addr().String()
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = d.Labels(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not allowed")
+	})
+
+	t.Run("token not sent to redirected host", func(t *testing.T) {
+		var gotToken string
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotToken = r.Header.Get("PRIVATE-TOKEN")
+			_, _ = w.Write([]byte("[]"))
+		}))
+		t.Cleanup(target.Close)
+		redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, target.URL+r.URL.RequestURI(), http.StatusFound)
+		}))
+		t.Cleanup(redirect.Close)
+
+		d, err := NewDownloader(ServiceGitLab, Options{
+			CloneAddr: redirect.URL + "/alice/group/example.git",
+			Token:     "gl-token",
+		})
+		require.NoError(t, err)
+
+		_, err = d.Labels(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, gotToken)
+	})
+}
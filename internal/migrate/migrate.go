@@ -0,0 +1,221 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package migrate provides downloaders to fetch issues, pull requests, releases
+// and other metadata of repositories from other code hosting services.
+package migrate
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Service is a code hosting service that repositories are migrated from.
+type Service string
+
+const (
+	// ServiceGit is any Git server, only Git data are migrated.
+	ServiceGit    Service = "git"
+	ServiceGitHub Service = "github"
+	ServiceGitLab Service = "gitlab"
+	ServiceGitea  Service = "gitea"
+)
+
+// DisplayName returns the name of the service for display.
+func (s Service) DisplayName() string {
+	switch s {
+	case ServiceGitHub:
+		return "GitHub"
+	case ServiceGitLab:
+		return "GitLab"
+	case ServiceGitea:
+		return "Gitea"
+	}
+	return "Git"
+}
+
+// Services returns all services that support migrating metadata.
+func Services() []Service {
+	return []Service{ServiceGitHub, ServiceGitLab, ServiceGitea}
+}
+
+// User is a user of the service.
+type User struct {
+	Name string
+	// The email is empty when it is not public.
+	Email string
+}
+
+type Label struct {
+	Name string
+	// The color in the format of "#rrggbb".
+	Color       string
+	Description string
+}
+
+type Milestone struct {
+	Title       string
+	Description string
+	Deadline    *time.Time
+	IsClosed    bool
+	Created     time.Time
+	Closed      *time.Time
+}
+
+type Issue struct {
+	Number    int64
+	Title     string
+	Content   string
+	Poster    User
+	Labels    []string
+	Milestone string
+	IsClosed  bool
+	Created   time.Time
+	Updated   time.Time
+	Closed    *time.Time
+	// The number of comments, or -1 when it is unknown. It is used to skip
+	// requesting comments of issues that have none.
+	NumComments int
+}
+
+type Comment struct {
+	Poster  User
+	Content string
+	Created time.Time
+	Updated time.Time
+}
+
+// PullRequestBranch is the head or base branch of a pull request.
+type PullRequestBranch struct {
+	Ref string
+	SHA string
+}
+
+type PullRequest struct {
+	Issue
+	Head           PullRequestBranch
+	Base           PullRequestBranch
+	IsMerged       bool
+	Merged         *time.Time
+	MergeCommitSHA string
+}
+
+type ReleaseAsset struct {
+	Name string
+	// The size is zero when it is unknown.
+	Size        int64
+	DownloadURL string
+}
+
+type Release struct {
+	TagName      string
+	Target       string
+	Name         string
+	Body         string
+	IsDraft      bool
+	IsPrerelease bool
+	Publisher    User
+	Created      time.Time
+	Assets       []*ReleaseAsset
+}
+
+// Downloader fetches metadata of a repository from a service.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type Downloader interface {
+	// Comments returns all comments of the issue or pull request with given
+	// number in the order of creation.
+	Comments(ctx context.Context, number int64, isPull bool) ([]*Comment, error)
+	// DownloadAsset returns the content of the release asset. It is caller's
+	// responsibility to close the returned reader.
+	DownloadAsset(ctx context.Context, asset *ReleaseAsset) (io.ReadCloser, error)
+	// Issues returns issues on the page (starting from 1), and whether it is
+	// the last page. Pull requests are not included.
+	Issues(ctx context.Context, page int) ([]*Issue, bool, error)
+	// Labels returns all labels.
+	Labels(ctx context.Context) ([]*Label, error)
+	// Milestones returns all milestones.
+	Milestones(ctx context.Context) ([]*Milestone, error)
+	// PullRequests returns pull requests on the page (starting from 1), and
+	// whether it is the last page.
+	PullRequests(ctx context.Context, page int) ([]*PullRequest, bool, error)
+	// Releases returns all releases.
+	Releases(ctx context.Context) ([]*Release, error)
+}
+
+// Options contains options to create a downloader.
+type Options struct {
+	// The clone address of the repository without credentials, e.g.
+	// "https://github.com/gogs/gogs.git".
+	CloneAddr string
+	// The credentials to access the API, the token takes precedence over the
+	// username and password.
+	Username string
+	Password string
+	Token    string
+	// AllowURL reports whether release assets are allowed to be downloaded from
+	// given URL. All URLs are allowed when it is nil.
+	AllowURL func(u *url.URL) bool
+	// The HTTP client to send requests, http.DefaultClient is used when it is
+	// nil.
+	HTTPClient *http.Client
+}
+
+// ErrUnsupportedService is returned when the service does not support
+// migrating metadata.
+type ErrUnsupportedService struct {
+	Service Service
+}
+
+func IsErrUnsupportedService(err error) bool {
+	_, ok := err.(ErrUnsupportedService)
+	return ok
+}
+
+func (err ErrUnsupportedService) Error() string {
+	return "unsupported service: " + string(err.Service)
+}
+
+// NewDownloader returns a downloader of the service for the repository.
+func NewDownloader(service Service, opts Options) (Downloader, error) {
+	u, err := url.Parse(opts.CloneAddr)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse clone address")
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("clone address must be HTTP or HTTPS to migrate from %s", service)
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git"), "/"), "/")
+	if len(segments) < 2 {
+		return nil, errors.New("clone address does not contain owner and repository name")
+	}
+
+	switch service {
+	case ServiceGitHub:
+		return newGitHubDownloader(u, segments, opts), nil
+	case ServiceGitLab:
+		return newGitLabDownloader(u, segments, opts), nil
+	case ServiceGitea:
+		return newGiteaDownloader(u, segments, opts), nil
+	}
+	return nil, ErrUnsupportedService{Service: service}
+}
+
+// normalizeColor returns the color in the format of "#rrggbb".
+func normalizeColor(color string) string {
+	color = strings.ToLower(strings.TrimPrefix(color, "#"))
+	if len(color) == 3 {
+		color = string([]byte{color[0], color[0], color[1], color[1], color[2], color[2]})
+	}
+	if len(color) != 6 || strings.Trim(color, "0123456789abcdef") != "" {
+		return "#cccccc"
+	}
+	return "#" + color
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFixtureServer returns a server that responds with recorded API fixtures in
+// "testdata/<service>". The path of a request is mapped to the fixture file
+// with the same path, and the ".json" extension for API requests. Pages other
+// than the first are always empty. Requests are rejected when authorized
+// returns false.
+func newFixtureServer(t *testing.T, service Service, authorized func(r *http.Request) bool) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Bad credentials"}`))
+			return
+		}
+
+		if page := r.URL.Query().Get("page"); page != "" && page != "1" {
+			_, _ = w.Write([]byte("[]"))
+			return
+		}
+
+		name := filepath.Join("testdata", string(service), filepath.FromSlash(r.URL.Path))
+		p, err := ioutil.ReadFile(name + ".json")
+		if os.IsNotExist(err) {
+			p, err = ioutil.ReadFile(name)
+		}
+		if os.IsNotExist(err) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		require.NoError(t, err)
+
+		_, _ = w.Write(bytes.ReplaceAll(p, []byte("{{SERVER}}"), []byte(server.URL)))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// readAsset returns the content of the release asset.
+func readAsset(t *testing.T, d Downloader, asset *ReleaseAsset) string {
+	rc, err := d.DownloadAsset(context.Background(), asset)
+	require.NoError(t, err)
+	defer func() { _ = rc.Close() }()
+
+	p, err := ioutil.ReadAll(rc)
+	require.NoError(t, err)
+	return string(p)
+}
+
+func TestNewDownloader(t *testing.T) {
+	tests := []struct {
+		name      string
+		service   Service
+		cloneAddr string
+		wantErr   string
+	}{
+		{
+			name:      "not HTTP",
+			service:   ServiceGitHub,
+			cloneAddr: "git@github.com:alice/example.git",
+			wantErr:   "parse clone address",
+		},
+		{
+			name:      "local path",
+			service:   ServiceGitea,
+			cloneAddr: "/home/alice/example.git",
+			wantErr:   "clone address must be HTTP or HTTPS to migrate from gitea",
+		},
+		{
+			name:      "no repository name",
+			service:   ServiceGitLab,
+			cloneAddr: "https://gitlab.com/alice",
+			wantErr:   "clone address does not contain owner and repository name",
+		},
+		{
+			name:      "unsupported service",
+			service:   ServiceGit,
+			cloneAddr: "https://example.com/alice/example.git",
+			wantErr:   "unsupported service: git",
+		},
+		{
+			name:      "GitHub",
+			service:   ServiceGitHub,
+			cloneAddr: "https://github.com/alice/example.git",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewDownloader(test.service, Options{CloneAddr: test.cloneAddr})
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, strings.HasPrefix(err.Error(), test.wantErr), err.Error())
+		})
+	}
+}
+
+func Test_normalizeColor(t *testing.T) {
+	tests := []struct {
+		color string
+		want  string
+	}{
+		{color: "d73a4a", want: "#d73a4a"},
+		{color: "#D9534F", want: "#d9534f"},
+		{color: "#fa0", want: "#ffaa00"},
+		{color: "red", want: "#cccccc"},
+	}
+	for _, test := range tests {
+		t.Run(test.color, func(t *testing.T) {
+			assert.Equal(t, test.want, normalizeColor(test.color))
+		})
+	}
+}
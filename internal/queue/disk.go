@@ -0,0 +1,297 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.etcd.io/bbolt"
+)
+
+// Disk is an embedded on-disk storage of queues. The file is locked while it is
+// open, thus it can only be used by one process at a time.
+type Disk struct {
+	db *bbolt.DB
+}
+
+// OpenDisk opens the storage at given path, the file is created if it does not
+// exist.
+func OpenDisk(path string) (*Disk, error) {
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return nil, errors.Wrap(err, "create directory")
+	}
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, errors.Wrap(err, "open")
+	}
+	return &Disk{db: db}, nil
+}
+
+// Close closes the storage.
+func (d *Disk) Close() error {
+	return d.db.Close()
+}
+
+// Queue returns the queue with given name in the storage.
+func (d *Disk) Queue(name string, opts Options) Queue {
+	return &diskQueue{
+		db:   d.db,
+		name: name,
+		opts: opts,
+		now:  time.Now,
+	}
+}
+
+var _ Queue = (*diskQueue)(nil)
+
+type diskQueue struct {
+	db   *bbolt.DB
+	name string
+	opts Options
+	now  func() time.Time
+}
+
+func (q *diskQueue) Name() string {
+	return q.name
+}
+
+// diskBuckets contains buckets of a queue. Tasks are stored by their keys, and
+// indexed by the time they become visible so that popping a task does not need
+// to decode all tasks.
+type diskBuckets struct {
+	tasks *bbolt.Bucket
+	// Keys are the visible time and the creation time in nanoseconds followed by
+	// the task key, values are empty.
+	visible *bbolt.Bucket
+}
+
+// visibleBucketName returns the name of the bucket that indexes tasks of the
+// queue by visible time.
+func (q *diskQueue) visibleBucketName() []byte {
+	return []byte(q.name + ":visible_at")
+}
+
+// update runs the function with buckets of the queue in a read-write
+// transaction.
+func (q *diskQueue) update(fn func(b *diskBuckets) error) error {
+	return q.db.Update(func(tx *bbolt.Tx) error {
+		tasks, err := tx.CreateBucketIfNotExists([]byte(q.name))
+		if err != nil {
+			return errors.Wrap(err, "create bucket")
+		}
+
+		b := &diskBuckets{
+			tasks:   tasks,
+			visible: tx.Bucket(q.visibleBucketName()),
+		}
+		if b.visible == nil {
+			// The index did not exist in files created by earlier versions.
+			b.visible, err = tx.CreateBucket(q.visibleBucketName())
+			if err != nil {
+				return errors.Wrap(err, "create index bucket")
+			}
+			err = tasks.ForEach(func(_, v []byte) error {
+				t := new(Task)
+				if err := json.Unmarshal(v, t); err != nil {
+					return errors.Wrap(err, "decode task")
+				}
+				return b.visible.Put(visibleKey(t), nil)
+			})
+			if err != nil {
+				return errors.Wrap(err, "build index")
+			}
+		}
+		return fn(b)
+	})
+}
+
+// view runs the function with the bucket of the queue in a read-only
+// transaction, the function is not called when the bucket does not exist.
+func (q *diskQueue) view(fn func(b *bbolt.Bucket) error) error {
+	return q.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(q.name))
+		if b == nil {
+			return nil
+		}
+		return fn(b)
+	})
+}
+
+// visibleKey returns the key of the task in the visible time index, which sorts
+// tasks by the visible time and then the creation time.
+func visibleKey(t *Task) []byte {
+	k := make([]byte, 16, 16+len(t.Key))
+	binary.BigEndian.PutUint64(k[:8], uint64(t.VisibleAt.UnixNano()))
+	binary.BigEndian.PutUint64(k[8:], uint64(t.CreatedAt.UnixNano()))
+	return append(k, t.Key...)
+}
+
+func getTask(b *bbolt.Bucket, key string) (*Task, error) {
+	v := b.Get([]byte(key))
+	if v == nil {
+		return nil, nil
+	}
+	t := new(Task)
+	if err := json.Unmarshal(v, t); err != nil {
+		return nil, errors.Wrapf(err, "decode task %q", key)
+	}
+	return t, nil
+}
+
+// putTask saves the task and updates the index, the old is the task before
+// changes or nil if the task is new.
+func putTask(b *diskBuckets, old, t *Task) error {
+	v, err := json.Marshal(t)
+	if err != nil {
+		return errors.Wrapf(err, "encode task %q", t.Key)
+	}
+	if old != nil {
+		if err = b.visible.Delete(visibleKey(old)); err != nil {
+			return errors.Wrapf(err, "delete index of task %q", t.Key)
+		}
+	}
+	if err = b.visible.Put(visibleKey(t), nil); err != nil {
+		return errors.Wrapf(err, "put index of task %q", t.Key)
+	}
+	return b.tasks.Put([]byte(t.Key), v)
+}
+
+// deleteTask deletes the task and its index.
+func deleteTask(b *diskBuckets, t *Task) error {
+	if err := b.visible.Delete(visibleKey(t)); err != nil {
+		return errors.Wrapf(err, "delete index of task %q", t.Key)
+	}
+	return b.tasks.Delete([]byte(t.Key))
+}
+
+func (q *diskQueue) Push(key string) error {
+	err := q.update(func(b *diskBuckets) error {
+		now := q.now()
+		t, err := getTask(b.tasks, key)
+		if err != nil {
+			return err
+		} else if t == nil {
+			return putTask(b, nil, NewTask(key, now))
+		}
+
+		old := *t
+		if t.MarkPushed(now) {
+			return putTask(b, &old, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	Wake(q.name)
+	return nil
+}
+
+func (q *diskQueue) Pop() (*Task, error) {
+	var next *Task
+	err := q.update(func(b *diskBuckets) error {
+		now := q.now()
+		k, _ := b.visible.Cursor().First()
+		if k == nil || len(k) < 16 || int64(binary.BigEndian.Uint64(k[:8])) > now.UnixNano() {
+			return ErrEmpty
+		}
+
+		key := string(k[16:])
+		var err error
+		next, err = getTask(b.tasks, key)
+		if err != nil {
+			return err
+		} else if next == nil {
+			return errors.Errorf("task %q of index does not exist", key)
+		}
+
+		old := *next
+		if err = next.MarkPopped(now, q.opts); err != nil {
+			return err
+		}
+		return putTask(b, &old, next)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// release applies the function to the task if it has not been popped again,
+// and removes the task when the function returns true.
+func (q *diskQueue) release(t *Task, fn func(current *Task, now time.Time) bool) (removed bool, _ error) {
+	err := q.update(func(b *diskBuckets) error {
+		current, err := getTask(b.tasks, t.Key)
+		if err != nil {
+			return err
+		} else if current == nil || current.Receipt != t.Receipt {
+			return ErrStaleReceipt
+		}
+
+		old := *current
+		if fn(current, q.now()) {
+			removed = true
+			return deleteTask(b, &old)
+		}
+		return putTask(b, &old, current)
+	})
+	return removed, err
+}
+
+func (q *diskQueue) Ack(t *Task) error {
+	_, err := q.release(t, func(current *Task, now time.Time) bool {
+		return current.MarkAcked(now)
+	})
+	return err
+}
+
+func (q *diskQueue) Nack(t *Task, reason string) (bool, error) {
+	return q.release(t, func(current *Task, now time.Time) bool {
+		return current.MarkNacked(now, q.opts, reason)
+	})
+}
+
+func (q *diskQueue) Has(key string) (bool, error) {
+	var has bool
+	err := q.view(func(b *bbolt.Bucket) error {
+		t, err := getTask(b, key)
+		if err != nil {
+			return err
+		}
+		has = t != nil && (t.Requeued || !t.IsProcessing(q.now()))
+		return nil
+	})
+	return has, err
+}
+
+func (q *diskQueue) List() ([]*Task, error) {
+	var tasks []*Task
+	err := q.view(func(b *bbolt.Bucket) error {
+		return b.ForEach(func(_, v []byte) error {
+			t := new(Task)
+			if err := json.Unmarshal(v, t); err != nil {
+				return errors.Wrap(err, "decode task")
+			}
+			tasks = append(tasks, t)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+	return tasks, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+func openTestDisk(t *testing.T) *Disk {
+	t.Helper()
+
+	d, err := OpenDisk(filepath.Join(t.TempDir(), "queues.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = d.Close()
+	})
+	return d
+}
+
+func TestDiskQueue(t *testing.T) {
+	d := openTestDisk(t)
+	opts := Options{
+		VisibilityTimeout: time.Minute,
+		MaxAttempts:       2,
+		RetryInterval:     time.Minute,
+	}
+	q := d.Queue("test", opts).(*diskQueue)
+	now := time.Now()
+	q.now = func() time.Time { return now }
+
+	_, err := q.Pop()
+	assert.Equal(t, ErrEmpty, err)
+
+	// Tasks are deduplicated by keys.
+	require.NoError(t, q.Push("1"))
+	now = now.Add(time.Second)
+	require.NoError(t, q.Push("2"))
+	require.NoError(t, q.Push("1"))
+	tasks, err := q.List()
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "1", tasks[0].Key)
+	assert.Equal(t, "2", tasks[1].Key)
+
+	// Tasks are popped in order, and are invisible while being processed.
+	task1, err := q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "1", task1.Key)
+	has, err := q.Has("1")
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	task2, err := q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "2", task2.Key)
+	_, err = q.Pop()
+	assert.Equal(t, ErrEmpty, err)
+
+	// Acknowledged task is removed.
+	require.NoError(t, q.Ack(task1))
+	has, err = q.Has("1")
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	// Failed task is retried after the backoff.
+	dropped, err := q.Nack(task2, "boom")
+	require.NoError(t, err)
+	assert.False(t, dropped)
+	_, err = q.Pop()
+	assert.Equal(t, ErrEmpty, err)
+	now = now.Add(time.Minute)
+	task2, err = q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, 2, task2.Attempts)
+	assert.Equal(t, "boom", task2.LastError)
+
+	// Task becomes visible again after the visibility timeout, and the stale
+	// receipt cannot be used anymore.
+	now = now.Add(2 * time.Minute)
+	again, err := q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "2", again.Key)
+	assert.Equal(t, ErrStaleReceipt, q.Ack(task2))
+
+	// Task is removed after running out of attempts.
+	dropped, err = q.Nack(again, "boom")
+	require.NoError(t, err)
+	assert.True(t, dropped)
+	tasks, err = q.List()
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	// Pushing the task being processed processes it again.
+	require.NoError(t, q.Push("3"))
+	task3, err := q.Pop()
+	require.NoError(t, err)
+	require.NoError(t, q.Push("3"))
+	has, err = q.Has("3")
+	require.NoError(t, err)
+	assert.True(t, has)
+	require.NoError(t, q.Ack(task3))
+	task3, err = q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "3", task3.Key)
+	assert.Equal(t, 1, task3.Attempts)
+}
+
+func TestDiskQueue_buildIndex(t *testing.T) {
+	d := openTestDisk(t)
+	q := d.Queue("test", Options{VisibilityTimeout: time.Minute, MaxAttempts: 1}).(*diskQueue)
+	now := time.Now()
+	q.now = func() time.Time { return now }
+
+	require.NoError(t, q.Push("1"))
+	now = now.Add(time.Second)
+	require.NoError(t, q.Push("2"))
+
+	// Drop the index like files created by earlier versions.
+	err := d.db.Update(func(tx *bbolt.Tx) error {
+		return tx.DeleteBucket(q.visibleBucketName())
+	})
+	require.NoError(t, err)
+
+	task, err := q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "1", task.Key)
+	task, err = q.Pop()
+	require.NoError(t, err)
+	assert.Equal(t, "2", task.Key)
+	_, err = q.Pop()
+	assert.Equal(t, ErrEmpty, err)
+}
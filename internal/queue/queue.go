@@ -0,0 +1,264 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package queue provides persistent task queues that survive restarts of the
+// process. Tasks are deduplicated by their keys, and a task that is popped but
+// not acknowledged within the visibility timeout becomes visible again, so a
+// crash of the consumer does not lose the task.
+package queue
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/strutil"
+)
+
+// ErrEmpty is returned by Pop when there is no task visible in the queue.
+var ErrEmpty = errors.New("queue is empty")
+
+// ErrStaleReceipt is returned by Ack and Nack when the task has been popped
+// again by another consumer after its visibility timeout.
+var ErrStaleReceipt = errors.New("task receipt is stale")
+
+// Queue is a persistent queue of tasks identified by keys.
+type Queue interface {
+	// Name returns the name of the queue.
+	Name() string
+	// Push adds a task with given key to the queue. It does not add a duplicate
+	// when the task with same key is waiting in the queue, and the task is
+	// processed again after the current attempt if it is being processed.
+	Push(key string) error
+	// Pop returns the next visible task and hides it from other consumers for the
+	// visibility timeout. It returns ErrEmpty when no task is visible.
+	Pop() (*Task, error)
+	// Ack removes the task that has been processed successfully.
+	Ack(t *Task) error
+	// Nack releases the task that failed to be processed with given reason, it is
+	// retried after a backoff until running out of attempts. It returns true if
+	// the task has been removed because of running out of attempts.
+	Nack(t *Task, reason string) (dropped bool, err error)
+	// Has returns true if the task with given key is waiting to be processed,
+	// which does not include the task being processed unless it has been pushed
+	// again.
+	Has(key string) (bool, error)
+	// List returns all tasks in the queue in the order of creation.
+	List() ([]*Task, error)
+}
+
+// Options contains options of a queue.
+type Options struct {
+	// The duration that a popped task is invisible to other consumers.
+	VisibilityTimeout time.Duration
+	// The maximum number of attempts to process a task.
+	MaxAttempts int
+	// The interval before the first retry, it is doubled after each failed
+	// attempt.
+	RetryInterval time.Duration
+}
+
+// RetryDelay returns the duration to wait before the next attempt after given
+// number of attempts.
+func (opts Options) RetryDelay(attempts int) time.Duration {
+	delay := opts.RetryInterval
+	for i := 1; i < attempts && delay < 24*time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > 24*time.Hour {
+		delay = 24 * time.Hour
+	}
+	return delay
+}
+
+// Task is a task in the queue.
+type Task struct {
+	// The key that identifies the task in the queue.
+	Key string `json:"key"`
+	// The number of times the task has been popped.
+	Attempts int `json:"attempts"`
+	// The reason of the last failed attempt.
+	LastError string `json:"last_error,omitempty"`
+	// Whether the task has been pushed again while being processed.
+	Requeued bool `json:"requeued,omitempty"`
+	// The receipt of the current attempt, it is empty when the task is not being
+	// processed.
+	Receipt   string    `json:"receipt,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// The time when the task becomes visible to consumers.
+	VisibleAt time.Time `json:"visible_at"`
+}
+
+// IsProcessing returns true if the task is being processed by a consumer.
+func (t *Task) IsProcessing(now time.Time) bool {
+	return t.Receipt != "" && t.VisibleAt.After(now)
+}
+
+// IsRetrying returns true if the task is waiting to be retried.
+func (t *Task) IsRetrying(now time.Time) bool {
+	return t.Receipt == "" && t.VisibleAt.After(now)
+}
+
+// The following methods implement state transitions of tasks, which are shared
+// by implementations of the queue.
+
+// NewTask returns a new task with given key that is visible immediately.
+func NewTask(key string, now time.Time) *Task {
+	return &Task{
+		Key:       key,
+		CreatedAt: now,
+		VisibleAt: now,
+	}
+}
+
+// MarkPushed updates the task for being pushed again, and returns false if
+// nothing is changed.
+func (t *Task) MarkPushed(now time.Time) bool {
+	switch {
+	case t.IsProcessing(now):
+		if t.Requeued {
+			return false
+		}
+		t.Requeued = true
+	case t.IsRetrying(now):
+		t.VisibleAt = now
+	default:
+		return false
+	}
+	return true
+}
+
+// MarkPopped updates the task for being popped with a new receipt.
+func (t *Task) MarkPopped(now time.Time, opts Options) error {
+	receipt, err := strutil.RandomChars(16)
+	if err != nil {
+		return errors.Wrap(err, "generate receipt")
+	}
+	t.Attempts++
+	t.Requeued = false
+	t.Receipt = receipt
+	t.VisibleAt = now.Add(opts.VisibilityTimeout)
+	return nil
+}
+
+// MarkAcked updates the task for being processed successfully, and returns
+// true if the task should be removed from the queue.
+func (t *Task) MarkAcked(now time.Time) bool {
+	if !t.Requeued {
+		return true
+	}
+	t.reset(now)
+	return false
+}
+
+// MarkNacked updates the task for failed to be processed, and returns true if
+// the task should be removed from the queue because of running out of attempts.
+func (t *Task) MarkNacked(now time.Time, opts Options, reason string) bool {
+	t.LastError = reason
+	if t.Requeued {
+		t.reset(now)
+		return false
+	} else if t.Attempts >= opts.MaxAttempts {
+		return true
+	}
+	t.Receipt = ""
+	t.VisibleAt = now.Add(opts.RetryDelay(t.Attempts))
+	return false
+}
+
+// reset makes the task a fresh one that is visible immediately.
+func (t *Task) reset(now time.Time) {
+	t.Attempts = 0
+	t.Requeued = false
+	t.Receipt = ""
+	t.VisibleAt = now
+}
+
+var (
+	wakersLock sync.Mutex
+	wakers     = make(map[string]chan struct{})
+)
+
+// waker returns the channel to wake up consumers of the queue in this process.
+func waker(name string) chan struct{} {
+	wakersLock.Lock()
+	defer wakersLock.Unlock()
+
+	ch, ok := wakers[name]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		wakers[name] = ch
+	}
+	return ch
+}
+
+// Wake wakes up consumers of the queue in this process, implementations should
+// call it after a task is pushed.
+func Wake(name string) {
+	select {
+	case waker(name) <- struct{}{}:
+	default:
+	}
+}
+
+// PollInterval is the interval that consumers check the queue when it is empty
+// for tasks pushed by other processes.
+var PollInterval = 5 * time.Second
+
+// Consume pops tasks from the queue and processes them with the handler one at
+// a time. Tasks are acknowledged when the handler returns nil, and are retried
+// otherwise. The optional giveUp function is called with the last error when a
+// task is removed because of running out of attempts. It never returns.
+func Consume(q Queue, handle func(key string) error, giveUp func(key, reason string)) {
+	wake := waker(q.Name())
+	for {
+		t, err := q.Pop()
+		if err != nil {
+			if err != ErrEmpty {
+				log.Error("Failed to pop task from queue %q: %v", q.Name(), err)
+			}
+			select {
+			case <-wake:
+			case <-time.After(PollInterval):
+			}
+			continue
+		}
+
+		if err = handle(t.Key); err != nil {
+			log.Error("Failed to process task %q of queue %q [attempts: %d]: %v", t.Key, q.Name(), t.Attempts, err)
+			reason := err.Error()
+			var dropped bool
+			dropped, err = q.Nack(t, reason)
+			if err == nil && dropped && giveUp != nil {
+				giveUp(t.Key, reason)
+			}
+		} else {
+			err = q.Ack(t)
+		}
+		if err != nil {
+			log.Error("Failed to release task %q of queue %q: %v", t.Key, q.Name(), err)
+		}
+	}
+}
+
+// discard is a queue that discards all tasks.
+type discard struct {
+	name string
+}
+
+// NewDiscard returns a queue that discards all tasks, which is used by
+// processes that do not have access to the queue.
+func NewDiscard(name string) Queue {
+	return &discard{name: name}
+}
+
+func (q *discard) Name() string                     { return q.name }
+func (q *discard) Push(string) error                { return nil }
+func (q *discard) Pop() (*Task, error)              { return nil, ErrEmpty }
+func (q *discard) Ack(*Task) error                  { return nil }
+func (q *discard) Nack(*Task, string) (bool, error) { return false, nil }
+func (q *discard) Has(string) (bool, error)         { return false, nil }
+func (q *discard) List() ([]*Task, error)           { return nil, nil }
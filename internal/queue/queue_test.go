@@ -0,0 +1,120 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_RetryDelay(t *testing.T) {
+	opts := Options{RetryInterval: time.Minute}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Minute},
+		{attempts: 2, want: 2 * time.Minute},
+		{attempts: 3, want: 4 * time.Minute},
+		{attempts: 100, want: 24 * time.Hour},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, opts.RetryDelay(test.attempts), "attempts: %d", test.attempts)
+	}
+}
+
+func TestTask(t *testing.T) {
+	opts := Options{
+		VisibilityTimeout: time.Minute,
+		MaxAttempts:       2,
+		RetryInterval:     time.Minute,
+	}
+	now := time.Now()
+
+	task := NewTask("1", now)
+	assert.False(t, task.IsProcessing(now))
+	assert.False(t, task.MarkPushed(now), "pending task is not duplicated")
+
+	require.NoError(t, task.MarkPopped(now, opts))
+	assert.True(t, task.IsProcessing(now))
+	assert.Equal(t, 1, task.Attempts)
+	assert.NotEmpty(t, task.Receipt)
+
+	// Pushing the task being processed requests another run.
+	assert.True(t, task.MarkPushed(now))
+	assert.False(t, task.MarkPushed(now))
+	assert.False(t, task.MarkAcked(now))
+	assert.False(t, task.IsProcessing(now))
+	assert.Equal(t, 0, task.Attempts)
+
+	// Failed task is retried after the backoff.
+	require.NoError(t, task.MarkPopped(now, opts))
+	assert.False(t, task.MarkNacked(now, opts, "boom"))
+	assert.True(t, task.IsRetrying(now))
+	assert.Equal(t, now.Add(time.Minute), task.VisibleAt)
+	assert.Equal(t, "boom", task.LastError)
+
+	// Pushing the task waiting to be retried makes it visible immediately.
+	assert.True(t, task.MarkPushed(now))
+	assert.False(t, task.IsRetrying(now))
+
+	// Task is removed after running out of attempts.
+	require.NoError(t, task.MarkPopped(now, opts))
+	assert.True(t, task.MarkNacked(now, opts, "boom"))
+
+	// Task is removed after processed successfully.
+	task = NewTask("2", now)
+	require.NoError(t, task.MarkPopped(now, opts))
+	assert.True(t, task.MarkAcked(now))
+}
+
+func TestConsume(t *testing.T) {
+	d := openTestDisk(t)
+	q := d.Queue("test", Options{VisibilityTimeout: time.Minute, MaxAttempts: 3, RetryInterval: time.Minute})
+
+	handled := make(chan string, 3)
+	go Consume(q, func(key string) error {
+		handled <- key
+		return nil
+	}, nil)
+
+	require.NoError(t, q.Push("1"))
+	require.NoError(t, q.Push("2"))
+	for _, want := range []string{"1", "2"} {
+		select {
+		case got := <-handled:
+			assert.Equal(t, want, got)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("task %q is not consumed", want)
+		}
+	}
+}
+
+func TestConsume_giveUp(t *testing.T) {
+	d := openTestDisk(t)
+	q := d.Queue("test", Options{VisibilityTimeout: time.Minute, MaxAttempts: 1, RetryInterval: time.Minute})
+
+	gaveUp := make(chan string, 1)
+	go Consume(q,
+		func(key string) error {
+			return errors.New("boom")
+		},
+		func(key, reason string) {
+			gaveUp <- key + ": " + reason
+		},
+	)
+
+	require.NoError(t, q.Push("1"))
+	select {
+	case got := <-gaveUp:
+		assert.Equal(t, "1: boom", got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("task is not given up")
+	}
+}
@@ -137,6 +137,8 @@ const (
 	SyncSSHAuthorizedKey
 	SyncRepositoryHooks
 	ReinitMissingRepository
+	GCLFSObjects
+	RebuildCodeIndex
 )
 
 func Operation(c *context.Context) {
@@ -164,6 +166,12 @@ func Operation(c *context.Context) {
 	case ReinitMissingRepository:
 		success = c.Tr("admin.dashboard.reinit_missing_repos_success")
 		err = db.ReinitMissingRepositories()
+	case GCLFSObjects:
+		success = c.Tr("admin.dashboard.gc_lfs_objects_success")
+		err = db.GarbageCollectLFSObjects()
+	case RebuildCodeIndex:
+		success = c.Tr("admin.dashboard.rebuild_code_index_success")
+		err = db.RebuildCodeIndex()
 	}
 
 	if err != nil {
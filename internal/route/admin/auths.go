@@ -15,6 +15,7 @@ import (
 	"gogs.io/gogs/internal/auth"
 	"gogs.io/gogs/internal/auth/github"
 	"gogs.io/gogs/internal/auth/ldap"
+	"gogs.io/gogs/internal/auth/oidc"
 	"gogs.io/gogs/internal/auth/pam"
 	"gogs.io/gogs/internal/auth/smtp"
 	"gogs.io/gogs/internal/conf"
@@ -57,6 +58,7 @@ var (
 		{auth.Name(auth.SMTP), auth.SMTP},
 		{auth.Name(auth.PAM), auth.PAM},
 		{auth.Name(auth.GitHub), auth.GitHub},
+		{auth.Name(auth.OIDC), auth.OIDC},
 	}
 	securityProtocols = []dropdownItem{
 		{ldap.SecurityProtocolName(ldap.SecurityProtocolUnencrypted), ldap.SecurityProtocolUnencrypted},
@@ -104,6 +106,8 @@ func parseLDAPConfig(f form.Authentication) *ldap.Config {
 		GroupMemberUID:    f.GroupMemberUID,
 		UserUID:           f.UserUID,
 		AdminFilter:       f.AdminFilter,
+		AdminGroupDN:      f.AdminGroupDN,
+		GroupTeamMap:      f.GroupTeamMap,
 	}
 }
 
@@ -118,6 +122,23 @@ func parseSMTPConfig(f form.Authentication) *smtp.Config {
 	}
 }
 
+func parseOIDCConfig(f form.Authentication) *oidc.Config {
+	return &oidc.Config{
+		DiscoveryURL:  f.OIDCDiscoveryURL,
+		ClientID:      f.OIDCClientID,
+		ClientSecret:  f.OIDCClientSecret,
+		Scopes:        f.OIDCScopes,
+		UsernameClaim: f.OIDCUsernameClaim,
+		EmailClaim:    f.OIDCEmailClaim,
+		FullNameClaim: f.OIDCFullNameClaim,
+		AdminClaim:    f.OIDCAdminClaim,
+		GroupsClaim:   f.OIDCGroupsClaim,
+		AdminGroup:    f.OIDCAdminGroup,
+		RequiredGroup: f.OIDCRequiredGroup,
+		SkipVerify:    f.SkipVerify,
+	}
+}
+
 func NewAuthSourcePost(c *context.Context, f form.Authentication) {
 	c.Title("admin.auths.new")
 	c.PageIs("Admin")
@@ -148,6 +169,9 @@ func NewAuthSourcePost(c *context.Context, f form.Authentication) {
 			SkipVerify:  f.SkipVerify,
 		}
 		hasTLS = true
+	case auth.OIDC:
+		config = parseOIDCConfig(f)
+		hasTLS = true
 	default:
 		c.Status(http.StatusBadRequest)
 		return
@@ -159,6 +183,12 @@ func NewAuthSourcePost(c *context.Context, f form.Authentication) {
 		return
 	}
 
+	if _, err := ldap.ParseGroupTeamMap(f.GroupTeamMap); err != nil {
+		c.FormErr("GroupTeamMap")
+		c.RenderWithErr(c.Tr("admin.auths.invalid_group_team_map", err.Error()), AUTH_NEW, f)
+		return
+	}
+
 	source, err := db.LoginSources.Create(c.Req.Context(),
 		db.CreateLoginSourceOptions{
 			Type:      auth.Type(f.Type),
@@ -231,6 +261,12 @@ func EditAuthSourcePost(c *context.Context, f form.Authentication) {
 		return
 	}
 
+	if _, err := ldap.ParseGroupTeamMap(f.GroupTeamMap); err != nil {
+		c.FormErr("GroupTeamMap")
+		c.RenderWithErr(c.Tr("admin.auths.invalid_group_team_map", err.Error()), AUTH_EDIT, f)
+		return
+	}
+
 	var provider auth.Provider
 	switch auth.Type(f.Type) {
 	case auth.LDAP:
@@ -248,6 +284,8 @@ func EditAuthSourcePost(c *context.Context, f form.Authentication) {
 			APIEndpoint: strings.TrimSuffix(f.GitHubAPIEndpoint, "/") + "/",
 			SkipVerify:  f.SkipVerify,
 		})
+	case auth.OIDC:
+		provider = oidc.NewProvider(parseOIDCConfig(f))
 	default:
 		c.Status(http.StatusBadRequest)
 		return
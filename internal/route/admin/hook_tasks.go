@@ -0,0 +1,98 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+
+	"github.com/unknwon/com"
+	"github.com/unknwon/paginater"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+const (
+	FAILED_HOOK_TASKS = "admin/hook_tasks"
+)
+
+func FailedHookTasks(c *context.Context) {
+	c.Title("admin.hook_tasks")
+	c.Data["PageIsAdmin"] = true
+	c.Data["PageIsAdminHookTasks"] = true
+
+	total := db.CountFailedHookTasks()
+	page := c.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	c.Data["Page"] = paginater.New(int(total), conf.UI.Admin.NoticePagingNum, page, 5)
+
+	tasks, err := db.FailedHookTasks(page, conf.UI.Admin.NoticePagingNum)
+	if err != nil {
+		c.Error(err, "list failed hook tasks")
+		return
+	}
+	c.Data["HookTasks"] = tasks
+
+	repos := make(map[int64]*db.Repository, len(tasks))
+	for _, t := range tasks {
+		if _, ok := repos[t.RepoID]; ok {
+			continue
+		}
+
+		repo, err := db.GetRepositoryByID(t.RepoID)
+		if err != nil && !db.IsErrRepoNotExist(err) {
+			c.Error(err, "get repository by ID")
+			return
+		} else if err == nil {
+			if err = repo.GetOwner(); err != nil {
+				c.Error(err, "get owner")
+				return
+			}
+		}
+		repos[t.RepoID] = repo
+	}
+	c.Data["Repos"] = repos
+
+	c.Data["Total"] = total
+	c.Success(FAILED_HOOK_TASKS)
+}
+
+func RedeliverHookTasks(c *context.Context) {
+	strs := c.QueryStrings("ids[]")
+	ids := make([]int64, 0, len(strs))
+	for i := range strs {
+		id := com.StrTo(strs[i]).MustInt64()
+		if id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	if err := db.RedeliverHookTasks(ids...); err != nil {
+		c.Flash.Error("RedeliverHookTasks: " + err.Error())
+		c.Status(http.StatusInternalServerError)
+	} else {
+		c.Flash.Success(c.Tr("admin.hook_tasks.redeliver_success"))
+		c.Status(http.StatusOK)
+	}
+}
+
+func RedeliverAllHookTasks(c *context.Context) {
+	if err := db.RedeliverHookTasks(); err != nil {
+		c.Error(err, "redeliver hook tasks")
+		return
+	}
+
+	log.Trace("Failed hook tasks redelivered by admin (%s)", c.User.Name)
+	c.Flash.Success(c.Tr("admin.hook_tasks.redeliver_success"))
+	c.Redirect(conf.Server.Subpath + "/admin/hook_tasks")
+}
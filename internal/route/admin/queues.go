@@ -0,0 +1,65 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"time"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/queue"
+)
+
+const (
+	QUEUES = "admin/queues"
+)
+
+// QueueTask is a task in the queue with its state for display.
+type QueueTask struct {
+	*queue.Task
+	// The state of the task, one of "processing", "retrying" and "pending".
+	State string
+}
+
+// QueueInfo contains tasks of a queue for display.
+type QueueInfo struct {
+	Name  string
+	Tasks []*QueueTask
+}
+
+func Queues(c *context.Context) {
+	c.Title("admin.queues")
+	c.Data["PageIsAdmin"] = true
+	c.Data["PageIsAdminQueues"] = true
+
+	now := time.Now()
+	queues := db.Queues()
+	infos := make([]*QueueInfo, 0, len(queues))
+	for _, q := range queues {
+		tasks, err := q.List()
+		if err != nil {
+			c.Errorf(err, "list tasks of queue %q", q.Name())
+			return
+		}
+
+		info := &QueueInfo{
+			Name:  q.Name(),
+			Tasks: make([]*QueueTask, 0, len(tasks)),
+		}
+		for _, t := range tasks {
+			state := "pending"
+			if t.IsProcessing(now) {
+				state = "processing"
+			} else if t.IsRetrying(now) {
+				state = "retrying"
+			}
+			info.Tasks = append(info.Tasks, &QueueTask{Task: t, State: state})
+		}
+		infos = append(infos, info)
+	}
+	c.Data["Queues"] = infos
+
+	c.Success(QUEUES)
+}
@@ -13,6 +13,7 @@ import (
 
 	api "github.com/gogs/go-gogs-client"
 
+	"gogs.io/gogs/internal/auth"
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/form"
@@ -54,7 +55,7 @@ func repoAssignment() macaron.Handler {
 			return
 		}
 
-		if c.IsTokenAuth && c.User.IsAdmin {
+		if c.IsTokenAuth && c.User.IsAdmin && c.TokenScopes.Has(auth.ScopeAdminSite) {
 			c.Repo.AccessMode = db.AccessModeOwner
 		} else {
 			c.Repo.AccessMode = db.Perms.AccessMode(c.Req.Context(), c.UserID(), repo.ID,
@@ -118,6 +119,30 @@ func reqToken() macaron.Handler {
 	}
 }
 
+// reqScope makes sure the token used by the context user is granted the read
+// scope for safe requests (i.e. GET and HEAD) and the write scope for the
+// others. An empty scope means no scope is required. It is noop when the
+// context user is not authorized via access token.
+func reqScope(read, write auth.Scope) macaron.Handler {
+	return func(c *context.Context) {
+		if !c.IsTokenAuth {
+			return
+		}
+
+		scope := write
+		if c.Req.Method == http.MethodGet || c.Req.Method == http.MethodHead {
+			if read == "" || c.TokenScopes.Has(write) {
+				return
+			}
+			scope = read
+		}
+		if scope != "" && !c.TokenScopes.Has(scope) {
+			c.PlainText(http.StatusForbidden, "The access token is not granted the scope: "+string(scope))
+			return
+		}
+	}
+}
+
 // reqBasicAuth makes sure the context user is authorized via HTTP Basic Auth.
 func reqBasicAuth() macaron.Handler {
 	return func(c *context.Context) {
@@ -165,6 +190,13 @@ func mustEnableIssues(c *context.APIContext) {
 	}
 }
 
+func mustAllowPulls(c *context.APIContext) {
+	if !c.Repo.Repository.AllowsPulls() {
+		c.NotFound()
+		return
+	}
+}
+
 // RegisterRoutes registers all route in API v1 to the web application.
 // FIXME: custom form error response
 func RegisterRoutes(m *macaron.Macaron) {
@@ -188,38 +220,43 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Group("/tokens", func() {
 					m.Combo("").
 						Get(user.ListAccessTokens).
-						Post(bind(api.CreateAccessTokenOption{}), user.CreateAccessToken)
+						Post(bind(user.CreateAccessTokenOption{}), user.CreateAccessToken)
 				}, reqBasicAuth())
 			})
 		})
 
 		m.Group("/users", func() {
 			m.Group("/:username", func() {
-				m.Get("/keys", user.ListPublicKeys)
+				m.Get("/keys", reqScope(auth.ScopeUser, auth.ScopeWriteKeys), user.ListPublicKeys)
 
-				m.Get("/followers", user.ListFollowers)
-				m.Group("/following", func() {
-					m.Get("", user.ListFollowing)
-					m.Get("/:target", user.CheckFollowing)
-				})
+				m.Group("", func() {
+					m.Get("/followers", user.ListFollowers)
+					m.Group("/following", func() {
+						m.Get("", user.ListFollowing)
+						m.Get("/:target", user.CheckFollowing)
+					})
+				}, reqScope(auth.ScopeUser, auth.ScopeUser))
 			})
 		}, reqToken())
 
 		m.Group("/user", func() {
 			m.Get("", user.GetAuthenticatedUser)
-			m.Combo("/emails").
-				Get(user.ListEmails).
-				Post(bind(api.CreateEmailOption{}), user.AddEmail).
-				Delete(bind(api.CreateEmailOption{}), user.DeleteEmail)
-
-			m.Get("/followers", user.ListMyFollowers)
-			m.Group("/following", func() {
-				m.Get("", user.ListMyFollowing)
-				m.Combo("/:username").
-					Get(user.CheckMyFollowing).
-					Put(user.Follow).
-					Delete(user.Unfollow)
-			})
+
+			m.Group("", func() {
+				m.Combo("/emails").
+					Get(user.ListEmails).
+					Post(bind(api.CreateEmailOption{}), user.AddEmail).
+					Delete(bind(api.CreateEmailOption{}), user.DeleteEmail)
+
+				m.Get("/followers", user.ListMyFollowers)
+				m.Group("/following", func() {
+					m.Get("", user.ListMyFollowing)
+					m.Combo("/:username").
+						Get(user.CheckMyFollowing).
+						Put(user.Follow).
+						Delete(user.Unfollow)
+				})
+			}, reqScope(auth.ScopeUser, auth.ScopeUser))
 
 			m.Group("/keys", func() {
 				m.Combo("").
@@ -228,28 +265,42 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Combo("/:id").
 					Get(user.GetPublicKey).
 					Delete(user.DeletePublicKey)
-			})
+			}, reqScope(auth.ScopeUser, auth.ScopeWriteKeys))
 
-			m.Get("/issues", repo.ListUserIssues)
+			m.Get("/issues", reqScope(auth.ScopeRepoRead, auth.ScopeRepoWrite), repo.ListUserIssues)
 		}, reqToken())
 
+		// Notifications
+		m.Group("/notifications", func() {
+			m.Combo("").
+				Get(user.ListNotifications).
+				Put(bind(user.MarkNotificationsReadOption{}), user.MarkNotificationsRead).
+				Patch(bind(user.MarkNotificationsReadOption{}), user.MarkNotificationsRead)
+			m.Combo("/threads/:id").
+				Get(user.GetNotificationThread).
+				Patch(user.MarkNotificationThreadRead)
+		}, reqToken(), reqScope(auth.ScopeUser, auth.ScopeUser))
+
 		// Repositories
-		m.Get("/users/:username/repos", reqToken(), repo.ListUserRepositories)
-		m.Get("/orgs/:org/repos", reqToken(), repo.ListOrgRepositories)
-		m.Combo("/user/repos", reqToken()).
-			Get(repo.ListMyRepos).
-			Post(bind(api.CreateRepoOption{}), repo.Create)
-		m.Post("/org/:org/repos", reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
+		m.Group("", func() {
+			m.Get("/users/:username/repos", reqToken(), repo.ListUserRepositories)
+			m.Get("/orgs/:org/repos", reqToken(), repo.ListOrgRepositories)
+			m.Combo("/user/repos", reqToken()).
+				Get(repo.ListMyRepos).
+				Post(bind(api.CreateRepoOption{}), repo.Create)
+			m.Post("/org/:org/repos", reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
+		}, reqScope(auth.ScopeRepoRead, auth.ScopeRepoWrite))
 
 		m.Group("/repos", func() {
 			m.Get("/search", repo.Search)
 
 			m.Get("/:username/:reponame", repoAssignment(), repo.Get)
 			m.Get("/:username/:reponame/releases", repoAssignment(), repo.Releases)
-		})
+		}, reqScope(auth.ScopeRepoRead, auth.ScopeRepoWrite))
 
 		m.Group("/repos", func() {
 			m.Post("/migrate", bind(form.MigrateRepo{}), repo.Migrate)
+			m.Get("/migrate/:id", repo.GetMigration)
 			m.Delete("/:username/:reponame", repoAssignment(), repo.Delete)
 
 			m.Group("/:username/:reponame", func() {
@@ -260,6 +311,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Combo("/:id").
 						Patch(bind(api.EditHookOption{}), repo.EditHook).
 						Delete(repo.DeleteHook)
+					m.Get("/:id/deliveries", repo.ListHookDeliveries)
 				}, reqRepoAdmin())
 
 				m.Group("/collaborators", func() {
@@ -270,7 +322,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 						Delete(repo.DeleteCollaborator)
 				}, reqRepoAdmin())
 
+				m.Combo("/notifications").
+					Get(user.ListRepoNotifications).
+					Put(bind(user.MarkNotificationsReadOption{}), user.MarkRepoNotificationsRead)
+
 				m.Get("/raw/*", context.RepoRef(), repo.GetRawFile)
+				m.Get("/blame/*", context.RepoRef(), repo.GetBlame)
 				m.Group("/contents", func() {
 					m.Get("", repo.GetContents)
 					m.Get("/*", repo.GetContents)
@@ -291,11 +348,17 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Get("/*", repo.GetBranch)
 				})
 				m.Group("/commits", func() {
+					m.Get("/:sha/status", repo.GetCombinedCommitStatus)
+					m.Get("/:sha/statuses", repo.ListCommitStatuses)
 					m.Get("/:sha", repo.GetSingleCommit)
 					m.Get("", repo.GetAllCommits)
 					m.Get("/*", repo.GetReferenceSHA)
 				})
 
+				m.Combo("/statuses/:sha").
+					Get(repo.ListCommitStatuses).
+					Post(reqRepoWriter(), bind(repo.CreateCommitStatusOption{}), repo.CreateCommitStatus)
+
 				m.Group("/keys", func() {
 					m.Combo("").
 						Get(repo.ListDeployKeys).
@@ -305,6 +368,16 @@ func RegisterRoutes(m *macaron.Macaron) {
 						Delete(repo.DeleteDeploykey)
 				}, reqRepoAdmin())
 
+				m.Group("/push_mirrors", func() {
+					m.Combo("").
+						Get(repo.ListPushMirrors).
+						Post(bind(form.NewPushMirror{}), repo.CreatePushMirror)
+					m.Combo("/:id").
+						Get(repo.GetPushMirror).
+						Delete(repo.DeletePushMirror)
+					m.Post("/:id/sync", repo.SyncPushMirror)
+				}, reqRepoAdmin())
+
 				m.Group("/issues", func() {
 					m.Combo("").
 						Get(repo.ListIssues).
@@ -338,6 +411,21 @@ func RegisterRoutes(m *macaron.Macaron) {
 					})
 				}, mustEnableIssues)
 
+				m.Group("/pulls", func() {
+					m.Combo("").
+						Get(repo.ListPullRequests).
+						Post(bind(repo.CreatePullRequestOption{}), repo.CreatePullRequest)
+					m.Group("/:index", func() {
+						m.Combo("").
+							Get(repo.GetPullRequest).
+							Patch(bind(repo.EditPullRequestOption{}), repo.EditPullRequest)
+						m.Get("/mergeable", repo.GetPullRequestMergeability)
+						m.Combo("/merge").
+							Get(repo.IsPullRequestMerged).
+							Post(reqRepoWriter(), bind(repo.MergePullRequestOption{}), repo.MergePullRequest)
+					})
+				}, mustAllowPulls)
+
 				m.Group("/labels", func() {
 					m.Get("", repo.ListLabels)
 					m.Get("/:id", repo.GetLabel)
@@ -365,12 +453,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Post("/mirror-sync", reqRepoWriter(), repo.MirrorSync)
 				m.Get("/editorconfig/:filename", context.RepoRef(), repo.GetEditorconfig)
 			}, repoAssignment())
-		}, reqToken())
+		}, reqToken(), reqScope(auth.ScopeRepoRead, auth.ScopeRepoWrite))
 
-		m.Get("/issues", reqToken(), repo.ListUserIssues)
+		m.Get("/issues", reqToken(), reqScope(auth.ScopeRepoRead, auth.ScopeRepoWrite), repo.ListUserIssues)
 
 		// Organizations
-		m.Combo("/user/orgs", reqToken()).
+		m.Combo("/user/orgs", reqToken(), reqScope(auth.ScopeUser, auth.ScopeAdminOrg)).
 			Get(org.ListMyOrgs).
 			Post(bind(api.CreateOrgOption{}), org.CreateMyOrg)
 
@@ -380,7 +468,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 				Get(org.Get).
 				Patch(bind(api.EditOrgOption{}), org.Edit)
 			m.Get("/teams", org.ListTeams)
-		}, orgAssignment(true))
+		}, orgAssignment(true), reqScope("", auth.ScopeAdminOrg))
 
 		m.Group("/admin", func() {
 			m.Group("/users", func() {
@@ -413,7 +501,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 						Delete(admin.RemoveTeamRepository)
 				}, orgAssignment(false, true))
 			})
-		}, reqAdmin())
+		}, reqAdmin(), reqScope(auth.ScopeAdminSite, auth.ScopeAdminSite))
 
 		m.Any("/*", func(c *context.Context) {
 			c.NotFound()
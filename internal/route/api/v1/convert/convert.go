@@ -6,6 +6,7 @@ package convert
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/unknwon/com"
 
@@ -13,6 +14,7 @@ import (
 	api "github.com/gogs/go-gogs-client"
 
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/gitutil"
 )
 
 func ToEmail(email *db.EmailAddress) *api.Email {
@@ -86,12 +88,17 @@ func ToHook(repoLink string, w *db.Webhook) *api.Hook {
 		"url":          w.URL,
 		"content_type": w.ContentType.Name(),
 	}
-	if w.HookTaskType == db.SLACK {
+	switch w.HookTaskType {
+	case db.SLACK, db.MATTERMOST:
 		s := w.SlackMeta()
 		config["channel"] = s.Channel
 		config["username"] = s.Username
 		config["icon_url"] = s.IconURL
 		config["color"] = s.Color
+	case db.CUSTOM:
+		m := w.CustomMeta()
+		config["template"] = m.Template
+		config["template_content_type"] = m.ContentType
 	}
 
 	return &api.Hook{
@@ -137,3 +144,326 @@ func ToTeam(team *db.Team) *api.Team {
 		Permission:  team.Authorize.String(),
 	}
 }
+
+type CommitStatus struct {
+	ID          int64     `json:"id"`
+	State       string    `json:"state"`
+	Context     string    `json:"context"`
+	TargetURL   string    `json:"target_url"`
+	Description string    `json:"description"`
+	Creator     *api.User `json:"creator"`
+	Created     time.Time `json:"created_at"`
+	Updated     time.Time `json:"updated_at"`
+}
+
+func ToCommitStatus(s *db.CommitStatus) *CommitStatus {
+	status := &CommitStatus{
+		ID:          s.ID,
+		State:       string(s.State),
+		Context:     s.Context,
+		TargetURL:   s.TargetURL,
+		Description: s.Description,
+		Created:     s.CreatedAt,
+		Updated:     s.UpdatedAt,
+	}
+	if s.Creator != nil {
+		status.Creator = s.Creator.APIFormat()
+	}
+	return status
+}
+
+type CombinedStatus struct {
+	State      string          `json:"state"`
+	SHA        string          `json:"sha"`
+	TotalCount int             `json:"total_count"`
+	Statuses   []*CommitStatus `json:"statuses"`
+}
+
+func ToCombinedStatus(sha string, statuses []*db.CommitStatus) *CombinedStatus {
+	apiStatuses := make([]*CommitStatus, len(statuses))
+	for i := range statuses {
+		apiStatuses[i] = ToCommitStatus(statuses[i])
+	}
+	return &CombinedStatus{
+		State:      string(db.CombinedCommitStatusState(statuses)),
+		SHA:        sha,
+		TotalCount: len(statuses),
+		Statuses:   apiStatuses,
+	}
+}
+
+type PushMirror struct {
+	ID           int64      `json:"id"`
+	RemoteURL    string     `json:"remote_url"`
+	Username     string     `json:"auth_username"`
+	SyncInterval int        `json:"sync_interval"`
+	SyncOnPush   bool       `json:"sync_on_push"`
+	Status       string     `json:"status"`
+	LastError    string     `json:"last_error"`
+	LastSynced   *time.Time `json:"last_synced_at"`
+	Created      time.Time  `json:"created_at"`
+	Updated      time.Time  `json:"updated_at"`
+}
+
+func ToPushMirror(m *db.PushMirror) *PushMirror {
+	return &PushMirror{
+		ID:           m.ID,
+		RemoteURL:    m.RemoteURL,
+		Username:     m.Username,
+		SyncInterval: m.SyncInterval,
+		SyncOnPush:   m.SyncOnPush,
+		Status:       string(m.Status()),
+		LastError:    m.LastError,
+		LastSynced:   m.LastSyncAt,
+		Created:      m.CreatedAt,
+		Updated:      m.UpdatedAt,
+	}
+}
+
+type RepoMigration struct {
+	ID           int64      `json:"id"`
+	OwnerID      int64      `json:"owner_id"`
+	RepoName     string     `json:"repo_name"`
+	RepoID       int64      `json:"repo_id"`
+	Service      string     `json:"service"`
+	CloneAddr    string     `json:"clone_addr"`
+	Labels       bool       `json:"labels"`
+	Milestones   bool       `json:"milestones"`
+	Issues       bool       `json:"issues"`
+	PullRequests bool       `json:"pull_requests"`
+	Releases     bool       `json:"releases"`
+	Status       string     `json:"status"`
+	Stage        string     `json:"stage"`
+	Progress     int        `json:"progress"`
+	Message      string     `json:"message"`
+	Created      time.Time  `json:"created_at"`
+	Updated      time.Time  `json:"updated_at"`
+	Finished     *time.Time `json:"finished_at"`
+}
+
+func ToRepoMigration(m *db.RepoMigration) *RepoMigration {
+	return &RepoMigration{
+		ID:           m.ID,
+		OwnerID:      m.OwnerID,
+		RepoName:     m.RepoName,
+		RepoID:       m.RepoID,
+		Service:      string(m.Service),
+		CloneAddr:    m.CloneAddr,
+		Labels:       m.WithLabels,
+		Milestones:   m.WithMilestones,
+		Issues:       m.WithIssues,
+		PullRequests: m.WithPullRequests,
+		Releases:     m.WithReleases,
+		Status:       string(m.Status),
+		Stage:        string(m.Stage),
+		Progress:     m.Progress,
+		Message:      m.Message,
+		Created:      m.CreatedAt,
+		Updated:      m.UpdatedAt,
+		Finished:     m.FinishedAt,
+	}
+}
+
+type HookDeliveryAttempt struct {
+	Delivered  time.Time `json:"delivered_at"`
+	DurationMS int64     `json:"duration_ms"`
+	Succeed    bool      `json:"succeed"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type HookDeliveryResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+}
+
+type HookDelivery struct {
+	ID          int64                  `json:"id"`
+	UUID        string                 `json:"uuid"`
+	Event       string                 `json:"event"`
+	URL         string                 `json:"url"`
+	Delivered   bool                   `json:"delivered"`
+	Succeed     bool                   `json:"succeed"`
+	Failed      bool                   `json:"failed"`
+	Attempts    int                    `json:"attempts"`
+	NextAttempt *time.Time             `json:"next_attempt_at"`
+	Request     map[string]string      `json:"request_headers"`
+	Response    *HookDeliveryResponse  `json:"response"`
+	History     []*HookDeliveryAttempt `json:"attempt_history"`
+}
+
+func ToHookDelivery(t *db.HookTask) *HookDelivery {
+	delivery := &HookDelivery{
+		ID:        t.ID,
+		UUID:      t.UUID,
+		Event:     string(t.EventType),
+		URL:       t.URL,
+		Delivered: t.IsDelivered,
+		Succeed:   t.IsSucceed,
+		Failed:    t.IsFailed,
+		Attempts:  t.Attempts,
+		History:   make([]*HookDeliveryAttempt, len(t.AttemptsInfo)),
+	}
+	if t.NextAttemptUnix > 0 {
+		next := t.NextAttemptTime()
+		delivery.NextAttempt = &next
+	}
+	if t.RequestInfo != nil {
+		delivery.Request = t.RequestInfo.Headers
+	}
+	if t.ResponseInfo != nil {
+		delivery.Response = &HookDeliveryResponse{
+			StatusCode: t.ResponseInfo.Status,
+			Headers:    t.ResponseInfo.Headers,
+			Body:       t.ResponseInfo.Body,
+		}
+	}
+	for i, a := range t.AttemptsInfo {
+		delivery.History[i] = &HookDeliveryAttempt{
+			Delivered:  a.DeliveredTime(),
+			DurationMS: a.Duration,
+			Succeed:    a.IsSucceed,
+			StatusCode: a.Status,
+			Error:      a.Error,
+		}
+	}
+	return delivery
+}
+
+type BlameCommit struct {
+	*api.CommitMeta
+	HTMLURL   string          `json:"html_url"`
+	Message   string          `json:"message"`
+	Author    *api.CommitUser `json:"author"`
+	Committer *api.CommitUser `json:"committer"`
+	// The commit to blame prior to this commit, nil when the lines were
+	// introduced by a root commit.
+	Previous *BlamePrevious `json:"previous"`
+}
+
+type BlamePrevious struct {
+	SHA  string `json:"sha"`
+	Path string `json:"path"`
+}
+
+type BlameRange struct {
+	StartingLine int          `json:"starting_line"`
+	EndingLine   int          `json:"ending_line"`
+	Commit       *BlameCommit `json:"commit"`
+	Lines        []string     `json:"lines"`
+}
+
+func ToBlameRange(apiLink, htmlLink string, p *gitutil.BlamePart) *BlameRange {
+	commit := &BlameCommit{
+		CommitMeta: &api.CommitMeta{
+			URL: apiLink + "/commits/" + p.Commit.ID,
+			SHA: p.Commit.ID,
+		},
+		HTMLURL: htmlLink + "/commit/" + p.Commit.ID,
+		Message: p.Commit.Summary,
+		Author: &api.CommitUser{
+			Name:  p.Commit.Author.Name,
+			Email: p.Commit.Author.Email,
+			Date:  p.Commit.Author.When.Format(time.RFC3339),
+		},
+		Committer: &api.CommitUser{
+			Name:  p.Commit.Committer.Name,
+			Email: p.Commit.Committer.Email,
+			Date:  p.Commit.Committer.When.Format(time.RFC3339),
+		},
+	}
+	if p.Commit.PreviousID != "" {
+		commit.Previous = &BlamePrevious{
+			SHA:  p.Commit.PreviousID,
+			Path: p.Commit.PreviousPath,
+		}
+	}
+	return &BlameRange{
+		StartingLine: p.StartLine,
+		EndingLine:   p.StartLine + len(p.Lines) - 1,
+		Commit:       commit,
+		Lines:        p.Lines,
+	}
+}
+
+type NotificationSubject struct {
+	Title            string `json:"title"`
+	URL              string `json:"url"`
+	LatestCommentURL string `json:"latest_comment_url"`
+	// One of "Issue", "PullRequest" and "Release".
+	Type string `json:"type"`
+}
+
+type NotificationThread struct {
+	// The ID is a string for compatibility with GitHub.
+	ID         string               `json:"id"`
+	Repository *api.Repository      `json:"repository"`
+	Subject    *NotificationSubject `json:"subject"`
+	Reason     string               `json:"reason"`
+	Unread     bool                 `json:"unread"`
+	Updated    time.Time            `json:"updated_at"`
+	LastRead   *time.Time           `json:"last_read_at"`
+	URL        string               `json:"url"`
+}
+
+func ToNotificationThread(apiLink string, t *db.NotificationThread) *NotificationThread {
+	repoLink := apiLink + "/repos/" + t.Repo.FullName()
+	subject := &NotificationSubject{
+		Title: t.Title(),
+	}
+	switch {
+	case t.Release != nil:
+		subject.Type = "Release"
+		subject.URL = repoLink + "/releases"
+	case t.Issue.IsPull:
+		subject.Type = "PullRequest"
+		subject.URL = fmt.Sprintf("%s/pulls/%d", repoLink, t.Issue.Index)
+	default:
+		subject.Type = "Issue"
+		subject.URL = fmt.Sprintf("%s/issues/%d", repoLink, t.Issue.Index)
+	}
+	subject.LatestCommentURL = subject.URL
+	if t.LatestCommentID > 0 {
+		subject.LatestCommentURL = fmt.Sprintf("%s/issues/comments/%d", repoLink, t.LatestCommentID)
+	}
+
+	id := com.ToStr(t.ID)
+	return &NotificationThread{
+		ID:         id,
+		Repository: t.Repo.APIFormatLegacy(nil),
+		Subject:    subject,
+		Reason:     string(t.Reason),
+		Unread:     !t.IsRead,
+		Updated:    t.UpdatedAt,
+		LastRead:   t.LastReadAt,
+		URL:        apiLink + "/notifications/threads/" + id,
+	}
+}
+
+type AccessToken struct {
+	Name     string     `json:"name"`
+	Sha1     string     `json:"sha1"`
+	Scopes   []string   `json:"scopes"`
+	Expires  *time.Time `json:"expires_at"`
+	LastUsed *time.Time `json:"last_used_at"`
+}
+
+func ToAccessToken(t *db.AccessToken) *AccessToken {
+	scopes := t.ScopeList()
+	token := &AccessToken{
+		Name:   t.Name,
+		Sha1:   t.Sha1,
+		Scopes: make([]string, len(scopes)),
+	}
+	for i := range scopes {
+		token.Scopes[i] = string(scopes[i])
+	}
+	if !t.Expires.IsZero() {
+		token.Expires = &t.Expires
+	}
+	if t.HasUsed {
+		token.LastUsed = &t.Updated
+	}
+	return token
+}
@@ -5,12 +5,18 @@
 package repo
 
 import (
+	"net/http"
+
 	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
 
+	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/route/api/v1/convert"
 	"gogs.io/gogs/internal/route/repo"
+	"gogs.io/gogs/internal/tool"
 )
 
 func GetRawFile(c *context.APIContext) {
@@ -34,6 +40,49 @@ func GetRawFile(c *context.APIContext) {
 	}
 }
 
+func GetBlame(c *context.APIContext) {
+	if c.Repo.Repository.IsBare {
+		c.NotFound()
+		return
+	}
+
+	entry, err := c.Repo.Commit.TreeEntry(c.Repo.TreePath)
+	if err != nil {
+		c.NotFoundOrError(gitutil.NewError(err), "get tree entry")
+		return
+	} else if entry.IsTree() {
+		c.NotFound()
+		return
+	}
+
+	blob := entry.Blob()
+	if blob.Size() >= conf.UI.MaxDisplayFileSize {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("File is too large to blame."))
+		return
+	}
+	p, err := blob.Bytes()
+	if err != nil {
+		c.Error(err, "read blob")
+		return
+	} else if !tool.IsTextFile(p) {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("File is not a text file."))
+		return
+	}
+
+	parts, err := gitutil.Blame(c.Repo.GitRepo.Path(), c.Repo.CommitID, c.Repo.TreePath)
+	if err != nil {
+		c.Error(err, "blame")
+		return
+	}
+
+	apiLink := c.BaseURL + "/repos/" + c.Repo.Repository.FullName()
+	ranges := make([]*convert.BlameRange, len(parts))
+	for i := range parts {
+		ranges[i] = convert.ToBlameRange(apiLink, c.Repo.Repository.HTMLURL(), parts[i])
+	}
+	c.JSONSuccess(&ranges)
+}
+
 func GetArchive(c *context.APIContext) {
 	repoPath := db.RepoPath(c.Params(":username"), c.Params(":reponame"))
 	gitRepo, err := git.Open(repoPath)
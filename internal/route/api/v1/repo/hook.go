@@ -69,6 +69,9 @@ func CreateHook(c *context.APIContext, form api.CreateHookOption) {
 				IssueComment: com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_ISSUE_COMMENT)),
 				PullRequest:  com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_PULL_REQUEST)),
 				Release:      com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_RELEASE)),
+				Status:       com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_STATUS)),
+
+				PullRequestReview: com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_PULL_REQUEST_REVIEW)),
 			},
 		},
 		IsActive:     form.Active,
@@ -91,6 +94,41 @@ func CreateHook(c *context.APIContext, form api.CreateHookOption) {
 			return
 		}
 		w.Meta = string(meta)
+	} else if w.HookTaskType == db.MATTERMOST {
+		meta, err := jsoniter.Marshal(&db.SlackMeta{
+			Channel:  form.Config["channel"],
+			Username: form.Config["username"],
+			IconURL:  form.Config["icon_url"],
+			Color:    form.Config["color"],
+		})
+		if err != nil {
+			c.Errorf(err, "marshal JSON")
+			return
+		}
+		w.Meta = string(meta)
+	} else if w.HookTaskType == db.CUSTOM {
+		if !c.User.IsAdmin {
+			c.ErrorStatus(http.StatusForbidden, errors.New("Only site admins can manage custom template webhooks."))
+			return
+		}
+		tmpl, ok := form.Config["template"]
+		if !ok {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("Missing config option: template"))
+			return
+		}
+		if _, err := db.ParseCustomTemplate(tmpl, ""); err != nil {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("Invalid template: "+err.Error()))
+			return
+		}
+		meta, err := jsoniter.Marshal(&db.CustomMeta{
+			Template:    tmpl,
+			ContentType: form.Config["template_content_type"],
+		})
+		if err != nil {
+			c.Errorf(err, "marshal JSON")
+			return
+		}
+		w.Meta = string(meta)
 	}
 
 	if err := w.UpdateEvent(); err != nil {
@@ -138,6 +176,38 @@ func EditHook(c *context.APIContext, form api.EditHookOption) {
 				}
 				w.Meta = string(meta)
 			}
+		} else if w.HookTaskType == db.MATTERMOST {
+			meta, err := jsoniter.Marshal(&db.SlackMeta{
+				Channel:  form.Config["channel"],
+				Username: form.Config["username"],
+				IconURL:  form.Config["icon_url"],
+				Color:    form.Config["color"],
+			})
+			if err != nil {
+				c.Errorf(err, "marshal JSON")
+				return
+			}
+			w.Meta = string(meta)
+		} else if w.HookTaskType == db.CUSTOM {
+			if tmpl, ok := form.Config["template"]; ok {
+				if !c.User.IsAdmin {
+					c.ErrorStatus(http.StatusForbidden, errors.New("Only site admins can manage custom template webhooks."))
+					return
+				}
+				if _, err := db.ParseCustomTemplate(tmpl, ""); err != nil {
+					c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("Invalid template: "+err.Error()))
+					return
+				}
+				meta, err := jsoniter.Marshal(&db.CustomMeta{
+					Template:    tmpl,
+					ContentType: form.Config["template_content_type"],
+				})
+				if err != nil {
+					c.Errorf(err, "marshal JSON")
+					return
+				}
+				w.Meta = string(meta)
+			}
 		}
 	}
 
@@ -156,6 +226,8 @@ func EditHook(c *context.APIContext, form api.EditHookOption) {
 	w.IssueComment = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_ISSUE_COMMENT))
 	w.PullRequest = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_PULL_REQUEST))
 	w.Release = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_RELEASE))
+	w.Status = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_STATUS))
+	w.PullRequestReview = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_PULL_REQUEST_REVIEW))
 	if err = w.UpdateEvent(); err != nil {
 		c.Errorf(err, "update event")
 		return
@@ -181,3 +253,27 @@ func DeleteHook(c *context.APIContext) {
 
 	c.NoContent()
 }
+
+func ListHookDeliveries(c *context.APIContext) {
+	w, err := db.GetWebhookOfRepoByID(c.Repo.Repository.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get webhook of repository by ID")
+		return
+	}
+
+	page := c.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	tasks, err := db.HookTasks(w.ID, page)
+	if err != nil {
+		c.Errorf(err, "list hook tasks")
+		return
+	}
+
+	deliveries := make([]*convert.HookDelivery, len(tasks))
+	for i := range tasks {
+		deliveries[i] = convert.ToHookDelivery(tasks[i])
+	}
+	c.JSONSuccess(&deliveries)
+}
@@ -14,6 +14,7 @@ import (
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/search"
 )
 
 func listIssues(c *context.APIContext, opts *db.IssuesOptions) {
@@ -43,12 +44,30 @@ func listIssues(c *context.APIContext, opts *db.IssuesOptions) {
 	c.JSONSuccess(&apiIssues)
 }
 
+// parseIssueQuery sets the search query from the "q" parameter to options,
+// the state and type qualifiers take precedence over other parameters.
+func parseIssueQuery(c *context.APIContext, opts *db.IssuesOptions) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		return
+	}
+
+	opts.Query = search.ParseIssueQuery(q)
+	if opts.Query.State != "" {
+		opts.IsClosed = opts.Query.State == "closed"
+	}
+	if opts.Query.Type != "" {
+		opts.IsPull = opts.Query.Type == "pr"
+	}
+}
+
 func ListUserIssues(c *context.APIContext) {
 	opts := db.IssuesOptions{
 		AssigneeID: c.User.ID,
 		Page:       c.QueryInt("page"),
 		IsClosed:   api.StateType(c.Query("state")) == api.STATE_CLOSED,
 	}
+	parseIssueQuery(c, &opts)
 
 	listIssues(c, &opts)
 }
@@ -59,6 +78,7 @@ func ListIssues(c *context.APIContext) {
 		Page:     c.QueryInt("page"),
 		IsClosed: api.StateType(c.Query("state")) == api.STATE_CLOSED,
 	}
+	parseIssueQuery(c, &opts)
 
 	listIssues(c, &opts)
 }
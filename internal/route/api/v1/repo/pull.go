@@ -0,0 +1,467 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gogs/git-module"
+	api "github.com/gogs/go-gogs-client"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/gitutil"
+)
+
+// CreatePullRequestOption is the request body to create a pull request. The
+// "head" is either a branch name of the same repository, or in the form of
+// "<username>:<branch>" for a branch of a fork.
+type CreatePullRequestOption struct {
+	Title     string  `json:"title" binding:"Required"`
+	Body      string  `json:"body"`
+	Head      string  `json:"head" binding:"Required"`
+	Base      string  `json:"base" binding:"Required"`
+	Assignee  string  `json:"assignee"`
+	Milestone int64   `json:"milestone"`
+	Labels    []int64 `json:"labels"`
+}
+
+// EditPullRequestOption is the request body to edit a pull request.
+type EditPullRequestOption struct {
+	Title     string  `json:"title"`
+	Body      *string `json:"body"`
+	Assignee  *string `json:"assignee"`
+	Milestone *int64  `json:"milestone"`
+	State     *string `json:"state"`
+}
+
+// MergePullRequestOption is the request body to merge a pull request.
+type MergePullRequestOption struct {
+	Style string `json:"merge_style"`
+	// The description of the merge commit, or the message of the squashed commit
+	// when merge style is "squash".
+	Description string `json:"commit_description"`
+}
+
+// PullRequestMergeability is the response of the mergeability check of a pull
+// request.
+type PullRequestMergeability struct {
+	Status    string `json:"status"`
+	Mergeable bool   `json:"mergeable"`
+	HasMerged bool   `json:"merged"`
+
+	// Merge requirements of the protected base branch
+	MissingStatusChecks []string `json:"missing_status_checks"`
+	Approvals           int      `json:"approvals"`
+	RequiredApprovals   int      `json:"required_approvals"`
+}
+
+// getPullRequestByIndex returns the pull request with its issue and
+// repositories loaded by the index in the URL.
+func getPullRequestByIndex(c *context.APIContext) *db.PullRequest {
+	issue, err := db.GetIssueByIndex(c.Repo.Repository.ID, c.ParamsInt64(":index"))
+	if err != nil {
+		c.NotFoundOrError(err, "get issue by index")
+		return nil
+	}
+	if !issue.IsPull || issue.PullRequest == nil {
+		c.NotFound()
+		return nil
+	}
+
+	pr := issue.PullRequest
+	pr.Issue = issue
+	pr.Issue.Repo = c.Repo.Repository
+	return pr
+}
+
+func ListPullRequests(c *context.APIContext) {
+	opts := &db.IssuesOptions{
+		RepoID:   c.Repo.Repository.ID,
+		Page:     c.QueryInt("page"),
+		IsClosed: api.StateType(c.Query("state")) == api.STATE_CLOSED,
+		IsPull:   true,
+		SortType: c.Query("sort"),
+	}
+
+	issues, err := db.Issues(opts)
+	if err != nil {
+		c.Error(err, "list pull requests")
+		return
+	}
+
+	count, err := db.IssuesCount(opts)
+	if err != nil {
+		c.Error(err, "count pull requests")
+		return
+	}
+
+	apiPullRequests := make([]*api.PullRequest, 0, len(issues))
+	for i := range issues {
+		if issues[i].PullRequest == nil {
+			continue
+		}
+
+		issues[i].PullRequest.Issue = issues[i]
+		apiPullRequests = append(apiPullRequests, issues[i].PullRequest.APIFormat())
+	}
+
+	c.SetLinkHeader(int(count), conf.UI.IssuePagingNum)
+	c.JSONSuccess(&apiPullRequests)
+}
+
+func GetPullRequest(c *context.APIContext) {
+	pr := getPullRequestByIndex(c)
+	if c.Written() {
+		return
+	}
+	c.JSONSuccess(pr.APIFormat())
+}
+
+// parsePullRequestHead returns the head user, repository and its Git
+// repository by given head information in the form of "[<username>:]<branch>".
+func parsePullRequestHead(c *context.APIContext, head string) (*db.User, *db.Repository, *git.Repository, string) {
+	baseRepo := c.Repo.Repository
+
+	var (
+		headUser   *db.User
+		headRepo   *db.Repository
+		headBranch string
+		err        error
+	)
+	headInfos := strings.Split(head, ":")
+	switch len(headInfos) {
+	case 1:
+		headUser = c.Repo.Owner
+		headRepo = baseRepo
+		headBranch = headInfos[0]
+	case 2:
+		headUser, err = db.GetUserByName(headInfos[0])
+		if err != nil {
+			if db.IsErrUserNotExist(err) {
+				c.ErrorStatus(http.StatusUnprocessableEntity, fmt.Errorf("head user does not exist: [name: %s]", headInfos[0]))
+			} else {
+				c.Error(err, "get user by name")
+			}
+			return nil, nil, nil, ""
+		}
+		headBranch = headInfos[1]
+
+		if headUser.ID == baseRepo.OwnerID {
+			headRepo = baseRepo
+			break
+		}
+
+		var has bool
+		headRepo, has, err = db.HasForkedRepo(headUser.ID, baseRepo.ID)
+		if err != nil {
+			c.Error(err, "get forked repository")
+			return nil, nil, nil, ""
+		} else if !has {
+			c.ErrorStatus(http.StatusUnprocessableEntity, fmt.Errorf("user %q does not have a fork of the repository", headUser.Name))
+			return nil, nil, nil, ""
+		}
+	default:
+		c.ErrorStatus(http.StatusUnprocessableEntity, fmt.Errorf("invalid head: %s", head))
+		return nil, nil, nil, ""
+	}
+
+	if !c.User.IsWriterOfRepo(headRepo) && !c.User.IsAdmin {
+		c.Status(http.StatusForbidden)
+		return nil, nil, nil, ""
+	}
+
+	headGitRepo, err := git.Open(headRepo.RepoPath())
+	if err != nil {
+		c.Error(err, "open repository")
+		return nil, nil, nil, ""
+	}
+	if !headGitRepo.HasBranch(headBranch) {
+		c.ErrorStatus(http.StatusUnprocessableEntity, fmt.Errorf("head branch does not exist: %s", headBranch))
+		return nil, nil, nil, ""
+	}
+	return headUser, headRepo, headGitRepo, headBranch
+}
+
+func CreatePullRequest(c *context.APIContext, form CreatePullRequestOption) {
+	repo := c.Repo.Repository
+
+	baseGitRepo, err := git.Open(repo.RepoPath())
+	if err != nil {
+		c.Error(err, "open repository")
+		return
+	}
+	if !baseGitRepo.HasBranch(form.Base) {
+		c.ErrorStatus(http.StatusUnprocessableEntity, fmt.Errorf("base branch does not exist: %s", form.Base))
+		return
+	}
+
+	headUser, headRepo, headGitRepo, headBranch := parsePullRequestHead(c, form.Head)
+	if c.Written() {
+		return
+	}
+
+	_, err = db.GetUnmergedPullRequest(headRepo.ID, repo.ID, headBranch, form.Base)
+	if err == nil {
+		c.ErrorStatus(http.StatusConflict, fmt.Errorf("pull request already exists for %s:%s", headUser.Name, headBranch))
+		return
+	} else if !db.IsErrPullRequestNotExist(err) {
+		c.Error(err, "get unmerged pull request")
+		return
+	}
+
+	meta, err := gitutil.Module.PullRequestMeta(headGitRepo.Path(), repo.RepoPath(), headBranch, form.Base)
+	if err != nil {
+		if gitutil.IsErrNoMergeBase(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, fmt.Errorf("no merge base found between %s and %s", form.Base, form.Head))
+		} else {
+			c.Error(err, "get pull request meta")
+		}
+		return
+	} else if len(meta.Commits) == 0 {
+		c.ErrorStatus(http.StatusUnprocessableEntity, fmt.Errorf("no commits between %s and %s", form.Base, form.Head))
+		return
+	}
+
+	patch, err := headGitRepo.DiffBinary(meta.MergeBase, headBranch)
+	if err != nil {
+		c.Error(err, "get patch")
+		return
+	}
+
+	pullIssue := &db.Issue{
+		RepoID:   repo.ID,
+		Index:    repo.NextIssueIndex(),
+		Title:    form.Title,
+		PosterID: c.User.ID,
+		Poster:   c.User,
+		IsPull:   true,
+		Content:  form.Body,
+	}
+
+	labelIDs := form.Labels
+	if c.Repo.IsWriter() {
+		if form.Assignee != "" {
+			assignee, err := db.GetUserByName(form.Assignee)
+			if err != nil {
+				if db.IsErrUserNotExist(err) {
+					c.ErrorStatus(http.StatusUnprocessableEntity, fmt.Errorf("assignee does not exist: [name: %s]", form.Assignee))
+				} else {
+					c.Error(err, "get user by name")
+				}
+				return
+			}
+			pullIssue.AssigneeID = assignee.ID
+		}
+		pullIssue.MilestoneID = form.Milestone
+	} else {
+		labelIDs = nil
+	}
+
+	pullRequest := &db.PullRequest{
+		HeadRepoID:   headRepo.ID,
+		BaseRepoID:   repo.ID,
+		HeadUserName: headUser.Name,
+		HeadBranch:   headBranch,
+		BaseBranch:   form.Base,
+		HeadRepo:     headRepo,
+		BaseRepo:     repo,
+		MergeBase:    meta.MergeBase,
+		Type:         db.PULL_REQUEST_GOGS,
+	}
+	if err = db.NewPullRequest(repo, pullIssue, labelIDs, nil, pullRequest, patch); err != nil {
+		c.Error(err, "new pull request")
+		return
+	} else if err = pullRequest.PushToBaseRepo(); err != nil {
+		c.Error(err, "push to base repository")
+		return
+	}
+	log.Trace("Pull request created via API: %d/%d", repo.ID, pullIssue.ID)
+
+	// Refetch from database to assign some automatic values
+	issue, err := db.GetIssueByID(pullIssue.ID)
+	if err != nil {
+		c.Error(err, "get issue by ID")
+		return
+	}
+	issue.PullRequest.Issue = issue
+	c.JSON(http.StatusCreated, issue.PullRequest.APIFormat())
+}
+
+func EditPullRequest(c *context.APIContext, form EditPullRequestOption) {
+	pr := getPullRequestByIndex(c)
+	if c.Written() {
+		return
+	}
+	issue := pr.Issue
+
+	if !issue.IsPoster(c.User.ID) && !c.Repo.IsWriter() {
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	if len(form.Title) > 0 {
+		issue.Title = form.Title
+	}
+	if form.Body != nil {
+		issue.Content = *form.Body
+	}
+
+	var err error
+	if c.Repo.IsWriter() && form.Assignee != nil &&
+		(issue.Assignee == nil || issue.Assignee.LowerName != strings.ToLower(*form.Assignee)) {
+		if *form.Assignee == "" {
+			issue.AssigneeID = 0
+		} else {
+			assignee, err := db.GetUserByName(*form.Assignee)
+			if err != nil {
+				if db.IsErrUserNotExist(err) {
+					c.ErrorStatus(http.StatusUnprocessableEntity, fmt.Errorf("assignee does not exist: [name: %s]", *form.Assignee))
+				} else {
+					c.Error(err, "get user by name")
+				}
+				return
+			}
+			issue.AssigneeID = assignee.ID
+		}
+
+		if err = db.UpdateIssueUserByAssignee(issue); err != nil {
+			c.Error(err, "update issue user by assignee")
+			return
+		}
+	}
+	if c.Repo.IsWriter() && form.Milestone != nil &&
+		issue.MilestoneID != *form.Milestone {
+		oldMilestoneID := issue.MilestoneID
+		issue.MilestoneID = *form.Milestone
+		if err = db.ChangeMilestoneAssign(c.User, issue, oldMilestoneID); err != nil {
+			c.Error(err, "change milestone assign")
+			return
+		}
+	}
+
+	if err = db.UpdateIssue(issue); err != nil {
+		c.Error(err, "update issue")
+		return
+	}
+	if form.State != nil && !pr.HasMerged {
+		if err = issue.ChangeStatus(c.User, c.Repo.Repository, api.STATE_CLOSED == api.StateType(*form.State)); err != nil {
+			c.Error(err, "change status")
+			return
+		}
+	}
+
+	// Refetch from database to assign some automatic values
+	issue, err = db.GetIssueByID(issue.ID)
+	if err != nil {
+		c.Error(err, "get issue by ID")
+		return
+	}
+	issue.PullRequest.Issue = issue
+	c.JSON(http.StatusCreated, issue.PullRequest.APIFormat())
+}
+
+// IsPullRequestMerged responds with 204 if the pull request has been merged,
+// and 404 otherwise.
+func IsPullRequestMerged(c *context.APIContext) {
+	pr := getPullRequestByIndex(c)
+	if c.Written() {
+		return
+	}
+
+	if pr.HasMerged {
+		c.NoContent()
+		return
+	}
+	c.NotFound()
+}
+
+func GetPullRequestMergeability(c *context.APIContext) {
+	pr := getPullRequestByIndex(c)
+	if c.Written() {
+		return
+	}
+
+	mergeability := &PullRequestMergeability{
+		Status:    pr.Status.String(),
+		Mergeable: !pr.HasMerged && !pr.Issue.IsClosed && pr.CanAutoMerge(),
+		HasMerged: pr.HasMerged,
+	}
+	if mergeability.Mergeable {
+		headCommitID, err := pr.HeadCommitID()
+		if err != nil {
+			c.Error(err, "get head commit ID")
+			return
+		}
+		check, err := pr.CheckProtectBranch(headCommitID)
+		if err != nil {
+			c.Error(err, "check protect branch")
+			return
+		}
+
+		mergeability.Mergeable = check.IsSatisfied()
+		mergeability.MissingStatusChecks = check.MissingContexts
+		mergeability.Approvals = check.Approvals
+		mergeability.RequiredApprovals = check.RequiredApprovals
+	}
+	c.JSONSuccess(mergeability)
+}
+
+func MergePullRequest(c *context.APIContext, form MergePullRequestOption) {
+	pr := getPullRequestByIndex(c)
+	if c.Written() {
+		return
+	}
+
+	if pr.HasMerged {
+		c.ErrorStatus(http.StatusMethodNotAllowed, fmt.Errorf("pull request has already been merged"))
+		return
+	} else if pr.Issue.IsClosed {
+		c.ErrorStatus(http.StatusMethodNotAllowed, fmt.Errorf("pull request has been closed"))
+		return
+	} else if !pr.CanAutoMerge() {
+		c.ErrorStatus(http.StatusMethodNotAllowed, fmt.Errorf("pull request is not mergeable: %s", pr.Status))
+		return
+	}
+
+	mergeStyle := db.MergeStyle(form.Style)
+	if mergeStyle == "" {
+		mergeStyle = db.MERGE_STYLE_REGULAR
+	}
+	if !pr.BaseRepo.IsMergeStyleAllowed(mergeStyle) {
+		c.ErrorStatus(http.StatusUnprocessableEntity, fmt.Errorf("merge style is not allowed: %s", form.Style))
+		return
+	}
+
+	baseGitRepo, err := git.Open(c.Repo.Repository.RepoPath())
+	if err != nil {
+		c.Error(err, "open repository")
+		return
+	}
+
+	if err = pr.Merge(c.User, baseGitRepo, mergeStyle, form.Description); err != nil {
+		if db.IsErrPullRequestProtectionNotSatisfied(err) || db.IsErrPullRequestNotFastForward(err) {
+			c.ErrorStatus(http.StatusMethodNotAllowed, err)
+			return
+		}
+		c.Error(err, "merge")
+		return
+	}
+	log.Trace("Pull request merged via API: %d", pr.ID)
+
+	pr, err = db.GetPullRequestByID(pr.ID)
+	if err != nil {
+		c.Error(err, "get pull request by ID")
+		return
+	} else if err = pr.LoadIssue(); err != nil {
+		c.Error(err, "load issue")
+		return
+	}
+	c.JSONSuccess(pr.APIFormat())
+}
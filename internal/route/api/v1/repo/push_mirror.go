@@ -0,0 +1,117 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/form"
+	"gogs.io/gogs/internal/route/api/v1/convert"
+)
+
+// getPushMirror returns the push mirror with ID in the URL if it belongs to the
+// repository.
+func getPushMirror(c *context.APIContext) *db.PushMirror {
+	m, err := db.PushMirrors.GetByID(c.Req.Context(), c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get push mirror")
+		return nil
+	} else if m.RepoID != c.Repo.Repository.ID {
+		c.NotFound()
+		return nil
+	}
+	return m
+}
+
+func ListPushMirrors(c *context.APIContext) {
+	mirrors, err := db.PushMirrors.List(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.Error(err, "list push mirrors")
+		return
+	}
+
+	apiMirrors := make([]*convert.PushMirror, len(mirrors))
+	for i := range mirrors {
+		apiMirrors[i] = convert.ToPushMirror(mirrors[i])
+	}
+	c.JSONSuccess(apiMirrors)
+}
+
+func GetPushMirror(c *context.APIContext) {
+	m := getPushMirror(c)
+	if c.Written() {
+		return
+	}
+	c.JSONSuccess(convert.ToPushMirror(m))
+}
+
+func CreatePushMirror(c *context.APIContext, f form.NewPushMirror) {
+	remoteAddr, err := f.ParseRemoteAddr(c.User)
+	if err != nil {
+		if db.IsErrInvalidCloneAddr(err) {
+			addrErr := err.(db.ErrInvalidCloneAddr)
+			switch {
+			case addrErr.IsURLError:
+				c.ErrorStatus(http.StatusUnprocessableEntity, err)
+			case addrErr.IsPermissionDenied:
+				c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("You are not allowed to push to local repositories."))
+			case addrErr.IsInvalidPath:
+				c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("Invalid local path, it does not exist or not a directory."))
+			case addrErr.IsBlockedLocalAddress:
+				c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("Remote address resolved to a local network address that is implicitly blocked."))
+			default:
+				c.Error(err, "unexpected error")
+			}
+		} else {
+			c.Error(err, "parse remote address")
+		}
+		return
+	}
+
+	m, err := db.PushMirrors.Create(c.Req.Context(), c.Repo.Repository.ID, conf.Security.SecretKey,
+		db.CreatePushMirrorOptions{
+			RemoteURL:    remoteAddr,
+			Username:     f.AuthUsername,
+			Password:     f.AuthPassword,
+			SyncInterval: f.SyncInterval,
+			SyncOnPush:   f.SyncOnPush,
+		},
+	)
+	if err != nil {
+		c.Error(err, "create push mirror")
+		return
+	}
+	go db.QueuePushMirrorSync(m.ID)
+
+	c.JSON(http.StatusCreated, convert.ToPushMirror(m))
+}
+
+func DeletePushMirror(c *context.APIContext) {
+	m := getPushMirror(c)
+	if c.Written() {
+		return
+	}
+
+	if err := db.PushMirrors.DeleteByID(c.Req.Context(), c.Repo.Repository.ID, m.ID); err != nil {
+		c.Error(err, "delete push mirror")
+		return
+	}
+	c.NoContent()
+}
+
+func SyncPushMirror(c *context.APIContext) {
+	m := getPushMirror(c)
+	if c.Written() {
+		return
+	}
+
+	go db.QueuePushMirrorSync(m.ID)
+	c.Status(http.StatusAccepted)
+}
@@ -16,6 +16,7 @@ import (
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/form"
+	"gogs.io/gogs/internal/migrate"
 	"gogs.io/gogs/internal/route/api/v1/convert"
 )
 
@@ -259,12 +260,57 @@ func Migrate(c *context.APIContext, f form.MigrateRepo) {
 		return
 	}
 
+	refspecs, err := db.ParseMirrorRefspecs(f.MirrorRefspecs)
+	if err != nil {
+		c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	// Metadata is migrated in the background, and the status of the migration is
+	// returned instead of the repository.
+	if f.Service != "" && migrate.Service(f.Service) != migrate.ServiceGit {
+		m, err := db.CreateRepoMigration(c.Req.Context(), c.User, ctxUser, db.CreateRepoMigrationOptions{
+			RepoName:         f.RepoName,
+			Service:          migrate.Service(f.Service),
+			CloneAddr:        remoteAddr,
+			AuthToken:        f.AuthToken,
+			Description:      f.Description,
+			IsPrivate:        f.Private || conf.Repository.ForcePrivate,
+			IsUnlisted:       f.Unlisted,
+			IsMirror:         f.Mirror,
+			MirrorRefspecs:   refspecs,
+			MirrorLFS:        f.MirrorLFS,
+			WithLabels:       f.Labels,
+			WithMilestones:   f.Milestones,
+			WithIssues:       f.Issues,
+			WithPullRequests: f.PullRequests,
+			WithReleases:     f.Releases,
+		})
+		if err != nil {
+			if db.IsErrInvalidCloneAddr(err) ||
+				db.IsErrReachLimitOfRepo(err) ||
+				db.IsErrRepoAlreadyExist(err) ||
+				db.IsErrNameNotAllowed(err) {
+				c.ErrorStatus(http.StatusUnprocessableEntity, err)
+			} else {
+				c.Error(err, "create migration")
+			}
+			return
+		}
+
+		log.Trace("Repository migration queued [%d]: %s/%s", m.ID, ctxUser.Name, f.RepoName)
+		c.JSON(http.StatusAccepted, convert.ToRepoMigration(m))
+		return
+	}
+
 	repo, err := db.MigrateRepository(c.User, ctxUser, db.MigrateRepoOptions{
-		Name:        f.RepoName,
-		Description: f.Description,
-		IsPrivate:   f.Private || conf.Repository.ForcePrivate,
-		IsMirror:    f.Mirror,
-		RemoteAddr:  remoteAddr,
+		Name:           f.RepoName,
+		Description:    f.Description,
+		IsPrivate:      f.Private || conf.Repository.ForcePrivate,
+		IsMirror:       f.Mirror,
+		RemoteAddr:     remoteAddr,
+		MirrorRefspecs: refspecs,
+		MirrorLFS:      f.MirrorLFS,
 	})
 	if err != nil {
 		if repo != nil {
@@ -285,6 +331,18 @@ func Migrate(c *context.APIContext, f form.MigrateRepo) {
 	c.JSON(201, repo.APIFormatLegacy(&api.Permission{Admin: true, Push: true, Pull: true}))
 }
 
+func GetMigration(c *context.APIContext) {
+	m, err := db.RepoMigrations.GetByID(c.Req.Context(), c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get migration")
+		return
+	} else if m.DoerID != c.User.ID && !c.User.IsAdmin {
+		c.NotFound()
+		return
+	}
+	c.JSONSuccess(convert.ToRepoMigration(m))
+}
+
 // FIXME: inject in the handler chain
 func parseOwnerAndRepo(c *context.APIContext) (*db.User, *db.Repository) {
 	owner, err := db.GetUserByName(c.Params(":username"))
@@ -427,7 +485,7 @@ func MirrorSync(c *context.APIContext) {
 		return
 	}
 
-	go db.MirrorQueue.Add(repo.ID)
+	go db.QueueMirrorSync(repo.ID)
 	c.Status(http.StatusAccepted)
 }
 
@@ -0,0 +1,138 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gogs/git-module"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/route/api/v1/convert"
+)
+
+// CreateCommitStatusOption is the request body to create a commit status.
+type CreateCommitStatusOption struct {
+	State       string `json:"state" binding:"Required"`
+	Context     string `json:"context" binding:"MaxSize(255)"`
+	TargetURL   string `json:"target_url"`
+	Description string `json:"description"`
+}
+
+// loadCommitStatusCreators loads creators of given commit statuses, users are
+// only loaded once regardless of how many statuses they have created.
+func loadCommitStatusCreators(c *context.APIContext, statuses []*db.CommitStatus) error {
+	creators := make(map[int64]*db.User)
+	for _, s := range statuses {
+		creator, ok := creators[s.CreatorID]
+		if !ok {
+			var err error
+			creator, err = db.Users.GetByID(c.Req.Context(), s.CreatorID)
+			if err != nil && !db.IsErrUserNotExist(err) {
+				return err
+			}
+			creators[s.CreatorID] = creator
+		}
+		s.Creator = creator
+	}
+	return nil
+}
+
+// resolveCommitSHA returns the full SHA of the commit that the given reference
+// (e.g. a branch, a tag or a commit SHA) points to.
+func resolveCommitSHA(c *context.APIContext, ref string) (string, error) {
+	gitRepo, err := git.Open(c.Repo.Repository.RepoPath())
+	if err != nil {
+		return "", err
+	}
+	commit, err := gitRepo.CatFileCommit(ref)
+	if err != nil {
+		return "", gitutil.NewError(err)
+	}
+	return commit.ID.String(), nil
+}
+
+func CreateCommitStatus(c *context.APIContext, form CreateCommitStatusOption) {
+	state := db.CommitStatusState(form.State)
+	if !state.IsValid() {
+		c.ErrorStatus(http.StatusUnprocessableEntity, fmt.Errorf("invalid state %q", form.State))
+		return
+	}
+
+	sha, err := resolveCommitSHA(c, c.Params(":sha"))
+	if err != nil {
+		if errutil.IsNotFound(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		} else {
+			c.Error(err, "resolve commit")
+		}
+		return
+	}
+
+	status, err := db.CommitStatuses.Create(c.Req.Context(), c.User, c.Repo.Repository, sha,
+		db.CreateCommitStatusOptions{
+			State:       state,
+			Context:     form.Context,
+			TargetURL:   form.TargetURL,
+			Description: form.Description,
+		},
+	)
+	if err != nil {
+		c.Error(err, "create commit status")
+		return
+	}
+	c.JSON(http.StatusCreated, convert.ToCommitStatus(status))
+}
+
+// ListCommitStatuses returns all statuses of the given commit reference in
+// reverse chronological order.
+func ListCommitStatuses(c *context.APIContext) {
+	sha, err := resolveCommitSHA(c, c.Params(":sha"))
+	if err != nil {
+		c.NotFoundOrError(err, "resolve commit")
+		return
+	}
+
+	statuses, err := db.CommitStatuses.ListBySHA(c.Req.Context(), c.Repo.Repository.ID, sha)
+	if err != nil {
+		c.Error(err, "list commit statuses")
+		return
+	} else if err = loadCommitStatusCreators(c, statuses); err != nil {
+		c.Error(err, "load creators")
+		return
+	}
+
+	apiStatuses := make([]*convert.CommitStatus, len(statuses))
+	for i := range statuses {
+		apiStatuses[i] = convert.ToCommitStatus(statuses[i])
+	}
+	c.JSONSuccess(apiStatuses)
+}
+
+// GetCombinedCommitStatus returns the combined status of the latest status of
+// each context for the given commit reference.
+func GetCombinedCommitStatus(c *context.APIContext) {
+	sha, err := resolveCommitSHA(c, c.Params(":sha"))
+	if err != nil {
+		c.NotFoundOrError(err, "resolve commit")
+		return
+	}
+
+	latest, err := db.CommitStatuses.ListLatestBySHAs(c.Req.Context(), c.Repo.Repository.ID, sha)
+	if err != nil {
+		c.Error(err, "list latest commit statuses")
+		return
+	}
+	statuses := latest[sha]
+	if err = loadCommitStatusCreators(c, statuses); err != nil {
+		c.Error(err, "load creators")
+		return
+	}
+	c.JSONSuccess(convert.ToCombinedStatus(sha, statuses))
+}
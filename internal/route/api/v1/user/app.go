@@ -6,13 +6,28 @@ package user
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
-	api "github.com/gogs/go-gogs-client"
+	"github.com/pkg/errors"
 
+	"gogs.io/gogs/internal/auth"
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/route/api/v1/convert"
 )
 
+// CreateAccessTokenOption is the request body to create an access token.
+type CreateAccessTokenOption struct {
+	Name string `json:"name" binding:"Required"`
+	// The list of scopes granted to the token. All scopes are granted when
+	// omitted, for the sake of backward compatibility.
+	Scopes []string `json:"scopes"`
+	// The time in the format of RFC 3339 when the token expires, the token never
+	// expires when empty.
+	ExpiresAt string `json:"expires_at"`
+}
+
 func ListAccessTokens(c *context.APIContext) {
 	tokens, err := db.AccessTokens.List(c.Req.Context(), c.User.ID)
 	if err != nil {
@@ -20,15 +35,43 @@ func ListAccessTokens(c *context.APIContext) {
 		return
 	}
 
-	apiTokens := make([]*api.AccessToken, len(tokens))
+	apiTokens := make([]*convert.AccessToken, len(tokens))
 	for i := range tokens {
-		apiTokens[i] = &api.AccessToken{Name: tokens[i].Name, Sha1: tokens[i].Sha1}
+		apiTokens[i] = convert.ToAccessToken(tokens[i])
 	}
 	c.JSONSuccess(&apiTokens)
 }
 
-func CreateAccessToken(c *context.APIContext, form api.CreateAccessTokenOption) {
-	t, err := db.AccessTokens.Create(c.Req.Context(), c.User.ID, form.Name)
+func CreateAccessToken(c *context.APIContext, form CreateAccessTokenOption) {
+	scopes := auth.AllScopes
+	if form.Scopes != nil {
+		var err error
+		scopes, err = auth.ParseScopes(strings.Join(form.Scopes, " "))
+		if err != nil {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+			return
+		}
+	}
+
+	var expiresAt time.Time
+	if form.ExpiresAt != "" {
+		var err error
+		expiresAt, err = time.Parse(time.RFC3339, form.ExpiresAt)
+		if err != nil {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.New(`"expires_at" is not a valid time in the format of RFC 3339`))
+			return
+		} else if !expiresAt.After(time.Now()) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.New(`"expires_at" must be in the future`))
+			return
+		}
+	}
+
+	t, err := db.AccessTokens.Create(c.Req.Context(), c.User.ID, form.Name,
+		db.CreateAccessTokenOptions{
+			Scopes:    scopes,
+			ExpiresAt: expiresAt,
+		},
+	)
 	if err != nil {
 		if db.IsErrAccessTokenAlreadyExist(err) {
 			c.ErrorStatus(http.StatusUnprocessableEntity, err)
@@ -37,5 +80,5 @@ func CreateAccessToken(c *context.APIContext, form api.CreateAccessTokenOption)
 		}
 		return
 	}
-	c.JSON(http.StatusCreated, &api.AccessToken{Name: t.Name, Sha1: t.Sha1})
+	c.JSON(http.StatusCreated, convert.ToAccessToken(t))
 }
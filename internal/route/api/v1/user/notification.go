@@ -0,0 +1,188 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/route/api/v1/convert"
+)
+
+// notificationPollInterval is the number of seconds clients are asked to wait
+// between two polls of notifications.
+const notificationPollInterval = "60"
+
+// MarkNotificationsReadOption is the request body to mark notifications as
+// read.
+type MarkNotificationsReadOption struct {
+	// Notifications updated after the time are not marked, default to now.
+	LastReadAt string `json:"last_read_at" form:"last_read_at"`
+}
+
+// parseNotificationTime parses the time in the query or request body in the
+// format of RFC 3339, it returns zero time for an empty string.
+func parseNotificationTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("%q is not a valid time in the format of RFC 3339", name)
+	}
+	return t, nil
+}
+
+func listNotifications(c *context.APIContext, repoID int64) {
+	since, err := parseNotificationTime("since", c.Query("since"))
+	if err != nil {
+		c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		return
+	}
+	before, err := parseNotificationTime("before", c.Query("before"))
+	if err != nil {
+		c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	c.Header().Set("X-Poll-Interval", notificationPollInterval)
+
+	// Clients poll with the "If-Modified-Since" header, respond with 304 when
+	// nothing has happened since the last poll.
+	latest, err := db.Notifications.List(c.Req.Context(), c.User.ID,
+		db.ListNotificationsOptions{
+			RepoID:   repoID,
+			All:      true,
+			PageSize: 1,
+		},
+	)
+	if err != nil {
+		c.Error(err, "get latest notification")
+		return
+	}
+	if len(latest) > 0 {
+		lastModified := latest[0].UpdatedAt.UTC().Truncate(time.Second)
+		c.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+
+		ifModifiedSince, err := http.ParseTime(c.Req.Header.Get("If-Modified-Since"))
+		if err == nil && !lastModified.After(ifModifiedSince) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	opts := db.ListNotificationsOptions{
+		RepoID:   repoID,
+		All:      c.QueryBool("all"),
+		Since:    since,
+		Before:   before,
+		Page:     c.QueryInt("page"),
+		PageSize: convert.ToCorrectPageSize(c.QueryInt("per_page")),
+	}
+	total, err := db.Notifications.Count(c.Req.Context(), c.User.ID, opts)
+	if err != nil {
+		c.Error(err, "count notifications")
+		return
+	}
+	notifications, err := db.Notifications.List(c.Req.Context(), c.User.ID, opts)
+	if err != nil {
+		c.Error(err, "list notifications")
+		return
+	}
+	threads, err := db.LoadNotificationThreads(c.User.ID, notifications)
+	if err != nil {
+		c.Error(err, "load notification threads")
+		return
+	}
+
+	apiThreads := make([]*convert.NotificationThread, len(threads))
+	for i := range threads {
+		apiThreads[i] = convert.ToNotificationThread(c.BaseURL, threads[i])
+	}
+	c.SetLinkHeader(int(total), opts.PageSize)
+	c.JSONSuccess(&apiThreads)
+}
+
+func markNotificationsRead(c *context.APIContext, repoID int64, form MarkNotificationsReadOption) {
+	lastReadAt, err := parseNotificationTime("last_read_at", form.LastReadAt)
+	if err != nil {
+		c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		return
+	} else if lastReadAt.IsZero() {
+		lastReadAt = time.Now()
+	}
+
+	err = db.Notifications.MarkAllRead(c.Req.Context(), c.User.ID, repoID, lastReadAt)
+	if err != nil {
+		c.Error(err, "mark all notifications as read")
+		return
+	}
+	c.Status(http.StatusResetContent)
+}
+
+// ListNotifications lists notifications of the authenticated user.
+func ListNotifications(c *context.APIContext) {
+	listNotifications(c, 0)
+}
+
+// MarkNotificationsRead marks notifications of the authenticated user as read.
+func MarkNotificationsRead(c *context.APIContext, form MarkNotificationsReadOption) {
+	markNotificationsRead(c, 0, form)
+}
+
+// ListRepoNotifications lists notifications of the authenticated user in the
+// repository.
+func ListRepoNotifications(c *context.APIContext) {
+	listNotifications(c, c.Repo.Repository.ID)
+}
+
+// MarkRepoNotificationsRead marks notifications of the authenticated user in
+// the repository as read.
+func MarkRepoNotificationsRead(c *context.APIContext, form MarkNotificationsReadOption) {
+	markNotificationsRead(c, c.Repo.Repository.ID, form)
+}
+
+func getNotificationThread(c *context.APIContext) *db.NotificationThread {
+	n, err := db.Notifications.GetByID(c.Req.Context(), c.User.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get notification by ID")
+		return nil
+	}
+
+	threads, err := db.LoadNotificationThreads(c.User.ID, []*db.Notification{n})
+	if err != nil {
+		c.Error(err, "load notification threads")
+		return nil
+	} else if len(threads) == 0 {
+		c.NotFound()
+		return nil
+	}
+	return threads[0]
+}
+
+func GetNotificationThread(c *context.APIContext) {
+	thread := getNotificationThread(c)
+	if c.Written() {
+		return
+	}
+	c.JSONSuccess(convert.ToNotificationThread(c.BaseURL, thread))
+}
+
+func MarkNotificationThreadRead(c *context.APIContext) {
+	thread := getNotificationThread(c)
+	if c.Written() {
+		return
+	}
+
+	if err := db.Notifications.MarkRead(c.Req.Context(), c.User.ID, thread.ID); err != nil {
+		c.Error(err, "mark notification as read")
+		return
+	}
+	c.Status(http.StatusResetContent)
+}
@@ -23,6 +23,7 @@ const (
 	EXPLORE_REPOS         = "explore/repos"
 	EXPLORE_USERS         = "explore/users"
 	EXPLORE_ORGANIZATIONS = "explore/organizations"
+	EXPLORE_CODE          = "explore/code"
 )
 
 func Home(c *context.Context) {
@@ -162,6 +163,40 @@ func ExploreOrganizations(c *context.Context) {
 	})
 }
 
+func ExploreCode(c *context.Context) {
+	c.Data["Title"] = c.Tr("explore")
+	c.Data["PageIsExplore"] = true
+	c.Data["PageIsExploreCode"] = true
+
+	page := c.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	keyword := c.Query("q")
+	c.Data["Keyword"] = keyword
+	if keyword == "" {
+		c.Success(EXPLORE_CODE)
+		return
+	}
+
+	results, count, err := db.SearchCode(&db.SearchCodeOptions{
+		Keyword:  keyword,
+		UserID:   c.UserID(),
+		Page:     page,
+		PageSize: conf.UI.ExplorePagingNum,
+	})
+	if err != nil {
+		c.Error(err, "search code")
+		return
+	}
+	c.Data["Total"] = count
+	c.Data["Page"] = paginater.New(int(count), conf.UI.ExplorePagingNum, page, 5)
+	c.Data["Results"] = results
+
+	c.Success(EXPLORE_CODE)
+}
+
 func NotFound(c *macaron.Context, l i18n.Locale) {
 	c.Data["Title"] = l.Tr("status.page_not_found")
 	c.HTML(http.StatusNotFound, fmt.Sprintf("status/%d", http.StatusNotFound))
@@ -84,6 +84,9 @@ func GlobalInit(customConf string) error {
 		db.InitSyncMirrors()
 		db.InitDeliverHooks()
 		db.InitTestPullRequests()
+		db.InitRepoMigrations()
+		db.InitIssueIndexer()
+		db.InitCodeIndexer()
 	}
 	if conf.HasMinWinSvc {
 		log.Info("Builtin Windows Service is supported")
@@ -92,10 +92,36 @@ func (h *basicHandler) serveUpload(c *macaron.Context, repo *db.Repository, oid
 		return
 	}
 
+	remaining, message, err := db.RemainingLFSQuota(c.Req.Context(), repo)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to get remaining quota [repo_id: %d]: %v", repo.ID, err)
+		return
+	}
+
+	body := c.Req.Request.Body
+	var quota *quotaReader
+	if remaining >= 0 {
+		if c.Req.Request.ContentLength > remaining {
+			responseJSON(c.Resp, http.StatusInsufficientStorage, responseError{
+				Message: message,
+			})
+			return
+		}
+
+		// The content length may be absent or not match the actual body.
+		quota = &quotaReader{ReadCloser: body, remaining: remaining}
+		body = quota
+	}
+
 	s := h.DefaultStorager()
-	written, err := s.Upload(oid, c.Req.Request.Body)
+	written, err := s.Upload(oid, body)
 	if err != nil {
-		if err == lfsutil.ErrInvalidOID {
+		if quota != nil && quota.exceeded {
+			responseJSON(c.Resp, http.StatusInsufficientStorage, responseError{
+				Message: message,
+			})
+		} else if err == lfsutil.ErrInvalidOID {
 			responseJSON(c.Resp, http.StatusBadRequest, responseError{
 				Message: err.Error(),
 			})
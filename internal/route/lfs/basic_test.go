@@ -41,6 +41,11 @@ func (s *mockStorage) Download(_ lfsutil.OID, w io.Writer) error {
 	return err
 }
 
+func (s *mockStorage) Delete(lfsutil.OID) error {
+	s.buf.Reset()
+	return nil
+}
+
 func Test_basicHandler_serveDownload(t *testing.T) {
 	s := &mockStorage{}
 	basic := &basicHandler{
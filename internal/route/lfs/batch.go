@@ -7,6 +7,7 @@ package lfs
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 	"gopkg.in/macaron.v1"
@@ -18,8 +19,78 @@ import (
 	"gogs.io/gogs/internal/strutil"
 )
 
+type batchHandler struct {
+	// The list of available storage backends to access objects.
+	storagers map[lfsutil.Storage]lfsutil.Storager
+}
+
+// downloadAction returns the download action of the object. Clients are
+// redirected to the storage backend directly when it supports presigned URLs,
+// and falls back to the basic transfer endpoint otherwise.
+func (h *batchHandler) downloadAction(baseHref string, object *db.LFSObject) *batchAction {
+	if p, ok := h.storagers[object.Storage].(lfsutil.Presigner); ok {
+		href, expiresIn, err := p.PresignDownloadURL(object.OID)
+		if err != nil {
+			log.Error("Failed to presign download URL [storage: %s, oid: %s]: %v", object.Storage, object.OID, err)
+		} else if href != "" {
+			return &batchAction{
+				Href:      href,
+				ExpiresIn: int64(expiresIn / time.Second),
+			}
+		}
+	}
+	return &batchAction{
+		Href: fmt.Sprintf("%s/%s", baseHref, object.OID),
+	}
+}
+
+// checkBatchQuota checks whether uploading new objects in the request would
+// exceed the LFS quotas, and writes the error response if so. Objects that are
+// already stored in the repository are not counted.
+func checkBatchQuota(c *macaron.Context, repo *db.Repository, objects []batchRequestObject) bool {
+	if conf.LFS.RepoQuota <= 0 && conf.LFS.OwnerQuota <= 0 {
+		return true
+	}
+
+	oids := make([]lfsutil.OID, 0, len(objects))
+	for _, obj := range objects {
+		oids = append(oids, obj.Oid)
+	}
+	stored, err := db.LFS.GetObjectsByOIDs(c.Req.Context(), repo.ID, oids...)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to get objects [repo_id: %d, oids: %v]: %v", repo.ID, oids, err)
+		return false
+	}
+	storedSet := make(map[lfsutil.OID]bool, len(stored))
+	for _, obj := range stored {
+		storedSet[obj.OID] = true
+	}
+
+	var size int64
+	for _, obj := range objects {
+		if !storedSet[obj.Oid] {
+			size += obj.Size
+			storedSet[obj.Oid] = true
+		}
+	}
+
+	message, err := checkQuota(c.Req.Context(), repo, size)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to check quota [repo_id: %d]: %v", repo.ID, err)
+		return false
+	} else if message != "" {
+		responseJSON(c.Resp, http.StatusInsufficientStorage, responseError{
+			Message: message,
+		})
+		return false
+	}
+	return true
+}
+
 // POST /{owner}/{repo}.git/info/lfs/object/batch
-func serveBatch(c *macaron.Context, owner *db.User, repo *db.Repository) {
+func (h *batchHandler) serveBatch(c *macaron.Context, owner *db.User, repo *db.Repository) {
 	var request batchRequest
 	defer c.Req.Request.Body.Close()
 	err := jsoniter.NewDecoder(c.Req.Request.Body).Decode(&request)
@@ -38,6 +109,10 @@ func serveBatch(c *macaron.Context, owner *db.User, repo *db.Repository) {
 	objects := make([]batchObject, 0, len(request.Objects))
 	switch request.Operation {
 	case basicOperationUpload:
+		if !checkBatchQuota(c, repo, request.Objects) {
+			return
+		}
+
 		for _, obj := range request.Objects {
 			var actions batchActions
 			if lfsutil.ValidOID(obj.Oid) {
@@ -95,9 +170,7 @@ func serveBatch(c *macaron.Context, owner *db.User, repo *db.Repository) {
 						Message: "Object size mismatch",
 					}
 				} else {
-					actions.Download = &batchAction{
-						Href: fmt.Sprintf("%s/%s", baseHref, obj.Oid),
-					}
+					actions.Download = h.downloadAction(baseHref, stored)
 				}
 			} else {
 				actions.Error = &batchError{
@@ -127,12 +200,14 @@ func serveBatch(c *macaron.Context, owner *db.User, repo *db.Repository) {
 }
 
 // batchRequest defines the request payload for the batch endpoint.
+type batchRequestObject struct {
+	Oid  lfsutil.OID `json:"oid"`
+	Size int64       `json:"size"`
+}
+
 type batchRequest struct {
-	Operation string `json:"operation"`
-	Objects   []struct {
-		Oid  lfsutil.OID `json:"oid"`
-		Size int64       `json:"size"`
-	} `json:"objects"`
+	Operation string               `json:"operation"`
+	Objects   []batchRequestObject `json:"objects"`
 }
 
 type batchError struct {
@@ -143,6 +218,8 @@ type batchError struct {
 type batchAction struct {
 	Href   string            `json:"href"`
 	Header map[string]string `json:"header,omitempty"`
+	// The number of seconds that the action expires in.
+	ExpiresIn int64 `json:"expires_in,omitempty"`
 }
 
 type batchActions struct {
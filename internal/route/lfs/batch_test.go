@@ -11,14 +11,28 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/macaron.v1"
 
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/lfsutil"
 )
 
+var _ lfsutil.Presigner = (*mockPresignStorage)(nil)
+
+// mockPresignStorage is a in-memory storage for LFS objects that presigns
+// download URLs.
+type mockPresignStorage struct {
+	mockStorage
+}
+
+func (*mockPresignStorage) PresignDownloadURL(oid lfsutil.OID) (string, time.Duration, error) {
+	return "https://s3.example.com/lfs/" + string(oid) + "?signature=1", time.Hour, nil
+}
+
 func Test_serveBatch(t *testing.T) {
 	conf.SetMockServer(t, conf.ServerOpts{
 		ExternalURL: "https://gogs.example.com/",
@@ -29,7 +43,12 @@ func Test_serveBatch(t *testing.T) {
 		c.Map(&db.User{Name: "owner"})
 		c.Map(&db.Repository{Name: "repo"})
 	})
-	m.Post("/", serveBatch)
+	h := &batchHandler{
+		storagers: map[lfsutil.Storage]lfsutil.Storager{
+			"presign": &mockPresignStorage{},
+		},
+	}
+	m.Post("/", h.serveBatch)
 
 	tests := []struct {
 		name          string
@@ -118,6 +137,44 @@ func Test_serveBatch(t *testing.T) {
 			}
 		}
 	]
+}` + "\n",
+		},
+		{
+			name: "download: presigned URL",
+			body: `{
+"operation": "download",
+"objects": [
+	{"oid": "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f", "size": 123}
+]}`,
+			mockLFSStore: func() db.LFSStore {
+				mock := NewMockLFSStore()
+				mock.GetObjectsByOIDsFunc.SetDefaultReturn(
+					[]*db.LFSObject{
+						{
+							OID:     "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f",
+							Size:    123,
+							Storage: "presign",
+						},
+					},
+					nil,
+				)
+				return mock
+			},
+			expStatusCode: http.StatusOK,
+			expBody: `{
+	"transfer": "basic",
+	"objects": [
+		{
+			"oid": "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f",
+			"size": 123,
+			"actions": {
+				"download": {
+					"href": "https://s3.example.com/lfs/ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f?signature=1",
+					"expires_in": 3600
+				}
+			}
+		}
+	]
 }` + "\n",
 		},
 	}
@@ -0,0 +1,336 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/strutil"
+)
+
+// maxLocksLimit is the maximum number of locks to return in a single request.
+const maxLocksLimit = 100
+
+// GET /{owner}/{repo}.git/info/lfs/locks
+func serveListLocks(c *macaron.Context, repo *db.Repository) {
+	opts := db.ListLFSLocksOptions{
+		Path: c.Query("path"),
+	}
+	if id := c.Query("id"); id != "" {
+		lockID, _ := strconv.ParseInt(id, 10, 64)
+		lock, err := db.LFS.GetLockByID(c.Req.Context(), repo.ID, lockID)
+		if err != nil {
+			if db.IsErrLFSLockNotExist(err) {
+				responseJSON(c.Resp, http.StatusOK, listLocksResponse{Locks: []*lockObject{}})
+			} else {
+				internalServerError(c.Resp)
+				log.Error("Failed to get lock [repo_id: %d, id: %d]: %v", repo.ID, lockID, err)
+			}
+			return
+		}
+
+		locks, err := toLockObjects(c.Req.Context(), lock)
+		if err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to convert locks: %v", err)
+			return
+		}
+		responseJSON(c.Resp, http.StatusOK, listLocksResponse{Locks: locks})
+		return
+	}
+
+	locks, nextCursor, err := listLocks(c.Req.Context(), repo.ID, opts, c.Query("cursor"), c.QueryInt("limit"))
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to list locks [repo_id: %d]: %v", repo.ID, err)
+		return
+	}
+	responseJSON(c.Resp, http.StatusOK, listLocksResponse{
+		Locks:      locks,
+		NextCursor: nextCursor,
+	})
+}
+
+// listLocks returns a page of locks with given options, and the cursor of the
+// next page if there is any.
+func listLocks(ctx context.Context, repoID int64, opts db.ListLFSLocksOptions, cursor string, limit int) ([]*lockObject, string, error) {
+	if limit <= 0 || limit > maxLocksLimit {
+		limit = maxLocksLimit
+	}
+	opts.Cursor, _ = strconv.ParseInt(cursor, 10, 64)
+	// Fetch one more lock to know whether there is a next page.
+	opts.Limit = limit + 1
+
+	locks, err := db.LFS.ListLocks(ctx, repoID, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(locks) > limit {
+		nextCursor = strconv.FormatInt(locks[limit].ID, 10)
+		locks = locks[:limit]
+	}
+
+	objects, err := toLockObjects(ctx, locks...)
+	if err != nil {
+		return nil, "", err
+	}
+	return objects, nextCursor, nil
+}
+
+// maxLockPathLength is the maximum length of a locked path, which is limited by
+// the size of the database column.
+const maxLockPathLength = 255
+
+// POST /{owner}/{repo}.git/info/lfs/locks
+func serveCreateLock(c *macaron.Context, actor *authenticatedUser, repo *db.Repository) {
+	var request createLockRequest
+	defer c.Req.Request.Body.Close()
+	err := jsoniter.NewDecoder(c.Req.Request.Body).Decode(&request)
+	if err != nil {
+		responseJSON(c.Resp, http.StatusBadRequest, responseError{
+			Message: strutil.ToUpperFirst(err.Error()),
+		})
+		return
+	} else if request.Path == "" {
+		responseJSON(c.Resp, http.StatusUnprocessableEntity, responseError{
+			Message: "Path is required",
+		})
+		return
+	} else if len(request.Path) > maxLockPathLength {
+		responseJSON(c.Resp, http.StatusUnprocessableEntity, responseError{
+			Message: fmt.Sprintf("Path must be at most %d characters", maxLockPathLength),
+		})
+		return
+	}
+
+	lock, err := db.LFS.CreateLock(c.Req.Context(), repo.ID, actor.ID, request.Path)
+	if err != nil {
+		if !db.IsErrLFSLockAlreadyExist(err) {
+			internalServerError(c.Resp)
+			log.Error("Failed to create lock [repo_id: %d, path: %s]: %v", repo.ID, request.Path, err)
+			return
+		}
+
+		existing, err := db.LFS.GetLockByPath(c.Req.Context(), repo.ID, request.Path)
+		if err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to get lock [repo_id: %d, path: %s]: %v", repo.ID, request.Path, err)
+			return
+		}
+		locks, err := toLockObjects(c.Req.Context(), existing)
+		if err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to convert locks: %v", err)
+			return
+		}
+		responseJSON(c.Resp, http.StatusConflict, lockResponse{
+			Lock:    locks[0],
+			Message: "Already created lock",
+		})
+		return
+	}
+
+	responseJSON(c.Resp, http.StatusCreated, lockResponse{
+		Lock: newLockObject(lock, actor.User),
+	})
+}
+
+// POST /{owner}/{repo}.git/info/lfs/locks/verify
+func serveVerifyLocks(c *macaron.Context, actor *authenticatedUser, repo *db.Repository) {
+	var request verifyLocksRequest
+	defer c.Req.Request.Body.Close()
+	err := jsoniter.NewDecoder(c.Req.Request.Body).Decode(&request)
+	if err != nil {
+		responseJSON(c.Resp, http.StatusBadRequest, responseError{
+			Message: strutil.ToUpperFirst(err.Error()),
+		})
+		return
+	}
+
+	locks, nextCursor, err := listLocks(c.Req.Context(), repo.ID, db.ListLFSLocksOptions{}, request.Cursor, request.Limit)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to list locks [repo_id: %d]: %v", repo.ID, err)
+		return
+	}
+
+	resp := verifyLocksResponse{
+		Ours:       []*lockObject{},
+		Theirs:     []*lockObject{},
+		NextCursor: nextCursor,
+	}
+	for _, lock := range locks {
+		if lock.ownerID == actor.ID {
+			resp.Ours = append(resp.Ours, lock)
+		} else {
+			resp.Theirs = append(resp.Theirs, lock)
+		}
+	}
+	responseJSON(c.Resp, http.StatusOK, resp)
+}
+
+// POST /{owner}/{repo}.git/info/lfs/locks/{id}/unlock
+func serveUnlock(c *macaron.Context, actor *authenticatedUser, repo *db.Repository) {
+	var request unlockRequest
+	defer c.Req.Request.Body.Close()
+	err := jsoniter.NewDecoder(c.Req.Request.Body).Decode(&request)
+	if err != nil {
+		responseJSON(c.Resp, http.StatusBadRequest, responseError{
+			Message: strutil.ToUpperFirst(err.Error()),
+		})
+		return
+	}
+
+	lockID := c.ParamsInt64(":id")
+	lock, err := db.LFS.GetLockByID(c.Req.Context(), repo.ID, lockID)
+	if err != nil {
+		if db.IsErrLFSLockNotExist(err) {
+			responseJSON(c.Resp, http.StatusNotFound, responseError{
+				Message: "Lock does not exist",
+			})
+		} else {
+			internalServerError(c.Resp)
+			log.Error("Failed to get lock [repo_id: %d, id: %d]: %v", repo.ID, lockID, err)
+		}
+		return
+	}
+
+	if lock.OwnerID != actor.ID {
+		// Only repository admins are allowed to remove locks of other users.
+		canForce := actor.IsAdmin || db.Perms.Authorize(c.Req.Context(), actor.ID, repo.ID, db.AccessModeAdmin,
+			db.AccessModeOptions{
+				OwnerID: repo.OwnerID,
+				Private: repo.IsPrivate,
+			},
+		)
+		if !request.Force || !canForce {
+			responseJSON(c.Resp, http.StatusForbidden, responseError{
+				Message: "Lock is owned by another user",
+			})
+			return
+		}
+	}
+
+	locks, err := toLockObjects(c.Req.Context(), lock)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to convert locks: %v", err)
+		return
+	}
+
+	err = db.LFS.DeleteLockByID(c.Req.Context(), repo.ID, lock.ID)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to delete lock [repo_id: %d, id: %d]: %v", repo.ID, lock.ID, err)
+		return
+	}
+
+	responseJSON(c.Resp, http.StatusOK, lockResponse{
+		Lock: locks[0],
+	})
+}
+
+// toLockObjects converts locks to their response objects with owners loaded.
+func toLockObjects(ctx context.Context, locks ...*db.LFSLock) ([]*lockObject, error) {
+	owners := make(map[int64]*db.User)
+	objects := make([]*lockObject, 0, len(locks))
+	for _, lock := range locks {
+		owner, ok := owners[lock.OwnerID]
+		if !ok {
+			var err error
+			owner, err = db.Users.GetByID(ctx, lock.OwnerID)
+			if err != nil && !db.IsErrUserNotExist(err) {
+				return nil, err
+			}
+			owners[lock.OwnerID] = owner
+		}
+		objects = append(objects, newLockObject(lock, owner))
+	}
+	return objects, nil
+}
+
+func newLockObject(lock *db.LFSLock, owner *db.User) *lockObject {
+	object := &lockObject{
+		ID:       strconv.FormatInt(lock.ID, 10),
+		Path:     lock.Path,
+		LockedAt: lock.CreatedAt,
+		ownerID:  lock.OwnerID,
+	}
+	if owner != nil {
+		object.Owner = &lockOwner{
+			Name: owner.Name,
+		}
+	}
+	return object
+}
+
+type lockRef struct {
+	Name string `json:"name"`
+}
+
+type lockOwner struct {
+	Name string `json:"name"`
+}
+
+type lockObject struct {
+	ID       string     `json:"id"`
+	Path     string     `json:"path"`
+	LockedAt time.Time  `json:"locked_at"`
+	Owner    *lockOwner `json:"owner,omitempty"`
+
+	ownerID int64
+}
+
+// createLockRequest defines the request payload for the create lock endpoint.
+type createLockRequest struct {
+	Path string   `json:"path"`
+	Ref  *lockRef `json:"ref,omitempty"`
+}
+
+// lockResponse defines the response payload for the create lock and unlock
+// endpoints.
+type lockResponse struct {
+	Lock    *lockObject `json:"lock"`
+	Message string      `json:"message,omitempty"`
+}
+
+// listLocksResponse defines the response payload for the list locks endpoint.
+type listLocksResponse struct {
+	Locks      []*lockObject `json:"locks"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// verifyLocksRequest defines the request payload for the verify locks
+// endpoint.
+type verifyLocksRequest struct {
+	Cursor string   `json:"cursor,omitempty"`
+	Limit  int      `json:"limit,omitempty"`
+	Ref    *lockRef `json:"ref,omitempty"`
+}
+
+// verifyLocksResponse defines the response payload for the verify locks
+// endpoint.
+type verifyLocksResponse struct {
+	Ours       []*lockObject `json:"ours"`
+	Theirs     []*lockObject `json:"theirs"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// unlockRequest defines the request payload for the unlock endpoint.
+type unlockRequest struct {
+	Force bool     `json:"force,omitempty"`
+	Ref   *lockRef `json:"ref,omitempty"`
+}
@@ -0,0 +1,294 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/macaron.v1"
+
+	"gogs.io/gogs/internal/db"
+)
+
+func Test_serveCreateLock(t *testing.T) {
+	mockUsersStore := NewMockUsersStore()
+	mockUsersStore.GetByIDFunc.SetDefaultHook(func(_ context.Context, id int64) (*db.User, error) {
+		return &db.User{ID: id, Name: map[int64]string{1: "alice", 2: "bob"}[id]}, nil
+	})
+	db.SetMockUsersStore(t, mockUsersStore)
+
+	m := macaron.New()
+	m.Use(func(c *macaron.Context) {
+		c.Map(&authenticatedUser{User: &db.User{ID: 1, Name: "alice"}})
+		c.Map(&db.Repository{ID: 1, Name: "repo"})
+	})
+	m.Post("/", serveCreateLock)
+
+	lockedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name          string
+		body          string
+		mockLFSStore  func() db.LFSStore
+		expStatusCode int
+		expBody       string
+	}{
+		{
+			name:          "empty path",
+			body:          `{"path": ""}`,
+			expStatusCode: http.StatusUnprocessableEntity,
+			expBody:       `{"message": "Path is required"}`,
+		},
+		{
+			name: "already locked",
+			body: `{"path": "assets/logo.psd"}`,
+			mockLFSStore: func() db.LFSStore {
+				mock := NewMockLFSStore()
+				mock.CreateLockFunc.SetDefaultReturn(nil, db.ErrLFSLockAlreadyExist{})
+				mock.GetLockByPathFunc.SetDefaultReturn(&db.LFSLock{ID: 2, RepoID: 1, OwnerID: 2, Path: "assets/logo.psd", CreatedAt: lockedAt}, nil)
+				return mock
+			},
+			expStatusCode: http.StatusConflict,
+			expBody: `{
+	"lock": {"id": "2", "path": "assets/logo.psd", "locked_at": "2026-01-02T03:04:05Z", "owner": {"name": "bob"}},
+	"message": "Already created lock"
+}`,
+		},
+		{
+			name: "created",
+			body: `{"path": "assets/logo.psd", "ref": {"name": "refs/heads/main"}}`,
+			mockLFSStore: func() db.LFSStore {
+				mock := NewMockLFSStore()
+				mock.CreateLockFunc.SetDefaultReturn(&db.LFSLock{ID: 1, RepoID: 1, OwnerID: 1, Path: "assets/logo.psd", CreatedAt: lockedAt}, nil)
+				return mock
+			},
+			expStatusCode: http.StatusCreated,
+			expBody: `{
+	"lock": {"id": "1", "path": "assets/logo.psd", "locked_at": "2026-01-02T03:04:05Z", "owner": {"name": "alice"}}
+}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.mockLFSStore != nil {
+				db.SetMockLFSStore(t, test.mockLFSStore())
+			}
+
+			r, err := http.NewRequest("POST", "/", bytes.NewBufferString(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			m.ServeHTTP(rr, r)
+
+			resp := rr.Result()
+			assert.Equal(t, test.expStatusCode, resp.StatusCode)
+			assertJSONBody(t, test.expBody, resp)
+		})
+	}
+}
+
+func Test_serveVerifyLocks(t *testing.T) {
+	mockUsersStore := NewMockUsersStore()
+	mockUsersStore.GetByIDFunc.SetDefaultHook(func(_ context.Context, id int64) (*db.User, error) {
+		return &db.User{ID: id, Name: map[int64]string{1: "alice", 2: "bob"}[id]}, nil
+	})
+	db.SetMockUsersStore(t, mockUsersStore)
+
+	lockedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockLFSStore := NewMockLFSStore()
+	mockLFSStore.ListLocksFunc.SetDefaultReturn(
+		[]*db.LFSLock{
+			{ID: 1, RepoID: 1, OwnerID: 1, Path: "assets/logo.psd", CreatedAt: lockedAt},
+			{ID: 2, RepoID: 1, OwnerID: 2, Path: "assets/banner.psd", CreatedAt: lockedAt},
+			{ID: 3, RepoID: 1, OwnerID: 1, Path: "assets/icon.psd", CreatedAt: lockedAt},
+		},
+		nil,
+	)
+	db.SetMockLFSStore(t, mockLFSStore)
+
+	m := macaron.New()
+	m.Use(func(c *macaron.Context) {
+		c.Map(&authenticatedUser{User: &db.User{ID: 1, Name: "alice"}})
+		c.Map(&db.Repository{ID: 1, Name: "repo"})
+	})
+	m.Post("/", serveVerifyLocks)
+
+	r, err := http.NewRequest("POST", "/", bytes.NewBufferString(`{"limit": 2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, r)
+
+	resp := rr.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assertJSONBody(t, `{
+	"ours": [{"id": "1", "path": "assets/logo.psd", "locked_at": "2026-01-02T03:04:05Z", "owner": {"name": "alice"}}],
+	"theirs": [{"id": "2", "path": "assets/banner.psd", "locked_at": "2026-01-02T03:04:05Z", "owner": {"name": "bob"}}],
+	"next_cursor": "3"
+}`, resp)
+
+	// One more lock is requested to know whether there is a next page.
+	assert.Equal(t, 3, mockLFSStore.ListLocksFunc.History()[0].Arg2.Limit)
+}
+
+func Test_serveUnlock(t *testing.T) {
+	mockUsersStore := NewMockUsersStore()
+	mockUsersStore.GetByIDFunc.SetDefaultHook(func(_ context.Context, id int64) (*db.User, error) {
+		return &db.User{ID: id, Name: map[int64]string{1: "alice", 2: "bob"}[id]}, nil
+	})
+	db.SetMockUsersStore(t, mockUsersStore)
+
+	lockedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name           string
+		actor          *db.User
+		body           string
+		mockLFSStore   func() db.LFSStore
+		mockPermsStore func() db.PermsStore
+		expStatusCode  int
+		expBody        string
+	}{
+		{
+			name:  "lock does not exist",
+			actor: &db.User{ID: 1, Name: "alice"},
+			body:  `{}`,
+			mockLFSStore: func() db.LFSStore {
+				mock := NewMockLFSStore()
+				mock.GetLockByIDFunc.SetDefaultReturn(nil, db.ErrLFSLockNotExist{})
+				return mock
+			},
+			expStatusCode: http.StatusNotFound,
+			expBody:       `{"message": "Lock does not exist"}`,
+		},
+		{
+			name:  "owned by another user without force",
+			actor: &db.User{ID: 1, Name: "alice"},
+			body:  `{}`,
+			mockLFSStore: func() db.LFSStore {
+				mock := NewMockLFSStore()
+				mock.GetLockByIDFunc.SetDefaultReturn(&db.LFSLock{ID: 1, RepoID: 1, OwnerID: 2, Path: "assets/logo.psd", CreatedAt: lockedAt}, nil)
+				return mock
+			},
+			mockPermsStore: func() db.PermsStore {
+				mock := NewMockPermsStore()
+				mock.AuthorizeFunc.SetDefaultReturn(true)
+				return mock
+			},
+			expStatusCode: http.StatusForbidden,
+			expBody:       `{"message": "Lock is owned by another user"}`,
+		},
+		{
+			name:  "force by non-admin",
+			actor: &db.User{ID: 1, Name: "alice"},
+			body:  `{"force": true}`,
+			mockLFSStore: func() db.LFSStore {
+				mock := NewMockLFSStore()
+				mock.GetLockByIDFunc.SetDefaultReturn(&db.LFSLock{ID: 1, RepoID: 1, OwnerID: 2, Path: "assets/logo.psd", CreatedAt: lockedAt}, nil)
+				return mock
+			},
+			mockPermsStore: func() db.PermsStore {
+				mock := NewMockPermsStore()
+				mock.AuthorizeFunc.SetDefaultReturn(false)
+				return mock
+			},
+			expStatusCode: http.StatusForbidden,
+			expBody:       `{"message": "Lock is owned by another user"}`,
+		},
+		{
+			name:  "force by admin",
+			actor: &db.User{ID: 1, Name: "alice"},
+			body:  `{"force": true}`,
+			mockLFSStore: func() db.LFSStore {
+				mock := NewMockLFSStore()
+				mock.GetLockByIDFunc.SetDefaultReturn(&db.LFSLock{ID: 1, RepoID: 1, OwnerID: 2, Path: "assets/logo.psd", CreatedAt: lockedAt}, nil)
+				return mock
+			},
+			mockPermsStore: func() db.PermsStore {
+				mock := NewMockPermsStore()
+				mock.AuthorizeFunc.SetDefaultReturn(true)
+				return mock
+			},
+			expStatusCode: http.StatusOK,
+			expBody: `{
+	"lock": {"id": "1", "path": "assets/logo.psd", "locked_at": "2026-01-02T03:04:05Z", "owner": {"name": "bob"}}
+}`,
+		},
+		{
+			name:  "owned by self",
+			actor: &db.User{ID: 2, Name: "bob"},
+			body:  `{}`,
+			mockLFSStore: func() db.LFSStore {
+				mock := NewMockLFSStore()
+				mock.GetLockByIDFunc.SetDefaultReturn(&db.LFSLock{ID: 1, RepoID: 1, OwnerID: 2, Path: "assets/logo.psd", CreatedAt: lockedAt}, nil)
+				return mock
+			},
+			expStatusCode: http.StatusOK,
+			expBody: `{
+	"lock": {"id": "1", "path": "assets/logo.psd", "locked_at": "2026-01-02T03:04:05Z", "owner": {"name": "bob"}}
+}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db.SetMockLFSStore(t, test.mockLFSStore())
+			if test.mockPermsStore != nil {
+				db.SetMockPermsStore(t, test.mockPermsStore())
+			}
+
+			m := macaron.New()
+			m.Use(func(c *macaron.Context) {
+				c.Map(&authenticatedUser{User: test.actor})
+				c.Map(&db.Repository{ID: 1, Name: "repo"})
+			})
+			m.Post("/:id/unlock", serveUnlock)
+
+			r, err := http.NewRequest("POST", "/1/unlock", bytes.NewBufferString(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			m.ServeHTTP(rr, r)
+
+			resp := rr.Result()
+			assert.Equal(t, test.expStatusCode, resp.StatusCode)
+			assertJSONBody(t, test.expBody, resp)
+		})
+	}
+}
+
+func assertJSONBody(t *testing.T, want string, resp *http.Response) {
+	t.Helper()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expBody bytes.Buffer
+	err = json.Indent(&expBody, []byte(want), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotBody bytes.Buffer
+	err = json.Indent(&gotBody, bytes.TrimSpace(body), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, expBody.String(), gotBody.String())
+}
@@ -9,9 +9,12 @@ package lfs
 import (
 	"context"
 	"sync"
+	"time"
 
+	auth "gogs.io/gogs/internal/auth"
 	db "gogs.io/gogs/internal/db"
 	lfsutil "gogs.io/gogs/internal/lfsutil"
+	webauthn "gogs.io/gogs/internal/webauthn"
 )
 
 // MockAccessTokensStore is a mock implementation of the AccessTokensStore
@@ -30,6 +33,12 @@ type MockAccessTokensStore struct {
 	// ListFunc is an instance of a mock function object controlling the
 	// behavior of the method List.
 	ListFunc *AccessTokensStoreListFunc
+	// ListExpiringFunc is an instance of a mock function object controlling
+	// the behavior of the method ListExpiring.
+	ListExpiringFunc *AccessTokensStoreListExpiringFunc
+	// MarkExpiryNotifiedFunc is an instance of a mock function object
+	// controlling the behavior of the method MarkExpiryNotified.
+	MarkExpiryNotifiedFunc *AccessTokensStoreMarkExpiryNotifiedFunc
 	// TouchFunc is an instance of a mock function object controlling the
 	// behavior of the method Touch.
 	TouchFunc *AccessTokensStoreTouchFunc
@@ -41,7 +50,7 @@ type MockAccessTokensStore struct {
 func NewMockAccessTokensStore() *MockAccessTokensStore {
 	return &MockAccessTokensStore{
 		CreateFunc: &AccessTokensStoreCreateFunc{
-			defaultHook: func(context.Context, int64, string) (r0 *db.AccessToken, r1 error) {
+			defaultHook: func(context.Context, int64, string, db.CreateAccessTokenOptions) (r0 *db.AccessToken, r1 error) {
 				return
 			},
 		},
@@ -60,6 +69,16 @@ func NewMockAccessTokensStore() *MockAccessTokensStore {
 				return
 			},
 		},
+		ListExpiringFunc: &AccessTokensStoreListExpiringFunc{
+			defaultHook: func(context.Context, time.Time) (r0 []*db.AccessToken, r1 error) {
+				return
+			},
+		},
+		MarkExpiryNotifiedFunc: &AccessTokensStoreMarkExpiryNotifiedFunc{
+			defaultHook: func(context.Context, int64) (r0 error) {
+				return
+			},
+		},
 		TouchFunc: &AccessTokensStoreTouchFunc{
 			defaultHook: func(context.Context, int64) (r0 error) {
 				return
@@ -74,7 +93,7 @@ func NewMockAccessTokensStore() *MockAccessTokensStore {
 func NewStrictMockAccessTokensStore() *MockAccessTokensStore {
 	return &MockAccessTokensStore{
 		CreateFunc: &AccessTokensStoreCreateFunc{
-			defaultHook: func(context.Context, int64, string) (*db.AccessToken, error) {
+			defaultHook: func(context.Context, int64, string, db.CreateAccessTokenOptions) (*db.AccessToken, error) {
 				panic("unexpected invocation of MockAccessTokensStore.Create")
 			},
 		},
@@ -93,6 +112,16 @@ func NewStrictMockAccessTokensStore() *MockAccessTokensStore {
 				panic("unexpected invocation of MockAccessTokensStore.List")
 			},
 		},
+		ListExpiringFunc: &AccessTokensStoreListExpiringFunc{
+			defaultHook: func(context.Context, time.Time) ([]*db.AccessToken, error) {
+				panic("unexpected invocation of MockAccessTokensStore.ListExpiring")
+			},
+		},
+		MarkExpiryNotifiedFunc: &AccessTokensStoreMarkExpiryNotifiedFunc{
+			defaultHook: func(context.Context, int64) error {
+				panic("unexpected invocation of MockAccessTokensStore.MarkExpiryNotified")
+			},
+		},
 		TouchFunc: &AccessTokensStoreTouchFunc{
 			defaultHook: func(context.Context, int64) error {
 				panic("unexpected invocation of MockAccessTokensStore.Touch")
@@ -118,6 +147,12 @@ func NewMockAccessTokensStoreFrom(i db.AccessTokensStore) *MockAccessTokensStore
 		ListFunc: &AccessTokensStoreListFunc{
 			defaultHook: i.List,
 		},
+		ListExpiringFunc: &AccessTokensStoreListExpiringFunc{
+			defaultHook: i.ListExpiring,
+		},
+		MarkExpiryNotifiedFunc: &AccessTokensStoreMarkExpiryNotifiedFunc{
+			defaultHook: i.MarkExpiryNotified,
+		},
 		TouchFunc: &AccessTokensStoreTouchFunc{
 			defaultHook: i.Touch,
 		},
@@ -127,24 +162,24 @@ func NewMockAccessTokensStoreFrom(i db.AccessTokensStore) *MockAccessTokensStore
 // AccessTokensStoreCreateFunc describes the behavior when the Create method
 // of the parent MockAccessTokensStore instance is invoked.
 type AccessTokensStoreCreateFunc struct {
-	defaultHook func(context.Context, int64, string) (*db.AccessToken, error)
-	hooks       []func(context.Context, int64, string) (*db.AccessToken, error)
+	defaultHook func(context.Context, int64, string, db.CreateAccessTokenOptions) (*db.AccessToken, error)
+	hooks       []func(context.Context, int64, string, db.CreateAccessTokenOptions) (*db.AccessToken, error)
 	history     []AccessTokensStoreCreateFuncCall
 	mutex       sync.Mutex
 }
 
 // Create delegates to the next hook function in the queue and stores the
 // parameter and result values of this invocation.
-func (m *MockAccessTokensStore) Create(v0 context.Context, v1 int64, v2 string) (*db.AccessToken, error) {
-	r0, r1 := m.CreateFunc.nextHook()(v0, v1, v2)
-	m.CreateFunc.appendCall(AccessTokensStoreCreateFuncCall{v0, v1, v2, r0, r1})
+func (m *MockAccessTokensStore) Create(v0 context.Context, v1 int64, v2 string, v3 db.CreateAccessTokenOptions) (*db.AccessToken, error) {
+	r0, r1 := m.CreateFunc.nextHook()(v0, v1, v2, v3)
+	m.CreateFunc.appendCall(AccessTokensStoreCreateFuncCall{v0, v1, v2, v3, r0, r1})
 	return r0, r1
 }
 
 // SetDefaultHook sets function that is called when the Create method of the
 // parent MockAccessTokensStore instance is invoked and the hook queue is
 // empty.
-func (f *AccessTokensStoreCreateFunc) SetDefaultHook(hook func(context.Context, int64, string) (*db.AccessToken, error)) {
+func (f *AccessTokensStoreCreateFunc) SetDefaultHook(hook func(context.Context, int64, string, db.CreateAccessTokenOptions) (*db.AccessToken, error)) {
 	f.defaultHook = hook
 }
 
@@ -152,7 +187,7 @@ func (f *AccessTokensStoreCreateFunc) SetDefaultHook(hook func(context.Context,
 // Create method of the parent MockAccessTokensStore instance invokes the
 // hook at the front of the queue and discards it. After the queue is empty,
 // the default hook function is invoked for any future action.
-func (f *AccessTokensStoreCreateFunc) PushHook(hook func(context.Context, int64, string) (*db.AccessToken, error)) {
+func (f *AccessTokensStoreCreateFunc) PushHook(hook func(context.Context, int64, string, db.CreateAccessTokenOptions) (*db.AccessToken, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -161,19 +196,19 @@ func (f *AccessTokensStoreCreateFunc) PushHook(hook func(context.Context, int64,
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
 func (f *AccessTokensStoreCreateFunc) SetDefaultReturn(r0 *db.AccessToken, r1 error) {
-	f.SetDefaultHook(func(context.Context, int64, string) (*db.AccessToken, error) {
+	f.SetDefaultHook(func(context.Context, int64, string, db.CreateAccessTokenOptions) (*db.AccessToken, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
 func (f *AccessTokensStoreCreateFunc) PushReturn(r0 *db.AccessToken, r1 error) {
-	f.PushHook(func(context.Context, int64, string) (*db.AccessToken, error) {
+	f.PushHook(func(context.Context, int64, string, db.CreateAccessTokenOptions) (*db.AccessToken, error) {
 		return r0, r1
 	})
 }
 
-func (f *AccessTokensStoreCreateFunc) nextHook() func(context.Context, int64, string) (*db.AccessToken, error) {
+func (f *AccessTokensStoreCreateFunc) nextHook() func(context.Context, int64, string, db.CreateAccessTokenOptions) (*db.AccessToken, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -215,6 +250,9 @@ type AccessTokensStoreCreateFuncCall struct {
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 db.CreateAccessTokenOptions
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 *db.AccessToken
@@ -226,7 +264,7 @@ type AccessTokensStoreCreateFuncCall struct {
 // Args returns an interface slice containing the arguments of this
 // invocation.
 func (c AccessTokensStoreCreateFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
 }
 
 // Results returns an interface slice containing the results of this
@@ -560,35 +598,36 @@ func (c AccessTokensStoreListFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// AccessTokensStoreTouchFunc describes the behavior when the Touch method
-// of the parent MockAccessTokensStore instance is invoked.
-type AccessTokensStoreTouchFunc struct {
-	defaultHook func(context.Context, int64) error
-	hooks       []func(context.Context, int64) error
-	history     []AccessTokensStoreTouchFuncCall
+// AccessTokensStoreListExpiringFunc describes the behavior when the
+// ListExpiring method of the parent MockAccessTokensStore instance is
+// invoked.
+type AccessTokensStoreListExpiringFunc struct {
+	defaultHook func(context.Context, time.Time) ([]*db.AccessToken, error)
+	hooks       []func(context.Context, time.Time) ([]*db.AccessToken, error)
+	history     []AccessTokensStoreListExpiringFuncCall
 	mutex       sync.Mutex
 }
 
-// Touch delegates to the next hook function in the queue and stores the
-// parameter and result values of this invocation.
-func (m *MockAccessTokensStore) Touch(v0 context.Context, v1 int64) error {
-	r0 := m.TouchFunc.nextHook()(v0, v1)
-	m.TouchFunc.appendCall(AccessTokensStoreTouchFuncCall{v0, v1, r0})
-	return r0
+// ListExpiring delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockAccessTokensStore) ListExpiring(v0 context.Context, v1 time.Time) ([]*db.AccessToken, error) {
+	r0, r1 := m.ListExpiringFunc.nextHook()(v0, v1)
+	m.ListExpiringFunc.appendCall(AccessTokensStoreListExpiringFuncCall{v0, v1, r0, r1})
+	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the Touch method of the
-// parent MockAccessTokensStore instance is invoked and the hook queue is
-// empty.
-func (f *AccessTokensStoreTouchFunc) SetDefaultHook(hook func(context.Context, int64) error) {
+// SetDefaultHook sets function that is called when the ListExpiring method
+// of the parent MockAccessTokensStore instance is invoked and the hook
+// queue is empty.
+func (f *AccessTokensStoreListExpiringFunc) SetDefaultHook(hook func(context.Context, time.Time) ([]*db.AccessToken, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// Touch method of the parent MockAccessTokensStore instance invokes the
-// hook at the front of the queue and discards it. After the queue is empty,
-// the default hook function is invoked for any future action.
-func (f *AccessTokensStoreTouchFunc) PushHook(hook func(context.Context, int64) error) {
+// ListExpiring method of the parent MockAccessTokensStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *AccessTokensStoreListExpiringFunc) PushHook(hook func(context.Context, time.Time) ([]*db.AccessToken, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -596,20 +635,20 @@ func (f *AccessTokensStoreTouchFunc) PushHook(hook func(context.Context, int64)
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *AccessTokensStoreTouchFunc) SetDefaultReturn(r0 error) {
-	f.SetDefaultHook(func(context.Context, int64) error {
-		return r0
+func (f *AccessTokensStoreListExpiringFunc) SetDefaultReturn(r0 []*db.AccessToken, r1 error) {
+	f.SetDefaultHook(func(context.Context, time.Time) ([]*db.AccessToken, error) {
+		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *AccessTokensStoreTouchFunc) PushReturn(r0 error) {
-	f.PushHook(func(context.Context, int64) error {
-		return r0
+func (f *AccessTokensStoreListExpiringFunc) PushReturn(r0 []*db.AccessToken, r1 error) {
+	f.PushHook(func(context.Context, time.Time) ([]*db.AccessToken, error) {
+		return r0, r1
 	})
 }
 
-func (f *AccessTokensStoreTouchFunc) nextHook() func(context.Context, int64) error {
+func (f *AccessTokensStoreListExpiringFunc) nextHook() func(context.Context, time.Time) ([]*db.AccessToken, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -622,152 +661,84 @@ func (f *AccessTokensStoreTouchFunc) nextHook() func(context.Context, int64) err
 	return hook
 }
 
-func (f *AccessTokensStoreTouchFunc) appendCall(r0 AccessTokensStoreTouchFuncCall) {
+func (f *AccessTokensStoreListExpiringFunc) appendCall(r0 AccessTokensStoreListExpiringFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of AccessTokensStoreTouchFuncCall objects
-// describing the invocations of this function.
-func (f *AccessTokensStoreTouchFunc) History() []AccessTokensStoreTouchFuncCall {
+// History returns a sequence of AccessTokensStoreListExpiringFuncCall
+// objects describing the invocations of this function.
+func (f *AccessTokensStoreListExpiringFunc) History() []AccessTokensStoreListExpiringFuncCall {
 	f.mutex.Lock()
-	history := make([]AccessTokensStoreTouchFuncCall, len(f.history))
+	history := make([]AccessTokensStoreListExpiringFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// AccessTokensStoreTouchFuncCall is an object that describes an invocation
-// of method Touch on an instance of MockAccessTokensStore.
-type AccessTokensStoreTouchFuncCall struct {
+// AccessTokensStoreListExpiringFuncCall is an object that describes an
+// invocation of method ListExpiring on an instance of
+// MockAccessTokensStore.
+type AccessTokensStoreListExpiringFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 int64
+	Arg1 time.Time
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 error
+	Result0 []*db.AccessToken
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c AccessTokensStoreTouchFuncCall) Args() []interface{} {
+func (c AccessTokensStoreListExpiringFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c AccessTokensStoreTouchFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0}
-}
-
-// MockLFSStore is a mock implementation of the LFSStore interface (from the
-// package gogs.io/gogs/internal/db) used for unit testing.
-type MockLFSStore struct {
-	// CreateObjectFunc is an instance of a mock function object controlling
-	// the behavior of the method CreateObject.
-	CreateObjectFunc *LFSStoreCreateObjectFunc
-	// GetObjectByOIDFunc is an instance of a mock function object
-	// controlling the behavior of the method GetObjectByOID.
-	GetObjectByOIDFunc *LFSStoreGetObjectByOIDFunc
-	// GetObjectsByOIDsFunc is an instance of a mock function object
-	// controlling the behavior of the method GetObjectsByOIDs.
-	GetObjectsByOIDsFunc *LFSStoreGetObjectsByOIDsFunc
-}
-
-// NewMockLFSStore creates a new mock of the LFSStore interface. All methods
-// return zero values for all results, unless overwritten.
-func NewMockLFSStore() *MockLFSStore {
-	return &MockLFSStore{
-		CreateObjectFunc: &LFSStoreCreateObjectFunc{
-			defaultHook: func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) (r0 error) {
-				return
-			},
-		},
-		GetObjectByOIDFunc: &LFSStoreGetObjectByOIDFunc{
-			defaultHook: func(context.Context, int64, lfsutil.OID) (r0 *db.LFSObject, r1 error) {
-				return
-			},
-		},
-		GetObjectsByOIDsFunc: &LFSStoreGetObjectsByOIDsFunc{
-			defaultHook: func(context.Context, int64, ...lfsutil.OID) (r0 []*db.LFSObject, r1 error) {
-				return
-			},
-		},
-	}
-}
-
-// NewStrictMockLFSStore creates a new mock of the LFSStore interface. All
-// methods panic on invocation, unless overwritten.
-func NewStrictMockLFSStore() *MockLFSStore {
-	return &MockLFSStore{
-		CreateObjectFunc: &LFSStoreCreateObjectFunc{
-			defaultHook: func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error {
-				panic("unexpected invocation of MockLFSStore.CreateObject")
-			},
-		},
-		GetObjectByOIDFunc: &LFSStoreGetObjectByOIDFunc{
-			defaultHook: func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error) {
-				panic("unexpected invocation of MockLFSStore.GetObjectByOID")
-			},
-		},
-		GetObjectsByOIDsFunc: &LFSStoreGetObjectsByOIDsFunc{
-			defaultHook: func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error) {
-				panic("unexpected invocation of MockLFSStore.GetObjectsByOIDs")
-			},
-		},
-	}
-}
-
-// NewMockLFSStoreFrom creates a new mock of the MockLFSStore interface. All
-// methods delegate to the given implementation, unless overwritten.
-func NewMockLFSStoreFrom(i db.LFSStore) *MockLFSStore {
-	return &MockLFSStore{
-		CreateObjectFunc: &LFSStoreCreateObjectFunc{
-			defaultHook: i.CreateObject,
-		},
-		GetObjectByOIDFunc: &LFSStoreGetObjectByOIDFunc{
-			defaultHook: i.GetObjectByOID,
-		},
-		GetObjectsByOIDsFunc: &LFSStoreGetObjectsByOIDsFunc{
-			defaultHook: i.GetObjectsByOIDs,
-		},
-	}
+func (c AccessTokensStoreListExpiringFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
-// LFSStoreCreateObjectFunc describes the behavior when the CreateObject
-// method of the parent MockLFSStore instance is invoked.
-type LFSStoreCreateObjectFunc struct {
-	defaultHook func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error
-	hooks       []func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error
-	history     []LFSStoreCreateObjectFuncCall
+// AccessTokensStoreMarkExpiryNotifiedFunc describes the behavior when the
+// MarkExpiryNotified method of the parent MockAccessTokensStore instance is
+// invoked.
+type AccessTokensStoreMarkExpiryNotifiedFunc struct {
+	defaultHook func(context.Context, int64) error
+	hooks       []func(context.Context, int64) error
+	history     []AccessTokensStoreMarkExpiryNotifiedFuncCall
 	mutex       sync.Mutex
 }
 
-// CreateObject delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockLFSStore) CreateObject(v0 context.Context, v1 int64, v2 lfsutil.OID, v3 int64, v4 lfsutil.Storage) error {
-	r0 := m.CreateObjectFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.CreateObjectFunc.appendCall(LFSStoreCreateObjectFuncCall{v0, v1, v2, v3, v4, r0})
+// MarkExpiryNotified delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockAccessTokensStore) MarkExpiryNotified(v0 context.Context, v1 int64) error {
+	r0 := m.MarkExpiryNotifiedFunc.nextHook()(v0, v1)
+	m.MarkExpiryNotifiedFunc.appendCall(AccessTokensStoreMarkExpiryNotifiedFuncCall{v0, v1, r0})
 	return r0
 }
 
-// SetDefaultHook sets function that is called when the CreateObject method
-// of the parent MockLFSStore instance is invoked and the hook queue is
-// empty.
-func (f *LFSStoreCreateObjectFunc) SetDefaultHook(hook func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error) {
+// SetDefaultHook sets function that is called when the MarkExpiryNotified
+// method of the parent MockAccessTokensStore instance is invoked and the
+// hook queue is empty.
+func (f *AccessTokensStoreMarkExpiryNotifiedFunc) SetDefaultHook(hook func(context.Context, int64) error) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// CreateObject method of the parent MockLFSStore instance invokes the hook
-// at the front of the queue and discards it. After the queue is empty, the
-// default hook function is invoked for any future action.
-func (f *LFSStoreCreateObjectFunc) PushHook(hook func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error) {
+// MarkExpiryNotified method of the parent MockAccessTokensStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *AccessTokensStoreMarkExpiryNotifiedFunc) PushHook(hook func(context.Context, int64) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -775,20 +746,20 @@ func (f *LFSStoreCreateObjectFunc) PushHook(hook func(context.Context, int64, lf
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *LFSStoreCreateObjectFunc) SetDefaultReturn(r0 error) {
-	f.SetDefaultHook(func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error {
+func (f *AccessTokensStoreMarkExpiryNotifiedFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64) error {
 		return r0
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *LFSStoreCreateObjectFunc) PushReturn(r0 error) {
-	f.PushHook(func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error {
+func (f *AccessTokensStoreMarkExpiryNotifiedFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64) error {
 		return r0
 	})
 }
 
-func (f *LFSStoreCreateObjectFunc) nextHook() func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error {
+func (f *AccessTokensStoreMarkExpiryNotifiedFunc) nextHook() func(context.Context, int64) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -801,41 +772,33 @@ func (f *LFSStoreCreateObjectFunc) nextHook() func(context.Context, int64, lfsut
 	return hook
 }
 
-func (f *LFSStoreCreateObjectFunc) appendCall(r0 LFSStoreCreateObjectFuncCall) {
+func (f *AccessTokensStoreMarkExpiryNotifiedFunc) appendCall(r0 AccessTokensStoreMarkExpiryNotifiedFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of LFSStoreCreateObjectFuncCall objects
-// describing the invocations of this function.
-func (f *LFSStoreCreateObjectFunc) History() []LFSStoreCreateObjectFuncCall {
+// History returns a sequence of AccessTokensStoreMarkExpiryNotifiedFuncCall
+// objects describing the invocations of this function.
+func (f *AccessTokensStoreMarkExpiryNotifiedFunc) History() []AccessTokensStoreMarkExpiryNotifiedFuncCall {
 	f.mutex.Lock()
-	history := make([]LFSStoreCreateObjectFuncCall, len(f.history))
+	history := make([]AccessTokensStoreMarkExpiryNotifiedFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// LFSStoreCreateObjectFuncCall is an object that describes an invocation of
-// method CreateObject on an instance of MockLFSStore.
-type LFSStoreCreateObjectFuncCall struct {
+// AccessTokensStoreMarkExpiryNotifiedFuncCall is an object that describes
+// an invocation of method MarkExpiryNotified on an instance of
+// MockAccessTokensStore.
+type AccessTokensStoreMarkExpiryNotifiedFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
 	Arg1 int64
-	// Arg2 is the value of the 3rd argument passed to this method
-	// invocation.
-	Arg2 lfsutil.OID
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 int64
-	// Arg4 is the value of the 5th argument passed to this method
-	// invocation.
-	Arg4 lfsutil.Storage
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 error
@@ -843,45 +806,45 @@ type LFSStoreCreateObjectFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c LFSStoreCreateObjectFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+func (c AccessTokensStoreMarkExpiryNotifiedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LFSStoreCreateObjectFuncCall) Results() []interface{} {
+func (c AccessTokensStoreMarkExpiryNotifiedFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
-// LFSStoreGetObjectByOIDFunc describes the behavior when the GetObjectByOID
-// method of the parent MockLFSStore instance is invoked.
-type LFSStoreGetObjectByOIDFunc struct {
-	defaultHook func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error)
-	hooks       []func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error)
-	history     []LFSStoreGetObjectByOIDFuncCall
+// AccessTokensStoreTouchFunc describes the behavior when the Touch method
+// of the parent MockAccessTokensStore instance is invoked.
+type AccessTokensStoreTouchFunc struct {
+	defaultHook func(context.Context, int64) error
+	hooks       []func(context.Context, int64) error
+	history     []AccessTokensStoreTouchFuncCall
 	mutex       sync.Mutex
 }
 
-// GetObjectByOID delegates to the next hook function in the queue and
-// stores the parameter and result values of this invocation.
-func (m *MockLFSStore) GetObjectByOID(v0 context.Context, v1 int64, v2 lfsutil.OID) (*db.LFSObject, error) {
-	r0, r1 := m.GetObjectByOIDFunc.nextHook()(v0, v1, v2)
-	m.GetObjectByOIDFunc.appendCall(LFSStoreGetObjectByOIDFuncCall{v0, v1, v2, r0, r1})
-	return r0, r1
+// Touch delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockAccessTokensStore) Touch(v0 context.Context, v1 int64) error {
+	r0 := m.TouchFunc.nextHook()(v0, v1)
+	m.TouchFunc.appendCall(AccessTokensStoreTouchFuncCall{v0, v1, r0})
+	return r0
 }
 
-// SetDefaultHook sets function that is called when the GetObjectByOID
-// method of the parent MockLFSStore instance is invoked and the hook queue
-// is empty.
-func (f *LFSStoreGetObjectByOIDFunc) SetDefaultHook(hook func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error)) {
+// SetDefaultHook sets function that is called when the Touch method of the
+// parent MockAccessTokensStore instance is invoked and the hook queue is
+// empty.
+func (f *AccessTokensStoreTouchFunc) SetDefaultHook(hook func(context.Context, int64) error) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetObjectByOID method of the parent MockLFSStore instance invokes the
+// Touch method of the parent MockAccessTokensStore instance invokes the
 // hook at the front of the queue and discards it. After the queue is empty,
 // the default hook function is invoked for any future action.
-func (f *LFSStoreGetObjectByOIDFunc) PushHook(hook func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error)) {
+func (f *AccessTokensStoreTouchFunc) PushHook(hook func(context.Context, int64) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -889,20 +852,20 @@ func (f *LFSStoreGetObjectByOIDFunc) PushHook(hook func(context.Context, int64,
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *LFSStoreGetObjectByOIDFunc) SetDefaultReturn(r0 *db.LFSObject, r1 error) {
-	f.SetDefaultHook(func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error) {
-		return r0, r1
+func (f *AccessTokensStoreTouchFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64) error {
+		return r0
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *LFSStoreGetObjectByOIDFunc) PushReturn(r0 *db.LFSObject, r1 error) {
-	f.PushHook(func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error) {
-		return r0, r1
+func (f *AccessTokensStoreTouchFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64) error {
+		return r0
 	})
 }
 
-func (f *LFSStoreGetObjectByOIDFunc) nextHook() func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error) {
+func (f *AccessTokensStoreTouchFunc) nextHook() func(context.Context, int64) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -915,84 +878,360 @@ func (f *LFSStoreGetObjectByOIDFunc) nextHook() func(context.Context, int64, lfs
 	return hook
 }
 
-func (f *LFSStoreGetObjectByOIDFunc) appendCall(r0 LFSStoreGetObjectByOIDFuncCall) {
+func (f *AccessTokensStoreTouchFunc) appendCall(r0 AccessTokensStoreTouchFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of LFSStoreGetObjectByOIDFuncCall objects
+// History returns a sequence of AccessTokensStoreTouchFuncCall objects
 // describing the invocations of this function.
-func (f *LFSStoreGetObjectByOIDFunc) History() []LFSStoreGetObjectByOIDFuncCall {
+func (f *AccessTokensStoreTouchFunc) History() []AccessTokensStoreTouchFuncCall {
 	f.mutex.Lock()
-	history := make([]LFSStoreGetObjectByOIDFuncCall, len(f.history))
+	history := make([]AccessTokensStoreTouchFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// LFSStoreGetObjectByOIDFuncCall is an object that describes an invocation
-// of method GetObjectByOID on an instance of MockLFSStore.
-type LFSStoreGetObjectByOIDFuncCall struct {
+// AccessTokensStoreTouchFuncCall is an object that describes an invocation
+// of method Touch on an instance of MockAccessTokensStore.
+type AccessTokensStoreTouchFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
 	Arg1 int64
-	// Arg2 is the value of the 3rd argument passed to this method
-	// invocation.
-	Arg2 lfsutil.OID
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 *db.LFSObject
-	// Result1 is the value of the 2nd result returned from this method
-	// invocation.
-	Result1 error
+	Result0 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c LFSStoreGetObjectByOIDFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+func (c AccessTokensStoreTouchFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LFSStoreGetObjectByOIDFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0, c.Result1}
+func (c AccessTokensStoreTouchFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
 }
 
-// LFSStoreGetObjectsByOIDsFunc describes the behavior when the
-// GetObjectsByOIDs method of the parent MockLFSStore instance is invoked.
-type LFSStoreGetObjectsByOIDsFunc struct {
-	defaultHook func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error)
-	hooks       []func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error)
-	history     []LFSStoreGetObjectsByOIDsFuncCall
+// MockLFSStore is a mock implementation of the LFSStore interface (from the
+// package gogs.io/gogs/internal/db) used for unit testing.
+type MockLFSStore struct {
+	// CountObjectsByOIDFunc is an instance of a mock function object
+	// controlling the behavior of the method CountObjectsByOID.
+	CountObjectsByOIDFunc *LFSStoreCountObjectsByOIDFunc
+	// CreateLockFunc is an instance of a mock function object controlling
+	// the behavior of the method CreateLock.
+	CreateLockFunc *LFSStoreCreateLockFunc
+	// CreateObjectFunc is an instance of a mock function object controlling
+	// the behavior of the method CreateObject.
+	CreateObjectFunc *LFSStoreCreateObjectFunc
+	// DeleteLockByIDFunc is an instance of a mock function object
+	// controlling the behavior of the method DeleteLockByID.
+	DeleteLockByIDFunc *LFSStoreDeleteLockByIDFunc
+	// DeleteObjectFunc is an instance of a mock function object controlling
+	// the behavior of the method DeleteObject.
+	DeleteObjectFunc *LFSStoreDeleteObjectFunc
+	// GetLockByIDFunc is an instance of a mock function object controlling
+	// the behavior of the method GetLockByID.
+	GetLockByIDFunc *LFSStoreGetLockByIDFunc
+	// GetLockByPathFunc is an instance of a mock function object
+	// controlling the behavior of the method GetLockByPath.
+	GetLockByPathFunc *LFSStoreGetLockByPathFunc
+	// GetObjectByOIDFunc is an instance of a mock function object
+	// controlling the behavior of the method GetObjectByOID.
+	GetObjectByOIDFunc *LFSStoreGetObjectByOIDFunc
+	// GetObjectsByOIDsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetObjectsByOIDs.
+	GetObjectsByOIDsFunc *LFSStoreGetObjectsByOIDsFunc
+	// GetObjectsByRepoIDFunc is an instance of a mock function object
+	// controlling the behavior of the method GetObjectsByRepoID.
+	GetObjectsByRepoIDFunc *LFSStoreGetObjectsByRepoIDFunc
+	// GetObjectsByStorageFunc is an instance of a mock function object
+	// controlling the behavior of the method GetObjectsByStorage.
+	GetObjectsByStorageFunc *LFSStoreGetObjectsByStorageFunc
+	// GetOrphanedObjectsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetOrphanedObjects.
+	GetOrphanedObjectsFunc *LFSStoreGetOrphanedObjectsFunc
+	// ListLocksFunc is an instance of a mock function object controlling
+	// the behavior of the method ListLocks.
+	ListLocksFunc *LFSStoreListLocksFunc
+	// TotalSizeByOwnerIDFunc is an instance of a mock function object
+	// controlling the behavior of the method TotalSizeByOwnerID.
+	TotalSizeByOwnerIDFunc *LFSStoreTotalSizeByOwnerIDFunc
+	// TotalSizeByRepoIDFunc is an instance of a mock function object
+	// controlling the behavior of the method TotalSizeByRepoID.
+	TotalSizeByRepoIDFunc *LFSStoreTotalSizeByRepoIDFunc
+	// UpdateObjectsStorageFunc is an instance of a mock function object
+	// controlling the behavior of the method UpdateObjectsStorage.
+	UpdateObjectsStorageFunc *LFSStoreUpdateObjectsStorageFunc
+}
+
+// NewMockLFSStore creates a new mock of the LFSStore interface. All methods
+// return zero values for all results, unless overwritten.
+func NewMockLFSStore() *MockLFSStore {
+	return &MockLFSStore{
+		CountObjectsByOIDFunc: &LFSStoreCountObjectsByOIDFunc{
+			defaultHook: func(context.Context, lfsutil.OID) (r0 int64, r1 error) {
+				return
+			},
+		},
+		CreateLockFunc: &LFSStoreCreateLockFunc{
+			defaultHook: func(context.Context, int64, int64, string) (r0 *db.LFSLock, r1 error) {
+				return
+			},
+		},
+		CreateObjectFunc: &LFSStoreCreateObjectFunc{
+			defaultHook: func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) (r0 error) {
+				return
+			},
+		},
+		DeleteLockByIDFunc: &LFSStoreDeleteLockByIDFunc{
+			defaultHook: func(context.Context, int64, int64) (r0 error) {
+				return
+			},
+		},
+		DeleteObjectFunc: &LFSStoreDeleteObjectFunc{
+			defaultHook: func(context.Context, int64, lfsutil.OID) (r0 error) {
+				return
+			},
+		},
+		GetLockByIDFunc: &LFSStoreGetLockByIDFunc{
+			defaultHook: func(context.Context, int64, int64) (r0 *db.LFSLock, r1 error) {
+				return
+			},
+		},
+		GetLockByPathFunc: &LFSStoreGetLockByPathFunc{
+			defaultHook: func(context.Context, int64, string) (r0 *db.LFSLock, r1 error) {
+				return
+			},
+		},
+		GetObjectByOIDFunc: &LFSStoreGetObjectByOIDFunc{
+			defaultHook: func(context.Context, int64, lfsutil.OID) (r0 *db.LFSObject, r1 error) {
+				return
+			},
+		},
+		GetObjectsByOIDsFunc: &LFSStoreGetObjectsByOIDsFunc{
+			defaultHook: func(context.Context, int64, ...lfsutil.OID) (r0 []*db.LFSObject, r1 error) {
+				return
+			},
+		},
+		GetObjectsByRepoIDFunc: &LFSStoreGetObjectsByRepoIDFunc{
+			defaultHook: func(context.Context, int64) (r0 []*db.LFSObject, r1 error) {
+				return
+			},
+		},
+		GetObjectsByStorageFunc: &LFSStoreGetObjectsByStorageFunc{
+			defaultHook: func(context.Context, lfsutil.Storage) (r0 []*db.LFSObject, r1 error) {
+				return
+			},
+		},
+		GetOrphanedObjectsFunc: &LFSStoreGetOrphanedObjectsFunc{
+			defaultHook: func(context.Context, time.Time) (r0 []*db.LFSObject, r1 error) {
+				return
+			},
+		},
+		ListLocksFunc: &LFSStoreListLocksFunc{
+			defaultHook: func(context.Context, int64, db.ListLFSLocksOptions) (r0 []*db.LFSLock, r1 error) {
+				return
+			},
+		},
+		TotalSizeByOwnerIDFunc: &LFSStoreTotalSizeByOwnerIDFunc{
+			defaultHook: func(context.Context, int64) (r0 int64, r1 error) {
+				return
+			},
+		},
+		TotalSizeByRepoIDFunc: &LFSStoreTotalSizeByRepoIDFunc{
+			defaultHook: func(context.Context, int64) (r0 int64, r1 error) {
+				return
+			},
+		},
+		UpdateObjectsStorageFunc: &LFSStoreUpdateObjectsStorageFunc{
+			defaultHook: func(context.Context, lfsutil.OID, lfsutil.Storage, lfsutil.Storage) (r0 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockLFSStore creates a new mock of the LFSStore interface. All
+// methods panic on invocation, unless overwritten.
+func NewStrictMockLFSStore() *MockLFSStore {
+	return &MockLFSStore{
+		CountObjectsByOIDFunc: &LFSStoreCountObjectsByOIDFunc{
+			defaultHook: func(context.Context, lfsutil.OID) (int64, error) {
+				panic("unexpected invocation of MockLFSStore.CountObjectsByOID")
+			},
+		},
+		CreateLockFunc: &LFSStoreCreateLockFunc{
+			defaultHook: func(context.Context, int64, int64, string) (*db.LFSLock, error) {
+				panic("unexpected invocation of MockLFSStore.CreateLock")
+			},
+		},
+		CreateObjectFunc: &LFSStoreCreateObjectFunc{
+			defaultHook: func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error {
+				panic("unexpected invocation of MockLFSStore.CreateObject")
+			},
+		},
+		DeleteLockByIDFunc: &LFSStoreDeleteLockByIDFunc{
+			defaultHook: func(context.Context, int64, int64) error {
+				panic("unexpected invocation of MockLFSStore.DeleteLockByID")
+			},
+		},
+		DeleteObjectFunc: &LFSStoreDeleteObjectFunc{
+			defaultHook: func(context.Context, int64, lfsutil.OID) error {
+				panic("unexpected invocation of MockLFSStore.DeleteObject")
+			},
+		},
+		GetLockByIDFunc: &LFSStoreGetLockByIDFunc{
+			defaultHook: func(context.Context, int64, int64) (*db.LFSLock, error) {
+				panic("unexpected invocation of MockLFSStore.GetLockByID")
+			},
+		},
+		GetLockByPathFunc: &LFSStoreGetLockByPathFunc{
+			defaultHook: func(context.Context, int64, string) (*db.LFSLock, error) {
+				panic("unexpected invocation of MockLFSStore.GetLockByPath")
+			},
+		},
+		GetObjectByOIDFunc: &LFSStoreGetObjectByOIDFunc{
+			defaultHook: func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error) {
+				panic("unexpected invocation of MockLFSStore.GetObjectByOID")
+			},
+		},
+		GetObjectsByOIDsFunc: &LFSStoreGetObjectsByOIDsFunc{
+			defaultHook: func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error) {
+				panic("unexpected invocation of MockLFSStore.GetObjectsByOIDs")
+			},
+		},
+		GetObjectsByRepoIDFunc: &LFSStoreGetObjectsByRepoIDFunc{
+			defaultHook: func(context.Context, int64) ([]*db.LFSObject, error) {
+				panic("unexpected invocation of MockLFSStore.GetObjectsByRepoID")
+			},
+		},
+		GetObjectsByStorageFunc: &LFSStoreGetObjectsByStorageFunc{
+			defaultHook: func(context.Context, lfsutil.Storage) ([]*db.LFSObject, error) {
+				panic("unexpected invocation of MockLFSStore.GetObjectsByStorage")
+			},
+		},
+		GetOrphanedObjectsFunc: &LFSStoreGetOrphanedObjectsFunc{
+			defaultHook: func(context.Context, time.Time) ([]*db.LFSObject, error) {
+				panic("unexpected invocation of MockLFSStore.GetOrphanedObjects")
+			},
+		},
+		ListLocksFunc: &LFSStoreListLocksFunc{
+			defaultHook: func(context.Context, int64, db.ListLFSLocksOptions) ([]*db.LFSLock, error) {
+				panic("unexpected invocation of MockLFSStore.ListLocks")
+			},
+		},
+		TotalSizeByOwnerIDFunc: &LFSStoreTotalSizeByOwnerIDFunc{
+			defaultHook: func(context.Context, int64) (int64, error) {
+				panic("unexpected invocation of MockLFSStore.TotalSizeByOwnerID")
+			},
+		},
+		TotalSizeByRepoIDFunc: &LFSStoreTotalSizeByRepoIDFunc{
+			defaultHook: func(context.Context, int64) (int64, error) {
+				panic("unexpected invocation of MockLFSStore.TotalSizeByRepoID")
+			},
+		},
+		UpdateObjectsStorageFunc: &LFSStoreUpdateObjectsStorageFunc{
+			defaultHook: func(context.Context, lfsutil.OID, lfsutil.Storage, lfsutil.Storage) error {
+				panic("unexpected invocation of MockLFSStore.UpdateObjectsStorage")
+			},
+		},
+	}
+}
+
+// NewMockLFSStoreFrom creates a new mock of the MockLFSStore interface. All
+// methods delegate to the given implementation, unless overwritten.
+func NewMockLFSStoreFrom(i db.LFSStore) *MockLFSStore {
+	return &MockLFSStore{
+		CountObjectsByOIDFunc: &LFSStoreCountObjectsByOIDFunc{
+			defaultHook: i.CountObjectsByOID,
+		},
+		CreateLockFunc: &LFSStoreCreateLockFunc{
+			defaultHook: i.CreateLock,
+		},
+		CreateObjectFunc: &LFSStoreCreateObjectFunc{
+			defaultHook: i.CreateObject,
+		},
+		DeleteLockByIDFunc: &LFSStoreDeleteLockByIDFunc{
+			defaultHook: i.DeleteLockByID,
+		},
+		DeleteObjectFunc: &LFSStoreDeleteObjectFunc{
+			defaultHook: i.DeleteObject,
+		},
+		GetLockByIDFunc: &LFSStoreGetLockByIDFunc{
+			defaultHook: i.GetLockByID,
+		},
+		GetLockByPathFunc: &LFSStoreGetLockByPathFunc{
+			defaultHook: i.GetLockByPath,
+		},
+		GetObjectByOIDFunc: &LFSStoreGetObjectByOIDFunc{
+			defaultHook: i.GetObjectByOID,
+		},
+		GetObjectsByOIDsFunc: &LFSStoreGetObjectsByOIDsFunc{
+			defaultHook: i.GetObjectsByOIDs,
+		},
+		GetObjectsByRepoIDFunc: &LFSStoreGetObjectsByRepoIDFunc{
+			defaultHook: i.GetObjectsByRepoID,
+		},
+		GetObjectsByStorageFunc: &LFSStoreGetObjectsByStorageFunc{
+			defaultHook: i.GetObjectsByStorage,
+		},
+		GetOrphanedObjectsFunc: &LFSStoreGetOrphanedObjectsFunc{
+			defaultHook: i.GetOrphanedObjects,
+		},
+		ListLocksFunc: &LFSStoreListLocksFunc{
+			defaultHook: i.ListLocks,
+		},
+		TotalSizeByOwnerIDFunc: &LFSStoreTotalSizeByOwnerIDFunc{
+			defaultHook: i.TotalSizeByOwnerID,
+		},
+		TotalSizeByRepoIDFunc: &LFSStoreTotalSizeByRepoIDFunc{
+			defaultHook: i.TotalSizeByRepoID,
+		},
+		UpdateObjectsStorageFunc: &LFSStoreUpdateObjectsStorageFunc{
+			defaultHook: i.UpdateObjectsStorage,
+		},
+	}
+}
+
+// LFSStoreCountObjectsByOIDFunc describes the behavior when the
+// CountObjectsByOID method of the parent MockLFSStore instance is invoked.
+type LFSStoreCountObjectsByOIDFunc struct {
+	defaultHook func(context.Context, lfsutil.OID) (int64, error)
+	hooks       []func(context.Context, lfsutil.OID) (int64, error)
+	history     []LFSStoreCountObjectsByOIDFuncCall
 	mutex       sync.Mutex
 }
 
-// GetObjectsByOIDs delegates to the next hook function in the queue and
+// CountObjectsByOID delegates to the next hook function in the queue and
 // stores the parameter and result values of this invocation.
-func (m *MockLFSStore) GetObjectsByOIDs(v0 context.Context, v1 int64, v2 ...lfsutil.OID) ([]*db.LFSObject, error) {
-	r0, r1 := m.GetObjectsByOIDsFunc.nextHook()(v0, v1, v2...)
-	m.GetObjectsByOIDsFunc.appendCall(LFSStoreGetObjectsByOIDsFuncCall{v0, v1, v2, r0, r1})
+func (m *MockLFSStore) CountObjectsByOID(v0 context.Context, v1 lfsutil.OID) (int64, error) {
+	r0, r1 := m.CountObjectsByOIDFunc.nextHook()(v0, v1)
+	m.CountObjectsByOIDFunc.appendCall(LFSStoreCountObjectsByOIDFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the GetObjectsByOIDs
+// SetDefaultHook sets function that is called when the CountObjectsByOID
 // method of the parent MockLFSStore instance is invoked and the hook queue
 // is empty.
-func (f *LFSStoreGetObjectsByOIDsFunc) SetDefaultHook(hook func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error)) {
+func (f *LFSStoreCountObjectsByOIDFunc) SetDefaultHook(hook func(context.Context, lfsutil.OID) (int64, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetObjectsByOIDs method of the parent MockLFSStore instance invokes the
+// CountObjectsByOID method of the parent MockLFSStore instance invokes the
 // hook at the front of the queue and discards it. After the queue is empty,
 // the default hook function is invoked for any future action.
-func (f *LFSStoreGetObjectsByOIDsFunc) PushHook(hook func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error)) {
+func (f *LFSStoreCountObjectsByOIDFunc) PushHook(hook func(context.Context, lfsutil.OID) (int64, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1000,20 +1239,20 @@ func (f *LFSStoreGetObjectsByOIDsFunc) PushHook(hook func(context.Context, int64
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *LFSStoreGetObjectsByOIDsFunc) SetDefaultReturn(r0 []*db.LFSObject, r1 error) {
-	f.SetDefaultHook(func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error) {
+func (f *LFSStoreCountObjectsByOIDFunc) SetDefaultReturn(r0 int64, r1 error) {
+	f.SetDefaultHook(func(context.Context, lfsutil.OID) (int64, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *LFSStoreGetObjectsByOIDsFunc) PushReturn(r0 []*db.LFSObject, r1 error) {
-	f.PushHook(func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error) {
+func (f *LFSStoreCountObjectsByOIDFunc) PushReturn(r0 int64, r1 error) {
+	f.PushHook(func(context.Context, lfsutil.OID) (int64, error) {
 		return r0, r1
 	})
 }
 
-func (f *LFSStoreGetObjectsByOIDsFunc) nextHook() func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error) {
+func (f *LFSStoreCountObjectsByOIDFunc) nextHook() func(context.Context, lfsutil.OID) (int64, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1026,165 +1265,2329 @@ func (f *LFSStoreGetObjectsByOIDsFunc) nextHook() func(context.Context, int64, .
 	return hook
 }
 
-func (f *LFSStoreGetObjectsByOIDsFunc) appendCall(r0 LFSStoreGetObjectsByOIDsFuncCall) {
+func (f *LFSStoreCountObjectsByOIDFunc) appendCall(r0 LFSStoreCountObjectsByOIDFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of LFSStoreGetObjectsByOIDsFuncCall objects
+// History returns a sequence of LFSStoreCountObjectsByOIDFuncCall objects
 // describing the invocations of this function.
-func (f *LFSStoreGetObjectsByOIDsFunc) History() []LFSStoreGetObjectsByOIDsFuncCall {
+func (f *LFSStoreCountObjectsByOIDFunc) History() []LFSStoreCountObjectsByOIDFuncCall {
 	f.mutex.Lock()
-	history := make([]LFSStoreGetObjectsByOIDsFuncCall, len(f.history))
+	history := make([]LFSStoreCountObjectsByOIDFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// LFSStoreGetObjectsByOIDsFuncCall is an object that describes an
-// invocation of method GetObjectsByOIDs on an instance of MockLFSStore.
-type LFSStoreGetObjectsByOIDsFuncCall struct {
+// LFSStoreCountObjectsByOIDFuncCall is an object that describes an
+// invocation of method CountObjectsByOID on an instance of MockLFSStore.
+type LFSStoreCountObjectsByOIDFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 int64
-	// Arg2 is a slice containing the values of the variadic arguments
-	// passed to this method invocation.
-	Arg2 []lfsutil.OID
+	Arg1 lfsutil.OID
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []*db.LFSObject
+	Result0 int64
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
 }
 
 // Args returns an interface slice containing the arguments of this
-// invocation. The variadic slice argument is flattened in this array such
-// that one positional argument and three variadic arguments would result in
-// a slice of four, not two.
-func (c LFSStoreGetObjectsByOIDsFuncCall) Args() []interface{} {
-	trailing := []interface{}{}
-	for _, val := range c.Arg2 {
-		trailing = append(trailing, val)
-	}
-
-	return append([]interface{}{c.Arg0, c.Arg1}, trailing...)
+// invocation.
+func (c LFSStoreCountObjectsByOIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c LFSStoreGetObjectsByOIDsFuncCall) Results() []interface{} {
+func (c LFSStoreCountObjectsByOIDFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// MockPermsStore is a mock implementation of the PermsStore interface (from
-// the package gogs.io/gogs/internal/db) used for unit testing.
-type MockPermsStore struct {
-	// AccessModeFunc is an instance of a mock function object controlling
-	// the behavior of the method AccessMode.
-	AccessModeFunc *PermsStoreAccessModeFunc
-	// AuthorizeFunc is an instance of a mock function object controlling
-	// the behavior of the method Authorize.
-	AuthorizeFunc *PermsStoreAuthorizeFunc
-	// SetRepoPermsFunc is an instance of a mock function object controlling
-	// the behavior of the method SetRepoPerms.
-	SetRepoPermsFunc *PermsStoreSetRepoPermsFunc
+// LFSStoreCreateLockFunc describes the behavior when the CreateLock method
+// of the parent MockLFSStore instance is invoked.
+type LFSStoreCreateLockFunc struct {
+	defaultHook func(context.Context, int64, int64, string) (*db.LFSLock, error)
+	hooks       []func(context.Context, int64, int64, string) (*db.LFSLock, error)
+	history     []LFSStoreCreateLockFuncCall
+	mutex       sync.Mutex
 }
 
-// NewMockPermsStore creates a new mock of the PermsStore interface. All
-// methods return zero values for all results, unless overwritten.
-func NewMockPermsStore() *MockPermsStore {
-	return &MockPermsStore{
-		AccessModeFunc: &PermsStoreAccessModeFunc{
-			defaultHook: func(context.Context, int64, int64, db.AccessModeOptions) (r0 db.AccessMode) {
-				return
-			},
-		},
-		AuthorizeFunc: &PermsStoreAuthorizeFunc{
-			defaultHook: func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) (r0 bool) {
-				return
-			},
-		},
-		SetRepoPermsFunc: &PermsStoreSetRepoPermsFunc{
-			defaultHook: func(context.Context, int64, map[int64]db.AccessMode) (r0 error) {
-				return
-			},
-		},
-	}
+// CreateLock delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockLFSStore) CreateLock(v0 context.Context, v1 int64, v2 int64, v3 string) (*db.LFSLock, error) {
+	r0, r1 := m.CreateLockFunc.nextHook()(v0, v1, v2, v3)
+	m.CreateLockFunc.appendCall(LFSStoreCreateLockFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CreateLock method of
+// the parent MockLFSStore instance is invoked and the hook queue is empty.
+func (f *LFSStoreCreateLockFunc) SetDefaultHook(hook func(context.Context, int64, int64, string) (*db.LFSLock, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CreateLock method of the parent MockLFSStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *LFSStoreCreateLockFunc) PushHook(hook func(context.Context, int64, int64, string) (*db.LFSLock, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreCreateLockFunc) SetDefaultReturn(r0 *db.LFSLock, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, int64, string) (*db.LFSLock, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreCreateLockFunc) PushReturn(r0 *db.LFSLock, r1 error) {
+	f.PushHook(func(context.Context, int64, int64, string) (*db.LFSLock, error) {
+		return r0, r1
+	})
+}
+
+func (f *LFSStoreCreateLockFunc) nextHook() func(context.Context, int64, int64, string) (*db.LFSLock, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreCreateLockFunc) appendCall(r0 LFSStoreCreateLockFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreCreateLockFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreCreateLockFunc) History() []LFSStoreCreateLockFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreCreateLockFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreCreateLockFuncCall is an object that describes an invocation of
+// method CreateLock on an instance of MockLFSStore.
+type LFSStoreCreateLockFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int64
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *db.LFSLock
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreCreateLockFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreCreateLockFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LFSStoreCreateObjectFunc describes the behavior when the CreateObject
+// method of the parent MockLFSStore instance is invoked.
+type LFSStoreCreateObjectFunc struct {
+	defaultHook func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error
+	hooks       []func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error
+	history     []LFSStoreCreateObjectFuncCall
+	mutex       sync.Mutex
+}
+
+// CreateObject delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockLFSStore) CreateObject(v0 context.Context, v1 int64, v2 lfsutil.OID, v3 int64, v4 lfsutil.Storage) error {
+	r0 := m.CreateObjectFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.CreateObjectFunc.appendCall(LFSStoreCreateObjectFuncCall{v0, v1, v2, v3, v4, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the CreateObject method
+// of the parent MockLFSStore instance is invoked and the hook queue is
+// empty.
+func (f *LFSStoreCreateObjectFunc) SetDefaultHook(hook func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CreateObject method of the parent MockLFSStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *LFSStoreCreateObjectFunc) PushHook(hook func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreCreateObjectFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreCreateObjectFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error {
+		return r0
+	})
+}
+
+func (f *LFSStoreCreateObjectFunc) nextHook() func(context.Context, int64, lfsutil.OID, int64, lfsutil.Storage) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreCreateObjectFunc) appendCall(r0 LFSStoreCreateObjectFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreCreateObjectFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreCreateObjectFunc) History() []LFSStoreCreateObjectFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreCreateObjectFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreCreateObjectFuncCall is an object that describes an invocation of
+// method CreateObject on an instance of MockLFSStore.
+type LFSStoreCreateObjectFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 lfsutil.OID
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int64
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 lfsutil.Storage
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreCreateObjectFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreCreateObjectFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// LFSStoreDeleteLockByIDFunc describes the behavior when the DeleteLockByID
+// method of the parent MockLFSStore instance is invoked.
+type LFSStoreDeleteLockByIDFunc struct {
+	defaultHook func(context.Context, int64, int64) error
+	hooks       []func(context.Context, int64, int64) error
+	history     []LFSStoreDeleteLockByIDFuncCall
+	mutex       sync.Mutex
+}
+
+// DeleteLockByID delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLFSStore) DeleteLockByID(v0 context.Context, v1 int64, v2 int64) error {
+	r0 := m.DeleteLockByIDFunc.nextHook()(v0, v1, v2)
+	m.DeleteLockByIDFunc.appendCall(LFSStoreDeleteLockByIDFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the DeleteLockByID
+// method of the parent MockLFSStore instance is invoked and the hook queue
+// is empty.
+func (f *LFSStoreDeleteLockByIDFunc) SetDefaultHook(hook func(context.Context, int64, int64) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DeleteLockByID method of the parent MockLFSStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LFSStoreDeleteLockByIDFunc) PushHook(hook func(context.Context, int64, int64) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreDeleteLockByIDFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64, int64) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreDeleteLockByIDFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64, int64) error {
+		return r0
+	})
+}
+
+func (f *LFSStoreDeleteLockByIDFunc) nextHook() func(context.Context, int64, int64) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreDeleteLockByIDFunc) appendCall(r0 LFSStoreDeleteLockByIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreDeleteLockByIDFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreDeleteLockByIDFunc) History() []LFSStoreDeleteLockByIDFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreDeleteLockByIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreDeleteLockByIDFuncCall is an object that describes an invocation
+// of method DeleteLockByID on an instance of MockLFSStore.
+type LFSStoreDeleteLockByIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int64
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreDeleteLockByIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreDeleteLockByIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// LFSStoreDeleteObjectFunc describes the behavior when the DeleteObject
+// method of the parent MockLFSStore instance is invoked.
+type LFSStoreDeleteObjectFunc struct {
+	defaultHook func(context.Context, int64, lfsutil.OID) error
+	hooks       []func(context.Context, int64, lfsutil.OID) error
+	history     []LFSStoreDeleteObjectFuncCall
+	mutex       sync.Mutex
+}
+
+// DeleteObject delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockLFSStore) DeleteObject(v0 context.Context, v1 int64, v2 lfsutil.OID) error {
+	r0 := m.DeleteObjectFunc.nextHook()(v0, v1, v2)
+	m.DeleteObjectFunc.appendCall(LFSStoreDeleteObjectFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the DeleteObject method
+// of the parent MockLFSStore instance is invoked and the hook queue is
+// empty.
+func (f *LFSStoreDeleteObjectFunc) SetDefaultHook(hook func(context.Context, int64, lfsutil.OID) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DeleteObject method of the parent MockLFSStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *LFSStoreDeleteObjectFunc) PushHook(hook func(context.Context, int64, lfsutil.OID) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreDeleteObjectFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64, lfsutil.OID) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreDeleteObjectFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64, lfsutil.OID) error {
+		return r0
+	})
+}
+
+func (f *LFSStoreDeleteObjectFunc) nextHook() func(context.Context, int64, lfsutil.OID) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreDeleteObjectFunc) appendCall(r0 LFSStoreDeleteObjectFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreDeleteObjectFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreDeleteObjectFunc) History() []LFSStoreDeleteObjectFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreDeleteObjectFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreDeleteObjectFuncCall is an object that describes an invocation of
+// method DeleteObject on an instance of MockLFSStore.
+type LFSStoreDeleteObjectFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 lfsutil.OID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreDeleteObjectFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreDeleteObjectFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// LFSStoreGetLockByIDFunc describes the behavior when the GetLockByID
+// method of the parent MockLFSStore instance is invoked.
+type LFSStoreGetLockByIDFunc struct {
+	defaultHook func(context.Context, int64, int64) (*db.LFSLock, error)
+	hooks       []func(context.Context, int64, int64) (*db.LFSLock, error)
+	history     []LFSStoreGetLockByIDFuncCall
+	mutex       sync.Mutex
+}
+
+// GetLockByID delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockLFSStore) GetLockByID(v0 context.Context, v1 int64, v2 int64) (*db.LFSLock, error) {
+	r0, r1 := m.GetLockByIDFunc.nextHook()(v0, v1, v2)
+	m.GetLockByIDFunc.appendCall(LFSStoreGetLockByIDFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetLockByID method
+// of the parent MockLFSStore instance is invoked and the hook queue is
+// empty.
+func (f *LFSStoreGetLockByIDFunc) SetDefaultHook(hook func(context.Context, int64, int64) (*db.LFSLock, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetLockByID method of the parent MockLFSStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *LFSStoreGetLockByIDFunc) PushHook(hook func(context.Context, int64, int64) (*db.LFSLock, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreGetLockByIDFunc) SetDefaultReturn(r0 *db.LFSLock, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, int64) (*db.LFSLock, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreGetLockByIDFunc) PushReturn(r0 *db.LFSLock, r1 error) {
+	f.PushHook(func(context.Context, int64, int64) (*db.LFSLock, error) {
+		return r0, r1
+	})
+}
+
+func (f *LFSStoreGetLockByIDFunc) nextHook() func(context.Context, int64, int64) (*db.LFSLock, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreGetLockByIDFunc) appendCall(r0 LFSStoreGetLockByIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreGetLockByIDFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreGetLockByIDFunc) History() []LFSStoreGetLockByIDFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreGetLockByIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreGetLockByIDFuncCall is an object that describes an invocation of
+// method GetLockByID on an instance of MockLFSStore.
+type LFSStoreGetLockByIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int64
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *db.LFSLock
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreGetLockByIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreGetLockByIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LFSStoreGetLockByPathFunc describes the behavior when the GetLockByPath
+// method of the parent MockLFSStore instance is invoked.
+type LFSStoreGetLockByPathFunc struct {
+	defaultHook func(context.Context, int64, string) (*db.LFSLock, error)
+	hooks       []func(context.Context, int64, string) (*db.LFSLock, error)
+	history     []LFSStoreGetLockByPathFuncCall
+	mutex       sync.Mutex
+}
+
+// GetLockByPath delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockLFSStore) GetLockByPath(v0 context.Context, v1 int64, v2 string) (*db.LFSLock, error) {
+	r0, r1 := m.GetLockByPathFunc.nextHook()(v0, v1, v2)
+	m.GetLockByPathFunc.appendCall(LFSStoreGetLockByPathFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetLockByPath method
+// of the parent MockLFSStore instance is invoked and the hook queue is
+// empty.
+func (f *LFSStoreGetLockByPathFunc) SetDefaultHook(hook func(context.Context, int64, string) (*db.LFSLock, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetLockByPath method of the parent MockLFSStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *LFSStoreGetLockByPathFunc) PushHook(hook func(context.Context, int64, string) (*db.LFSLock, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreGetLockByPathFunc) SetDefaultReturn(r0 *db.LFSLock, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, string) (*db.LFSLock, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreGetLockByPathFunc) PushReturn(r0 *db.LFSLock, r1 error) {
+	f.PushHook(func(context.Context, int64, string) (*db.LFSLock, error) {
+		return r0, r1
+	})
+}
+
+func (f *LFSStoreGetLockByPathFunc) nextHook() func(context.Context, int64, string) (*db.LFSLock, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreGetLockByPathFunc) appendCall(r0 LFSStoreGetLockByPathFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreGetLockByPathFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreGetLockByPathFunc) History() []LFSStoreGetLockByPathFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreGetLockByPathFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreGetLockByPathFuncCall is an object that describes an invocation
+// of method GetLockByPath on an instance of MockLFSStore.
+type LFSStoreGetLockByPathFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *db.LFSLock
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreGetLockByPathFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreGetLockByPathFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LFSStoreGetObjectByOIDFunc describes the behavior when the GetObjectByOID
+// method of the parent MockLFSStore instance is invoked.
+type LFSStoreGetObjectByOIDFunc struct {
+	defaultHook func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error)
+	hooks       []func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error)
+	history     []LFSStoreGetObjectByOIDFuncCall
+	mutex       sync.Mutex
+}
+
+// GetObjectByOID delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLFSStore) GetObjectByOID(v0 context.Context, v1 int64, v2 lfsutil.OID) (*db.LFSObject, error) {
+	r0, r1 := m.GetObjectByOIDFunc.nextHook()(v0, v1, v2)
+	m.GetObjectByOIDFunc.appendCall(LFSStoreGetObjectByOIDFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetObjectByOID
+// method of the parent MockLFSStore instance is invoked and the hook queue
+// is empty.
+func (f *LFSStoreGetObjectByOIDFunc) SetDefaultHook(hook func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetObjectByOID method of the parent MockLFSStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LFSStoreGetObjectByOIDFunc) PushHook(hook func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreGetObjectByOIDFunc) SetDefaultReturn(r0 *db.LFSObject, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreGetObjectByOIDFunc) PushReturn(r0 *db.LFSObject, r1 error) {
+	f.PushHook(func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error) {
+		return r0, r1
+	})
+}
+
+func (f *LFSStoreGetObjectByOIDFunc) nextHook() func(context.Context, int64, lfsutil.OID) (*db.LFSObject, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreGetObjectByOIDFunc) appendCall(r0 LFSStoreGetObjectByOIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreGetObjectByOIDFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreGetObjectByOIDFunc) History() []LFSStoreGetObjectByOIDFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreGetObjectByOIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreGetObjectByOIDFuncCall is an object that describes an invocation
+// of method GetObjectByOID on an instance of MockLFSStore.
+type LFSStoreGetObjectByOIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 lfsutil.OID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *db.LFSObject
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreGetObjectByOIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreGetObjectByOIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LFSStoreGetObjectsByOIDsFunc describes the behavior when the
+// GetObjectsByOIDs method of the parent MockLFSStore instance is invoked.
+type LFSStoreGetObjectsByOIDsFunc struct {
+	defaultHook func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error)
+	hooks       []func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error)
+	history     []LFSStoreGetObjectsByOIDsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetObjectsByOIDs delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLFSStore) GetObjectsByOIDs(v0 context.Context, v1 int64, v2 ...lfsutil.OID) ([]*db.LFSObject, error) {
+	r0, r1 := m.GetObjectsByOIDsFunc.nextHook()(v0, v1, v2...)
+	m.GetObjectsByOIDsFunc.appendCall(LFSStoreGetObjectsByOIDsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetObjectsByOIDs
+// method of the parent MockLFSStore instance is invoked and the hook queue
+// is empty.
+func (f *LFSStoreGetObjectsByOIDsFunc) SetDefaultHook(hook func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetObjectsByOIDs method of the parent MockLFSStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LFSStoreGetObjectsByOIDsFunc) PushHook(hook func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreGetObjectsByOIDsFunc) SetDefaultReturn(r0 []*db.LFSObject, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreGetObjectsByOIDsFunc) PushReturn(r0 []*db.LFSObject, r1 error) {
+	f.PushHook(func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error) {
+		return r0, r1
+	})
+}
+
+func (f *LFSStoreGetObjectsByOIDsFunc) nextHook() func(context.Context, int64, ...lfsutil.OID) ([]*db.LFSObject, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreGetObjectsByOIDsFunc) appendCall(r0 LFSStoreGetObjectsByOIDsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreGetObjectsByOIDsFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreGetObjectsByOIDsFunc) History() []LFSStoreGetObjectsByOIDsFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreGetObjectsByOIDsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreGetObjectsByOIDsFuncCall is an object that describes an
+// invocation of method GetObjectsByOIDs on an instance of MockLFSStore.
+type LFSStoreGetObjectsByOIDsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is a slice containing the values of the variadic arguments
+	// passed to this method invocation.
+	Arg2 []lfsutil.OID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*db.LFSObject
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation. The variadic slice argument is flattened in this array such
+// that one positional argument and three variadic arguments would result in
+// a slice of four, not two.
+func (c LFSStoreGetObjectsByOIDsFuncCall) Args() []interface{} {
+	trailing := []interface{}{}
+	for _, val := range c.Arg2 {
+		trailing = append(trailing, val)
+	}
+
+	return append([]interface{}{c.Arg0, c.Arg1}, trailing...)
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreGetObjectsByOIDsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LFSStoreGetObjectsByRepoIDFunc describes the behavior when the
+// GetObjectsByRepoID method of the parent MockLFSStore instance is invoked.
+type LFSStoreGetObjectsByRepoIDFunc struct {
+	defaultHook func(context.Context, int64) ([]*db.LFSObject, error)
+	hooks       []func(context.Context, int64) ([]*db.LFSObject, error)
+	history     []LFSStoreGetObjectsByRepoIDFuncCall
+	mutex       sync.Mutex
+}
+
+// GetObjectsByRepoID delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLFSStore) GetObjectsByRepoID(v0 context.Context, v1 int64) ([]*db.LFSObject, error) {
+	r0, r1 := m.GetObjectsByRepoIDFunc.nextHook()(v0, v1)
+	m.GetObjectsByRepoIDFunc.appendCall(LFSStoreGetObjectsByRepoIDFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetObjectsByRepoID
+// method of the parent MockLFSStore instance is invoked and the hook queue
+// is empty.
+func (f *LFSStoreGetObjectsByRepoIDFunc) SetDefaultHook(hook func(context.Context, int64) ([]*db.LFSObject, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetObjectsByRepoID method of the parent MockLFSStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LFSStoreGetObjectsByRepoIDFunc) PushHook(hook func(context.Context, int64) ([]*db.LFSObject, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreGetObjectsByRepoIDFunc) SetDefaultReturn(r0 []*db.LFSObject, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64) ([]*db.LFSObject, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreGetObjectsByRepoIDFunc) PushReturn(r0 []*db.LFSObject, r1 error) {
+	f.PushHook(func(context.Context, int64) ([]*db.LFSObject, error) {
+		return r0, r1
+	})
+}
+
+func (f *LFSStoreGetObjectsByRepoIDFunc) nextHook() func(context.Context, int64) ([]*db.LFSObject, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreGetObjectsByRepoIDFunc) appendCall(r0 LFSStoreGetObjectsByRepoIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreGetObjectsByRepoIDFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreGetObjectsByRepoIDFunc) History() []LFSStoreGetObjectsByRepoIDFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreGetObjectsByRepoIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreGetObjectsByRepoIDFuncCall is an object that describes an
+// invocation of method GetObjectsByRepoID on an instance of MockLFSStore.
+type LFSStoreGetObjectsByRepoIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*db.LFSObject
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreGetObjectsByRepoIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreGetObjectsByRepoIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LFSStoreGetObjectsByStorageFunc describes the behavior when the
+// GetObjectsByStorage method of the parent MockLFSStore instance is
+// invoked.
+type LFSStoreGetObjectsByStorageFunc struct {
+	defaultHook func(context.Context, lfsutil.Storage) ([]*db.LFSObject, error)
+	hooks       []func(context.Context, lfsutil.Storage) ([]*db.LFSObject, error)
+	history     []LFSStoreGetObjectsByStorageFuncCall
+	mutex       sync.Mutex
+}
+
+// GetObjectsByStorage delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLFSStore) GetObjectsByStorage(v0 context.Context, v1 lfsutil.Storage) ([]*db.LFSObject, error) {
+	r0, r1 := m.GetObjectsByStorageFunc.nextHook()(v0, v1)
+	m.GetObjectsByStorageFunc.appendCall(LFSStoreGetObjectsByStorageFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetObjectsByStorage
+// method of the parent MockLFSStore instance is invoked and the hook queue
+// is empty.
+func (f *LFSStoreGetObjectsByStorageFunc) SetDefaultHook(hook func(context.Context, lfsutil.Storage) ([]*db.LFSObject, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetObjectsByStorage method of the parent MockLFSStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *LFSStoreGetObjectsByStorageFunc) PushHook(hook func(context.Context, lfsutil.Storage) ([]*db.LFSObject, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreGetObjectsByStorageFunc) SetDefaultReturn(r0 []*db.LFSObject, r1 error) {
+	f.SetDefaultHook(func(context.Context, lfsutil.Storage) ([]*db.LFSObject, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreGetObjectsByStorageFunc) PushReturn(r0 []*db.LFSObject, r1 error) {
+	f.PushHook(func(context.Context, lfsutil.Storage) ([]*db.LFSObject, error) {
+		return r0, r1
+	})
+}
+
+func (f *LFSStoreGetObjectsByStorageFunc) nextHook() func(context.Context, lfsutil.Storage) ([]*db.LFSObject, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreGetObjectsByStorageFunc) appendCall(r0 LFSStoreGetObjectsByStorageFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreGetObjectsByStorageFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreGetObjectsByStorageFunc) History() []LFSStoreGetObjectsByStorageFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreGetObjectsByStorageFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreGetObjectsByStorageFuncCall is an object that describes an
+// invocation of method GetObjectsByStorage on an instance of MockLFSStore.
+type LFSStoreGetObjectsByStorageFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 lfsutil.Storage
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*db.LFSObject
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreGetObjectsByStorageFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreGetObjectsByStorageFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LFSStoreGetOrphanedObjectsFunc describes the behavior when the
+// GetOrphanedObjects method of the parent MockLFSStore instance is invoked.
+type LFSStoreGetOrphanedObjectsFunc struct {
+	defaultHook func(context.Context, time.Time) ([]*db.LFSObject, error)
+	hooks       []func(context.Context, time.Time) ([]*db.LFSObject, error)
+	history     []LFSStoreGetOrphanedObjectsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetOrphanedObjects delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLFSStore) GetOrphanedObjects(v0 context.Context, v1 time.Time) ([]*db.LFSObject, error) {
+	r0, r1 := m.GetOrphanedObjectsFunc.nextHook()(v0, v1)
+	m.GetOrphanedObjectsFunc.appendCall(LFSStoreGetOrphanedObjectsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetOrphanedObjects
+// method of the parent MockLFSStore instance is invoked and the hook queue
+// is empty.
+func (f *LFSStoreGetOrphanedObjectsFunc) SetDefaultHook(hook func(context.Context, time.Time) ([]*db.LFSObject, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetOrphanedObjects method of the parent MockLFSStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LFSStoreGetOrphanedObjectsFunc) PushHook(hook func(context.Context, time.Time) ([]*db.LFSObject, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreGetOrphanedObjectsFunc) SetDefaultReturn(r0 []*db.LFSObject, r1 error) {
+	f.SetDefaultHook(func(context.Context, time.Time) ([]*db.LFSObject, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreGetOrphanedObjectsFunc) PushReturn(r0 []*db.LFSObject, r1 error) {
+	f.PushHook(func(context.Context, time.Time) ([]*db.LFSObject, error) {
+		return r0, r1
+	})
+}
+
+func (f *LFSStoreGetOrphanedObjectsFunc) nextHook() func(context.Context, time.Time) ([]*db.LFSObject, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreGetOrphanedObjectsFunc) appendCall(r0 LFSStoreGetOrphanedObjectsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreGetOrphanedObjectsFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreGetOrphanedObjectsFunc) History() []LFSStoreGetOrphanedObjectsFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreGetOrphanedObjectsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreGetOrphanedObjectsFuncCall is an object that describes an
+// invocation of method GetOrphanedObjects on an instance of MockLFSStore.
+type LFSStoreGetOrphanedObjectsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 time.Time
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*db.LFSObject
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreGetOrphanedObjectsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreGetOrphanedObjectsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LFSStoreListLocksFunc describes the behavior when the ListLocks method of
+// the parent MockLFSStore instance is invoked.
+type LFSStoreListLocksFunc struct {
+	defaultHook func(context.Context, int64, db.ListLFSLocksOptions) ([]*db.LFSLock, error)
+	hooks       []func(context.Context, int64, db.ListLFSLocksOptions) ([]*db.LFSLock, error)
+	history     []LFSStoreListLocksFuncCall
+	mutex       sync.Mutex
+}
+
+// ListLocks delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockLFSStore) ListLocks(v0 context.Context, v1 int64, v2 db.ListLFSLocksOptions) ([]*db.LFSLock, error) {
+	r0, r1 := m.ListLocksFunc.nextHook()(v0, v1, v2)
+	m.ListLocksFunc.appendCall(LFSStoreListLocksFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListLocks method of
+// the parent MockLFSStore instance is invoked and the hook queue is empty.
+func (f *LFSStoreListLocksFunc) SetDefaultHook(hook func(context.Context, int64, db.ListLFSLocksOptions) ([]*db.LFSLock, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListLocks method of the parent MockLFSStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *LFSStoreListLocksFunc) PushHook(hook func(context.Context, int64, db.ListLFSLocksOptions) ([]*db.LFSLock, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreListLocksFunc) SetDefaultReturn(r0 []*db.LFSLock, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, db.ListLFSLocksOptions) ([]*db.LFSLock, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreListLocksFunc) PushReturn(r0 []*db.LFSLock, r1 error) {
+	f.PushHook(func(context.Context, int64, db.ListLFSLocksOptions) ([]*db.LFSLock, error) {
+		return r0, r1
+	})
+}
+
+func (f *LFSStoreListLocksFunc) nextHook() func(context.Context, int64, db.ListLFSLocksOptions) ([]*db.LFSLock, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreListLocksFunc) appendCall(r0 LFSStoreListLocksFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreListLocksFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreListLocksFunc) History() []LFSStoreListLocksFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreListLocksFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreListLocksFuncCall is an object that describes an invocation of
+// method ListLocks on an instance of MockLFSStore.
+type LFSStoreListLocksFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 db.ListLFSLocksOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*db.LFSLock
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreListLocksFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreListLocksFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LFSStoreTotalSizeByOwnerIDFunc describes the behavior when the
+// TotalSizeByOwnerID method of the parent MockLFSStore instance is invoked.
+type LFSStoreTotalSizeByOwnerIDFunc struct {
+	defaultHook func(context.Context, int64) (int64, error)
+	hooks       []func(context.Context, int64) (int64, error)
+	history     []LFSStoreTotalSizeByOwnerIDFuncCall
+	mutex       sync.Mutex
+}
+
+// TotalSizeByOwnerID delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLFSStore) TotalSizeByOwnerID(v0 context.Context, v1 int64) (int64, error) {
+	r0, r1 := m.TotalSizeByOwnerIDFunc.nextHook()(v0, v1)
+	m.TotalSizeByOwnerIDFunc.appendCall(LFSStoreTotalSizeByOwnerIDFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the TotalSizeByOwnerID
+// method of the parent MockLFSStore instance is invoked and the hook queue
+// is empty.
+func (f *LFSStoreTotalSizeByOwnerIDFunc) SetDefaultHook(hook func(context.Context, int64) (int64, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// TotalSizeByOwnerID method of the parent MockLFSStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LFSStoreTotalSizeByOwnerIDFunc) PushHook(hook func(context.Context, int64) (int64, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreTotalSizeByOwnerIDFunc) SetDefaultReturn(r0 int64, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64) (int64, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreTotalSizeByOwnerIDFunc) PushReturn(r0 int64, r1 error) {
+	f.PushHook(func(context.Context, int64) (int64, error) {
+		return r0, r1
+	})
+}
+
+func (f *LFSStoreTotalSizeByOwnerIDFunc) nextHook() func(context.Context, int64) (int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreTotalSizeByOwnerIDFunc) appendCall(r0 LFSStoreTotalSizeByOwnerIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreTotalSizeByOwnerIDFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreTotalSizeByOwnerIDFunc) History() []LFSStoreTotalSizeByOwnerIDFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreTotalSizeByOwnerIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreTotalSizeByOwnerIDFuncCall is an object that describes an
+// invocation of method TotalSizeByOwnerID on an instance of MockLFSStore.
+type LFSStoreTotalSizeByOwnerIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int64
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreTotalSizeByOwnerIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreTotalSizeByOwnerIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LFSStoreTotalSizeByRepoIDFunc describes the behavior when the
+// TotalSizeByRepoID method of the parent MockLFSStore instance is invoked.
+type LFSStoreTotalSizeByRepoIDFunc struct {
+	defaultHook func(context.Context, int64) (int64, error)
+	hooks       []func(context.Context, int64) (int64, error)
+	history     []LFSStoreTotalSizeByRepoIDFuncCall
+	mutex       sync.Mutex
+}
+
+// TotalSizeByRepoID delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLFSStore) TotalSizeByRepoID(v0 context.Context, v1 int64) (int64, error) {
+	r0, r1 := m.TotalSizeByRepoIDFunc.nextHook()(v0, v1)
+	m.TotalSizeByRepoIDFunc.appendCall(LFSStoreTotalSizeByRepoIDFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the TotalSizeByRepoID
+// method of the parent MockLFSStore instance is invoked and the hook queue
+// is empty.
+func (f *LFSStoreTotalSizeByRepoIDFunc) SetDefaultHook(hook func(context.Context, int64) (int64, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// TotalSizeByRepoID method of the parent MockLFSStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LFSStoreTotalSizeByRepoIDFunc) PushHook(hook func(context.Context, int64) (int64, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreTotalSizeByRepoIDFunc) SetDefaultReturn(r0 int64, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64) (int64, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreTotalSizeByRepoIDFunc) PushReturn(r0 int64, r1 error) {
+	f.PushHook(func(context.Context, int64) (int64, error) {
+		return r0, r1
+	})
+}
+
+func (f *LFSStoreTotalSizeByRepoIDFunc) nextHook() func(context.Context, int64) (int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreTotalSizeByRepoIDFunc) appendCall(r0 LFSStoreTotalSizeByRepoIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreTotalSizeByRepoIDFuncCall objects
+// describing the invocations of this function.
+func (f *LFSStoreTotalSizeByRepoIDFunc) History() []LFSStoreTotalSizeByRepoIDFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreTotalSizeByRepoIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreTotalSizeByRepoIDFuncCall is an object that describes an
+// invocation of method TotalSizeByRepoID on an instance of MockLFSStore.
+type LFSStoreTotalSizeByRepoIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int64
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreTotalSizeByRepoIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreTotalSizeByRepoIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// LFSStoreUpdateObjectsStorageFunc describes the behavior when the
+// UpdateObjectsStorage method of the parent MockLFSStore instance is
+// invoked.
+type LFSStoreUpdateObjectsStorageFunc struct {
+	defaultHook func(context.Context, lfsutil.OID, lfsutil.Storage, lfsutil.Storage) error
+	hooks       []func(context.Context, lfsutil.OID, lfsutil.Storage, lfsutil.Storage) error
+	history     []LFSStoreUpdateObjectsStorageFuncCall
+	mutex       sync.Mutex
+}
+
+// UpdateObjectsStorage delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLFSStore) UpdateObjectsStorage(v0 context.Context, v1 lfsutil.OID, v2 lfsutil.Storage, v3 lfsutil.Storage) error {
+	r0 := m.UpdateObjectsStorageFunc.nextHook()(v0, v1, v2, v3)
+	m.UpdateObjectsStorageFunc.appendCall(LFSStoreUpdateObjectsStorageFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the UpdateObjectsStorage
+// method of the parent MockLFSStore instance is invoked and the hook queue
+// is empty.
+func (f *LFSStoreUpdateObjectsStorageFunc) SetDefaultHook(hook func(context.Context, lfsutil.OID, lfsutil.Storage, lfsutil.Storage) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UpdateObjectsStorage method of the parent MockLFSStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *LFSStoreUpdateObjectsStorageFunc) PushHook(hook func(context.Context, lfsutil.OID, lfsutil.Storage, lfsutil.Storage) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LFSStoreUpdateObjectsStorageFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, lfsutil.OID, lfsutil.Storage, lfsutil.Storage) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LFSStoreUpdateObjectsStorageFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, lfsutil.OID, lfsutil.Storage, lfsutil.Storage) error {
+		return r0
+	})
+}
+
+func (f *LFSStoreUpdateObjectsStorageFunc) nextHook() func(context.Context, lfsutil.OID, lfsutil.Storage, lfsutil.Storage) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LFSStoreUpdateObjectsStorageFunc) appendCall(r0 LFSStoreUpdateObjectsStorageFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LFSStoreUpdateObjectsStorageFuncCall
+// objects describing the invocations of this function.
+func (f *LFSStoreUpdateObjectsStorageFunc) History() []LFSStoreUpdateObjectsStorageFuncCall {
+	f.mutex.Lock()
+	history := make([]LFSStoreUpdateObjectsStorageFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LFSStoreUpdateObjectsStorageFuncCall is an object that describes an
+// invocation of method UpdateObjectsStorage on an instance of MockLFSStore.
+type LFSStoreUpdateObjectsStorageFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 lfsutil.OID
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 lfsutil.Storage
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 lfsutil.Storage
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LFSStoreUpdateObjectsStorageFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LFSStoreUpdateObjectsStorageFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// MockPermsStore is a mock implementation of the PermsStore interface (from
+// the package gogs.io/gogs/internal/db) used for unit testing.
+type MockPermsStore struct {
+	// AccessModeFunc is an instance of a mock function object controlling
+	// the behavior of the method AccessMode.
+	AccessModeFunc *PermsStoreAccessModeFunc
+	// AuthorizeFunc is an instance of a mock function object controlling
+	// the behavior of the method Authorize.
+	AuthorizeFunc *PermsStoreAuthorizeFunc
+	// SetRepoPermsFunc is an instance of a mock function object controlling
+	// the behavior of the method SetRepoPerms.
+	SetRepoPermsFunc *PermsStoreSetRepoPermsFunc
+}
+
+// NewMockPermsStore creates a new mock of the PermsStore interface. All
+// methods return zero values for all results, unless overwritten.
+func NewMockPermsStore() *MockPermsStore {
+	return &MockPermsStore{
+		AccessModeFunc: &PermsStoreAccessModeFunc{
+			defaultHook: func(context.Context, int64, int64, db.AccessModeOptions) (r0 db.AccessMode) {
+				return
+			},
+		},
+		AuthorizeFunc: &PermsStoreAuthorizeFunc{
+			defaultHook: func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) (r0 bool) {
+				return
+			},
+		},
+		SetRepoPermsFunc: &PermsStoreSetRepoPermsFunc{
+			defaultHook: func(context.Context, int64, map[int64]db.AccessMode) (r0 error) {
+				return
+			},
+		},
+	}
 }
 
 // NewStrictMockPermsStore creates a new mock of the PermsStore interface.
 // All methods panic on invocation, unless overwritten.
-func NewStrictMockPermsStore() *MockPermsStore {
-	return &MockPermsStore{
-		AccessModeFunc: &PermsStoreAccessModeFunc{
-			defaultHook: func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode {
-				panic("unexpected invocation of MockPermsStore.AccessMode")
+func NewStrictMockPermsStore() *MockPermsStore {
+	return &MockPermsStore{
+		AccessModeFunc: &PermsStoreAccessModeFunc{
+			defaultHook: func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode {
+				panic("unexpected invocation of MockPermsStore.AccessMode")
+			},
+		},
+		AuthorizeFunc: &PermsStoreAuthorizeFunc{
+			defaultHook: func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool {
+				panic("unexpected invocation of MockPermsStore.Authorize")
+			},
+		},
+		SetRepoPermsFunc: &PermsStoreSetRepoPermsFunc{
+			defaultHook: func(context.Context, int64, map[int64]db.AccessMode) error {
+				panic("unexpected invocation of MockPermsStore.SetRepoPerms")
+			},
+		},
+	}
+}
+
+// NewMockPermsStoreFrom creates a new mock of the MockPermsStore interface.
+// All methods delegate to the given implementation, unless overwritten.
+func NewMockPermsStoreFrom(i db.PermsStore) *MockPermsStore {
+	return &MockPermsStore{
+		AccessModeFunc: &PermsStoreAccessModeFunc{
+			defaultHook: i.AccessMode,
+		},
+		AuthorizeFunc: &PermsStoreAuthorizeFunc{
+			defaultHook: i.Authorize,
+		},
+		SetRepoPermsFunc: &PermsStoreSetRepoPermsFunc{
+			defaultHook: i.SetRepoPerms,
+		},
+	}
+}
+
+// PermsStoreAccessModeFunc describes the behavior when the AccessMode
+// method of the parent MockPermsStore instance is invoked.
+type PermsStoreAccessModeFunc struct {
+	defaultHook func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode
+	hooks       []func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode
+	history     []PermsStoreAccessModeFuncCall
+	mutex       sync.Mutex
+}
+
+// AccessMode delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockPermsStore) AccessMode(v0 context.Context, v1 int64, v2 int64, v3 db.AccessModeOptions) db.AccessMode {
+	r0 := m.AccessModeFunc.nextHook()(v0, v1, v2, v3)
+	m.AccessModeFunc.appendCall(PermsStoreAccessModeFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the AccessMode method of
+// the parent MockPermsStore instance is invoked and the hook queue is
+// empty.
+func (f *PermsStoreAccessModeFunc) SetDefaultHook(hook func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// AccessMode method of the parent MockPermsStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *PermsStoreAccessModeFunc) PushHook(hook func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *PermsStoreAccessModeFunc) SetDefaultReturn(r0 db.AccessMode) {
+	f.SetDefaultHook(func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *PermsStoreAccessModeFunc) PushReturn(r0 db.AccessMode) {
+	f.PushHook(func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode {
+		return r0
+	})
+}
+
+func (f *PermsStoreAccessModeFunc) nextHook() func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *PermsStoreAccessModeFunc) appendCall(r0 PermsStoreAccessModeFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of PermsStoreAccessModeFuncCall objects
+// describing the invocations of this function.
+func (f *PermsStoreAccessModeFunc) History() []PermsStoreAccessModeFuncCall {
+	f.mutex.Lock()
+	history := make([]PermsStoreAccessModeFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// PermsStoreAccessModeFuncCall is an object that describes an invocation of
+// method AccessMode on an instance of MockPermsStore.
+type PermsStoreAccessModeFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int64
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 db.AccessModeOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 db.AccessMode
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c PermsStoreAccessModeFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c PermsStoreAccessModeFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// PermsStoreAuthorizeFunc describes the behavior when the Authorize method
+// of the parent MockPermsStore instance is invoked.
+type PermsStoreAuthorizeFunc struct {
+	defaultHook func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool
+	hooks       []func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool
+	history     []PermsStoreAuthorizeFuncCall
+	mutex       sync.Mutex
+}
+
+// Authorize delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockPermsStore) Authorize(v0 context.Context, v1 int64, v2 int64, v3 db.AccessMode, v4 db.AccessModeOptions) bool {
+	r0 := m.AuthorizeFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.AuthorizeFunc.appendCall(PermsStoreAuthorizeFuncCall{v0, v1, v2, v3, v4, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Authorize method of
+// the parent MockPermsStore instance is invoked and the hook queue is
+// empty.
+func (f *PermsStoreAuthorizeFunc) SetDefaultHook(hook func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Authorize method of the parent MockPermsStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *PermsStoreAuthorizeFunc) PushHook(hook func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *PermsStoreAuthorizeFunc) SetDefaultReturn(r0 bool) {
+	f.SetDefaultHook(func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *PermsStoreAuthorizeFunc) PushReturn(r0 bool) {
+	f.PushHook(func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool {
+		return r0
+	})
+}
+
+func (f *PermsStoreAuthorizeFunc) nextHook() func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *PermsStoreAuthorizeFunc) appendCall(r0 PermsStoreAuthorizeFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of PermsStoreAuthorizeFuncCall objects
+// describing the invocations of this function.
+func (f *PermsStoreAuthorizeFunc) History() []PermsStoreAuthorizeFuncCall {
+	f.mutex.Lock()
+	history := make([]PermsStoreAuthorizeFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// PermsStoreAuthorizeFuncCall is an object that describes an invocation of
+// method Authorize on an instance of MockPermsStore.
+type PermsStoreAuthorizeFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int64
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 db.AccessMode
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 db.AccessModeOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c PermsStoreAuthorizeFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c PermsStoreAuthorizeFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// PermsStoreSetRepoPermsFunc describes the behavior when the SetRepoPerms
+// method of the parent MockPermsStore instance is invoked.
+type PermsStoreSetRepoPermsFunc struct {
+	defaultHook func(context.Context, int64, map[int64]db.AccessMode) error
+	hooks       []func(context.Context, int64, map[int64]db.AccessMode) error
+	history     []PermsStoreSetRepoPermsFuncCall
+	mutex       sync.Mutex
+}
+
+// SetRepoPerms delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockPermsStore) SetRepoPerms(v0 context.Context, v1 int64, v2 map[int64]db.AccessMode) error {
+	r0 := m.SetRepoPermsFunc.nextHook()(v0, v1, v2)
+	m.SetRepoPermsFunc.appendCall(PermsStoreSetRepoPermsFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the SetRepoPerms method
+// of the parent MockPermsStore instance is invoked and the hook queue is
+// empty.
+func (f *PermsStoreSetRepoPermsFunc) SetDefaultHook(hook func(context.Context, int64, map[int64]db.AccessMode) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetRepoPerms method of the parent MockPermsStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *PermsStoreSetRepoPermsFunc) PushHook(hook func(context.Context, int64, map[int64]db.AccessMode) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *PermsStoreSetRepoPermsFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64, map[int64]db.AccessMode) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *PermsStoreSetRepoPermsFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64, map[int64]db.AccessMode) error {
+		return r0
+	})
+}
+
+func (f *PermsStoreSetRepoPermsFunc) nextHook() func(context.Context, int64, map[int64]db.AccessMode) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *PermsStoreSetRepoPermsFunc) appendCall(r0 PermsStoreSetRepoPermsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of PermsStoreSetRepoPermsFuncCall objects
+// describing the invocations of this function.
+func (f *PermsStoreSetRepoPermsFunc) History() []PermsStoreSetRepoPermsFuncCall {
+	f.mutex.Lock()
+	history := make([]PermsStoreSetRepoPermsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// PermsStoreSetRepoPermsFuncCall is an object that describes an invocation
+// of method SetRepoPerms on an instance of MockPermsStore.
+type PermsStoreSetRepoPermsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 map[int64]db.AccessMode
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c PermsStoreSetRepoPermsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c PermsStoreSetRepoPermsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// MockReposStore is a mock implementation of the ReposStore interface (from
+// the package gogs.io/gogs/internal/db) used for unit testing.
+type MockReposStore struct {
+	// CreateFunc is an instance of a mock function object controlling the
+	// behavior of the method Create.
+	CreateFunc *ReposStoreCreateFunc
+	// GetByNameFunc is an instance of a mock function object controlling
+	// the behavior of the method GetByName.
+	GetByNameFunc *ReposStoreGetByNameFunc
+	// TouchFunc is an instance of a mock function object controlling the
+	// behavior of the method Touch.
+	TouchFunc *ReposStoreTouchFunc
+}
+
+// NewMockReposStore creates a new mock of the ReposStore interface. All
+// methods return zero values for all results, unless overwritten.
+func NewMockReposStore() *MockReposStore {
+	return &MockReposStore{
+		CreateFunc: &ReposStoreCreateFunc{
+			defaultHook: func(context.Context, int64, db.CreateRepoOptions) (r0 *db.Repository, r1 error) {
+				return
+			},
+		},
+		GetByNameFunc: &ReposStoreGetByNameFunc{
+			defaultHook: func(context.Context, int64, string) (r0 *db.Repository, r1 error) {
+				return
+			},
+		},
+		TouchFunc: &ReposStoreTouchFunc{
+			defaultHook: func(context.Context, int64) (r0 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockReposStore creates a new mock of the ReposStore interface.
+// All methods panic on invocation, unless overwritten.
+func NewStrictMockReposStore() *MockReposStore {
+	return &MockReposStore{
+		CreateFunc: &ReposStoreCreateFunc{
+			defaultHook: func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error) {
+				panic("unexpected invocation of MockReposStore.Create")
 			},
 		},
-		AuthorizeFunc: &PermsStoreAuthorizeFunc{
-			defaultHook: func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool {
-				panic("unexpected invocation of MockPermsStore.Authorize")
+		GetByNameFunc: &ReposStoreGetByNameFunc{
+			defaultHook: func(context.Context, int64, string) (*db.Repository, error) {
+				panic("unexpected invocation of MockReposStore.GetByName")
 			},
 		},
-		SetRepoPermsFunc: &PermsStoreSetRepoPermsFunc{
-			defaultHook: func(context.Context, int64, map[int64]db.AccessMode) error {
-				panic("unexpected invocation of MockPermsStore.SetRepoPerms")
+		TouchFunc: &ReposStoreTouchFunc{
+			defaultHook: func(context.Context, int64) error {
+				panic("unexpected invocation of MockReposStore.Touch")
 			},
 		},
 	}
 }
 
-// NewMockPermsStoreFrom creates a new mock of the MockPermsStore interface.
-// All methods delegate to the given implementation, unless overwritten.
-func NewMockPermsStoreFrom(i db.PermsStore) *MockPermsStore {
-	return &MockPermsStore{
-		AccessModeFunc: &PermsStoreAccessModeFunc{
-			defaultHook: i.AccessMode,
-		},
-		AuthorizeFunc: &PermsStoreAuthorizeFunc{
-			defaultHook: i.Authorize,
-		},
-		SetRepoPermsFunc: &PermsStoreSetRepoPermsFunc{
-			defaultHook: i.SetRepoPerms,
-		},
-	}
+// NewMockReposStoreFrom creates a new mock of the MockReposStore interface.
+// All methods delegate to the given implementation, unless overwritten.
+func NewMockReposStoreFrom(i db.ReposStore) *MockReposStore {
+	return &MockReposStore{
+		CreateFunc: &ReposStoreCreateFunc{
+			defaultHook: i.Create,
+		},
+		GetByNameFunc: &ReposStoreGetByNameFunc{
+			defaultHook: i.GetByName,
+		},
+		TouchFunc: &ReposStoreTouchFunc{
+			defaultHook: i.Touch,
+		},
+	}
+}
+
+// ReposStoreCreateFunc describes the behavior when the Create method of the
+// parent MockReposStore instance is invoked.
+type ReposStoreCreateFunc struct {
+	defaultHook func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error)
+	hooks       []func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error)
+	history     []ReposStoreCreateFuncCall
+	mutex       sync.Mutex
+}
+
+// Create delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockReposStore) Create(v0 context.Context, v1 int64, v2 db.CreateRepoOptions) (*db.Repository, error) {
+	r0, r1 := m.CreateFunc.nextHook()(v0, v1, v2)
+	m.CreateFunc.appendCall(ReposStoreCreateFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Create method of the
+// parent MockReposStore instance is invoked and the hook queue is empty.
+func (f *ReposStoreCreateFunc) SetDefaultHook(hook func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Create method of the parent MockReposStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ReposStoreCreateFunc) PushHook(hook func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ReposStoreCreateFunc) SetDefaultReturn(r0 *db.Repository, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ReposStoreCreateFunc) PushReturn(r0 *db.Repository, r1 error) {
+	f.PushHook(func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error) {
+		return r0, r1
+	})
+}
+
+func (f *ReposStoreCreateFunc) nextHook() func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ReposStoreCreateFunc) appendCall(r0 ReposStoreCreateFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ReposStoreCreateFuncCall objects describing
+// the invocations of this function.
+func (f *ReposStoreCreateFunc) History() []ReposStoreCreateFuncCall {
+	f.mutex.Lock()
+	history := make([]ReposStoreCreateFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ReposStoreCreateFuncCall is an object that describes an invocation of
+// method Create on an instance of MockReposStore.
+type ReposStoreCreateFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 db.CreateRepoOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *db.Repository
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ReposStoreCreateFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
-// PermsStoreAccessModeFunc describes the behavior when the AccessMode
-// method of the parent MockPermsStore instance is invoked.
-type PermsStoreAccessModeFunc struct {
-	defaultHook func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode
-	hooks       []func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode
-	history     []PermsStoreAccessModeFuncCall
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ReposStoreCreateFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// ReposStoreGetByNameFunc describes the behavior when the GetByName method
+// of the parent MockReposStore instance is invoked.
+type ReposStoreGetByNameFunc struct {
+	defaultHook func(context.Context, int64, string) (*db.Repository, error)
+	hooks       []func(context.Context, int64, string) (*db.Repository, error)
+	history     []ReposStoreGetByNameFuncCall
 	mutex       sync.Mutex
 }
 
-// AccessMode delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockPermsStore) AccessMode(v0 context.Context, v1 int64, v2 int64, v3 db.AccessModeOptions) db.AccessMode {
-	r0 := m.AccessModeFunc.nextHook()(v0, v1, v2, v3)
-	m.AccessModeFunc.appendCall(PermsStoreAccessModeFuncCall{v0, v1, v2, v3, r0})
-	return r0
+// GetByName delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockReposStore) GetByName(v0 context.Context, v1 int64, v2 string) (*db.Repository, error) {
+	r0, r1 := m.GetByNameFunc.nextHook()(v0, v1, v2)
+	m.GetByNameFunc.appendCall(ReposStoreGetByNameFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the AccessMode method of
-// the parent MockPermsStore instance is invoked and the hook queue is
+// SetDefaultHook sets function that is called when the GetByName method of
+// the parent MockReposStore instance is invoked and the hook queue is
 // empty.
-func (f *PermsStoreAccessModeFunc) SetDefaultHook(hook func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode) {
+func (f *ReposStoreGetByNameFunc) SetDefaultHook(hook func(context.Context, int64, string) (*db.Repository, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// AccessMode method of the parent MockPermsStore instance invokes the hook
+// GetByName method of the parent MockReposStore instance invokes the hook
 // at the front of the queue and discards it. After the queue is empty, the
 // default hook function is invoked for any future action.
-func (f *PermsStoreAccessModeFunc) PushHook(hook func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode) {
+func (f *ReposStoreGetByNameFunc) PushHook(hook func(context.Context, int64, string) (*db.Repository, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1192,20 +3595,20 @@ func (f *PermsStoreAccessModeFunc) PushHook(hook func(context.Context, int64, in
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *PermsStoreAccessModeFunc) SetDefaultReturn(r0 db.AccessMode) {
-	f.SetDefaultHook(func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode {
-		return r0
+func (f *ReposStoreGetByNameFunc) SetDefaultReturn(r0 *db.Repository, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, string) (*db.Repository, error) {
+		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *PermsStoreAccessModeFunc) PushReturn(r0 db.AccessMode) {
-	f.PushHook(func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode {
-		return r0
+func (f *ReposStoreGetByNameFunc) PushReturn(r0 *db.Repository, r1 error) {
+	f.PushHook(func(context.Context, int64, string) (*db.Repository, error) {
+		return r0, r1
 	})
 }
 
-func (f *PermsStoreAccessModeFunc) nextHook() func(context.Context, int64, int64, db.AccessModeOptions) db.AccessMode {
+func (f *ReposStoreGetByNameFunc) nextHook() func(context.Context, int64, string) (*db.Repository, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1218,26 +3621,26 @@ func (f *PermsStoreAccessModeFunc) nextHook() func(context.Context, int64, int64
 	return hook
 }
 
-func (f *PermsStoreAccessModeFunc) appendCall(r0 PermsStoreAccessModeFuncCall) {
+func (f *ReposStoreGetByNameFunc) appendCall(r0 ReposStoreGetByNameFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of PermsStoreAccessModeFuncCall objects
+// History returns a sequence of ReposStoreGetByNameFuncCall objects
 // describing the invocations of this function.
-func (f *PermsStoreAccessModeFunc) History() []PermsStoreAccessModeFuncCall {
+func (f *ReposStoreGetByNameFunc) History() []ReposStoreGetByNameFuncCall {
 	f.mutex.Lock()
-	history := make([]PermsStoreAccessModeFuncCall, len(f.history))
+	history := make([]ReposStoreGetByNameFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// PermsStoreAccessModeFuncCall is an object that describes an invocation of
-// method AccessMode on an instance of MockPermsStore.
-type PermsStoreAccessModeFuncCall struct {
+// ReposStoreGetByNameFuncCall is an object that describes an invocation of
+// method GetByName on an instance of MockReposStore.
+type ReposStoreGetByNameFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -1246,56 +3649,55 @@ type PermsStoreAccessModeFuncCall struct {
 	Arg1 int64
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 int64
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 db.AccessModeOptions
+	Arg2 string
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 db.AccessMode
+	Result0 *db.Repository
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c PermsStoreAccessModeFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+func (c ReposStoreGetByNameFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c PermsStoreAccessModeFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0}
+func (c ReposStoreGetByNameFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
-// PermsStoreAuthorizeFunc describes the behavior when the Authorize method
-// of the parent MockPermsStore instance is invoked.
-type PermsStoreAuthorizeFunc struct {
-	defaultHook func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool
-	hooks       []func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool
-	history     []PermsStoreAuthorizeFuncCall
+// ReposStoreTouchFunc describes the behavior when the Touch method of the
+// parent MockReposStore instance is invoked.
+type ReposStoreTouchFunc struct {
+	defaultHook func(context.Context, int64) error
+	hooks       []func(context.Context, int64) error
+	history     []ReposStoreTouchFuncCall
 	mutex       sync.Mutex
 }
 
-// Authorize delegates to the next hook function in the queue and stores the
+// Touch delegates to the next hook function in the queue and stores the
 // parameter and result values of this invocation.
-func (m *MockPermsStore) Authorize(v0 context.Context, v1 int64, v2 int64, v3 db.AccessMode, v4 db.AccessModeOptions) bool {
-	r0 := m.AuthorizeFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.AuthorizeFunc.appendCall(PermsStoreAuthorizeFuncCall{v0, v1, v2, v3, v4, r0})
+func (m *MockReposStore) Touch(v0 context.Context, v1 int64) error {
+	r0 := m.TouchFunc.nextHook()(v0, v1)
+	m.TouchFunc.appendCall(ReposStoreTouchFuncCall{v0, v1, r0})
 	return r0
 }
 
-// SetDefaultHook sets function that is called when the Authorize method of
-// the parent MockPermsStore instance is invoked and the hook queue is
-// empty.
-func (f *PermsStoreAuthorizeFunc) SetDefaultHook(hook func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool) {
+// SetDefaultHook sets function that is called when the Touch method of the
+// parent MockReposStore instance is invoked and the hook queue is empty.
+func (f *ReposStoreTouchFunc) SetDefaultHook(hook func(context.Context, int64) error) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// Authorize method of the parent MockPermsStore instance invokes the hook
-// at the front of the queue and discards it. After the queue is empty, the
+// Touch method of the parent MockReposStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
 // default hook function is invoked for any future action.
-func (f *PermsStoreAuthorizeFunc) PushHook(hook func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool) {
+func (f *ReposStoreTouchFunc) PushHook(hook func(context.Context, int64) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1303,20 +3705,20 @@ func (f *PermsStoreAuthorizeFunc) PushHook(hook func(context.Context, int64, int
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *PermsStoreAuthorizeFunc) SetDefaultReturn(r0 bool) {
-	f.SetDefaultHook(func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool {
+func (f *ReposStoreTouchFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64) error {
 		return r0
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *PermsStoreAuthorizeFunc) PushReturn(r0 bool) {
-	f.PushHook(func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool {
+func (f *ReposStoreTouchFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64) error {
 		return r0
 	})
 }
 
-func (f *PermsStoreAuthorizeFunc) nextHook() func(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool {
+func (f *ReposStoreTouchFunc) nextHook() func(context.Context, int64) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1329,87 +3731,219 @@ func (f *PermsStoreAuthorizeFunc) nextHook() func(context.Context, int64, int64,
 	return hook
 }
 
-func (f *PermsStoreAuthorizeFunc) appendCall(r0 PermsStoreAuthorizeFuncCall) {
+func (f *ReposStoreTouchFunc) appendCall(r0 ReposStoreTouchFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of PermsStoreAuthorizeFuncCall objects
-// describing the invocations of this function.
-func (f *PermsStoreAuthorizeFunc) History() []PermsStoreAuthorizeFuncCall {
+// History returns a sequence of ReposStoreTouchFuncCall objects describing
+// the invocations of this function.
+func (f *ReposStoreTouchFunc) History() []ReposStoreTouchFuncCall {
 	f.mutex.Lock()
-	history := make([]PermsStoreAuthorizeFuncCall, len(f.history))
+	history := make([]ReposStoreTouchFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// PermsStoreAuthorizeFuncCall is an object that describes an invocation of
-// method Authorize on an instance of MockPermsStore.
-type PermsStoreAuthorizeFuncCall struct {
+// ReposStoreTouchFuncCall is an object that describes an invocation of
+// method Touch on an instance of MockReposStore.
+type ReposStoreTouchFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
 	Arg1 int64
-	// Arg2 is the value of the 3rd argument passed to this method
-	// invocation.
-	Arg2 int64
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 db.AccessMode
-	// Arg4 is the value of the 5th argument passed to this method
-	// invocation.
-	Arg4 db.AccessModeOptions
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 bool
+	Result0 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c PermsStoreAuthorizeFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+func (c ReposStoreTouchFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ReposStoreTouchFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// MockTwoFactorsStore is a mock implementation of the TwoFactorsStore
+// interface (from the package gogs.io/gogs/internal/db) used for unit
+// testing.
+type MockTwoFactorsStore struct {
+	// CreateFunc is an instance of a mock function object controlling the
+	// behavior of the method Create.
+	CreateFunc *TwoFactorsStoreCreateFunc
+	// CreateWebAuthnCredentialFunc is an instance of a mock function object
+	// controlling the behavior of the method CreateWebAuthnCredential.
+	CreateWebAuthnCredentialFunc *TwoFactorsStoreCreateWebAuthnCredentialFunc
+	// DeleteWebAuthnCredentialFunc is an instance of a mock function object
+	// controlling the behavior of the method DeleteWebAuthnCredential.
+	DeleteWebAuthnCredentialFunc *TwoFactorsStoreDeleteWebAuthnCredentialFunc
+	// GetByUserIDFunc is an instance of a mock function object controlling
+	// the behavior of the method GetByUserID.
+	GetByUserIDFunc *TwoFactorsStoreGetByUserIDFunc
+	// IsUserEnabledFunc is an instance of a mock function object
+	// controlling the behavior of the method IsUserEnabled.
+	IsUserEnabledFunc *TwoFactorsStoreIsUserEnabledFunc
+	// ListWebAuthnCredentialsFunc is an instance of a mock function object
+	// controlling the behavior of the method ListWebAuthnCredentials.
+	ListWebAuthnCredentialsFunc *TwoFactorsStoreListWebAuthnCredentialsFunc
+	// UseWebAuthnCredentialFunc is an instance of a mock function object
+	// controlling the behavior of the method UseWebAuthnCredential.
+	UseWebAuthnCredentialFunc *TwoFactorsStoreUseWebAuthnCredentialFunc
+}
+
+// NewMockTwoFactorsStore creates a new mock of the TwoFactorsStore
+// interface. All methods return zero values for all results, unless
+// overwritten.
+func NewMockTwoFactorsStore() *MockTwoFactorsStore {
+	return &MockTwoFactorsStore{
+		CreateFunc: &TwoFactorsStoreCreateFunc{
+			defaultHook: func(context.Context, int64, string, string) (r0 error) {
+				return
+			},
+		},
+		CreateWebAuthnCredentialFunc: &TwoFactorsStoreCreateWebAuthnCredentialFunc{
+			defaultHook: func(context.Context, int64, string, *webauthn.Credential) (r0 *db.WebAuthnCredential, r1 error) {
+				return
+			},
+		},
+		DeleteWebAuthnCredentialFunc: &TwoFactorsStoreDeleteWebAuthnCredentialFunc{
+			defaultHook: func(context.Context, int64, int64) (r0 error) {
+				return
+			},
+		},
+		GetByUserIDFunc: &TwoFactorsStoreGetByUserIDFunc{
+			defaultHook: func(context.Context, int64) (r0 *db.TwoFactor, r1 error) {
+				return
+			},
+		},
+		IsUserEnabledFunc: &TwoFactorsStoreIsUserEnabledFunc{
+			defaultHook: func(context.Context, int64) (r0 bool) {
+				return
+			},
+		},
+		ListWebAuthnCredentialsFunc: &TwoFactorsStoreListWebAuthnCredentialsFunc{
+			defaultHook: func(context.Context, int64) (r0 []*db.WebAuthnCredential, r1 error) {
+				return
+			},
+		},
+		UseWebAuthnCredentialFunc: &TwoFactorsStoreUseWebAuthnCredentialFunc{
+			defaultHook: func(context.Context, int64, uint32) (r0 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockTwoFactorsStore creates a new mock of the TwoFactorsStore
+// interface. All methods panic on invocation, unless overwritten.
+func NewStrictMockTwoFactorsStore() *MockTwoFactorsStore {
+	return &MockTwoFactorsStore{
+		CreateFunc: &TwoFactorsStoreCreateFunc{
+			defaultHook: func(context.Context, int64, string, string) error {
+				panic("unexpected invocation of MockTwoFactorsStore.Create")
+			},
+		},
+		CreateWebAuthnCredentialFunc: &TwoFactorsStoreCreateWebAuthnCredentialFunc{
+			defaultHook: func(context.Context, int64, string, *webauthn.Credential) (*db.WebAuthnCredential, error) {
+				panic("unexpected invocation of MockTwoFactorsStore.CreateWebAuthnCredential")
+			},
+		},
+		DeleteWebAuthnCredentialFunc: &TwoFactorsStoreDeleteWebAuthnCredentialFunc{
+			defaultHook: func(context.Context, int64, int64) error {
+				panic("unexpected invocation of MockTwoFactorsStore.DeleteWebAuthnCredential")
+			},
+		},
+		GetByUserIDFunc: &TwoFactorsStoreGetByUserIDFunc{
+			defaultHook: func(context.Context, int64) (*db.TwoFactor, error) {
+				panic("unexpected invocation of MockTwoFactorsStore.GetByUserID")
+			},
+		},
+		IsUserEnabledFunc: &TwoFactorsStoreIsUserEnabledFunc{
+			defaultHook: func(context.Context, int64) bool {
+				panic("unexpected invocation of MockTwoFactorsStore.IsUserEnabled")
+			},
+		},
+		ListWebAuthnCredentialsFunc: &TwoFactorsStoreListWebAuthnCredentialsFunc{
+			defaultHook: func(context.Context, int64) ([]*db.WebAuthnCredential, error) {
+				panic("unexpected invocation of MockTwoFactorsStore.ListWebAuthnCredentials")
+			},
+		},
+		UseWebAuthnCredentialFunc: &TwoFactorsStoreUseWebAuthnCredentialFunc{
+			defaultHook: func(context.Context, int64, uint32) error {
+				panic("unexpected invocation of MockTwoFactorsStore.UseWebAuthnCredential")
+			},
+		},
+	}
 }
 
-// Results returns an interface slice containing the results of this
-// invocation.
-func (c PermsStoreAuthorizeFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0}
+// NewMockTwoFactorsStoreFrom creates a new mock of the MockTwoFactorsStore
+// interface. All methods delegate to the given implementation, unless
+// overwritten.
+func NewMockTwoFactorsStoreFrom(i db.TwoFactorsStore) *MockTwoFactorsStore {
+	return &MockTwoFactorsStore{
+		CreateFunc: &TwoFactorsStoreCreateFunc{
+			defaultHook: i.Create,
+		},
+		CreateWebAuthnCredentialFunc: &TwoFactorsStoreCreateWebAuthnCredentialFunc{
+			defaultHook: i.CreateWebAuthnCredential,
+		},
+		DeleteWebAuthnCredentialFunc: &TwoFactorsStoreDeleteWebAuthnCredentialFunc{
+			defaultHook: i.DeleteWebAuthnCredential,
+		},
+		GetByUserIDFunc: &TwoFactorsStoreGetByUserIDFunc{
+			defaultHook: i.GetByUserID,
+		},
+		IsUserEnabledFunc: &TwoFactorsStoreIsUserEnabledFunc{
+			defaultHook: i.IsUserEnabled,
+		},
+		ListWebAuthnCredentialsFunc: &TwoFactorsStoreListWebAuthnCredentialsFunc{
+			defaultHook: i.ListWebAuthnCredentials,
+		},
+		UseWebAuthnCredentialFunc: &TwoFactorsStoreUseWebAuthnCredentialFunc{
+			defaultHook: i.UseWebAuthnCredential,
+		},
+	}
 }
 
-// PermsStoreSetRepoPermsFunc describes the behavior when the SetRepoPerms
-// method of the parent MockPermsStore instance is invoked.
-type PermsStoreSetRepoPermsFunc struct {
-	defaultHook func(context.Context, int64, map[int64]db.AccessMode) error
-	hooks       []func(context.Context, int64, map[int64]db.AccessMode) error
-	history     []PermsStoreSetRepoPermsFuncCall
+// TwoFactorsStoreCreateFunc describes the behavior when the Create method
+// of the parent MockTwoFactorsStore instance is invoked.
+type TwoFactorsStoreCreateFunc struct {
+	defaultHook func(context.Context, int64, string, string) error
+	hooks       []func(context.Context, int64, string, string) error
+	history     []TwoFactorsStoreCreateFuncCall
 	mutex       sync.Mutex
 }
 
-// SetRepoPerms delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockPermsStore) SetRepoPerms(v0 context.Context, v1 int64, v2 map[int64]db.AccessMode) error {
-	r0 := m.SetRepoPermsFunc.nextHook()(v0, v1, v2)
-	m.SetRepoPermsFunc.appendCall(PermsStoreSetRepoPermsFuncCall{v0, v1, v2, r0})
+// Create delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockTwoFactorsStore) Create(v0 context.Context, v1 int64, v2 string, v3 string) error {
+	r0 := m.CreateFunc.nextHook()(v0, v1, v2, v3)
+	m.CreateFunc.appendCall(TwoFactorsStoreCreateFuncCall{v0, v1, v2, v3, r0})
 	return r0
 }
 
-// SetDefaultHook sets function that is called when the SetRepoPerms method
-// of the parent MockPermsStore instance is invoked and the hook queue is
+// SetDefaultHook sets function that is called when the Create method of the
+// parent MockTwoFactorsStore instance is invoked and the hook queue is
 // empty.
-func (f *PermsStoreSetRepoPermsFunc) SetDefaultHook(hook func(context.Context, int64, map[int64]db.AccessMode) error) {
+func (f *TwoFactorsStoreCreateFunc) SetDefaultHook(hook func(context.Context, int64, string, string) error) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// SetRepoPerms method of the parent MockPermsStore instance invokes the
-// hook at the front of the queue and discards it. After the queue is empty,
-// the default hook function is invoked for any future action.
-func (f *PermsStoreSetRepoPermsFunc) PushHook(hook func(context.Context, int64, map[int64]db.AccessMode) error) {
+// Create method of the parent MockTwoFactorsStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *TwoFactorsStoreCreateFunc) PushHook(hook func(context.Context, int64, string, string) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1417,20 +3951,20 @@ func (f *PermsStoreSetRepoPermsFunc) PushHook(hook func(context.Context, int64,
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *PermsStoreSetRepoPermsFunc) SetDefaultReturn(r0 error) {
-	f.SetDefaultHook(func(context.Context, int64, map[int64]db.AccessMode) error {
+func (f *TwoFactorsStoreCreateFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64, string, string) error {
 		return r0
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *PermsStoreSetRepoPermsFunc) PushReturn(r0 error) {
-	f.PushHook(func(context.Context, int64, map[int64]db.AccessMode) error {
+func (f *TwoFactorsStoreCreateFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64, string, string) error {
 		return r0
 	})
 }
 
-func (f *PermsStoreSetRepoPermsFunc) nextHook() func(context.Context, int64, map[int64]db.AccessMode) error {
+func (f *TwoFactorsStoreCreateFunc) nextHook() func(context.Context, int64, string, string) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1443,26 +3977,26 @@ func (f *PermsStoreSetRepoPermsFunc) nextHook() func(context.Context, int64, map
 	return hook
 }
 
-func (f *PermsStoreSetRepoPermsFunc) appendCall(r0 PermsStoreSetRepoPermsFuncCall) {
+func (f *TwoFactorsStoreCreateFunc) appendCall(r0 TwoFactorsStoreCreateFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of PermsStoreSetRepoPermsFuncCall objects
+// History returns a sequence of TwoFactorsStoreCreateFuncCall objects
 // describing the invocations of this function.
-func (f *PermsStoreSetRepoPermsFunc) History() []PermsStoreSetRepoPermsFuncCall {
+func (f *TwoFactorsStoreCreateFunc) History() []TwoFactorsStoreCreateFuncCall {
 	f.mutex.Lock()
-	history := make([]PermsStoreSetRepoPermsFuncCall, len(f.history))
+	history := make([]TwoFactorsStoreCreateFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// PermsStoreSetRepoPermsFuncCall is an object that describes an invocation
-// of method SetRepoPerms on an instance of MockPermsStore.
-type PermsStoreSetRepoPermsFuncCall struct {
+// TwoFactorsStoreCreateFuncCall is an object that describes an invocation
+// of method Create on an instance of MockTwoFactorsStore.
+type TwoFactorsStoreCreateFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -1471,7 +4005,10 @@ type PermsStoreSetRepoPermsFuncCall struct {
 	Arg1 int64
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 map[int64]db.AccessMode
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 error
@@ -1479,118 +4016,47 @@ type PermsStoreSetRepoPermsFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c PermsStoreSetRepoPermsFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+func (c TwoFactorsStoreCreateFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c PermsStoreSetRepoPermsFuncCall) Results() []interface{} {
+func (c TwoFactorsStoreCreateFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
-// MockReposStore is a mock implementation of the ReposStore interface (from
-// the package gogs.io/gogs/internal/db) used for unit testing.
-type MockReposStore struct {
-	// CreateFunc is an instance of a mock function object controlling the
-	// behavior of the method Create.
-	CreateFunc *ReposStoreCreateFunc
-	// GetByNameFunc is an instance of a mock function object controlling
-	// the behavior of the method GetByName.
-	GetByNameFunc *ReposStoreGetByNameFunc
-	// TouchFunc is an instance of a mock function object controlling the
-	// behavior of the method Touch.
-	TouchFunc *ReposStoreTouchFunc
-}
-
-// NewMockReposStore creates a new mock of the ReposStore interface. All
-// methods return zero values for all results, unless overwritten.
-func NewMockReposStore() *MockReposStore {
-	return &MockReposStore{
-		CreateFunc: &ReposStoreCreateFunc{
-			defaultHook: func(context.Context, int64, db.CreateRepoOptions) (r0 *db.Repository, r1 error) {
-				return
-			},
-		},
-		GetByNameFunc: &ReposStoreGetByNameFunc{
-			defaultHook: func(context.Context, int64, string) (r0 *db.Repository, r1 error) {
-				return
-			},
-		},
-		TouchFunc: &ReposStoreTouchFunc{
-			defaultHook: func(context.Context, int64) (r0 error) {
-				return
-			},
-		},
-	}
-}
-
-// NewStrictMockReposStore creates a new mock of the ReposStore interface.
-// All methods panic on invocation, unless overwritten.
-func NewStrictMockReposStore() *MockReposStore {
-	return &MockReposStore{
-		CreateFunc: &ReposStoreCreateFunc{
-			defaultHook: func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error) {
-				panic("unexpected invocation of MockReposStore.Create")
-			},
-		},
-		GetByNameFunc: &ReposStoreGetByNameFunc{
-			defaultHook: func(context.Context, int64, string) (*db.Repository, error) {
-				panic("unexpected invocation of MockReposStore.GetByName")
-			},
-		},
-		TouchFunc: &ReposStoreTouchFunc{
-			defaultHook: func(context.Context, int64) error {
-				panic("unexpected invocation of MockReposStore.Touch")
-			},
-		},
-	}
-}
-
-// NewMockReposStoreFrom creates a new mock of the MockReposStore interface.
-// All methods delegate to the given implementation, unless overwritten.
-func NewMockReposStoreFrom(i db.ReposStore) *MockReposStore {
-	return &MockReposStore{
-		CreateFunc: &ReposStoreCreateFunc{
-			defaultHook: i.Create,
-		},
-		GetByNameFunc: &ReposStoreGetByNameFunc{
-			defaultHook: i.GetByName,
-		},
-		TouchFunc: &ReposStoreTouchFunc{
-			defaultHook: i.Touch,
-		},
-	}
-}
-
-// ReposStoreCreateFunc describes the behavior when the Create method of the
-// parent MockReposStore instance is invoked.
-type ReposStoreCreateFunc struct {
-	defaultHook func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error)
-	hooks       []func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error)
-	history     []ReposStoreCreateFuncCall
+// TwoFactorsStoreCreateWebAuthnCredentialFunc describes the behavior when
+// the CreateWebAuthnCredential method of the parent MockTwoFactorsStore
+// instance is invoked.
+type TwoFactorsStoreCreateWebAuthnCredentialFunc struct {
+	defaultHook func(context.Context, int64, string, *webauthn.Credential) (*db.WebAuthnCredential, error)
+	hooks       []func(context.Context, int64, string, *webauthn.Credential) (*db.WebAuthnCredential, error)
+	history     []TwoFactorsStoreCreateWebAuthnCredentialFuncCall
 	mutex       sync.Mutex
 }
 
-// Create delegates to the next hook function in the queue and stores the
-// parameter and result values of this invocation.
-func (m *MockReposStore) Create(v0 context.Context, v1 int64, v2 db.CreateRepoOptions) (*db.Repository, error) {
-	r0, r1 := m.CreateFunc.nextHook()(v0, v1, v2)
-	m.CreateFunc.appendCall(ReposStoreCreateFuncCall{v0, v1, v2, r0, r1})
+// CreateWebAuthnCredential delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockTwoFactorsStore) CreateWebAuthnCredential(v0 context.Context, v1 int64, v2 string, v3 *webauthn.Credential) (*db.WebAuthnCredential, error) {
+	r0, r1 := m.CreateWebAuthnCredentialFunc.nextHook()(v0, v1, v2, v3)
+	m.CreateWebAuthnCredentialFunc.appendCall(TwoFactorsStoreCreateWebAuthnCredentialFuncCall{v0, v1, v2, v3, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the Create method of the
-// parent MockReposStore instance is invoked and the hook queue is empty.
-func (f *ReposStoreCreateFunc) SetDefaultHook(hook func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error)) {
+// SetDefaultHook sets function that is called when the
+// CreateWebAuthnCredential method of the parent MockTwoFactorsStore
+// instance is invoked and the hook queue is empty.
+func (f *TwoFactorsStoreCreateWebAuthnCredentialFunc) SetDefaultHook(hook func(context.Context, int64, string, *webauthn.Credential) (*db.WebAuthnCredential, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// Create method of the parent MockReposStore instance invokes the hook at
-// the front of the queue and discards it. After the queue is empty, the
-// default hook function is invoked for any future action.
-func (f *ReposStoreCreateFunc) PushHook(hook func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error)) {
+// CreateWebAuthnCredential method of the parent MockTwoFactorsStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *TwoFactorsStoreCreateWebAuthnCredentialFunc) PushHook(hook func(context.Context, int64, string, *webauthn.Credential) (*db.WebAuthnCredential, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1598,20 +4064,20 @@ func (f *ReposStoreCreateFunc) PushHook(hook func(context.Context, int64, db.Cre
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *ReposStoreCreateFunc) SetDefaultReturn(r0 *db.Repository, r1 error) {
-	f.SetDefaultHook(func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error) {
+func (f *TwoFactorsStoreCreateWebAuthnCredentialFunc) SetDefaultReturn(r0 *db.WebAuthnCredential, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, string, *webauthn.Credential) (*db.WebAuthnCredential, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *ReposStoreCreateFunc) PushReturn(r0 *db.Repository, r1 error) {
-	f.PushHook(func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error) {
+func (f *TwoFactorsStoreCreateWebAuthnCredentialFunc) PushReturn(r0 *db.WebAuthnCredential, r1 error) {
+	f.PushHook(func(context.Context, int64, string, *webauthn.Credential) (*db.WebAuthnCredential, error) {
 		return r0, r1
 	})
 }
 
-func (f *ReposStoreCreateFunc) nextHook() func(context.Context, int64, db.CreateRepoOptions) (*db.Repository, error) {
+func (f *TwoFactorsStoreCreateWebAuthnCredentialFunc) nextHook() func(context.Context, int64, string, *webauthn.Credential) (*db.WebAuthnCredential, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1624,26 +4090,28 @@ func (f *ReposStoreCreateFunc) nextHook() func(context.Context, int64, db.Create
 	return hook
 }
 
-func (f *ReposStoreCreateFunc) appendCall(r0 ReposStoreCreateFuncCall) {
+func (f *TwoFactorsStoreCreateWebAuthnCredentialFunc) appendCall(r0 TwoFactorsStoreCreateWebAuthnCredentialFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of ReposStoreCreateFuncCall objects describing
-// the invocations of this function.
-func (f *ReposStoreCreateFunc) History() []ReposStoreCreateFuncCall {
+// History returns a sequence of
+// TwoFactorsStoreCreateWebAuthnCredentialFuncCall objects describing the
+// invocations of this function.
+func (f *TwoFactorsStoreCreateWebAuthnCredentialFunc) History() []TwoFactorsStoreCreateWebAuthnCredentialFuncCall {
 	f.mutex.Lock()
-	history := make([]ReposStoreCreateFuncCall, len(f.history))
+	history := make([]TwoFactorsStoreCreateWebAuthnCredentialFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// ReposStoreCreateFuncCall is an object that describes an invocation of
-// method Create on an instance of MockReposStore.
-type ReposStoreCreateFuncCall struct {
+// TwoFactorsStoreCreateWebAuthnCredentialFuncCall is an object that
+// describes an invocation of method CreateWebAuthnCredential on an instance
+// of MockTwoFactorsStore.
+type TwoFactorsStoreCreateWebAuthnCredentialFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -1652,10 +4120,13 @@ type ReposStoreCreateFuncCall struct {
 	Arg1 int64
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 db.CreateRepoOptions
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 *webauthn.Credential
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 *db.Repository
+	Result0 *db.WebAuthnCredential
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -1663,45 +4134,47 @@ type ReposStoreCreateFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c ReposStoreCreateFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+func (c TwoFactorsStoreCreateWebAuthnCredentialFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c ReposStoreCreateFuncCall) Results() []interface{} {
+func (c TwoFactorsStoreCreateWebAuthnCredentialFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// ReposStoreGetByNameFunc describes the behavior when the GetByName method
-// of the parent MockReposStore instance is invoked.
-type ReposStoreGetByNameFunc struct {
-	defaultHook func(context.Context, int64, string) (*db.Repository, error)
-	hooks       []func(context.Context, int64, string) (*db.Repository, error)
-	history     []ReposStoreGetByNameFuncCall
+// TwoFactorsStoreDeleteWebAuthnCredentialFunc describes the behavior when
+// the DeleteWebAuthnCredential method of the parent MockTwoFactorsStore
+// instance is invoked.
+type TwoFactorsStoreDeleteWebAuthnCredentialFunc struct {
+	defaultHook func(context.Context, int64, int64) error
+	hooks       []func(context.Context, int64, int64) error
+	history     []TwoFactorsStoreDeleteWebAuthnCredentialFuncCall
 	mutex       sync.Mutex
 }
 
-// GetByName delegates to the next hook function in the queue and stores the
-// parameter and result values of this invocation.
-func (m *MockReposStore) GetByName(v0 context.Context, v1 int64, v2 string) (*db.Repository, error) {
-	r0, r1 := m.GetByNameFunc.nextHook()(v0, v1, v2)
-	m.GetByNameFunc.appendCall(ReposStoreGetByNameFuncCall{v0, v1, v2, r0, r1})
-	return r0, r1
+// DeleteWebAuthnCredential delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockTwoFactorsStore) DeleteWebAuthnCredential(v0 context.Context, v1 int64, v2 int64) error {
+	r0 := m.DeleteWebAuthnCredentialFunc.nextHook()(v0, v1, v2)
+	m.DeleteWebAuthnCredentialFunc.appendCall(TwoFactorsStoreDeleteWebAuthnCredentialFuncCall{v0, v1, v2, r0})
+	return r0
 }
 
-// SetDefaultHook sets function that is called when the GetByName method of
-// the parent MockReposStore instance is invoked and the hook queue is
-// empty.
-func (f *ReposStoreGetByNameFunc) SetDefaultHook(hook func(context.Context, int64, string) (*db.Repository, error)) {
+// SetDefaultHook sets function that is called when the
+// DeleteWebAuthnCredential method of the parent MockTwoFactorsStore
+// instance is invoked and the hook queue is empty.
+func (f *TwoFactorsStoreDeleteWebAuthnCredentialFunc) SetDefaultHook(hook func(context.Context, int64, int64) error) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetByName method of the parent MockReposStore instance invokes the hook
-// at the front of the queue and discards it. After the queue is empty, the
-// default hook function is invoked for any future action.
-func (f *ReposStoreGetByNameFunc) PushHook(hook func(context.Context, int64, string) (*db.Repository, error)) {
+// DeleteWebAuthnCredential method of the parent MockTwoFactorsStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *TwoFactorsStoreDeleteWebAuthnCredentialFunc) PushHook(hook func(context.Context, int64, int64) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1709,20 +4182,20 @@ func (f *ReposStoreGetByNameFunc) PushHook(hook func(context.Context, int64, str
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *ReposStoreGetByNameFunc) SetDefaultReturn(r0 *db.Repository, r1 error) {
-	f.SetDefaultHook(func(context.Context, int64, string) (*db.Repository, error) {
-		return r0, r1
+func (f *TwoFactorsStoreDeleteWebAuthnCredentialFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64, int64) error {
+		return r0
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *ReposStoreGetByNameFunc) PushReturn(r0 *db.Repository, r1 error) {
-	f.PushHook(func(context.Context, int64, string) (*db.Repository, error) {
-		return r0, r1
+func (f *TwoFactorsStoreDeleteWebAuthnCredentialFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64, int64) error {
+		return r0
 	})
 }
 
-func (f *ReposStoreGetByNameFunc) nextHook() func(context.Context, int64, string) (*db.Repository, error) {
+func (f *TwoFactorsStoreDeleteWebAuthnCredentialFunc) nextHook() func(context.Context, int64, int64) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1735,26 +4208,28 @@ func (f *ReposStoreGetByNameFunc) nextHook() func(context.Context, int64, string
 	return hook
 }
 
-func (f *ReposStoreGetByNameFunc) appendCall(r0 ReposStoreGetByNameFuncCall) {
+func (f *TwoFactorsStoreDeleteWebAuthnCredentialFunc) appendCall(r0 TwoFactorsStoreDeleteWebAuthnCredentialFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of ReposStoreGetByNameFuncCall objects
-// describing the invocations of this function.
-func (f *ReposStoreGetByNameFunc) History() []ReposStoreGetByNameFuncCall {
+// History returns a sequence of
+// TwoFactorsStoreDeleteWebAuthnCredentialFuncCall objects describing the
+// invocations of this function.
+func (f *TwoFactorsStoreDeleteWebAuthnCredentialFunc) History() []TwoFactorsStoreDeleteWebAuthnCredentialFuncCall {
 	f.mutex.Lock()
-	history := make([]ReposStoreGetByNameFuncCall, len(f.history))
+	history := make([]TwoFactorsStoreDeleteWebAuthnCredentialFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// ReposStoreGetByNameFuncCall is an object that describes an invocation of
-// method GetByName on an instance of MockReposStore.
-type ReposStoreGetByNameFuncCall struct {
+// TwoFactorsStoreDeleteWebAuthnCredentialFuncCall is an object that
+// describes an invocation of method DeleteWebAuthnCredential on an instance
+// of MockTwoFactorsStore.
+type TwoFactorsStoreDeleteWebAuthnCredentialFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -1763,55 +4238,53 @@ type ReposStoreGetByNameFuncCall struct {
 	Arg1 int64
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 string
+	Arg2 int64
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 *db.Repository
-	// Result1 is the value of the 2nd result returned from this method
-	// invocation.
-	Result1 error
+	Result0 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c ReposStoreGetByNameFuncCall) Args() []interface{} {
+func (c TwoFactorsStoreDeleteWebAuthnCredentialFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c ReposStoreGetByNameFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0, c.Result1}
+func (c TwoFactorsStoreDeleteWebAuthnCredentialFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
 }
 
-// ReposStoreTouchFunc describes the behavior when the Touch method of the
-// parent MockReposStore instance is invoked.
-type ReposStoreTouchFunc struct {
-	defaultHook func(context.Context, int64) error
-	hooks       []func(context.Context, int64) error
-	history     []ReposStoreTouchFuncCall
+// TwoFactorsStoreGetByUserIDFunc describes the behavior when the
+// GetByUserID method of the parent MockTwoFactorsStore instance is invoked.
+type TwoFactorsStoreGetByUserIDFunc struct {
+	defaultHook func(context.Context, int64) (*db.TwoFactor, error)
+	hooks       []func(context.Context, int64) (*db.TwoFactor, error)
+	history     []TwoFactorsStoreGetByUserIDFuncCall
 	mutex       sync.Mutex
 }
 
-// Touch delegates to the next hook function in the queue and stores the
-// parameter and result values of this invocation.
-func (m *MockReposStore) Touch(v0 context.Context, v1 int64) error {
-	r0 := m.TouchFunc.nextHook()(v0, v1)
-	m.TouchFunc.appendCall(ReposStoreTouchFuncCall{v0, v1, r0})
-	return r0
+// GetByUserID delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockTwoFactorsStore) GetByUserID(v0 context.Context, v1 int64) (*db.TwoFactor, error) {
+	r0, r1 := m.GetByUserIDFunc.nextHook()(v0, v1)
+	m.GetByUserIDFunc.appendCall(TwoFactorsStoreGetByUserIDFuncCall{v0, v1, r0, r1})
+	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the Touch method of the
-// parent MockReposStore instance is invoked and the hook queue is empty.
-func (f *ReposStoreTouchFunc) SetDefaultHook(hook func(context.Context, int64) error) {
+// SetDefaultHook sets function that is called when the GetByUserID method
+// of the parent MockTwoFactorsStore instance is invoked and the hook queue
+// is empty.
+func (f *TwoFactorsStoreGetByUserIDFunc) SetDefaultHook(hook func(context.Context, int64) (*db.TwoFactor, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// Touch method of the parent MockReposStore instance invokes the hook at
-// the front of the queue and discards it. After the queue is empty, the
-// default hook function is invoked for any future action.
-func (f *ReposStoreTouchFunc) PushHook(hook func(context.Context, int64) error) {
+// GetByUserID method of the parent MockTwoFactorsStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *TwoFactorsStoreGetByUserIDFunc) PushHook(hook func(context.Context, int64) (*db.TwoFactor, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1819,20 +4292,20 @@ func (f *ReposStoreTouchFunc) PushHook(hook func(context.Context, int64) error)
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *ReposStoreTouchFunc) SetDefaultReturn(r0 error) {
-	f.SetDefaultHook(func(context.Context, int64) error {
-		return r0
+func (f *TwoFactorsStoreGetByUserIDFunc) SetDefaultReturn(r0 *db.TwoFactor, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64) (*db.TwoFactor, error) {
+		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *ReposStoreTouchFunc) PushReturn(r0 error) {
-	f.PushHook(func(context.Context, int64) error {
-		return r0
+func (f *TwoFactorsStoreGetByUserIDFunc) PushReturn(r0 *db.TwoFactor, r1 error) {
+	f.PushHook(func(context.Context, int64) (*db.TwoFactor, error) {
+		return r0, r1
 	})
 }
 
-func (f *ReposStoreTouchFunc) nextHook() func(context.Context, int64) error {
+func (f *TwoFactorsStoreGetByUserIDFunc) nextHook() func(context.Context, int64) (*db.TwoFactor, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1845,26 +4318,26 @@ func (f *ReposStoreTouchFunc) nextHook() func(context.Context, int64) error {
 	return hook
 }
 
-func (f *ReposStoreTouchFunc) appendCall(r0 ReposStoreTouchFuncCall) {
+func (f *TwoFactorsStoreGetByUserIDFunc) appendCall(r0 TwoFactorsStoreGetByUserIDFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of ReposStoreTouchFuncCall objects describing
-// the invocations of this function.
-func (f *ReposStoreTouchFunc) History() []ReposStoreTouchFuncCall {
+// History returns a sequence of TwoFactorsStoreGetByUserIDFuncCall objects
+// describing the invocations of this function.
+func (f *TwoFactorsStoreGetByUserIDFunc) History() []TwoFactorsStoreGetByUserIDFuncCall {
 	f.mutex.Lock()
-	history := make([]ReposStoreTouchFuncCall, len(f.history))
+	history := make([]TwoFactorsStoreGetByUserIDFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// ReposStoreTouchFuncCall is an object that describes an invocation of
-// method Touch on an instance of MockReposStore.
-type ReposStoreTouchFuncCall struct {
+// TwoFactorsStoreGetByUserIDFuncCall is an object that describes an
+// invocation of method GetByUserID on an instance of MockTwoFactorsStore.
+type TwoFactorsStoreGetByUserIDFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -1873,127 +4346,54 @@ type ReposStoreTouchFuncCall struct {
 	Arg1 int64
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 error
+	Result0 *db.TwoFactor
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c ReposStoreTouchFuncCall) Args() []interface{} {
+func (c TwoFactorsStoreGetByUserIDFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c ReposStoreTouchFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0}
-}
-
-// MockTwoFactorsStore is a mock implementation of the TwoFactorsStore
-// interface (from the package gogs.io/gogs/internal/db) used for unit
-// testing.
-type MockTwoFactorsStore struct {
-	// CreateFunc is an instance of a mock function object controlling the
-	// behavior of the method Create.
-	CreateFunc *TwoFactorsStoreCreateFunc
-	// GetByUserIDFunc is an instance of a mock function object controlling
-	// the behavior of the method GetByUserID.
-	GetByUserIDFunc *TwoFactorsStoreGetByUserIDFunc
-	// IsUserEnabledFunc is an instance of a mock function object
-	// controlling the behavior of the method IsUserEnabled.
-	IsUserEnabledFunc *TwoFactorsStoreIsUserEnabledFunc
-}
-
-// NewMockTwoFactorsStore creates a new mock of the TwoFactorsStore
-// interface. All methods return zero values for all results, unless
-// overwritten.
-func NewMockTwoFactorsStore() *MockTwoFactorsStore {
-	return &MockTwoFactorsStore{
-		CreateFunc: &TwoFactorsStoreCreateFunc{
-			defaultHook: func(context.Context, int64, string, string) (r0 error) {
-				return
-			},
-		},
-		GetByUserIDFunc: &TwoFactorsStoreGetByUserIDFunc{
-			defaultHook: func(context.Context, int64) (r0 *db.TwoFactor, r1 error) {
-				return
-			},
-		},
-		IsUserEnabledFunc: &TwoFactorsStoreIsUserEnabledFunc{
-			defaultHook: func(context.Context, int64) (r0 bool) {
-				return
-			},
-		},
-	}
-}
-
-// NewStrictMockTwoFactorsStore creates a new mock of the TwoFactorsStore
-// interface. All methods panic on invocation, unless overwritten.
-func NewStrictMockTwoFactorsStore() *MockTwoFactorsStore {
-	return &MockTwoFactorsStore{
-		CreateFunc: &TwoFactorsStoreCreateFunc{
-			defaultHook: func(context.Context, int64, string, string) error {
-				panic("unexpected invocation of MockTwoFactorsStore.Create")
-			},
-		},
-		GetByUserIDFunc: &TwoFactorsStoreGetByUserIDFunc{
-			defaultHook: func(context.Context, int64) (*db.TwoFactor, error) {
-				panic("unexpected invocation of MockTwoFactorsStore.GetByUserID")
-			},
-		},
-		IsUserEnabledFunc: &TwoFactorsStoreIsUserEnabledFunc{
-			defaultHook: func(context.Context, int64) bool {
-				panic("unexpected invocation of MockTwoFactorsStore.IsUserEnabled")
-			},
-		},
-	}
-}
-
-// NewMockTwoFactorsStoreFrom creates a new mock of the MockTwoFactorsStore
-// interface. All methods delegate to the given implementation, unless
-// overwritten.
-func NewMockTwoFactorsStoreFrom(i db.TwoFactorsStore) *MockTwoFactorsStore {
-	return &MockTwoFactorsStore{
-		CreateFunc: &TwoFactorsStoreCreateFunc{
-			defaultHook: i.Create,
-		},
-		GetByUserIDFunc: &TwoFactorsStoreGetByUserIDFunc{
-			defaultHook: i.GetByUserID,
-		},
-		IsUserEnabledFunc: &TwoFactorsStoreIsUserEnabledFunc{
-			defaultHook: i.IsUserEnabled,
-		},
-	}
+func (c TwoFactorsStoreGetByUserIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
-// TwoFactorsStoreCreateFunc describes the behavior when the Create method
-// of the parent MockTwoFactorsStore instance is invoked.
-type TwoFactorsStoreCreateFunc struct {
-	defaultHook func(context.Context, int64, string, string) error
-	hooks       []func(context.Context, int64, string, string) error
-	history     []TwoFactorsStoreCreateFuncCall
+// TwoFactorsStoreIsUserEnabledFunc describes the behavior when the
+// IsUserEnabled method of the parent MockTwoFactorsStore instance is
+// invoked.
+type TwoFactorsStoreIsUserEnabledFunc struct {
+	defaultHook func(context.Context, int64) bool
+	hooks       []func(context.Context, int64) bool
+	history     []TwoFactorsStoreIsUserEnabledFuncCall
 	mutex       sync.Mutex
 }
 
-// Create delegates to the next hook function in the queue and stores the
-// parameter and result values of this invocation.
-func (m *MockTwoFactorsStore) Create(v0 context.Context, v1 int64, v2 string, v3 string) error {
-	r0 := m.CreateFunc.nextHook()(v0, v1, v2, v3)
-	m.CreateFunc.appendCall(TwoFactorsStoreCreateFuncCall{v0, v1, v2, v3, r0})
+// IsUserEnabled delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockTwoFactorsStore) IsUserEnabled(v0 context.Context, v1 int64) bool {
+	r0 := m.IsUserEnabledFunc.nextHook()(v0, v1)
+	m.IsUserEnabledFunc.appendCall(TwoFactorsStoreIsUserEnabledFuncCall{v0, v1, r0})
 	return r0
 }
 
-// SetDefaultHook sets function that is called when the Create method of the
-// parent MockTwoFactorsStore instance is invoked and the hook queue is
-// empty.
-func (f *TwoFactorsStoreCreateFunc) SetDefaultHook(hook func(context.Context, int64, string, string) error) {
+// SetDefaultHook sets function that is called when the IsUserEnabled method
+// of the parent MockTwoFactorsStore instance is invoked and the hook queue
+// is empty.
+func (f *TwoFactorsStoreIsUserEnabledFunc) SetDefaultHook(hook func(context.Context, int64) bool) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// Create method of the parent MockTwoFactorsStore instance invokes the hook
-// at the front of the queue and discards it. After the queue is empty, the
-// default hook function is invoked for any future action.
-func (f *TwoFactorsStoreCreateFunc) PushHook(hook func(context.Context, int64, string, string) error) {
+// IsUserEnabled method of the parent MockTwoFactorsStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *TwoFactorsStoreIsUserEnabledFunc) PushHook(hook func(context.Context, int64) bool) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -2001,20 +4401,20 @@ func (f *TwoFactorsStoreCreateFunc) PushHook(hook func(context.Context, int64, s
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *TwoFactorsStoreCreateFunc) SetDefaultReturn(r0 error) {
-	f.SetDefaultHook(func(context.Context, int64, string, string) error {
+func (f *TwoFactorsStoreIsUserEnabledFunc) SetDefaultReturn(r0 bool) {
+	f.SetDefaultHook(func(context.Context, int64) bool {
 		return r0
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *TwoFactorsStoreCreateFunc) PushReturn(r0 error) {
-	f.PushHook(func(context.Context, int64, string, string) error {
+func (f *TwoFactorsStoreIsUserEnabledFunc) PushReturn(r0 bool) {
+	f.PushHook(func(context.Context, int64) bool {
 		return r0
 	})
 }
 
-func (f *TwoFactorsStoreCreateFunc) nextHook() func(context.Context, int64, string, string) error {
+func (f *TwoFactorsStoreIsUserEnabledFunc) nextHook() func(context.Context, int64) bool {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -2027,84 +4427,80 @@ func (f *TwoFactorsStoreCreateFunc) nextHook() func(context.Context, int64, stri
 	return hook
 }
 
-func (f *TwoFactorsStoreCreateFunc) appendCall(r0 TwoFactorsStoreCreateFuncCall) {
+func (f *TwoFactorsStoreIsUserEnabledFunc) appendCall(r0 TwoFactorsStoreIsUserEnabledFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of TwoFactorsStoreCreateFuncCall objects
-// describing the invocations of this function.
-func (f *TwoFactorsStoreCreateFunc) History() []TwoFactorsStoreCreateFuncCall {
+// History returns a sequence of TwoFactorsStoreIsUserEnabledFuncCall
+// objects describing the invocations of this function.
+func (f *TwoFactorsStoreIsUserEnabledFunc) History() []TwoFactorsStoreIsUserEnabledFuncCall {
 	f.mutex.Lock()
-	history := make([]TwoFactorsStoreCreateFuncCall, len(f.history))
+	history := make([]TwoFactorsStoreIsUserEnabledFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// TwoFactorsStoreCreateFuncCall is an object that describes an invocation
-// of method Create on an instance of MockTwoFactorsStore.
-type TwoFactorsStoreCreateFuncCall struct {
+// TwoFactorsStoreIsUserEnabledFuncCall is an object that describes an
+// invocation of method IsUserEnabled on an instance of MockTwoFactorsStore.
+type TwoFactorsStoreIsUserEnabledFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
 	Arg1 int64
-	// Arg2 is the value of the 3rd argument passed to this method
-	// invocation.
-	Arg2 string
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 string
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 error
+	Result0 bool
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c TwoFactorsStoreCreateFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+func (c TwoFactorsStoreIsUserEnabledFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c TwoFactorsStoreCreateFuncCall) Results() []interface{} {
+func (c TwoFactorsStoreIsUserEnabledFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
-// TwoFactorsStoreGetByUserIDFunc describes the behavior when the
-// GetByUserID method of the parent MockTwoFactorsStore instance is invoked.
-type TwoFactorsStoreGetByUserIDFunc struct {
-	defaultHook func(context.Context, int64) (*db.TwoFactor, error)
-	hooks       []func(context.Context, int64) (*db.TwoFactor, error)
-	history     []TwoFactorsStoreGetByUserIDFuncCall
+// TwoFactorsStoreListWebAuthnCredentialsFunc describes the behavior when
+// the ListWebAuthnCredentials method of the parent MockTwoFactorsStore
+// instance is invoked.
+type TwoFactorsStoreListWebAuthnCredentialsFunc struct {
+	defaultHook func(context.Context, int64) ([]*db.WebAuthnCredential, error)
+	hooks       []func(context.Context, int64) ([]*db.WebAuthnCredential, error)
+	history     []TwoFactorsStoreListWebAuthnCredentialsFuncCall
 	mutex       sync.Mutex
 }
 
-// GetByUserID delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockTwoFactorsStore) GetByUserID(v0 context.Context, v1 int64) (*db.TwoFactor, error) {
-	r0, r1 := m.GetByUserIDFunc.nextHook()(v0, v1)
-	m.GetByUserIDFunc.appendCall(TwoFactorsStoreGetByUserIDFuncCall{v0, v1, r0, r1})
+// ListWebAuthnCredentials delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockTwoFactorsStore) ListWebAuthnCredentials(v0 context.Context, v1 int64) ([]*db.WebAuthnCredential, error) {
+	r0, r1 := m.ListWebAuthnCredentialsFunc.nextHook()(v0, v1)
+	m.ListWebAuthnCredentialsFunc.appendCall(TwoFactorsStoreListWebAuthnCredentialsFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the GetByUserID method
-// of the parent MockTwoFactorsStore instance is invoked and the hook queue
-// is empty.
-func (f *TwoFactorsStoreGetByUserIDFunc) SetDefaultHook(hook func(context.Context, int64) (*db.TwoFactor, error)) {
+// SetDefaultHook sets function that is called when the
+// ListWebAuthnCredentials method of the parent MockTwoFactorsStore instance
+// is invoked and the hook queue is empty.
+func (f *TwoFactorsStoreListWebAuthnCredentialsFunc) SetDefaultHook(hook func(context.Context, int64) ([]*db.WebAuthnCredential, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetByUserID method of the parent MockTwoFactorsStore instance invokes the
-// hook at the front of the queue and discards it. After the queue is empty,
-// the default hook function is invoked for any future action.
-func (f *TwoFactorsStoreGetByUserIDFunc) PushHook(hook func(context.Context, int64) (*db.TwoFactor, error)) {
+// ListWebAuthnCredentials method of the parent MockTwoFactorsStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *TwoFactorsStoreListWebAuthnCredentialsFunc) PushHook(hook func(context.Context, int64) ([]*db.WebAuthnCredential, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -2112,20 +4508,20 @@ func (f *TwoFactorsStoreGetByUserIDFunc) PushHook(hook func(context.Context, int
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *TwoFactorsStoreGetByUserIDFunc) SetDefaultReturn(r0 *db.TwoFactor, r1 error) {
-	f.SetDefaultHook(func(context.Context, int64) (*db.TwoFactor, error) {
+func (f *TwoFactorsStoreListWebAuthnCredentialsFunc) SetDefaultReturn(r0 []*db.WebAuthnCredential, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64) ([]*db.WebAuthnCredential, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *TwoFactorsStoreGetByUserIDFunc) PushReturn(r0 *db.TwoFactor, r1 error) {
-	f.PushHook(func(context.Context, int64) (*db.TwoFactor, error) {
+func (f *TwoFactorsStoreListWebAuthnCredentialsFunc) PushReturn(r0 []*db.WebAuthnCredential, r1 error) {
+	f.PushHook(func(context.Context, int64) ([]*db.WebAuthnCredential, error) {
 		return r0, r1
 	})
 }
 
-func (f *TwoFactorsStoreGetByUserIDFunc) nextHook() func(context.Context, int64) (*db.TwoFactor, error) {
+func (f *TwoFactorsStoreListWebAuthnCredentialsFunc) nextHook() func(context.Context, int64) ([]*db.WebAuthnCredential, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -2138,26 +4534,28 @@ func (f *TwoFactorsStoreGetByUserIDFunc) nextHook() func(context.Context, int64)
 	return hook
 }
 
-func (f *TwoFactorsStoreGetByUserIDFunc) appendCall(r0 TwoFactorsStoreGetByUserIDFuncCall) {
+func (f *TwoFactorsStoreListWebAuthnCredentialsFunc) appendCall(r0 TwoFactorsStoreListWebAuthnCredentialsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of TwoFactorsStoreGetByUserIDFuncCall objects
-// describing the invocations of this function.
-func (f *TwoFactorsStoreGetByUserIDFunc) History() []TwoFactorsStoreGetByUserIDFuncCall {
+// History returns a sequence of
+// TwoFactorsStoreListWebAuthnCredentialsFuncCall objects describing the
+// invocations of this function.
+func (f *TwoFactorsStoreListWebAuthnCredentialsFunc) History() []TwoFactorsStoreListWebAuthnCredentialsFuncCall {
 	f.mutex.Lock()
-	history := make([]TwoFactorsStoreGetByUserIDFuncCall, len(f.history))
+	history := make([]TwoFactorsStoreListWebAuthnCredentialsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// TwoFactorsStoreGetByUserIDFuncCall is an object that describes an
-// invocation of method GetByUserID on an instance of MockTwoFactorsStore.
-type TwoFactorsStoreGetByUserIDFuncCall struct {
+// TwoFactorsStoreListWebAuthnCredentialsFuncCall is an object that
+// describes an invocation of method ListWebAuthnCredentials on an instance
+// of MockTwoFactorsStore.
+type TwoFactorsStoreListWebAuthnCredentialsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -2166,7 +4564,7 @@ type TwoFactorsStoreGetByUserIDFuncCall struct {
 	Arg1 int64
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 *db.TwoFactor
+	Result0 []*db.WebAuthnCredential
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -2174,46 +4572,47 @@ type TwoFactorsStoreGetByUserIDFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c TwoFactorsStoreGetByUserIDFuncCall) Args() []interface{} {
+func (c TwoFactorsStoreListWebAuthnCredentialsFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c TwoFactorsStoreGetByUserIDFuncCall) Results() []interface{} {
+func (c TwoFactorsStoreListWebAuthnCredentialsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// TwoFactorsStoreIsUserEnabledFunc describes the behavior when the
-// IsUserEnabled method of the parent MockTwoFactorsStore instance is
-// invoked.
-type TwoFactorsStoreIsUserEnabledFunc struct {
-	defaultHook func(context.Context, int64) bool
-	hooks       []func(context.Context, int64) bool
-	history     []TwoFactorsStoreIsUserEnabledFuncCall
+// TwoFactorsStoreUseWebAuthnCredentialFunc describes the behavior when the
+// UseWebAuthnCredential method of the parent MockTwoFactorsStore instance
+// is invoked.
+type TwoFactorsStoreUseWebAuthnCredentialFunc struct {
+	defaultHook func(context.Context, int64, uint32) error
+	hooks       []func(context.Context, int64, uint32) error
+	history     []TwoFactorsStoreUseWebAuthnCredentialFuncCall
 	mutex       sync.Mutex
 }
 
-// IsUserEnabled delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockTwoFactorsStore) IsUserEnabled(v0 context.Context, v1 int64) bool {
-	r0 := m.IsUserEnabledFunc.nextHook()(v0, v1)
-	m.IsUserEnabledFunc.appendCall(TwoFactorsStoreIsUserEnabledFuncCall{v0, v1, r0})
+// UseWebAuthnCredential delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockTwoFactorsStore) UseWebAuthnCredential(v0 context.Context, v1 int64, v2 uint32) error {
+	r0 := m.UseWebAuthnCredentialFunc.nextHook()(v0, v1, v2)
+	m.UseWebAuthnCredentialFunc.appendCall(TwoFactorsStoreUseWebAuthnCredentialFuncCall{v0, v1, v2, r0})
 	return r0
 }
 
-// SetDefaultHook sets function that is called when the IsUserEnabled method
-// of the parent MockTwoFactorsStore instance is invoked and the hook queue
-// is empty.
-func (f *TwoFactorsStoreIsUserEnabledFunc) SetDefaultHook(hook func(context.Context, int64) bool) {
+// SetDefaultHook sets function that is called when the
+// UseWebAuthnCredential method of the parent MockTwoFactorsStore instance
+// is invoked and the hook queue is empty.
+func (f *TwoFactorsStoreUseWebAuthnCredentialFunc) SetDefaultHook(hook func(context.Context, int64, uint32) error) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// IsUserEnabled method of the parent MockTwoFactorsStore instance invokes
-// the hook at the front of the queue and discards it. After the queue is
-// empty, the default hook function is invoked for any future action.
-func (f *TwoFactorsStoreIsUserEnabledFunc) PushHook(hook func(context.Context, int64) bool) {
+// UseWebAuthnCredential method of the parent MockTwoFactorsStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *TwoFactorsStoreUseWebAuthnCredentialFunc) PushHook(hook func(context.Context, int64, uint32) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -2221,20 +4620,20 @@ func (f *TwoFactorsStoreIsUserEnabledFunc) PushHook(hook func(context.Context, i
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *TwoFactorsStoreIsUserEnabledFunc) SetDefaultReturn(r0 bool) {
-	f.SetDefaultHook(func(context.Context, int64) bool {
+func (f *TwoFactorsStoreUseWebAuthnCredentialFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64, uint32) error {
 		return r0
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *TwoFactorsStoreIsUserEnabledFunc) PushReturn(r0 bool) {
-	f.PushHook(func(context.Context, int64) bool {
+func (f *TwoFactorsStoreUseWebAuthnCredentialFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64, uint32) error {
 		return r0
 	})
 }
 
-func (f *TwoFactorsStoreIsUserEnabledFunc) nextHook() func(context.Context, int64) bool {
+func (f *TwoFactorsStoreUseWebAuthnCredentialFunc) nextHook() func(context.Context, int64, uint32) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -2247,46 +4646,51 @@ func (f *TwoFactorsStoreIsUserEnabledFunc) nextHook() func(context.Context, int6
 	return hook
 }
 
-func (f *TwoFactorsStoreIsUserEnabledFunc) appendCall(r0 TwoFactorsStoreIsUserEnabledFuncCall) {
+func (f *TwoFactorsStoreUseWebAuthnCredentialFunc) appendCall(r0 TwoFactorsStoreUseWebAuthnCredentialFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of TwoFactorsStoreIsUserEnabledFuncCall
-// objects describing the invocations of this function.
-func (f *TwoFactorsStoreIsUserEnabledFunc) History() []TwoFactorsStoreIsUserEnabledFuncCall {
+// History returns a sequence of
+// TwoFactorsStoreUseWebAuthnCredentialFuncCall objects describing the
+// invocations of this function.
+func (f *TwoFactorsStoreUseWebAuthnCredentialFunc) History() []TwoFactorsStoreUseWebAuthnCredentialFuncCall {
 	f.mutex.Lock()
-	history := make([]TwoFactorsStoreIsUserEnabledFuncCall, len(f.history))
+	history := make([]TwoFactorsStoreUseWebAuthnCredentialFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// TwoFactorsStoreIsUserEnabledFuncCall is an object that describes an
-// invocation of method IsUserEnabled on an instance of MockTwoFactorsStore.
-type TwoFactorsStoreIsUserEnabledFuncCall struct {
+// TwoFactorsStoreUseWebAuthnCredentialFuncCall is an object that describes
+// an invocation of method UseWebAuthnCredential on an instance of
+// MockTwoFactorsStore.
+type TwoFactorsStoreUseWebAuthnCredentialFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
 	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 uint32
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 bool
+	Result0 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c TwoFactorsStoreIsUserEnabledFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1}
+func (c TwoFactorsStoreUseWebAuthnCredentialFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c TwoFactorsStoreIsUserEnabledFuncCall) Results() []interface{} {
+func (c TwoFactorsStoreUseWebAuthnCredentialFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
@@ -2296,6 +4700,9 @@ type MockUsersStore struct {
 	// AuthenticateFunc is an instance of a mock function object controlling
 	// the behavior of the method Authenticate.
 	AuthenticateFunc *UsersStoreAuthenticateFunc
+	// AuthenticateExternalFunc is an instance of a mock function object
+	// controlling the behavior of the method AuthenticateExternal.
+	AuthenticateExternalFunc *UsersStoreAuthenticateExternalFunc
 	// CreateFunc is an instance of a mock function object controlling the
 	// behavior of the method Create.
 	CreateFunc *UsersStoreCreateFunc
@@ -2308,6 +4715,9 @@ type MockUsersStore struct {
 	// GetByUsernameFunc is an instance of a mock function object
 	// controlling the behavior of the method GetByUsername.
 	GetByUsernameFunc *UsersStoreGetByUsernameFunc
+	// SetAdminFunc is an instance of a mock function object controlling the
+	// behavior of the method SetAdmin.
+	SetAdminFunc *UsersStoreSetAdminFunc
 }
 
 // NewMockUsersStore creates a new mock of the UsersStore interface. All
@@ -2319,6 +4729,11 @@ func NewMockUsersStore() *MockUsersStore {
 				return
 			},
 		},
+		AuthenticateExternalFunc: &UsersStoreAuthenticateExternalFunc{
+			defaultHook: func(context.Context, int64, *auth.ExternalAccount) (r0 *db.User, r1 error) {
+				return
+			},
+		},
 		CreateFunc: &UsersStoreCreateFunc{
 			defaultHook: func(context.Context, string, string, db.CreateUserOptions) (r0 *db.User, r1 error) {
 				return
@@ -2339,6 +4754,11 @@ func NewMockUsersStore() *MockUsersStore {
 				return
 			},
 		},
+		SetAdminFunc: &UsersStoreSetAdminFunc{
+			defaultHook: func(context.Context, int64, bool) (r0 error) {
+				return
+			},
+		},
 	}
 }
 
@@ -2351,6 +4771,11 @@ func NewStrictMockUsersStore() *MockUsersStore {
 				panic("unexpected invocation of MockUsersStore.Authenticate")
 			},
 		},
+		AuthenticateExternalFunc: &UsersStoreAuthenticateExternalFunc{
+			defaultHook: func(context.Context, int64, *auth.ExternalAccount) (*db.User, error) {
+				panic("unexpected invocation of MockUsersStore.AuthenticateExternal")
+			},
+		},
 		CreateFunc: &UsersStoreCreateFunc{
 			defaultHook: func(context.Context, string, string, db.CreateUserOptions) (*db.User, error) {
 				panic("unexpected invocation of MockUsersStore.Create")
@@ -2371,6 +4796,11 @@ func NewStrictMockUsersStore() *MockUsersStore {
 				panic("unexpected invocation of MockUsersStore.GetByUsername")
 			},
 		},
+		SetAdminFunc: &UsersStoreSetAdminFunc{
+			defaultHook: func(context.Context, int64, bool) error {
+				panic("unexpected invocation of MockUsersStore.SetAdmin")
+			},
+		},
 	}
 }
 
@@ -2381,6 +4811,9 @@ func NewMockUsersStoreFrom(i db.UsersStore) *MockUsersStore {
 		AuthenticateFunc: &UsersStoreAuthenticateFunc{
 			defaultHook: i.Authenticate,
 		},
+		AuthenticateExternalFunc: &UsersStoreAuthenticateExternalFunc{
+			defaultHook: i.AuthenticateExternal,
+		},
 		CreateFunc: &UsersStoreCreateFunc{
 			defaultHook: i.Create,
 		},
@@ -2393,6 +4826,9 @@ func NewMockUsersStoreFrom(i db.UsersStore) *MockUsersStore {
 		GetByUsernameFunc: &UsersStoreGetByUsernameFunc{
 			defaultHook: i.GetByUsername,
 		},
+		SetAdminFunc: &UsersStoreSetAdminFunc{
+			defaultHook: i.SetAdmin,
+		},
 	}
 }
 
@@ -2510,6 +4946,119 @@ func (c UsersStoreAuthenticateFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// UsersStoreAuthenticateExternalFunc describes the behavior when the
+// AuthenticateExternal method of the parent MockUsersStore instance is
+// invoked.
+type UsersStoreAuthenticateExternalFunc struct {
+	defaultHook func(context.Context, int64, *auth.ExternalAccount) (*db.User, error)
+	hooks       []func(context.Context, int64, *auth.ExternalAccount) (*db.User, error)
+	history     []UsersStoreAuthenticateExternalFuncCall
+	mutex       sync.Mutex
+}
+
+// AuthenticateExternal delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockUsersStore) AuthenticateExternal(v0 context.Context, v1 int64, v2 *auth.ExternalAccount) (*db.User, error) {
+	r0, r1 := m.AuthenticateExternalFunc.nextHook()(v0, v1, v2)
+	m.AuthenticateExternalFunc.appendCall(UsersStoreAuthenticateExternalFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the AuthenticateExternal
+// method of the parent MockUsersStore instance is invoked and the hook
+// queue is empty.
+func (f *UsersStoreAuthenticateExternalFunc) SetDefaultHook(hook func(context.Context, int64, *auth.ExternalAccount) (*db.User, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// AuthenticateExternal method of the parent MockUsersStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *UsersStoreAuthenticateExternalFunc) PushHook(hook func(context.Context, int64, *auth.ExternalAccount) (*db.User, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *UsersStoreAuthenticateExternalFunc) SetDefaultReturn(r0 *db.User, r1 error) {
+	f.SetDefaultHook(func(context.Context, int64, *auth.ExternalAccount) (*db.User, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *UsersStoreAuthenticateExternalFunc) PushReturn(r0 *db.User, r1 error) {
+	f.PushHook(func(context.Context, int64, *auth.ExternalAccount) (*db.User, error) {
+		return r0, r1
+	})
+}
+
+func (f *UsersStoreAuthenticateExternalFunc) nextHook() func(context.Context, int64, *auth.ExternalAccount) (*db.User, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *UsersStoreAuthenticateExternalFunc) appendCall(r0 UsersStoreAuthenticateExternalFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of UsersStoreAuthenticateExternalFuncCall
+// objects describing the invocations of this function.
+func (f *UsersStoreAuthenticateExternalFunc) History() []UsersStoreAuthenticateExternalFuncCall {
+	f.mutex.Lock()
+	history := make([]UsersStoreAuthenticateExternalFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// UsersStoreAuthenticateExternalFuncCall is an object that describes an
+// invocation of method AuthenticateExternal on an instance of
+// MockUsersStore.
+type UsersStoreAuthenticateExternalFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 *auth.ExternalAccount
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *db.User
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c UsersStoreAuthenticateExternalFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c UsersStoreAuthenticateExternalFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // UsersStoreCreateFunc describes the behavior when the Create method of the
 // parent MockUsersStore instance is invoked.
 type UsersStoreCreateFunc struct {
@@ -2946,3 +5495,111 @@ func (c UsersStoreGetByUsernameFuncCall) Args() []interface{} {
 func (c UsersStoreGetByUsernameFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
+
+// UsersStoreSetAdminFunc describes the behavior when the SetAdmin method of
+// the parent MockUsersStore instance is invoked.
+type UsersStoreSetAdminFunc struct {
+	defaultHook func(context.Context, int64, bool) error
+	hooks       []func(context.Context, int64, bool) error
+	history     []UsersStoreSetAdminFuncCall
+	mutex       sync.Mutex
+}
+
+// SetAdmin delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockUsersStore) SetAdmin(v0 context.Context, v1 int64, v2 bool) error {
+	r0 := m.SetAdminFunc.nextHook()(v0, v1, v2)
+	m.SetAdminFunc.appendCall(UsersStoreSetAdminFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the SetAdmin method of
+// the parent MockUsersStore instance is invoked and the hook queue is
+// empty.
+func (f *UsersStoreSetAdminFunc) SetDefaultHook(hook func(context.Context, int64, bool) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetAdmin method of the parent MockUsersStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *UsersStoreSetAdminFunc) PushHook(hook func(context.Context, int64, bool) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *UsersStoreSetAdminFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64, bool) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *UsersStoreSetAdminFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64, bool) error {
+		return r0
+	})
+}
+
+func (f *UsersStoreSetAdminFunc) nextHook() func(context.Context, int64, bool) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *UsersStoreSetAdminFunc) appendCall(r0 UsersStoreSetAdminFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of UsersStoreSetAdminFuncCall objects
+// describing the invocations of this function.
+func (f *UsersStoreSetAdminFunc) History() []UsersStoreSetAdminFuncCall {
+	f.mutex.Lock()
+	history := make([]UsersStoreSetAdminFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// UsersStoreSetAdminFuncCall is an object that describes an invocation of
+// method SetAdmin on an instance of MockUsersStore.
+type UsersStoreSetAdminFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 bool
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c UsersStoreSetAdminFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c UsersStoreSetAdminFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
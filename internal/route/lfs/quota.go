@@ -0,0 +1,48 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"gogs.io/gogs/internal/db"
+)
+
+// checkQuota returns a message describing the exceeded quota if storing
+// additional "size" bytes of LFS objects in the repository would exceed
+// either the repository quota or the owner quota. It returns an empty string
+// when quotas are not exceeded.
+func checkQuota(ctx context.Context, repo *db.Repository, size int64) (string, error) {
+	remaining, message, err := db.RemainingLFSQuota(ctx, repo)
+	if err != nil {
+		return "", err
+	} else if remaining >= 0 && size > remaining {
+		return message, nil
+	}
+	return "", nil
+}
+
+var errQuotaExceeded = errors.New("quota exceeded")
+
+// quotaReader reads from the underlying reader and fails with errQuotaExceeded
+// once more than "remaining" bytes have been read, which enforces the quota on
+// request bodies whose size is not known in advance.
+type quotaReader struct {
+	io.ReadCloser
+	remaining int64
+	exceeded  bool
+}
+
+func (r *quotaReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		r.exceeded = true
+		return n, errQuotaExceeded
+	}
+	return n, err
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/macaron.v1"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/lfsutil"
+)
+
+func Test_checkQuota(t *testing.T) {
+	const mb = 1024 * 1024
+	mockLFSStore := NewMockLFSStore()
+	mockLFSStore.TotalSizeByRepoIDFunc.SetDefaultReturn(5*mb, nil)
+	mockLFSStore.TotalSizeByOwnerIDFunc.SetDefaultReturn(8*mb, nil)
+	db.SetMockLFSStore(t, mockLFSStore)
+
+	tests := []struct {
+		name       string
+		opts       conf.LFSOpts
+		size       int64
+		expMessage string
+	}{
+		{
+			name: "unlimited",
+			size: 100 * mb,
+		},
+		{
+			name: "within quotas",
+			opts: conf.LFSOpts{RepoQuota: 10, OwnerQuota: 10},
+			size: 2 * mb,
+		},
+		{
+			name:       "exceeds repository quota",
+			opts:       conf.LFSOpts{RepoQuota: 6},
+			size:       2 * mb,
+			expMessage: "Repository LFS quota exceeded",
+		},
+		{
+			name:       "exceeds owner quota",
+			opts:       conf.LFSOpts{RepoQuota: 10, OwnerQuota: 9},
+			size:       2 * mb,
+			expMessage: "Owner LFS quota exceeded",
+		},
+		{
+			name:       "repository quota already exceeded",
+			opts:       conf.LFSOpts{RepoQuota: 4, OwnerQuota: 100},
+			size:       1 * mb,
+			expMessage: "Repository LFS quota exceeded",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf.SetMockLFS(t, test.opts)
+
+			message, err := checkQuota(context.Background(), &db.Repository{ID: 1, OwnerID: 1}, test.size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.expMessage, message)
+		})
+	}
+}
+
+func Test_serveBatch_quota(t *testing.T) {
+	conf.SetMockServer(t, conf.ServerOpts{
+		ExternalURL: "https://gogs.example.com/",
+	})
+	conf.SetMockLFS(t, conf.LFSOpts{RepoQuota: 1})
+
+	mockLFSStore := NewMockLFSStore()
+	mockLFSStore.GetObjectsByOIDsFunc.SetDefaultReturn(
+		[]*db.LFSObject{
+			{Size: 1024 * 1024, OID: "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f"},
+		},
+		nil,
+	)
+	mockLFSStore.TotalSizeByRepoIDFunc.SetDefaultReturn(1024, nil)
+	db.SetMockLFSStore(t, mockLFSStore)
+
+	m := macaron.New()
+	m.Use(func(c *macaron.Context) {
+		c.Map(&db.User{Name: "owner"})
+		c.Map(&db.Repository{Name: "repo"})
+	})
+	h := &batchHandler{storagers: map[lfsutil.Storage]lfsutil.Storager{}}
+	m.Post("/", h.serveBatch)
+
+	// The object that is already stored is not counted.
+	r, err := http.NewRequest("POST", "/", bytes.NewBufferString(`{
+"operation": "upload",
+"objects": [
+	{"oid": "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f", "size": 1048576},
+	{"oid": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", "size": 1048576}
+]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, r)
+
+	resp := rr.Result()
+	assert.Equal(t, http.StatusInsufficientStorage, resp.StatusCode)
+	assertJSONBody(t, `{"message": "Repository LFS quota exceeded"}`, resp)
+}
+
+func Test_basicHandler_serveUpload_quota(t *testing.T) {
+	conf.SetMockLFS(t, conf.LFSOpts{RepoQuota: 1})
+
+	mockLFSStore := NewMockLFSStore()
+	mockLFSStore.GetObjectByOIDFunc.SetDefaultReturn(nil, db.ErrLFSObjectNotExist{})
+	mockLFSStore.TotalSizeByRepoIDFunc.SetDefaultReturn(1024*1024-5, nil)
+	db.SetMockLFSStore(t, mockLFSStore)
+
+	s := &mockStorage{buf: &bytes.Buffer{}}
+	basic := &basicHandler{
+		defaultStorage: s.Storage(),
+		storagers: map[lfsutil.Storage]lfsutil.Storager{
+			s.Storage(): s,
+		},
+	}
+
+	m := macaron.New()
+	m.Use(func(c *macaron.Context) {
+		c.Map(&db.Repository{Name: "repo"})
+		c.Map(lfsutil.OID("ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f"))
+	})
+	m.Put("/", basic.serveUpload)
+
+	// The content length is unknown, the upload is rejected once the body
+	// exceeds the remaining quota.
+	r, err := http.NewRequest("PUT", "/", ioutil.NopCloser(strings.NewReader("Hello world!")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(0), r.ContentLength)
+
+	rr := httptest.NewRecorder()
+	m.ServeHTTP(rr, r)
+
+	resp := rr.Result()
+	assert.Equal(t, http.StatusInsufficientStorage, resp.StatusCode)
+	assertJSONBody(t, `{"message": "Repository LFS quota exceeded"}`, resp)
+	assert.Empty(t, mockLFSStore.CreateObjectFunc.History())
+}
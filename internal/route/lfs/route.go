@@ -5,6 +5,7 @@
 package lfs
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -24,20 +25,33 @@ func RegisterRoutes(r *macaron.Router) {
 	verifyContentTypeJSON := verifyHeader("Content-Type", contentType, http.StatusBadRequest)
 	verifyContentTypeStream := verifyHeader("Content-Type", "application/octet-stream", http.StatusBadRequest)
 
+	storagers, err := db.NewLFSStoragers()
+	if err != nil {
+		log.Fatal("Failed to create LFS storage backends: %v", err)
+	}
+
 	r.Group("", func() {
-		r.Post("/objects/batch", authorize(db.AccessModeRead), verifyAccept, verifyContentTypeJSON, serveBatch)
+		batch := &batchHandler{
+			storagers: storagers,
+		}
+		r.Post("/objects/batch", authorize(db.AccessModeRead), verifyAccept, verifyContentTypeJSON, batch.serveBatch)
 		r.Group("/objects/basic", func() {
 			basic := &basicHandler{
 				defaultStorage: lfsutil.Storage(conf.LFS.Storage),
-				storagers: map[lfsutil.Storage]lfsutil.Storager{
-					lfsutil.StorageLocal: &lfsutil.LocalStorage{Root: conf.LFS.ObjectsPath},
-				},
+				storagers:      storagers,
 			}
 			r.Combo("/:oid", verifyOID()).
 				Get(authorize(db.AccessModeRead), basic.serveDownload).
 				Put(authorize(db.AccessModeWrite), verifyContentTypeStream, basic.serveUpload)
 			r.Post("/verify", authorize(db.AccessModeWrite), verifyAccept, verifyContentTypeJSON, basic.serveVerify)
 		})
+		r.Group("/locks", func() {
+			r.Combo("").
+				Get(authorize(db.AccessModeRead), verifyAccept, serveListLocks).
+				Post(authorize(db.AccessModeWrite), verifyAccept, verifyContentTypeJSON, serveCreateLock)
+			r.Post("/verify", authorize(db.AccessModeWrite), verifyAccept, verifyContentTypeJSON, serveVerifyLocks)
+			r.Post("/:id/unlock", authorize(db.AccessModeWrite), verifyAccept, verifyContentTypeJSON, serveUnlock)
+		})
 	}, authenticate())
 }
 
@@ -71,6 +85,7 @@ func authenticate() macaron.Handler {
 		}
 
 		// If username and password authentication failed, try again using username as an access token.
+		scopes := auth.AllScopes
 		if auth.IsErrBadCredentials(err) {
 			token, err := db.AccessTokens.GetBySHA1(c.Req.Context(), username)
 			if err != nil {
@@ -85,6 +100,7 @@ func authenticate() macaron.Handler {
 			if err = db.AccessTokens.Touch(c.Req.Context(), token.ID); err != nil {
 				log.Error("Failed to touch access token: %v", err)
 			}
+			scopes = token.ScopeList()
 
 			user, err = db.Users.GetByID(c.Req.Context(), token.UserID)
 			if err != nil {
@@ -96,15 +112,41 @@ func authenticate() macaron.Handler {
 			}
 		}
 
+		if user.ProhibitLogin {
+			responseJSON(c.Resp, http.StatusForbidden, responseError{
+				Message: "User is not allowed to sign in",
+			})
+			return
+		}
+
 		log.Trace("[LFS] Authenticated user: %s", user.Name)
 
 		c.Map(user)
+		c.Map(&authenticatedUser{User: user, Scopes: scopes})
 	}
 }
 
+// authenticatedUser is the user who sends the request. It is mapped separately
+// because the *db.User is overridden by the repository owner once authorized.
+type authenticatedUser struct {
+	*db.User
+	Scopes auth.Scopes // The scopes granted to the access token, or all scopes when authenticated by password
+}
+
 // authorize tries to authorize the user to the context repository with given access mode.
 func authorize(mode db.AccessMode) macaron.Handler {
-	return func(c *macaron.Context, actor *db.User) {
+	return func(c *macaron.Context, actor *authenticatedUser) {
+		scope := auth.ScopeRepoRead
+		if mode > db.AccessModeRead {
+			scope = auth.ScopeRepoWrite
+		}
+		if !actor.Scopes.Has(scope) {
+			responseJSON(c.Resp, http.StatusForbidden, responseError{
+				Message: fmt.Sprintf("The access token is not granted the scope: %s", scope),
+			})
+			return
+		}
+
 		username := c.Params(":username")
 		reponame := strings.TrimSuffix(c.Params(":reponame"), ".git")
 
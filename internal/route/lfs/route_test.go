@@ -127,6 +127,28 @@ func Test_authenticate(t *testing.T) {
 			expHeader:     http.Header{},
 			expBody:       "ID: 1, Name: unknwon",
 		},
+		{
+			name: "user of access token is prohibited from signing in",
+			header: http.Header{
+				"Authorization": []string{"Basic dXNlcm5hbWU="},
+			},
+			mockUsersStore: func() db.UsersStore {
+				mock := NewMockUsersStore()
+				mock.AuthenticateFunc.SetDefaultReturn(nil, auth.ErrBadCredentials{})
+				mock.GetByIDFunc.SetDefaultReturn(&db.User{ID: 1, Name: "unknwon", ProhibitLogin: true}, nil)
+				return mock
+			},
+			mockAccessTokensStore: func() db.AccessTokensStore {
+				mock := NewMockAccessTokensStore()
+				mock.GetBySHA1Func.SetDefaultReturn(&db.AccessToken{}, nil)
+				return mock
+			},
+			expStatusCode: http.StatusForbidden,
+			expHeader: http.Header{
+				"Content-Type": []string{"application/vnd.git-lfs+json"},
+			},
+			expBody: `{"message":"User is not allowed to sign in"}` + "\n",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -166,12 +188,20 @@ func Test_authorize(t *testing.T) {
 	tests := []struct {
 		name           string
 		authroize      macaron.Handler
+		actorScopes    auth.Scopes
 		mockUsersStore func() db.UsersStore
 		mockReposStore func() db.ReposStore
 		mockPermsStore func() db.PermsStore
 		expStatusCode  int
 		expBody        string
 	}{
+		{
+			name:          "access token is not granted the scope",
+			authroize:     authorize(db.AccessModeWrite),
+			actorScopes:   auth.Scopes{auth.ScopeRepoRead},
+			expStatusCode: http.StatusForbidden,
+			expBody:       `{"message":"The access token is not granted the scope: repo:write"}` + "\n",
+		},
 		{
 			name:      "user does not exist",
 			authroize: authorize(db.AccessModeNone),
@@ -269,7 +299,11 @@ func Test_authorize(t *testing.T) {
 			m := macaron.New()
 			m.Use(macaron.Renderer())
 			m.Use(func(c *macaron.Context) {
-				c.Map(&db.User{})
+				scopes := test.actorScopes
+				if scopes == nil {
+					scopes = auth.AllScopes
+				}
+				c.Map(&authenticatedUser{User: &db.User{}, Scopes: scopes})
 			})
 			m.Get("/:username/:reponame", test.authroize, func(w http.ResponseWriter, owner *db.User, repo *db.Repository) {
 				fmt.Fprintf(w, "owner.Name: %s, repo.Name: %s", owner.Name, repo.Name)
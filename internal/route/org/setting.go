@@ -75,6 +75,7 @@ func SettingsPost(c *context.Context, f form.UpdateOrgSetting) {
 	org.Description = f.Description
 	org.Website = f.Website
 	org.Location = f.Location
+	org.RequireTwoFactor = f.RequireTwoFactor
 	if err := db.UpdateUser(org); err != nil {
 		c.Error(err, "update user")
 		return
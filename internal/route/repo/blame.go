@@ -0,0 +1,66 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/template/highlight"
+	"gogs.io/gogs/internal/tool"
+)
+
+const (
+	BLAME = "repo/blame"
+)
+
+// Blame shows the commit that last changed each line of the file, consecutive
+// lines changed by the same commit are grouped together.
+func Blame(c *context.Context) {
+	c.Data["PageIsViewFiles"] = true
+	c.Data["RequireHighlightJS"] = true
+
+	entry, err := c.Repo.Commit.TreeEntry(c.Repo.TreePath)
+	if err != nil {
+		c.NotFoundOrError(gitutil.NewError(err), "get tree entry")
+		return
+	} else if entry.IsTree() {
+		c.Redirect(c.Repo.RepoLink + "/src/" + c.Repo.BranchName + "/" + c.Repo.TreePath)
+		return
+	}
+
+	blob := entry.Blob()
+	c.Data["Title"] = c.Tr("repo.blame") + " · " + c.Repo.TreePath + " @ " + c.Repo.BranchName
+	c.Data["FileName"] = blob.Name()
+	c.Data["FileSize"] = blob.Size()
+	c.Data["HighlightClass"] = highlight.FileNameToHighlightClass(blob.Name())
+	c.Data["RawFileLink"] = c.Repo.RepoLink + "/raw/" + c.Repo.BranchName + "/" + c.Repo.TreePath
+
+	if blob.Size() >= conf.UI.MaxDisplayFileSize {
+		c.Data["IsFileTooLarge"] = true
+		c.Success(BLAME)
+		return
+	}
+
+	p, err := blob.Bytes()
+	if err != nil {
+		c.Error(err, "read blob")
+		return
+	}
+	if !tool.IsTextFile(p) {
+		c.Data["IsNotTextFile"] = true
+		c.Success(BLAME)
+		return
+	}
+
+	parts, err := gitutil.Blame(c.Repo.GitRepo.Path(), c.Repo.CommitID, c.Repo.TreePath)
+	if err != nil {
+		c.Error(err, "blame")
+		return
+	}
+	c.Data["BlameParts"] = parts
+
+	c.Success(BLAME)
+}
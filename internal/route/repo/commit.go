@@ -34,6 +34,27 @@ func RefCommits(c *context.Context) {
 	}
 }
 
+// setCommitStatuses sets the combined state of the latest commit statuses of
+// given commits in the repository to the context data.
+func setCommitStatuses(c *context.Context, repoID int64, commits []*git.Commit) {
+	shas := make([]string, len(commits))
+	for i := range commits {
+		shas[i] = commits[i].ID.String()
+	}
+
+	latest, err := db.CommitStatuses.ListLatestBySHAs(c.Req.Context(), repoID, shas...)
+	if err != nil {
+		c.Error(err, "list latest commit statuses")
+		return
+	}
+
+	states := make(map[string]db.CommitStatusState, len(latest))
+	for sha, statuses := range latest {
+		states[sha] = db.CombinedCommitStatusState(statuses)
+	}
+	c.Data["CommitStatuses"] = states
+}
+
 // TODO(unknwon)
 func RenderIssueLinks(oldCommits []*git.Commit, _ string) []*git.Commit {
 	return oldCommits
@@ -61,6 +82,10 @@ func renderCommits(c *context.Context, filename string) {
 
 	commits = RenderIssueLinks(commits, c.Repo.RepoLink)
 	c.Data["Commits"] = db.ValidateCommitsWithEmails(commits)
+	setCommitStatuses(c, c.Repo.Repository.ID, commits)
+	if c.Written() {
+		return
+	}
 
 	if page > 1 {
 		c.Data["HasPrevious"] = true
@@ -98,6 +123,10 @@ func SearchCommits(c *context.Context) {
 
 	commits = RenderIssueLinks(commits, c.Repo.RepoLink)
 	c.Data["Commits"] = db.ValidateCommitsWithEmails(commits)
+	setCommitStatuses(c, c.Repo.Repository.ID, commits)
+	if c.Written() {
+		return
+	}
 
 	c.Data["Keyword"] = keyword
 	c.Data["Username"] = c.Repo.Owner.Name
@@ -148,6 +177,16 @@ func Diff(c *context.Context) {
 		return
 	}
 
+	latest, err := db.CommitStatuses.ListLatestBySHAs(c.Req.Context(), c.Repo.Repository.ID, commit.ID.String())
+	if err != nil {
+		c.Error(err, "list latest commit statuses")
+		return
+	}
+	if statuses := latest[commit.ID.String()]; len(statuses) > 0 {
+		c.Data["Statuses"] = statuses
+		c.Data["CombinedStatus"] = db.CombinedCommitStatusState(statuses)
+	}
+
 	c.RawTitle(commit.Summary() + " · " + tool.ShortSHA1(commitID))
 	c.Data["CommitID"] = commitID
 	c.Data["IsSplitStyle"] = c.Query("style") == "split"
@@ -208,6 +247,11 @@ func CompareDiff(c *context.Context) {
 		return
 	}
 
+	setCommitStatuses(c, c.Repo.Repository.ID, commits)
+	if c.Written() {
+		return
+	}
+
 	c.Data["IsSplitStyle"] = c.Query("style") == "split"
 	c.Data["CommitRepoLink"] = c.Repo.RepoLink
 	c.Data["Commits"] = db.ValidateCommitsWithEmails(commits)
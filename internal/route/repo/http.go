@@ -142,6 +142,16 @@ func HTTPContexter() macaron.Handler {
 				}
 				return
 			}
+
+			scope := auth.ScopeRepoWrite
+			if isPull {
+				scope = auth.ScopeRepoRead
+			}
+			if !token.ScopeList().Has(scope) {
+				askCredentials(c, http.StatusForbidden, fmt.Sprintf("The access token is not granted the scope: %s", scope))
+				return
+			}
+
 			if err = db.AccessTokens.Touch(c.Req.Context(), token.ID); err != nil {
 				log.Error("Failed to touch access token: %v", err)
 			}
@@ -160,6 +170,25 @@ Please create and use personal access token on user settings page`)
 			return
 		}
 
+		if authUser.ProhibitLogin {
+			askCredentials(c, http.StatusForbidden, "User is not allowed to sign in")
+			return
+		}
+
+		// Organizations may require 2FA after the access token was created.
+		if !authUser.IsEnabledTwoFactor() {
+			orgs, err := db.GetOrgsRequireTwoFactorByUserID(authUser.ID)
+			if err != nil {
+				c.Status(http.StatusInternalServerError)
+				log.Error("Failed to get organizations require two-factor authentication [user_id: %d]: %v", authUser.ID, err)
+				return
+			} else if len(orgs) > 0 {
+				askCredentials(c, http.StatusForbidden, fmt.Sprintf(`Organization %q requires members to enable two-factor authentication
+Please enable two-factor authentication, and create and use personal access token on user settings page`, orgs[0].Name))
+				return
+			}
+		}
+
 		log.Trace("[Git] Authenticated user: %s", authUser.Name)
 
 		mode := db.AccessModeWrite
@@ -0,0 +1,123 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/macaron.v1"
+
+	"gogs.io/gogs/internal/auth"
+	"gogs.io/gogs/internal/db"
+)
+
+// stubUsersStore returns the owner by username and the token user by ID, and
+// never authenticates by password.
+type stubUsersStore struct {
+	db.UsersStore
+	owner     *db.User
+	tokenUser *db.User
+}
+
+func (s *stubUsersStore) GetByUsername(context.Context, string) (*db.User, error) {
+	return s.owner, nil
+}
+
+func (s *stubUsersStore) GetByID(context.Context, int64) (*db.User, error) {
+	return s.tokenUser, nil
+}
+
+func (*stubUsersStore) Authenticate(context.Context, string, string, int64) (*db.User, error) {
+	return nil, auth.ErrBadCredentials{}
+}
+
+type stubReposStore struct {
+	db.ReposStore
+	repo *db.Repository
+}
+
+func (s *stubReposStore) GetByName(context.Context, int64, string) (*db.Repository, error) {
+	return s.repo, nil
+}
+
+type stubAccessTokensStore struct {
+	db.AccessTokensStore
+	token *db.AccessToken
+}
+
+func (s *stubAccessTokensStore) GetBySHA1(context.Context, string) (*db.AccessToken, error) {
+	return s.token, nil
+}
+
+func (*stubAccessTokensStore) Touch(context.Context, int64) error {
+	return nil
+}
+
+// stubTwoFactorsStore reports 2FA is enabled for all users, so no organization
+// requirement needs to be checked.
+type stubTwoFactorsStore struct {
+	db.TwoFactorsStore
+}
+
+func (*stubTwoFactorsStore) IsUserEnabled(context.Context, int64) bool {
+	return true
+}
+
+type stubPermsStore struct {
+	db.PermsStore
+}
+
+func (*stubPermsStore) Authorize(context.Context, int64, int64, db.AccessMode, db.AccessModeOptions) bool {
+	return true
+}
+
+func TestHTTPContexter(t *testing.T) {
+	owner := &db.User{ID: 1, Name: "alice"}
+	db.SetMockReposStore(t, &stubReposStore{repo: &db.Repository{ID: 1, OwnerID: owner.ID, IsPrivate: true}})
+	db.SetMockAccessTokensStore(t, &stubAccessTokensStore{token: &db.AccessToken{UserID: 2, Scopes: string(auth.ScopeRepoRead)}})
+	db.SetMockTwoFactorsStore(t, &stubTwoFactorsStore{})
+	db.SetMockPermsStore(t, &stubPermsStore{})
+
+	m := macaron.New()
+	m.Use(macaron.Renderer())
+	m.Get("/:username/:reponame/*", HTTPContexter(), func(c *HTTPContext) {
+		c.PlainText(http.StatusOK, []byte(c.AuthUser.Name))
+	})
+
+	tests := []struct {
+		name          string
+		tokenUser     *db.User
+		expStatusCode int
+	}{
+		{
+			name:          "active user",
+			tokenUser:     &db.User{ID: 2, Name: "bob", IsActive: true},
+			expStatusCode: http.StatusOK,
+		},
+		{
+			name:          "deactivated user",
+			tokenUser:     &db.User{ID: 2, Name: "bob", IsActive: true, ProhibitLogin: true},
+			expStatusCode: http.StatusForbidden,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db.SetMockUsersStore(t, &stubUsersStore{owner: owner, tokenUser: test.tokenUser})
+
+			r, err := http.NewRequest("GET", "/alice/example.git/info/refs?service=git-upload-pack", nil)
+			require.NoError(t, err)
+			r.SetBasicAuth("0123456789abcdef0123456789abcdef01234567", "")
+
+			rr := httptest.NewRecorder()
+			m.ServeHTTP(rr, r)
+			assert.Equal(t, test.expStatusCode, rr.Code)
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"gogs.io/gogs/internal/db/errors"
 	"gogs.io/gogs/internal/form"
 	"gogs.io/gogs/internal/markup"
+	"gogs.io/gogs/internal/search"
 	"gogs.io/gogs/internal/tool"
 )
 
@@ -144,6 +145,16 @@ func issues(c *context.Context, isPullList bool) {
 	selectLabels := c.Query("labels")
 	milestoneID := c.QueryInt64("milestone")
 	isShowClosed := c.Query("state") == "closed"
+
+	keyword := strings.TrimSpace(c.Query("q"))
+	var query *search.IssueQuery
+	if keyword != "" {
+		query = search.ParseIssueQuery(keyword)
+		if query.State != "" {
+			isShowClosed = query.State == "closed"
+		}
+	}
+
 	issueStats := db.GetIssueStats(&db.IssueStatsOptions{
 		RepoID:      repo.ID,
 		UserID:      uid,
@@ -154,6 +165,37 @@ func issues(c *context.Context, isPullList bool) {
 		IsPull:      isPullList,
 	})
 
+	opts := &db.IssuesOptions{
+		UserID:      uid,
+		AssigneeID:  assigneeID,
+		RepoID:      repo.ID,
+		PosterID:    posterID,
+		MilestoneID: milestoneID,
+		IsMention:   filterMode == db.FILTER_MODE_MENTION,
+		IsPull:      isPullList,
+		Labels:      selectLabels,
+		SortType:    sortType,
+		Query:       query,
+	}
+
+	// Issue statistics do not take the search query into account, count matched
+	// issues instead.
+	if query != nil {
+		var err error
+		opts.IsClosed = false
+		issueStats.OpenCount, err = db.IssuesCount(opts)
+		if err != nil {
+			c.Error(err, "count open issues")
+			return
+		}
+		opts.IsClosed = true
+		issueStats.ClosedCount, err = db.IssuesCount(opts)
+		if err != nil {
+			c.Error(err, "count closed issues")
+			return
+		}
+	}
+
 	page := c.QueryInt("page")
 	if page <= 1 {
 		page = 1
@@ -168,19 +210,9 @@ func issues(c *context.Context, isPullList bool) {
 	pager := paginater.New(total, conf.UI.IssuePagingNum, page, 5)
 	c.Data["Page"] = pager
 
-	issues, err := db.Issues(&db.IssuesOptions{
-		UserID:      uid,
-		AssigneeID:  assigneeID,
-		RepoID:      repo.ID,
-		PosterID:    posterID,
-		MilestoneID: milestoneID,
-		Page:        pager.Current(),
-		IsClosed:    isShowClosed,
-		IsMention:   filterMode == db.FILTER_MODE_MENTION,
-		IsPull:      isPullList,
-		Labels:      selectLabels,
-		SortType:    sortType,
-	})
+	opts.Page = pager.Current()
+	opts.IsClosed = isShowClosed
+	issues, err := db.Issues(opts)
 	if err != nil {
 		c.Error(err, "list issues")
 		return
@@ -235,6 +267,7 @@ func issues(c *context.Context, isPullList bool) {
 	c.Data["MilestoneID"] = milestoneID
 	c.Data["AssigneeID"] = assigneeID
 	c.Data["IsShowClosed"] = isShowClosed
+	c.Data["Keyword"] = keyword
 	if isShowClosed {
 		c.Data["State"] = "closed"
 	} else {
@@ -596,9 +629,26 @@ func viewIssue(c *context.Context, isPullList bool) {
 		participants = make([]*db.User, 1, 10)
 	)
 
+	if issue.IsPull {
+		if err = db.LoadReviewsOfComments(issue.Comments); err != nil {
+			c.Error(err, "load reviews of comments")
+			return
+		}
+	}
+
 	// Render comments and and fetch participants.
 	participants[0] = issue.Poster
 	for _, comment = range issue.Comments {
+		if comment.Type == db.COMMENT_TYPE_REVIEW {
+			comment.RenderedContent = string(markup.Markdown(comment.Content, c.Repo.RepoLink, c.Repo.Repository.ComposeMetas()))
+			if comment.Review != nil {
+				for _, codeComment := range comment.Review.Comments {
+					codeComment.RenderedContent = string(markup.Markdown(codeComment.Content, c.Repo.RepoLink, c.Repo.Repository.ComposeMetas()))
+				}
+			}
+			continue
+		}
+
 		if comment.Type == db.COMMENT_TYPE_COMMENT {
 			comment.RenderedContent = string(markup.Markdown(comment.Content, c.Repo.RepoLink, c.Repo.Repository.ComposeMetas()))
 
@@ -660,6 +710,24 @@ func viewIssue(c *context.Context, isPullList bool) {
 	c.Data["NumParticipants"] = len(participants)
 	c.Data["Issue"] = issue
 	c.Data["IsIssueOwner"] = c.Repo.IsWriter() || (c.IsLogged && issue.IsPoster(c.User.ID))
+	if issue.IsPull && c.IsLogged {
+		c.Data["CanReview"] = true
+		c.Data["CanApprove"] = c.Repo.IsWriter() && !issue.IsPoster(c.User.ID)
+
+		pendingReview, err := db.GetPendingReview(issue.ID, c.User.ID)
+		if err != nil {
+			c.Error(err, "get pending review")
+			return
+		}
+		c.Data["NumPendingComments"] = 0
+		if pendingReview != nil {
+			c.Data["NumPendingComments"], err = db.CountReviewCodeComments(pendingReview.ID)
+			if err != nil {
+				c.Error(err, "count review code comments")
+				return
+			}
+		}
+	}
 	c.Data["SignInLink"] = conf.Server.Subpath + "/user/login?redirect_to=" + c.Data["Link"].(string)
 	c.Success(ISSUE_VIEW)
 }
@@ -20,6 +20,7 @@ import (
 	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/form"
 	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/markup"
 )
 
 const (
@@ -240,6 +241,33 @@ func PrepareViewPullInfo(c *context.Context, issue *db.Issue) *gitutil.PullReque
 	}
 	c.Data["NumCommits"] = len(prMeta.Commits)
 	c.Data["NumFiles"] = prMeta.NumFiles
+
+	headCommitID, err := headGitRepo.BranchCommitID(pull.HeadBranch)
+	if err != nil {
+		c.Error(err, "get head branch commit ID")
+		return nil
+	}
+	latest, err := db.CommitStatuses.ListLatestBySHAs(c.Req.Context(), repo.ID, headCommitID)
+	if err != nil {
+		c.Error(err, "list latest commit statuses")
+		return nil
+	}
+	if statuses := latest[headCommitID]; len(statuses) > 0 {
+		c.Data["Statuses"] = statuses
+		c.Data["CombinedStatus"] = db.CombinedCommitStatusState(statuses)
+	}
+
+	check, err := pull.CheckProtectBranch(headCommitID)
+	if err != nil {
+		c.Error(err, "check protect branch")
+		return nil
+	}
+	c.Data["ProtectionCheck"] = check
+
+	if repo.PullsAllowSquash {
+		pull.Issue = issue
+		c.Data["SquashCommitMessage"] = pull.SquashCommitMessage(prMeta.Commits)
+	}
 	return prMeta
 }
 
@@ -291,6 +319,11 @@ func ViewPullCommits(c *context.Context) {
 		commits = prInfo.Commits
 	}
 
+	setCommitStatuses(c, c.Repo.Repository.ID, commits)
+	if c.Written() {
+		return
+	}
+
 	c.Data["Commits"] = db.ValidateCommitsWithEmails(commits)
 	c.Data["CommitsCount"] = len(commits)
 
@@ -379,6 +412,49 @@ func ViewPullFiles(c *context.Context) {
 	c.Data["IsImageFile"] = commit.IsImageFile
 	c.Data["IsImageFileByIndex"] = commit.IsImageFileByIndex
 
+	var viewerID int64
+	if c.IsLogged {
+		viewerID = c.User.ID
+	}
+	codeComments, err := db.GetCodeComments(issue.ID, viewerID)
+	if err != nil {
+		c.Error(err, "get code comments")
+		return
+	}
+	commentsByLine := make(map[string][]*db.Comment, len(codeComments))
+	for _, comment := range codeComments {
+		comment.RenderedContent = string(markup.Markdown(comment.Content, c.Repo.RepoLink, c.Repo.Repository.ComposeMetas()))
+		key := comment.TreePath + ":" + com.ToStr(comment.Line)
+		commentsByLine[key] = append(commentsByLine[key], comment)
+	}
+	// Code comments on the right side of the diff use positive line numbers,
+	// and comments on deleted lines use negative line numbers of the left side.
+	c.Data["CodeCommentsOf"] = func(treePath string, line *git.DiffLine) []*db.Comment {
+		if line.Type == git.DiffLineDelete {
+			return commentsByLine[treePath+":"+com.ToStr(-line.LeftLine)]
+		}
+		return commentsByLine[treePath+":"+com.ToStr(line.RightLine)]
+	}
+
+	if c.IsLogged && !pull.HasMerged && !issue.IsClosed {
+		c.Data["CanReview"] = true
+		c.Data["CanApprove"] = c.Repo.IsWriter() && !issue.IsPoster(c.User.ID)
+
+		pendingReview, err := db.GetPendingReview(issue.ID, c.User.ID)
+		if err != nil {
+			c.Error(err, "get pending review")
+			return
+		}
+		c.Data["NumPendingComments"] = 0
+		if pendingReview != nil {
+			c.Data["NumPendingComments"], err = db.CountReviewCodeComments(pendingReview.ID)
+			if err != nil {
+				c.Error(err, "count review code comments")
+				return
+			}
+		}
+	}
+
 	// It is possible head repo has been deleted for merged pull requests
 	if pull.HeadRepo != nil {
 		c.Data["Username"] = pull.HeadUserName
@@ -418,7 +494,22 @@ func MergePullRequest(c *context.Context) {
 
 	pr.Issue = issue
 	pr.Issue.Repo = c.Repo.Repository
-	if err = pr.Merge(c.User, c.Repo.GitRepo, db.MergeStyle(c.Query("merge_style")), c.Query("commit_description")); err != nil {
+
+	mergeStyle := db.MergeStyle(c.Query("merge_style"))
+	commitDescription := c.Query("commit_description")
+	if mergeStyle == db.MERGE_STYLE_SQUASH {
+		commitDescription = c.Query("squash_commit_message")
+	}
+	if err = pr.Merge(c.User, c.Repo.GitRepo, mergeStyle, commitDescription); err != nil {
+		if db.IsErrPullRequestProtectionNotSatisfied(err) {
+			c.Flash.Error(c.Tr("repo.pulls.protection_not_satisfied"))
+			c.Redirect(c.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+			return
+		} else if db.IsErrPullRequestNotFastForward(err) {
+			c.Flash.Error(c.Tr("repo.pulls.cannot_fast_forward"))
+			c.Redirect(c.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+			return
+		}
 		c.Error(err, "merge")
 		return
 	}
@@ -427,6 +518,117 @@ func MergePullRequest(c *context.Context) {
 	c.Redirect(c.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
 }
 
+// preparePullRequestForReview loads attributes of the pull request that are
+// required by creating code comments and submitting reviews.
+func preparePullRequestForReview(c *context.Context, issue *db.Issue) (*db.PullRequest, string) {
+	issue.Repo = c.Repo.Repository
+	pr := issue.PullRequest
+	pr.Issue = issue
+	pr.BaseRepo = c.Repo.Repository
+
+	headCommitID, err := pr.HeadCommitID()
+	if err != nil {
+		c.Error(err, "get head commit ID")
+		return nil, ""
+	}
+	return pr, headCommitID
+}
+
+func CreateCodeComment(c *context.Context) {
+	issue := checkPullInfo(c)
+	if c.Written() {
+		return
+	}
+	if issue.IsClosed || issue.PullRequest.HasMerged {
+		c.NotFound()
+		return
+	}
+
+	redirectTo := c.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index) + "/files"
+	if c.Query("style") == "split" {
+		redirectTo += "?style=split"
+	}
+
+	treePath := c.Query("tree_path")
+	line := c.QueryInt64("line")
+	content := c.Query("content")
+	if treePath == "" || line == 0 || content == "" {
+		c.Flash.Error(c.Tr("repo.pulls.code_comment_empty"))
+		c.Redirect(redirectTo)
+		return
+	}
+
+	pr, headCommitID := preparePullRequestForReview(c, issue)
+	if c.Written() {
+		return
+	}
+
+	comment, err := db.CreateCodeComment(c.User, pr, headCommitID, treePath, line, content)
+	if err != nil {
+		c.Error(err, "create code comment")
+		return
+	}
+
+	log.Trace("Code comment created [pull_id: %d, comment_id: %d]", pr.ID, comment.ID)
+	c.Redirect(redirectTo + "#" + comment.HashTag())
+}
+
+func SubmitReview(c *context.Context) {
+	issue := checkPullInfo(c)
+	if c.Written() {
+		return
+	}
+	if issue.IsClosed || issue.PullRequest.HasMerged {
+		c.NotFound()
+		return
+	}
+
+	var typ db.ReviewType
+	switch c.Query("type") {
+	case "comment":
+		typ = db.REVIEW_TYPE_COMMENT
+	case "approve":
+		typ = db.REVIEW_TYPE_APPROVE
+	case "request_changes":
+		typ = db.REVIEW_TYPE_REQUEST_CHANGES
+	default:
+		c.NotFound()
+		return
+	}
+
+	redirectTo := c.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index)
+	if typ != db.REVIEW_TYPE_COMMENT {
+		if !c.Repo.IsWriter() {
+			c.NotFound()
+			return
+		} else if issue.IsPoster(c.User.ID) {
+			c.Flash.Error(c.Tr("repo.pulls.review_own_pull_request"))
+			c.Redirect(redirectTo)
+			return
+		}
+	}
+
+	pr, headCommitID := preparePullRequestForReview(c, issue)
+	if c.Written() {
+		return
+	}
+
+	review, err := db.SubmitReview(c.User, pr, typ, headCommitID, c.Query("content"))
+	if err != nil {
+		if db.IsErrReviewEmpty(err) {
+			c.Flash.Error(c.Tr("repo.pulls.review_empty"))
+			c.Redirect(redirectTo)
+		} else {
+			c.Error(err, "submit review")
+		}
+		return
+	}
+
+	log.Trace("Pull request reviewed [pull_id: %d, review_id: %d, type: %d]", pr.ID, review.ID, typ)
+	c.Flash.Success(c.Tr("repo.pulls.review_submitted"))
+	c.Redirect(redirectTo)
+}
+
 func ParseCompareInfo(c *context.Context) (*db.User, *db.Repository, *git.Repository, *gitutil.PullRequestMeta, string, string) {
 	baseRepo := c.Repo.Repository
 
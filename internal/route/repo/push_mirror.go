@@ -0,0 +1,121 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/form"
+)
+
+const (
+	SETTINGS_PUSH_MIRRORS = "repo/settings/push_mirrors"
+)
+
+func preparePushMirrors(c *context.Context) bool {
+	c.Title("repo.settings.push_mirrors")
+	c.PageIs("SettingsPushMirrors")
+
+	mirrors, err := db.PushMirrors.List(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.Error(err, "list push mirrors")
+		return false
+	}
+	c.Data["PushMirrors"] = mirrors
+	return true
+}
+
+func SettingsPushMirrors(c *context.Context) {
+	if !preparePushMirrors(c) {
+		return
+	}
+	c.Data["sync_interval"] = conf.Mirror.DefaultInterval
+	c.Data["sync_on_push"] = true
+	c.Success(SETTINGS_PUSH_MIRRORS)
+}
+
+func SettingsPushMirrorsPost(c *context.Context, f form.NewPushMirror) {
+	if !preparePushMirrors(c) {
+		return
+	}
+
+	if c.HasError() {
+		c.Success(SETTINGS_PUSH_MIRRORS)
+		return
+	}
+
+	remoteAddr, err := f.ParseRemoteAddr(c.User)
+	if err != nil {
+		if db.IsErrInvalidCloneAddr(err) {
+			c.Data["HasError"] = true
+			c.Data["Err_RemoteAddr"] = true
+			addrErr := err.(db.ErrInvalidCloneAddr)
+			switch {
+			case addrErr.IsURLError:
+				c.RenderWithErr(c.Tr("repo.settings.push_mirrors.remote_addr")+c.Tr("form.url_error"), SETTINGS_PUSH_MIRRORS, &f)
+			case addrErr.IsPermissionDenied:
+				c.RenderWithErr(c.Tr("repo.settings.push_mirrors.local_permission_denied"), SETTINGS_PUSH_MIRRORS, &f)
+			case addrErr.IsInvalidPath:
+				c.RenderWithErr(c.Tr("repo.migrate.invalid_local_path"), SETTINGS_PUSH_MIRRORS, &f)
+			case addrErr.IsBlockedLocalAddress:
+				c.RenderWithErr(c.Tr("repo.migrate.clone_address_resolved_to_blocked_local_address"), SETTINGS_PUSH_MIRRORS, &f)
+			default:
+				c.Error(err, "unexpected error")
+			}
+		} else {
+			c.Error(err, "parse remote address")
+		}
+		return
+	}
+
+	m, err := db.PushMirrors.Create(c.Req.Context(), c.Repo.Repository.ID, conf.Security.SecretKey,
+		db.CreatePushMirrorOptions{
+			RemoteURL:    remoteAddr,
+			Username:     f.AuthUsername,
+			Password:     f.AuthPassword,
+			SyncInterval: f.SyncInterval,
+			SyncOnPush:   f.SyncOnPush,
+		},
+	)
+	if err != nil {
+		c.Error(err, "create push mirror")
+		return
+	}
+	go db.QueuePushMirrorSync(m.ID)
+
+	log.Trace("Push mirror added [repo_id: %d]: %s", c.Repo.Repository.ID, m.RemoteURL)
+	c.Flash.Success(c.Tr("repo.settings.push_mirrors.add_success"))
+	c.Redirect(c.Repo.RepoLink + "/settings/push_mirrors")
+}
+
+func DeletePushMirror(c *context.Context) {
+	if err := db.PushMirrors.DeleteByID(c.Req.Context(), c.Repo.Repository.ID, c.QueryInt64("id")); err != nil {
+		c.Flash.Error("DeletePushMirror: " + err.Error())
+	} else {
+		c.Flash.Success(c.Tr("repo.settings.push_mirrors.deletion_success"))
+	}
+
+	c.JSONSuccess(map[string]interface{}{
+		"redirect": c.Repo.RepoLink + "/settings/push_mirrors",
+	})
+}
+
+func SyncPushMirror(c *context.Context) {
+	m, err := db.PushMirrors.GetByID(c.Req.Context(), c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get push mirror")
+		return
+	} else if m.RepoID != c.Repo.Repository.ID {
+		c.NotFound()
+		return
+	}
+
+	go db.QueuePushMirrorSync(m.ID)
+	c.Flash.Info(c.Tr("repo.settings.push_mirrors.sync_in_progress"))
+	c.Redirect(c.Repo.RepoLink + "/settings/push_mirrors")
+}
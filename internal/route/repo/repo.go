@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/unknwon/com"
@@ -20,12 +21,14 @@ import (
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/form"
+	"gogs.io/gogs/internal/migrate"
 	"gogs.io/gogs/internal/tool"
 )
 
 const (
-	CREATE  = "repo/create"
-	MIGRATE = "repo/migrate"
+	CREATE         = "repo/create"
+	MIGRATE        = "repo/migrate"
+	MIGRATE_STATUS = "repo/migrate_status"
 )
 
 func MustBeNotBare(c *context.Context) {
@@ -149,6 +152,12 @@ func Migrate(c *context.Context) {
 	c.Data["private"] = c.User.LastRepoVisibility
 	c.Data["IsForcedPrivate"] = conf.Repository.ForcePrivate
 	c.Data["mirror"] = c.Query("mirror") == "1"
+	c.Data["MigrateServices"] = migrate.Services()
+	c.Data["labels"] = true
+	c.Data["milestones"] = true
+	c.Data["issues"] = true
+	c.Data["pull_requests"] = true
+	c.Data["releases"] = true
 
 	ctxUser := checkContextUser(c, c.QueryInt64("org"))
 	if c.Written() {
@@ -161,6 +170,7 @@ func Migrate(c *context.Context) {
 
 func MigratePost(c *context.Context, f form.MigrateRepo) {
 	c.Data["Title"] = c.Tr("new_migrate")
+	c.Data["MigrateServices"] = migrate.Services()
 
 	ctxUser := checkContextUser(c, f.Uid)
 	if c.Written() {
@@ -196,38 +206,69 @@ func MigratePost(c *context.Context, f form.MigrateRepo) {
 		return
 	}
 
-	repo, err := db.MigrateRepository(c.User, ctxUser, db.MigrateRepoOptions{
-		Name:        f.RepoName,
-		Description: f.Description,
-		IsPrivate:   f.Private || conf.Repository.ForcePrivate,
-		IsUnlisted:  f.Unlisted,
-		IsMirror:    f.Mirror,
-		RemoteAddr:  remoteAddr,
-	})
-	if err == nil {
-		log.Trace("Repository migrated [%d]: %s/%s", repo.ID, ctxUser.Name, f.RepoName)
-		c.Redirect(conf.Server.Subpath + "/" + ctxUser.Name + "/" + f.RepoName)
+	refspecs, err := db.ParseMirrorRefspecs(f.MirrorRefspecs)
+	if err != nil {
+		c.Data["Err_MirrorRefspecs"] = true
+		c.RenderWithErr(c.Tr("repo.mirror_refspecs_invalid", err.(db.ErrInvalidRefspec).Refspec), MIGRATE, &f)
 		return
 	}
 
-	if repo != nil {
-		if errDelete := db.DeleteRepository(ctxUser.ID, repo.ID); errDelete != nil {
-			log.Error("DeleteRepository: %v", errDelete)
+	m, err := db.CreateRepoMigration(c.Req.Context(), c.User, ctxUser, db.CreateRepoMigrationOptions{
+		RepoName:         f.RepoName,
+		Service:          migrate.Service(f.Service),
+		CloneAddr:        remoteAddr,
+		AuthToken:        f.AuthToken,
+		Description:      f.Description,
+		IsPrivate:        f.Private || conf.Repository.ForcePrivate,
+		IsUnlisted:       f.Unlisted,
+		IsMirror:         f.Mirror,
+		MirrorRefspecs:   refspecs,
+		MirrorLFS:        f.MirrorLFS,
+		WithLabels:       f.Labels,
+		WithMilestones:   f.Milestones,
+		WithIssues:       f.Issues,
+		WithPullRequests: f.PullRequests,
+		WithReleases:     f.Releases,
+	})
+	if err != nil {
+		if db.IsErrInvalidCloneAddr(err) {
+			c.Data["Err_CloneAddr"] = true
+			c.RenderWithErr(c.Tr("repo.migrate.clone_address_not_supported", migrate.Service(f.Service).DisplayName()), MIGRATE, &f)
+			return
 		}
+		handleCreateError(c, ctxUser, err, "MigratePost", MIGRATE, &f)
+		return
 	}
 
-	if strings.Contains(err.Error(), "Authentication failed") ||
-		strings.Contains(err.Error(), "could not read Username") {
-		c.Data["Err_Auth"] = true
-		c.RenderWithErr(c.Tr("form.auth_failed", db.HandleMirrorCredentials(err.Error(), true)), MIGRATE, &f)
+	log.Trace("Repository migration queued [%d]: %s/%s", m.ID, ctxUser.Name, f.RepoName)
+	c.Redirect(conf.Server.Subpath + "/repo/migrate/" + strconv.FormatInt(m.ID, 10))
+}
+
+// MigrateStatus shows the progress of the repository migration, or its result
+// once finished.
+func MigrateStatus(c *context.Context) {
+	m, err := db.RepoMigrations.GetByID(c.Req.Context(), c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get migration")
 		return
-	} else if strings.Contains(err.Error(), "fatal:") {
-		c.Data["Err_CloneAddr"] = true
-		c.RenderWithErr(c.Tr("repo.migrate.failed", db.HandleMirrorCredentials(err.Error(), true)), MIGRATE, &f)
+	} else if m.DoerID != c.User.ID && !c.User.IsAdmin {
+		c.NotFound()
+		return
+	}
+
+	owner, err := db.Users.GetByID(c.Req.Context(), m.OwnerID)
+	if err != nil {
+		c.NotFoundOrError(err, "get owner")
 		return
 	}
 
-	handleCreateError(c, ctxUser, err, "MigratePost", MIGRATE, &f)
+	c.Data["Title"] = c.Tr("repo.migrate.status")
+	c.Data["Migration"] = m
+	c.Data["MigrationOwner"] = owner
+	if !m.IsFinished() {
+		c.Data["PageRefresh"] = 3
+	}
+	c.Success(MIGRATE_STATUS)
 }
 
 func Action(c *context.Context) {
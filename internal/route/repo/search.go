@@ -0,0 +1,52 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"github.com/unknwon/paginater"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+const (
+	SEARCH = "repo/search"
+)
+
+// SearchCode searches code in the default branch of the repository.
+func SearchCode(c *context.Context) {
+	c.Data["Title"] = c.Tr("repo.code_search")
+	c.Data["PageIsViewFiles"] = true
+
+	page := c.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	keyword := c.Query("q")
+	c.Data["Keyword"] = keyword
+	if keyword == "" {
+		c.Success(SEARCH)
+		return
+	}
+
+	results, count, err := db.SearchCode(&db.SearchCodeOptions{
+		Keyword:  keyword,
+		RepoID:   c.Repo.Repository.ID,
+		UserID:   c.UserID(),
+		Page:     page,
+		PageSize: conf.UI.ExplorePagingNum,
+	})
+	if err != nil {
+		c.Error(err, "search code")
+		return
+	}
+	c.Data["Total"] = count
+	c.Data["Page"] = paginater.New(int(count), conf.UI.ExplorePagingNum, page, 5)
+	c.Data["Results"] = results
+
+	c.Success(SEARCH)
+}
@@ -39,13 +39,32 @@ func Settings(c *context.Context) {
 	c.Title("repo.settings")
 	c.PageIs("SettingsOptions")
 	c.RequireAutosize()
+	if !setLFSUsage(c) {
+		return
+	}
 	c.Success(SETTINGS_OPTIONS)
 }
 
+// setLFSUsage sets the total size of LFS objects of the repository along with
+// its quota to the template data.
+func setLFSUsage(c *context.Context) bool {
+	usage, err := db.LFS.TotalSizeByRepoID(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.Error(err, "get total size of LFS objects")
+		return false
+	}
+	c.Data["LFSUsage"] = usage
+	c.Data["LFSQuota"] = conf.LFS.RepoQuota * 1024 * 1024
+	return true
+}
+
 func SettingsPost(c *context.Context, f form.RepoSetting) {
 	c.Title("repo.settings")
 	c.PageIs("SettingsOptions")
 	c.RequireAutosize()
+	if !setLFSUsage(c) {
+		return
+	}
 
 	repo := c.Repo.Repository
 
@@ -114,8 +133,28 @@ func SettingsPost(c *context.Context, f form.RepoSetting) {
 			return
 		}
 
+		refspecs, err := db.ParseMirrorRefspecs(f.Refspecs)
+		if err != nil {
+			c.Data["Err_Refspecs"] = true
+			c.RenderWithErr(c.Tr("repo.mirror_refspecs_invalid", err.(db.ErrInvalidRefspec).Refspec), SETTINGS_OPTIONS, nil)
+			return
+		}
+		lfsEndpoint, err := f.ParseLFSEndpoint()
+		if err != nil {
+			c.Data["Err_LFSEndpoint"] = true
+			if db.IsErrInvalidCloneAddr(err) && err.(db.ErrInvalidCloneAddr).IsBlockedLocalAddress {
+				c.RenderWithErr(c.Tr("repo.migrate.clone_address_resolved_to_blocked_local_address"), SETTINGS_OPTIONS, nil)
+			} else {
+				c.RenderWithErr(c.Tr("repo.mirror_lfs_endpoint_invalid"), SETTINGS_OPTIONS, nil)
+			}
+			return
+		}
+
 		if f.Interval > 0 {
 			c.Repo.Mirror.EnablePrune = f.EnablePrune
+			c.Repo.Mirror.Refspecs = refspecs
+			c.Repo.Mirror.EnableLFS = f.EnableLFS
+			c.Repo.Mirror.LFSEndpoint = lfsEndpoint
 			c.Repo.Mirror.Interval = f.Interval
 			c.Repo.Mirror.NextSync = time.Now().Add(time.Duration(f.Interval) * time.Hour)
 			if err := db.UpdateMirror(c.Repo.Mirror); err != nil {
@@ -137,7 +176,7 @@ func SettingsPost(c *context.Context, f form.RepoSetting) {
 			return
 		}
 
-		go db.MirrorQueue.Add(repo.ID)
+		go db.QueueMirrorSync(repo.ID)
 		c.Flash.Info(c.Tr("repo.settings.mirror_sync_in_progress"))
 		c.Redirect(repo.Link() + "/settings")
 
@@ -155,6 +194,8 @@ func SettingsPost(c *context.Context, f form.RepoSetting) {
 		repo.EnablePulls = f.EnablePulls
 		repo.PullsIgnoreWhitespace = f.PullsIgnoreWhitespace
 		repo.PullsAllowRebase = f.PullsAllowRebase
+		repo.PullsAllowSquash = f.PullsAllowSquash
+		repo.PullsAllowFastForward = f.PullsAllowFastForward
 
 		if !repo.EnableWiki || repo.EnableExternalWiki {
 			repo.AllowPublicWiki = false
@@ -483,6 +524,7 @@ func UpdateDefaultBranch(c *context.Context) {
 		c.Error(err, "update repository")
 		return
 	}
+	db.UpdateCodeIndexer(c.Repo.Repository.ID)
 
 	c.Flash.Success(c.Tr("repo.settings.update_default_branch_success"))
 	c.Redirect(c.Repo.RepoLink + "/settings/branches")
@@ -557,6 +599,10 @@ func SettingsProtectedBranchPost(c *context.Context, f form.ProtectBranch) {
 	protectBranch.Protected = f.Protected
 	protectBranch.RequirePullRequest = f.RequirePullRequest
 	protectBranch.EnableWhitelist = f.EnableWhitelist
+	protectBranch.EnableStatusCheck = f.EnableStatusCheck
+	protectBranch.StatusCheckContexts = f.StatusCheckContexts
+	protectBranch.RequiredApprovals = f.RequiredApprovals
+	protectBranch.DismissStaleApprovals = f.DismissStaleApprovals
 	if c.Repo.Owner.IsOrganization() {
 		err = db.UpdateOrgProtectBranch(c.Repo.Repository, protectBranch, f.WhitelistUsers, f.WhitelistTeams)
 	} else {
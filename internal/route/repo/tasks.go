@@ -68,7 +68,12 @@ func TriggerTask(c *macaron.Context) {
 
 	log.Trace("TriggerTask: %s/%s@%s by %q", owner.Name, repo.Name, branch, pusher.Name)
 
-	go db.HookQueue.Add(repo.ID)
+	go db.QueueHookDelivery(repo.ID)
 	go db.AddTestPullRequestTask(pusher, repo.ID, branch, true)
+	db.UpdateCodeIndexerOnPush(repo, branch)
+
+	if err = db.QueuePushMirrorsOnPush(c.Req.Context(), repo.ID); err != nil {
+		log.Error("Failed to queue push mirrors of repository [id: %d]: %v", repo.ID, err)
+	}
 	c.Status(http.StatusAccepted)
 }
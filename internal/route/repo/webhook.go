@@ -74,10 +74,27 @@ func getOrgRepoContext(c *context.Context) (*orgRepoContext, error) {
 	return nil, errors.New("unable to determine context")
 }
 
+// webhookTypes returns the webhook types that are allowed to be created by the
+// current user. Custom templates are executed by the server, thus only site
+// admins are allowed to manage them.
+func webhookTypes(c *context.Context) []string {
+	if c.User.IsAdmin {
+		return conf.Webhook.Types
+	}
+
+	types := make([]string, 0, len(conf.Webhook.Types))
+	for _, typ := range conf.Webhook.Types {
+		if typ != "custom" {
+			types = append(types, typ)
+		}
+	}
+	return types
+}
+
 func Webhooks(c *context.Context, orCtx *orgRepoContext) {
 	c.Title("repo.settings.hooks")
 	c.PageIs("SettingsHooks")
-	c.Data["Types"] = conf.Webhook.Types
+	c.Data["Types"] = webhookTypes(c)
 
 	var err error
 	var ws []*db.Webhook
@@ -104,7 +121,7 @@ func WebhooksNew(c *context.Context, orCtx *orgRepoContext) {
 
 	allowed := false
 	hookType := strings.ToLower(c.Params(":type"))
-	for _, typ := range conf.Webhook.Types {
+	for _, typ := range webhookTypes(c) {
 		if hookType == typ {
 			allowed = true
 			c.Data["HookType"] = typ
@@ -130,6 +147,13 @@ func validateWebhook(l macaron.Locale, w *db.Webhook) (field, msg string, ok boo
 	if netutil.IsBlockedLocalHostname(payloadURL.Hostname(), conf.Security.LocalNetworkAllowlist) {
 		return "PayloadURL", l.Tr("repo.settings.webhook.url_resolved_to_blocked_local_address"), false
 	}
+
+	if w.HookTaskType == db.CUSTOM {
+		_, err = db.ParseCustomTemplate(w.CustomMeta().Template, "")
+		if err != nil {
+			return "Template", l.Tr("repo.settings.webhook.err_cannot_parse_template", err), false
+		}
+	}
 	return "", "", true
 }
 
@@ -174,6 +198,9 @@ func toHookEvent(f form.Webhook) *db.HookEvent {
 			IssueComment: f.IssueComment,
 			PullRequest:  f.PullRequest,
 			Release:      f.Release,
+			Status:       f.Status,
+
+			PullRequestReview: f.PullRequestReview,
 		},
 	}
 }
@@ -285,6 +312,94 @@ func WebhooksDingtalkNewPost(c *context.Context, orCtx *orgRepoContext, f form.N
 	validateAndCreateWebhook(c, orCtx, w)
 }
 
+func WebhooksMattermostNewPost(c *context.Context, orCtx *orgRepoContext, f form.NewMattermostHook) {
+	c.Title("repo.settings.add_webhook")
+	c.PageIs("SettingsHooks")
+	c.PageIs("SettingsHooksNew")
+	c.Data["HookType"] = "mattermost"
+
+	meta := &db.SlackMeta{
+		Channel:  f.Channel,
+		Username: f.Username,
+		IconURL:  f.IconURL,
+		Color:    f.Color,
+	}
+	c.Data["SlackMeta"] = meta
+
+	p, err := jsoniter.Marshal(meta)
+	if err != nil {
+		c.Error(err, "marshal JSON")
+		return
+	}
+
+	w := &db.Webhook{
+		RepoID:       orCtx.RepoID,
+		URL:          f.PayloadURL,
+		ContentType:  db.JSON,
+		HookEvent:    toHookEvent(f.Webhook),
+		IsActive:     f.Active,
+		HookTaskType: db.MATTERMOST,
+		Meta:         string(p),
+		OrgID:        orCtx.OrgID,
+	}
+	validateAndCreateWebhook(c, orCtx, w)
+}
+
+func WebhooksTeamsNewPost(c *context.Context, orCtx *orgRepoContext, f form.NewTeamsHook) {
+	c.Title("repo.settings.add_webhook")
+	c.PageIs("SettingsHooks")
+	c.PageIs("SettingsHooksNew")
+	c.Data["HookType"] = "teams"
+
+	w := &db.Webhook{
+		RepoID:       orCtx.RepoID,
+		URL:          f.PayloadURL,
+		ContentType:  db.JSON,
+		HookEvent:    toHookEvent(f.Webhook),
+		IsActive:     f.Active,
+		HookTaskType: db.TEAMS,
+		OrgID:        orCtx.OrgID,
+	}
+	validateAndCreateWebhook(c, orCtx, w)
+}
+
+func WebhooksCustomNewPost(c *context.Context, orCtx *orgRepoContext, f form.NewCustomHook) {
+	c.Title("repo.settings.add_webhook")
+	c.PageIs("SettingsHooks")
+	c.PageIs("SettingsHooksNew")
+	c.Data["HookType"] = "custom"
+
+	if !c.User.IsAdmin {
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	meta := &db.CustomMeta{
+		Template:    f.Template,
+		ContentType: f.CustomContentType,
+	}
+	c.Data["CustomMeta"] = meta
+
+	p, err := jsoniter.Marshal(meta)
+	if err != nil {
+		c.Error(err, "marshal JSON")
+		return
+	}
+
+	w := &db.Webhook{
+		RepoID:       orCtx.RepoID,
+		URL:          f.PayloadURL,
+		ContentType:  db.JSON,
+		Secret:       f.Secret,
+		HookEvent:    toHookEvent(f.Webhook),
+		IsActive:     f.Active,
+		HookTaskType: db.CUSTOM,
+		Meta:         string(p),
+		OrgID:        orCtx.OrgID,
+	}
+	validateAndCreateWebhook(c, orCtx, w)
+}
+
 func loadWebhook(c *context.Context, orCtx *orgRepoContext) *db.Webhook {
 	c.RequireHighlightJS()
 
@@ -310,6 +425,14 @@ func loadWebhook(c *context.Context, orCtx *orgRepoContext) *db.Webhook {
 		c.Data["HookType"] = "discord"
 	case db.DINGTALK:
 		c.Data["HookType"] = "dingtalk"
+	case db.MATTERMOST:
+		c.Data["SlackMeta"] = w.SlackMeta()
+		c.Data["HookType"] = "mattermost"
+	case db.TEAMS:
+		c.Data["HookType"] = "teams"
+	case db.CUSTOM:
+		c.Data["CustomMeta"] = w.CustomMeta()
+		c.Data["HookType"] = "custom"
 	default:
 		c.Data["HookType"] = "gogs"
 	}
@@ -458,6 +581,85 @@ func WebhooksDingtalkEditPost(c *context.Context, orCtx *orgRepoContext, f form.
 	validateAndUpdateWebhook(c, orCtx, w)
 }
 
+func WebhooksMattermostEditPost(c *context.Context, orCtx *orgRepoContext, f form.NewMattermostHook) {
+	c.Title("repo.settings.update_webhook")
+	c.PageIs("SettingsHooks")
+	c.PageIs("SettingsHooksEdit")
+
+	w := loadWebhook(c, orCtx)
+	if c.Written() {
+		return
+	}
+
+	meta, err := jsoniter.Marshal(&db.SlackMeta{
+		Channel:  f.Channel,
+		Username: f.Username,
+		IconURL:  f.IconURL,
+		Color:    f.Color,
+	})
+	if err != nil {
+		c.Error(err, "marshal JSON")
+		return
+	}
+
+	w.URL = f.PayloadURL
+	w.Meta = string(meta)
+	w.HookEvent = toHookEvent(f.Webhook)
+	w.IsActive = f.Active
+	validateAndUpdateWebhook(c, orCtx, w)
+}
+
+func WebhooksTeamsEditPost(c *context.Context, orCtx *orgRepoContext, f form.NewTeamsHook) {
+	c.Title("repo.settings.update_webhook")
+	c.PageIs("SettingsHooks")
+	c.PageIs("SettingsHooksEdit")
+
+	w := loadWebhook(c, orCtx)
+	if c.Written() {
+		return
+	}
+
+	w.URL = f.PayloadURL
+	w.HookEvent = toHookEvent(f.Webhook)
+	w.IsActive = f.Active
+	validateAndUpdateWebhook(c, orCtx, w)
+}
+
+func WebhooksCustomEditPost(c *context.Context, orCtx *orgRepoContext, f form.NewCustomHook) {
+	c.Title("repo.settings.update_webhook")
+	c.PageIs("SettingsHooks")
+	c.PageIs("SettingsHooksEdit")
+
+	if !c.User.IsAdmin {
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	w := loadWebhook(c, orCtx)
+	if c.Written() {
+		return
+	}
+
+	meta := &db.CustomMeta{
+		Template:    f.Template,
+		ContentType: f.CustomContentType,
+	}
+	c.Data["CustomMeta"] = meta
+
+	p, err := jsoniter.Marshal(meta)
+	if err != nil {
+		c.Error(err, "marshal JSON")
+		return
+	}
+
+	w.URL = f.PayloadURL
+	w.Secret = f.Secret
+	w.Meta = string(p)
+	w.HookEvent = toHookEvent(f.Webhook)
+	w.IsActive = f.Active
+	validateAndUpdateWebhook(c, orCtx, w)
+}
+
 func TestWebhook(c *context.Context) {
 	var (
 		commitID          string
@@ -563,12 +765,15 @@ func RedeliveryWebhook(c *context.Context) {
 	}
 
 	hookTask.IsDelivered = false
+	hookTask.IsFailed = false
+	hookTask.Attempts = 0
+	hookTask.NextAttemptUnix = 0
 	if err = db.UpdateHookTask(hookTask); err != nil {
 		c.Error(err, "update hook task")
 		return
 	}
 
-	go db.HookQueue.Add(c.Repo.Repository.ID)
+	go db.QueueHookDelivery(c.Repo.Repository.ID)
 	c.Flash.Info(c.Tr("repo.settings.webhook.redelivery_success", hookTask.UUID))
 	c.Status(http.StatusOK)
 }
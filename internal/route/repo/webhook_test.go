@@ -9,6 +9,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/mocks"
 )
@@ -42,6 +44,17 @@ func Test_validateWebhook(t *testing.T) {
 			expMsg:   "repo.settings.webhook.url_resolved_to_blocked_local_address",
 			expOK:    false,
 		},
+		{
+			name: "bad custom template",
+			webhook: &db.Webhook{
+				URL:          "http://8.8.8.8",
+				HookTaskType: db.CUSTOM,
+				Meta:         `{"template":"{{.Ref"}`,
+			},
+			expField: "Template",
+			expMsg:   "repo.settings.webhook.err_cannot_parse_template",
+			expOK:    false,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -52,3 +65,17 @@ func Test_validateWebhook(t *testing.T) {
 		})
 	}
 }
+
+func Test_webhookTypes(t *testing.T) {
+	before := conf.Webhook.Types
+	conf.Webhook.Types = []string{"gogs", "slack", "custom"}
+	t.Cleanup(func() {
+		conf.Webhook.Types = before
+	})
+
+	got := webhookTypes(&context.Context{User: &db.User{IsAdmin: true}})
+	assert.Equal(t, []string{"gogs", "slack", "custom"}, got)
+
+	got = webhookTypes(&context.Context{User: &db.User{}})
+	assert.Equal(t, []string{"gogs", "slack"}, got)
+}
@@ -0,0 +1,541 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/db"
+)
+
+type groupMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// group is the Group resource, see
+// https://datatracker.ietf.org/doc/html/rfc7643#section-4.2. Each group maps to
+// a team of an organization, and the display name is in the form of
+// "<org>/<team>".
+type group struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	DisplayName string        `json:"displayName"`
+	Members     []groupMember `json:"members"`
+	Meta        *meta         `json:"meta,omitempty"`
+}
+
+// orgTeam is a team along with its organization.
+type orgTeam struct {
+	*db.Team
+	Org *db.User
+}
+
+func toGroup(t *orgTeam) (*group, error) {
+	if err := t.GetMembers(); err != nil {
+		return nil, errors.Wrap(err, "get members")
+	}
+
+	members := make([]groupMember, len(t.Members))
+	for i, m := range t.Members {
+		members[i] = groupMember{
+			Value:   strconv.FormatInt(m.ID, 10),
+			Display: m.Name,
+			Ref:     location("Users", m.ID),
+		}
+	}
+	return &group{
+		Schemas:     []string{schemaGroup},
+		ID:          strconv.FormatInt(t.ID, 10),
+		DisplayName: t.Org.Name + "/" + t.Name,
+		Members:     members,
+		Meta: &meta{
+			ResourceType: "Group",
+			Location:     location("Groups", t.ID),
+		},
+	}, nil
+}
+
+// parseDisplayName parses the display name of a group into the organization
+// name and the team name.
+func parseDisplayName(displayName string) (orgName, teamName string, err error) {
+	i := strings.Index(displayName, "/")
+	if i <= 0 || i == len(displayName)-1 || strings.Count(displayName, "/") != 1 {
+		return "", "", errors.Errorf(`the displayName %q must be in the form of "<org>/<team>"`, displayName)
+	}
+	return displayName[:i], displayName[i+1:], nil
+}
+
+// parseMemberIDs parses the values of members into user IDs.
+func parseMemberIDs(members []groupMember) ([]int64, error) {
+	ids := make([]int64, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m.Value, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, errors.Errorf("invalid member value %q", m.Value)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func getOrgTeam(t *db.Team) (*orgTeam, error) {
+	org, err := db.GetUserByID(t.OrgID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get organization")
+	}
+	return &orgTeam{Team: t, Org: org}, nil
+}
+
+// assignGroup assigns the team of the ID in the URL.
+func assignGroup(c *macaron.Context) {
+	t, err := db.GetTeamByID(c.ParamsInt64(":id"))
+	if err != nil {
+		if db.IsErrTeamNotExist(err) {
+			responseError(c.Resp, http.StatusNotFound, "", "Group not found")
+		} else {
+			internalServerError(c.Resp)
+			log.Error("Failed to get team [id: %s]: %v", c.Params(":id"), err)
+		}
+		return
+	}
+
+	ot, err := getOrgTeam(t)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to get organization of team [id: %d]: %v", t.ID, err)
+		return
+	}
+	c.Map(ot)
+}
+
+func serveListGroups(c *macaron.Context) {
+	startIndex, count := pagination(c)
+
+	var teams []*db.Team
+	var total int64
+	if filter := c.Query("filter"); filter != "" {
+		attr, value, err := parseFilter(filter)
+		if err != nil {
+			responseError(c.Resp, http.StatusBadRequest, errInvalidFilter, err.Error())
+			return
+		} else if attr != "displayname" {
+			responseError(c.Resp, http.StatusBadRequest, errInvalidFilter, "Unsupported attribute in filter: "+attr)
+			return
+		}
+
+		// A display name not in the form of "<org>/<team>" never matches.
+		orgName, teamName, err := parseDisplayName(value)
+		if err == nil {
+			var org *db.User
+			org, err = db.GetOrgByName(orgName)
+			if err == nil {
+				var t *db.Team
+				t, err = org.GetTeam(teamName)
+				if err == nil {
+					total = 1
+					if startIndex == 1 && count > 0 {
+						teams = append(teams, t)
+					}
+				}
+			}
+			if err != nil && err != db.ErrOrgNotExist && !db.IsErrTeamNotExist(err) {
+				internalServerError(c.Resp)
+				log.Error("Failed to get team by filter %q: %v", filter, err)
+				return
+			}
+		}
+	} else {
+		total = db.CountTeams()
+		if count > 0 {
+			var err error
+			teams, err = db.ListTeams(startIndex-1, count)
+			if err != nil {
+				internalServerError(c.Resp)
+				log.Error("Failed to list teams: %v", err)
+				return
+			}
+		}
+	}
+
+	resources := make([]*group, 0, len(teams))
+	for _, t := range teams {
+		ot, err := getOrgTeam(t)
+		if err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to get organization of team [id: %d]: %v", t.ID, err)
+			return
+		}
+		g, err := toGroup(ot)
+		if err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to convert team [id: %d]: %v", t.ID, err)
+			return
+		}
+		resources = append(resources, g)
+	}
+	responseJSON(c.Resp, http.StatusOK, listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// respondGroup responds the group of given team with the status.
+func respondGroup(c *macaron.Context, status int, t *orgTeam) {
+	// Reload to have the members up to date.
+	reloaded, err := db.GetTeamByID(t.ID)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to get team [id: %d]: %v", t.ID, err)
+		return
+	}
+
+	g, err := toGroup(&orgTeam{Team: reloaded, Org: t.Org})
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to convert team [id: %d]: %v", t.ID, err)
+		return
+	}
+	responseJSON(c.Resp, status, g)
+}
+
+// updateMembers adds and removes members of the team. It responds the error to
+// the client and returns false when failed.
+func updateMembers(c *macaron.Context, t *orgTeam, add, remove []int64) bool {
+	for _, id := range add {
+		u, err := db.GetUserByID(id)
+		if err != nil {
+			if db.IsErrUserNotExist(err) {
+				responseError(c.Resp, http.StatusBadRequest, errInvalidValue, "User not found: "+strconv.FormatInt(id, 10))
+			} else {
+				internalServerError(c.Resp)
+				log.Error("Failed to get user [id: %d]: %v", id, err)
+			}
+			return false
+		} else if u.IsOrganization() {
+			responseError(c.Resp, http.StatusBadRequest, errInvalidValue, "User not found: "+strconv.FormatInt(id, 10))
+			return false
+		}
+
+		if err = db.AddTeamMember(t.OrgID, t.ID, id); err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to add user [id: %d] to team [id: %d]: %v", id, t.ID, err)
+			return false
+		}
+	}
+
+	for _, id := range remove {
+		if !t.IsMember(id) {
+			continue
+		}
+
+		if err := db.RemoveTeamMember(t.OrgID, t.ID, id); err != nil {
+			if db.IsErrLastOrgOwner(err) {
+				responseError(c.Resp, http.StatusBadRequest, errMutability, "Cannot remove the last owner of the organization")
+			} else {
+				internalServerError(c.Resp)
+				log.Error("Failed to remove user [id: %d] from team [id: %d]: %v", id, t.ID, err)
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// setMembers makes the team to have exactly the given members.
+func setMembers(c *macaron.Context, t *orgTeam, ids []int64) bool {
+	if err := t.GetMembers(); err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to get members of team [id: %d]: %v", t.ID, err)
+		return false
+	}
+
+	want := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	var remove []int64
+	for _, m := range t.Members {
+		if !want[m.ID] {
+			remove = append(remove, m.ID)
+		}
+	}
+	// Add members first so the owner team is never left empty in the middle.
+	return updateMembers(c, t, ids, remove)
+}
+
+func serveCreateGroup(c *macaron.Context, actor *authenticatedUser) {
+	var g group
+	if !decodeJSON(c, &g) {
+		return
+	}
+
+	orgName, teamName, err := parseDisplayName(g.DisplayName)
+	if err != nil {
+		responseError(c.Resp, http.StatusBadRequest, errInvalidValue, err.Error())
+		return
+	}
+	memberIDs, err := parseMemberIDs(g.Members)
+	if err != nil {
+		responseError(c.Resp, http.StatusBadRequest, errInvalidValue, err.Error())
+		return
+	}
+
+	org, err := db.GetOrgByName(orgName)
+	if err != nil {
+		if err == db.ErrOrgNotExist {
+			responseError(c.Resp, http.StatusBadRequest, errInvalidValue, "Organization not found: "+orgName)
+		} else {
+			internalServerError(c.Resp)
+			log.Error("Failed to get organization %q: %v", orgName, err)
+		}
+		return
+	}
+
+	t := &db.Team{
+		OrgID:     org.ID,
+		Name:      teamName,
+		Authorize: db.AccessModeRead,
+	}
+	if err = db.NewTeam(t); err != nil {
+		switch {
+		case db.IsErrTeamAlreadyExist(err):
+			responseError(c.Resp, http.StatusConflict, errUniqueness, "The team already exists")
+		case db.IsErrNameNotAllowed(err):
+			responseError(c.Resp, http.StatusBadRequest, errInvalidValue, err.Error())
+		default:
+			internalServerError(c.Resp)
+			log.Error("Failed to create team: %v", err)
+		}
+		return
+	}
+	log.Trace("Team created via SCIM by admin %q: %s/%s", actor.Name, org.Name, t.Name)
+
+	ot := &orgTeam{Team: t, Org: org}
+	if !updateMembers(c, ot, memberIDs, nil) {
+		return
+	}
+	respondGroup(c, http.StatusCreated, ot)
+}
+
+func serveGetGroup(c *macaron.Context, t *orgTeam) {
+	respondGroup(c, http.StatusOK, t)
+}
+
+// renameTeam renames the team within its organization.
+func renameTeam(c *macaron.Context, t *orgTeam, displayName string) bool {
+	orgName, teamName, err := parseDisplayName(displayName)
+	if err != nil {
+		responseError(c.Resp, http.StatusBadRequest, errInvalidValue, err.Error())
+		return false
+	} else if !strings.EqualFold(orgName, t.Org.Name) {
+		responseError(c.Resp, http.StatusBadRequest, errMutability, "The organization of a group cannot be changed")
+		return false
+	} else if teamName == t.Name {
+		return true
+	} else if t.IsOwnerTeam() {
+		responseError(c.Resp, http.StatusBadRequest, errMutability, "The owner team cannot be renamed")
+		return false
+	}
+
+	if err = db.IsUsableTeamName(teamName); err != nil {
+		responseError(c.Resp, http.StatusBadRequest, errInvalidValue, err.Error())
+		return false
+	}
+
+	t.Name = teamName
+	if err = db.UpdateTeam(t.Team, false); err != nil {
+		if db.IsErrTeamAlreadyExist(err) {
+			responseError(c.Resp, http.StatusConflict, errUniqueness, "The team already exists")
+		} else {
+			internalServerError(c.Resp)
+			log.Error("Failed to update team [id: %d]: %v", t.ID, err)
+		}
+		return false
+	}
+	return true
+}
+
+func serveReplaceGroup(c *macaron.Context, actor *authenticatedUser, t *orgTeam) {
+	var g group
+	if !decodeJSON(c, &g) {
+		return
+	}
+
+	memberIDs, err := parseMemberIDs(g.Members)
+	if err != nil {
+		responseError(c.Resp, http.StatusBadRequest, errInvalidValue, err.Error())
+		return
+	}
+
+	if !renameTeam(c, t, g.DisplayName) || !setMembers(c, t, memberIDs) {
+		return
+	}
+	log.Trace("Team updated via SCIM by admin %q: %s/%s", actor.Name, t.Org.Name, t.Name)
+
+	respondGroup(c, http.StatusOK, t)
+}
+
+// memberFilterPattern matches the path of removing a single member, e.g.
+// `members[value eq "2"]`.
+var memberFilterPattern = regexp.MustCompile(`^(?i:members)\[(.+)\]$`)
+
+// parseMemberPath returns the user ID when the path selects a single member,
+// or 0 when the path selects all members.
+func parseMemberPath(path string) (int64, error) {
+	if strings.EqualFold(path, "members") {
+		return 0, nil
+	}
+
+	m := memberFilterPattern.FindStringSubmatch(path)
+	if m == nil {
+		return 0, errors.Errorf("unsupported path %q", path)
+	}
+	attr, value, err := parseFilter(m[1])
+	if err != nil {
+		return 0, err
+	} else if attr != "value" {
+		return 0, errors.Errorf("unsupported path %q", path)
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.Errorf("invalid member value %q", value)
+	}
+	return id, nil
+}
+
+func servePatchGroup(c *macaron.Context, actor *authenticatedUser, t *orgTeam) {
+	var req patchRequest
+	if !decodeJSON(c, &req) {
+		return
+	}
+
+	for _, op := range req.Operations {
+		path := op.Path
+		value := op.Value
+		// Some clients send the attribute in the value without a path, e.g.
+		// `{"op":"add","value":{"members":[...]}}`.
+		if path == "" {
+			var values map[string]json.RawMessage
+			if err := json.Unmarshal(value, &values); err != nil || len(values) != 1 {
+				responseError(c.Resp, http.StatusBadRequest, errInvalidValue, "The value must be an object of a single attribute when the path is omitted")
+				return
+			}
+			for k, v := range values {
+				path, value = k, v
+			}
+		}
+
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+			if strings.EqualFold(path, "displayName") {
+				var displayName string
+				if err := json.Unmarshal(value, &displayName); err != nil {
+					responseError(c.Resp, http.StatusBadRequest, errInvalidValue, `Invalid value for "displayName"`)
+					return
+				}
+				if !renameTeam(c, t, displayName) {
+					return
+				}
+				continue
+			} else if !strings.EqualFold(path, "members") {
+				responseError(c.Resp, http.StatusBadRequest, errInvalidPath, "Unsupported path: "+path)
+				return
+			}
+
+			var members []groupMember
+			if err := json.Unmarshal(value, &members); err != nil {
+				responseError(c.Resp, http.StatusBadRequest, errInvalidValue, `Invalid value for "members"`)
+				return
+			}
+			ids, err := parseMemberIDs(members)
+			if err != nil {
+				responseError(c.Resp, http.StatusBadRequest, errInvalidValue, err.Error())
+				return
+			}
+
+			var ok bool
+			if strings.EqualFold(op.Op, "add") {
+				ok = updateMembers(c, t, ids, nil)
+			} else {
+				ok = setMembers(c, t, ids)
+			}
+			if !ok {
+				return
+			}
+
+		case "remove":
+			id, err := parseMemberPath(path)
+			if err != nil {
+				responseError(c.Resp, http.StatusBadRequest, errInvalidPath, err.Error())
+				return
+			}
+
+			var ids []int64
+			if id > 0 {
+				ids = []int64{id}
+			} else if len(value) > 0 && string(value) != "null" {
+				// Some clients put members to remove in the value.
+				var members []groupMember
+				if err = json.Unmarshal(value, &members); err != nil {
+					responseError(c.Resp, http.StatusBadRequest, errInvalidValue, `Invalid value for "members"`)
+					return
+				}
+				if ids, err = parseMemberIDs(members); err != nil {
+					responseError(c.Resp, http.StatusBadRequest, errInvalidValue, err.Error())
+					return
+				}
+			} else {
+				if err = t.GetMembers(); err != nil {
+					internalServerError(c.Resp)
+					log.Error("Failed to get members of team [id: %d]: %v", t.ID, err)
+					return
+				}
+				for _, m := range t.Members {
+					ids = append(ids, m.ID)
+				}
+			}
+			if !updateMembers(c, t, nil, ids) {
+				return
+			}
+
+		default:
+			responseError(c.Resp, http.StatusBadRequest, errInvalidSyntax, "Unsupported operation: "+op.Op)
+			return
+		}
+	}
+	log.Trace("Team updated via SCIM by admin %q: %s/%s", actor.Name, t.Org.Name, t.Name)
+
+	respondGroup(c, http.StatusOK, t)
+}
+
+func serveDeleteGroup(c *macaron.Context, actor *authenticatedUser, t *orgTeam) {
+	if t.IsOwnerTeam() {
+		responseError(c.Resp, http.StatusBadRequest, errMutability, "The owner team cannot be deleted")
+		return
+	}
+
+	if err := db.DeleteTeam(t.Team); err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to delete team [id: %d]: %v", t.ID, err)
+		return
+	}
+	log.Trace("Team deleted via SCIM by admin %q: %s/%s", actor.Name, t.Org.Name, t.Name)
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDisplayName(t *testing.T) {
+	orgName, teamName, err := parseDisplayName("acme/developers")
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", orgName)
+	assert.Equal(t, "developers", teamName)
+
+	for _, displayName := range []string{"", "developers", "/developers", "acme/", "acme/dev/ops"} {
+		_, _, err = parseDisplayName(displayName)
+		assert.Error(t, err, displayName)
+	}
+}
+
+func TestParseMemberPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    int64
+		wantErr bool
+	}{
+		{path: "members", want: 0},
+		{path: "Members", want: 0},
+		{path: `members[value eq "2"]`, want: 2},
+		{path: `members[display eq "alice"]`, wantErr: true},
+		{path: `members[value eq "alice"]`, wantErr: true},
+		{path: "displayName", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			got, err := parseMemberPath(test.path)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package scim implements a SCIM 2.0 server for provisioning users and
+// organization teams, see https://datatracker.ietf.org/doc/html/rfc7644.
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/auth"
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/db"
+)
+
+const (
+	contentType = "application/scim+json"
+
+	schemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	schemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	schemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	schemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+// Error types of SCIM, see https://datatracker.ietf.org/doc/html/rfc7644#section-3.12.
+const (
+	errInvalidFilter = "invalidFilter"
+	errUniqueness    = "uniqueness"
+	errMutability    = "mutability"
+	errInvalidSyntax = "invalidSyntax"
+	errInvalidPath   = "invalidPath"
+	errInvalidValue  = "invalidValue"
+)
+
+// RegisterRoutes registers SCIM routes using given router, and inherits all
+// groups and middleware.
+func RegisterRoutes(r *macaron.Router) {
+	r.Group("", func() {
+		r.Get("/ServiceProviderConfig", serveServiceProviderConfig)
+		r.Get("/ResourceTypes", serveResourceTypes)
+
+		r.Group("/Users", func() {
+			r.Combo("").
+				Get(serveListUsers).
+				Post(serveCreateUser)
+			r.Combo("/:id", assignUser).
+				Get(serveGetUser).
+				Put(serveReplaceUser).
+				Patch(servePatchUser).
+				Delete(serveDeleteUser)
+		})
+
+		r.Group("/Groups", func() {
+			r.Combo("").
+				Get(serveListGroups).
+				Post(serveCreateGroup)
+			r.Combo("/:id", assignGroup).
+				Get(serveGetGroup).
+				Put(serveReplaceGroup).
+				Patch(servePatchGroup).
+				Delete(serveDeleteGroup)
+		})
+	}, authenticate())
+}
+
+// authenticate authenticates the request with a personal access token of a
+// site admin, the token must be granted the "admin:site" scope.
+func authenticate() macaron.Handler {
+	return func(c *macaron.Context) {
+		fields := strings.Fields(c.Req.Header.Get("Authorization"))
+		if len(fields) != 2 || (!strings.EqualFold(fields[0], "bearer") && fields[0] != "token") {
+			c.Header().Set("WWW-Authenticate", `Bearer realm="SCIM"`)
+			responseError(c.Resp, http.StatusUnauthorized, "", "Bearer token is required")
+			return
+		}
+
+		token, err := db.AccessTokens.GetBySHA1(c.Req.Context(), fields[1])
+		if err != nil {
+			if db.IsErrAccessTokenNotExist(err) {
+				c.Header().Set("WWW-Authenticate", `Bearer realm="SCIM"`)
+				responseError(c.Resp, http.StatusUnauthorized, "", "The access token is invalid or has expired")
+			} else {
+				internalServerError(c.Resp)
+				log.Error("Failed to get access token: %v", err)
+			}
+			return
+		}
+
+		if !token.ScopeList().Has(auth.ScopeAdminSite) {
+			responseError(c.Resp, http.StatusForbidden, "", "The access token is not granted the scope: "+string(auth.ScopeAdminSite))
+			return
+		}
+
+		actor, err := db.Users.GetByID(c.Req.Context(), token.UserID)
+		if err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to get user [id: %d]: %v", token.UserID, err)
+			return
+		} else if !actor.IsAdmin {
+			responseError(c.Resp, http.StatusForbidden, "", "Only site admins are allowed to provision")
+			return
+		}
+
+		if err = db.AccessTokens.Touch(c.Req.Context(), token.ID); err != nil {
+			log.Error("Failed to touch access token: %v", err)
+		}
+		c.Map(&authenticatedUser{User: actor})
+	}
+}
+
+// authenticatedUser is the site admin who sends the request. It is mapped
+// separately from the *db.User which is the user being provisioned.
+type authenticatedUser struct {
+	*db.User
+}
+
+// meta is the metadata of a resource.
+type meta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location"`
+}
+
+func location(endpoint string, id int64) string {
+	return conf.Server.ExternalURL + "scim/v2/" + endpoint + "/" + strconv.FormatInt(id, 10)
+}
+
+// listResponse is the response of querying resources, see
+// https://datatracker.ietf.org/doc/html/rfc7644#section-3.4.2.
+type listResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// patchRequest is the request body of modifying a resource with PATCH, see
+// https://datatracker.ietf.org/doc/html/rfc7644#section-3.5.2.
+type patchRequest struct {
+	Schemas    []string `json:"schemas"`
+	Operations []struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	} `json:"Operations"`
+}
+
+// decodeJSON decodes the request body into v, it responds the error to the
+// client and returns false when failed.
+func decodeJSON(c *macaron.Context, v interface{}) bool {
+	err := json.NewDecoder(c.Req.Request.Body).Decode(v)
+	if err != nil {
+		responseError(c.Resp, http.StatusBadRequest, errInvalidSyntax, "Invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// pagination returns the 1-based start index and the number of resources per
+// page requested by the client.
+func pagination(c *macaron.Context) (startIndex, count int) {
+	startIndex = c.QueryInt("startIndex")
+	if startIndex < 1 {
+		startIndex = 1
+	}
+
+	count = conf.API.MaxResponseItems
+	if c.Query("count") != "" {
+		count = c.QueryInt("count")
+		if count < 0 {
+			count = 0
+		} else if count > conf.API.MaxResponseItems {
+			count = conf.API.MaxResponseItems
+		}
+	}
+	return startIndex, count
+}
+
+var filterPattern = regexp.MustCompile(`^\s*([A-Za-z][\w.]*)\s+(?i:eq)\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// parseFilter parses the filter which only supports the "eq" operator, e.g.
+// `userName eq "alice"`. It returns the attribute in lower case and the value.
+func parseFilter(filter string) (attr, value string, err error) {
+	m := filterPattern.FindStringSubmatch(filter)
+	if m == nil {
+		return "", "", errors.Errorf(`unsupported filter %q, only the "eq" operator is supported`, filter)
+	}
+
+	value, err = strconv.Unquote(m[2])
+	if err != nil {
+		return "", "", errors.Errorf("invalid value in filter %q", filter)
+	}
+	return strings.ToLower(m[1]), value, nil
+}
+
+// parseBool parses a boolean value that some clients send as a string, e.g.
+// "False".
+func parseBool(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return false, errors.New("not a boolean")
+	}
+	return strconv.ParseBool(strings.ToLower(s))
+}
+
+func responseJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		log.Error("Failed to encode JSON: %v", err)
+		return
+	}
+}
+
+// responseError responds an error to the client, see
+// https://datatracker.ietf.org/doc/html/rfc7644#section-3.12.
+func responseError(w http.ResponseWriter, status int, scimType, detail string) {
+	resp := map[string]interface{}{
+		"schemas": []string{schemaError},
+		"status":  strconv.Itoa(status),
+		"detail":  detail,
+	}
+	if scimType != "" {
+		resp["scimType"] = scimType
+	}
+	responseJSON(w, status, resp)
+}
+
+func internalServerError(w http.ResponseWriter) {
+	responseError(w, http.StatusInternalServerError, "", "Internal server error")
+}
+
+func serveServiceProviderConfig(c *macaron.Context) {
+	supported := func(v bool) map[string]bool {
+		return map[string]bool{"supported": v}
+	}
+	responseJSON(c.Resp, http.StatusOK, map[string]interface{}{
+		"schemas": []string{schemaServiceProviderConfig},
+		"patch":   supported(true),
+		"bulk": map[string]interface{}{
+			"supported":      false,
+			"maxOperations":  0,
+			"maxPayloadSize": 0,
+		},
+		"filter": map[string]interface{}{
+			"supported":  true,
+			"maxResults": conf.API.MaxResponseItems,
+		},
+		"changePassword": supported(true),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]interface{}{
+			{
+				"type":        "oauthbearertoken",
+				"name":        "Personal access token",
+				"description": `Personal access token of a site admin with the "admin:site" scope`,
+				"primary":     true,
+			},
+		},
+		"meta": map[string]string{
+			"resourceType": "ServiceProviderConfig",
+			"location":     conf.Server.ExternalURL + "scim/v2/ServiceProviderConfig",
+		},
+	})
+}
+
+func serveResourceTypes(c *macaron.Context) {
+	resourceType := func(name, endpoint, schema string) map[string]interface{} {
+		return map[string]interface{}{
+			"schemas":  []string{schemaResourceType},
+			"id":       name,
+			"name":     name,
+			"endpoint": endpoint,
+			"schema":   schema,
+			"meta": map[string]string{
+				"resourceType": "ResourceType",
+				"location":     conf.Server.ExternalURL + "scim/v2/ResourceTypes/" + name,
+			},
+		}
+	}
+	resources := []map[string]interface{}{
+		resourceType("User", "/Users", schemaUser),
+		resourceType("Group", "/Groups", schemaGroup),
+	}
+	responseJSON(c.Resp, http.StatusOK, listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: int64(len(resources)),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
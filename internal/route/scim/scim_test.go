@@ -0,0 +1,66 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter    string
+		wantAttr  string
+		wantValue string
+		wantErr   bool
+	}{
+		{filter: `userName eq "alice"`, wantAttr: "username", wantValue: "alice"},
+		{filter: `  emails.value EQ "alice@example.com" `, wantAttr: "emails.value", wantValue: "alice@example.com"},
+		{filter: `displayName eq "org/team \"a\""`, wantAttr: "displayname", wantValue: `org/team "a"`},
+		{filter: `userName co "alice"`, wantErr: true},
+		{filter: `userName eq alice`, wantErr: true},
+		{filter: `userName eq "alice" and active eq true`, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.filter, func(t *testing.T) {
+			attr, value, err := parseFilter(test.filter)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantAttr, attr)
+			assert.Equal(t, test.wantValue, value)
+		})
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    bool
+		wantErr bool
+	}{
+		{raw: `true`, want: true},
+		{raw: `false`, want: false},
+		{raw: `"True"`, want: true},
+		{raw: `"False"`, want: false},
+		{raw: `"yes"`, wantErr: true},
+		{raw: `1`, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.raw, func(t *testing.T) {
+			got, err := parseBool(json.RawMessage(test.raw))
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
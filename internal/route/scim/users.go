@@ -0,0 +1,444 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/strutil"
+)
+
+type userName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+}
+
+type userEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// user is the User resource, see
+// https://datatracker.ietf.org/doc/html/rfc7643#section-4.1.
+type user struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	UserName    string      `json:"userName"`
+	Name        *userName   `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Emails      []userEmail `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Password    string      `json:"password,omitempty"`
+	Meta        *meta       `json:"meta,omitempty"`
+}
+
+// fullName returns the full name from the display name or the name.
+func (u *user) fullName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	} else if u.Name == nil {
+		return ""
+	} else if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+	return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+}
+
+// email returns the primary email, or the first one when none is primary.
+func (u *user) email() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+// setEmail sets the primary email.
+func (u *user) setEmail(email string) {
+	for i := range u.Emails {
+		if u.Emails[i].Primary {
+			u.Emails[i].Value = email
+			return
+		}
+	}
+	if len(u.Emails) > 0 {
+		u.Emails[0].Value = email
+		return
+	}
+	u.Emails = []userEmail{{Value: email, Primary: true}}
+}
+
+func toUser(u *db.User) *user {
+	active := !u.ProhibitLogin
+	su := &user{
+		Schemas:     []string{schemaUser},
+		ID:          strconv.FormatInt(u.ID, 10),
+		UserName:    u.Name,
+		DisplayName: u.FullName,
+		Emails: []userEmail{
+			{Value: u.Email, Primary: true},
+		},
+		Active: &active,
+		Meta: &meta{
+			ResourceType: "User",
+			Created:      u.Created.UTC().Format(time.RFC3339),
+			LastModified: u.Updated.UTC().Format(time.RFC3339),
+			Location:     location("Users", u.ID),
+		},
+	}
+	if u.FullName != "" {
+		su.Name = &userName{Formatted: u.FullName}
+	}
+	if u.LoginSource > 0 && u.LoginName != u.Name {
+		su.ExternalID = u.LoginName
+	}
+	return su
+}
+
+// assignUser assigns the individual user of the ID in the URL.
+func assignUser(c *macaron.Context) {
+	u, err := db.GetUserByID(c.ParamsInt64(":id"))
+	if err != nil {
+		if db.IsErrUserNotExist(err) {
+			responseError(c.Resp, http.StatusNotFound, "", "User not found")
+		} else {
+			internalServerError(c.Resp)
+			log.Error("Failed to get user [id: %s]: %v", c.Params(":id"), err)
+		}
+		return
+	} else if u.IsOrganization() {
+		responseError(c.Resp, http.StatusNotFound, "", "User not found")
+		return
+	}
+	c.Map(u)
+}
+
+func serveListUsers(c *macaron.Context) {
+	startIndex, count := pagination(c)
+
+	var users []*db.User
+	var total int64
+	if filter := c.Query("filter"); filter != "" {
+		attr, value, err := parseFilter(filter)
+		if err != nil {
+			responseError(c.Resp, http.StatusBadRequest, errInvalidFilter, err.Error())
+			return
+		}
+
+		var u *db.User
+		switch attr {
+		case "username":
+			u, err = db.GetUserByName(value)
+		case "emails", "emails.value":
+			u, err = db.Users.GetByEmail(c.Req.Context(), value)
+		case "externalid":
+			if conf.SCIM.LoginSource > 0 {
+				u, err = db.GetUserByLoginName(conf.SCIM.LoginSource, value)
+			}
+		default:
+			responseError(c.Resp, http.StatusBadRequest, errInvalidFilter, "Unsupported attribute in filter: "+attr)
+			return
+		}
+		if err != nil && !db.IsErrUserNotExist(err) {
+			internalServerError(c.Resp)
+			log.Error("Failed to get user by filter %q: %v", filter, err)
+			return
+		}
+
+		if u != nil && !u.IsOrganization() {
+			total = 1
+			if startIndex == 1 && count > 0 {
+				users = append(users, u)
+			}
+		}
+	} else {
+		total = db.CountUsers()
+		if count > 0 {
+			var err error
+			users, err = db.ListUsersByOffset(startIndex-1, count)
+			if err != nil {
+				internalServerError(c.Resp)
+				log.Error("Failed to list users: %v", err)
+				return
+			}
+		}
+	}
+
+	resources := make([]*user, len(users))
+	for i := range users {
+		resources[i] = toUser(users[i])
+	}
+	responseJSON(c.Resp, http.StatusOK, listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+func serveGetUser(c *macaron.Context, u *db.User) {
+	responseJSON(c.Resp, http.StatusOK, toUser(u))
+}
+
+// respondUserError responds the error of creating or updating a user.
+func respondUserError(c *macaron.Context, err error) {
+	switch {
+	case db.IsErrUserAlreadyExist(err):
+		responseError(c.Resp, http.StatusConflict, errUniqueness, "The userName has been taken")
+	case db.IsErrEmailAlreadyUsed(err):
+		responseError(c.Resp, http.StatusConflict, errUniqueness, "The email has been used")
+	case db.IsErrNameNotAllowed(err):
+		responseError(c.Resp, http.StatusBadRequest, errInvalidValue, err.Error())
+	default:
+		internalServerError(c.Resp)
+		log.Error("Failed to save user: %v", err)
+	}
+}
+
+// validateUser validates required attributes of the user, it responds the error
+// to the client and returns false when invalid.
+func validateUser(c *macaron.Context, su *user) bool {
+	if su.UserName == "" {
+		responseError(c.Resp, http.StatusBadRequest, errInvalidValue, "The userName is required")
+		return false
+	} else if su.email() == "" {
+		responseError(c.Resp, http.StatusBadRequest, errInvalidValue, "The emails is required")
+		return false
+	}
+	return true
+}
+
+func serveCreateUser(c *macaron.Context, actor *authenticatedUser) {
+	var su user
+	if !decodeJSON(c, &su) || !validateUser(c, &su) {
+		return
+	}
+
+	password := su.Password
+	if password == "" {
+		// Users authenticate with the identity provider, the random password makes
+		// sure nobody signs in with an empty password.
+		var err error
+		password, err = strutil.RandomChars(32)
+		if err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to generate random password: %v", err)
+			return
+		}
+	}
+
+	u := &db.User{
+		Name:          su.UserName,
+		FullName:      su.fullName(),
+		Email:         su.email(),
+		Passwd:        password,
+		IsActive:      true,
+		ProhibitLogin: su.Active != nil && !*su.Active,
+	}
+	if conf.SCIM.LoginSource > 0 {
+		u.LoginSource = conf.SCIM.LoginSource
+		u.LoginName = su.ExternalID
+		if u.LoginName == "" {
+			u.LoginName = su.UserName
+		}
+	}
+
+	if err := db.CreateUser(u); err != nil {
+		respondUserError(c, err)
+		return
+	}
+	log.Trace("Account created via SCIM by admin %q: %s", actor.Name, u.Name)
+
+	// Reload to have the timestamps populated.
+	created, err := db.GetUserByID(u.ID)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to get user [id: %d]: %v", u.ID, err)
+		return
+	}
+	responseJSON(c.Resp, http.StatusCreated, toUser(created))
+}
+
+// updateUser saves attributes of the SCIM user to the user.
+func updateUser(c *macaron.Context, actor *authenticatedUser, u *db.User, su *user) {
+	if !strings.EqualFold(u.Name, su.UserName) {
+		if err := db.ChangeUserName(u, su.UserName); err != nil {
+			respondUserError(c, err)
+			return
+		}
+	}
+	u.Name = su.UserName
+	u.FullName = su.fullName()
+	u.Email = su.email()
+	if su.Active != nil {
+		u.ProhibitLogin = !*su.Active
+		if *su.Active {
+			u.IsActive = true
+		}
+	}
+	if su.Password != "" {
+		u.Passwd = su.Password
+		var err error
+		if u.Salt, err = db.GetUserSalt(); err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to get user salt: %v", err)
+			return
+		}
+		u.EncodePassword()
+	}
+	if conf.SCIM.LoginSource > 0 && u.LoginSource == conf.SCIM.LoginSource && su.ExternalID != "" {
+		u.LoginName = su.ExternalID
+	}
+
+	// Deactivated users must not keep access to repositories via their access
+	// tokens and public keys.
+	if err := db.UpdateUserProhibitLogin(u); err != nil {
+		respondUserError(c, err)
+		return
+	}
+	log.Trace("Account updated via SCIM by admin %q: %s", actor.Name, u.Name)
+
+	updated, err := db.GetUserByID(u.ID)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to get user [id: %d]: %v", u.ID, err)
+		return
+	}
+	responseJSON(c.Resp, http.StatusOK, toUser(updated))
+}
+
+func serveReplaceUser(c *macaron.Context, actor *authenticatedUser, u *db.User) {
+	var su user
+	if !decodeJSON(c, &su) || !validateUser(c, &su) {
+		return
+	}
+	updateUser(c, actor, u, &su)
+}
+
+// applyUserPatch applies the value to the attribute of given path. It returns
+// the SCIM error type along with the error when failed.
+func applyUserPatch(su *user, op, path string, value json.RawMessage) (string, error) {
+	op = strings.ToLower(op)
+	if op != "add" && op != "replace" {
+		return errMutability, errors.Errorf("unsupported operation %q", op)
+	}
+
+	if path == "" {
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(value, &values); err != nil {
+			return errInvalidValue, errors.New("the value must be an object when the path is omitted")
+		}
+		for path, value := range values {
+			if typ, err := applyUserPatch(su, op, path, value); err != nil {
+				return typ, err
+			}
+		}
+		return "", nil
+	}
+
+	var err error
+	switch lowerPath := strings.ToLower(path); lowerPath {
+	case "active":
+		var active bool
+		active, err = parseBool(value)
+		su.Active = &active
+	case "username":
+		err = json.Unmarshal(value, &su.UserName)
+	case "displayname":
+		err = json.Unmarshal(value, &su.DisplayName)
+	case "externalid":
+		err = json.Unmarshal(value, &su.ExternalID)
+	case "password":
+		err = json.Unmarshal(value, &su.Password)
+	case "name":
+		su.Name = nil
+		err = json.Unmarshal(value, &su.Name)
+	case "name.formatted", "name.givenname", "name.familyname":
+		if su.Name == nil {
+			su.Name = new(userName)
+		}
+		// The display name takes precedence, reset it to use the new name.
+		su.DisplayName = ""
+		switch lowerPath {
+		case "name.formatted":
+			err = json.Unmarshal(value, &su.Name.Formatted)
+		case "name.givenname":
+			su.Name.Formatted = ""
+			err = json.Unmarshal(value, &su.Name.GivenName)
+		case "name.familyname":
+			su.Name.Formatted = ""
+			err = json.Unmarshal(value, &su.Name.FamilyName)
+		}
+	case "emails":
+		su.Emails = nil
+		err = json.Unmarshal(value, &su.Emails)
+	default:
+		// Some clients update the email with a value filter, e.g.
+		// `emails[type eq "work"].value`.
+		if strings.HasPrefix(lowerPath, "emails[") && strings.HasSuffix(lowerPath, "].value") {
+			var email string
+			err = json.Unmarshal(value, &email)
+			su.setEmail(email)
+			break
+		}
+		return errInvalidPath, errors.Errorf("unsupported path %q", path)
+	}
+	if err != nil {
+		return errInvalidValue, errors.Errorf("invalid value for %q", path)
+	}
+	return "", nil
+}
+
+func servePatchUser(c *macaron.Context, actor *authenticatedUser, u *db.User) {
+	var req patchRequest
+	if !decodeJSON(c, &req) {
+		return
+	}
+
+	su := toUser(u)
+	for _, op := range req.Operations {
+		if typ, err := applyUserPatch(su, op.Op, op.Path, op.Value); err != nil {
+			responseError(c.Resp, http.StatusBadRequest, typ, err.Error())
+			return
+		}
+	}
+	if !validateUser(c, su) {
+		return
+	}
+	updateUser(c, actor, u, su)
+}
+
+func serveDeleteUser(c *macaron.Context, actor *authenticatedUser, u *db.User) {
+	if err := db.DeleteUser(u); err != nil {
+		if db.IsErrUserOwnRepos(err) || db.IsErrUserHasOrgs(err) {
+			responseError(c.Resp, http.StatusConflict, "", err.Error()+`, set "active" to false to prohibit the user from signing in instead`)
+		} else {
+			internalServerError(c.Resp)
+			log.Error("Failed to delete user [id: %d]: %v", u.ID, err)
+		}
+		return
+	}
+	log.Trace("Account deleted via SCIM by admin %q: %s", actor.Name, u.Name)
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyUserPatch(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+
+	tests := []struct {
+		name     string
+		op       string
+		path     string
+		value    string
+		want     *user
+		wantType string
+	}{
+		{
+			name:  "deactivate with string value",
+			op:    "Replace",
+			path:  "active",
+			value: `"False"`,
+			want:  &user{UserName: "alice", DisplayName: "Alice", Active: boolPtr(false), Emails: []userEmail{{Value: "alice@example.com", Primary: true}}},
+		},
+		{
+			name:  "replace given name",
+			op:    "replace",
+			path:  "name.givenName",
+			value: `"Alicia"`,
+			want:  &user{UserName: "alice", Name: &userName{GivenName: "Alicia"}, Active: boolPtr(true), Emails: []userEmail{{Value: "alice@example.com", Primary: true}}},
+		},
+		{
+			name:  "replace email with value filter",
+			op:    "replace",
+			path:  `emails[type eq "work"].value`,
+			value: `"alicia@example.com"`,
+			want:  &user{UserName: "alice", DisplayName: "Alice", Active: boolPtr(true), Emails: []userEmail{{Value: "alicia@example.com", Primary: true}}},
+		},
+		{
+			name:  "replace without path",
+			op:    "replace",
+			value: `{"userName":"alicia","active":false}`,
+			want:  &user{UserName: "alicia", DisplayName: "Alice", Active: boolPtr(false), Emails: []userEmail{{Value: "alice@example.com", Primary: true}}},
+		},
+		{
+			name:     "remove is not supported",
+			op:       "remove",
+			path:     "displayName",
+			wantType: errMutability,
+		},
+		{
+			name:     "unsupported path",
+			op:       "replace",
+			path:     "nickName",
+			value:    `"al"`,
+			wantType: errInvalidPath,
+		},
+		{
+			name:     "invalid value",
+			op:       "replace",
+			path:     "active",
+			value:    `"maybe"`,
+			wantType: errInvalidValue,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			su := &user{
+				UserName:    "alice",
+				Name:        &userName{Formatted: "Alice"},
+				DisplayName: "Alice",
+				Active:      boolPtr(true),
+				Emails:      []userEmail{{Value: "alice@example.com", Primary: true}},
+			}
+			typ, err := applyUserPatch(su, test.op, test.path, json.RawMessage(test.value))
+			if test.wantType != "" {
+				assert.Error(t, err)
+				assert.Equal(t, test.wantType, typ)
+				return
+			}
+			assert.NoError(t, err)
+
+			// Only compare the name when the test wants it.
+			if test.want.Name == nil {
+				su.Name = nil
+			}
+			assert.Equal(t, test.want, su)
+		})
+	}
+}
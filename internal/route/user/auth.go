@@ -64,6 +64,10 @@ func AutoLogin(c *context.Context) (bool, error) {
 		return false, nil
 	}
 
+	if err = c.CheckTwoFactorRequired(u); err != nil {
+		return false, fmt.Errorf("check two-factor requirement: %v", err)
+	}
+
 	isSucceed = true
 	_ = c.Session.Set("uid", u.ID)
 	_ = c.Session.Set("uname", u.Name)
@@ -102,12 +106,11 @@ func Login(c *context.Context) {
 	}
 
 	// Display normal login page
-	loginSources, err := db.LoginSources.List(c.Req.Context(), db.ListLoginSourceOptions{OnlyActivated: true})
+	loginSources, err := listLoginSources(c)
 	if err != nil {
 		c.Error(err, "list activated login sources")
 		return
 	}
-	c.Data["LoginSources"] = loginSources
 	for i := range loginSources {
 		if loginSources[i].IsDefault {
 			c.Data["DefaultLoginSource"] = loginSources[i]
@@ -118,7 +121,35 @@ func Login(c *context.Context) {
 	c.Success(LOGIN)
 }
 
+// listLoginSources returns activated login sources which authenticate users
+// with password, and sets them to the context data along with those
+// authenticate users by redirecting to the external identity provider.
+func listLoginSources(c *context.Context) ([]*db.LoginSource, error) {
+	sources, err := db.LoginSources.List(c.Req.Context(), db.ListLoginSourceOptions{OnlyActivated: true})
+	if err != nil {
+		return nil, err
+	}
+
+	loginSources := make([]*db.LoginSource, 0, len(sources))
+	redirectLoginSources := make([]*db.LoginSource, 0, len(sources))
+	for _, source := range sources {
+		if _, ok := source.Provider.(auth.RedirectProvider); ok {
+			redirectLoginSources = append(redirectLoginSources, source)
+		} else {
+			loginSources = append(loginSources, source)
+		}
+	}
+	c.Data["LoginSources"] = loginSources
+	c.Data["RedirectLoginSources"] = redirectLoginSources
+	return loginSources, nil
+}
+
 func afterLogin(c *context.Context, u *db.User, remember bool) {
+	if err := c.CheckTwoFactorRequired(u); err != nil {
+		c.Error(err, "check two-factor requirement")
+		return
+	}
+
 	if remember {
 		days := 86400 * conf.Security.LoginRememberDays
 		c.SetCookie(conf.Security.CookieUsername, u.Name, days, conf.Server.Subpath, "", conf.Security.CookieSecure, true)
@@ -138,6 +169,10 @@ func afterLogin(c *context.Context, u *db.User, remember bool) {
 
 	redirectTo, _ := url.QueryUnescape(c.GetCookie("redirect_to"))
 	c.SetCookie("redirect_to", "", -1, conf.Server.Subpath)
+	if c.Session.Get("twoFactorRequired") != nil {
+		c.RedirectSubpath("/user/settings/security")
+		return
+	}
 	if tool.IsSameSiteURLPath(redirectTo) {
 		c.Redirect(redirectTo)
 		return
@@ -149,12 +184,11 @@ func afterLogin(c *context.Context, u *db.User, remember bool) {
 func LoginPost(c *context.Context, f form.SignIn) {
 	c.Title("sign_in")
 
-	loginSources, err := db.LoginSources.List(c.Req.Context(), db.ListLoginSourceOptions{OnlyActivated: true})
+	loginSources, err := listLoginSources(c)
 	if err != nil {
 		c.Error(err, "list activated login sources")
 		return
 	}
-	c.Data["LoginSources"] = loginSources
 
 	if c.HasError() {
 		c.Success(LOGIN)
@@ -194,12 +228,24 @@ func LoginPost(c *context.Context, f form.SignIn) {
 }
 
 func LoginTwoFactor(c *context.Context) {
-	_, ok := c.Session.Get("twoFactorUserID").(int64)
+	userID, ok := c.Session.Get("twoFactorUserID").(int64)
 	if !ok {
 		c.NotFound()
 		return
 	}
 
+	_, err := db.TwoFactors.GetByUserID(c.Req.Context(), userID)
+	if err != nil && !db.IsErrTwoFactorNotFound(err) {
+		c.Error(err, "get two factor by user ID")
+		return
+	}
+	c.Data["HasTOTP"] = err == nil
+
+	if err = prepareWebAuthnLogin(c, userID); err != nil {
+		c.Error(err, "prepare WebAuthn login")
+		return
+	}
+
 	c.Success(TWO_FACTOR)
 }
 
@@ -210,9 +256,29 @@ func LoginTwoFactorPost(c *context.Context) {
 		return
 	}
 
+	if c.Query("credential_id") != "" {
+		valid, err := loginWebAuthn(c, userID)
+		if err != nil {
+			c.Error(err, "login with WebAuthn")
+			return
+		} else if !valid {
+			c.Flash.Error(c.Tr("auth.login_two_factor_webauthn_failed"))
+			c.RedirectSubpath("/user/login/two_factor")
+			return
+		}
+
+		u, err := db.GetUserByID(userID)
+		if err != nil {
+			c.Error(err, "get user by ID")
+			return
+		}
+		afterLogin(c, u, c.Session.Get("twoFactorRemember").(bool))
+		return
+	}
+
 	t, err := db.TwoFactors.GetByUserID(c.Req.Context(), userID)
 	if err != nil {
-		c.Error(err, "get two factor by user ID")
+		c.NotFoundOrError(err, "get two factor by user ID")
 		return
 	}
 
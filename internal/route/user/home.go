@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/unknwon/com"
 	"github.com/unknwon/paginater"
@@ -15,6 +16,7 @@ import (
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/search"
 )
 
 const (
@@ -214,6 +216,15 @@ func Issues(c *context.Context) {
 	repoID := c.QueryInt64("repo")
 	isShowClosed := c.Query("state") == "closed"
 
+	keyword := strings.TrimSpace(c.Query("q"))
+	var query *search.IssueQuery
+	if keyword != "" {
+		query = search.ParseIssueQuery(keyword)
+		if query.State != "" {
+			isShowClosed = query.State == "closed"
+		}
+	}
+
 	// Get repositories.
 	var (
 		err         error
@@ -274,6 +285,7 @@ func Issues(c *context.Context) {
 		IsClosed: isShowClosed,
 		IsPull:   isPullList,
 		SortType: sortType,
+		Query:    query,
 	}
 	switch filterMode {
 	case db.FILTER_MODE_YOUR_REPOS:
@@ -327,6 +339,24 @@ func Issues(c *context.Context) {
 
 	issueStats := db.GetUserIssueStats(repoID, ctxUser.ID, userRepoIDs, filterMode, isPullList)
 
+	// Issue statistics do not take the search query into account, count matched
+	// issues instead.
+	if query != nil {
+		opts := *issueOptions
+		opts.IsClosed = false
+		issueStats.OpenCount, err = db.IssuesCount(&opts)
+		if err != nil {
+			c.Error(err, "count open issues")
+			return
+		}
+		opts.IsClosed = true
+		issueStats.ClosedCount, err = db.IssuesCount(&opts)
+		if err != nil {
+			c.Error(err, "count closed issues")
+			return
+		}
+	}
+
 	var total int
 	if !isShowClosed {
 		total = int(issueStats.OpenCount)
@@ -342,6 +372,7 @@ func Issues(c *context.Context) {
 	c.Data["SortType"] = sortType
 	c.Data["RepoID"] = repoID
 	c.Data["IsShowClosed"] = isShowClosed
+	c.Data["Keyword"] = keyword
 
 	if isShowClosed {
 		c.Data["State"] = "closed"
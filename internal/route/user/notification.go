@@ -0,0 +1,123 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/unknwon/paginater"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+const (
+	NOTIFICATIONS = "user/notifications"
+)
+
+func Notifications(c *context.Context) {
+	c.Data["Title"] = c.Tr("notifications")
+	c.Data["PageIsNotifications"] = true
+
+	page := c.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+	opts := db.ListNotificationsOptions{
+		RepoID:   c.QueryInt64("repo"),
+		All:      c.Query("type") == "all",
+		Page:     page,
+		PageSize: conf.UI.IssuePagingNum,
+	}
+	c.Data["RepoID"] = opts.RepoID
+	c.Data["IsShowAll"] = opts.All
+	c.Data["ViewType"] = c.Query("type")
+
+	counts, err := db.Notifications.CountByRepo(c.Req.Context(), c.User.ID, opts)
+	if err != nil {
+		c.Error(err, "count notifications by repository")
+		return
+	}
+	repoIDs := make([]int64, 0, len(counts))
+	for repoID := range counts {
+		repoIDs = append(repoIDs, repoID)
+	}
+	repos, err := db.GetNotificationRepos(c.User.ID, repoIDs)
+	if err != nil {
+		c.Error(err, "get notification repositories")
+		return
+	}
+	c.Data["Repos"] = repos
+	c.Data["RepoCounts"] = counts
+
+	total, err := db.Notifications.Count(c.Req.Context(), c.User.ID, opts)
+	if err != nil {
+		c.Error(err, "count notifications")
+		return
+	}
+	c.Data["Page"] = paginater.New(int(total), opts.PageSize, page, 5)
+
+	notifications, err := db.Notifications.List(c.Req.Context(), c.User.ID, opts)
+	if err != nil {
+		c.Error(err, "list notifications")
+		return
+	}
+	threads, err := db.LoadNotificationThreads(c.User.ID, notifications)
+	if err != nil {
+		c.Error(err, "load notification threads")
+		return
+	}
+	c.Data["Threads"] = threads
+
+	c.Success(NOTIFICATIONS)
+}
+
+// NotificationThread marks the notification as read and redirects to the
+// latest activity on its subject.
+func NotificationThread(c *context.Context) {
+	n, err := db.Notifications.GetByID(c.Req.Context(), c.User.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get notification by ID")
+		return
+	}
+
+	threads, err := db.LoadNotificationThreads(c.User.ID, []*db.Notification{n})
+	if err != nil {
+		c.Error(err, "load notification threads")
+		return
+	} else if len(threads) == 0 {
+		c.NotFound()
+		return
+	}
+
+	if err = db.Notifications.MarkRead(c.Req.Context(), c.User.ID, n.ID); err != nil {
+		c.Error(err, "mark notification as read")
+		return
+	}
+	c.Redirect(threads[0].HTMLURL())
+}
+
+func MarkNotificationRead(c *context.Context) {
+	err := db.Notifications.MarkRead(c.Req.Context(), c.User.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "mark notification as read")
+		return
+	}
+	c.RedirectSubpath("/notifications", http.StatusSeeOther)
+}
+
+func MarkAllNotificationsRead(c *context.Context) {
+	repoID := c.QueryInt64("repo")
+	err := db.Notifications.MarkAllRead(c.Req.Context(), c.User.ID, repoID, time.Now())
+	if err != nil {
+		c.Error(err, "mark all notifications as read")
+		return
+	}
+
+	c.Flash.Success(c.Tr("notification.marked_all_read"))
+	c.RedirectSubpath("/notifications", http.StatusSeeOther)
+}
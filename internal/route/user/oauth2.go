@@ -0,0 +1,330 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/auth"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+const (
+	OAUTH2_AUTHORIZE = "user/auth/oauth2_authorize"
+)
+
+// oauth2AuthorizeRequest is a validated authorization request of the
+// authorization-code flow.
+type oauth2AuthorizeRequest struct {
+	Application         *db.OAuth2Application
+	RedirectURI         string
+	Scopes              auth.Scopes
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// redirectOAuth2 redirects the user back to the application with given query
+// parameters appended to the redirect URI.
+func redirectOAuth2(c *context.Context, redirectURI string, params url.Values) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		c.Error(err, "parse redirect URI")
+		return
+	}
+
+	query := u.Query()
+	for k, vs := range params {
+		for _, v := range vs {
+			if v != "" {
+				query.Add(k, v)
+			}
+		}
+	}
+	u.RawQuery = query.Encode()
+	c.Redirect(u.String())
+}
+
+// parseOAuth2AuthorizeRequest validates the authorization request. Errors about
+// the client or the redirect URI are shown to the user directly, others are
+// reported back to the application via the redirect URI.
+func parseOAuth2AuthorizeRequest(c *context.Context) *oauth2AuthorizeRequest {
+	app, err := db.OAuth2.GetApplicationByClientID(c.Req.Context(), c.Query("client_id"))
+	if err != nil {
+		if db.IsErrOAuth2ApplicationNotExist(err) {
+			c.PlainText(http.StatusBadRequest, "Unknown client_id.")
+		} else {
+			c.Error(err, "get OAuth2 application by client ID")
+		}
+		return nil
+	}
+
+	redirectURI := c.Query("redirect_uri")
+	if redirectURI == "" {
+		uris := app.RedirectURIList()
+		if len(uris) == 1 {
+			redirectURI = uris[0]
+		}
+	}
+	if !app.IsValidRedirectURI(redirectURI) {
+		c.PlainText(http.StatusBadRequest, "The redirect_uri does not match any of registered redirect URIs.")
+		return nil
+	}
+
+	state := c.Query("state")
+	fail := func(code, desc string) {
+		redirectOAuth2(c, redirectURI, url.Values{
+			"error":             {code},
+			"error_description": {desc},
+			"state":             {state},
+		})
+	}
+
+	if c.Query("response_type") != "code" {
+		fail("unsupported_response_type", "Only the authorization code flow is supported.")
+		return nil
+	}
+
+	scopes, err := auth.ParseScopes(c.Query("scope"))
+	if err != nil {
+		fail("invalid_scope", err.Error())
+		return nil
+	}
+
+	codeChallenge := c.Query("code_challenge")
+	codeChallengeMethod := c.Query("code_challenge_method")
+	if codeChallenge == "" {
+		if !app.Confidential {
+			fail("invalid_request", "Public clients must use PKCE.")
+			return nil
+		}
+	} else {
+		if codeChallengeMethod == "" {
+			codeChallengeMethod = "plain"
+		}
+		if codeChallengeMethod != "plain" && codeChallengeMethod != "S256" {
+			fail("invalid_request", "The code_challenge_method must be either \"plain\" or \"S256\".")
+			return nil
+		}
+	}
+
+	return &oauth2AuthorizeRequest{
+		Application:         app,
+		RedirectURI:         redirectURI,
+		Scopes:              scopes,
+		State:               state,
+		CodeChallenge:       codeChallenge,
+		CodeChallengeMethod: codeChallengeMethod,
+	}
+}
+
+// redirectOAuth2WithCode issues an authorization code and redirects the user
+// back to the application.
+func redirectOAuth2WithCode(c *context.Context, req *oauth2AuthorizeRequest) {
+	code, err := db.OAuth2.CreateAuthorizationCode(c.Req.Context(),
+		db.CreateOAuth2AuthorizationCodeOptions{
+			ApplicationID:       req.Application.ID,
+			UserID:              c.User.ID,
+			RedirectURI:         req.RedirectURI,
+			Scopes:              req.Scopes,
+			CodeChallenge:       req.CodeChallenge,
+			CodeChallengeMethod: req.CodeChallengeMethod,
+		},
+	)
+	if err != nil {
+		c.Error(err, "create authorization code")
+		return
+	}
+
+	redirectOAuth2(c, req.RedirectURI, url.Values{
+		"code":  {code},
+		"state": {req.State},
+	})
+}
+
+// OAuth2Authorize shows the consent screen to the user, or redirects back to
+// the application directly when requested scopes have been granted.
+func OAuth2Authorize(c *context.Context) {
+	req := parseOAuth2AuthorizeRequest(c)
+	if c.Written() {
+		return
+	}
+
+	grant, err := db.OAuth2.GetGrant(c.Req.Context(), c.User.ID, req.Application.ID)
+	if err != nil && !db.IsErrOAuth2GrantNotExist(err) {
+		c.Error(err, "get OAuth2 grant")
+		return
+	} else if err == nil && grant.ScopeList().HasAll(req.Scopes) {
+		redirectOAuth2WithCode(c, req)
+		return
+	}
+
+	owner, err := db.GetUserByID(req.Application.UserID)
+	if err != nil {
+		c.Error(err, "get application owner")
+		return
+	}
+
+	c.Title("auth.oauth2_authorize_title")
+	c.Data["Request"] = req
+	c.Data["Owner"] = owner
+	c.Data["Scope"] = req.Scopes.String()
+	c.Success(OAUTH2_AUTHORIZE)
+}
+
+// OAuth2AuthorizePost handles the decision of the user on the consent screen.
+func OAuth2AuthorizePost(c *context.Context) {
+	req := parseOAuth2AuthorizeRequest(c)
+	if c.Written() {
+		return
+	}
+
+	if c.Query("action") != "approve" {
+		redirectOAuth2(c, req.RedirectURI, url.Values{
+			"error":             {"access_denied"},
+			"error_description": {"The user denied the authorization."},
+			"state":             {req.State},
+		})
+		return
+	}
+
+	err := db.OAuth2.SaveGrant(c.Req.Context(), c.User.ID, req.Application.ID, req.Scopes)
+	if err != nil {
+		c.Error(err, "save OAuth2 grant")
+		return
+	}
+	redirectOAuth2WithCode(c, req)
+}
+
+// oauth2TokenError responds an error of the token endpoint, see
+// https://datatracker.ietf.org/doc/html/rfc6749#section-5.2.
+func oauth2TokenError(c *context.Context, status int, code, desc string) {
+	c.Header().Set("Cache-Control", "no-store")
+	c.Header().Set("Pragma", "no-cache")
+	c.JSON(status, map[string]string{
+		"error":             code,
+		"error_description": desc,
+	})
+}
+
+// authenticateOAuth2Client authenticates the application using either HTTP
+// Basic Authentication or the client credentials in the request body. Public
+// applications are only identified by the client ID.
+func authenticateOAuth2Client(c *context.Context) *db.OAuth2Application {
+	clientID, clientSecret, ok := c.Req.BasicAuth()
+	if !ok {
+		clientID = c.Query("client_id")
+		clientSecret = c.Query("client_secret")
+	}
+
+	app, err := db.OAuth2.GetApplicationByClientID(c.Req.Context(), clientID)
+	if err != nil {
+		if db.IsErrOAuth2ApplicationNotExist(err) {
+			oauth2TokenError(c, http.StatusUnauthorized, "invalid_client", "Client authentication failed.")
+		} else {
+			c.Error(err, "get OAuth2 application by client ID")
+		}
+		return nil
+	}
+
+	if app.Confidential && !app.ValidateClientSecret(clientSecret) {
+		oauth2TokenError(c, http.StatusUnauthorized, "invalid_client", "Client authentication failed.")
+		return nil
+	}
+	return app
+}
+
+// OAuth2AccessToken exchanges an authorization code or a refresh token for a
+// new pair of tokens.
+func OAuth2AccessToken(c *context.Context) {
+	app := authenticateOAuth2Client(c)
+	if c.Written() {
+		return
+	}
+
+	var token *db.OAuth2Token
+	switch c.Query("grant_type") {
+	case "authorization_code":
+		code, err := db.OAuth2.ExchangeAuthorizationCode(c.Req.Context(), app.ID, c.Query("code"))
+		if err != nil {
+			if db.IsErrOAuth2AuthorizationCodeNotExist(err) {
+				oauth2TokenError(c, http.StatusBadRequest, "invalid_grant", "The authorization code is invalid or has expired.")
+			} else {
+				c.Error(err, "exchange authorization code")
+			}
+			return
+		}
+
+		if c.Query("redirect_uri") != code.RedirectURI {
+			oauth2TokenError(c, http.StatusBadRequest, "invalid_grant", "The redirect_uri does not match the authorization request.")
+			return
+		} else if !code.VerifyCodeChallenge(c.Query("code_verifier")) {
+			oauth2TokenError(c, http.StatusBadRequest, "invalid_grant", "The code_verifier does not match the code challenge.")
+			return
+		}
+
+		token, err = db.OAuth2.IssueToken(c.Req.Context(), app.ID, code.UserID, code.ScopeList())
+		if err != nil {
+			c.Error(err, "issue token")
+			return
+		}
+
+	case "refresh_token":
+		scopes, err := auth.ParseScopes(c.Query("scope"))
+		if err != nil {
+			oauth2TokenError(c, http.StatusBadRequest, "invalid_scope", err.Error())
+			return
+		}
+
+		token, err = db.OAuth2.RefreshToken(c.Req.Context(), app.ID, c.Query("refresh_token"), scopes)
+		if err != nil {
+			switch {
+			case db.IsErrOAuth2TokenNotExist(err):
+				oauth2TokenError(c, http.StatusBadRequest, "invalid_grant", "The refresh token is invalid or has expired.")
+			case db.IsErrOAuth2ScopesExceeded(err):
+				oauth2TokenError(c, http.StatusBadRequest, "invalid_scope", "The requested scope exceeds the scope granted by the user.")
+			default:
+				c.Error(err, "refresh token")
+			}
+			return
+		}
+
+	default:
+		oauth2TokenError(c, http.StatusBadRequest, "unsupported_grant_type", "The grant_type must be either \"authorization_code\" or \"refresh_token\".")
+		return
+	}
+
+	c.Header().Set("Cache-Control", "no-store")
+	c.Header().Set("Pragma", "no-cache")
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"access_token":  token.AccessToken,
+		"token_type":    "bearer",
+		"expires_in":    int64(time.Until(token.AccessExpiresAt).Seconds()),
+		"refresh_token": token.RefreshToken,
+		"scope":         token.Scopes,
+	})
+}
+
+// OAuth2Revoke revokes an access token or a refresh token issued to the
+// application, see https://datatracker.ietf.org/doc/html/rfc7009.
+func OAuth2Revoke(c *context.Context) {
+	app := authenticateOAuth2Client(c)
+	if c.Written() {
+		return
+	}
+
+	err := db.OAuth2.RevokeToken(c.Req.Context(), app.ID, c.Query("token"))
+	if err != nil {
+		log.Error("Failed to revoke OAuth2 token: %v", err)
+		oauth2TokenError(c, http.StatusServiceUnavailable, "temporarily_unavailable", "Failed to revoke the token.")
+		return
+	}
+	c.Status(http.StatusOK)
+}
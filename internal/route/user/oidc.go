@@ -0,0 +1,133 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/auth"
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/strutil"
+)
+
+// oidcRedirectURL returns the URL the identity provider redirects the user back
+// to after signed in.
+func oidcRedirectURL(loginSourceID int64) string {
+	return fmt.Sprintf("%suser/login/oidc/%d/callback", conf.Server.ExternalURL, loginSourceID)
+}
+
+// getRedirectLoginSource returns the activated login source in the URL
+// parameters which authenticates users by redirecting them to the external
+// identity provider.
+func getRedirectLoginSource(c *context.Context) (*db.LoginSource, auth.RedirectProvider) {
+	source, err := db.LoginSources.GetByID(c.Req.Context(), c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get login source by ID")
+		return nil, nil
+	}
+
+	provider, ok := source.Provider.(auth.RedirectProvider)
+	if !ok || !source.IsActived {
+		c.NotFound()
+		return nil, nil
+	}
+	return source, provider
+}
+
+// LoginOIDC redirects the user to sign in at the identity provider.
+func LoginOIDC(c *context.Context) {
+	source, provider := getRedirectLoginSource(c)
+	if c.Written() {
+		return
+	}
+
+	state, err := strutil.RandomChars(32)
+	if err != nil {
+		c.Error(err, "generate state")
+		return
+	}
+	nonce, err := strutil.RandomChars(32)
+	if err != nil {
+		c.Error(err, "generate nonce")
+		return
+	}
+	_ = c.Session.Set("oidcState", state)
+	_ = c.Session.Set("oidcNonce", nonce)
+
+	authCodeURL, err := provider.AuthCodeURL(oidcRedirectURL(source.ID), state, nonce)
+	if err != nil {
+		c.Error(err, "get auth code URL")
+		return
+	}
+	c.Redirect(authCodeURL)
+}
+
+// LoginOIDCCallback signs in the user who comes back from the identity
+// provider, a new user is created for the first time.
+func LoginOIDCCallback(c *context.Context) {
+	source, provider := getRedirectLoginSource(c)
+	if c.Written() {
+		return
+	}
+
+	state, _ := c.Session.Get("oidcState").(string)
+	nonce, _ := c.Session.Get("oidcNonce").(string)
+	_ = c.Session.Delete("oidcState")
+	_ = c.Session.Delete("oidcNonce")
+
+	if state == "" || c.Query("state") != state {
+		c.Flash.Error(c.Tr("auth.oidc_invalid_state"))
+		c.RedirectSubpath("/user/login")
+		return
+	}
+
+	// The user may decline the authorization at the identity provider.
+	if errCode := c.Query("error"); errCode != "" {
+		log.Trace("OpenID Connect sign in via %q failed: %s: %s", source.Name, errCode, c.Query("error_description"))
+		c.Flash.Error(c.Tr("auth.oidc_failed", source.Name))
+		c.RedirectSubpath("/user/login")
+		return
+	}
+
+	extAccount, err := provider.Exchange(c.Req.Context(), oidcRedirectURL(source.ID), c.Query("code"), nonce)
+	if err != nil {
+		if auth.IsErrBadCredentials(err) {
+			c.Flash.Error(c.Tr("auth.oidc_not_allowed", source.Name))
+		} else {
+			log.Error("Failed to exchange OpenID Connect token via %q: %v", source.Name, err)
+			c.Flash.Error(c.Tr("auth.oidc_failed", source.Name))
+		}
+		c.RedirectSubpath("/user/login")
+		return
+	}
+
+	u, err := db.Users.AuthenticateExternal(c.Req.Context(), source.ID, extAccount)
+	if err != nil {
+		switch {
+		case db.IsErrUserAlreadyExist(err):
+			c.Flash.Error(c.Tr("form.username_been_taken"))
+		case db.IsErrEmailAlreadyUsed(err):
+			c.Flash.Error(c.Tr("form.email_been_used"))
+		default:
+			c.Error(err, "authenticate external user")
+			return
+		}
+		c.RedirectSubpath("/user/login")
+		return
+	}
+
+	if !u.IsEnabledTwoFactor() {
+		afterLogin(c, u, false)
+		return
+	}
+
+	_ = c.Session.Set("twoFactorRemember", false)
+	_ = c.Session.Set("twoFactorUserID", u.ID)
+	c.RedirectSubpath("/user/login/two_factor")
+}
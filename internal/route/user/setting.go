@@ -11,7 +11,9 @@ import (
 	"html/template"
 	"image/png"
 	"io/ioutil"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
@@ -41,6 +43,7 @@ const (
 	SETTINGS_REPOSITORIES              = "user/settings/repositories"
 	SETTINGS_ORGANIZATIONS             = "user/settings/organizations"
 	SETTINGS_APPLICATIONS              = "user/settings/applications"
+	SETTINGS_OAUTH2_APPLICATION        = "user/settings/oauth2_application"
 	SETTINGS_DELETE                    = "user/settings/delete"
 	NOTIFICATION                       = "user/notification"
 )
@@ -387,21 +390,32 @@ func SettingsSecurity(c *context.Context) {
 		return
 	}
 	c.Data["TwoFactor"] = t
+	c.Data["TwoFactorRequiredBy"] = c.Session.Get("twoFactorRequired")
+
+	credentials, err := db.TwoFactors.ListWebAuthnCredentials(c.Req.Context(), c.UserID())
+	if err != nil {
+		c.Errorf(err, "list WebAuthn credentials")
+		return
+	}
+	c.Data["WebAuthnCredentials"] = credentials
 
 	c.Success(SETTINGS_SECURITY)
 }
 
 func SettingsTwoFactorEnable(c *context.Context) {
-	if c.User.IsEnabledTwoFactor() {
+	_, err := db.TwoFactors.GetByUserID(c.Req.Context(), c.UserID())
+	if err == nil {
 		c.NotFound()
 		return
+	} else if !db.IsErrTwoFactorNotFound(err) {
+		c.Errorf(err, "get two factor by user ID")
+		return
 	}
 
 	c.Title("settings.two_factor_enable_title")
 	c.PageIs("SettingsSecurity")
 
 	var key *otp.Key
-	var err error
 	keyURL := c.Session.Get("twoFactorURL")
 	if keyURL != nil {
 		key, _ = otp.NewKeyFromURL(keyURL.(string))
@@ -457,6 +471,7 @@ func SettingsTwoFactorEnablePost(c *context.Context) {
 
 	_ = c.Session.Delete("twoFactorSecret")
 	_ = c.Session.Delete("twoFactorURL")
+	_ = c.Session.Delete("twoFactorRequired")
 	c.Flash.Success(c.Tr("settings.two_factor_enable_success"))
 	c.RedirectSubpath("/user/settings/security/two_factor_recovery_codes")
 }
@@ -496,12 +511,13 @@ func SettingsTwoFactorRecoveryCodesPost(c *context.Context) {
 }
 
 func SettingsTwoFactorDisable(c *context.Context) {
-	if !c.User.IsEnabledTwoFactor() {
-		c.NotFound()
+	_, err := db.TwoFactors.GetByUserID(c.Req.Context(), c.UserID())
+	if err != nil {
+		c.NotFoundOrError(err, "get two factor by user ID")
 		return
 	}
 
-	if err := db.DeleteTwoFactor(c.UserID()); err != nil {
+	if err = db.DeleteTwoFactor(c.UserID()); err != nil {
 		c.Errorf(err, "delete two factor")
 		return
 	}
@@ -577,16 +593,40 @@ func SettingsLeaveOrganization(c *context.Context) {
 	})
 }
 
-func SettingsApplications(c *context.Context) {
-	c.Title("settings.applications")
-	c.PageIs("SettingsApplications")
-
+// loadApplications loads access tokens, OAuth2 applications and authorized
+// OAuth2 applications of the user into template data.
+func loadApplications(c *context.Context) {
 	tokens, err := db.AccessTokens.List(c.Req.Context(), c.User.ID)
 	if err != nil {
 		c.Errorf(err, "list access tokens")
 		return
 	}
 	c.Data["Tokens"] = tokens
+	c.Data["AllScopes"] = auth.AllScopes
+
+	apps, err := db.OAuth2.ListApplications(c.Req.Context(), c.User.ID)
+	if err != nil {
+		c.Errorf(err, "list OAuth2 applications")
+		return
+	}
+	c.Data["OAuth2Applications"] = apps
+
+	grants, err := db.OAuth2.ListGrants(c.Req.Context(), c.User.ID)
+	if err != nil {
+		c.Errorf(err, "list OAuth2 grants")
+		return
+	}
+	c.Data["OAuth2Grants"] = grants
+}
+
+func SettingsApplications(c *context.Context) {
+	c.Title("settings.applications")
+	c.PageIs("SettingsApplications")
+
+	loadApplications(c)
+	if c.Written() {
+		return
+	}
 
 	c.Success(SETTINGS_APPLICATIONS)
 }
@@ -596,18 +636,29 @@ func SettingsApplicationsPost(c *context.Context, f form.NewAccessToken) {
 	c.PageIs("SettingsApplications")
 
 	if c.HasError() {
-		tokens, err := db.AccessTokens.List(c.Req.Context(), c.User.ID)
-		if err != nil {
-			c.Errorf(err, "list access tokens")
+		loadApplications(c)
+		if c.Written() {
 			return
 		}
 
-		c.Data["Tokens"] = tokens
 		c.Success(SETTINGS_APPLICATIONS)
 		return
 	}
 
-	t, err := db.AccessTokens.Create(c.Req.Context(), c.User.ID, f.Name)
+	scopes, err := auth.ParseScopes(strings.Join(f.Scopes, " "))
+	if err != nil {
+		c.Flash.Error(c.Tr("settings.token_invalid_scopes"))
+		c.RedirectSubpath("/user/settings/applications")
+		return
+	}
+
+	opts := db.CreateAccessTokenOptions{
+		Scopes: scopes,
+	}
+	if f.Expiration > 0 {
+		opts.ExpiresAt = time.Now().AddDate(0, 0, f.Expiration)
+	}
+	t, err := db.AccessTokens.Create(c.Req.Context(), c.User.ID, f.Name, opts)
 	if err != nil {
 		if db.IsErrAccessTokenAlreadyExist(err) {
 			c.Flash.Error(c.Tr("settings.token_name_exists"))
@@ -635,6 +686,169 @@ func SettingsDeleteApplication(c *context.Context) {
 	})
 }
 
+// parseOAuth2RedirectURIs parses the newline-separated list of redirect URIs,
+// every URI must be absolute. Custom schemes are allowed for native
+// applications.
+func parseOAuth2RedirectURIs(s string) ([]string, bool) {
+	uris := strings.Fields(s)
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err != nil || !u.IsAbs() || u.Fragment != "" {
+			return nil, false
+		}
+	}
+	return uris, len(uris) > 0
+}
+
+func SettingsOAuth2ApplicationNew(c *context.Context) {
+	c.Title("settings.oauth2_new_app")
+	c.PageIs("SettingsApplications")
+	c.PageIs("OAuth2ApplicationNew")
+	c.Data["confidential"] = true
+	c.Success(SETTINGS_OAUTH2_APPLICATION)
+}
+
+func SettingsOAuth2ApplicationNewPost(c *context.Context, f form.OAuth2Application) {
+	c.Title("settings.oauth2_new_app")
+	c.PageIs("SettingsApplications")
+	c.PageIs("OAuth2ApplicationNew")
+
+	if c.HasError() {
+		c.Success(SETTINGS_OAUTH2_APPLICATION)
+		return
+	}
+
+	redirectURIs, ok := parseOAuth2RedirectURIs(f.RedirectURIs)
+	if !ok {
+		c.Data["Err_RedirectURIs"] = true
+		c.RenderWithErr(c.Tr("settings.oauth2_invalid_redirect_uris"), SETTINGS_OAUTH2_APPLICATION, &f)
+		return
+	}
+
+	app, err := db.OAuth2.CreateApplication(c.Req.Context(), c.User.ID,
+		db.CreateOAuth2ApplicationOptions{
+			Name:         f.Name,
+			HomepageURL:  f.HomepageURL,
+			RedirectURIs: redirectURIs,
+			Confidential: f.Confidential,
+		},
+	)
+	if err != nil {
+		if db.IsErrOAuth2ApplicationAlreadyExist(err) {
+			c.Data["Err_Name"] = true
+			c.RenderWithErr(c.Tr("settings.oauth2_app_name_exists"), SETTINGS_OAUTH2_APPLICATION, &f)
+		} else {
+			c.Errorf(err, "create OAuth2 application")
+		}
+		return
+	}
+
+	c.Flash.Success(c.Tr("settings.oauth2_new_app_success", app.Name))
+	c.Flash.Info(app.ClientSecret)
+	c.RedirectSubpath(fmt.Sprintf("/user/settings/applications/oauth2/%d", app.ID))
+}
+
+func getOAuth2Application(c *context.Context) *db.OAuth2Application {
+	app, err := db.OAuth2.GetApplicationByID(c.Req.Context(), c.User.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get OAuth2 application by ID")
+		return nil
+	}
+	c.Data["Application"] = app
+	return app
+}
+
+func SettingsOAuth2Application(c *context.Context) {
+	c.Title("settings.oauth2_edit_app")
+	c.PageIs("SettingsApplications")
+
+	app := getOAuth2Application(c)
+	if c.Written() {
+		return
+	}
+
+	c.Data["name"] = app.Name
+	c.Data["homepage_url"] = app.HomepageURL
+	c.Data["redirect_uris"] = app.RedirectURIs
+	c.Data["confidential"] = app.Confidential
+	c.Success(SETTINGS_OAUTH2_APPLICATION)
+}
+
+func SettingsOAuth2ApplicationPost(c *context.Context, f form.OAuth2Application) {
+	c.Title("settings.oauth2_edit_app")
+	c.PageIs("SettingsApplications")
+
+	app := getOAuth2Application(c)
+	if c.Written() {
+		return
+	}
+
+	if c.HasError() {
+		c.Success(SETTINGS_OAUTH2_APPLICATION)
+		return
+	}
+
+	redirectURIs, ok := parseOAuth2RedirectURIs(f.RedirectURIs)
+	if !ok {
+		c.Data["Err_RedirectURIs"] = true
+		c.RenderWithErr(c.Tr("settings.oauth2_invalid_redirect_uris"), SETTINGS_OAUTH2_APPLICATION, &f)
+		return
+	}
+
+	err := db.OAuth2.UpdateApplication(c.Req.Context(), c.User.ID, app.ID,
+		db.UpdateOAuth2ApplicationOptions{
+			Name:         f.Name,
+			HomepageURL:  f.HomepageURL,
+			RedirectURIs: redirectURIs,
+			Confidential: f.Confidential,
+		},
+	)
+	if err != nil {
+		if db.IsErrOAuth2ApplicationAlreadyExist(err) {
+			c.Data["Err_Name"] = true
+			c.RenderWithErr(c.Tr("settings.oauth2_app_name_exists"), SETTINGS_OAUTH2_APPLICATION, &f)
+		} else {
+			c.Errorf(err, "update OAuth2 application")
+		}
+		return
+	}
+
+	c.Flash.Success(c.Tr("settings.oauth2_update_app_success"))
+	c.RedirectSubpath(fmt.Sprintf("/user/settings/applications/oauth2/%d", app.ID))
+}
+
+func SettingsOAuth2ApplicationRegenerateSecret(c *context.Context) {
+	secret, err := db.OAuth2.RegenerateClientSecret(c.Req.Context(), c.User.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "regenerate client secret")
+		return
+	}
+
+	c.Flash.Success(c.Tr("settings.oauth2_regenerate_secret_success"))
+	c.Flash.Info(secret)
+	c.RedirectSubpath(fmt.Sprintf("/user/settings/applications/oauth2/%d", c.ParamsInt64(":id")))
+}
+
+func SettingsOAuth2ApplicationDelete(c *context.Context) {
+	if err := db.OAuth2.DeleteApplication(c.Req.Context(), c.User.ID, c.ParamsInt64(":id")); err != nil {
+		c.Errorf(err, "delete OAuth2 application")
+		return
+	}
+
+	c.Flash.Success(c.Tr("settings.oauth2_delete_app_success"))
+	c.RedirectSubpath("/user/settings/applications")
+}
+
+func SettingsOAuth2GrantRevoke(c *context.Context) {
+	if err := db.OAuth2.RevokeGrant(c.Req.Context(), c.User.ID, c.ParamsInt64(":id")); err != nil {
+		c.Errorf(err, "revoke OAuth2 grant")
+		return
+	}
+
+	c.Flash.Success(c.Tr("settings.oauth2_revoke_grant_success"))
+	c.RedirectSubpath("/user/settings/applications")
+}
+
 func SettingsDelete(c *context.Context) {
 	c.Title("settings.delete")
 	c.PageIs("SettingsDelete")
@@ -0,0 +1,209 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/webauthn"
+)
+
+// relyingParty returns the WebAuthn relying party of the site.
+func relyingParty() *webauthn.RelyingParty {
+	return &webauthn.RelyingParty{
+		ID:     conf.Server.URL.Hostname(),
+		Name:   conf.App.BrandName,
+		Origin: conf.Server.URL.Scheme + "://" + conf.Server.URL.Host,
+	}
+}
+
+// popWebAuthnChallenge returns the pending WebAuthn challenge of the session and
+// deletes it, so that each challenge is used at most once.
+func popWebAuthnChallenge(c *context.Context) string {
+	challenge, _ := c.Session.Get("webauthnChallenge").(string)
+	_ = c.Session.Delete("webauthnChallenge")
+	return challenge
+}
+
+// decodeWebAuthnFields decodes the base64url encoded form fields submitted by
+// the browser.
+func decodeWebAuthnFields(c *context.Context, names ...string) ([][]byte, error) {
+	fields := make([][]byte, len(names))
+	for i, name := range names {
+		b, err := webauthn.DecodeString(c.Query(name))
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = b
+	}
+	return fields, nil
+}
+
+// credentialIDs returns the decoded IDs of given credentials.
+func credentialIDs(credentials []*db.WebAuthnCredential) [][]byte {
+	ids := make([][]byte, 0, len(credentials))
+	for _, cred := range credentials {
+		id, err := webauthn.DecodeString(cred.CredentialID)
+		if err != nil {
+			log.Error("Failed to decode WebAuthn credential ID [id: %d]: %v", cred.ID, err)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func SettingsWebAuthnOptions(c *context.Context) {
+	credentials, err := db.TwoFactors.ListWebAuthnCredentials(c.Req.Context(), c.UserID())
+	if err != nil {
+		c.Errorf(err, "list WebAuthn credentials")
+		return
+	}
+
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		c.Errorf(err, "new challenge")
+		return
+	}
+	_ = c.Session.Set("webauthnChallenge", challenge)
+
+	user := webauthn.User{
+		ID:          []byte(strconv.FormatInt(c.UserID(), 10)),
+		Name:        c.User.Name,
+		DisplayName: c.User.DisplayName(),
+	}
+	c.JSONSuccess(relyingParty().CreationOptions(user, challenge, credentialIDs(credentials)))
+}
+
+func SettingsWebAuthnRegisterPost(c *context.Context) {
+	challenge := popWebAuthnChallenge(c)
+
+	name := strings.TrimSpace(c.Query("name"))
+	if name == "" {
+		c.Flash.Error(c.Tr("settings.webauthn_name_required"))
+		c.RedirectSubpath("/user/settings/security")
+		return
+	}
+
+	fields, err := decodeWebAuthnFields(c, "client_data", "attestation_object")
+	if err != nil {
+		c.Flash.Error(c.Tr("settings.webauthn_register_error", err))
+		c.RedirectSubpath("/user/settings/security")
+		return
+	}
+
+	cred, err := relyingParty().VerifyRegistration(challenge, fields[0], fields[1])
+	if err != nil {
+		c.Flash.Error(c.Tr("settings.webauthn_register_error", err))
+		c.RedirectSubpath("/user/settings/security")
+		return
+	}
+
+	enabled := c.User.IsEnabledTwoFactor()
+	_, err = db.TwoFactors.CreateWebAuthnCredential(c.Req.Context(), c.UserID(), name, cred)
+	if err != nil {
+		if db.IsErrWebAuthnCredentialAlreadyExist(err) {
+			c.Flash.Error(c.Tr("settings.webauthn_already_exist"))
+			c.RedirectSubpath("/user/settings/security")
+		} else {
+			c.Errorf(err, "create WebAuthn credential")
+		}
+		return
+	}
+
+	_ = c.Session.Delete("twoFactorRequired")
+	c.Flash.Success(c.Tr("settings.webauthn_register_success", name))
+	if !enabled {
+		c.RedirectSubpath("/user/settings/security/two_factor_recovery_codes")
+		return
+	}
+	c.RedirectSubpath("/user/settings/security")
+}
+
+func SettingsWebAuthnDelete(c *context.Context) {
+	err := db.TwoFactors.DeleteWebAuthnCredential(c.Req.Context(), c.UserID(), c.QueryInt64("id"))
+	if err != nil {
+		c.Flash.Error(c.Tr("settings.webauthn_delete_error", err))
+	} else {
+		c.Flash.Success(c.Tr("settings.webauthn_delete_success"))
+	}
+
+	c.JSONSuccess(map[string]interface{}{
+		"redirect": conf.Server.Subpath + "/user/settings/security",
+	})
+}
+
+// prepareWebAuthnLogin sets the options for the user to authenticate with one
+// of the registered security keys if any.
+func prepareWebAuthnLogin(c *context.Context, userID int64) error {
+	credentials, err := db.TwoFactors.ListWebAuthnCredentials(c.Req.Context(), userID)
+	if err != nil {
+		return errors.Wrap(err, "list WebAuthn credentials")
+	} else if len(credentials) == 0 {
+		return nil
+	}
+
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return errors.Wrap(err, "new challenge")
+	}
+	_ = c.Session.Set("webauthnChallenge", challenge)
+
+	options, err := json.Marshal(relyingParty().RequestOptions(challenge, credentialIDs(credentials)))
+	if err != nil {
+		return errors.Wrap(err, "encode request options")
+	}
+	c.Data["WebAuthnOptions"] = string(options)
+	return nil
+}
+
+// loginWebAuthn verifies the assertion submitted by the browser. It returns
+// false when the assertion is invalid.
+func loginWebAuthn(c *context.Context, userID int64) (bool, error) {
+	challenge := popWebAuthnChallenge(c)
+
+	fields, err := decodeWebAuthnFields(c, "client_data", "authenticator_data", "signature")
+	if err != nil {
+		return false, nil
+	}
+
+	credentials, err := db.TwoFactors.ListWebAuthnCredentials(c.Req.Context(), userID)
+	if err != nil {
+		return false, errors.Wrap(err, "list WebAuthn credentials")
+	}
+
+	credentialID := strings.TrimRight(c.Query("credential_id"), "=")
+	for _, credential := range credentials {
+		if credential.CredentialID != credentialID {
+			continue
+		}
+
+		cred, err := credential.Credential()
+		if err != nil {
+			return false, errors.Wrap(err, "decode WebAuthn credential")
+		}
+
+		signCount, err := relyingParty().VerifyAssertion(challenge, cred, fields[0], fields[1], fields[2])
+		if err != nil {
+			log.Trace("Failed to verify WebAuthn assertion [user_id: %d, credential_id: %d]: %v", userID, credential.ID, err)
+			return false, nil
+		}
+
+		err = db.TwoFactors.UseWebAuthnCredential(c.Req.Context(), credential.ID, signCount)
+		if err != nil {
+			return false, errors.Wrap(err, "use WebAuthn credential")
+		}
+		return true, nil
+	}
+	return false, nil
+}
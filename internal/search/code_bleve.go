@@ -0,0 +1,273 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	bleveregexp "github.com/blevesearch/bleve/v2/analysis/tokenizer/regexp"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/pkg/errors"
+)
+
+const (
+	codeAnalyzer  = "code"
+	codeTokenizer = "code"
+
+	// maxCodeLines is the maximum number of matched lines returned for a file.
+	maxCodeLines = 5
+)
+
+// codeTokenRegexp matches words in code, identifiers are not split by dots,
+// slashes or other punctuation so "Perms" also matches "db.Perms".
+var codeTokenRegexp = regexp.MustCompile(`[\p{L}\p{N}_]+`)
+
+var _ CodeIndexer = (*bleveCodeIndexer)(nil)
+
+type bleveCodeIndexer struct {
+	index bleve.Index
+}
+
+// bleveCode is the indexed representation of a CodeDocument.
+type bleveCode struct {
+	RepoID  float64 `json:"repo_id"`
+	Path    string  `json:"path"`
+	Content string  `json:"content"`
+}
+
+func newBleveCodeMapping() (mapping.IndexMapping, error) {
+	m := bleve.NewIndexMapping()
+	err := m.AddCustomTokenizer(codeTokenizer, map[string]interface{}{
+		"type":   bleveregexp.Name,
+		"regexp": codeTokenRegexp.String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "add tokenizer")
+	}
+	err = m.AddCustomAnalyzer(codeAnalyzer, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     codeTokenizer,
+		"token_filters": []string{lowercase.Name},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "add analyzer")
+	}
+
+	// The content is stored to highlight matched lines.
+	text := bleve.NewTextFieldMapping()
+	text.Analyzer = codeAnalyzer
+	text.Store = true
+	text.IncludeInAll = false
+	text.IncludeTermVectors = false
+
+	numeric := bleve.NewNumericFieldMapping()
+	numeric.Store = true
+	numeric.IncludeInAll = false
+
+	doc := bleve.NewDocumentStaticMapping()
+	doc.AddFieldMappingsAt("repo_id", numeric)
+	doc.AddFieldMappingsAt("path", text)
+	doc.AddFieldMappingsAt("content", text)
+
+	m.DefaultMapping = doc
+	m.DefaultAnalyzer = codeAnalyzer
+	return m, nil
+}
+
+// NewBleveCodeIndexer opens the bleve code index at given path, or creates
+// one if it does not exist yet. The returned boolean indicates whether the
+// index is newly created and needs to be populated.
+func NewBleveCodeIndexer(path string) (_ CodeIndexer, created bool, _ error) {
+	m, err := newBleveCodeMapping()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "new mapping")
+	}
+	index, created, err := openBleveIndex(path, m)
+	if err != nil {
+		return nil, false, err
+	}
+	return &bleveCodeIndexer{index: index}, created, nil
+}
+
+func codeDocumentID(repoID int64, path string) string {
+	return strconv.FormatInt(repoID, 10) + ":" + path
+}
+
+func (idx *bleveCodeIndexer) Index(docs ...*CodeDocument) error {
+	batch := idx.index.NewBatch()
+	for _, doc := range docs {
+		err := batch.Index(codeDocumentID(doc.RepoID, doc.Path), &bleveCode{
+			RepoID:  float64(doc.RepoID),
+			Path:    doc.Path,
+			Content: doc.Content,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "index file %q of repository %d", doc.Path, doc.RepoID)
+		}
+	}
+	return idx.index.Batch(batch)
+}
+
+func repoIDQuery(repoIDs ...int64) query.Query {
+	repos := make([]query.Query, len(repoIDs))
+	for i, repoID := range repoIDs {
+		id := float64(repoID)
+		inclusive := true
+		q := bleve.NewNumericRangeInclusiveQuery(&id, &id, &inclusive, &inclusive)
+		q.SetField("repo_id")
+		repos[i] = q
+	}
+	return bleve.NewDisjunctionQuery(repos...)
+}
+
+func (idx *bleveCodeIndexer) DeleteRepo(repoID int64) error {
+	const batchSize = 1000
+	for {
+		req := bleve.NewSearchRequestOptions(repoIDQuery(repoID), batchSize, 0, false)
+		result, err := idx.index.Search(req)
+		if err != nil {
+			return errors.Wrap(err, "search")
+		} else if len(result.Hits) == 0 {
+			return nil
+		}
+
+		batch := idx.index.NewBatch()
+		for _, hit := range result.Hits {
+			batch.Delete(hit.ID)
+		}
+		if err = idx.index.Batch(batch); err != nil {
+			return errors.Wrap(err, "delete")
+		}
+	}
+}
+
+func (idx *bleveCodeIndexer) Search(opts CodeSearchOptions) ([]*CodeHit, int64, error) {
+	keyword := strings.TrimSpace(opts.Keyword)
+	if keyword == "" {
+		return []*CodeHit{}, 0, nil
+	}
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+
+	fields := make([]query.Query, 0, 2)
+	for _, field := range []string{"path", "content"} {
+		q := bleve.NewMatchQuery(keyword)
+		q.SetField(field)
+		q.SetOperator(query.MatchQueryOperatorAnd)
+		fields = append(fields, q)
+	}
+	must := []query.Query{bleve.NewDisjunctionQuery(fields...)}
+	if len(opts.RepoIDs) > 0 {
+		must = append(must, repoIDQuery(opts.RepoIDs...))
+	}
+
+	req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(must...), opts.PageSize, (opts.Page-1)*opts.PageSize, false)
+	req.Fields = []string{"repo_id", "path", "content"}
+	result, err := idx.index.Search(req)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "search")
+	}
+
+	terms := codeTerms(keyword)
+	hits := make([]*CodeHit, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		repoID, _ := hit.Fields["repo_id"].(float64)
+		path, _ := hit.Fields["path"].(string)
+		content, _ := hit.Fields["content"].(string)
+		hits = append(hits, &CodeHit{
+			RepoID: int64(repoID),
+			Path:   path,
+			Lines:  highlightCodeLines(content, terms, maxCodeLines),
+		})
+	}
+	return hits, int64(result.Total), nil
+}
+
+func (idx *bleveCodeIndexer) Close() error {
+	return idx.index.Close()
+}
+
+// codeTerms returns lowercased words of the keyword in the same way as they
+// are indexed.
+func codeTerms(keyword string) []string {
+	return codeTokenRegexp.FindAllString(strings.ToLower(keyword), -1)
+}
+
+// highlightCodeLines returns at most limit lines of the content that contain
+// any of the terms, with every occurrence of the terms highlighted.
+func highlightCodeLines(content string, terms []string, limit int) []*CodeLine {
+	lines := make([]*CodeLine, 0, limit)
+	for i, line := range strings.Split(content, "\n") {
+		if len(lines) >= limit {
+			break
+		}
+
+		highlighted, ok := highlightCodeLine(strings.TrimRight(line, "\r"), terms)
+		if !ok {
+			continue
+		}
+		lines = append(lines, &CodeLine{
+			Number:      i + 1,
+			Highlighted: highlighted,
+		})
+	}
+	return lines
+}
+
+// highlightCodeLine escapes the line and wraps occurrences of any of the terms
+// in <mark> tags. It returns false if the line contains none of the terms.
+func highlightCodeLine(line string, terms []string) (template.HTML, bool) {
+	lower := strings.ToLower(line)
+	// Offsets of the lowercased line are only usable when lowercasing does not
+	// change the length of the line.
+	if len(lower) != len(line) {
+		for _, term := range terms {
+			if strings.Contains(lower, term) {
+				return template.HTML(html.EscapeString(line)), true
+			}
+		}
+		return "", false
+	}
+
+	var buf strings.Builder
+	matched := false
+	start := 0
+	for start < len(line) {
+		pos, length := -1, 0
+		for _, term := range terms {
+			i := strings.Index(lower[start:], term)
+			if i < 0 {
+				continue
+			}
+			if pos < 0 || i < pos || (i == pos && len(term) > length) {
+				pos, length = i, len(term)
+			}
+		}
+		if pos < 0 {
+			break
+		}
+
+		matched = true
+		buf.WriteString(html.EscapeString(line[start : start+pos]))
+		buf.WriteString("<mark>")
+		buf.WriteString(html.EscapeString(line[start+pos : start+pos+length]))
+		buf.WriteString("</mark>")
+		start += pos + length
+	}
+	if !matched {
+		return "", false
+	}
+	buf.WriteString(html.EscapeString(line[start:]))
+	return template.HTML(buf.String()), true
+}
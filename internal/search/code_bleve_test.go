@@ -0,0 +1,84 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"html/template"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBleveCodeIndexer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repos.bleve")
+	indexer, created, err := NewBleveCodeIndexer(path)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	err = indexer.Index(
+		&CodeDocument{RepoID: 1, Path: "main.go", Content: "package main\n\nfunc main() {\n\tdb.Perms.Authorize()\n}\n"},
+		&CodeDocument{RepoID: 1, Path: "README.md", Content: "# Perms\n"},
+		&CodeDocument{RepoID: 2, Path: "perms/store.go", Content: "package perms\n\ntype Store struct{}\n"},
+	)
+	require.NoError(t, err)
+
+	type hit struct {
+		RepoID int64
+		Path   string
+	}
+	search := func(opts CodeSearchOptions) ([]hit, int64) {
+		t.Helper()
+		opts.PageSize = 10
+		results, total, err := indexer.Search(opts)
+		require.NoError(t, err)
+
+		hits := make([]hit, len(results))
+		for i, result := range results {
+			hits[i] = hit{RepoID: result.RepoID, Path: result.Path}
+		}
+		return hits, total
+	}
+
+	t.Run("words of identifiers", func(t *testing.T) {
+		hits, total := search(CodeSearchOptions{Keyword: "Authorize"})
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []hit{{RepoID: 1, Path: "main.go"}}, hits)
+	})
+
+	t.Run("content or path", func(t *testing.T) {
+		hits, total := search(CodeSearchOptions{Keyword: "perms"})
+		assert.Equal(t, int64(3), total)
+		assert.ElementsMatch(t, []hit{{RepoID: 1, Path: "main.go"}, {RepoID: 1, Path: "README.md"}, {RepoID: 2, Path: "perms/store.go"}}, hits)
+	})
+
+	t.Run("scoped to repositories", func(t *testing.T) {
+		hits, total := search(CodeSearchOptions{RepoIDs: []int64{2}, Keyword: "perms"})
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []hit{{RepoID: 2, Path: "perms/store.go"}}, hits)
+	})
+
+	t.Run("delete repository", func(t *testing.T) {
+		require.NoError(t, indexer.DeleteRepo(1))
+		hits, total := search(CodeSearchOptions{Keyword: "perms"})
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []hit{{RepoID: 2, Path: "perms/store.go"}}, hits)
+	})
+}
+
+func TestHighlightCodeLines(t *testing.T) {
+	content := "package main\n\nfunc main() {\r\n\tif a < b && db.Perms != nil {}\n}\n"
+	got := highlightCodeLines(content, codeTerms("db.Perms main"), 5)
+	want := []*CodeLine{
+		{Number: 1, Highlighted: template.HTML("package <mark>main</mark>")},
+		{Number: 3, Highlighted: template.HTML("func <mark>main</mark>() {")},
+		{Number: 4, Highlighted: template.HTML("\tif a &lt; b &amp;&amp; <mark>db</mark>.<mark>Perms</mark> != nil {}")},
+	}
+	assert.Equal(t, want, got)
+
+	got = highlightCodeLines(content, codeTerms("main"), 1)
+	assert.Len(t, got, 1)
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/standard"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/pkg/errors"
+)
+
+var _ IssueIndexer = (*bleveIssueIndexer)(nil)
+
+type bleveIssueIndexer struct {
+	index bleve.Index
+}
+
+// bleveIssue is the indexed representation of an IssueDocument.
+type bleveIssue struct {
+	RepoID   float64  `json:"repo_id"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Comments []string `json:"comments"`
+}
+
+func newBleveIssueMapping() mapping.IndexMapping {
+	text := bleve.NewTextFieldMapping()
+	text.Analyzer = standard.Name
+	text.Store = false
+	text.IncludeInAll = false
+
+	numeric := bleve.NewNumericFieldMapping()
+	numeric.Store = false
+	numeric.IncludeInAll = false
+
+	doc := bleve.NewDocumentStaticMapping()
+	doc.AddFieldMappingsAt("repo_id", numeric)
+	doc.AddFieldMappingsAt("title", text)
+	doc.AddFieldMappingsAt("content", text)
+	doc.AddFieldMappingsAt("comments", text)
+
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping = doc
+	m.DefaultAnalyzer = standard.Name
+	return m
+}
+
+// NewBleveIssueIndexer opens the bleve issue index at given path, or creates
+// one if it does not exist yet. The returned boolean indicates whether the
+// index is newly created and needs to be populated.
+func NewBleveIssueIndexer(path string) (_ IssueIndexer, created bool, _ error) {
+	index, created, err := openBleveIndex(path, newBleveIssueMapping())
+	if err != nil {
+		return nil, false, err
+	}
+	return &bleveIssueIndexer{index: index}, created, nil
+}
+
+// openBleveIndex opens the bleve index at given path, or creates one with
+// given mapping if it does not exist yet. The index can only be opened by one
+// process at a time, it gives up after a few seconds when the index is held by
+// another process.
+func openBleveIndex(path string, m mapping.IndexMapping) (_ bleve.Index, created bool, _ error) {
+	config := map[string]interface{}{
+		"bolt_timeout": "5s",
+	}
+	index, err := bleve.OpenUsing(path, config)
+	if err == nil {
+		return index, false, nil
+	} else if err != bleve.ErrorIndexPathDoesNotExist {
+		return nil, false, errors.Wrap(err, "open")
+	}
+
+	if err = os.MkdirAll(path, os.ModePerm); err != nil {
+		return nil, false, errors.Wrap(err, "create directory")
+	}
+	// bleve refuses to create an index in an existing directory.
+	if err = os.Remove(path); err != nil {
+		return nil, false, errors.Wrap(err, "remove directory")
+	}
+	index, err = bleve.NewUsing(path, m, bleve.Config.DefaultIndexType, bleve.Config.DefaultKVStore, config)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "create")
+	}
+	return index, true, nil
+}
+
+func (idx *bleveIssueIndexer) Index(docs ...*IssueDocument) error {
+	batch := idx.index.NewBatch()
+	for _, doc := range docs {
+		err := batch.Index(strconv.FormatInt(doc.ID, 10), &bleveIssue{
+			RepoID:   float64(doc.RepoID),
+			Title:    doc.Title,
+			Content:  doc.Content,
+			Comments: doc.Comments,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "index issue %d", doc.ID)
+		}
+	}
+	return idx.index.Batch(batch)
+}
+
+func (idx *bleveIssueIndexer) Delete(ids ...int64) error {
+	batch := idx.index.NewBatch()
+	for _, id := range ids {
+		batch.Delete(strconv.FormatInt(id, 10))
+	}
+	return idx.index.Batch(batch)
+}
+
+// matchAnyField returns a query that matches if any of the searchable fields
+// matches the query returned by newQuery.
+func matchAnyField(newQuery func(field string) query.FieldableQuery) query.Query {
+	queries := make([]query.Query, 0, 3)
+	for _, field := range []string{"title", "content", "comments"} {
+		q := newQuery(field)
+		q.SetField(field)
+		queries = append(queries, q)
+	}
+	return bleve.NewDisjunctionQuery(queries...)
+}
+
+func (idx *bleveIssueIndexer) Search(opts IssueSearchOptions) ([]int64, error) {
+	var must []query.Query
+	for _, term := range opts.Terms {
+		term := term
+		must = append(must, matchAnyField(func(string) query.FieldableQuery {
+			q := bleve.NewMatchQuery(term)
+			q.SetOperator(query.MatchQueryOperatorAnd)
+			return q
+		}))
+	}
+	for _, phrase := range opts.Phrases {
+		phrase := phrase
+		must = append(must, matchAnyField(func(string) query.FieldableQuery {
+			return bleve.NewMatchPhraseQuery(phrase)
+		}))
+	}
+	if len(must) == 0 {
+		return []int64{}, nil
+	}
+
+	if len(opts.RepoIDs) > 0 {
+		must = append(must, repoIDQuery(opts.RepoIDs...))
+	}
+
+	size := opts.MaxHits
+	if size <= 0 {
+		size = DefaultIssueSearchMaxHits
+	}
+	req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(must...), size, 0, false)
+	result, err := idx.index.Search(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "search")
+	}
+
+	ids := make([]int64, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		id, err := strconv.ParseInt(hit.ID, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse document ID %q", hit.ID)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (idx *bleveIssueIndexer) Close() error {
+	return idx.index.Close()
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBleveIssueIndexer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.bleve")
+	indexer, created, err := NewBleveIssueIndexer(path)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	err = indexer.Index(
+		&IssueDocument{ID: 1, RepoID: 1, Title: "Crash on start", Content: "The server panics when starting."},
+		&IssueDocument{ID: 2, RepoID: 1, Title: "Add dark theme", Comments: []string{"The server should remember the theme."}},
+		&IssueDocument{ID: 3, RepoID: 2, Title: "Server crash", Content: "Out of memory."},
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		opts IssueSearchOptions
+		want []int64
+	}{
+		{
+			name: "term in title",
+			opts: IssueSearchOptions{Terms: []string{"crash"}},
+			want: []int64{1, 3},
+		},
+		{
+			name: "term in comments",
+			opts: IssueSearchOptions{Terms: []string{"remember"}},
+			want: []int64{2},
+		},
+		{
+			name: "all terms must match",
+			opts: IssueSearchOptions{Terms: []string{"server", "memory"}},
+			want: []int64{3},
+		},
+		{
+			name: "phrase",
+			opts: IssueSearchOptions{Phrases: []string{"server panics"}},
+			want: []int64{1},
+		},
+		{
+			name: "scoped to repositories",
+			opts: IssueSearchOptions{RepoIDs: []int64{2}, Terms: []string{"crash"}},
+			want: []int64{3},
+		},
+		{
+			name: "no text",
+			opts: IssueSearchOptions{RepoIDs: []int64{1}},
+			want: []int64{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := indexer.Search(test.opts)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, test.want, got)
+		})
+	}
+
+	got, err := indexer.Search(IssueSearchOptions{Terms: []string{"crash"}, MaxHits: 1})
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	// Deleted and re-opened
+	require.NoError(t, indexer.Delete(3))
+	require.NoError(t, indexer.Close())
+
+	indexer, created, err = NewBleveIssueIndexer(path)
+	require.NoError(t, err)
+	assert.False(t, created)
+	defer func() { _ = indexer.Close() }()
+
+	got, err = indexer.Search(IssueSearchOptions{Terms: []string{"crash"}})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, got)
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IssueQuery is a parsed issue search query, e.g.
+// `is:open label:bug author:alice "exact phrase" keyword`.
+type IssueQuery struct {
+	// Free text, matched against titles, contents and comments.
+	Terms   []string
+	Phrases []string
+
+	// Qualifiers, empty value means not specified.
+	State     string // "open" or "closed"
+	Type      string // "issue" or "pr"
+	Labels    []string
+	Author    string
+	Assignee  string
+	Mentions  string
+	Milestone string
+}
+
+// HasText returns true if the query contains any free text.
+func (q *IssueQuery) HasText() bool {
+	return len(q.Terms) > 0 || len(q.Phrases) > 0
+}
+
+// splitQuery splits the query into tokens by whitespace, a quoted part is kept
+// in the same token with quotes removed. The returned boolean of each token
+// indicates whether the token is fully quoted.
+func splitQuery(s string) (tokens []string, quoted []bool) {
+	var (
+		buf      strings.Builder
+		inQuotes bool
+		started  bool
+		// Whether the current token consists of only a quoted part.
+		allQuoted bool
+	)
+	flush := func() {
+		if started {
+			tokens = append(tokens, buf.String())
+			quoted = append(quoted, allQuoted)
+		}
+		buf.Reset()
+		started = false
+		allQuoted = false
+	}
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			if !inQuotes && !started {
+				allQuoted = true
+			}
+			inQuotes = !inQuotes
+			started = true
+		case unicode.IsSpace(r) && !inQuotes:
+			flush()
+		default:
+			if !inQuotes {
+				allQuoted = false
+			}
+			buf.WriteRune(r)
+			started = true
+		}
+	}
+	flush()
+	return tokens, quoted
+}
+
+// ParseIssueQuery parses the issue search query. Unrecognized qualifiers are
+// treated as free text.
+func ParseIssueQuery(s string) *IssueQuery {
+	q := &IssueQuery{}
+	tokens, quoted := splitQuery(s)
+	for i, token := range tokens {
+		if token == "" {
+			continue
+		} else if quoted[i] {
+			q.Phrases = append(q.Phrases, token)
+			continue
+		}
+
+		fields := strings.SplitN(token, ":", 2)
+		if len(fields) == 2 && fields[1] != "" {
+			value := fields[1]
+			switch strings.ToLower(fields[0]) {
+			case "is":
+				switch strings.ToLower(value) {
+				case "open", "closed":
+					q.State = strings.ToLower(value)
+					continue
+				case "issue", "pr":
+					q.Type = strings.ToLower(value)
+					continue
+				}
+			case "label":
+				q.Labels = append(q.Labels, value)
+				continue
+			case "author":
+				q.Author = value
+				continue
+			case "assignee":
+				q.Assignee = value
+				continue
+			case "mentions":
+				q.Mentions = value
+				continue
+			case "milestone":
+				q.Milestone = value
+				continue
+			}
+		}
+		q.Terms = append(q.Terms, token)
+	}
+	return q
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIssueQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  *IssueQuery
+	}{
+		{
+			query: "",
+			want:  &IssueQuery{},
+		},
+		{
+			query: "crash on start",
+			want: &IssueQuery{
+				Terms: []string{"crash", "on", "start"},
+			},
+		},
+		{
+			query: `is:open label:bug author:alice "exact phrase"`,
+			want: &IssueQuery{
+				Phrases: []string{"exact phrase"},
+				State:   "open",
+				Labels:  []string{"bug"},
+				Author:  "alice",
+			},
+		},
+		{
+			query: `is:pr is:closed label:"help wanted" label:ui assignee:bob mentions:carol milestone:"v1.0" panic`,
+			want: &IssueQuery{
+				Terms:     []string{"panic"},
+				State:     "closed",
+				Type:      "pr",
+				Labels:    []string{"help wanted", "ui"},
+				Assignee:  "bob",
+				Mentions:  "carol",
+				Milestone: "v1.0",
+			},
+		},
+		{
+			query: "is:unknown foo:bar label: http://example.com",
+			want: &IssueQuery{
+				Terms: []string{"is:unknown", "foo:bar", "label:", "http://example.com"},
+			},
+		},
+		{
+			query: `  "unterminated phrase  `,
+			want: &IssueQuery{
+				Phrases: []string{"unterminated phrase  "},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			assert.Equal(t, test.want, ParseIssueQuery(test.query))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package search provides search indexes for full-text search.
+package search
+
+import "html/template"
+
+// IssueDocument is the searchable content of an issue or a pull request.
+type IssueDocument struct {
+	ID       int64
+	RepoID   int64
+	Title    string
+	Content  string
+	Comments []string
+}
+
+// IssueSearchOptions contains options for searching issues.
+type IssueSearchOptions struct {
+	// The list of repositories to search in, empty means all repositories.
+	RepoIDs []int64
+	// Every term and phrase must be matched in the title, content or comments.
+	Terms   []string
+	Phrases []string
+	// The maximum number of matched issues to return, the most relevant ones are
+	// returned first. Zero means DefaultIssueSearchMaxHits.
+	MaxHits int
+}
+
+// DefaultIssueSearchMaxHits is the default maximum number of matched issues to
+// return for a search.
+const DefaultIssueSearchMaxHits = 1000
+
+// IssueIndexer is a search index of issues and pull requests.
+type IssueIndexer interface {
+	// Index adds or replaces given documents in the index.
+	Index(docs ...*IssueDocument) error
+	// Delete removes documents with given issue IDs from the index.
+	Delete(ids ...int64) error
+	// Search returns IDs of matched issues, ordered by relevance.
+	Search(opts IssueSearchOptions) ([]int64, error)
+	// Close releases the resources held by the index.
+	Close() error
+}
+
+// CodeDocument is the searchable content of a file in a repository.
+type CodeDocument struct {
+	RepoID  int64
+	Path    string
+	Content string
+}
+
+// CodeSearchOptions contains options for searching code.
+type CodeSearchOptions struct {
+	// The list of repositories to search in, empty means all repositories.
+	RepoIDs []int64
+	// Every word of the keyword must be matched in the path or content.
+	Keyword  string
+	Page     int
+	PageSize int
+}
+
+// CodeLine is a line of a file that matches the search keyword.
+type CodeLine struct {
+	// The 1-based line number.
+	Number int
+	// The escaped content of the line with matches wrapped in <mark> tags.
+	Highlighted template.HTML
+}
+
+// CodeHit is a file that matches the search keyword.
+type CodeHit struct {
+	RepoID int64
+	Path   string
+	Lines  []*CodeLine
+}
+
+// CodeIndexer is a search index of files in repositories.
+type CodeIndexer interface {
+	// Index adds or replaces given documents in the index.
+	Index(docs ...*CodeDocument) error
+	// DeleteRepo removes all documents of given repository from the index.
+	DeleteRepo(repoID int64) error
+	// Search returns matched files in given page and the total number of
+	// matched files.
+	Search(opts CodeSearchOptions) (hits []*CodeHit, total int64, _ error)
+	// Close releases the resources held by the index.
+	Close() error
+}
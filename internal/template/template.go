@@ -110,6 +110,7 @@ func FuncMap() []template.FuncMap {
 			"EllipsisString":        strutil.Ellipsis,
 			"DiffFileTypeToStr":     DiffFileTypeToStr,
 			"DiffLineTypeToStr":     DiffLineTypeToStr,
+			"Dict":                  Dict,
 			"Sha1":                  Sha1,
 			"ShortSHA1":             tool.ShortSHA1,
 			"ActionContent2Commits": ActionContent2Commits,
@@ -283,3 +284,21 @@ func DiffLineTypeToStr(typ git.DiffLineType) string {
 	}
 	return "same"
 }
+
+// Dict returns a map of given key-value pairs, which is useful to pass multiple
+// values to a sub-template.
+func Dict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("odd number of arguments: %d", len(pairs))
+	}
+
+	dict := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("key at %d is not a string: %v", i, pairs[i])
+		}
+		dict[key] = pairs[i+1]
+	}
+	return dict, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webauthn
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// NOTE: CBOR is decoded by hand rather than with a general-purpose library,
+// because WebAuthn only needs a small subset of it, and existing CBOR and
+// WebAuthn libraries require newer Go versions than this module supports. The
+// decoder and its callers are fuzzed (see fuzz_test.go) to guard against panics
+// and excessive allocations on untrusted input.
+
+// maxCBORDepth is the maximum nesting level of arrays and maps, authenticator
+// data never goes deeper than a few levels.
+const maxCBORDepth = 16
+
+// decodeCBOR decodes the first CBOR data item of the data, and returns the rest
+// of the data, see https://datatracker.ietf.org/doc/html/rfc8949. Only the
+// subset used by WebAuthn is supported: integers are decoded as int64, byte
+// strings as []byte, text strings as string, arrays as []interface{} and maps
+// as map[interface{}]interface{}. Tags are ignored, and indefinite-length items
+// are rejected.
+func decodeCBOR(data []byte) (v interface{}, rest []byte, err error) {
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (interface{}, []byte, error) {
+	if depth > maxCBORDepth {
+		return nil, nil, errors.New("cbor: exceeded max nesting depth")
+	} else if len(data) == 0 {
+		return nil, nil, errors.New("cbor: unexpected end of data")
+	}
+
+	major := data[0] >> 5
+	info := data[0] & 0x1f
+	data = data[1:]
+
+	// Simple values and floats carry no length argument.
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, data, nil
+		case 21:
+			return true, data, nil
+		case 22, 23:
+			return nil, data, nil
+		case 25, 26, 27:
+			size := 1 << (info - 24)
+			if len(data) < size {
+				return nil, nil, errors.New("cbor: unexpected end of data")
+			}
+			var f float64
+			switch size {
+			case 2:
+				f = float16To64(binary.BigEndian.Uint16(data))
+			case 4:
+				f = float64(math.Float32frombits(binary.BigEndian.Uint32(data)))
+			case 8:
+				f = math.Float64frombits(binary.BigEndian.Uint64(data))
+			}
+			return f, data[size:], nil
+		}
+		return nil, nil, errors.Errorf("cbor: unsupported simple value %d", info)
+	}
+
+	var arg uint64
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(data) < size {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		switch size {
+		case 1:
+			arg = uint64(data[0])
+		case 2:
+			arg = uint64(binary.BigEndian.Uint16(data))
+		case 4:
+			arg = uint64(binary.BigEndian.Uint32(data))
+		case 8:
+			arg = binary.BigEndian.Uint64(data)
+		}
+		data = data[size:]
+	case info == 31:
+		return nil, nil, errors.New("cbor: indefinite-length items are not supported")
+	default:
+		return nil, nil, errors.Errorf("cbor: invalid additional information %d", info)
+	}
+
+	switch major {
+	case 0:
+		if arg > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: integer overflow")
+		}
+		return int64(arg), data, nil
+
+	case 1:
+		if arg > math.MaxInt64 {
+			return nil, nil, errors.New("cbor: integer overflow")
+		}
+		return -1 - int64(arg), data, nil
+
+	case 2, 3:
+		if arg > uint64(len(data)) {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		b := data[:arg]
+		if major == 3 {
+			return string(b), data[arg:], nil
+		}
+		return append([]byte(nil), b...), data[arg:], nil
+
+	case 4:
+		// Each item takes at least one byte.
+		if arg > uint64(len(data)) {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		items := make([]interface{}, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			var item interface{}
+			var err error
+			item, data, err = decodeCBORItem(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, data, nil
+
+	case 5:
+		// Each pair takes at least two bytes.
+		if arg > uint64(len(data))/2 {
+			return nil, nil, errors.New("cbor: unexpected end of data")
+		}
+		m := make(map[interface{}]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			var key, value interface{}
+			var err error
+			key, data, err = decodeCBORItem(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, errors.Errorf("cbor: unsupported map key type %T", key)
+			}
+			value, data, err = decodeCBORItem(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			m[key] = value
+		}
+		return m, data, nil
+
+	case 6:
+		return decodeCBORItem(data, depth+1)
+	}
+	panic("unreachable")
+}
+
+// float16To64 converts an IEEE 754 half-precision float to float64.
+func float16To64(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(h>>10) & 0x1f
+	frac := float64(h & 0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(frac, -24)
+	case 0x1f:
+		if frac == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	}
+	return sign * math.Ldexp(frac+1024, exp-25)
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webauthn
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+	// Test vectors from https://datatracker.ietf.org/doc/html/rfc8949#appendix-A.
+	tests := []struct {
+		hex     string
+		want    interface{}
+		wantErr bool
+	}{
+		{hex: "00", want: int64(0)},
+		{hex: "17", want: int64(23)},
+		{hex: "1818", want: int64(24)},
+		{hex: "1903e8", want: int64(1000)},
+		{hex: "1b000000e8d4a51000", want: int64(1000000000000)},
+		{hex: "20", want: int64(-1)},
+		{hex: "3903e7", want: int64(-1000)},
+		{hex: "f90001", want: 5.960464477539063e-08},
+		{hex: "f93c00", want: 1.0},
+		{hex: "fa47c35000", want: 100000.0},
+		{hex: "f4", want: false},
+		{hex: "f5", want: true},
+		{hex: "f6", want: nil},
+		{hex: "4401020304", want: []byte{1, 2, 3, 4}},
+		{hex: "6449455446", want: "IETF"},
+		{hex: "83010203", want: []interface{}{int64(1), int64(2), int64(3)}},
+		{hex: "a201020304", want: map[interface{}]interface{}{int64(1): int64(2), int64(3): int64(4)}},
+		{hex: "a26161016162820203", want: map[interface{}]interface{}{"a": int64(1), "b": []interface{}{int64(2), int64(3)}}},
+		{hex: "c11a514b67b0", want: int64(1363896240)},
+
+		{hex: "", wantErr: true},
+		{hex: "1b", wantErr: true},
+		{hex: "44010203", wantErr: true},
+		{hex: "5f42010243030405ff", wantErr: true},   // Indefinite-length
+		{hex: "1bffffffffffffffff", wantErr: true},   // Overflow
+		{hex: "a1f401", wantErr: true},               // Unsupported key type
+		{hex: "9bffffffffffffffff00", wantErr: true}, // Too many items
+	}
+	for _, test := range tests {
+		t.Run(test.hex, func(t *testing.T) {
+			data, err := hex.DecodeString(test.hex)
+			assert.NoError(t, err)
+
+			got, rest, err := decodeCBOR(data)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Empty(t, rest)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	t.Run("rest", func(t *testing.T) {
+		got, rest, err := decodeCBOR([]byte{0x01, 0x02})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), got)
+		assert.Equal(t, []byte{0x02}, rest)
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		data := make([]byte, maxCBORDepth+2)
+		for i := range data {
+			data[i] = 0x81 // Array of one item
+		}
+		_, _, err := decodeCBOR(data)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+func FuzzDecodeCBOR(f *testing.F) {
+	for _, s := range []string{
+		"00", "1b000000e8d4a51000", "3903e7", "f93c00", "fb3ff199999999999a",
+		"4401020304", "6449455446", "83010203", "a26161016162820203", "c11a514b67b0",
+		"9bffffffffffffffff00", "5f42010243030405ff",
+	} {
+		data, err := hex.DecodeString(s)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, rest, err := decodeCBOR(data)
+		if err != nil {
+			return
+		}
+		if len(rest) >= len(data) || !bytes.HasSuffix(data, rest) {
+			t.Fatalf("rest %x is not a proper suffix of data %x", rest, data)
+		}
+	})
+}
+
+func FuzzParsePublicKey(f *testing.F) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(encodeCBOR(map[interface{}]interface{}{
+		coseKeyType:   coseKeyTypeEC2,
+		coseAlgorithm: algES256,
+		-1:            coseCurveP256,
+		-2:            key.X.FillBytes(make([]byte, 32)),
+		-3:            key.Y.FillBytes(make([]byte, 32)),
+	}))
+	f.Add(encodeCBOR(map[interface{}]interface{}{
+		coseKeyType:   coseKeyTypeOKP,
+		coseAlgorithm: algEdDSA,
+		-1:            coseCurveEd25519,
+		-2:            make([]byte, 32),
+	}))
+	f.Add(encodeCBOR(map[interface{}]interface{}{
+		coseKeyType:   coseKeyTypeRSA,
+		coseAlgorithm: algRS256,
+		-1:            []byte{0xc3, 0x5f},
+		-2:            []byte{1, 0, 1},
+	}))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		pub, err := parsePublicKey(data)
+		if err != nil {
+			return
+		}
+		_ = pub.verify([]byte("message"), data)
+	})
+}
+
+func FuzzParseAuthenticatorData(f *testing.F) {
+	a := &testAuthenticator{
+		credentialID: []byte("credential"),
+		publicKey:    encodeCBOR(map[interface{}]interface{}{coseKeyType: coseKeyTypeOKP}),
+	}
+	f.Add(a.authenticatorData("localhost", flagUserPresent, false))
+	f.Add(a.authenticatorData("localhost", flagUserPresent, true))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = parseAuthenticatorData(data)
+	})
+}
@@ -0,0 +1,331 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package webauthn implements the relying party side of Web Authentication for
+// security keys as the second factor, see https://www.w3.org/TR/webauthn-2/.
+//
+// Attestation statements are not verified because credentials are only used as
+// the second factor and attestation is never requested.
+package webauthn
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Timeout is the time in milliseconds that the browser waits for the user to
+// complete a ceremony.
+const Timeout = 60000
+
+// Credential is a public key credential created by an authenticator.
+type Credential struct {
+	// The credential ID.
+	ID []byte
+	// The credential public key as COSE_Key encoded in CBOR.
+	PublicKey []byte
+	// The signature counter of the authenticator.
+	SignCount uint32
+}
+
+// RelyingParty is the website that users register credentials with.
+type RelyingParty struct {
+	// The RP ID, which is the effective domain of the site, e.g. "gogs.example.com".
+	ID string
+	// The human-palatable name of the site.
+	Name string
+	// The origin that browsers report in client data, e.g.
+	// "https://gogs.example.com".
+	Origin string
+}
+
+// NewChallenge returns a new random challenge encoded in base64url.
+func NewChallenge() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "read random bytes")
+	}
+	return encode(b), nil
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeString decodes base64url encoded data with or without padding, which is
+// how browsers encode binary data of credentials.
+func DecodeString(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// User is the user account that credentials are registered for.
+type User struct {
+	// The user handle, which must not contain personally identifying information.
+	ID          []byte
+	Name        string
+	DisplayName string
+}
+
+type credentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+func credentialDescriptors(ids [][]byte) []credentialDescriptor {
+	descs := make([]credentialDescriptor, len(ids))
+	for i := range ids {
+		descs[i] = credentialDescriptor{Type: "public-key", ID: encode(ids[i])}
+	}
+	return descs
+}
+
+type credentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// CreationOptions is the options for creating a credential, binary values are
+// encoded in base64url. See
+// https://www.w3.org/TR/webauthn-2/#dictdef-publickeycredentialcreationoptions.
+type CreationOptions struct {
+	Challenge string `json:"challenge"`
+	RP        struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	PubKeyCredParams       []credentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int                    `json:"timeout"`
+	ExcludeCredentials     []credentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection struct {
+		UserVerification string `json:"userVerification"`
+	} `json:"authenticatorSelection"`
+	Attestation string `json:"attestation"`
+}
+
+// CreationOptions returns the options for the user to create a new credential.
+// The "exclude" is the list of IDs of credentials that the user already has.
+func (rp *RelyingParty) CreationOptions(user User, challenge string, exclude [][]byte) *CreationOptions {
+	opts := &CreationOptions{
+		Challenge:          challenge,
+		Timeout:            Timeout,
+		ExcludeCredentials: credentialDescriptors(exclude),
+		Attestation:        "none",
+	}
+	opts.RP.ID = rp.ID
+	opts.RP.Name = rp.Name
+	opts.User.ID = encode(user.ID)
+	opts.User.Name = user.Name
+	opts.User.DisplayName = user.DisplayName
+	for _, alg := range supportedAlgorithms {
+		opts.PubKeyCredParams = append(opts.PubKeyCredParams, credentialParameter{Type: "public-key", Alg: alg})
+	}
+	opts.AuthenticatorSelection.UserVerification = "discouraged"
+	return opts
+}
+
+// RequestOptions is the options for getting an assertion, binary values are
+// encoded in base64url. See
+// https://www.w3.org/TR/webauthn-2/#dictdef-publickeycredentialrequestoptions.
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	RPID             string                 `json:"rpId"`
+	Timeout          int                    `json:"timeout"`
+	AllowCredentials []credentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// RequestOptions returns the options for the user to authenticate with one of
+// the allowed credentials.
+func (rp *RelyingParty) RequestOptions(challenge string, allow [][]byte) *RequestOptions {
+	return &RequestOptions{
+		Challenge:        challenge,
+		RPID:             rp.ID,
+		Timeout:          Timeout,
+		AllowCredentials: credentialDescriptors(allow),
+		UserVerification: "discouraged",
+	}
+}
+
+// verifyClientData verifies the client data collected by the browser.
+func (rp *RelyingParty) verifyClientData(clientDataJSON []byte, typ, challenge string) error {
+	var clientData struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+		Origin    string `json:"origin"`
+	}
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return errors.Wrap(err, "parse client data")
+	}
+
+	if clientData.Type != typ {
+		return errors.Errorf("unexpected client data type %q", clientData.Type)
+	} else if challenge == "" || subtle.ConstantTimeCompare([]byte(clientData.Challenge), []byte(challenge)) != 1 {
+		return errors.New("challenge mismatch")
+	} else if clientData.Origin != rp.Origin {
+		return errors.Errorf("unexpected origin %q", clientData.Origin)
+	}
+	return nil
+}
+
+// Flags of authenticator data.
+const (
+	flagUserPresent            = 0x01
+	flagAttestedCredentialData = 0x40
+	flagExtensionData          = 0x80
+)
+
+// authenticatorData is the parsed authenticator data, see
+// https://www.w3.org/TR/webauthn-2/#sctn-authenticator-data.
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	publicKey    []byte
+}
+
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, errors.New("authenticator data is too short")
+	}
+
+	ad := &authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rest := data[37:]
+	if ad.flags&flagAttestedCredentialData != 0 {
+		// AAGUID (16 bytes) and the length of the credential ID (2 bytes).
+		if len(rest) < 18 {
+			return nil, errors.New("attested credential data is too short")
+		}
+		n := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if n == 0 || len(rest) < n {
+			return nil, errors.New("invalid credential ID length")
+		}
+		ad.credentialID = rest[:n]
+		rest = rest[n:]
+
+		_, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode credential public key")
+		}
+		ad.publicKey = rest[:len(rest)-len(after)]
+		rest = after
+	}
+	if ad.flags&flagExtensionData != 0 {
+		_, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode extensions")
+		}
+		rest = after
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing data after authenticator data")
+	}
+	return ad, nil
+}
+
+// verify verifies the RP ID hash and the user presence.
+func (ad *authenticatorData) verify(rpID string) error {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	if subtle.ConstantTimeCompare(ad.rpIDHash, rpIDHash[:]) != 1 {
+		return errors.New("RP ID hash mismatch")
+	} else if ad.flags&flagUserPresent == 0 {
+		return errors.New("user is not present")
+	}
+	return nil
+}
+
+// VerifyRegistration verifies the response of creating a credential, and
+// returns the new credential. See
+// https://www.w3.org/TR/webauthn-2/#sctn-registering-a-new-credential.
+func (rp *RelyingParty) VerifyRegistration(challenge string, clientDataJSON, attestationObject []byte) (*Credential, error) {
+	err := rp.verifyClientData(clientDataJSON, "webauthn.create", challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	v, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode attestation object")
+	}
+	obj, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("attestation object is not a map")
+	}
+	rawAuthData, ok := obj["authData"].([]byte)
+	if !ok {
+		return nil, errors.New("missing authenticator data")
+	}
+
+	ad, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	} else if err = ad.verify(rp.ID); err != nil {
+		return nil, err
+	} else if ad.credentialID == nil {
+		return nil, errors.New("missing attested credential data")
+	}
+
+	if _, err = parsePublicKey(ad.publicKey); err != nil {
+		return nil, errors.Wrap(err, "parse public key")
+	}
+	return &Credential{
+		ID:        append([]byte(nil), ad.credentialID...),
+		PublicKey: append([]byte(nil), ad.publicKey...),
+		SignCount: ad.signCount,
+	}, nil
+}
+
+// ErrSignCountRegressed is returned when the signature counter of the
+// authenticator did not increase, which indicates the authenticator may have
+// been cloned.
+var ErrSignCountRegressed = errors.New("signature counter did not increase")
+
+// VerifyAssertion verifies the response of getting an assertion with the
+// credential, and returns the new signature counter of the authenticator. See
+// https://www.w3.org/TR/webauthn-2/#sctn-verifying-assertion.
+func (rp *RelyingParty) VerifyAssertion(challenge string, cred *Credential, clientDataJSON, rawAuthData, sig []byte) (uint32, error) {
+	err := rp.verifyClientData(clientDataJSON, "webauthn.get", challenge)
+	if err != nil {
+		return 0, err
+	}
+
+	ad, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return 0, err
+	} else if err = ad.verify(rp.ID); err != nil {
+		return 0, err
+	}
+
+	key, err := parsePublicKey(cred.PublicKey)
+	if err != nil {
+		return 0, errors.Wrap(err, "parse public key")
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	message := append(append([]byte(nil), rawAuthData...), clientDataHash[:]...)
+	if !key.verify(message, sig) {
+		return 0, errors.New("invalid signature")
+	}
+
+	// Authenticators without a counter always report zero.
+	if (ad.signCount != 0 || cred.SignCount != 0) && ad.signCount <= cred.SignCount {
+		return 0, ErrSignCountRegressed
+	}
+	return ad.signCount, nil
+}
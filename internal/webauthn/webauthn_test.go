@@ -0,0 +1,224 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeCBOR encodes the subset of CBOR used in tests.
+func encodeCBOR(v interface{}) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n < 1<<8:
+			return []byte{major<<5 | 24, byte(n)}
+		case n < 1<<16:
+			b := []byte{major<<5 | 25, 0, 0}
+			binary.BigEndian.PutUint16(b[1:], uint16(n))
+			return b
+		}
+		b := []byte{major<<5 | 26, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(b[1:], uint32(n))
+		return b
+	}
+
+	switch v := v.(type) {
+	case int:
+		if v < 0 {
+			return head(1, uint64(-1-v))
+		}
+		return head(0, uint64(v))
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case map[interface{}]interface{}:
+		// Sort keys to have deterministic output.
+		keys := make([][]byte, 0, len(v))
+		encoded := make(map[string][]byte, len(v))
+		for key, value := range v {
+			k := encodeCBOR(key)
+			keys = append(keys, k)
+			encoded[string(k)] = encodeCBOR(value)
+		}
+		sort.Slice(keys, func(i, j int) bool { return string(keys[i]) < string(keys[j]) })
+
+		b := head(5, uint64(len(v)))
+		for _, k := range keys {
+			b = append(b, k...)
+			b = append(b, encoded[string(k)]...)
+		}
+		return b
+	}
+	panic("unsupported type")
+}
+
+type testAuthenticator struct {
+	credentialID []byte
+	publicKey    []byte
+	sign         func(message []byte) []byte
+	signCount    uint32
+}
+
+func newES256Authenticator(t *testing.T) *testAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pad := func(b []byte) []byte {
+		return append(make([]byte, 32-len(b)), b...)
+	}
+	return &testAuthenticator{
+		credentialID: []byte("es256-credential"),
+		publicKey: encodeCBOR(map[interface{}]interface{}{
+			coseKeyType:   coseKeyTypeEC2,
+			coseAlgorithm: algES256,
+			-1:            coseCurveP256,
+			-2:            pad(key.X.Bytes()),
+			-3:            pad(key.Y.Bytes()),
+		}),
+		sign: func(message []byte) []byte {
+			digest := sha256.Sum256(message)
+			sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+			require.NoError(t, err)
+			return sig
+		},
+	}
+}
+
+func newEdDSAAuthenticator(t *testing.T) *testAuthenticator {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return &testAuthenticator{
+		credentialID: []byte("eddsa-credential"),
+		publicKey: encodeCBOR(map[interface{}]interface{}{
+			coseKeyType:   coseKeyTypeOKP,
+			coseAlgorithm: algEdDSA,
+			-1:            coseCurveEd25519,
+			-2:            []byte(pub),
+		}),
+		sign: func(message []byte) []byte {
+			return ed25519.Sign(priv, message)
+		},
+	}
+}
+
+func (a *testAuthenticator) authenticatorData(rpID string, flags byte, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append([]byte(nil), rpIDHash[:]...)
+	if attested {
+		flags |= flagAttestedCredentialData
+	}
+	data = append(data, flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[33:], a.signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...) // AAGUID
+		data = append(data, byte(len(a.credentialID)>>8), byte(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.publicKey...)
+	}
+	return data
+}
+
+func clientDataJSON(typ, challenge, origin string) []byte {
+	b, _ := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    origin,
+	})
+	return b
+}
+
+func TestRelyingParty(t *testing.T) {
+	rp := &RelyingParty{
+		ID:     "gogs.example.com",
+		Name:   "Gogs",
+		Origin: "https://gogs.example.com",
+	}
+
+	for _, newAuthenticator := range []func(*testing.T) *testAuthenticator{
+		newES256Authenticator,
+		newEdDSAAuthenticator,
+	} {
+		a := newAuthenticator(t)
+		t.Run(string(a.credentialID), func(t *testing.T) {
+			challenge, err := NewChallenge()
+			require.NoError(t, err)
+
+			attestationObject := encodeCBOR(map[interface{}]interface{}{
+				"fmt":      "none",
+				"attStmt":  map[interface{}]interface{}{},
+				"authData": a.authenticatorData(rp.ID, flagUserPresent, true),
+			})
+
+			// Registration
+			_, err = rp.VerifyRegistration(challenge, clientDataJSON("webauthn.create", "bad-challenge", rp.Origin), attestationObject)
+			assert.Error(t, err)
+			_, err = rp.VerifyRegistration(challenge, clientDataJSON("webauthn.create", challenge, "https://evil.example.com"), attestationObject)
+			assert.Error(t, err)
+			_, err = rp.VerifyRegistration(challenge, clientDataJSON("webauthn.get", challenge, rp.Origin), attestationObject)
+			assert.Error(t, err)
+
+			cred, err := rp.VerifyRegistration(challenge, clientDataJSON("webauthn.create", challenge, rp.Origin), attestationObject)
+			require.NoError(t, err)
+			assert.Equal(t, a.credentialID, cred.ID)
+			assert.Equal(t, a.publicKey, cred.PublicKey)
+			assert.Equal(t, uint32(0), cred.SignCount)
+
+			// Assertion
+			verify := func(challenge string, clientData []byte, authData []byte) (uint32, error) {
+				clientDataHash := sha256.Sum256(clientData)
+				sig := a.sign(append(append([]byte(nil), authData...), clientDataHash[:]...))
+				return rp.VerifyAssertion(challenge, cred, clientData, authData, sig)
+			}
+
+			a.signCount = 1
+			signCount, err := verify(challenge, clientDataJSON("webauthn.get", challenge, rp.Origin), a.authenticatorData(rp.ID, flagUserPresent, false))
+			require.NoError(t, err)
+			require.Equal(t, uint32(1), signCount)
+			cred.SignCount = signCount
+
+			_, err = verify(challenge, clientDataJSON("webauthn.get", challenge, rp.Origin), a.authenticatorData(rp.ID, flagUserPresent, false))
+			require.Equal(t, ErrSignCountRegressed, err, "replayed counter")
+
+			a.signCount = 2
+			_, err = verify(challenge, clientDataJSON("webauthn.get", challenge, rp.Origin), a.authenticatorData("evil.example.com", flagUserPresent, false))
+			require.Error(t, err, "RP ID mismatch")
+			_, err = verify(challenge, clientDataJSON("webauthn.get", challenge, rp.Origin), a.authenticatorData(rp.ID, 0, false))
+			require.Error(t, err, "user not present")
+			_, err = verify(challenge, clientDataJSON("webauthn.get", "bad-challenge", rp.Origin), a.authenticatorData(rp.ID, flagUserPresent, false))
+			require.Error(t, err, "challenge mismatch")
+
+			authData := a.authenticatorData(rp.ID, flagUserPresent, false)
+			clientData := clientDataJSON("webauthn.get", challenge, rp.Origin)
+			clientDataHash := sha256.Sum256(clientData)
+			sig := a.sign(append(append([]byte(nil), authData...), clientDataHash[:]...))
+			authData[len(authData)-1]++ // Tamper the counter after signed
+			_, err = rp.VerifyAssertion(challenge, cred, clientData, authData, sig)
+			require.Error(t, err, "invalid signature")
+		})
+	}
+}
+
+func TestDecodeString(t *testing.T) {
+	for _, s := range []string{"-_8", "-_8=", "-_8=="} {
+		got, err := DecodeString(s)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0xfb, 0xff}, got)
+	}
+}
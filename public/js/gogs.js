@@ -354,7 +354,7 @@ function initRepository() {
   // Branches
   if ($(".repository.settings.branches").length > 0) {
     initFilterSearchDropdown(".protected-branches .dropdown");
-    $(".enable-protection, .enable-whitelist").change(function() {
+    $(".enable-protection, .enable-whitelist, .enable-status-check").change(function() {
       if (this.checked) {
         $($(this).data("target")).removeClass("disabled");
       } else {
@@ -601,6 +601,31 @@ function initRepository() {
         }
       })
       .trigger("hashchange");
+
+    // Code comments of pull request
+    $(".add-code-comment").click(function(e) {
+      e.preventDefault();
+      var $row = $(this).closest("tr");
+      if ($row.next().is(".code-comments")) {
+        $row = $row.next();
+      }
+      if ($row.next().is(".code-comment-form")) {
+        $row.next().find("textarea").focus();
+        return;
+      }
+
+      var $form = $("#code-comment-form-template")
+        .children()
+        .clone();
+      $form.find("input[name=tree_path]").val($(this).data("tree-path"));
+      $form.find("input[name=line]").val($(this).data("line"));
+      $form.find(".cancel").click(function(e) {
+        e.preventDefault();
+        $form.remove();
+      });
+      $row.after($form);
+      $form.find("textarea").focus();
+    });
   }
 
   // Quick start and repository home
@@ -633,6 +658,11 @@ function initRepository() {
       } else {
         $(".commit.description.field").hide();
       }
+      if ($(this).val() === "squash") {
+        $(".squash.commit.message.field").show();
+      } else {
+        $(".squash.commit.message.field").hide();
+      }
     });
   }
 }
@@ -1070,6 +1100,7 @@ function initAdmin() {
       $(".smtp").hide();
       $(".pam").hide();
       $(".github").hide();
+      $(".oidc").hide();
       $(".has-tls").hide();
 
       var authType = $(this).val();
@@ -1091,6 +1122,10 @@ function initAdmin() {
           $(".github").show();
           $(".has-tls").show();
           break;
+        case "7": // OpenID Connect
+          $(".oidc").show();
+          $(".has-tls").show();
+          break;
       }
 
       if (authType == "2" || authType == "5") {
@@ -1133,7 +1168,7 @@ function initAdmin() {
           break;
       }
     });
-    $("#delete-selection").click(function() {
+    $("#delete-selection, #redeliver-selection").click(function() {
       var $this = $(this);
       $this.addClass("loading disabled");
       var ids = [];
@@ -1360,6 +1395,106 @@ function initUserSettings() {
   }
 }
 
+function base64URLToBuffer(s) {
+  var str = atob(s.replace(/-/g, "+").replace(/_/g, "/"));
+  var bytes = new Uint8Array(str.length);
+  for (var i = 0; i < str.length; i++) {
+    bytes[i] = str.charCodeAt(i);
+  }
+  return bytes.buffer;
+}
+
+function bufferToBase64URL(buf) {
+  var bytes = new Uint8Array(buf);
+  var str = "";
+  for (var i = 0; i < bytes.length; i++) {
+    str += String.fromCharCode(bytes[i]);
+  }
+  return btoa(str)
+    .replace(/\+/g, "-")
+    .replace(/\//g, "_")
+    .replace(/=+$/, "");
+}
+
+function decodeCredentialDescriptors(descs) {
+  return (descs || []).map(function(desc) {
+    return { type: desc.type, id: base64URLToBuffer(desc.id) };
+  });
+}
+
+function initWebAuthn() {
+  var $register = $("#webauthn-register-form");
+  var $login = $(".webauthn-login");
+  if ($register.length === 0 && $login.length === 0) {
+    return;
+  }
+  if (!window.PublicKeyCredential) {
+    $(".webauthn-unsupported").show();
+    $register.find("button").addClass("disabled");
+    $login.find("button").addClass("disabled");
+    return;
+  }
+
+  // Register a new security key
+  $register.submit(function(e) {
+    if ($register.find("input[name=client_data]").val() !== "") {
+      return;
+    }
+    e.preventDefault();
+
+    $.getJSON($register.data("options-url")).done(function(options) {
+      options.challenge = base64URLToBuffer(options.challenge);
+      options.user.id = base64URLToBuffer(options.user.id);
+      options.excludeCredentials = decodeCredentialDescriptors(
+        options.excludeCredentials
+      );
+      navigator.credentials.create({ publicKey: options }).then(
+        function(credential) {
+          $register
+            .find("input[name=client_data]")
+            .val(bufferToBase64URL(credential.response.clientDataJSON));
+          $register
+            .find("input[name=attestation_object]")
+            .val(bufferToBase64URL(credential.response.attestationObject));
+          $register.submit();
+        },
+        function(err) {
+          console.error(err);
+        }
+      );
+    });
+  });
+
+  // Authenticate with a security key
+  $login.find("button").click(function() {
+    var options = $.extend(true, {}, $login.data("options"));
+    options.challenge = base64URLToBuffer(options.challenge);
+    options.allowCredentials = decodeCredentialDescriptors(
+      options.allowCredentials
+    );
+    navigator.credentials.get({ publicKey: options }).then(
+      function(credential) {
+        $login
+          .find("input[name=credential_id]")
+          .val(bufferToBase64URL(credential.rawId));
+        $login
+          .find("input[name=client_data]")
+          .val(bufferToBase64URL(credential.response.clientDataJSON));
+        $login
+          .find("input[name=authenticator_data]")
+          .val(bufferToBase64URL(credential.response.authenticatorData));
+        $login
+          .find("input[name=signature]")
+          .val(bufferToBase64URL(credential.response.signature));
+        $login.closest("form")[0].submit();
+      },
+      function(err) {
+        console.error(err);
+      }
+    );
+  });
+}
+
 function initRepositoryCollaboration() {
   console.log("initRepositoryCollaboration");
 
@@ -1577,7 +1712,7 @@ $(document).ready(function() {
   // Helpers
   $(".delete-button").click(function() {
     var $this = $(this);
-    $(".delete.modal")
+    $($this.data("modal") || ".delete.modal")
       .modal({
         closable: false,
         onApprove: function() {
@@ -1675,6 +1810,7 @@ $(document).ready(function() {
   initOrganization();
   initAdmin();
   initCodeView();
+  initWebAuthn();
 
   // Repo clone url.
   if ($("#repo-clone-url").length > 0) {